@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/rpc"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/stream"
+	"github.com/aronipurwanto/go-restful-api/webhook"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Application is the fully wired program, built by InitializeApplication
+type Application struct {
+	Config     Config
+	DB         *gorm.DB
+	Metrics    *metrics.Metrics
+	Health     *health.Registry
+	Background *BackgroundGroup
+	Server     *fiber.App
+	GRPC       *rpc.Server
+
+	Services Services
+
+	Relay         *event.Relay
+	WebhookSender *webhook.Sender
+	Stream        *stream.Broker
+	// PaymentProvider is the card gateway, the tests settle the charges of the fake one
+	PaymentProvider payment.Provider
+}
+
+// Services are the service layer shared by the REST, GraphQL and gRPC APIs and the command line
+type Services struct {
+	Category  service.CategoryService
+	Customer  service.CustomerService
+	Employee  service.EmployeeService
+	Product   service.ProductService
+	Store     service.StoreService
+	Transfer  service.StockTransferService
+	Promotion service.PromotionService
+	Pricing   service.PricingService
+	APIKey    service.APIKeyService
+}
+
+// Migrate creates or updates the tables of every model, then moves the data the new columns
+// replace
+func (application *Application) Migrate() error {
+	if err := application.DB.AutoMigrate(Models()...); err != nil {
+		return err
+	}
+	if err := migrateTaxClasses(application.DB); err != nil {
+		return err
+	}
+	if err := migrateActivePayments(application.DB); err != nil {
+		return err
+	}
+	if err := migrateOpenCashSessions(application.DB); err != nil {
+		return err
+	}
+	if err := migratePromotionAmounts(application.DB); err != nil {
+		return err
+	}
+	return migrateOutboxSequences(application.DB)
+}
+
+// Run starts the background workers and serves the API (and the metrics and gRPC listeners
+// when configured) until ctx is done, see Run
+func (application *Application) Run(ctx context.Context) int {
+	sqlDB, err := application.DB.DB()
+	helper.PanicIfError(err)
+
+	application.Background.Go(application.Relay.Run)
+	application.Background.Go(application.WebhookSender.Run)
+	go func() {
+		// Streams never end on their own, close them so that the servers can drain
+		<-ctx.Done()
+		application.Stream.Close()
+	}()
+
+	servers := []Server{{App: application.Server, Addr: application.Config.ServerAddr}}
+	if application.Config.MetricsAddr != "" {
+		servers = append(servers, Server{App: NewMetricsServer(application.Config, application.Metrics), Addr: application.Config.MetricsAddr})
+	}
+	if application.Config.GRPCAddr != "" {
+		servers = append(servers, Server{App: application.GRPC, Addr: application.Config.GRPCAddr})
+	}
+	return Run(ctx, application.Config, application.Health, application.Background, sqlDB, servers...)
+}
+
+// NewInstrumentedDB opens the MySQL database and reports its queries, pool and inventory to appMetrics
+func NewInstrumentedDB(config Config, appMetrics *metrics.Metrics) *gorm.DB {
+	db := NewDB(config)
+	err := db.Use(appMetrics.GormPlugin())
+	helper.PanicIfError(err)
+
+	sqlDB, err := db.DB()
+	helper.PanicIfError(err)
+	appMetrics.RegisterDBStats(sqlDB, "struct_db")
+	appMetrics.Registry.MustRegister(metrics.NewInventoryCollector(db, config.ReorderLevel))
+	return db
+}
+
+// NewServer returns the Fiber application serving the controllers
+func NewServer(config Config, appMetrics *metrics.Metrics, middlewares Middlewares, controllers Controllers) *fiber.App {
+	server := fiber.New()
+	server.Use(appMetrics.Middleware())
+
+	// Setup Routes
+	NewRouter(server, config, middlewares, controllers)
+
+	// Metrics are served by NewMetricsServer when a dedicated address is configured
+	if config.MetricsAddr == "" {
+		server.Get("/metrics", appMetrics.Handler(config.MetricsToken))
+	}
+	return server
+}
+
+// NewMetricsServer serves /metrics on its own listener, see Config.MetricsAddr
+func NewMetricsServer(config Config, appMetrics *metrics.Metrics) *fiber.App {
+	server := fiber.New(fiber.Config{DisableStartupMessage: true})
+	server.Get("/metrics", appMetrics.Handler(config.MetricsToken))
+	return server
+}
+
+// NewHealthRegistry registers the dependency checks run by /readyz
+func NewHealthRegistry(config Config, db *gorm.DB) *health.Registry {
+	healthRegistry := health.NewRegistry(config.HealthTimeout)
+	healthRegistry.Register(
+		health.NewDatabaseChecker(db),
+		health.NewMigrationChecker(db, Models()...),
+	)
+	return healthRegistry
+}
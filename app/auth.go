@@ -0,0 +1,15 @@
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// NewAuthenticator accepts the API keys configured by API_KEYS and the ones issued with the
+// apikey command, see APIKeyService
+func NewAuthenticator(config Config, apiKeyService service.APIKeyService) auth.Authenticator {
+	keys, err := auth.ParseStaticKeys(config.APIKeys)
+	helper.PanicIfError(err)
+	return auth.Chain{keys, apiKeyService}
+}
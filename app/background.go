@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// BackgroundGroup tracks long-running goroutines (relays, dispatchers, ...) that must be
+// stopped and awaited before the process exits
+type BackgroundGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewBackgroundGroup() *BackgroundGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackgroundGroup{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in its own goroutine; fn must return once ctx is cancelled
+func (group *BackgroundGroup) Go(fn func(ctx context.Context)) {
+	group.wg.Add(1)
+	go func() {
+		defer group.wg.Done()
+		fn(group.ctx)
+	}()
+}
+
+// Shutdown cancels every worker and waits for them until ctx expires
+func (group *BackgroundGroup) Shutdown(ctx context.Context) error {
+	group.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		group.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/cache"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"gorm.io/gorm"
+)
+
+// NewCacheBackend returns the store shared by every repository cache
+func NewCacheBackend(config Config) cache.Backend {
+	return cache.NewLRU(config.CacheSize)
+}
+
+// NewProductRepository wraps the GORM repository in a read-through cache unless CACHE_TTL is zero
+func NewProductRepository(config Config, db *gorm.DB, backend cache.Backend, appMetrics *metrics.Metrics) repository.ProductRepository {
+	productRepository := repository.NewProductRepository(db)
+	if config.CacheTTL <= 0 {
+		return productRepository
+	}
+	return repository.NewCachedProductRepository(productRepository, cache.New("products", backend, config.CacheTTL, appMetrics))
+}
+
+// NewCategoryRepository wraps the GORM repository in a read-through cache unless CACHE_TTL is zero
+func NewCategoryRepository(config Config, db *gorm.DB, backend cache.Backend, appMetrics *metrics.Metrics) repository.CategoryRepository {
+	categoryRepository := repository.NewCategoryRepository(db)
+	if config.CacheTTL <= 0 {
+		return categoryRepository
+	}
+	return repository.NewCachedCategoryRepository(categoryRepository, cache.New("categories", backend, config.CacheTTL, appMetrics))
+}
@@ -0,0 +1,285 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/qris"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// Config holds the runtime settings of the application, read from environment variables
+type Config struct {
+	ServerAddr               string
+	DatabaseDSN              string
+	MetricsAddr              string
+	MetricsToken             string
+	ReorderLevel             int
+	HealthTimeout            time.Duration
+	ShutdownTimeout          time.Duration
+	ShutdownDelay            time.Duration
+	OpenAPIValidation        bool
+	OpenAPIValidateResponses bool
+	CacheTTL                 time.Duration
+	CacheSize                int
+	OutboxPollInterval       time.Duration
+	WebhookMaxAttempts       int
+	WebhookBackoff           time.Duration
+	WebhookMaxBackoff        time.Duration
+	WebhookTimeout           time.Duration
+	WebhookConcurrency       int
+	APIKeys                  string
+	StreamHeartbeat          time.Duration
+	StreamBuffer             int
+	GraphQLMaxDepth          int
+	GraphQLMaxComplexity     int
+	GraphQLListFactor        int
+	GRPCAddr                 string
+	QRISAcquirerID           string
+	QRISMerchantPAN          string
+	QRISMerchantID           string
+	QRISNMID                 string
+	QRISMerchantCriteria     string
+	QRISMerchantCategory     string
+	QRISMerchantName         string
+	QRISMerchantCity         string
+	QRISPostalCode           string
+	PaymentProvider          string
+	PaymentWebhookSecret     string
+	PaymentWebhookTolerance  time.Duration
+	ReturnApprovalThreshold  money.Money
+	ReturnApproverRoles      string
+}
+
+// NewConfig loads the configuration from the environment, falling back to the defaults for
+// missing and invalid values, see LoadConfig
+func NewConfig() Config {
+	config, _ := LoadConfig()
+	return config
+}
+
+// LoadConfig loads the configuration like NewConfig and also reports the variables that could
+// not be parsed and the settings rejected by Validate
+func LoadConfig() (Config, error) {
+	env := &environment{}
+	config := Config{
+		ServerAddr:               env.get("SERVER_ADDR", ":8080"),
+		DatabaseDSN:              env.get("DATABASE_DSN", "root:Rt0011Rw007@tcp(localhost:3306)/struct_db?charset=utf8mb4&parseTime=True&loc=Local"),
+		MetricsAddr:              env.get("METRICS_ADDR", ""),
+		MetricsToken:             env.get("METRICS_TOKEN", ""),
+		ReorderLevel:             env.getInt("REORDER_LEVEL", 10),
+		HealthTimeout:            env.getDuration("HEALTH_TIMEOUT", 2*time.Second),
+		ShutdownTimeout:          env.getDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		ShutdownDelay:            env.getDuration("SHUTDOWN_DELAY", 0),
+		OpenAPIValidation:        env.getBool("OPENAPI_VALIDATION", false),
+		OpenAPIValidateResponses: env.getBool("OPENAPI_VALIDATE_RESPONSES", false),
+		CacheTTL:                 env.getDuration("CACHE_TTL", time.Minute),
+		CacheSize:                env.getInt("CACHE_SIZE", 10000),
+		OutboxPollInterval:       env.getDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		WebhookMaxAttempts:       env.getInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookBackoff:           env.getDuration("WEBHOOK_BACKOFF", 30*time.Second),
+		WebhookMaxBackoff:        env.getDuration("WEBHOOK_MAX_BACKOFF", time.Hour),
+		WebhookTimeout:           env.getDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookConcurrency:       env.getInt("WEBHOOK_CONCURRENCY", 4),
+		APIKeys:                  env.get("API_KEYS", "RAHASIA=admin:*"),
+		StreamHeartbeat:          env.getDuration("STREAM_HEARTBEAT", 15*time.Second),
+		StreamBuffer:             env.getInt("STREAM_BUFFER", 256),
+		GraphQLMaxDepth:          env.getInt("GRAPHQL_MAX_DEPTH", 6),
+		GraphQLMaxComplexity:     env.getInt("GRAPHQL_MAX_COMPLEXITY", 1000),
+		GraphQLListFactor:        env.getInt("GRAPHQL_LIST_FACTOR", 10),
+		GRPCAddr:                 env.get("GRPC_ADDR", ":9090"),
+		QRISAcquirerID:           env.get("QRIS_ACQUIRER_ID", "ID.CO.BANKDEMO.WWW"),
+		QRISMerchantPAN:          env.get("QRIS_MERCHANT_PAN", "9360000800000000001"),
+		QRISMerchantID:           env.get("QRIS_MERCHANT_ID", ""),
+		QRISNMID:                 env.get("QRIS_NMID", "ID1020000000001"),
+		QRISMerchantCriteria:     env.get("QRIS_MERCHANT_CRITERIA", "UMI"),
+		QRISMerchantCategory:     env.get("QRIS_MCC", "5411"),
+		QRISMerchantName:         env.get("QRIS_MERCHANT_NAME", "Toko Demo"),
+		QRISMerchantCity:         env.get("QRIS_MERCHANT_CITY", "JAKARTA"),
+		QRISPostalCode:           env.get("QRIS_POSTAL_CODE", ""),
+		PaymentProvider:          env.get("PAYMENT_PROVIDER", "fake"),
+		PaymentWebhookSecret:     env.get("PAYMENT_WEBHOOK_SECRET", "RAHASIA-WEBHOOK"),
+		PaymentWebhookTolerance:  env.getDuration("PAYMENT_WEBHOOK_TOLERANCE", 5*time.Minute),
+		ReturnApprovalThreshold:  env.getMoney("RETURN_APPROVAL_THRESHOLD", money.IDR(1000000)),
+		ReturnApproverRoles:      env.get("RETURN_APPROVER_ROLES", "supervisor,manager"),
+	}
+	return config, errors.Join(append(env.errs, config.Validate())...)
+}
+
+// Validate reports the settings the application cannot run with
+func (config Config) Validate() error {
+	var errs []error
+	if config.ServerAddr == "" {
+		errs = append(errs, errors.New("SERVER_ADDR must not be empty"))
+	}
+	if config.DatabaseDSN == "" {
+		errs = append(errs, errors.New("DATABASE_DSN must not be empty"))
+	}
+
+	addrs := map[string]string{}
+	for _, listener := range []struct{ name, addr string }{
+		{"SERVER_ADDR", config.ServerAddr},
+		{"METRICS_ADDR", config.MetricsAddr},
+		{"GRPC_ADDR", config.GRPCAddr},
+	} {
+		if other, ok := addrs[listener.addr]; ok && listener.addr != "" {
+			errs = append(errs, fmt.Errorf("%s and %s both listen on %s", other, listener.name, listener.addr))
+		}
+		addrs[listener.addr] = listener.name
+	}
+
+	for _, duration := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HEALTH_TIMEOUT", config.HealthTimeout},
+		{"SHUTDOWN_TIMEOUT", config.ShutdownTimeout},
+		{"OUTBOX_POLL_INTERVAL", config.OutboxPollInterval},
+		{"WEBHOOK_BACKOFF", config.WebhookBackoff},
+		{"WEBHOOK_MAX_BACKOFF", config.WebhookMaxBackoff},
+		{"WEBHOOK_TIMEOUT", config.WebhookTimeout},
+		{"STREAM_HEARTBEAT", config.StreamHeartbeat},
+		{"PAYMENT_WEBHOOK_TOLERANCE", config.PaymentWebhookTolerance},
+	} {
+		if duration.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", duration.name, duration.value))
+		}
+	}
+	if config.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_DELAY must not be negative, got %s", config.ShutdownDelay))
+	}
+	if config.WebhookMaxBackoff < config.WebhookBackoff {
+		errs = append(errs, errors.New("WEBHOOK_MAX_BACKOFF must not be shorter than WEBHOOK_BACKOFF"))
+	}
+
+	for _, count := range []struct {
+		name  string
+		value int
+	}{
+		{"CACHE_SIZE", config.CacheSize},
+		{"WEBHOOK_MAX_ATTEMPTS", config.WebhookMaxAttempts},
+		{"WEBHOOK_CONCURRENCY", config.WebhookConcurrency},
+		{"STREAM_BUFFER", config.StreamBuffer},
+	} {
+		if count.value < 1 {
+			errs = append(errs, fmt.Errorf("%s must be at least 1, got %d", count.name, count.value))
+		}
+	}
+
+	if _, err := auth.ParseStaticKeys(config.APIKeys); err != nil {
+		errs = append(errs, fmt.Errorf("API_KEYS: %w", err))
+	}
+	if err := config.QRISMerchant().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("QRIS_*: %w", err))
+	}
+	if config.PaymentProvider != "fake" {
+		errs = append(errs, fmt.Errorf("PAYMENT_PROVIDER must be fake, got %q", config.PaymentProvider))
+	}
+	if config.PaymentWebhookSecret == "" {
+		errs = append(errs, errors.New("PAYMENT_WEBHOOK_SECRET must not be empty"))
+	}
+	if config.ReturnApprovalThreshold.IsNegative() {
+		errs = append(errs, fmt.Errorf("RETURN_APPROVAL_THRESHOLD must not be negative, got %s", config.ReturnApprovalThreshold))
+	}
+	if len(config.ReturnPolicy().ApproverRoles) == 0 {
+		errs = append(errs, errors.New("RETURN_APPROVER_ROLES must name at least one role"))
+	}
+	return errors.Join(errs...)
+}
+
+// ReturnPolicy is the approval of returns configured by the RETURN_* variables
+func (config Config) ReturnPolicy() service.ReturnPolicy {
+	policy := service.ReturnPolicy{ApprovalThreshold: config.ReturnApprovalThreshold}
+	for _, role := range strings.Split(config.ReturnApproverRoles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			policy.ApproverRoles = append(policy.ApproverRoles, role)
+		}
+	}
+	return policy
+}
+
+// QRISMerchant is the merchant the QRIS payloads pay, as registered with the acquirer
+func (config Config) QRISMerchant() qris.Merchant {
+	return qris.Merchant{
+		AcquirerID:   config.QRISAcquirerID,
+		PAN:          config.QRISMerchantPAN,
+		MerchantID:   config.QRISMerchantID,
+		NMID:         config.QRISNMID,
+		Criteria:     config.QRISMerchantCriteria,
+		CategoryCode: config.QRISMerchantCategory,
+		Name:         config.QRISMerchantName,
+		City:         config.QRISMerchantCity,
+		PostalCode:   config.QRISPostalCode,
+	}
+}
+
+// environment reads typed variables and remembers the ones that could not be parsed
+type environment struct {
+	errs []error
+}
+
+func (env *environment) get(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func (env *environment) getInt(key string, fallback int) int {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s: %q is not an integer", key, raw))
+		return fallback
+	}
+	return value
+}
+
+func (env *environment) getBool(key string, fallback bool) bool {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s: %q is not a boolean", key, raw))
+		return fallback
+	}
+	return value
+}
+
+// getMoney reads an amount in rupiah, e.g. 1000000
+func (env *environment) getMoney(key string, fallback money.Money) money.Money {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	value, err := money.Parse(raw, money.DefaultCurrency)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s: %q is not an amount", key, raw))
+		return fallback
+	}
+	return value
+}
+
+func (env *environment) getDuration(key string, fallback time.Duration) time.Duration {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("%s: %q is not a duration", key, raw))
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("defaults are valid", func(t *testing.T) {
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, ":8080", config.ServerAddr)
+	})
+
+	t.Run("invalid values fall back and are reported", func(t *testing.T) {
+		t.Setenv("SHUTDOWN_TIMEOUT", "abc")
+		t.Setenv("CACHE_SIZE", "banyak")
+		t.Setenv("OPENAPI_VALIDATION", "ya")
+		t.Setenv("RETURN_APPROVAL_THRESHOLD", "sejuta")
+
+		config, err := LoadConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `SHUTDOWN_TIMEOUT: "abc" is not a duration`)
+		assert.Contains(t, err.Error(), `CACHE_SIZE: "banyak" is not an integer`)
+		assert.Contains(t, err.Error(), `OPENAPI_VALIDATION: "ya" is not a boolean`)
+		assert.Contains(t, err.Error(), `RETURN_APPROVAL_THRESHOLD: "sejuta" is not an amount`)
+		assert.Equal(t, 30*time.Second, config.ShutdownTimeout)
+		assert.Equal(t, config, NewConfig())
+	})
+
+	t.Run("rejected settings", func(t *testing.T) {
+		t.Setenv("GRPC_ADDR", ":8080")
+		t.Setenv("WEBHOOK_CONCURRENCY", "0")
+		t.Setenv("API_KEYS", "RAHASIA")
+		t.Setenv("QRIS_MCC", "54")
+		t.Setenv("RETURN_APPROVER_ROLES", " , ")
+
+		_, err := LoadConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SERVER_ADDR and GRPC_ADDR both listen on :8080")
+		assert.Contains(t, err.Error(), "WEBHOOK_CONCURRENCY must be at least 1")
+		assert.Contains(t, err.Error(), "API_KEYS: auth: invalid key entry")
+		assert.Contains(t, err.Error(), "QRIS_*: merchant category code must have 4 digits")
+		assert.Contains(t, err.Error(), "RETURN_APPROVER_ROLES must name at least one role")
+	})
+}
@@ -0,0 +1,532 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/gql"
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type contractServices struct {
+	category  *mocks.MockCategoryService
+	customer  *mocks.MockCustomerService
+	employee  *mocks.MockEmployeeService
+	product   *mocks.MockProductService
+	store     *mocks.MockStoreService
+	transfer  *mocks.MockStockTransferService
+	promotion *mocks.MockPromotionService
+	pricing   *mocks.MockPricingService
+	order     *mocks.MockOrderService
+	payment   *mocks.MockPaymentService
+	returns   *mocks.MockReturnService
+	cash      *mocks.MockCashSessionService
+	webhook   *mocks.MockWebhookService
+	stream    *mocks.MockStreamService
+}
+
+func setupTestAppContract(t *testing.T) (*fiber.App, contractServices) {
+	ctrl := gomock.NewController(t)
+	services := contractServices{
+		category:  mocks.NewMockCategoryService(ctrl),
+		customer:  mocks.NewMockCustomerService(ctrl),
+		employee:  mocks.NewMockEmployeeService(ctrl),
+		product:   mocks.NewMockProductService(ctrl),
+		store:     mocks.NewMockStoreService(ctrl),
+		transfer:  mocks.NewMockStockTransferService(ctrl),
+		promotion: mocks.NewMockPromotionService(ctrl),
+		pricing:   mocks.NewMockPricingService(ctrl),
+		order:     mocks.NewMockOrderService(ctrl),
+		payment:   mocks.NewMockPaymentService(ctrl),
+		returns:   mocks.NewMockReturnService(ctrl),
+		cash:      mocks.NewMockCashSessionService(ctrl),
+		webhook:   mocks.NewMockWebhookService(ctrl),
+		stream:    mocks.NewMockStreamService(ctrl),
+	}
+
+	executor, err := gql.NewExecutor(gql.Services{Category: services.category, Customer: services.customer, Employee: services.employee, Product: services.product, Store: services.store}, gql.Limits{})
+	assert.NoError(t, err)
+
+	server := fiber.New()
+	NewRouter(server, Config{OpenAPIValidation: true, OpenAPIValidateResponses: true}, NewMiddlewares(auth.StaticKeys{"RAHASIA": {Name: "admin", Permissions: []string{auth.Wildcard}}}), Controllers{
+		Health:      controller.NewHealthController(health.NewRegistry(0)),
+		Category:    controller.NewCategoryController(services.category),
+		Customer:    controller.NewCustomerController(services.customer),
+		Employee:    controller.NewEmployeeController(services.employee, services.store),
+		Product:     controller.NewProductController(services.product, services.store),
+		Store:       controller.NewStoreController(services.store),
+		Transfer:    controller.NewStockTransferController(services.transfer),
+		Promotion:   controller.NewPromotionController(services.promotion),
+		Pricing:     controller.NewPricingController(services.pricing),
+		Order:       controller.NewOrderController(services.order),
+		Payment:     controller.NewPaymentController(services.payment),
+		Return:      controller.NewReturnController(services.returns),
+		CashSession: controller.NewCashSessionController(services.cash),
+		Webhook:     controller.NewWebhookController(services.webhook),
+		Stream:      controller.NewStreamController(services.stream, time.Second),
+		GraphQL:     controller.NewGraphQLController(executor),
+	})
+	return server, services
+}
+
+// TestControllersHonourContract calls every controller through the validation middleware with
+// response validation enabled, so any drift between a handler and the document fails here
+func TestControllersHonourContract(t *testing.T) {
+	server, services := setupTestAppContract(t)
+
+	category := web.CategoryResponse{Id: 1, Name: "Electronics"}
+	customer := web.CustomerResponse{CustomerID: "C1", Name: "Budi", Email: "budi@example.com", Phone: "0812", Address: "Jakarta", LoyaltyPts: 10}
+	employee := web.EmployeeResponse{EmployeeID: "E1", Name: "Siti", Role: "cashier", Email: "siti@example.com", Phone: "0813", DateHired: "2024-01-01"}
+	product := web.ProductResponse{ProductID: "P1", Name: "Laptop", Price: 15000000, PriceAmount: money.IDR(15000000), Currency: "IDR", StockQty: 10, Category: "Electronics", SKU: "LAP123", TaxRate: 11}
+	store := web.StoreResponse{StoreID: "JKT01", Name: "Jakarta Pusat", Address: "Jl. Thamrin 1", Phone: "021555", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	override := money.IDR(14500000)
+	overrideNumber := override.Float64()
+	stock := web.StoreStockResponse{StoreID: "JKT01", ProductID: "P1", StockQty: 3, PriceOverride: &overrideNumber, PriceOverrideAmount: &override, UpdatedAt: time.Now()}
+	promotion := web.PromotionResponse{PromotionID: "PR1", Name: "Weekend Gaming", Type: domain.PromotionPercentage, Value: 10, Categories: []string{"Gaming Laptop"}, Active: true, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	ppn := []web.TaxAmountResponse{{Code: "PPN", Name: "PPN 11%", Percent: 11, Base: money.IDR(13500000), Tax: money.IDR(1485000)}}
+	quote := web.QuoteResponse{
+		Lines: []web.QuoteLineResponse{{
+			ProductID: "P1", Name: "Laptop", UnitPrice: money.IDR(15000000), Quantity: 1, Subtotal: money.IDR(15000000), Discount: money.IDR(1500000),
+			TaxClass: "standard", TaxRate: 11, Tax: money.IDR(1485000), Taxes: ppn, Total: money.IDR(14985000),
+			Promotions: []web.AppliedPromotionResponse{{PromotionID: "PR1", Name: "Weekend Gaming", Discount: money.IDR(1500000)}},
+		}},
+		Promotions: []web.AppliedPromotionResponse{{PromotionID: "PR1", Name: "Weekend Gaming", Discount: money.IDR(1500000)}},
+		PriceMode:  "exclusive",
+		Currency:   "IDR",
+		Subtotal:   money.IDR(15000000), Discount: money.IDR(1500000), Tax: money.IDR(1485000), Taxes: ppn, Total: money.IDR(14985000), CashTotal: money.IDR(14985000),
+	}
+	order := web.OrderResponse{
+		OrderID: "O1", StoreID: "JKT01", EmployeeID: "E1", CustomerID: "C1", PriceMode: "exclusive", Currency: "IDR",
+		Lines: []web.OrderLineResponse{{
+			ProductID: "P1", Name: "Laptop", TaxClass: "standard", UnitPrice: money.IDR(15000000), Quantity: 1,
+			Subtotal: money.IDR(15000000), Discount: money.IDR(1500000), Tax: money.IDR(1485000), Total: money.IDR(14985000),
+		}},
+		Promotions: quote.Promotions,
+		Subtotal:   money.IDR(15000000), Discount: money.IDR(1500000), Tax: money.IDR(1485000), Taxes: ppn, Total: money.IDR(14985000),
+		PointsEarned: 1498, PointsBalance: 1508, CreatedAt: time.Now(),
+	}
+	paymentResponse := web.PaymentResponse{
+		PaymentID: "PAY1", OrderID: "O1", StoreID: "JKT01", CustomerID: "C1", Amount: money.IDR(116550), Status: domain.PaymentPaid,
+		Tenders: []web.TenderResponse{
+			{Method: domain.TenderCash, Amount: money.IDR(100000), Tendered: money.IDR(120000), Change: money.IDR(20000), Status: "captured"},
+			{Method: domain.TenderCard, Amount: money.IDR(16550), Tendered: money.IDR(0), Change: money.IDR(0), Provider: "fake", ChargeID: "ch_1", Status: "captured"},
+		},
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	returnResponse := web.ReturnResponse{
+		ReturnID: "R1", OrderID: "O1", StoreID: "JKT01", CustomerID: "C1", EmployeeID: "E1", Status: domain.ReturnCompleted,
+		Lines: []web.ReturnLineResponse{{
+			OrderPosition: 1, ProductID: "P1", Name: "Laptop", Quantity: 1, Reason: domain.ReasonDefective, Disposition: domain.DispositionWriteOff,
+			Subtotal: money.IDR(15000000), Discount: money.IDR(1500000), Tax: money.IDR(1485000), Refund: money.IDR(14985000),
+		}},
+		Subtotal: money.IDR(15000000), Discount: money.IDR(1500000), Tax: money.IDR(1485000), Refund: money.IDR(14985000), PointsReversed: 1498,
+		Refunds:   []web.ReturnRefundResponse{{Method: domain.TenderCard, Amount: money.IDR(14985000), ChargeID: "ch_1"}},
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	closedAt := time.Now()
+	counted, variance := money.IDR(149000), money.IDR(-1000)
+	cashSession := web.CashSessionResponse{
+		SessionID: "S1", StoreID: "JKT01", EmployeeID: "E1", ClosedBy: "E1", Status: domain.CashSessionClosed, OpeningFloat: money.IDR(100000),
+		Expected: &[]money.Money{money.IDR(150000)}[0], Counted: &counted, Variance: &variance,
+		Counts:    []web.CashCountResponse{{Denomination: money.IDR(50000), Quantity: 2, Amount: money.IDR(100000)}, {Denomination: money.IDR(1000), Quantity: 49, Amount: money.IDR(49000)}},
+		Movements: []web.CashMovementResponse{{Position: 1, Type: domain.CashIn, Amount: money.IDR(50000), Reason: "kembalian", EmployeeID: "E1", CreatedAt: time.Now()}},
+		OpenedAt:  time.Now().Add(-8 * time.Hour), ClosedAt: &closedAt,
+	}
+	cashReport := web.CashReportResponse{
+		Kind: "Z", StoreID: "JKT01", SessionID: "S1", EmployeeID: "E1", From: cashSession.OpenedAt, To: closedAt,
+		Taxes:       []web.CashReportTaxResponse{},
+		Payments:    []web.CashReportPaymentResponse{{Method: domain.TenderCash, Sales: money.IDR(0), Refunds: money.IDR(0), Net: money.IDR(0)}},
+		Cash:        web.CashReportCashResponse{OpeningFloat: money.IDR(100000), CashIn: money.IDR(50000), Expected: money.IDR(150000), Counted: &counted, Variance: &variance},
+		GeneratedAt: time.Now(),
+	}
+	dispatchedAt := time.Now()
+	transfer := web.StockTransferResponse{
+		TransferID:         "T1",
+		DestinationStoreID: "JKT01",
+		Status:             domain.TransferDispatched,
+		Lines:              []web.StockTransferLineResponse{{ProductID: "P1", Quantity: 3}},
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+		DispatchedAt:       &dispatchedAt,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           interface{}
+		storeId        string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{name: "list categories", method: http.MethodGet, url: "/api/categories", setupMock: func() {
+			services.category.EXPECT().FindAll(gomock.Any()).Return([]web.CategoryResponse{category}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "list categories empty", method: http.MethodGet, url: "/api/categories", setupMock: func() {
+			services.category.EXPECT().FindAll(gomock.Any()).Return(nil, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get category", method: http.MethodGet, url: "/api/categories/1", setupMock: func() {
+			services.category.EXPECT().FindById(gomock.Any(), 1).Return(category, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get missing category", method: http.MethodGet, url: "/api/categories/2", setupMock: func() {
+			services.category.EXPECT().FindById(gomock.Any(), 2).Return(web.CategoryResponse{}, exception.NewNotFoundError("Category not found"))
+		}, expectedStatus: http.StatusNotFound},
+		{name: "create category", method: http.MethodPost, url: "/api/categories", body: web.CategoryCreateRequest{Name: "Electronics"}, setupMock: func() {
+			services.category.EXPECT().Create(gomock.Any(), gomock.Any()).Return(category, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "update category", method: http.MethodPut, url: "/api/categories/1", body: map[string]string{"name": "Gadget"}, setupMock: func() {
+			services.category.EXPECT().Update(gomock.Any(), web.CategoryUpdateRequest{Id: 1, Name: "Gadget"}).Return(category, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "delete category", method: http.MethodDelete, url: "/api/categories/1", setupMock: func() {
+			services.category.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list customers", method: http.MethodGet, url: "/api/customers", setupMock: func() {
+			services.customer.EXPECT().FindAll(gomock.Any()).Return([]web.CustomerResponse{customer}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get customer", method: http.MethodGet, url: "/api/customers/C1", setupMock: func() {
+			services.customer.EXPECT().FindById(gomock.Any(), "C1").Return(customer, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "create customer", method: http.MethodPost, url: "/api/customers", body: web.CustomerCreateRequest{Name: "Budi", Email: "budi@example.com", Phone: "0812"}, setupMock: func() {
+			services.customer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(customer, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "update customer", method: http.MethodPut, url: "/api/customers/C1", body: web.CustomerUpdateRequest{Name: "Budi", Email: "budi@example.com", Phone: "0812"}, setupMock: func() {
+			services.customer.EXPECT().Update(gomock.Any(), gomock.Any()).Return(customer, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "delete customer", method: http.MethodDelete, url: "/api/customers/C1", setupMock: func() {
+			services.customer.EXPECT().Delete(gomock.Any(), "C1").Return(nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list employees", method: http.MethodGet, url: "/api/employees", setupMock: func() {
+			services.employee.EXPECT().FindAll(gomock.Any()).Return([]web.EmployeeResponse{employee}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get employee", method: http.MethodGet, url: "/api/employees/E1", setupMock: func() {
+			services.employee.EXPECT().FindById(gomock.Any(), "E1").Return(employee, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "create employee", method: http.MethodPost, url: "/api/employees", body: web.EmployeeCreateRequest{Name: "Siti", Role: "cashier", Email: "siti@example.com", Phone: "0813", DateHired: "2024-01-01"}, setupMock: func() {
+			services.employee.EXPECT().Create(gomock.Any(), gomock.Any()).Return(employee, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "update employee", method: http.MethodPut, url: "/api/employees/E1", body: web.EmployeeUpdateRequest{Name: "Siti", Role: "supervisor", Email: "siti@example.com", Phone: "0813", DateHired: "2024-01-01"}, setupMock: func() {
+			services.employee.EXPECT().Update(gomock.Any(), gomock.Any()).Return(employee, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "delete employee", method: http.MethodDelete, url: "/api/employees/E1", setupMock: func() {
+			services.employee.EXPECT().Delete(gomock.Any(), "E1").Return(nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list products", method: http.MethodGet, url: "/api/products", setupMock: func() {
+			services.product.EXPECT().FindAll(gomock.Any()).Return([]web.ProductResponse{product}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get product", method: http.MethodGet, url: "/api/products/P1", setupMock: func() {
+			services.product.EXPECT().FindById(gomock.Any(), "P1").Return(product, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "create product", method: http.MethodPost, url: "/api/products", body: web.ProductCreateRequest{Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP123"}, setupMock: func() {
+			services.product.EXPECT().Create(gomock.Any(), gomock.Any()).Return(product, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "create product failure", method: http.MethodPost, url: "/api/products", body: web.ProductCreateRequest{Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP123"}, setupMock: func() {
+			services.product.EXPECT().Create(gomock.Any(), gomock.Any()).Return(web.ProductResponse{}, errors.New("database is down"))
+		}, expectedStatus: http.StatusInternalServerError},
+		{name: "update product", method: http.MethodPut, url: "/api/products/P1", body: web.ProductUpdateRequest{Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP123"}, setupMock: func() {
+			services.product.EXPECT().Update(gomock.Any(), gomock.Any()).Return(product, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "delete product", method: http.MethodDelete, url: "/api/products/P1", setupMock: func() {
+			services.product.EXPECT().Delete(gomock.Any(), "P1").Return(nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list products in a store", method: http.MethodGet, url: "/api/products", storeId: "JKT01", setupMock: func() {
+			inStore := product
+			inStore.StoreID, inStore.StockQty, inStore.Price, inStore.PriceAmount = "JKT01", 3, overrideNumber, override
+			services.store.EXPECT().FindProducts(gomock.Any(), "JKT01", nil).Return([]web.ProductResponse{inStore}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "list employees in a store", method: http.MethodGet, url: "/api/employees", storeId: "JKT01", setupMock: func() {
+			assigned := employee
+			assigned.StoreIDs = []string{"JKT01"}
+			services.store.EXPECT().FindEmployees(gomock.Any(), "JKT01").Return([]web.EmployeeResponse{assigned}, nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list stores", method: http.MethodGet, url: "/api/stores", setupMock: func() {
+			services.store.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return([]web.StoreResponse{store}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get forbidden store", method: http.MethodGet, url: "/api/stores/BDG01", setupMock: func() {
+			services.store.EXPECT().FindById(gomock.Any(), gomock.Any(), "BDG01").Return(web.StoreResponse{}, exception.NewForbiddenError("kasir is not allowed to access store BDG01"))
+		}, expectedStatus: http.StatusForbidden},
+		{name: "create store", method: http.MethodPost, url: "/api/stores", body: web.StoreCreateRequest{StoreID: "JKT01", Name: "Jakarta Pusat"}, setupMock: func() {
+			services.store.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(store, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "update store", method: http.MethodPut, url: "/api/stores/JKT01", body: map[string]string{"name": "Jakarta Pusat"}, setupMock: func() {
+			services.store.EXPECT().Update(gomock.Any(), gomock.Any(), web.StoreUpdateRequest{StoreID: "JKT01", Name: "Jakarta Pusat"}).Return(store, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "delete store", method: http.MethodDelete, url: "/api/stores/JKT01", setupMock: func() {
+			services.store.EXPECT().Delete(gomock.Any(), gomock.Any(), "JKT01").Return(nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "list store stock", method: http.MethodGet, url: "/api/stores/JKT01/stock", setupMock: func() {
+			services.store.EXPECT().FindStock(gomock.Any(), gomock.Any(), "JKT01").Return([]web.StoreStockResponse{stock}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "update store stock", method: http.MethodPut, url: "/api/stores/JKT01/stock/P1", body: map[string]interface{}{"stock_qty": 3, "price_override": override}, setupMock: func() {
+			services.store.EXPECT().UpdateStock(gomock.Any(), gomock.Any(), web.StoreStockUpdateRequest{StoreID: "JKT01", ProductID: "P1", StockQty: 3, PriceOverride: &override}).Return(stock, nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list open transfers", method: http.MethodGet, url: "/api/transfers?status=open", storeId: "JKT01", setupMock: func() {
+			services.transfer.EXPECT().FindAll(gomock.Any(), "JKT01", "open").Return([]web.StockTransferResponse{transfer}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get transfer", method: http.MethodGet, url: "/api/transfers/T1", setupMock: func() {
+			services.transfer.EXPECT().FindById(gomock.Any(), gomock.Any(), "T1").Return(transfer, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "create transfer", method: http.MethodPost, url: "/api/transfers", body: map[string]interface{}{
+			"destination_store_id": "JKT01", "lines": []map[string]interface{}{{"product_id": "P1", "quantity": 3}},
+		}, setupMock: func() {
+			services.transfer.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(transfer, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "update transfer", method: http.MethodPut, url: "/api/transfers/T1", body: map[string]interface{}{
+			"note": "rak baru", "lines": []map[string]interface{}{{"product_id": "P1", "quantity": 3}},
+		}, setupMock: func() {
+			services.transfer.EXPECT().Update(gomock.Any(), gomock.Any(), web.StockTransferUpdateRequest{
+				TransferID: "T1", Note: "rak baru", Lines: []web.StockTransferLineRequest{{ProductID: "P1", Quantity: 3}},
+			}).Return(transfer, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "cancel transfer", method: http.MethodPost, url: "/api/transfers/T1/cancel", setupMock: func() {
+			services.transfer.EXPECT().Cancel(gomock.Any(), gomock.Any(), "T1").Return(transfer, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "dispatch transfer without stock", method: http.MethodPost, url: "/api/transfers/T1/dispatch", setupMock: func() {
+			services.transfer.EXPECT().Dispatch(gomock.Any(), gomock.Any(), "T1").Return(web.StockTransferResponse{}, exception.NewBadRequestError("insufficient central stock of product P1"))
+		}, expectedStatus: http.StatusBadRequest},
+		{name: "receive transfer", method: http.MethodPost, url: "/api/transfers/T1/receive", body: map[string]interface{}{
+			"lines": []map[string]interface{}{{"product_id": "P1", "quantity": 2, "reason": "1 rusak"}}, "complete": true,
+		}, setupMock: func() {
+			services.transfer.EXPECT().Receive(gomock.Any(), gomock.Any(), web.StockTransferReceiveRequest{
+				TransferID: "T1", Lines: []web.StockTransferReceiptLine{{ProductID: "P1", Quantity: 2, Reason: "1 rusak"}}, Complete: true,
+			}).Return(transfer, nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "list promotions", method: http.MethodGet, url: "/api/promotions", setupMock: func() {
+			services.promotion.EXPECT().FindAll(gomock.Any()).Return([]web.PromotionResponse{promotion}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get promotion", method: http.MethodGet, url: "/api/promotions/PR1", setupMock: func() {
+			services.promotion.EXPECT().FindById(gomock.Any(), "PR1").Return(promotion, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "create promotion", method: http.MethodPost, url: "/api/promotions", body: map[string]interface{}{
+			"name": "Weekend Gaming", "type": "percentage", "value": 10, "categories": []string{"Gaming Laptop"}, "starts_at": "2026-03-14T00:00:00Z", "ends_at": "2026-03-16T00:00:00Z",
+		}, setupMock: func() {
+			services.promotion.EXPECT().Create(gomock.Any(), gomock.Any()).Return(promotion, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "update promotion", method: http.MethodPut, url: "/api/promotions/PR1", body: map[string]interface{}{
+			"name": "Weekend Gaming", "type": "percentage", "value": 15, "active": false,
+		}, setupMock: func() {
+			services.promotion.EXPECT().Update(gomock.Any(), web.PromotionUpdateRequest{PromotionID: "PR1", Name: "Weekend Gaming", Type: "percentage", Value: 15}).Return(promotion, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "delete promotion", method: http.MethodDelete, url: "/api/promotions/PR1", setupMock: func() {
+			services.promotion.EXPECT().Delete(gomock.Any(), "PR1").Return(nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "quote", method: http.MethodPost, url: "/api/pricing/quote", body: map[string]interface{}{
+			"lines": []map[string]interface{}{{"product_id": "P1", "quantity": 1}},
+		}, setupMock: func() {
+			services.pricing.EXPECT().Quote(gomock.Any(), web.QuoteRequest{Lines: []web.QuoteLineRequest{{ProductID: "P1", Quantity: 1}}}).Return(quote, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "list tax classes", method: http.MethodGet, url: "/api/tax-classes", setupMock: func() {
+			services.pricing.EXPECT().FindTaxClasses(gomock.Any()).Return([]web.TaxClassResponse{
+				{TaxClassID: "luxury", Name: "PPN dan PPnBM", Percent: 31, Rates: []web.TaxRateResponse{{Code: "PPN", Name: "PPN 11%", Percent: 11}, {Code: "PPNBM", Name: "PPnBM 20%", Percent: 20}}},
+			}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "quote unknown product", method: http.MethodPost, url: "/api/pricing/quote", body: map[string]interface{}{
+			"lines": []map[string]interface{}{{"product_id": "P404", "quantity": 1}},
+		}, setupMock: func() {
+			services.pricing.EXPECT().Quote(gomock.Any(), gomock.Any()).Return(web.QuoteResponse{}, exception.NewBadRequestError("unknown product P404"))
+		}, expectedStatus: http.StatusBadRequest},
+
+		{name: "create order", method: http.MethodPost, url: "/api/orders", storeId: "JKT01", body: map[string]interface{}{
+			"employee_id": "E1", "customer_id": "C1", "lines": []map[string]interface{}{{"product_id": "P1", "quantity": 1}},
+		}, setupMock: func() {
+			services.order.EXPECT().Create(gomock.Any(), gomock.Any(), web.OrderCreateRequest{StoreID: "JKT01", EmployeeID: "E1", CustomerID: "C1", Lines: []web.QuoteLineRequest{{ProductID: "P1", Quantity: 1}}}).Return(order, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "list orders", method: http.MethodGet, url: "/api/orders", storeId: "JKT01", setupMock: func() {
+			services.order.EXPECT().FindAll(gomock.Any(), "JKT01").Return([]web.OrderResponse{order}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get order", method: http.MethodGet, url: "/api/orders/O1", setupMock: func() {
+			services.order.EXPECT().FindById(gomock.Any(), gomock.Any(), "O1").Return(order, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "receipt of unknown order", method: http.MethodGet, url: "/api/orders/O404/receipt?format=html&paper=58", setupMock: func() {
+			services.order.EXPECT().Receipt(gomock.Any(), gomock.Any(), "O404").Return(receipt.Receipt{}, exception.NewNotFoundError("Order not found"))
+		}, expectedStatus: http.StatusNotFound},
+		{name: "receipt in unknown format", method: http.MethodGet, url: "/api/orders/O1/receipt?format=pdf", setupMock: func() {}, expectedStatus: http.StatusBadRequest},
+
+		{name: "create payment", method: http.MethodPost, url: "/api/payments", body: map[string]interface{}{
+			"order_id": "O1", "tenders": []map[string]interface{}{{"method": "cash", "amount": "100000", "tendered": "120000"}, {"method": "card", "amount": "16550", "token": "tok_success"}},
+		}, setupMock: func() {
+			services.payment.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(paymentResponse, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "list payments of a customer", method: http.MethodGet, url: "/api/payments?customer_id=C1", storeId: "JKT01", setupMock: func() {
+			services.payment.EXPECT().FindAll(gomock.Any(), "JKT01", "C1").Return([]web.PaymentResponse{paymentResponse}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get payment", method: http.MethodGet, url: "/api/payments/PAY1", setupMock: func() {
+			services.payment.EXPECT().FindById(gomock.Any(), gomock.Any(), "PAY1").Return(paymentResponse, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "sync unknown payment", method: http.MethodPost, url: "/api/payments/PAY404/sync", setupMock: func() {
+			services.payment.EXPECT().Sync(gomock.Any(), gomock.Any(), "PAY404").Return(web.PaymentResponse{}, exception.NewNotFoundError("Payment not found"))
+		}, expectedStatus: http.StatusNotFound},
+		{name: "create QRIS payload", method: http.MethodPost, url: "/api/payments/qris", body: map[string]interface{}{
+			"order_id": "O1", "image": "svg",
+		}, setupMock: func() {
+			services.payment.EXPECT().CreateQRIS(gomock.Any(), gomock.Any(), web.QRISCreateRequest{OrderID: "O1", Image: "svg"}).Return(web.QRISResponse{
+				Payload: "00020101021226...6304ABCD", Amount: money.IDR(116550), Currency: "IDR", Reference: "O1", MerchantName: "Toko Demo", MerchantCity: "JAKARTA", NMID: "ID1020000000001", OwnMerchant: true, Image: "data:image/svg+xml;base64,PHN2Zy8+",
+			}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "verify QRIS payload", method: http.MethodPost, url: "/api/payments/qris/verify", body: map[string]interface{}{
+			"payload": "000201",
+		}, setupMock: func() {
+			services.payment.EXPECT().VerifyQRIS(gomock.Any(), web.QRISVerifyRequest{Payload: "000201"}).Return(web.QRISResponse{}, exception.NewBadRequestError("invalid QRIS payload: payload does not end with a checksum"))
+		}, expectedStatus: http.StatusBadRequest},
+
+		{name: "create return", method: http.MethodPost, url: "/api/returns", body: map[string]interface{}{
+			"order_id": "O1", "employee_id": "E1", "lines": []map[string]interface{}{{"position": 1, "quantity": 1, "reason": "defective", "disposition": "write_off"}},
+		}, setupMock: func() {
+			services.returns.EXPECT().Create(gomock.Any(), gomock.Any(), web.ReturnCreateRequest{OrderID: "O1", EmployeeID: "E1", Lines: []web.ReturnLineRequest{{Position: 1, Quantity: 1, Reason: "defective", Disposition: "write_off"}}}).Return(returnResponse, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "list pending returns", method: http.MethodGet, url: "/api/returns?status=pending", storeId: "JKT01", setupMock: func() {
+			services.returns.EXPECT().FindAll(gomock.Any(), "JKT01", "pending").Return([]web.ReturnResponse{}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get return", method: http.MethodGet, url: "/api/returns/R1", setupMock: func() {
+			services.returns.EXPECT().FindById(gomock.Any(), gomock.Any(), "R1").Return(returnResponse, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "approve return by a cashier", method: http.MethodPost, url: "/api/returns/R1/approve", body: map[string]interface{}{"employee_id": "E1"}, setupMock: func() {
+			services.returns.EXPECT().Approve(gomock.Any(), gomock.Any(), web.ReturnDecisionRequest{ReturnID: "R1", EmployeeID: "E1"}).Return(web.ReturnResponse{}, exception.NewForbiddenError("employee E1 is a cashier"))
+		}, expectedStatus: http.StatusForbidden},
+		{name: "reject return", method: http.MethodPost, url: "/api/returns/R1/reject", body: map[string]interface{}{"employee_id": "E2", "note": "opened"}, setupMock: func() {
+			returnResponse := returnResponse
+			returnResponse.Status, returnResponse.ApprovedBy, returnResponse.Refunds = domain.ReturnRejected, "E2", []web.ReturnRefundResponse{}
+			services.returns.EXPECT().Reject(gomock.Any(), gomock.Any(), web.ReturnDecisionRequest{ReturnID: "R1", EmployeeID: "E2", Note: "opened"}).Return(returnResponse, nil)
+		}, expectedStatus: http.StatusOK},
+
+		{name: "open cash session", method: http.MethodPost, url: "/api/cash-sessions", body: map[string]interface{}{"employee_id": "E1", "opening_float": "100000"}, storeId: "JKT01", setupMock: func() {
+			cashSession := cashSession
+			cashSession.Status, cashSession.ClosedBy, cashSession.Expected, cashSession.Counted, cashSession.Variance, cashSession.Counts, cashSession.ClosedAt = domain.CashSessionOpen, "", nil, nil, nil, []web.CashCountResponse{}, nil
+			services.cash.EXPECT().Open(gomock.Any(), gomock.Any(), web.CashSessionOpenRequest{StoreID: "JKT01", EmployeeID: "E1", OpeningFloat: money.IDR(100000)}).Return(cashSession, nil)
+		}, expectedStatus: http.StatusCreated},
+		{name: "list closed cash sessions", method: http.MethodGet, url: "/api/cash-sessions?status=closed", storeId: "JKT01", setupMock: func() {
+			services.cash.EXPECT().FindAll(gomock.Any(), "JKT01", "closed").Return([]web.CashSessionResponse{cashSession}, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "get cash session", method: http.MethodGet, url: "/api/cash-sessions/S1", setupMock: func() {
+			services.cash.EXPECT().FindById(gomock.Any(), gomock.Any(), "S1").Return(cashSession, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "take out more cash than the drawer holds", method: http.MethodPost, url: "/api/cash-sessions/S1/movements", body: map[string]interface{}{"employee_id": "E1", "type": "cash_out", "amount": "500000", "reason": "setor"}, setupMock: func() {
+			services.cash.EXPECT().RecordMovement(gomock.Any(), gomock.Any(), web.CashMovementRequest{SessionID: "S1", EmployeeID: "E1", Type: domain.CashOut, Amount: money.IDR(500000), Reason: "setor"}).Return(web.CashSessionResponse{}, exception.NewBadRequestError("the drawer is expected to hold 150000"))
+		}, expectedStatus: http.StatusBadRequest},
+		{name: "close cash session", method: http.MethodPost, url: "/api/cash-sessions/S1/close", body: map[string]interface{}{"employee_id": "E1", "counts": []map[string]interface{}{{"denomination": "50000", "quantity": 2}, {"denomination": "1000", "quantity": 49}}}, setupMock: func() {
+			services.cash.EXPECT().Close(gomock.Any(), gomock.Any(), web.CashSessionCloseRequest{SessionID: "S1", EmployeeID: "E1", Counts: []web.CashCountRequest{{Denomination: money.IDR(50000), Quantity: 2}, {Denomination: money.IDR(1000), Quantity: 49}}}).Return(cashSession, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "z report of cash session", method: http.MethodGet, url: "/api/cash-sessions/S1/report", setupMock: func() {
+			services.cash.EXPECT().Report(gomock.Any(), gomock.Any(), "S1").Return(cashReport, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "print report of unknown cash session", method: http.MethodGet, url: "/api/cash-sessions/S404/report/print?paper=58", setupMock: func() {
+			services.cash.EXPECT().PrintReport(gomock.Any(), gomock.Any(), "S404").Return(receipt.Report{}, exception.NewNotFoundError("Cash session not found"))
+		}, expectedStatus: http.StatusNotFound},
+		{name: "print report on unknown paper", method: http.MethodGet, url: "/api/cash-sessions/S1/report/print?paper=100", setupMock: func() {}, expectedStatus: http.StatusBadRequest},
+		{name: "store report of a day", method: http.MethodGet, url: "/api/cash-reports?date=2026-10-19", storeId: "JKT01", setupMock: func() {
+			cashReport := cashReport
+			cashReport.SessionID, cashReport.EmployeeID, cashReport.Sessions = "", "", []web.CashSessionResponse{cashSession}
+			services.cash.EXPECT().StoreReport(gomock.Any(), gomock.Any(), "JKT01", "2026-10-19").Return(cashReport, nil)
+		}, expectedStatus: http.StatusOK},
+		{name: "print store report of a bad date", method: http.MethodGet, url: "/api/cash-reports/print?date=2026-02-30", storeId: "JKT01", setupMock: func() {}, expectedStatus: http.StatusBadRequest},
+
+		{name: "stream forbidden topic", method: http.MethodGet, url: "/api/stream?topics=customers", setupMock: func() {
+			services.stream.EXPECT().Subscribe(gomock.Any(), gomock.Any(), "customers").Return(nil, exception.NewForbiddenError("dashboard is not allowed to subscribe to customers"))
+		}, expectedStatus: http.StatusForbidden},
+		{name: "stream unknown topic", method: http.MethodGet, url: "/api/stream?topics=weather", setupMock: func() {
+			services.stream.EXPECT().Subscribe(gomock.Any(), gomock.Any(), "weather").Return(nil, exception.NewBadRequestError(`unknown topic "weather"`))
+		}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			var reqBody []byte
+			if tt.body != nil {
+				reqBody, _ = json.Marshal(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", "RAHASIA")
+			if tt.storeId != "" {
+				req.Header.Set("X-Store-ID", tt.storeId)
+			}
+
+			resp, err := server.Test(req)
+			assert.NoError(t, err)
+
+			var respBody web.WebResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.NotEqual(t, "Contract Violation", respBody.Status, "%v", respBody.Data)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestValidationMiddlewareRejectsInvalidRequests(t *testing.T) {
+	server, _ := setupTestAppContract(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		expected []interface{}
+	}{
+		{
+			name:   "invalid path parameter",
+			method: http.MethodGet,
+			url:    "/api/categories/abc",
+			expected: []interface{}{
+				map[string]interface{}{"in": "path", "pointer": "/categoryId", "message": `must be integer, got "abc"`},
+			},
+		},
+		{
+			name:   "invalid body",
+			method: http.MethodPost,
+			url:    "/api/customers",
+			body:   `{"name":"Budi","email":"budi","phone":"0812","loyalty_points":-5}`,
+			expected: []interface{}{
+				map[string]interface{}{"in": "body", "pointer": "/email", "message": "must be a valid email address"},
+				map[string]interface{}{"in": "body", "pointer": "/loyalty_points", "message": "must be greater than or equal to 0"},
+			},
+		},
+		{
+			name:   "missing body",
+			method: http.MethodPost,
+			url:    "/api/products",
+			expected: []interface{}{
+				map[string]interface{}{"in": "body", "pointer": "", "message": "request body is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", "RAHASIA")
+
+			resp, err := server.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var respBody web.WebResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expected, respBody.Data)
+		})
+	}
+}
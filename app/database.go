@@ -9,18 +9,26 @@ import (
 )
 
 // NewDB initializes the database connection using GORM
-func NewDB() *gorm.DB {
-	dsn := "root:Rt0011Rw007@tcp(localhost:3306)/struct_db?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // Logging SQL queries
-	})
+func NewDB(config Config) *gorm.DB {
+	db, err := OpenDB(config, logger.Default.LogMode(logger.Info)) // Logging SQL queries
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	log.Println("Database connected successfully!")
+	return db
+}
+
+// OpenDB connects to the MySQL database of config, queries are reported to gormLogger
+func OpenDB(config Config, gormLogger logger.Interface) (*gorm.DB, error) {
+	db, err := gorm.Open(mysql.Open(config.DatabaseDSN), &gorm.Config{Logger: gormLogger})
+	if err != nil {
+		return nil, err
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Failed to get database instance: %v", err)
+		return nil, err
 	}
 
 	// Set database connection pool settings
@@ -28,7 +36,5 @@ func NewDB() *gorm.DB {
 	sqlDB.SetMaxOpenConns(20)
 	sqlDB.SetConnMaxLifetime(60 * time.Minute)
 	sqlDB.SetConnMaxIdleTime(10 * time.Minute)
-
-	log.Println("Database connected successfully!")
-	return db
+	return db, nil
 }
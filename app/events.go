@@ -0,0 +1,40 @@
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/stream"
+	"github.com/aronipurwanto/go-restful-api/webhook"
+)
+
+// NewRelay returns the outbox relay with every event handler registered
+func NewRelay(config Config, transactor repository.Transactor, outbox repository.OutboxRepository, dispatcher *webhook.Dispatcher, broker *stream.Broker) *event.Relay {
+	relay := event.NewRelay(transactor, outbox, config.OutboxPollInterval)
+	relay.Handle(dispatcher.Handle)
+	relay.Handle(broker.Handle)
+	return relay
+}
+
+// NewStreamBroker returns the fan-out of relayed events to the Server-Sent Events clients
+func NewStreamBroker(config Config) *stream.Broker {
+	return stream.NewBroker(config.StreamBuffer)
+}
+
+// NewStreamController returns the Server-Sent Events endpoint
+func NewStreamController(config Config, streamService service.StreamService) controller.StreamController {
+	return controller.NewStreamController(streamService, config.StreamHeartbeat)
+}
+
+// NewWebhookSender returns the worker posting webhook deliveries
+func NewWebhookSender(config Config, webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) *webhook.Sender {
+	return webhook.NewSender(webhooks, deliveries, webhook.SenderConfig{
+		MaxAttempts: config.WebhookMaxAttempts,
+		Backoff:     config.WebhookBackoff,
+		MaxBackoff:  config.WebhookMaxBackoff,
+		Timeout:     config.WebhookTimeout,
+		Interval:    config.OutboxPollInterval,
+		Concurrency: config.WebhookConcurrency,
+	})
+}
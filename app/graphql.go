@@ -0,0 +1,24 @@
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/gql"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// NewGraphQLExecutor returns the GraphQL schema on top of the services, limited by the configuration
+func NewGraphQLExecutor(config Config, categoryService service.CategoryService, customerService service.CustomerService, employeeService service.EmployeeService, productService service.ProductService, storeService service.StoreService) *gql.Executor {
+	executor, err := gql.NewExecutor(gql.Services{
+		Category: categoryService,
+		Customer: customerService,
+		Employee: employeeService,
+		Product:  productService,
+		Store:    storeService,
+	}, gql.Limits{
+		MaxDepth:      config.GraphQLMaxDepth,
+		MaxComplexity: config.GraphQLMaxComplexity,
+		ListFactor:    config.GraphQLListFactor,
+	})
+	helper.PanicIfError(err)
+	return executor
+}
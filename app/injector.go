@@ -0,0 +1,30 @@
+//go:build wireinject
+// +build wireinject
+
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+// InitializeApplication builds the application from the environment and the MySQL database
+func InitializeApplication() *Application {
+	wire.Build(NewConfig, metrics.NewMetrics, NewInstrumentedDB, ServerSet)
+	return nil
+}
+
+// InitializeApplicationWithConfig builds the application from an already loaded configuration
+// and the MySQL database, used by the serve command
+func InitializeApplicationWithConfig(config Config) *Application {
+	wire.Build(metrics.NewMetrics, NewInstrumentedDB, ServerSet)
+	return nil
+}
+
+// InitializeApplicationWithDB builds the application on top of an already opened database,
+// used by the integration tests to run against SQLite
+func InitializeApplicationWithDB(config Config, db *gorm.DB) *Application {
+	wire.Build(metrics.NewMetrics, ServerSet)
+	return nil
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Models lists every domain model managed by the auto migration
+func Models() []interface{} {
+	return []interface{}{
+		&domain.Category{},
+		&domain.Customer{},
+		&domain.TaxClass{},
+		&domain.Product{},
+		&domain.ProductOption{},
+		&domain.ProductVariant{},
+		&domain.ProductVariantOption{},
+		&domain.Employee{},
+		&domain.Store{},
+		&domain.StoreStock{},
+		&domain.StoreVariantStock{},
+		&domain.EmployeeStore{},
+		&domain.StockTransfer{},
+		&domain.StockTransferLine{},
+		&domain.Promotion{},
+		&domain.Order{},
+		&domain.OrderLine{},
+		&domain.Payment{},
+		&domain.PaymentTender{},
+		&domain.PaymentNotification{},
+		&domain.Return{},
+		&domain.ReturnLine{},
+		&domain.CashSession{},
+		&domain.CashMovement{},
+		&domain.OutboxEvent{},
+		&domain.Webhook{},
+		&domain.WebhookDelivery{},
+		&domain.APIKey{},
+	}
+}
+
+// migrateTaxClasses creates the default tax classes and gives the products without a tax class,
+// those created when products only had a tax rate, the class of their rate. A rate below 1 is a
+// fraction, 0.11 is 11%; the product then keeps the rate as a percent. A PPN class is created
+// for a rate no class has. It changes nothing once every product has a class.
+func migrateTaxClasses(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, class := range tax.DefaultClasses() {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&class).Error; err != nil {
+				return err
+			}
+		}
+
+		withoutClass := tx.Model(&domain.Product{}).Where("tax_class_id = '' OR tax_class_id IS NULL")
+		var rates []float64
+		if err := withoutClass.Session(&gorm.Session{}).Distinct().Pluck("tax_rate", &rates).Error; err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			return nil
+		}
+		var classes []domain.TaxClass
+		if err := tx.Order("tax_class_id").Find(&classes).Error; err != nil {
+			return err
+		}
+
+		for _, rate := range rates {
+			percent := legacyPercent(rate)
+			class, ok := classOfRate(classes, percent)
+			if !ok {
+				name := fmt.Sprintf("PPN %v%%", percent)
+				class = domain.TaxClass{
+					TaxClassID: legacyClassID(classes, percent),
+					Name:       name,
+					Rates:      []domain.TaxRate{{Code: "PPN", Name: name, Percent: percent}},
+				}
+				if err := tx.Create(&class).Error; err != nil {
+					return err
+				}
+				classes = append(classes, class)
+			}
+			err := withoutClass.Session(&gorm.Session{}).Where("tax_rate = ?", rate).
+				Updates(map[string]interface{}{"tax_class_id": class.TaxClassID, "tax_rate": percent}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// maxTaxClassID is the size of the tax_class_id columns
+const maxTaxClassID = 20
+
+// legacyPercent is the percent of a rate stored before tax classes, some clients sent fractions
+func legacyPercent(rate float64) float64 {
+	if rate > 0 && rate < 1 {
+		// 0.11 * 100 is 11.000000000000002, the percents keep at most 6 decimals
+		return math.Round(rate*100*1e6) / 1e6
+	}
+	return rate
+}
+
+// legacyClassID is ppn- followed by the percent, cut to the size of the column and numbered
+// when another class has the ID already
+func legacyClassID(classes []domain.TaxClass, percent float64) string {
+	taken := make(map[string]bool, len(classes))
+	for _, class := range classes {
+		taken[class.TaxClassID] = true
+	}
+	base := "ppn-" + strconv.FormatFloat(percent, 'f', -1, 64)
+	for n := 1; ; n++ {
+		var suffix string
+		if n > 1 {
+			suffix = "-" + strconv.Itoa(n)
+		}
+		id := base
+		if len(id)+len(suffix) > maxTaxClassID {
+			id = id[:maxTaxClassID-len(suffix)]
+		}
+		if id += suffix; !taken[id] {
+			return id
+		}
+	}
+}
+
+func classOfRate(classes []domain.TaxClass, rate float64) (domain.TaxClass, bool) {
+	for _, class := range classes {
+		if class.Percent() == rate {
+			return class, true
+		}
+	}
+	return domain.TaxClass{}, false
+}
+
+// migrateActivePayments sets the active order of the payments recorded before it had a column,
+// those that did not fail. The oldest one wins when an order has several, the others keep none.
+func migrateActivePayments(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var active []string
+		if err := tx.Model(&domain.Payment{}).Where("active_order_id IS NOT NULL").Pluck("active_order_id", &active).Error; err != nil {
+			return err
+		}
+		paid := make(map[string]bool, len(active))
+		for _, orderId := range active {
+			paid[orderId] = true
+		}
+
+		var payments []domain.Payment
+		err := tx.Select("payment_id", "order_id").Where("active_order_id IS NULL AND status <> ?", domain.PaymentFailed).
+			Order("created_at").Order("payment_id").Find(&payments).Error
+		if err != nil {
+			return err
+		}
+		for _, payment := range payments {
+			if paid[payment.OrderID] {
+				continue
+			}
+			paid[payment.OrderID] = true
+			if err := tx.Model(&domain.Payment{}).Where("payment_id = ?", payment.PaymentID).Update("active_order_id", payment.OrderID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateOutboxSequences numbers the events published before they had a sequence with their ID,
+// the streams resuming from one of those IDs keep receiving what follows it
+func migrateOutboxSequences(db *gorm.DB) error {
+	return db.Model(&domain.OutboxEvent{}).Where("sequence IS NULL AND published_at IS NOT NULL").
+		Update("sequence", gorm.Expr("id")).Error
+}
+
+// migrateOpenCashSessions sets the open key of the sessions opened before it had a column. The
+// oldest one keeps it when an employee has several open in a store, the others keep none.
+func migrateOpenCashSessions(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var keys []string
+		if err := tx.Model(&domain.CashSession{}).Where("open_key IS NOT NULL").Pluck("open_key", &keys).Error; err != nil {
+			return err
+		}
+		open := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			open[key] = true
+		}
+
+		var sessions []domain.CashSession
+		err := tx.Select("session_id", "store_id", "employee_id").Where("open_key IS NULL AND status = ?", domain.CashSessionOpen).
+			Order("opened_at").Order("session_id").Find(&sessions).Error
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			key := domain.CashSessionOpenKey(session.StoreID, session.EmployeeID)
+			if open[key] {
+				continue
+			}
+			open[key] = true
+			if err := tx.Model(&domain.CashSession{}).Where("session_id = ?", session.SessionID).Update("open_key", key).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migratePromotionAmounts gives the fixed amount and bundle price promotions created when their
+// amount was the value, a number of rupiah, that amount in minor units of the default currency
+func migratePromotionAmounts(db *gorm.DB) error {
+	return db.Model(&domain.Promotion{}).
+		Where("type IN ? AND amount = 0 AND value > 0", []string{domain.PromotionFixedAmount, domain.PromotionBundlePrice}).
+		Updates(map[string]interface{}{"amount": gorm.Expr("ROUND(value)"), "value": 0}).Error
+}
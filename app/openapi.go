@@ -0,0 +1,210 @@
+package app
+
+import (
+	"reflect"
+
+	"github.com/aronipurwanto/go-restful-api/gql"
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewOpenAPIBuilder describes the API-wide parts of the document: servers, authentication and
+// the WebResponse envelope every JSON endpoint answers with
+func NewOpenAPIBuilder() *openapi.Builder {
+	return &openapi.Builder{
+		Info: openapi.Info{
+			Title:       "Product Management RESTful API",
+			Description: "API Spec for categories, customers, employees, products, stores, stock transfers, promotions, pricing, orders, card and QRIS payments, returns, cash sessions with X and Z reports, webhooks, the change stream and GraphQL",
+			Version:     "1.0.0",
+		},
+		Servers: []openapi.Server{{URL: "http://localhost:8080"}},
+		Envelope: func(data *openapi.Schema) *openapi.Schema {
+			return &openapi.Schema{
+				Type: "object",
+				Properties: map[string]*openapi.Schema{
+					"code":   {Type: "integer", Format: "int32"},
+					"status": {Type: "string"},
+					"data":   data,
+				},
+				Required: []string{"code", "status", "data"},
+			}
+		},
+		SecuredPrefix:  "/api",
+		SecurityName:   "ApiKeyAuth",
+		SecurityScheme: &openapi.SecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"},
+		Types: map[reflect.Type]*openapi.Schema{
+			reflect.TypeOf(money.Money{}): {Description: "Decimal string in major units, e.g. \"35000\". Numbers are accepted in requests during the transition from float prices."},
+		},
+	}
+}
+
+// NewValidationMiddleware checks the requests, and the responses when enabled, against the document
+func NewValidationMiddleware(document func() *openapi.Document, validateResponses bool) fiber.Handler {
+	return openapi.NewValidationMiddleware(openapi.MiddlewareConfig{
+		Document:          document,
+		ValidateResponses: validateResponses,
+		ErrorHandler: func(c *fiber.Ctx, status int, errs []openapi.ValidationError) error {
+			webStatus := "Bad Request"
+			if status == fiber.StatusInternalServerError {
+				webStatus = "Contract Violation"
+			}
+			return c.Status(status).JSON(web.WebResponse{
+				Code:   status,
+				Status: webStatus,
+				Data:   errs,
+			})
+		},
+	})
+}
+
+// storeContext documents the header read by the store middleware
+var storeContext = []openapi.Parameter{
+	{Name: "X-Store-ID", In: "header", Description: "Store to work on, defaults to the store the API key is bound to", Schema: &openapi.Schema{Type: "string"}},
+}
+
+// APIEndpoints documents every route registered by NewRouter; TestEveryRouteIsDocumented fails
+// when a route is added without an entry here
+func APIEndpoints() []openapi.Endpoint {
+	return []openapi.Endpoint{
+		// Operations
+		{Method: fiber.MethodGet, Path: "/healthz", Tag: "Operations", Summary: "Liveness probe"},
+		{Method: fiber.MethodGet, Path: "/readyz", Tag: "Operations", Summary: "Readiness probe with dependency checks", Response: health.Report{}, Responses: map[int]interface{}{fiber.StatusServiceUnavailable: health.Report{}}},
+		{Method: fiber.MethodGet, Path: "/metrics", Tag: "Operations", Summary: "Prometheus metrics", ContentType: "text/plain"},
+		{Method: fiber.MethodGet, Path: "/openapi.json", Tag: "Operations", Summary: "OpenAPI document", ContentType: fiber.MIMEApplicationJSON},
+		{Method: fiber.MethodGet, Path: "/docs", Tag: "Operations", Summary: "Interactive API documentation", ContentType: fiber.MIMETextHTML},
+
+		// GraphQL API
+		{Method: fiber.MethodPost, Path: "/graphql", Tag: "GraphQL API", Summary: "Run a GraphQL query or mutation on categories, customers, employees and products", Secured: true, Unwrapped: true, Request: gql.GraphQLRequest{}, Response: gql.GraphQLResponse{}, Responses: map[int]interface{}{fiber.StatusBadRequest: gql.GraphQLResponse{}}},
+
+		// Category API
+		{Method: fiber.MethodGet, Path: "/api/categories/", Tag: "Category API", Summary: "List all categories", Response: []web.CategoryResponse{}},
+		{Method: fiber.MethodGet, Path: "/api/categories/:categoryId", Tag: "Category API", Summary: "Get category by id", PathParams: map[string]string{"categoryId": "integer"}, Response: web.CategoryResponse{}},
+		{Method: fiber.MethodPost, Path: "/api/categories/", Tag: "Category API", Summary: "Create new category", Request: web.CategoryCreateRequest{}, Response: web.CategoryResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPut, Path: "/api/categories/:categoryId", Tag: "Category API", Summary: "Update category by id", PathParams: map[string]string{"categoryId": "integer"}, Request: web.CategoryUpdateRequest{}, RequestOmit: []string{"Id"}, Response: web.CategoryResponse{}},
+		{Method: fiber.MethodDelete, Path: "/api/categories/:categoryId", Tag: "Category API", Summary: "Delete category by id", PathParams: map[string]string{"categoryId": "integer"}},
+
+		// Customer API
+		{Method: fiber.MethodGet, Path: "/api/customers/", Tag: "Customer API", Summary: "List all customers", Response: []web.CustomerResponse{}},
+		{Method: fiber.MethodGet, Path: "/api/customers/:customerId", Tag: "Customer API", Summary: "Get customer by id", Response: web.CustomerResponse{}},
+		{Method: fiber.MethodPost, Path: "/api/customers/", Tag: "Customer API", Summary: "Create new customer", Request: web.CustomerCreateRequest{}, Response: web.CustomerResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPut, Path: "/api/customers/:customerId", Tag: "Customer API", Summary: "Update customer by id", Request: web.CustomerUpdateRequest{}, RequestOmit: []string{"CustomerID"}, Response: web.CustomerResponse{}},
+		{Method: fiber.MethodDelete, Path: "/api/customers/:customerId", Tag: "Customer API", Summary: "Delete customer by id"},
+
+		// Employee API
+		{Method: fiber.MethodGet, Path: "/api/employees/", Tag: "Employee API", Summary: "List all employees, only those of the store in a store context", Query: storeContext, Response: []web.EmployeeResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/employees/:employeeId", Tag: "Employee API", Summary: "Get employee by id, only an employee of the store in a store context", Query: storeContext, Response: web.EmployeeResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/employees/", Tag: "Employee API", Summary: "Create new employee", Request: web.EmployeeCreateRequest{}, Response: web.EmployeeResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPut, Path: "/api/employees/:employeeId", Tag: "Employee API", Summary: "Update employee by id", Request: web.EmployeeUpdateRequest{}, RequestOmit: []string{"EmployeeID"}, Response: web.EmployeeResponse{}},
+		{Method: fiber.MethodDelete, Path: "/api/employees/:employeeId", Tag: "Employee API", Summary: "Delete employee by id"},
+
+		// Product API
+		{Method: fiber.MethodGet, Path: "/api/products/", Tag: "Product API", Summary: "List all products, with the stock and prices of the store in a store context", Query: append([]openapi.Parameter{
+			{Name: "options", In: "query", Description: "Comma separated name:value pairs, e.g. size:M,colour:Merah, keeps the products with a variant of those values and only those variants", Schema: &openapi.Schema{Type: "string"}},
+		}, storeContext...), Response: []web.ProductResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/products/:productId", Tag: "Product API", Summary: "Get product by id, with the stock and price of the store in a store context", Query: storeContext, Response: web.ProductResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/products/", Tag: "Product API", Summary: "Create new product with its variants", Request: web.ProductCreateRequest{}, Response: web.ProductResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPut, Path: "/api/products/:productId", Tag: "Product API", Summary: "Update product by id, the variants given replace those of the product", Request: web.ProductUpdateRequest{}, RequestOmit: []string{"ProductID"}, Response: web.ProductResponse{}},
+		{Method: fiber.MethodDelete, Path: "/api/products/:productId", Tag: "Product API", Summary: "Delete product by id"},
+
+		// Store API
+		{Method: fiber.MethodGet, Path: "/api/stores/", Tag: "Store API", Summary: "List the stores the caller may access", Response: []web.StoreResponse{}},
+		{Method: fiber.MethodGet, Path: "/api/stores/:storeId", Tag: "Store API", Summary: "Get store by id", Response: web.StoreResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/stores/", Tag: "Store API", Summary: "Create new store, requires the cross-store permission", Request: web.StoreCreateRequest{}, Response: web.StoreResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPut, Path: "/api/stores/:storeId", Tag: "Store API", Summary: "Update store by id, requires the cross-store permission", Request: web.StoreUpdateRequest{}, RequestOmit: []string{"StoreID"}, Response: web.StoreResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodDelete, Path: "/api/stores/:storeId", Tag: "Store API", Summary: "Delete store with its stock, requires the cross-store permission", Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/stores/:storeId/stock", Tag: "Store API", Summary: "Stock and price overrides of a store", Response: []web.StoreStockResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPut, Path: "/api/stores/:storeId/stock/:productId", Tag: "Store API", Summary: "Set the stock and the price override of a product in a store", Request: web.StoreStockUpdateRequest{}, RequestOmit: []string{"StoreID", "ProductID"}, Response: web.StoreStockResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+
+		// Stock Transfer API
+		{Method: fiber.MethodGet, Path: "/api/transfers/", Tag: "Stock Transfer API", Summary: "List the transfers of the store context, newest first", Query: append([]openapi.Parameter{
+			{Name: "status", In: "query", Description: "open, draft, dispatched, partially_received, received or cancelled", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"open", "draft", "dispatched", "partially_received", "received", "cancelled"}}},
+		}, storeContext...), Response: []web.StockTransferResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/transfers/:transferId", Tag: "Stock Transfer API", Summary: "Get stock transfer by id", Response: web.StockTransferResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/transfers/", Tag: "Stock Transfer API", Summary: "Prepare a draft transfer, an empty store id is the central stock", Request: web.StockTransferCreateRequest{}, Response: web.StockTransferResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPut, Path: "/api/transfers/:transferId", Tag: "Stock Transfer API", Summary: "Update the lines of a draft transfer", Request: web.StockTransferUpdateRequest{}, RequestOmit: []string{"TransferID"}, Response: web.StockTransferResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/transfers/:transferId/cancel", Tag: "Stock Transfer API", Summary: "Cancel a draft transfer", Response: web.StockTransferResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/transfers/:transferId/dispatch", Tag: "Stock Transfer API", Summary: "Dispatch a draft transfer, its stock leaves the source", Response: web.StockTransferResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/transfers/:transferId/receive", Tag: "Stock Transfer API", Summary: "Receive all or part of a dispatched transfer at the destination", Request: web.StockTransferReceiveRequest{}, RequestOmit: []string{"TransferID"}, Response: web.StockTransferResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+
+		// Promotion API
+		{Method: fiber.MethodGet, Path: "/api/promotions/", Tag: "Promotion API", Summary: "List all promotions, highest priority first", Response: []web.PromotionResponse{}},
+		{Method: fiber.MethodGet, Path: "/api/promotions/:promotionId", Tag: "Promotion API", Summary: "Get promotion by id", Response: web.PromotionResponse{}},
+		{Method: fiber.MethodPost, Path: "/api/promotions/", Tag: "Promotion API", Summary: "Create new promotion", Request: web.PromotionCreateRequest{}, Response: web.PromotionResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPut, Path: "/api/promotions/:promotionId", Tag: "Promotion API", Summary: "Update promotion by id", Request: web.PromotionUpdateRequest{}, RequestOmit: []string{"PromotionID"}, Response: web.PromotionResponse{}},
+		{Method: fiber.MethodDelete, Path: "/api/promotions/:promotionId", Tag: "Promotion API", Summary: "Delete promotion by id"},
+
+		// Pricing API
+		{Method: fiber.MethodPost, Path: "/api/pricing/quote", Tag: "Pricing API", Summary: "Price a basket with the promotions in effect and the tax of every product, at the prices and with the tax settings of the store context", Query: storeContext, Request: web.QuoteRequest{}, Response: web.QuoteResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/tax-classes", Tag: "Pricing API", Summary: "List the tax classes products refer to", Response: []web.TaxClassResponse{}},
+
+		// Order API
+		{Method: fiber.MethodGet, Path: "/api/orders/", Tag: "Order API", Summary: "List the orders of the store context, newest first", Query: storeContext, Response: []web.OrderResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/orders/:orderId", Tag: "Order API", Summary: "Get order by id", Response: web.OrderResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/orders/:orderId/receipt", Tag: "Order API", Summary: "Receipt of an order as HTML, plain text or ESC/POS commands for a thermal printer", Query: []openapi.Parameter{
+			{Name: "format", In: "query", Description: "html, text (default) or escpos", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"html", "text", "escpos"}}},
+			{Name: "paper", In: "query", Description: "Width of the paper in millimetres, 58 or 80 (default)", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"58", "80"}}},
+		}, ContentTypes: []string{fiber.MIMETextHTML, fiber.MIMETextPlain, fiber.MIMEOctetStream}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/orders/", Tag: "Order API", Summary: "Record a sale in the store context at the prices of a quote, the customer earns loyalty points", Query: storeContext, Request: web.OrderCreateRequest{}, Response: web.OrderResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+
+		// Payment API
+		{Method: fiber.MethodGet, Path: "/api/payments/", Tag: "Payment API", Summary: "List the payments of the store context, newest first", Query: append([]openapi.Parameter{
+			{Name: "customer_id", In: "query", Description: "Only the payments of this customer", Schema: &openapi.Schema{Type: "string"}},
+		}, storeContext...), Response: []web.PaymentResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/payments/:paymentId", Tag: "Payment API", Summary: "Get payment by id", Response: web.PaymentResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/payments/", Tag: "Payment API", Summary: "Pay an order with cash, cards and loyalty points adding up to its total, a declined card fails the payment and gives the other tenders back", Request: web.PaymentCreateRequest{}, Response: web.PaymentResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: "", fiber.StatusNotFound: ""}},
+		{Method: fiber.MethodPost, Path: "/api/payments/:paymentId/sync", Tag: "Payment API", Summary: "Ask the gateway how the pending cards of a payment ended", Response: web.PaymentResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/payments/webhooks/:provider", Tag: "Payment API", Summary: "Notification of a payment gateway, authenticated by its signature, a redelivered notification is acknowledged as a duplicate", Query: []openapi.Parameter{
+			{Name: "X-Payment-Timestamp", In: "header", Required: true, Description: "Unix timestamp of the notification", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "X-Payment-Signature", In: "header", Required: true, Description: "Hex HMAC-SHA256 of <timestamp>.<body> with PAYMENT_WEBHOOK_SECRET", Schema: &openapi.Schema{Type: "string"}},
+		}, Request: payment.Notification{}, Response: web.PaymentNotificationResponse{}, Responses: map[int]interface{}{fiber.StatusUnauthorized: "", fiber.StatusNotFound: ""}},
+		{Method: fiber.MethodPost, Path: "/api/payments/qris", Tag: "Payment API", Summary: "QRIS payload paying the total of an order or an amount, static without either, optionally with its QR code as a PNG or SVG data URI", Request: web.QRISCreateRequest{}, Response: web.QRISResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: "", fiber.StatusNotFound: ""}},
+		{Method: fiber.MethodPost, Path: "/api/payments/qris/verify", Tag: "Payment API", Summary: "Check the checksum and the fields of a QRIS payload scanned back and read it", Request: web.QRISVerifyRequest{}, Response: web.QRISResponse{}},
+
+		// Return API
+		{Method: fiber.MethodGet, Path: "/api/returns/", Tag: "Return API", Summary: "List the returns of the store context, newest first", Query: append([]openapi.Parameter{
+			{Name: "status", In: "query", Description: "pending, completed or rejected", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"pending", "completed", "rejected"}}},
+		}, storeContext...), Response: []web.ReturnResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/returns/:returnId", Tag: "Return API", Summary: "Get return by id", Response: web.ReturnResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/returns/", Tag: "Return API", Summary: "Return quantities of the lines of an order, restocked or written off; the refund is completed at once up to the approval threshold and waits for a supervisor above it", Request: web.ReturnCreateRequest{}, Response: web.ReturnResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: "", fiber.StatusNotFound: ""}},
+		{Method: fiber.MethodPost, Path: "/api/returns/:returnId/approve", Tag: "Return API", Summary: "Approve a pending return by a supervisor: the stock, the loyalty points and the refund are settled", Request: web.ReturnDecisionRequest{}, RequestOmit: []string{"ReturnID"}, Response: web.ReturnResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/returns/:returnId/reject", Tag: "Return API", Summary: "Reject a pending return by a supervisor", Request: web.ReturnDecisionRequest{}, RequestOmit: []string{"ReturnID"}, Response: web.ReturnResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+
+		// Cash Session API
+		{Method: fiber.MethodGet, Path: "/api/cash-sessions/", Tag: "Cash Session API", Summary: "List the cash sessions of the store context, newest first", Query: append([]openapi.Parameter{
+			{Name: "status", In: "query", Description: "open or closed", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"open", "closed"}}},
+		}, storeContext...), Response: []web.CashSessionResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/cash-sessions/:sessionId", Tag: "Cash Session API", Summary: "Get cash session by id, with its cash movements", Response: web.CashSessionResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/cash-sessions/", Tag: "Cash Session API", Summary: "Open a cash session of an employee in the store context with an opening float, one open session per employee and store", Query: storeContext, Request: web.CashSessionOpenRequest{}, RequestOmit: []string{"StoreID"}, Response: web.CashSessionResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: "", fiber.StatusNotFound: ""}},
+		{Method: fiber.MethodPost, Path: "/api/cash-sessions/:sessionId/movements", Tag: "Cash Session API", Summary: "Put cash in or take cash out of the drawer of an open session, not more than the drawer is expected to hold", Request: web.CashMovementRequest{}, RequestOmit: []string{"SessionID"}, Response: web.CashSessionResponse{}, Status: fiber.StatusCreated, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodPost, Path: "/api/cash-sessions/:sessionId/close", Tag: "Cash Session API", Summary: "Close a session with the notes and coins counted in the drawer, the variance is the counted minus the expected cash", Request: web.CashSessionCloseRequest{}, RequestOmit: []string{"SessionID"}, Response: web.CashSessionResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/cash-sessions/:sessionId/report", Tag: "Cash Session API", Summary: "X report of an open session so far, Z report of a closed session", Response: web.CashReportResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/cash-sessions/:sessionId/report/print", Tag: "Cash Session API", Summary: "X or Z report of a session as plain text for a thermal printer", Query: []openapi.Parameter{
+			{Name: "paper", In: "query", Description: "Width of the paper in millimetres, 58 or 80 (default)", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"58", "80"}}},
+		}, ContentType: fiber.MIMETextPlain, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/cash-reports/", Tag: "Cash Session API", Summary: "Report of the sales and the cash sessions of the store context on a day, Z once every session opened that day is closed", Query: append([]openapi.Parameter{
+			{Name: "date", In: "query", Description: "Day of the report as YYYY-MM-DD, today by default", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+		}, storeContext...), Response: web.CashReportResponse{}, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+		{Method: fiber.MethodGet, Path: "/api/cash-reports/print", Tag: "Cash Session API", Summary: "Report of the store context on a day as plain text for a thermal printer", Query: append([]openapi.Parameter{
+			{Name: "date", In: "query", Description: "Day of the report as YYYY-MM-DD, today by default", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+			{Name: "paper", In: "query", Description: "Width of the paper in millimetres, 58 or 80 (default)", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"58", "80"}}},
+		}, storeContext...), ContentType: fiber.MIMETextPlain, Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+
+		// Stream API
+		{Method: fiber.MethodGet, Path: "/api/stream", Tag: "Stream API", Summary: "Server-Sent Events of the changes on the given topics, resumable with Last-Event-ID", Query: []openapi.Parameter{
+			{Name: "topics", In: "query", Required: true, Description: "Comma separated list of categories, customers, employees, products, stores, transfers, promotions, orders, payments, returns and cash_sessions", Schema: &openapi.Schema{Type: "string"}},
+		}, ContentType: "text/event-stream", Responses: map[int]interface{}{fiber.StatusForbidden: ""}},
+
+		// Webhook API
+		{Method: fiber.MethodGet, Path: "/api/webhooks/", Tag: "Webhook API", Summary: "List all webhook subscriptions", Response: []web.WebhookResponse{}},
+		{Method: fiber.MethodGet, Path: "/api/webhooks/:webhookId", Tag: "Webhook API", Summary: "Get webhook subscription by id", Response: web.WebhookResponse{}},
+		{Method: fiber.MethodPost, Path: "/api/webhooks/", Tag: "Webhook API", Summary: "Subscribe a URL to event types, the secret is only returned here", Request: web.WebhookCreateRequest{}, Response: web.WebhookResponse{}, Status: fiber.StatusCreated},
+		{Method: fiber.MethodPut, Path: "/api/webhooks/:webhookId", Tag: "Webhook API", Summary: "Update webhook subscription, a new secret rotates it", Request: web.WebhookUpdateRequest{}, RequestOmit: []string{"WebhookID"}, Response: web.WebhookResponse{}},
+		{Method: fiber.MethodDelete, Path: "/api/webhooks/:webhookId", Tag: "Webhook API", Summary: "Delete webhook subscription"},
+		{Method: fiber.MethodGet, Path: "/api/webhooks/:webhookId/deliveries", Tag: "Webhook API", Summary: "Latest deliveries of a webhook, newest first", Response: []web.WebhookDeliveryResponse{}},
+		{Method: fiber.MethodPost, Path: "/api/webhooks/:webhookId/deliveries/:deliveryId/redeliver", Tag: "Webhook API", Summary: "Queue a new delivery of the same event", Response: web.WebhookDeliveryResponse{}, Status: fiber.StatusAccepted},
+	}
+}
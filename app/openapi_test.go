@@ -0,0 +1,85 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTestAppRouter() *fiber.App {
+	server := fiber.New()
+	NewRouter(server, Config{}, NewMiddlewares(auth.StaticKeys{}), Controllers{
+		Health:      controller.NewHealthController(health.NewRegistry(0)),
+		Category:    controller.NewCategoryController(nil),
+		Customer:    controller.NewCustomerController(nil),
+		Employee:    controller.NewEmployeeController(nil, nil),
+		Product:     controller.NewProductController(nil, nil),
+		Store:       controller.NewStoreController(nil),
+		Transfer:    controller.NewStockTransferController(nil),
+		Promotion:   controller.NewPromotionController(nil),
+		Pricing:     controller.NewPricingController(nil),
+		Order:       controller.NewOrderController(nil),
+		Payment:     controller.NewPaymentController(nil),
+		Return:      controller.NewReturnController(nil),
+		CashSession: controller.NewCashSessionController(nil),
+		Webhook:     controller.NewWebhookController(nil),
+		Stream:      controller.NewStreamController(nil, time.Second),
+		GraphQL:     controller.NewGraphQLController(nil),
+	})
+	server.Get("/metrics", func(c *fiber.Ctx) error { return nil })
+	return server
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	server := setupTestAppRouter()
+
+	missing := openapi.Undocumented(server.GetRoutes(true), APIEndpoints())
+	assert.Empty(t, missing, "add these routes to APIEndpoints")
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	server := setupTestAppRouter()
+
+	resp, err := server.Test(httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var document openapi.Document
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&document))
+
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+	for _, path := range []string{"/api/categories", "/api/categories/{categoryId}", "/api/customers", "/api/employees/{employeeId}", "/api/products/{productId}", "/readyz"} {
+		assert.Contains(t, document.Paths, path)
+	}
+
+	createProduct := (*document.Paths["/api/products"])["post"]
+	assert.Contains(t, createProduct.Responses, "201")
+	assert.Equal(t, []map[string][]string{{"ApiKeyAuth": {}}}, createProduct.Security)
+	assert.Nil(t, (*document.Paths["/healthz"])["get"].Security)
+
+	categoryResponse := document.Components.Schemas["CategoryResponse"]
+	assert.Contains(t, categoryResponse.Properties, "category_id")
+	assert.Contains(t, categoryResponse.Properties, "category_name")
+
+	updateCategory := document.Components.Schemas["CategoryUpdateRequest"]
+	assert.NotContains(t, updateCategory.Properties, "Id")
+	assert.Equal(t, []string{"name"}, updateCategory.Required)
+}
+
+func TestOpenAPIUI(t *testing.T) {
+	server := setupTestAppRouter()
+
+	resp, err := server.Test(httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+}
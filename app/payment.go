@@ -0,0 +1,17 @@
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/qris"
+)
+
+// NewQRISMerchant is the merchant configured by the QRIS_* variables
+func NewQRISMerchant(config Config) qris.Merchant {
+	return config.QRISMerchant()
+}
+
+// NewPaymentProvider is the card gateway configured by PAYMENT_PROVIDER. Only the fake gateway,
+// which simulates the payment flow offline, is available so far.
+func NewPaymentProvider(config Config) payment.Provider {
+	return payment.NewFake(config.PaymentWebhookSecret, config.PaymentWebhookTolerance)
+}
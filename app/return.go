@@ -0,0 +1,8 @@
+package app
+
+import "github.com/aronipurwanto/go-restful-api/service"
+
+// NewReturnPolicy is the approval of returns configured by the RETURN_* variables
+func NewReturnPolicy(config Config) service.ReturnPolicy {
+	return config.ReturnPolicy()
+}
@@ -1,50 +1,185 @@
 package app
 
 import (
+	"github.com/aronipurwanto/go-restful-api/auth"
 	"github.com/aronipurwanto/go-restful-api/controller"
 	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/openapi"
 	"github.com/gofiber/fiber/v2"
 )
 
-func NewRouter(app *fiber.App,
-	categoryController controller.CategoryController,
-	customerController controller.CustomerController,
-	employeeController controller.EmployeeController,
-	productController controller.ProductController) {
+// Controllers groups every controller mounted by NewRouter
+type Controllers struct {
+	Health      controller.HealthController
+	Category    controller.CategoryController
+	Customer    controller.CustomerController
+	Employee    controller.EmployeeController
+	Product     controller.ProductController
+	Store       controller.StoreController
+	Transfer    controller.StockTransferController
+	Promotion   controller.PromotionController
+	Pricing     controller.PricingController
+	Order       controller.OrderController
+	Payment     controller.PaymentController
+	Return      controller.ReturnController
+	CashSession controller.CashSessionController
+	Webhook     controller.WebhookController
+	Stream      controller.StreamController
+	GraphQL     controller.GraphQLController
+}
+
+// Middlewares groups the handlers NewRouter puts in front of the API routes
+type Middlewares struct {
+	Auth fiber.Handler
+	// Store resolves the store context of the routes that are scoped to a store
+	Store fiber.Handler
+}
+
+// NewMiddlewares returns the default middlewares
+func NewMiddlewares(authenticator auth.Authenticator) Middlewares {
+	return Middlewares{
+		Auth:  middleware.NewAuthMiddleware(authenticator),
+		Store: middleware.NewStoreMiddleware(),
+	}
+}
 
-	authMiddleware := middleware.NewAuthMiddleware()
+func NewRouter(app *fiber.App, config Config, middlewares Middlewares, controllers Controllers) {
+	// Health check tanpa autentikasi untuk orchestrator
+	app.Get("/healthz", controllers.Health.Liveness)
+	app.Get("/readyz", controllers.Health.Readiness)
 
-	api := app.Group("/api", authMiddleware)
+	// Dokumentasi OpenAPI dibangun dari tabel route di atas aplikasi ini
+	document := func() *openapi.Document {
+		return NewOpenAPIBuilder().Build(app.GetRoutes(true), APIEndpoints())
+	}
+	docsController := controller.NewDocsController(document, "/openapi.json")
+	app.Get("/openapi.json", docsController.Spec)
+	app.Get("/docs", docsController.UI)
+
+	// Notifikasi gateway pembayaran, diautentikasi dengan tanda tangan bukan API key
+	app.Post("/payments/webhooks/:provider", controllers.Payment.Notify)
+
+	// GraphQL memakai autentikasi dan store context yang sama dengan /api
+	app.Post("/graphql", middlewares.Auth, middlewares.Store, controllers.GraphQL.Query)
+
+	api := app.Group("/api", middlewares.Auth)
+	if config.OpenAPIValidation {
+		api.Use(NewValidationMiddleware(document, config.OpenAPIValidateResponses))
+	}
 
 	// Routes untuk Category
 	categories := api.Group("/categories")
-	categories.Get("/", categoryController.FindAll)
-	categories.Get("/:categoryId", categoryController.FindById)
-	categories.Post("/", categoryController.Create)
-	categories.Put("/:categoryId", categoryController.Update)
-	categories.Delete("/:categoryId", categoryController.Delete)
+	categories.Get("/", controllers.Category.FindAll)
+	categories.Get("/:categoryId", controllers.Category.FindById)
+	categories.Post("/", controllers.Category.Create)
+	categories.Put("/:categoryId", controllers.Category.Update)
+	categories.Delete("/:categoryId", controllers.Category.Delete)
 
 	// Routes untuk Customer
 	customers := api.Group("/customers")
-	customers.Get("/", customerController.FindAll)
-	customers.Get("/:customerId", customerController.FindById)
-	customers.Post("/", customerController.Create)
-	customers.Put("/:customerId", customerController.Update)
-	customers.Delete("/:customerId", customerController.Delete)
+	customers.Get("/", controllers.Customer.FindAll)
+	customers.Get("/:customerId", controllers.Customer.FindById)
+	customers.Post("/", controllers.Customer.Create)
+	customers.Put("/:customerId", controllers.Customer.Update)
+	customers.Delete("/:customerId", controllers.Customer.Delete)
 
 	// Routes untuk Employee
 	employees := api.Group("/employees")
-	employees.Get("/", employeeController.FindAll)
-	employees.Get("/:employeeId", employeeController.FindById)
-	employees.Post("/", employeeController.Create)
-	employees.Put("/:employeeId", employeeController.Update)
-	employees.Delete("/:employeeId", employeeController.Delete)
+	employees.Get("/", middlewares.Store, controllers.Employee.FindAll)
+	employees.Get("/:employeeId", middlewares.Store, controllers.Employee.FindById)
+	employees.Post("/", controllers.Employee.Create)
+	employees.Put("/:employeeId", controllers.Employee.Update)
+	employees.Delete("/:employeeId", controllers.Employee.Delete)
 
 	// Routes untuk Product
 	products := api.Group("/products")
-	products.Get("/", productController.FindAll)
-	products.Get("/:productId", productController.FindById)
-	products.Post("/", productController.Create)
-	products.Put("/:productId", productController.Update)
-	products.Delete("/:productId", productController.Delete)
+	products.Get("/", middlewares.Store, controllers.Product.FindAll)
+	products.Get("/:productId", middlewares.Store, controllers.Product.FindById)
+	products.Post("/", controllers.Product.Create)
+	products.Put("/:productId", controllers.Product.Update)
+	products.Delete("/:productId", controllers.Product.Delete)
+
+	// Routes untuk Store
+	stores := api.Group("/stores")
+	stores.Get("/", controllers.Store.FindAll)
+	stores.Get("/:storeId", controllers.Store.FindById)
+	stores.Post("/", controllers.Store.Create)
+	stores.Put("/:storeId", controllers.Store.Update)
+	stores.Delete("/:storeId", controllers.Store.Delete)
+	stores.Get("/:storeId/stock", controllers.Store.FindStock)
+	stores.Put("/:storeId/stock/:productId", controllers.Store.UpdateStock)
+
+	// Routes untuk Stock Transfer
+	transfers := api.Group("/transfers")
+	transfers.Get("/", middlewares.Store, controllers.Transfer.FindAll)
+	transfers.Get("/:transferId", controllers.Transfer.FindById)
+	transfers.Post("/", controllers.Transfer.Create)
+	transfers.Put("/:transferId", controllers.Transfer.Update)
+	transfers.Post("/:transferId/cancel", controllers.Transfer.Cancel)
+	transfers.Post("/:transferId/dispatch", controllers.Transfer.Dispatch)
+	transfers.Post("/:transferId/receive", controllers.Transfer.Receive)
+
+	// Routes untuk Promotion
+	promotions := api.Group("/promotions")
+	promotions.Get("/", controllers.Promotion.FindAll)
+	promotions.Get("/:promotionId", controllers.Promotion.FindById)
+	promotions.Post("/", controllers.Promotion.Create)
+	promotions.Put("/:promotionId", controllers.Promotion.Update)
+	promotions.Delete("/:promotionId", controllers.Promotion.Delete)
+
+	// Harga keranjang dengan promosi yang berlaku
+	api.Post("/pricing/quote", middlewares.Store, controllers.Pricing.Quote)
+	api.Get("/tax-classes", controllers.Pricing.FindTaxClasses)
+
+	// Routes untuk Order
+	orders := api.Group("/orders")
+	orders.Get("/", middlewares.Store, controllers.Order.FindAll)
+	orders.Get("/:orderId", controllers.Order.FindById)
+	orders.Get("/:orderId/receipt", controllers.Order.Receipt)
+	orders.Post("/", middlewares.Store, controllers.Order.Create)
+
+	// Routes untuk Pembayaran
+	payments := api.Group("/payments")
+	payments.Get("/", middlewares.Store, controllers.Payment.FindAll)
+	payments.Post("/", controllers.Payment.Create)
+	payments.Post("/qris", controllers.Payment.CreateQRIS)
+	payments.Post("/qris/verify", controllers.Payment.VerifyQRIS)
+	payments.Get("/:paymentId", controllers.Payment.FindById)
+	payments.Post("/:paymentId/sync", controllers.Payment.Sync)
+
+	// Routes untuk Retur
+	returns := api.Group("/returns")
+	returns.Get("/", middlewares.Store, controllers.Return.FindAll)
+	returns.Get("/:returnId", controllers.Return.FindById)
+	returns.Post("/", controllers.Return.Create)
+	returns.Post("/:returnId/approve", controllers.Return.Approve)
+	returns.Post("/:returnId/reject", controllers.Return.Reject)
+
+	// Routes untuk Sesi Kas
+	cashSessions := api.Group("/cash-sessions")
+	cashSessions.Get("/", middlewares.Store, controllers.CashSession.FindAll)
+	cashSessions.Post("/", middlewares.Store, controllers.CashSession.Open)
+	cashSessions.Get("/:sessionId", controllers.CashSession.FindById)
+	cashSessions.Post("/:sessionId/movements", controllers.CashSession.RecordMovement)
+	cashSessions.Post("/:sessionId/close", controllers.CashSession.Close)
+	cashSessions.Get("/:sessionId/report", controllers.CashSession.Report)
+	cashSessions.Get("/:sessionId/report/print", controllers.CashSession.PrintReport)
+
+	// Routes untuk Laporan Kas harian toko
+	cashReports := api.Group("/cash-reports")
+	cashReports.Get("/", middlewares.Store, controllers.CashSession.StoreReport)
+	cashReports.Get("/print", middlewares.Store, controllers.CashSession.PrintStoreReport)
+
+	// Routes untuk Webhook
+	webhooks := api.Group("/webhooks")
+	webhooks.Get("/", controllers.Webhook.FindAll)
+	webhooks.Get("/:webhookId", controllers.Webhook.FindById)
+	webhooks.Post("/", controllers.Webhook.Create)
+	webhooks.Put("/:webhookId", controllers.Webhook.Update)
+	webhooks.Delete("/:webhookId", controllers.Webhook.Delete)
+	webhooks.Get("/:webhookId/deliveries", controllers.Webhook.FindDeliveries)
+	webhooks.Post("/:webhookId/deliveries/:deliveryId/redeliver", controllers.Webhook.Redeliver)
+
+	// Server-Sent Events untuk dashboard
+	api.Get("/stream", controllers.Stream.Stream)
 }
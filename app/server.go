@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/health"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK              = 0
+	ExitServerError     = 1
+	ExitShutdownTimeout = 2
+)
+
+// Listener is implemented by *fiber.App and *rpc.Server
+type Listener interface {
+	Listen(addr string) error
+	ShutdownWithContext(ctx context.Context) error
+}
+
+// Server is a Fiber application (or the gRPC server) bound to its listen address
+type Server struct {
+	App  Listener
+	Addr string
+}
+
+// Run serves every server until ctx is cancelled (typically by SIGINT/SIGTERM), then shuts down
+// gracefully: readiness is flipped to failing, listeners stop accepting connections, in-flight
+// requests and background workers are drained within config.ShutdownTimeout and the database
+// pool is closed. The returned value is meant to be used as the process exit code.
+func Run(ctx context.Context, config Config, healthRegistry *health.Registry, background *BackgroundGroup, db io.Closer, servers ...Server) int {
+	serverErr := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server Server) {
+			log.Printf("Server running on %s", server.Addr)
+			if err := server.App.Listen(server.Addr); err != nil {
+				serverErr <- err
+			}
+		}(server)
+	}
+
+	exitCode := ExitOK
+	select {
+	case <-ctx.Done():
+		log.Println("Shutdown signal received, draining requests")
+	case err := <-serverErr:
+		log.Printf("Server error: %v", err)
+		exitCode = ExitServerError
+	}
+
+	healthRegistry.MarkShuttingDown()
+	if config.ShutdownDelay > 0 {
+		// Give load balancers time to observe the failing readiness probe
+		time.Sleep(config.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.App.ShutdownWithContext(shutdownCtx); err != nil {
+			log.Printf("Failed to shutdown server %s: %v", server.Addr, err)
+			exitCode = max(exitCode, ExitShutdownTimeout)
+		}
+	}
+
+	if background != nil {
+		if err := background.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Background workers did not stop in time: %v", err)
+			exitCode = max(exitCode, ExitShutdownTimeout)
+		}
+	}
+
+	if db != nil {
+		if err := db.Close(); err != nil {
+			log.Printf("Failed to close database: %v", err)
+			exitCode = max(exitCode, ExitServerError)
+		}
+	}
+
+	log.Printf("Shutdown complete with exit code %d", exitCode)
+	return exitCode
+}
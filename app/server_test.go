@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func waitForServer(t *testing.T, addr string) {
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server %s did not start", addr)
+}
+
+func TestRunGracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name            string
+		handlerDelay    time.Duration
+		shutdownTimeout time.Duration
+		expectExitCode  int
+		expectStatus    int
+	}{
+		{
+			name:            "in-flight request is drained",
+			handlerDelay:    200 * time.Millisecond,
+			shutdownTimeout: 2 * time.Second,
+			expectExitCode:  ExitOK,
+			expectStatus:    http.StatusOK,
+		},
+		{
+			name:            "deadline exceeded",
+			handlerDelay:    time.Second,
+			shutdownTimeout: 100 * time.Millisecond,
+			expectExitCode:  ExitShutdownTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := freeAddr(t)
+			server := fiber.New(fiber.Config{DisableStartupMessage: true})
+			server.Get("/slow", func(c *fiber.Ctx) error {
+				time.Sleep(tt.handlerDelay)
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			registry := health.NewRegistry(time.Second)
+			background := NewBackgroundGroup()
+			workerStopped := make(chan struct{})
+			background.Go(func(ctx context.Context) {
+				<-ctx.Done()
+				close(workerStopped)
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			exitCode := make(chan int, 1)
+			go func() {
+				exitCode <- Run(ctx, Config{ShutdownTimeout: tt.shutdownTimeout}, registry, background, nil, Server{App: server, Addr: addr})
+			}()
+			waitForServer(t, addr)
+
+			status := make(chan int, 1)
+			go func() {
+				resp, err := http.Get("http://" + addr + "/slow")
+				if err != nil {
+					status <- 0
+					return
+				}
+				resp.Body.Close()
+				status <- resp.StatusCode
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+
+			assert.Equal(t, tt.expectExitCode, <-exitCode)
+			assert.Equal(t, health.StatusDown, registry.Check(context.Background()).Status)
+			<-workerStopped
+			if tt.expectStatus != 0 {
+				assert.Equal(t, tt.expectStatus, <-status)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/rpc"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/webhook"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/wire"
+)
+
+// Provider sets per resource, swap one of them in an injector to replace a layer (e.g. the repository)
+
+var CategorySet = wire.NewSet(
+	NewCategoryRepository,
+	service.NewCategoryService,
+	controller.NewCategoryController,
+)
+
+var CustomerSet = wire.NewSet(
+	repository.NewCustomerRepository,
+	service.NewCustomerService,
+	controller.NewCustomerController,
+)
+
+var EmployeeSet = wire.NewSet(
+	repository.NewEmployeeRepository,
+	service.NewEmployeeService,
+	controller.NewEmployeeController,
+)
+
+var ProductSet = wire.NewSet(
+	NewProductRepository,
+	service.NewProductService,
+	controller.NewProductController,
+)
+
+var StoreSet = wire.NewSet(
+	repository.NewStoreRepository,
+	repository.NewStoreStockRepository,
+	service.NewStoreService,
+	controller.NewStoreController,
+)
+
+var StockTransferSet = wire.NewSet(
+	repository.NewStockTransferRepository,
+	service.NewStockTransferService,
+	controller.NewStockTransferController,
+)
+
+var PromotionSet = wire.NewSet(
+	repository.NewPromotionRepository,
+	service.NewPromotionService,
+	controller.NewPromotionController,
+)
+
+var PricingSet = wire.NewSet(
+	repository.NewTaxClassRepository,
+	service.NewPricingService,
+	controller.NewPricingController,
+)
+
+var OrderSet = wire.NewSet(
+	repository.NewOrderRepository,
+	service.NewOrderService,
+	controller.NewOrderController,
+)
+
+var PaymentSet = wire.NewSet(
+	repository.NewPaymentRepository,
+	NewQRISMerchant,
+	NewPaymentProvider,
+	service.NewPaymentService,
+	controller.NewPaymentController,
+)
+
+var ReturnSet = wire.NewSet(
+	repository.NewReturnRepository,
+	NewReturnPolicy,
+	service.NewReturnService,
+	controller.NewReturnController,
+)
+
+var CashSessionSet = wire.NewSet(
+	repository.NewCashSessionRepository,
+	service.NewCashSessionService,
+	controller.NewCashSessionController,
+)
+
+var WebhookSet = wire.NewSet(
+	repository.NewWebhookRepository,
+	repository.NewWebhookDeliveryRepository,
+	service.NewWebhookService,
+	controller.NewWebhookController,
+)
+
+var APIKeySet = wire.NewSet(
+	repository.NewAPIKeyRepository,
+	service.NewAPIKeyService,
+)
+
+var GraphQLSet = wire.NewSet(
+	NewGraphQLExecutor,
+	controller.NewGraphQLController,
+)
+
+// RPCSet serves the same services over gRPC
+var RPCSet = wire.NewSet(
+	rpc.NewCategoryServer,
+	rpc.NewCustomerServer,
+	rpc.NewEmployeeServer,
+	rpc.NewProductServer,
+	rpc.NewServer,
+)
+
+var StreamSet = wire.NewSet(
+	NewStreamBroker,
+	service.NewStreamService,
+	NewStreamController,
+)
+
+// EventSet provides the transactional outbox, its relay and the webhook delivery workers
+var EventSet = wire.NewSet(
+	repository.NewTransactor,
+	repository.NewOutboxRepository,
+	event.NewOutboxPublisher,
+	NewRelay,
+	webhook.NewDispatcher,
+	NewWebhookSender,
+	wire.Bind(new(service.DeliveryNotifier), new(*webhook.Sender)),
+)
+
+// HealthSet provides the readiness checks and their controller
+var HealthSet = wire.NewSet(
+	NewHealthRegistry,
+	controller.NewHealthController,
+)
+
+// ServerSet builds everything on top of a Config, a *gorm.DB and a *metrics.Metrics
+var ServerSet = wire.NewSet(
+	validator.New,
+	NewCacheBackend,
+	HealthSet,
+	EventSet,
+	CategorySet,
+	CustomerSet,
+	EmployeeSet,
+	ProductSet,
+	StoreSet,
+	StockTransferSet,
+	PromotionSet,
+	PricingSet,
+	OrderSet,
+	PaymentSet,
+	ReturnSet,
+	CashSessionSet,
+	WebhookSet,
+	StreamSet,
+	GraphQLSet,
+	RPCSet,
+	APIKeySet,
+	NewAuthenticator,
+	wire.Struct(new(Services), "*"),
+	wire.Struct(new(Controllers), "*"),
+	NewMiddlewares,
+	NewServer,
+	NewBackgroundGroup,
+	wire.Struct(new(Application), "*"),
+)
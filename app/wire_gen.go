@@ -0,0 +1,383 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package app
+
+import (
+	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/rpc"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/webhook"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Injectors from injector.go:
+
+// InitializeApplication builds the application from the environment and the MySQL database
+func InitializeApplication() *Application {
+	config := NewConfig()
+	metricsMetrics := metrics.NewMetrics()
+	db := NewInstrumentedDB(config, metricsMetrics)
+	registry := NewHealthRegistry(config, db)
+	backgroundGroup := NewBackgroundGroup()
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	validate := validator.New()
+	apiKeyService := service.NewAPIKeyService(apiKeyRepository, validate)
+	authenticator := NewAuthenticator(config, apiKeyService)
+	middlewares := NewMiddlewares(authenticator)
+	healthController := controller.NewHealthController(registry)
+	backend := NewCacheBackend(config)
+	categoryRepository := NewCategoryRepository(config, db, backend, metricsMetrics)
+	transactor := repository.NewTransactor(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository(db)
+	sender := NewWebhookSender(config, webhookRepository, webhookDeliveryRepository)
+	dispatcher := webhook.NewDispatcher(webhookRepository, webhookDeliveryRepository, sender)
+	broker := NewStreamBroker(config)
+	relay := NewRelay(config, transactor, outboxRepository, dispatcher, broker)
+	publisher := event.NewOutboxPublisher(outboxRepository, relay)
+	categoryService := service.NewCategoryService(categoryRepository, transactor, publisher, validate)
+	categoryController := controller.NewCategoryController(categoryService)
+	customerRepository := repository.NewCustomerRepository(db)
+	customerService := service.NewCustomerService(customerRepository, transactor, publisher, validate)
+	customerController := controller.NewCustomerController(customerService)
+	employeeRepository := repository.NewEmployeeRepository(db)
+	storeRepository := repository.NewStoreRepository(db)
+	employeeService := service.NewEmployeeService(employeeRepository, storeRepository, transactor, publisher, validate)
+	storeStockRepository := repository.NewStoreStockRepository(db)
+	productRepository := NewProductRepository(config, db, backend, metricsMetrics)
+	storeService := service.NewStoreService(storeRepository, storeStockRepository, productRepository, employeeRepository, transactor, publisher, validate)
+	employeeController := controller.NewEmployeeController(employeeService, storeService)
+	taxClassRepository := repository.NewTaxClassRepository(db)
+	productService := service.NewProductService(productRepository, taxClassRepository, transactor, publisher, validate)
+	productController := controller.NewProductController(productService, storeService)
+	storeController := controller.NewStoreController(storeService)
+	stockTransferRepository := repository.NewStockTransferRepository(db)
+	stockTransferService := service.NewStockTransferService(stockTransferRepository, storeRepository, storeStockRepository, productRepository, transactor, publisher, validate)
+	stockTransferController := controller.NewStockTransferController(stockTransferService)
+	promotionRepository := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepository, transactor, publisher, validate)
+	promotionController := controller.NewPromotionController(promotionService)
+	pricingService := service.NewPricingService(productRepository, customerRepository, promotionRepository, storeRepository, storeStockRepository, taxClassRepository, validate)
+	pricingController := controller.NewPricingController(pricingService)
+	orderRepository := repository.NewOrderRepository(db)
+	merchant := NewQRISMerchant(config)
+	orderService := service.NewOrderService(pricingService, orderRepository, employeeRepository, customerRepository, promotionRepository, storeRepository, storeStockRepository, merchant, transactor, publisher, validate)
+	orderController := controller.NewOrderController(orderService)
+	paymentRepository := repository.NewPaymentRepository(db)
+	provider := NewPaymentProvider(config)
+	paymentService := service.NewPaymentService(orderRepository, paymentRepository, customerRepository, provider, merchant, transactor, publisher, validate)
+	paymentController := controller.NewPaymentController(paymentService)
+	returnRepository := repository.NewReturnRepository(db)
+	returnPolicy := NewReturnPolicy(config)
+	returnService := service.NewReturnService(orderRepository, returnRepository, paymentRepository, employeeRepository, customerRepository, storeStockRepository, provider, returnPolicy, transactor, publisher, validate)
+	returnController := controller.NewReturnController(returnService)
+	cashSessionRepository := repository.NewCashSessionRepository(db)
+	cashSessionService := service.NewCashSessionService(cashSessionRepository, orderRepository, paymentRepository, returnRepository, employeeRepository, storeRepository, transactor, publisher, validate)
+	cashSessionController := controller.NewCashSessionController(cashSessionService)
+	webhookService := service.NewWebhookService(webhookRepository, webhookDeliveryRepository, sender, validate)
+	webhookController := controller.NewWebhookController(webhookService)
+	streamService := service.NewStreamService(broker, outboxRepository)
+	streamController := NewStreamController(config, streamService)
+	executor := NewGraphQLExecutor(config, categoryService, customerService, employeeService, productService, storeService)
+	graphQLController := controller.NewGraphQLController(executor)
+	controllers := Controllers{
+		Health:      healthController,
+		Category:    categoryController,
+		Customer:    customerController,
+		Employee:    employeeController,
+		Product:     productController,
+		Store:       storeController,
+		Transfer:    stockTransferController,
+		Promotion:   promotionController,
+		Pricing:     pricingController,
+		Order:       orderController,
+		Payment:     paymentController,
+		Return:      returnController,
+		CashSession: cashSessionController,
+		Webhook:     webhookController,
+		Stream:      streamController,
+		GraphQL:     graphQLController,
+	}
+	app := NewServer(config, metricsMetrics, middlewares, controllers)
+	categoryServer := rpc.NewCategoryServer(categoryService)
+	customerServer := rpc.NewCustomerServer(customerService)
+	employeeServer := rpc.NewEmployeeServer(employeeService, storeService)
+	productServer := rpc.NewProductServer(productService, storeService)
+	server := rpc.NewServer(authenticator, categoryServer, customerServer, employeeServer, productServer)
+	services := Services{
+		Category:  categoryService,
+		Customer:  customerService,
+		Employee:  employeeService,
+		Product:   productService,
+		Store:     storeService,
+		Transfer:  stockTransferService,
+		Promotion: promotionService,
+		Pricing:   pricingService,
+		APIKey:    apiKeyService,
+	}
+	application := &Application{
+		Config:          config,
+		DB:              db,
+		Metrics:         metricsMetrics,
+		Health:          registry,
+		Background:      backgroundGroup,
+		Server:          app,
+		GRPC:            server,
+		Services:        services,
+		Relay:           relay,
+		WebhookSender:   sender,
+		Stream:          broker,
+		PaymentProvider: provider,
+	}
+	return application
+}
+
+// InitializeApplicationWithConfig builds the application from an already loaded configuration
+// and the MySQL database, used by the serve command
+func InitializeApplicationWithConfig(config Config) *Application {
+	metricsMetrics := metrics.NewMetrics()
+	db := NewInstrumentedDB(config, metricsMetrics)
+	registry := NewHealthRegistry(config, db)
+	backgroundGroup := NewBackgroundGroup()
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	validate := validator.New()
+	apiKeyService := service.NewAPIKeyService(apiKeyRepository, validate)
+	authenticator := NewAuthenticator(config, apiKeyService)
+	middlewares := NewMiddlewares(authenticator)
+	healthController := controller.NewHealthController(registry)
+	backend := NewCacheBackend(config)
+	categoryRepository := NewCategoryRepository(config, db, backend, metricsMetrics)
+	transactor := repository.NewTransactor(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository(db)
+	sender := NewWebhookSender(config, webhookRepository, webhookDeliveryRepository)
+	dispatcher := webhook.NewDispatcher(webhookRepository, webhookDeliveryRepository, sender)
+	broker := NewStreamBroker(config)
+	relay := NewRelay(config, transactor, outboxRepository, dispatcher, broker)
+	publisher := event.NewOutboxPublisher(outboxRepository, relay)
+	categoryService := service.NewCategoryService(categoryRepository, transactor, publisher, validate)
+	categoryController := controller.NewCategoryController(categoryService)
+	customerRepository := repository.NewCustomerRepository(db)
+	customerService := service.NewCustomerService(customerRepository, transactor, publisher, validate)
+	customerController := controller.NewCustomerController(customerService)
+	employeeRepository := repository.NewEmployeeRepository(db)
+	storeRepository := repository.NewStoreRepository(db)
+	employeeService := service.NewEmployeeService(employeeRepository, storeRepository, transactor, publisher, validate)
+	storeStockRepository := repository.NewStoreStockRepository(db)
+	productRepository := NewProductRepository(config, db, backend, metricsMetrics)
+	storeService := service.NewStoreService(storeRepository, storeStockRepository, productRepository, employeeRepository, transactor, publisher, validate)
+	employeeController := controller.NewEmployeeController(employeeService, storeService)
+	taxClassRepository := repository.NewTaxClassRepository(db)
+	productService := service.NewProductService(productRepository, taxClassRepository, transactor, publisher, validate)
+	productController := controller.NewProductController(productService, storeService)
+	storeController := controller.NewStoreController(storeService)
+	stockTransferRepository := repository.NewStockTransferRepository(db)
+	stockTransferService := service.NewStockTransferService(stockTransferRepository, storeRepository, storeStockRepository, productRepository, transactor, publisher, validate)
+	stockTransferController := controller.NewStockTransferController(stockTransferService)
+	promotionRepository := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepository, transactor, publisher, validate)
+	promotionController := controller.NewPromotionController(promotionService)
+	pricingService := service.NewPricingService(productRepository, customerRepository, promotionRepository, storeRepository, storeStockRepository, taxClassRepository, validate)
+	pricingController := controller.NewPricingController(pricingService)
+	orderRepository := repository.NewOrderRepository(db)
+	merchant := NewQRISMerchant(config)
+	orderService := service.NewOrderService(pricingService, orderRepository, employeeRepository, customerRepository, promotionRepository, storeRepository, storeStockRepository, merchant, transactor, publisher, validate)
+	orderController := controller.NewOrderController(orderService)
+	paymentRepository := repository.NewPaymentRepository(db)
+	provider := NewPaymentProvider(config)
+	paymentService := service.NewPaymentService(orderRepository, paymentRepository, customerRepository, provider, merchant, transactor, publisher, validate)
+	paymentController := controller.NewPaymentController(paymentService)
+	returnRepository := repository.NewReturnRepository(db)
+	returnPolicy := NewReturnPolicy(config)
+	returnService := service.NewReturnService(orderRepository, returnRepository, paymentRepository, employeeRepository, customerRepository, storeStockRepository, provider, returnPolicy, transactor, publisher, validate)
+	returnController := controller.NewReturnController(returnService)
+	cashSessionRepository := repository.NewCashSessionRepository(db)
+	cashSessionService := service.NewCashSessionService(cashSessionRepository, orderRepository, paymentRepository, returnRepository, employeeRepository, storeRepository, transactor, publisher, validate)
+	cashSessionController := controller.NewCashSessionController(cashSessionService)
+	webhookService := service.NewWebhookService(webhookRepository, webhookDeliveryRepository, sender, validate)
+	webhookController := controller.NewWebhookController(webhookService)
+	streamService := service.NewStreamService(broker, outboxRepository)
+	streamController := NewStreamController(config, streamService)
+	executor := NewGraphQLExecutor(config, categoryService, customerService, employeeService, productService, storeService)
+	graphQLController := controller.NewGraphQLController(executor)
+	controllers := Controllers{
+		Health:      healthController,
+		Category:    categoryController,
+		Customer:    customerController,
+		Employee:    employeeController,
+		Product:     productController,
+		Store:       storeController,
+		Transfer:    stockTransferController,
+		Promotion:   promotionController,
+		Pricing:     pricingController,
+		Order:       orderController,
+		Payment:     paymentController,
+		Return:      returnController,
+		CashSession: cashSessionController,
+		Webhook:     webhookController,
+		Stream:      streamController,
+		GraphQL:     graphQLController,
+	}
+	app := NewServer(config, metricsMetrics, middlewares, controllers)
+	categoryServer := rpc.NewCategoryServer(categoryService)
+	customerServer := rpc.NewCustomerServer(customerService)
+	employeeServer := rpc.NewEmployeeServer(employeeService, storeService)
+	productServer := rpc.NewProductServer(productService, storeService)
+	server := rpc.NewServer(authenticator, categoryServer, customerServer, employeeServer, productServer)
+	services := Services{
+		Category:  categoryService,
+		Customer:  customerService,
+		Employee:  employeeService,
+		Product:   productService,
+		Store:     storeService,
+		Transfer:  stockTransferService,
+		Promotion: promotionService,
+		Pricing:   pricingService,
+		APIKey:    apiKeyService,
+	}
+	application := &Application{
+		Config:          config,
+		DB:              db,
+		Metrics:         metricsMetrics,
+		Health:          registry,
+		Background:      backgroundGroup,
+		Server:          app,
+		GRPC:            server,
+		Services:        services,
+		Relay:           relay,
+		WebhookSender:   sender,
+		Stream:          broker,
+		PaymentProvider: provider,
+	}
+	return application
+}
+
+// InitializeApplicationWithDB builds the application on top of an already opened database,
+// used by the integration tests to run against SQLite
+func InitializeApplicationWithDB(config Config, db *gorm.DB) *Application {
+	metricsMetrics := metrics.NewMetrics()
+	registry := NewHealthRegistry(config, db)
+	backgroundGroup := NewBackgroundGroup()
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	validate := validator.New()
+	apiKeyService := service.NewAPIKeyService(apiKeyRepository, validate)
+	authenticator := NewAuthenticator(config, apiKeyService)
+	middlewares := NewMiddlewares(authenticator)
+	healthController := controller.NewHealthController(registry)
+	backend := NewCacheBackend(config)
+	categoryRepository := NewCategoryRepository(config, db, backend, metricsMetrics)
+	transactor := repository.NewTransactor(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository(db)
+	sender := NewWebhookSender(config, webhookRepository, webhookDeliveryRepository)
+	dispatcher := webhook.NewDispatcher(webhookRepository, webhookDeliveryRepository, sender)
+	broker := NewStreamBroker(config)
+	relay := NewRelay(config, transactor, outboxRepository, dispatcher, broker)
+	publisher := event.NewOutboxPublisher(outboxRepository, relay)
+	categoryService := service.NewCategoryService(categoryRepository, transactor, publisher, validate)
+	categoryController := controller.NewCategoryController(categoryService)
+	customerRepository := repository.NewCustomerRepository(db)
+	customerService := service.NewCustomerService(customerRepository, transactor, publisher, validate)
+	customerController := controller.NewCustomerController(customerService)
+	employeeRepository := repository.NewEmployeeRepository(db)
+	storeRepository := repository.NewStoreRepository(db)
+	employeeService := service.NewEmployeeService(employeeRepository, storeRepository, transactor, publisher, validate)
+	storeStockRepository := repository.NewStoreStockRepository(db)
+	productRepository := NewProductRepository(config, db, backend, metricsMetrics)
+	storeService := service.NewStoreService(storeRepository, storeStockRepository, productRepository, employeeRepository, transactor, publisher, validate)
+	employeeController := controller.NewEmployeeController(employeeService, storeService)
+	taxClassRepository := repository.NewTaxClassRepository(db)
+	productService := service.NewProductService(productRepository, taxClassRepository, transactor, publisher, validate)
+	productController := controller.NewProductController(productService, storeService)
+	storeController := controller.NewStoreController(storeService)
+	stockTransferRepository := repository.NewStockTransferRepository(db)
+	stockTransferService := service.NewStockTransferService(stockTransferRepository, storeRepository, storeStockRepository, productRepository, transactor, publisher, validate)
+	stockTransferController := controller.NewStockTransferController(stockTransferService)
+	promotionRepository := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepository, transactor, publisher, validate)
+	promotionController := controller.NewPromotionController(promotionService)
+	pricingService := service.NewPricingService(productRepository, customerRepository, promotionRepository, storeRepository, storeStockRepository, taxClassRepository, validate)
+	pricingController := controller.NewPricingController(pricingService)
+	orderRepository := repository.NewOrderRepository(db)
+	merchant := NewQRISMerchant(config)
+	orderService := service.NewOrderService(pricingService, orderRepository, employeeRepository, customerRepository, promotionRepository, storeRepository, storeStockRepository, merchant, transactor, publisher, validate)
+	orderController := controller.NewOrderController(orderService)
+	paymentRepository := repository.NewPaymentRepository(db)
+	provider := NewPaymentProvider(config)
+	paymentService := service.NewPaymentService(orderRepository, paymentRepository, customerRepository, provider, merchant, transactor, publisher, validate)
+	paymentController := controller.NewPaymentController(paymentService)
+	returnRepository := repository.NewReturnRepository(db)
+	returnPolicy := NewReturnPolicy(config)
+	returnService := service.NewReturnService(orderRepository, returnRepository, paymentRepository, employeeRepository, customerRepository, storeStockRepository, provider, returnPolicy, transactor, publisher, validate)
+	returnController := controller.NewReturnController(returnService)
+	cashSessionRepository := repository.NewCashSessionRepository(db)
+	cashSessionService := service.NewCashSessionService(cashSessionRepository, orderRepository, paymentRepository, returnRepository, employeeRepository, storeRepository, transactor, publisher, validate)
+	cashSessionController := controller.NewCashSessionController(cashSessionService)
+	webhookService := service.NewWebhookService(webhookRepository, webhookDeliveryRepository, sender, validate)
+	webhookController := controller.NewWebhookController(webhookService)
+	streamService := service.NewStreamService(broker, outboxRepository)
+	streamController := NewStreamController(config, streamService)
+	executor := NewGraphQLExecutor(config, categoryService, customerService, employeeService, productService, storeService)
+	graphQLController := controller.NewGraphQLController(executor)
+	controllers := Controllers{
+		Health:      healthController,
+		Category:    categoryController,
+		Customer:    customerController,
+		Employee:    employeeController,
+		Product:     productController,
+		Store:       storeController,
+		Transfer:    stockTransferController,
+		Promotion:   promotionController,
+		Pricing:     pricingController,
+		Order:       orderController,
+		Payment:     paymentController,
+		Return:      returnController,
+		CashSession: cashSessionController,
+		Webhook:     webhookController,
+		Stream:      streamController,
+		GraphQL:     graphQLController,
+	}
+	app := NewServer(config, metricsMetrics, middlewares, controllers)
+	categoryServer := rpc.NewCategoryServer(categoryService)
+	customerServer := rpc.NewCustomerServer(customerService)
+	employeeServer := rpc.NewEmployeeServer(employeeService, storeService)
+	productServer := rpc.NewProductServer(productService, storeService)
+	server := rpc.NewServer(authenticator, categoryServer, customerServer, employeeServer, productServer)
+	services := Services{
+		Category:  categoryService,
+		Customer:  customerService,
+		Employee:  employeeService,
+		Product:   productService,
+		Store:     storeService,
+		Transfer:  stockTransferService,
+		Promotion: promotionService,
+		Pricing:   pricingService,
+		APIKey:    apiKeyService,
+	}
+	application := &Application{
+		Config:          config,
+		DB:              db,
+		Metrics:         metricsMetrics,
+		Health:          registry,
+		Background:      backgroundGroup,
+		Server:          app,
+		GRPC:            server,
+		Services:        services,
+		Relay:           relay,
+		WebhookSender:   sender,
+		Stream:          broker,
+		PaymentProvider: provider,
+	}
+	return application
+}
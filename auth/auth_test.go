@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipalCan(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		permission  string
+		expected    bool
+	}{
+		{name: "wildcard", permissions: []string{"*"}, permission: "stream:products", expected: true},
+		{name: "exact", permissions: []string{"stream:products"}, permission: "stream:products", expected: true},
+		{name: "prefix wildcard", permissions: []string{"stream:*"}, permission: "stream:customers", expected: true},
+		{name: "other topic", permissions: []string{"stream:products"}, permission: "stream:customers", expected: false},
+		{name: "prefix of another scope", permissions: []string{"stream:*"}, permission: "streaming:products", expected: false},
+		{name: "no permission", permissions: nil, permission: "stream:products", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Principal{Name: "kasir", Permissions: tt.permissions}.Can(tt.permission))
+		})
+	}
+}
+
+func TestPrincipalStores(t *testing.T) {
+	cashier := Principal{Name: "kasir", Permissions: []string{"store:JKT01", "stream:products"}}
+	assert.Equal(t, []string{"JKT01"}, cashier.Stores())
+	assert.True(t, cashier.Can(StorePermission("JKT01")))
+	assert.False(t, cashier.Can(StorePermission("BDG01")))
+	assert.False(t, cashier.Can(CrossStore))
+
+	manager := Principal{Name: "manager", Permissions: []string{CrossStore}}
+	assert.Empty(t, manager.Stores())
+	assert.True(t, manager.Can(StorePermission("BDG01")))
+	assert.True(t, Principal{Name: "admin", Permissions: []string{Wildcard}}.Can(CrossStore))
+}
+
+func TestPrincipalEmployeeContext(t *testing.T) {
+	supervisor := Principal{Name: "rina", Permissions: []string{"store:JKT01", "employee:E002"}}
+	employeeId, err := supervisor.EmployeeContext("")
+	require.NoError(t, err)
+	assert.Equal(t, "E002", employeeId)
+	employeeId, err = supervisor.EmployeeContext("E002")
+	require.NoError(t, err)
+	assert.Equal(t, "E002", employeeId)
+	_, err = supervisor.EmployeeContext("E001")
+	assert.ErrorIs(t, err, ErrEmployeeForbidden)
+
+	admin := Principal{Name: "admin", Permissions: []string{Wildcard}}
+	employeeId, err = admin.EmployeeContext("E001")
+	require.NoError(t, err)
+	assert.Equal(t, "E001", employeeId)
+	_, err = admin.EmployeeContext("")
+	assert.ErrorIs(t, err, ErrEmployeeRequired)
+}
+
+func TestParseStaticKeys(t *testing.T) {
+	keys, err := ParseStaticKeys("RAHASIA=admin:*; DASHBOARD=dashboard:stream:products, stream:customers;")
+	require.NoError(t, err)
+
+	principal, ok, err := keys.Authenticate(context.Background(), "DASHBOARD")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Principal{Name: "dashboard", Permissions: []string{"stream:products", "stream:customers"}}, principal)
+
+	_, ok, _ = keys.Authenticate(context.Background(), "SALAH")
+	assert.False(t, ok)
+
+	for _, spec := range []string{"RAHASIA", "=admin:*", "RAHASIA=admin", "A=x:*;A=y:*"} {
+		_, err := ParseStaticKeys(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestChain(t *testing.T) {
+	static, err := ParseStaticKeys("RAHASIA=admin:*")
+	require.NoError(t, err)
+	stored := StaticKeys{"pos_abc": {Name: "kasir", Permissions: []string{"stream:products"}}}
+	chain := Chain{static, stored}
+
+	principal, ok, err := chain.Authenticate(context.Background(), "pos_abc")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "kasir", principal.Name)
+
+	principal, ok, _ = chain.Authenticate(context.Background(), "RAHASIA")
+	require.True(t, ok)
+	assert.Equal(t, "admin", principal.Name)
+
+	_, ok, _ = chain.Authenticate(context.Background(), "SALAH")
+	assert.False(t, ok)
+}
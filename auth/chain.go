@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+// Chain tries every authenticator in order, the first one that knows the key wins
+type Chain []Authenticator
+
+func (chain Chain) Authenticate(ctx context.Context, key string) (Principal, bool, error) {
+	for _, authenticator := range chain {
+		principal, ok, err := authenticator.Authenticate(ctx, key)
+		if err != nil || ok {
+			return principal, ok, err
+		}
+	}
+	return Principal{}, false, nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Wildcard grants every permission
+const Wildcard = "*"
+
+// Principal is the caller behind an API key
+type Principal struct {
+	Name        string
+	Permissions []string
+}
+
+// Can tells whether the principal holds permission, either exactly, through "*" or through a
+// "<prefix>:*" grant such as "stream:*"
+func (principal Principal) Can(permission string) bool {
+	for _, granted := range principal.Permissions {
+		if granted == Wildcard || granted == permission {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(granted, ":"+Wildcard); ok && strings.HasPrefix(permission, prefix+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator resolves an API key to its principal, ok is false for an unknown key
+type Authenticator interface {
+	Authenticate(ctx context.Context, key string) (principal Principal, ok bool, err error)
+}
+
+// CrossStore lets a principal work on the data of every store, see StorePermission
+const CrossStore = "store:*"
+
+// StorePermission is the permission to work on the data of one store
+func StorePermission(storeId string) string {
+	return "store:" + storeId
+}
+
+// Stores lists the stores granted one by one through "store:<id>" permissions; a CrossStore or
+// "*" grant is not expanded
+func (principal Principal) Stores() []string {
+	var stores []string
+	for _, granted := range principal.Permissions {
+		if storeId, ok := strings.CutPrefix(granted, "store:"); ok && storeId != Wildcard {
+			stores = append(stores, storeId)
+		}
+	}
+	return stores
+}
+
+// Errors of StoreContext
+var (
+	ErrStoreForbidden = errors.New("store is not granted")
+	ErrStoreRequired  = errors.New("a store context is required")
+)
+
+// StoreContext resolves the store a request works on: requested, which the principal must be
+// granted, or else the single store the principal is bound to. It is empty when the request
+// covers every store, which only a principal holding CrossStore may do.
+func (principal Principal) StoreContext(requested string) (string, error) {
+	storeId := requested
+	if storeId == "" {
+		if stores := principal.Stores(); len(stores) == 1 {
+			storeId = stores[0]
+		}
+	}
+	if storeId != "" && !principal.Can(StorePermission(storeId)) {
+		return "", ErrStoreForbidden
+	}
+	if storeId == "" && !principal.Can(CrossStore) {
+		return "", ErrStoreRequired
+	}
+	return storeId, nil
+}
+
+// EmployeePermission binds a principal to an employee: it acts as that employee where the
+// employee decides, such as the approval of a return
+func EmployeePermission(employeeId string) string {
+	return "employee:" + employeeId
+}
+
+// Errors of EmployeeContext
+var (
+	ErrEmployeeForbidden = errors.New("employee is not bound to the key")
+	ErrEmployeeRequired  = errors.New("an employee bound to the key is required")
+)
+
+// EmployeeContext resolves the employee a principal acts as: requested, which the principal must
+// be bound to, or else the single employee it is bound to
+func (principal Principal) EmployeeContext(requested string) (string, error) {
+	if requested != "" {
+		if !principal.Can(EmployeePermission(requested)) {
+			return "", ErrEmployeeForbidden
+		}
+		return requested, nil
+	}
+	var employees []string
+	for _, granted := range principal.Permissions {
+		if employeeId, ok := strings.CutPrefix(granted, "employee:"); ok && employeeId != Wildcard {
+			employees = append(employees, employeeId)
+		}
+	}
+	if len(employees) != 1 {
+		return "", ErrEmployeeRequired
+	}
+	return employees[0], nil
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StaticKeys authenticates against a fixed set of keys, see ParseStaticKeys
+type StaticKeys map[string]Principal
+
+// ParseStaticKeys reads keys written as "<key>=<name>:<permission>,<permission>;<key>=...",
+// e.g. "RAHASIA=admin:*;DASHBOARD=dashboard:stream:products"
+func ParseStaticKeys(spec string) (StaticKeys, error) {
+	keys := StaticKeys{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, rest, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("auth: invalid key entry %q, expected <key>=<name>:<permissions>", entry)
+		}
+		name, permissions, ok := strings.Cut(rest, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("auth: invalid key entry %q, expected <key>=<name>:<permissions>", entry)
+		}
+		if _, exists := keys[key]; exists {
+			return nil, fmt.Errorf("auth: key of %q is defined twice", name)
+		}
+
+		principal := Principal{Name: name}
+		for _, permission := range strings.Split(permissions, ",") {
+			if permission = strings.TrimSpace(permission); permission != "" {
+				principal.Permissions = append(principal.Permissions, permission)
+			}
+		}
+		keys[key] = principal
+	}
+	return keys, nil
+}
+
+func (keys StaticKeys) Authenticate(ctx context.Context, key string) (Principal, bool, error) {
+	principal, ok := keys[key]
+	return principal, ok, nil
+}
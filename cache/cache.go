@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Backend stores encoded values by key. The in-process LRU is the default, a shared store
+// (e.g. Redis) only needs to implement these three methods.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Recorder counts lookups per cache name, implemented by *metrics.Metrics
+type Recorder interface {
+	CacheHit(name string)
+	CacheMiss(name string)
+}
+
+// Cache is a read-through cache on top of a Backend. Values are stored as JSON so every caller
+// gets its own copy and shared backends work the same way as the in-process one.
+type Cache struct {
+	name     string
+	backend  Backend
+	ttl      time.Duration
+	recorder Recorder
+
+	group singleflight.Group
+	// generation changes on every invalidation, a load started before it must not be stored
+	generation atomic.Uint64
+}
+
+// New returns a cache whose keys are prefixed with name, recorder may be nil
+func New(name string, backend Backend, ttl time.Duration, recorder Recorder) *Cache {
+	if recorder == nil {
+		recorder = nopRecorder{}
+	}
+	return &Cache{
+		name:     name,
+		backend:  backend,
+		ttl:      ttl,
+		recorder: recorder,
+	}
+}
+
+// Load decodes the cached value of key into dest. On a miss it calls load once for all the
+// concurrent callers of the same key, stores the result and decodes it into dest.
+// Errors returned by load are never cached.
+func (c *Cache) Load(ctx context.Context, key string, dest interface{}, load func(ctx context.Context) (interface{}, error)) error {
+	key = c.name + ":" + key
+
+	// A failing backend only costs a miss
+	if data, ok, err := c.backend.Get(ctx, key); err == nil && ok {
+		if json.Unmarshal(data, dest) == nil {
+			c.recorder.CacheHit(c.name)
+			return nil
+		}
+	}
+	c.recorder.CacheMiss(c.name)
+
+	generation := c.generation.Load()
+	data, err, _ := c.group.Do(key, func() (interface{}, error) {
+		// The load is shared, it must not fail because the first caller went away
+		loadCtx := context.WithoutCancel(ctx)
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if c.generation.Load() == generation {
+			_ = c.backend.Set(loadCtx, key, data, c.ttl)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+// Invalidate removes keys, loads still in flight for any key are not stored
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	c.generation.Add(1)
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.name + ":" + key
+		c.group.Forget(prefixed[i])
+	}
+	return c.backend.Delete(ctx, prefixed...)
+}
+
+type nopRecorder struct{}
+
+func (nopRecorder) CacheHit(string)  {}
+func (nopRecorder) CacheMiss(string) {}
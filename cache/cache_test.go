@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingRecorder struct {
+	mu     sync.Mutex
+	hits   int
+	misses int
+}
+
+func (r *countingRecorder) CacheHit(string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits++
+}
+
+func (r *countingRecorder) CacheMiss(string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.misses++
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2)
+
+	require.NoError(t, lru.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, lru.Set(ctx, "b", []byte("2"), 0))
+	_, ok, _ := lru.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, lru.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ = lru.Get(ctx, "b")
+	assert.False(t, ok, "b was the least recently used entry")
+	value, ok, _ := lru.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, lru.Len())
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lru := NewLRU(10)
+	lru.now = func() time.Time { return now }
+
+	require.NoError(t, lru.Set(ctx, "short", []byte("1"), time.Minute))
+	require.NoError(t, lru.Set(ctx, "forever", []byte("2"), 0))
+
+	now = now.Add(59 * time.Second)
+	_, ok, _ := lru.Get(ctx, "short")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok, _ = lru.Get(ctx, "short")
+	assert.False(t, ok)
+	_, ok, _ = lru.Get(ctx, "forever")
+	assert.True(t, ok)
+	assert.Equal(t, 1, lru.Len())
+}
+
+func TestCacheLoad(t *testing.T) {
+	ctx := context.Background()
+	recorder := &countingRecorder{}
+	c := New("products", NewLRU(10), time.Minute, recorder)
+
+	calls := 0
+	load := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return []string{"Laptop", "Kopi"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		var names []string
+		require.NoError(t, c.Load(ctx, "all", &names, load))
+		assert.Equal(t, []string{"Laptop", "Kopi"}, names)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 2, recorder.hits)
+	assert.Equal(t, 1, recorder.misses)
+
+	require.NoError(t, c.Invalidate(ctx, "all"))
+	var names []string
+	require.NoError(t, c.Load(ctx, "all", &names, load))
+	assert.Equal(t, 2, calls)
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	c := New("products", NewLRU(10), time.Minute, nil)
+	errNotFound := errors.New("product is not found")
+
+	calls := 0
+	load := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return nil, errNotFound
+	}
+
+	var name string
+	assert.ErrorIs(t, c.Load(ctx, "P404", &name, load), errNotFound)
+	assert.ErrorIs(t, c.Load(ctx, "P404", &name, load), errNotFound)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCacheCollapsesConcurrentLoads(t *testing.T) {
+	ctx := context.Background()
+	c := New("products", NewLRU(10), time.Minute, nil)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "Laptop", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, c.Load(ctx, "P001", &results[i], load))
+		}(i)
+	}
+
+	// Give every goroutine the chance to join the flight before the load returns
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, result := range results {
+		assert.Equal(t, "Laptop", result)
+	}
+}
+
+func TestCacheSkipsLoadInvalidatedInFlight(t *testing.T) {
+	ctx := context.Background()
+	backend := NewLRU(10)
+	c := New("products", backend, time.Minute, nil)
+
+	load := func(ctx context.Context) (interface{}, error) {
+		// An update commits and invalidates while the stale row is being read
+		require.NoError(t, c.Invalidate(ctx, "P001"))
+		return "stale", nil
+	}
+
+	var name string
+	require.NoError(t, c.Load(ctx, "P001", &name, load))
+	assert.Equal(t, "stale", name)
+	assert.Equal(t, 0, backend.Len())
+}
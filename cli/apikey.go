@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+func createAdminCommand() *Command {
+	return &Command{
+		Name:    "create-admin",
+		Summary: "Issue an API key holding every permission",
+		Flags: func(flags *flag.FlagSet) Action {
+			name := flags.String("name", "admin", "name of the key owner")
+
+			return func(ctx context.Context, env *Env, args []string) error {
+				return createAPIKey(ctx, env, web.APIKeyCreateRequest{Name: *name, Permissions: []string{auth.Wildcard}})
+			}
+		},
+	}
+}
+
+func apiKeyCommand() *Command {
+	return &Command{
+		Name:    "apikey",
+		Summary: "Manage the API keys stored in the database",
+		Commands: []*Command{
+			{
+				Name:    "create",
+				Summary: "Issue an API key, it is printed once and cannot be shown again",
+				Flags: func(flags *flag.FlagSet) Action {
+					name := flags.String("name", "", "name of the key owner (required)")
+					permissions := flags.String("permissions", "", `comma separated permissions, e.g. "stream:products,stream:customers" or "*" (required)`)
+
+					return func(ctx context.Context, env *Env, args []string) error {
+						var request web.APIKeyCreateRequest
+						request.Name = *name
+						for _, permission := range strings.Split(*permissions, ",") {
+							if permission = strings.TrimSpace(permission); permission != "" {
+								request.Permissions = append(request.Permissions, permission)
+							}
+						}
+						return createAPIKey(ctx, env, request)
+					}
+				},
+			},
+			{
+				Name:    "revoke",
+				Summary: "Revoke an API key, requests using it are rejected immediately",
+				Args:    []string{"key-id"},
+				Flags: noFlags(func(ctx context.Context, env *Env, args []string) error {
+					application, err := env.Application()
+					if err != nil {
+						return err
+					}
+					apiKey, err := application.Services.APIKey.Revoke(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(env.Stdout, "API key %s (%s) revoked at %s\n", apiKey.KeyID, apiKey.Name, apiKey.RevokedAt.Format(time.RFC3339))
+					return nil
+				}),
+			},
+			{
+				Name:    "list",
+				Summary: "List the API keys, revoked ones included",
+				Flags: func(flags *flag.FlagSet) Action {
+					format := flags.String("format", "table", "output format: table or json")
+
+					return func(ctx context.Context, env *Env, args []string) error {
+						if *format != "table" && *format != "json" {
+							return usageError{path: Program + " apikey list", message: fmt.Sprintf("unknown format %q", *format)}
+						}
+						application, err := env.Application()
+						if err != nil {
+							return err
+						}
+						apiKeys, err := application.Services.APIKey.FindAll(ctx)
+						if err != nil {
+							return err
+						}
+						if *format == "json" {
+							return writeJSON(env.Stdout, apiKeys)
+						}
+						return writeAPIKeys(env.Stdout, apiKeys)
+					}
+				},
+			},
+		},
+	}
+}
+
+func createAPIKey(ctx context.Context, env *Env, request web.APIKeyCreateRequest) error {
+	application, err := env.Application()
+	if err != nil {
+		return err
+	}
+	apiKey, err := application.Services.APIKey.Create(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stdout, "ID:          %s\nName:        %s\nPermissions: %s\nKey:         %s\n\n",
+		apiKey.KeyID, apiKey.Name, strings.Join(apiKey.Permissions, ","), apiKey.Key)
+	fmt.Fprintln(env.Stdout, "Store the key now, it cannot be shown again. Send it in the X-API-Key header.")
+	return nil
+}
+
+func writeAPIKeys(w io.Writer, apiKeys []web.APIKeyResponse) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tPREFIX\tPERMISSIONS\tCREATED\tREVOKED")
+	for _, apiKey := range apiKeys {
+		revoked := "-"
+		if apiKey.RevokedAt != nil {
+			revoked = apiKey.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", apiKey.KeyID, apiKey.Name, apiKey.Prefix,
+			strings.Join(apiKey.Permissions, ","), apiKey.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	return table.Flush()
+}
+
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
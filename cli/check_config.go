@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+func checkConfigCommand() *Command {
+	return &Command{
+		Name:    "check-config",
+		Summary: "Validate the configuration read from the environment and the database connection",
+		Flags: func(flags *flag.FlagSet) Action {
+			skipDB := flags.Bool("skip-db", false, "do not connect to the database")
+
+			return func(ctx context.Context, env *Env, args []string) error {
+				config, err := env.Config()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(env.Stdout, "Configuration is valid")
+				if *skipDB {
+					return nil
+				}
+
+				application, err := env.Application()
+				if err != nil {
+					return err
+				}
+				sqlDB, err := application.DB.DB()
+				if err != nil {
+					return err
+				}
+				if err := sqlDB.PingContext(ctx); err != nil {
+					return fmt.Errorf("database is not reachable: %w", err)
+				}
+				fmt.Fprintf(env.Stdout, "Database is reachable (%s)\n", redactDSN(config.DatabaseDSN))
+				return nil
+			}
+		},
+	}
+}
+
+// redactDSN hides the password of a MySQL DSN such as "user:password@tcp(host)/db"
+func redactDSN(dsn string) string {
+	at := strings.LastIndex(dsn, "@")
+	if at < 0 {
+		return dsn
+	}
+	user, _, hasPassword := strings.Cut(dsn[:at], ":")
+	if !hasPassword {
+		return dsn
+	}
+	return user + ":***" + dsn[at:]
+}
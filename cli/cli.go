@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/app"
+	"gorm.io/gorm/logger"
+)
+
+// Program is the name of the binary shown in the usage
+const Program = "go-restful-api"
+
+// Exit codes returned by Run, serve returns the exit codes of app.Run
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 64
+	ExitConfig  = 78
+)
+
+// Action runs a command with the arguments left after its flags
+type Action func(ctx context.Context, env *Env, args []string) error
+
+// Command is a node of the command tree, either a group of Commands or a leaf with Flags
+type Command struct {
+	Name    string
+	Summary string
+	// Args names the positional arguments of a leaf command, each of them is required
+	Args     []string
+	Commands []*Command
+	// Flags registers the flags of a leaf command and returns its action
+	Flags func(flags *flag.FlagSet) Action
+}
+
+// Env is what the commands share: the output streams, the configuration and the application
+// wired exactly like the server
+type Env struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// LoadConfig and OpenApplication are replaced by the tests
+	LoadConfig      func() (app.Config, error)
+	OpenApplication func(config app.Config) (*app.Application, error)
+
+	application *app.Application
+}
+
+// NewEnv reads the configuration from the environment and opens the MySQL database
+func NewEnv(stdout io.Writer, stderr io.Writer) *Env {
+	return &Env{
+		Stdout:     stdout,
+		Stderr:     stderr,
+		LoadConfig: app.LoadConfig,
+		OpenApplication: func(config app.Config) (*app.Application, error) {
+			// Only warnings and on stderr, stdout may carry an export
+			db, err := app.OpenDB(config, logger.New(log.New(stderr, "", log.LstdFlags), logger.Config{
+				SlowThreshold:             200 * time.Millisecond,
+				LogLevel:                  logger.Warn,
+				IgnoreRecordNotFoundError: true,
+			}))
+			if err != nil {
+				return nil, err
+			}
+			return app.InitializeApplicationWithDB(config, db), nil
+		},
+	}
+}
+
+// Config loads the configuration, an invalid configuration ends the command with ExitConfig
+func (env *Env) Config() (app.Config, error) {
+	config, err := env.LoadConfig()
+	if err != nil {
+		return config, configError{err: err}
+	}
+	return config, nil
+}
+
+// Application opens the application once per command, it is closed when the command returns
+func (env *Env) Application() (*app.Application, error) {
+	if env.application != nil {
+		return env.application, nil
+	}
+	config, err := env.Config()
+	if err != nil {
+		return nil, err
+	}
+	application, err := env.OpenApplication(config)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	env.application = application
+	return application, nil
+}
+
+func (env *Env) close() {
+	if env.application == nil {
+		return
+	}
+	if sqlDB, err := env.application.DB.DB(); err == nil {
+		sqlDB.Close()
+	}
+	env.application = nil
+}
+
+// usageError is reported with a pointer to --help and ExitUsage
+type usageError struct {
+	path    string
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+// configError lists the invalid settings and ends with ExitConfig
+type configError struct {
+	err error
+}
+
+func (e configError) Error() string {
+	return "invalid configuration:\n  " + strings.ReplaceAll(e.err.Error(), "\n", "\n  ")
+}
+
+// exitCode ends a command with a specific exit code, its message has already been printed
+type exitCode int
+
+func (e exitCode) Error() string {
+	return fmt.Sprintf("exit code %d", int(e))
+}
+
+// Run executes the command named by args, without arguments the server is started
+func Run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	return RunEnv(ctx, NewEnv(stdout, stderr), args)
+}
+
+// RunEnv is Run with a custom environment
+func RunEnv(ctx context.Context, env *Env, args []string) int {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	defer env.close()
+	err := execute(ctx, env, Root(), []string{Program}, args)
+
+	var usage usageError
+	var config configError
+	var code exitCode
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &code):
+		return int(code)
+	case errors.As(err, &usage):
+		fmt.Fprintf(env.Stderr, "error: %s\nRun '%s --help' for usage.\n", usage.message, usage.path)
+		return ExitUsage
+	case errors.As(err, &config):
+		fmt.Fprintf(env.Stderr, "error: %s\n", config.Error())
+		return ExitConfig
+	default:
+		fmt.Fprintf(env.Stderr, "error: %s\n", err)
+		return ExitFailure
+	}
+}
+
+func execute(ctx context.Context, env *Env, command *Command, path []string, args []string) error {
+	if len(command.Commands) > 0 {
+		if len(args) == 0 {
+			return usageError{path: strings.Join(path, " "), message: "missing command"}
+		}
+		if isHelp(args[0]) {
+			printUsage(env.Stdout, command, path, nil)
+			return nil
+		}
+		for _, subcommand := range command.Commands {
+			if subcommand.Name == args[0] {
+				return execute(ctx, env, subcommand, append(path, subcommand.Name), args[1:])
+			}
+		}
+		return usageError{path: strings.Join(path, " "), message: fmt.Sprintf("unknown command %q", args[0])}
+	}
+
+	flags := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	action := command.Flags(flags)
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		printUsage(env.Stdout, command, path, flags)
+		return nil
+	} else if err != nil {
+		return usageError{path: strings.Join(path, " "), message: err.Error()}
+	}
+	if flags.NArg() != len(command.Args) {
+		return usageError{path: strings.Join(path, " "), message: fmt.Sprintf("expected %d argument(s), got %d", len(command.Args), flags.NArg())}
+	}
+	return action(ctx, env, flags.Args())
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help" || arg == "help"
+}
+
+func printUsage(w io.Writer, command *Command, path []string, flags *flag.FlagSet) {
+	hasFlags := false
+	if flags != nil {
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+	}
+
+	usage := strings.Join(path, " ")
+	if len(command.Commands) > 0 {
+		usage += " <command>"
+	}
+	if hasFlags {
+		usage += " [flags]"
+	}
+	for _, arg := range command.Args {
+		usage += " <" + arg + ">"
+	}
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", usage, command.Summary)
+
+	if len(command.Commands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		for _, subcommand := range command.Commands {
+			fmt.Fprintf(table, "  %s\t%s\n", subcommand.Name, subcommand.Summary)
+		}
+		table.Flush()
+		fmt.Fprintf(w, "\nRun '%s <command> --help' for more information on a command.\n", strings.Join(path, " "))
+	}
+	if hasFlags {
+		fmt.Fprintln(w, "\nFlags:")
+		flags.SetOutput(w)
+		flags.PrintDefaults()
+	}
+}
+
+// noFlags is the Flags of a command without flags
+func noFlags(action Action) func(flags *flag.FlagSet) Action {
+	return func(flags *flag.FlagSet) Action {
+		return action
+	}
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/app"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testCLI struct {
+	t      *testing.T
+	dsn    string
+	db     *gorm.DB
+	config func() (app.Config, error)
+}
+
+// setupCLI runs the commands against a private in-memory SQLite database
+func setupCLI(t *testing.T) *testCLI {
+	dsn := fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"), time.Now().UnixNano())
+	// Keeps the in-memory database alive between the commands
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	return &testCLI{t: t, dsn: dsn, db: db, config: func() (app.Config, error) {
+		config := app.NewConfig()
+		config.MetricsAddr = ""
+		return config, nil
+	}}
+}
+
+func (c *testCLI) run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	env := &Env{
+		Stdout:     &stdout,
+		Stderr:     &stderr,
+		LoadConfig: c.config,
+		OpenApplication: func(config app.Config) (*app.Application, error) {
+			db, err := gorm.Open(sqlite.Open(c.dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+			if err != nil {
+				return nil, err
+			}
+			return app.InitializeApplicationWithDB(config, db), nil
+		},
+	}
+	code := RunEnv(context.Background(), env, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestHelpAndUsageErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectCode   int
+		expectStdout string
+		expectStderr string
+	}{
+		{name: "root help", args: []string{"--help"}, expectCode: ExitOK, expectStdout: "check-config"},
+		{name: "group help", args: []string{"apikey", "help"}, expectCode: ExitOK, expectStdout: "revoke"},
+		{name: "leaf help", args: []string{"export", "products", "-h"}, expectCode: ExitOK, expectStdout: "-format string"},
+		{name: "unknown command", args: []string{"deploy"}, expectCode: ExitUsage, expectStderr: `unknown command "deploy"`},
+		{name: "missing subcommand", args: []string{"apikey"}, expectCode: ExitUsage, expectStderr: "missing command"},
+		{name: "unknown flag", args: []string{"migrate", "--force"}, expectCode: ExitUsage, expectStderr: "flag provided but not defined"},
+		{name: "missing argument", args: []string{"apikey", "revoke"}, expectCode: ExitUsage, expectStderr: "go-restful-api apikey revoke --help"},
+		{name: "unknown format", args: []string{"export", "products", "--format", "xml"}, expectCode: ExitUsage, expectStderr: `unknown format "xml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := setupCLI(t).run(tt.args...)
+			assert.Equal(t, tt.expectCode, code)
+			assert.Contains(t, stdout, tt.expectStdout)
+			assert.Contains(t, stderr, tt.expectStderr)
+		})
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	cli := setupCLI(t)
+	code, stdout, _ := cli.run("check-config")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "Database is reachable (root:***@tcp(localhost:3306)")
+
+	cli.config = func() (app.Config, error) {
+		return app.Config{}, errors.New("SHUTDOWN_TIMEOUT: \"abc\" is not a duration")
+	}
+	code, _, stderr := cli.run("check-config", "--skip-db")
+	assert.Equal(t, ExitConfig, code)
+	assert.Contains(t, stderr, "SHUTDOWN_TIMEOUT")
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	cli := setupCLI(t)
+	code, _, _ := cli.run("migrate")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ := cli.run("create-admin", "--name", "ops")
+	require.Equal(t, ExitOK, code)
+	key := regexp.MustCompile(`Key:\s+(pos_\w+)`).FindStringSubmatch(stdout)[1]
+	keyId := regexp.MustCompile(`ID:\s+(\S+)`).FindStringSubmatch(stdout)[1]
+
+	code, stdout, _ = cli.run("apikey", "create", "--name", "dashboard", "--permissions", "stream:products, stream:customers")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "Permissions: stream:products,stream:customers")
+
+	code, _, stderr := cli.run("apikey", "create", "--name", "kosong")
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, stderr, "Permissions")
+
+	var stored domain.APIKey
+	require.NoError(t, cli.db.First(&stored, "key_id = ?", keyId).Error)
+	assert.NotEqual(t, key, stored.Hash, "only the hash of the key is stored")
+	assert.Equal(t, key[:12], stored.Prefix)
+
+	code, stdout, _ = cli.run("apikey", "list")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "ops")
+	assert.Contains(t, stdout, "dashboard")
+	assert.NotContains(t, stdout, key)
+
+	code, stdout, _ = cli.run("apikey", "revoke", keyId)
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "revoked")
+	require.NoError(t, cli.db.First(&stored, "key_id = ?", keyId).Error)
+	assert.NotNil(t, stored.RevokedAt)
+
+	code, _, stderr = cli.run("apikey", "revoke", "tidak-ada")
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, stderr, "API key not found")
+}
+
+func TestSeedAndExport(t *testing.T) {
+	cli := setupCLI(t)
+	code, _, _ := cli.run("migrate")
+	require.Equal(t, ExitOK, code)
+
+	code, stdout, _ := cli.run("seed")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "categories 10 created, 0 updated, 0 unchanged")
+	code, stdout, _ = cli.run("seed")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "products   0 created, 0 updated, 42 unchanged")
+
+	code, _, stderr := cli.run("seed", "--file", "fixtures.yaml", "--fake", "10")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "cannot be combined")
+	require.NoError(t, cli.db.Exec("DELETE FROM products").Error)
+
+	require.NoError(t, cli.db.Create(&[]domain.Product{
+		{ProductID: "P001", Name: "Kopi Bubuk, 250g", Price: money.IDR(35000), StockQty: 200, Category: "Minuman", SKU: "KOP250", TaxRate: 11},
+		{ProductID: "P002", Name: "Sabun Mandi", Price: money.IDR(4500), StockQty: 0, Category: "Perawatan Diri", SKU: "SBN01"},
+	}).Error)
+
+	code, stdout, _ = cli.run("export", "products", "--format", "csv")
+	require.Equal(t, ExitOK, code)
+	rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"product_id", "name", "description", "price", "stock_qty", "category", "sku", "tax_rate"},
+		{"P001", "Kopi Bubuk, 250g", "", "35000", "200", "Minuman", "KOP250", "11"},
+		{"P002", "Sabun Mandi", "", "4500", "0", "Perawatan Diri", "SBN01", "0"},
+	}, rows)
+
+	code, stdout, _ = cli.run("export", "products", "--format", "json")
+	require.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, `"sku": "SBN01"`)
+}
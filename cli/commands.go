@@ -0,0 +1,18 @@
+package cli
+
+// Root is the command tree of the binary
+func Root() *Command {
+	return &Command{
+		Name:    Program,
+		Summary: "Product management API and its administrative commands. Without a command the server is started.",
+		Commands: []*Command{
+			serveCommand(),
+			migrateCommand(),
+			seedCommand(),
+			createAdminCommand(),
+			apiKeyCommand(),
+			exportCommand(),
+			checkConfigCommand(),
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+func exportCommand() *Command {
+	return &Command{
+		Name:    "export",
+		Summary: "Export data from the database",
+		Commands: []*Command{
+			{
+				Name:    "products",
+				Summary: "Export every product",
+				Flags: func(flags *flag.FlagSet) Action {
+					format := flags.String("format", "csv", "output format: csv or json")
+					output := flags.String("output", "", "write to this file instead of stdout")
+
+					return func(ctx context.Context, env *Env, args []string) error {
+						if *format != "csv" && *format != "json" {
+							return usageError{path: Program + " export products", message: fmt.Sprintf("unknown format %q", *format)}
+						}
+						application, err := env.Application()
+						if err != nil {
+							return err
+						}
+						products, err := application.Services.Product.FindAll(ctx)
+						if err != nil {
+							return err
+						}
+
+						w := env.Stdout
+						if *output != "" {
+							file, err := os.Create(*output)
+							if err != nil {
+								return err
+							}
+							defer file.Close()
+							w = file
+						}
+
+						if *format == "json" {
+							err = writeJSON(w, products)
+						} else {
+							err = writeProductsCSV(w, products)
+						}
+						if err != nil {
+							return err
+						}
+						if *output != "" {
+							fmt.Fprintf(env.Stderr, "Exported %d products to %s\n", len(products), *output)
+						}
+						return nil
+					}
+				},
+			},
+		},
+	}
+}
+
+// writeProductsCSV writes the products with a header row named after the JSON fields
+func writeProductsCSV(w io.Writer, products []web.ProductResponse) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"product_id", "name", "description", "price", "stock_qty", "category", "sku", "tax_rate"})
+	for _, product := range products {
+		writer.Write([]string{
+			product.ProductID,
+			product.Name,
+			product.Description,
+			product.PriceAmount.String(),
+			strconv.Itoa(product.StockQty),
+			product.Category,
+			product.SKU,
+			strconv.FormatFloat(product.TaxRate, 'f', -1, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
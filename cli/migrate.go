@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/app"
+)
+
+func migrateCommand() *Command {
+	return &Command{
+		Name:    "migrate",
+		Summary: "Create or update the tables of every model",
+		Flags: noFlags(func(ctx context.Context, env *Env, args []string) error {
+			application, err := env.Application()
+			if err != nil {
+				return err
+			}
+			if err := application.Migrate(); err != nil {
+				return err
+			}
+			fmt.Fprintf(env.Stdout, "Migrated %d tables\n", len(app.Models()))
+			return nil
+		}),
+	}
+}
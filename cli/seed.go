@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/fixture"
+	"github.com/go-playground/validator/v10"
+)
+
+func seedCommand() *Command {
+	return &Command{
+		Name:    "seed",
+		Summary: "Load fixtures, upserted by natural key (category name, SKU, email): the built-in demo dataset unless --file or --fake is given",
+		Flags: func(flags *flag.FlagSet) Action {
+			file := flags.String("file", "", "YAML or JSON fixtures file to load")
+			fake := flags.Int("fake", 0, "generate this many products and customers (and 1 employee per 50) for performance tests")
+			seed := flags.Int64("seed", 1, "random seed of --fake, the same seed gives the same rows")
+
+			return func(ctx context.Context, env *Env, args []string) error {
+				if *file != "" && *fake > 0 {
+					return usageError{path: Program + " seed", message: "--file and --fake cannot be combined"}
+				}
+
+				set := fixture.Demo()
+				switch {
+				case *file != "":
+					var err error
+					if set, err = fixture.ReadFile(*file); err != nil {
+						return err
+					}
+				case *fake > 0:
+					set = fixture.Generate(fixture.Sizes{Products: *fake, Customers: *fake, Employees: max(1, *fake/50)}, *seed)
+				}
+
+				application, err := env.Application()
+				if err != nil {
+					return err
+				}
+				report, err := fixture.NewLoader(application.DB, validator.New()).Load(ctx, set)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(env.Stdout, report)
+				return nil
+			}
+		},
+	}
+}
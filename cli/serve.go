@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/aronipurwanto/go-restful-api/app"
+)
+
+func serveCommand() *Command {
+	return &Command{
+		Name:    "serve",
+		Summary: "Start the REST, GraphQL and gRPC servers until SIGINT/SIGTERM",
+		Flags: func(flags *flag.FlagSet) Action {
+			migrate := flags.Bool("migrate", true, "run the auto migration before serving")
+
+			return func(ctx context.Context, env *Env, args []string) error {
+				config, err := env.Config()
+				if err != nil {
+					return err
+				}
+
+				// Semua dependency dirangkai oleh Wire, lihat app/injector.go
+				application := app.InitializeApplicationWithConfig(config)
+				if *migrate {
+					if err := application.Migrate(); err != nil {
+						return err
+					}
+				}
+
+				// Run closes the database itself
+				if code := application.Run(ctx); code != app.ExitOK {
+					return exitCode(code)
+				}
+				return nil
+			}
+		},
+	}
+}
@@ -0,0 +1,15 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type CashSessionController interface {
+	Open(c *fiber.Ctx) error
+	FindById(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	RecordMovement(c *fiber.Ctx) error
+	Close(c *fiber.Ctx) error
+	Report(c *fiber.Ctx) error
+	PrintReport(c *fiber.Ctx) error
+	StoreReport(c *fiber.Ctx) error
+	PrintStoreReport(c *fiber.Ctx) error
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CashSessionControllerImpl struct {
+	CashSessionService service.CashSessionService
+}
+
+func NewCashSessionController(cashSessionService service.CashSessionService) CashSessionController {
+	return &CashSessionControllerImpl{
+		CashSessionService: cashSessionService,
+	}
+}
+
+// Open a Cash Session in the store context
+func (controller *CashSessionControllerImpl) Open(c *fiber.Ctx) error {
+	openRequest := new(web.CashSessionOpenRequest)
+	if err := c.BodyParser(openRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	openRequest.StoreID = middleware.Store(c)
+
+	sessionResponse, err := controller.CashSessionService.Open(c.Context(), middleware.Principal(c), *openRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   sessionResponse,
+	})
+}
+
+// Find Cash Session By ID
+func (controller *CashSessionControllerImpl) FindById(c *fiber.Ctx) error {
+	sessionResponse, err := controller.CashSessionService.FindById(c.Context(), middleware.Principal(c), c.Params("sessionId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   sessionResponse,
+	})
+}
+
+// Find All Cash Sessions of the store context, of one status with the status query
+func (controller *CashSessionControllerImpl) FindAll(c *fiber.Ctx) error {
+	sessionResponses, err := controller.CashSessionService.FindAll(c.Context(), middleware.Store(c), c.Query("status"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   sessionResponses,
+	})
+}
+
+// Record Movement of cash in or out of the drawer of a Cash Session
+func (controller *CashSessionControllerImpl) RecordMovement(c *fiber.Ctx) error {
+	movementRequest := new(web.CashMovementRequest)
+	if err := c.BodyParser(movementRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	movementRequest.SessionID = c.Params("sessionId")
+
+	sessionResponse, err := controller.CashSessionService.RecordMovement(c.Context(), middleware.Principal(c), *movementRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   sessionResponse,
+	})
+}
+
+// Close a Cash Session with the cash counted in the drawer
+func (controller *CashSessionControllerImpl) Close(c *fiber.Ctx) error {
+	closeRequest := new(web.CashSessionCloseRequest)
+	if err := c.BodyParser(closeRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	closeRequest.SessionID = c.Params("sessionId")
+
+	sessionResponse, err := controller.CashSessionService.Close(c.Context(), middleware.Principal(c), *closeRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   sessionResponse,
+	})
+}
+
+// Report of a Cash Session, X while it is open and Z once it is closed
+func (controller *CashSessionControllerImpl) Report(c *fiber.Ctx) error {
+	reportResponse, err := controller.CashSessionService.Report(c.Context(), middleware.Principal(c), c.Params("sessionId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   reportResponse,
+	})
+}
+
+// Print Report of a Cash Session as plain text, the paper query is the width of the paper in
+// millimetres, 58 or 80, the default
+func (controller *CashSessionControllerImpl) PrintReport(c *fiber.Ctx) error {
+	paper, err := receipt.ParsePaper(c.Query("paper", "80"))
+	if err != nil {
+		return errorResponse(c, exception.NewBadRequestError(err.Error()))
+	}
+
+	report, err := controller.CashSessionService.PrintReport(c.Context(), middleware.Principal(c), c.Params("sessionId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, receipt.ContentType(receipt.FormatText))
+	return c.Status(fiber.StatusOK).Send(receipt.ReportText(report, paper))
+}
+
+// Store Report of the cash of the store context on the day of the date query, today by default
+func (controller *CashSessionControllerImpl) StoreReport(c *fiber.Ctx) error {
+	reportResponse, err := controller.CashSessionService.StoreReport(c.Context(), middleware.Principal(c), middleware.Store(c), c.Query("date"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   reportResponse,
+	})
+}
+
+// Print Store Report of the cash of the store context as plain text
+func (controller *CashSessionControllerImpl) PrintStoreReport(c *fiber.Ctx) error {
+	paper, err := receipt.ParsePaper(c.Query("paper", "80"))
+	if err != nil {
+		return errorResponse(c, exception.NewBadRequestError(err.Error()))
+	}
+
+	report, err := controller.CashSessionService.PrintStoreReport(c.Context(), middleware.Principal(c), middleware.Store(c), c.Query("date"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, receipt.ContentType(receipt.FormatText))
+	return c.Status(fiber.StatusOK).Send(receipt.ReportText(report, paper))
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
-	"strconv"
 )
 
 type CustomerControllerImpl struct {
@@ -56,15 +55,15 @@ func (controller *CustomerControllerImpl) Update(c *fiber.Ctx) error {
 		})
 	}
 
-	id, err := strconv.Atoi(c.Params("customerId"))
-	if err != nil {
+	customerID := c.Params("customerId")
+	if customerID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
 			Code:   fiber.StatusBadRequest,
 			Status: "Invalid Customer ID",
-			Data:   err.Error(),
+			Data:   "Customer ID tidak boleh kosong",
 		})
 	}
-	customerUpdateRequest.CustomerID = strconv.Itoa(id)
+	customerUpdateRequest.CustomerID = customerID
 
 	customerResponse, err := controller.CustomerService.Update(c.Context(), *customerUpdateRequest)
 	if err != nil {
@@ -91,16 +90,16 @@ func (controller *CustomerControllerImpl) Update(c *fiber.Ctx) error {
 
 // Delete Customer
 func (controller *CustomerControllerImpl) Delete(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("customerId"))
-	if err != nil {
+	customerID := c.Params("customerId")
+	if customerID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
 			Code:   fiber.StatusBadRequest,
 			Status: "Invalid Customer ID",
-			Data:   err.Error(),
+			Data:   "Customer ID tidak boleh kosong",
 		})
 	}
 
-	err = controller.CustomerService.Delete(c.Context(), strconv.Itoa(id))
+	err := controller.CustomerService.Delete(c.Context(), customerID)
 	if err != nil {
 		if _, ok := err.(exception.NotFoundError); ok {
 			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
@@ -124,16 +123,16 @@ func (controller *CustomerControllerImpl) Delete(c *fiber.Ctx) error {
 
 // Find Customer By ID
 func (controller *CustomerControllerImpl) FindById(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("customerId"))
-	if err != nil {
+	customerID := c.Params("customerId")
+	if customerID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
 			Code:   fiber.StatusBadRequest,
 			Status: "Invalid Customer ID",
-			Data:   err.Error(),
+			Data:   "Customer ID tidak boleh kosong",
 		})
 	}
 
-	customerResponse, err := controller.CustomerService.FindById(c.Context(), strconv.Itoa(id))
+	customerResponse, err := controller.CustomerService.FindById(c.Context(), customerID)
 	if err != nil {
 		if _, ok := err.(exception.NotFoundError); ok {
 			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
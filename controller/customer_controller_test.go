@@ -80,6 +80,56 @@ func TestCustomerController(t *testing.T) {
 				Data:   web.CustomerResponse{CustomerID: "1", Name: "John Doe", Email: "john@example.com"},
 			},
 		},
+		{
+			name:   "Find customer by non-numeric ID - success",
+			method: "GET",
+			url:    "/api/customers/C001",
+			body:   nil,
+			setupMock: func() {
+				mockService.EXPECT().
+					FindById(gomock.Any(), "C001").
+					Return(web.CustomerResponse{CustomerID: "C001", Name: "John Doe", Email: "john@example.com"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: web.WebResponse{
+				Code:   http.StatusOK,
+				Status: "OK",
+				Data:   web.CustomerResponse{CustomerID: "C001", Name: "John Doe", Email: "john@example.com"},
+			},
+		},
+		{
+			name:   "Update customer by non-numeric ID - success",
+			method: "PUT",
+			url:    "/api/customers/C001",
+			body:   web.CustomerUpdateRequest{Name: "John Doe", Email: "john@example.com"},
+			setupMock: func() {
+				mockService.EXPECT().
+					Update(gomock.Any(), web.CustomerUpdateRequest{CustomerID: "C001", Name: "John Doe", Email: "john@example.com"}).
+					Return(web.CustomerResponse{CustomerID: "C001", Name: "John Doe", Email: "john@example.com"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: web.WebResponse{
+				Code:   http.StatusOK,
+				Status: "OK",
+				Data:   web.CustomerResponse{CustomerID: "C001", Name: "John Doe", Email: "john@example.com"},
+			},
+		},
+		{
+			name:   "Delete customer by non-numeric ID - success",
+			method: "DELETE",
+			url:    "/api/customers/C001",
+			body:   nil,
+			setupMock: func() {
+				mockService.EXPECT().
+					Delete(gomock.Any(), "C001").
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: web.WebResponse{
+				Code:   http.StatusOK,
+				Status: "Deleted Successfully",
+			},
+		},
 	}
 
 	for _, tt := range tests {
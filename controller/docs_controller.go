@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type DocsController interface {
+	Spec(c *fiber.Ctx) error
+	UI(c *fiber.Ctx) error
+}
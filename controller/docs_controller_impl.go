@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DocsControllerImpl struct {
+	Build   func() *openapi.Document
+	SpecURL string
+
+	once     sync.Once
+	document *openapi.Document
+}
+
+// NewDocsController serves the document returned by build. The document is built on the
+// first request, once every route has been registered on the application.
+func NewDocsController(build func() *openapi.Document, specURL string) DocsController {
+	return &DocsControllerImpl{
+		Build:   build,
+		SpecURL: specURL,
+	}
+}
+
+// Spec returns the OpenAPI document
+func (controller *DocsControllerImpl) Spec(c *fiber.Ctx) error {
+	controller.once.Do(func() {
+		controller.document = controller.Build()
+	})
+	return c.Status(fiber.StatusOK).JSON(controller.document)
+}
+
+// UI returns the interactive documentation page
+func (controller *DocsControllerImpl) UI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).SendString(openapi.UIPage(controller.SpecURL))
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/middleware"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
@@ -9,11 +10,13 @@ import (
 
 type EmployeeControllerImpl struct {
 	EmployeeService service.EmployeeService
+	StoreService    service.StoreService
 }
 
-func NewEmployeeController(employeeService service.EmployeeService) EmployeeController {
+func NewEmployeeController(employeeService service.EmployeeService, storeService service.StoreService) EmployeeController {
 	return &EmployeeControllerImpl{
 		EmployeeService: employeeService,
+		StoreService:    storeService,
 	}
 }
 
@@ -30,11 +33,7 @@ func (controller *EmployeeControllerImpl) Create(c *fiber.Ctx) error {
 
 	employeeResponse, err := controller.EmployeeService.Create(c.Context(), *employeeCreateRequest)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   err.Error(),
-		})
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
@@ -67,18 +66,7 @@ func (controller *EmployeeControllerImpl) Update(c *fiber.Ctx) error {
 
 	employeeResponse, err := controller.EmployeeService.Update(c.Context(), *employeeUpdateRequest)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   err.Error(),
-		})
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
@@ -121,7 +109,7 @@ func (controller *EmployeeControllerImpl) Delete(c *fiber.Ctx) error {
 	})
 }
 
-// Find Employee By ID
+// Find Employee By ID, only an employee of the store in a store context
 func (controller *EmployeeControllerImpl) FindById(c *fiber.Ctx) error {
 	employeeID := c.Params("employeeId")
 	if employeeID == "" {
@@ -132,7 +120,13 @@ func (controller *EmployeeControllerImpl) FindById(c *fiber.Ctx) error {
 		})
 	}
 
-	employeeResponse, err := controller.EmployeeService.FindById(c.Context(), employeeID)
+	var employeeResponse web.EmployeeResponse
+	var err error
+	if storeId := middleware.Store(c); storeId != "" {
+		employeeResponse, err = controller.StoreService.FindEmployee(c.Context(), storeId, employeeID)
+	} else {
+		employeeResponse, err = controller.EmployeeService.FindById(c.Context(), employeeID)
+	}
 	if err != nil {
 		if _, ok := err.(exception.NotFoundError); ok {
 			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
@@ -155,15 +149,17 @@ func (controller *EmployeeControllerImpl) FindById(c *fiber.Ctx) error {
 	})
 }
 
-// Find All Employees
+// Find All Employees, only those of the store in a store context
 func (controller *EmployeeControllerImpl) FindAll(c *fiber.Ctx) error {
-	employeeResponses, err := controller.EmployeeService.FindAll(c.Context())
+	var employeeResponses []web.EmployeeResponse
+	var err error
+	if storeId := middleware.Store(c); storeId != "" {
+		employeeResponses, err = controller.StoreService.FindEmployees(c.Context(), storeId)
+	} else {
+		employeeResponses, err = controller.EmployeeService.FindAll(c.Context())
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   err.Error(),
-		})
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
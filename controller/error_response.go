@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorResponse maps a service error to its WebResponse: not found errors to 404, invalid
+// input to 400, missing permissions to 403 and everything else to 500
+func errorResponse(c *fiber.Ctx, err error) error {
+	var notFoundError exception.NotFoundError
+	var badRequestError exception.BadRequestError
+	var forbiddenError exception.ForbiddenError
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.As(err, &notFoundError):
+		return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
+			Code:   fiber.StatusNotFound,
+			Status: "Not Found",
+			Data:   err.Error(),
+		})
+	case errors.As(err, &badRequestError), errors.As(err, &validationErrors):
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	case errors.As(err, &forbiddenError):
+		return c.Status(fiber.StatusForbidden).JSON(web.WebResponse{
+			Code:   fiber.StatusForbidden,
+			Status: "Forbidden",
+			Data:   err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+			Code:   fiber.StatusInternalServerError,
+			Status: "Internal Server Error",
+			Data:   err.Error(),
+		})
+	}
+}
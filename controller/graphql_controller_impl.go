@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/gql"
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+type GraphQLControllerImpl struct {
+	Executor *gql.Executor
+}
+
+func NewGraphQLController(executor *gql.Executor) GraphQLController {
+	return &GraphQLControllerImpl{
+		Executor: executor,
+	}
+}
+
+// Query runs a GraphQL query or mutation in the store context of the request. Errors of an executed request are reported in the
+// body with status 200, a request that cannot be executed is answered with 400.
+func (controller *GraphQLControllerImpl) Query(c *fiber.Ctx) error {
+	request := new(gql.GraphQLRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(gql.GraphQLResponse{
+			Errors: gqlerrors.FormatErrors(err),
+		})
+	}
+
+	response, executed := controller.Executor.Execute(gql.WithStore(c.Context(), middleware.Store(c)), *request)
+	if !executed {
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthControllerImpl struct {
+	Registry *health.Registry
+}
+
+func NewHealthController(registry *health.Registry) HealthController {
+	return &HealthControllerImpl{
+		Registry: registry,
+	}
+}
+
+// Liveness only tells that the process is able to answer HTTP requests
+func (controller *HealthControllerImpl) Liveness(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: health.StatusUp,
+	})
+}
+
+// Readiness runs every registered dependency check
+func (controller *HealthControllerImpl) Readiness(c *fiber.Ctx) error {
+	report := controller.Registry.Check(c.UserContext())
+	if report.Status != health.StatusUp {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(web.WebResponse{
+			Code:   fiber.StatusServiceUnavailable,
+			Status: report.Status,
+			Data:   report,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: report.Status,
+		Data:   report,
+	})
+}
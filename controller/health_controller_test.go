@@ -0,0 +1,88 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChecker struct {
+	err error
+}
+
+func (checker fakeChecker) Name() string {
+	return "fake"
+}
+
+func (checker fakeChecker) Check(ctx context.Context) error {
+	return checker.err
+}
+
+func setupTestAppHealth(registry *health.Registry) *fiber.App {
+	app := fiber.New()
+	healthController := controller.NewHealthController(registry)
+
+	app.Get("/healthz", healthController.Liveness)
+	app.Get("/readyz", healthController.Readiness)
+
+	return app
+}
+
+func TestHealthController(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		checker        health.Checker
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Liveness - up",
+			url:            "/healthz",
+			checker:        fakeChecker{err: errors.New("database is down")},
+			expectedStatus: http.StatusOK,
+			expectedBody:   health.StatusUp,
+		},
+		{
+			name:           "Readiness - up",
+			url:            "/readyz",
+			checker:        fakeChecker{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   health.StatusUp,
+		},
+		{
+			name:           "Readiness - down",
+			url:            "/readyz",
+			checker:        fakeChecker{err: errors.New("database is down")},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   health.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry(time.Second)
+			registry.Register(tt.checker)
+			app := setupTestAppHealth(registry)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody struct {
+				Status string `json:"status"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expectedBody, respBody.Status)
+		})
+	}
+}
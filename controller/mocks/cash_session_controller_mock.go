@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/cash_session_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCashSessionController is a mock of CashSessionController interface.
+type MockCashSessionController struct {
+	ctrl     *gomock.Controller
+	recorder *MockCashSessionControllerMockRecorder
+}
+
+// MockCashSessionControllerMockRecorder is the mock recorder for MockCashSessionController.
+type MockCashSessionControllerMockRecorder struct {
+	mock *MockCashSessionController
+}
+
+// NewMockCashSessionController creates a new mock instance.
+func NewMockCashSessionController(ctrl *gomock.Controller) *MockCashSessionController {
+	mock := &MockCashSessionController{ctrl: ctrl}
+	mock.recorder = &MockCashSessionControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCashSessionController) EXPECT() *MockCashSessionControllerMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockCashSessionController) Close(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockCashSessionControllerMockRecorder) Close(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCashSessionController)(nil).Close), c)
+}
+
+// FindAll mocks base method.
+func (m *MockCashSessionController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockCashSessionControllerMockRecorder) FindAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockCashSessionController)(nil).FindAll), c)
+}
+
+// FindById mocks base method.
+func (m *MockCashSessionController) FindById(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCashSessionControllerMockRecorder) FindById(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCashSessionController)(nil).FindById), c)
+}
+
+// Open mocks base method.
+func (m *MockCashSessionController) Open(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockCashSessionControllerMockRecorder) Open(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockCashSessionController)(nil).Open), c)
+}
+
+// PrintReport mocks base method.
+func (m *MockCashSessionController) PrintReport(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrintReport", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrintReport indicates an expected call of PrintReport.
+func (mr *MockCashSessionControllerMockRecorder) PrintReport(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrintReport", reflect.TypeOf((*MockCashSessionController)(nil).PrintReport), c)
+}
+
+// PrintStoreReport mocks base method.
+func (m *MockCashSessionController) PrintStoreReport(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrintStoreReport", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrintStoreReport indicates an expected call of PrintStoreReport.
+func (mr *MockCashSessionControllerMockRecorder) PrintStoreReport(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrintStoreReport", reflect.TypeOf((*MockCashSessionController)(nil).PrintStoreReport), c)
+}
+
+// RecordMovement mocks base method.
+func (m *MockCashSessionController) RecordMovement(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMovement", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMovement indicates an expected call of RecordMovement.
+func (mr *MockCashSessionControllerMockRecorder) RecordMovement(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMovement", reflect.TypeOf((*MockCashSessionController)(nil).RecordMovement), c)
+}
+
+// Report mocks base method.
+func (m *MockCashSessionController) Report(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockCashSessionControllerMockRecorder) Report(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockCashSessionController)(nil).Report), c)
+}
+
+// StoreReport mocks base method.
+func (m *MockCashSessionController) StoreReport(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreReport", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreReport indicates an expected call of StoreReport.
+func (mr *MockCashSessionControllerMockRecorder) StoreReport(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreReport", reflect.TypeOf((*MockCashSessionController)(nil).StoreReport), c)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/graphql_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGraphQLController is a mock of GraphQLController interface.
+type MockGraphQLController struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLControllerMockRecorder
+}
+
+// MockGraphQLControllerMockRecorder is the mock recorder for MockGraphQLController.
+type MockGraphQLControllerMockRecorder struct {
+	mock *MockGraphQLController
+}
+
+// NewMockGraphQLController creates a new mock instance.
+func NewMockGraphQLController(ctrl *gomock.Controller) *MockGraphQLController {
+	mock := &MockGraphQLController{ctrl: ctrl}
+	mock.recorder = &MockGraphQLControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQLController) EXPECT() *MockGraphQLControllerMockRecorder {
+	return m.recorder
+}
+
+// Query mocks base method.
+func (m *MockGraphQLController) Query(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockGraphQLControllerMockRecorder) Query(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockGraphQLController)(nil).Query), c)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/order_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderController is a mock of OrderController interface.
+type MockOrderController struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderControllerMockRecorder
+}
+
+// MockOrderControllerMockRecorder is the mock recorder for MockOrderController.
+type MockOrderControllerMockRecorder struct {
+	mock *MockOrderController
+}
+
+// NewMockOrderController creates a new mock instance.
+func NewMockOrderController(ctrl *gomock.Controller) *MockOrderController {
+	mock := &MockOrderController{ctrl: ctrl}
+	mock.recorder = &MockOrderControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderController) EXPECT() *MockOrderControllerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrderController) Create(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderControllerMockRecorder) Create(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderController)(nil).Create), c)
+}
+
+// FindAll mocks base method.
+func (m *MockOrderController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockOrderControllerMockRecorder) FindAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockOrderController)(nil).FindAll), c)
+}
+
+// FindById mocks base method.
+func (m *MockOrderController) FindById(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockOrderControllerMockRecorder) FindById(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockOrderController)(nil).FindById), c)
+}
+
+// Receipt mocks base method.
+func (m *MockOrderController) Receipt(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receipt", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Receipt indicates an expected call of Receipt.
+func (mr *MockOrderControllerMockRecorder) Receipt(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receipt", reflect.TypeOf((*MockOrderController)(nil).Receipt), c)
+}
@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/payment_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentController is a mock of PaymentController interface.
+type MockPaymentController struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentControllerMockRecorder
+}
+
+// MockPaymentControllerMockRecorder is the mock recorder for MockPaymentController.
+type MockPaymentControllerMockRecorder struct {
+	mock *MockPaymentController
+}
+
+// NewMockPaymentController creates a new mock instance.
+func NewMockPaymentController(ctrl *gomock.Controller) *MockPaymentController {
+	mock := &MockPaymentController{ctrl: ctrl}
+	mock.recorder = &MockPaymentControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentController) EXPECT() *MockPaymentControllerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPaymentController) Create(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentControllerMockRecorder) Create(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentController)(nil).Create), c)
+}
+
+// CreateQRIS mocks base method.
+func (m *MockPaymentController) CreateQRIS(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQRIS", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateQRIS indicates an expected call of CreateQRIS.
+func (mr *MockPaymentControllerMockRecorder) CreateQRIS(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQRIS", reflect.TypeOf((*MockPaymentController)(nil).CreateQRIS), c)
+}
+
+// FindAll mocks base method.
+func (m *MockPaymentController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockPaymentControllerMockRecorder) FindAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockPaymentController)(nil).FindAll), c)
+}
+
+// FindById mocks base method.
+func (m *MockPaymentController) FindById(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockPaymentControllerMockRecorder) FindById(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockPaymentController)(nil).FindById), c)
+}
+
+// Notify mocks base method.
+func (m *MockPaymentController) Notify(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockPaymentControllerMockRecorder) Notify(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockPaymentController)(nil).Notify), c)
+}
+
+// Sync mocks base method.
+func (m *MockPaymentController) Sync(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockPaymentControllerMockRecorder) Sync(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockPaymentController)(nil).Sync), c)
+}
+
+// VerifyQRIS mocks base method.
+func (m *MockPaymentController) VerifyQRIS(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyQRIS", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyQRIS indicates an expected call of VerifyQRIS.
+func (mr *MockPaymentControllerMockRecorder) VerifyQRIS(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyQRIS", reflect.TypeOf((*MockPaymentController)(nil).VerifyQRIS), c)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/pricing_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPricingController is a mock of PricingController interface.
+type MockPricingController struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingControllerMockRecorder
+}
+
+// MockPricingControllerMockRecorder is the mock recorder for MockPricingController.
+type MockPricingControllerMockRecorder struct {
+	mock *MockPricingController
+}
+
+// NewMockPricingController creates a new mock instance.
+func NewMockPricingController(ctrl *gomock.Controller) *MockPricingController {
+	mock := &MockPricingController{ctrl: ctrl}
+	mock.recorder = &MockPricingControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricingController) EXPECT() *MockPricingControllerMockRecorder {
+	return m.recorder
+}
+
+// FindTaxClasses mocks base method.
+func (m *MockPricingController) FindTaxClasses(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTaxClasses", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindTaxClasses indicates an expected call of FindTaxClasses.
+func (mr *MockPricingControllerMockRecorder) FindTaxClasses(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTaxClasses", reflect.TypeOf((*MockPricingController)(nil).FindTaxClasses), c)
+}
+
+// Quote mocks base method.
+func (m *MockPricingController) Quote(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockPricingControllerMockRecorder) Quote(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockPricingController)(nil).Quote), c)
+}
@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/stock_transfer_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStockTransferController is a mock of StockTransferController interface.
+type MockStockTransferController struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockTransferControllerMockRecorder
+}
+
+// MockStockTransferControllerMockRecorder is the mock recorder for MockStockTransferController.
+type MockStockTransferControllerMockRecorder struct {
+	mock *MockStockTransferController
+}
+
+// NewMockStockTransferController creates a new mock instance.
+func NewMockStockTransferController(ctrl *gomock.Controller) *MockStockTransferController {
+	mock := &MockStockTransferController{ctrl: ctrl}
+	mock.recorder = &MockStockTransferControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockTransferController) EXPECT() *MockStockTransferControllerMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockStockTransferController) Cancel(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockStockTransferControllerMockRecorder) Cancel(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockStockTransferController)(nil).Cancel), c)
+}
+
+// Create mocks base method.
+func (m *MockStockTransferController) Create(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStockTransferControllerMockRecorder) Create(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStockTransferController)(nil).Create), c)
+}
+
+// Dispatch mocks base method.
+func (m *MockStockTransferController) Dispatch(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockStockTransferControllerMockRecorder) Dispatch(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockStockTransferController)(nil).Dispatch), c)
+}
+
+// FindAll mocks base method.
+func (m *MockStockTransferController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockStockTransferControllerMockRecorder) FindAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockStockTransferController)(nil).FindAll), c)
+}
+
+// FindById mocks base method.
+func (m *MockStockTransferController) FindById(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStockTransferControllerMockRecorder) FindById(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStockTransferController)(nil).FindById), c)
+}
+
+// Receive mocks base method.
+func (m *MockStockTransferController) Receive(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receive", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Receive indicates an expected call of Receive.
+func (mr *MockStockTransferControllerMockRecorder) Receive(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receive", reflect.TypeOf((*MockStockTransferController)(nil).Receive), c)
+}
+
+// Update mocks base method.
+func (m *MockStockTransferController) Update(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStockTransferControllerMockRecorder) Update(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStockTransferController)(nil).Update), c)
+}
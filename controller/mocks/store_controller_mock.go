@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/store_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStoreController is a mock of StoreController interface.
+type MockStoreController struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreControllerMockRecorder
+}
+
+// MockStoreControllerMockRecorder is the mock recorder for MockStoreController.
+type MockStoreControllerMockRecorder struct {
+	mock *MockStoreController
+}
+
+// NewMockStoreController creates a new mock instance.
+func NewMockStoreController(ctrl *gomock.Controller) *MockStoreController {
+	mock := &MockStoreController{ctrl: ctrl}
+	mock.recorder = &MockStoreControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreController) EXPECT() *MockStoreControllerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockStoreController) Create(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStoreControllerMockRecorder) Create(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStoreController)(nil).Create), c)
+}
+
+// Delete mocks base method.
+func (m *MockStoreController) Delete(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStoreControllerMockRecorder) Delete(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStoreController)(nil).Delete), c)
+}
+
+// FindAll mocks base method.
+func (m *MockStoreController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockStoreControllerMockRecorder) FindAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockStoreController)(nil).FindAll), c)
+}
+
+// FindById mocks base method.
+func (m *MockStoreController) FindById(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStoreControllerMockRecorder) FindById(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStoreController)(nil).FindById), c)
+}
+
+// FindStock mocks base method.
+func (m *MockStoreController) FindStock(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStock", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindStock indicates an expected call of FindStock.
+func (mr *MockStoreControllerMockRecorder) FindStock(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStock", reflect.TypeOf((*MockStoreController)(nil).FindStock), c)
+}
+
+// Update mocks base method.
+func (m *MockStoreController) Update(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStoreControllerMockRecorder) Update(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStoreController)(nil).Update), c)
+}
+
+// UpdateStock mocks base method.
+func (m *MockStoreController) UpdateStock(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStock", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStock indicates an expected call of UpdateStock.
+func (mr *MockStoreControllerMockRecorder) UpdateStock(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockStoreController)(nil).UpdateStock), c)
+}
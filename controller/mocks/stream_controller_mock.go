@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/stream_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStreamController is a mock of StreamController interface.
+type MockStreamController struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamControllerMockRecorder
+}
+
+// MockStreamControllerMockRecorder is the mock recorder for MockStreamController.
+type MockStreamControllerMockRecorder struct {
+	mock *MockStreamController
+}
+
+// NewMockStreamController creates a new mock instance.
+func NewMockStreamController(ctrl *gomock.Controller) *MockStreamController {
+	mock := &MockStreamController{ctrl: ctrl}
+	mock.recorder = &MockStreamControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamController) EXPECT() *MockStreamControllerMockRecorder {
+	return m.recorder
+}
+
+// Stream mocks base method.
+func (m *MockStreamController) Stream(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockStreamControllerMockRecorder) Stream(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockStreamController)(nil).Stream), c)
+}
@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/webhook_controller.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookController is a mock of WebhookController interface.
+type MockWebhookController struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookControllerMockRecorder
+}
+
+// MockWebhookControllerMockRecorder is the mock recorder for MockWebhookController.
+type MockWebhookControllerMockRecorder struct {
+	mock *MockWebhookController
+}
+
+// NewMockWebhookController creates a new mock instance.
+func NewMockWebhookController(ctrl *gomock.Controller) *MockWebhookController {
+	mock := &MockWebhookController{ctrl: ctrl}
+	mock.recorder = &MockWebhookControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookController) EXPECT() *MockWebhookControllerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookController) Create(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookControllerMockRecorder) Create(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookController)(nil).Create), c)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookController) Delete(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookControllerMockRecorder) Delete(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookController)(nil).Delete), c)
+}
+
+// FindAll mocks base method.
+func (m *MockWebhookController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockWebhookControllerMockRecorder) FindAll(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockWebhookController)(nil).FindAll), c)
+}
+
+// FindById mocks base method.
+func (m *MockWebhookController) FindById(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockWebhookControllerMockRecorder) FindById(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockWebhookController)(nil).FindById), c)
+}
+
+// FindDeliveries mocks base method.
+func (m *MockWebhookController) FindDeliveries(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeliveries", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindDeliveries indicates an expected call of FindDeliveries.
+func (mr *MockWebhookControllerMockRecorder) FindDeliveries(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeliveries", reflect.TypeOf((*MockWebhookController)(nil).FindDeliveries), c)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookController) Redeliver(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookControllerMockRecorder) Redeliver(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookController)(nil).Redeliver), c)
+}
+
+// Update mocks base method.
+func (m *MockWebhookController) Update(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookControllerMockRecorder) Update(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookController)(nil).Update), c)
+}
@@ -0,0 +1,10 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type OrderController interface {
+	Create(c *fiber.Ctx) error
+	FindById(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	Receipt(c *fiber.Ctx) error
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderControllerImpl struct {
+	OrderService service.OrderService
+}
+
+func NewOrderController(orderService service.OrderService) OrderController {
+	return &OrderControllerImpl{
+		OrderService: orderService,
+	}
+}
+
+// Create Order in the store context
+func (controller *OrderControllerImpl) Create(c *fiber.Ctx) error {
+	orderCreateRequest := new(web.OrderCreateRequest)
+	if err := c.BodyParser(orderCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	orderCreateRequest.StoreID = middleware.Store(c)
+
+	orderResponse, err := controller.OrderService.Create(c.Context(), middleware.Principal(c), *orderCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   orderResponse,
+	})
+}
+
+// Find Order By ID
+func (controller *OrderControllerImpl) FindById(c *fiber.Ctx) error {
+	orderResponse, err := controller.OrderService.FindById(c.Context(), middleware.Principal(c), c.Params("orderId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   orderResponse,
+	})
+}
+
+// Find All Orders of the store context
+func (controller *OrderControllerImpl) FindAll(c *fiber.Ctx) error {
+	orderResponses, err := controller.OrderService.FindAll(c.Context(), middleware.Store(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   orderResponses,
+	})
+}
+
+// Receipt of an Order. The format query is html, text, the default, or escpos and the paper
+// query the width of the paper in millimetres, 58 or 80, the default.
+func (controller *OrderControllerImpl) Receipt(c *fiber.Ctx) error {
+	format := c.Query("format", receipt.FormatText)
+	if format != receipt.FormatHTML && format != receipt.FormatText && format != receipt.FormatESCPOS {
+		return errorResponse(c, exception.NewBadRequestError("format must be html, text or escpos"))
+	}
+	paper, err := receipt.ParsePaper(c.Query("paper", "80"))
+	if err != nil {
+		return errorResponse(c, exception.NewBadRequestError(err.Error()))
+	}
+
+	orderReceipt, err := controller.OrderService.Receipt(c.Context(), middleware.Principal(c), c.Params("orderId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	body, err := receipt.Render(orderReceipt, format, paper)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, receipt.ContentType(format))
+	return c.Status(fiber.StatusOK).Send(body)
+}
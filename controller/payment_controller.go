@@ -0,0 +1,13 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type PaymentController interface {
+	Create(c *fiber.Ctx) error
+	FindById(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	Sync(c *fiber.Ctx) error
+	Notify(c *fiber.Ctx) error
+	CreateQRIS(c *fiber.Ctx) error
+	VerifyQRIS(c *fiber.Ctx) error
+}
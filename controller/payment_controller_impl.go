@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentControllerImpl struct {
+	PaymentService service.PaymentService
+}
+
+func NewPaymentController(paymentService service.PaymentService) PaymentController {
+	return &PaymentControllerImpl{
+		PaymentService: paymentService,
+	}
+}
+
+// Create Payment of an order
+func (controller *PaymentControllerImpl) Create(c *fiber.Ctx) error {
+	paymentCreateRequest := new(web.PaymentCreateRequest)
+	if err := c.BodyParser(paymentCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	paymentResponse, err := controller.PaymentService.Create(c.Context(), middleware.Principal(c), *paymentCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   paymentResponse,
+	})
+}
+
+// Find Payment By ID
+func (controller *PaymentControllerImpl) FindById(c *fiber.Ctx) error {
+	paymentResponse, err := controller.PaymentService.FindById(c.Context(), middleware.Principal(c), c.Params("paymentId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   paymentResponse,
+	})
+}
+
+// Find All Payments of the store context, of a customer with the customer_id query
+func (controller *PaymentControllerImpl) FindAll(c *fiber.Ctx) error {
+	paymentResponses, err := controller.PaymentService.FindAll(c.Context(), middleware.Store(c), c.Query("customer_id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   paymentResponses,
+	})
+}
+
+// Sync asks the gateway how the pending cards of a Payment ended
+func (controller *PaymentControllerImpl) Sync(c *fiber.Ctx) error {
+	paymentResponse, err := controller.PaymentService.Sync(c.Context(), middleware.Principal(c), c.Params("paymentId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   paymentResponse,
+	})
+}
+
+// Notify receives the notifications of a payment gateway, authenticated by their signature
+// rather than by an API key
+func (controller *PaymentControllerImpl) Notify(c *fiber.Ctx) error {
+	header := func(key string) string { return c.Get(key) }
+	notificationResponse, err := controller.PaymentService.Notify(c.Context(), c.Params("provider"), header, c.Body())
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return c.Status(fiber.StatusUnauthorized).JSON(web.WebResponse{
+			Code:   fiber.StatusUnauthorized,
+			Status: "Unauthorized",
+			Data:   err.Error(),
+		})
+	} else if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   notificationResponse,
+	})
+}
+
+// CreateQRIS returns the QRIS payload for an order or an amount
+func (controller *PaymentControllerImpl) CreateQRIS(c *fiber.Ctx) error {
+	qrisCreateRequest := new(web.QRISCreateRequest)
+	if err := c.BodyParser(qrisCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	qrisResponse, err := controller.PaymentService.CreateQRIS(c.Context(), middleware.Principal(c), *qrisCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   qrisResponse,
+	})
+}
+
+// VerifyQRIS checks and reads a QRIS payload scanned back
+func (controller *PaymentControllerImpl) VerifyQRIS(c *fiber.Ctx) error {
+	qrisVerifyRequest := new(web.QRISVerifyRequest)
+	if err := c.BodyParser(qrisVerifyRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	qrisResponse, err := controller.PaymentService.VerifyQRIS(c.Context(), *qrisVerifyRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   qrisResponse,
+	})
+}
@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type PricingController interface {
+	Quote(c *fiber.Ctx) error
+	FindTaxClasses(c *fiber.Ctx) error
+}
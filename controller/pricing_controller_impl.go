@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PricingControllerImpl struct {
+	PricingService service.PricingService
+}
+
+func NewPricingController(pricingService service.PricingService) PricingController {
+	return &PricingControllerImpl{
+		PricingService: pricingService,
+	}
+}
+
+// Quote prices a basket with the promotions in effect, for the store context if any
+func (controller *PricingControllerImpl) Quote(c *fiber.Ctx) error {
+	quoteRequest := new(web.QuoteRequest)
+	if err := c.BodyParser(quoteRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	quoteRequest.StoreID = middleware.Store(c)
+
+	quoteResponse, err := controller.PricingService.Quote(c.Context(), *quoteRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   quoteResponse,
+	})
+}
+
+// FindTaxClasses lists the tax classes
+func (controller *PricingControllerImpl) FindTaxClasses(c *fiber.Ctx) error {
+	classResponses, err := controller.PricingService.FindTaxClasses(c.Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   classResponses,
+	})
+}
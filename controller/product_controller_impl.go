@@ -1,7 +1,11 @@
 package controller
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/middleware"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
@@ -9,11 +13,13 @@ import (
 
 type ProductControllerImpl struct {
 	ProductService service.ProductService
+	StoreService   service.StoreService
 }
 
-func NewProductController(productService service.ProductService) ProductController {
+func NewProductController(productService service.ProductService, storeService service.StoreService) ProductController {
 	return &ProductControllerImpl{
 		ProductService: productService,
+		StoreService:   storeService,
 	}
 }
 
@@ -30,11 +36,7 @@ func (controller *ProductControllerImpl) Create(c *fiber.Ctx) error {
 
 	productResponse, err := controller.ProductService.Create(c.Context(), *productCreateRequest)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   err.Error(),
-		})
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
@@ -67,18 +69,7 @@ func (controller *ProductControllerImpl) Update(c *fiber.Ctx) error {
 
 	productResponse, err := controller.ProductService.Update(c.Context(), *productUpdateRequest)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   err.Error(),
-		})
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
@@ -121,7 +112,7 @@ func (controller *ProductControllerImpl) Delete(c *fiber.Ctx) error {
 	})
 }
 
-// Find Product By ID
+// Find Product By ID, with the stock and the price of the store in a store context
 func (controller *ProductControllerImpl) FindById(c *fiber.Ctx) error {
 	productID := c.Params("productId")
 	if productID == "" {
@@ -132,7 +123,13 @@ func (controller *ProductControllerImpl) FindById(c *fiber.Ctx) error {
 		})
 	}
 
-	productResponse, err := controller.ProductService.FindById(c.Context(), productID)
+	var productResponse web.ProductResponse
+	var err error
+	if storeId := middleware.Store(c); storeId != "" {
+		productResponse, err = controller.StoreService.FindProduct(c.Context(), storeId, productID)
+	} else {
+		productResponse, err = controller.ProductService.FindById(c.Context(), productID)
+	}
 	if err != nil {
 		if _, ok := err.(exception.NotFoundError); ok {
 			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
@@ -155,15 +152,24 @@ func (controller *ProductControllerImpl) FindById(c *fiber.Ctx) error {
 	})
 }
 
-// Find All Products
+// Find All Products, with the stock and the prices of the store in a store context. The
+// options query, e.g. size:M,colour:Merah, keeps the products with a variant of those values.
 func (controller *ProductControllerImpl) FindAll(c *fiber.Ctx) error {
-	productResponses, err := controller.ProductService.FindAll(c.Context())
+	options, err := parseOptions(c.Query("options"))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   err.Error(),
-		})
+		return errorResponse(c, err)
+	}
+
+	var productResponses []web.ProductResponse
+	if storeId := middleware.Store(c); storeId != "" {
+		productResponses, err = controller.StoreService.FindProducts(c.Context(), storeId, options)
+	} else if len(options) > 0 {
+		productResponses, err = controller.ProductService.FindByOptions(c.Context(), options)
+	} else {
+		productResponses, err = controller.ProductService.FindAll(c.Context())
+	}
+	if err != nil {
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
@@ -172,3 +178,23 @@ func (controller *ProductControllerImpl) FindAll(c *fiber.Ctx) error {
 		Data:   productResponses,
 	})
 }
+
+// parseOptions reads a comma separated list of name:value pairs
+func parseOptions(raw string) (map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	options := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, exception.NewBadRequestError(fmt.Sprintf("option %q is not name:value", pair))
+		}
+		if _, ok := options[name]; ok {
+			return nil, exception.NewBadRequestError(fmt.Sprintf("option %s is given twice", name))
+		}
+		options[name] = value
+	}
+	return options, nil
+}
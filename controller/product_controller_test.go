@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
 	"github.com/aronipurwanto/go-restful-api/service/mocks"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
@@ -15,7 +16,7 @@ import (
 
 func setupTestAppProduct(mockService *mocks.MockProductService) *fiber.App {
 	app := fiber.New()
-	productController := NewProductController(mockService)
+	productController := NewProductController(mockService, nil)
 
 	api := app.Group("/api")
 	products := api.Group("/products")
@@ -48,25 +49,28 @@ func TestProductController(t *testing.T) {
 			name:   "Create product - success",
 			method: "POST",
 			url:    "/api/products/",
-			body:   web.ProductCreateRequest{Name: "Product A", Price: 1000},
+			body:   web.ProductCreateRequest{Name: "Product A", Price: money.IDR(1000)},
 			setupMock: func() {
 				mockService.EXPECT().
 					Create(gomock.Any(), gomock.Any()).
-					Return(web.ProductResponse{ProductID: "1", Name: "Product A", Price: 1000}, nil)
+					Return(web.ProductResponse{ProductID: "1", Name: "Product A", Price: 1000, PriceAmount: money.IDR(1000), Currency: "IDR"}, nil)
 			},
 			expectedStatus: http.StatusCreated,
 			expectedBody: web.WebResponse{
 				Code:   http.StatusCreated,
 				Status: "Created",
 				Data: map[string]interface{}{
-					"product_id":  "1",
-					"name":        "Product A",
-					"description": "",
-					"price":       float64(1000),
-					"stock_qty":   float64(0),
-					"category":    "",
-					"sku":         "",
-					"tax_rate":    float64(0),
+					"product_id":   "1",
+					"name":         "Product A",
+					"description":  "",
+					"price":        float64(1000),
+					"price_amount": "1000",
+					"currency":     "IDR",
+					"stock_qty":    float64(0),
+					"category":     "",
+					"sku":          "",
+					"tax_rate":     float64(0),
+					"tax_class":    "",
 				},
 			},
 		},
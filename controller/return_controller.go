@@ -0,0 +1,11 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type ReturnController interface {
+	Create(c *fiber.Ctx) error
+	FindById(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	Approve(c *fiber.Ctx) error
+	Reject(c *fiber.Ctx) error
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReturnControllerImpl struct {
+	ReturnService service.ReturnService
+}
+
+func NewReturnController(returnService service.ReturnService) ReturnController {
+	return &ReturnControllerImpl{
+		ReturnService: returnService,
+	}
+}
+
+// Create Return of lines of an order
+func (controller *ReturnControllerImpl) Create(c *fiber.Ctx) error {
+	returnCreateRequest := new(web.ReturnCreateRequest)
+	if err := c.BodyParser(returnCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	returnResponse, err := controller.ReturnService.Create(c.Context(), middleware.Principal(c), *returnCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   returnResponse,
+	})
+}
+
+// Find Return By ID
+func (controller *ReturnControllerImpl) FindById(c *fiber.Ctx) error {
+	returnResponse, err := controller.ReturnService.FindById(c.Context(), middleware.Principal(c), c.Params("returnId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   returnResponse,
+	})
+}
+
+// Find All Returns of the store context, of one status with the status query
+func (controller *ReturnControllerImpl) FindAll(c *fiber.Ctx) error {
+	returnResponses, err := controller.ReturnService.FindAll(c.Context(), middleware.Store(c), c.Query("status"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   returnResponses,
+	})
+}
+
+// Approve a pending Return, which completes it
+func (controller *ReturnControllerImpl) Approve(c *fiber.Ctx) error {
+	decisionRequest := new(web.ReturnDecisionRequest)
+	if err := c.BodyParser(decisionRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	decisionRequest.ReturnID = c.Params("returnId")
+
+	returnResponse, err := controller.ReturnService.Approve(c.Context(), middleware.Principal(c), *decisionRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   returnResponse,
+	})
+}
+
+// Reject a pending Return
+func (controller *ReturnControllerImpl) Reject(c *fiber.Ctx) error {
+	decisionRequest := new(web.ReturnDecisionRequest)
+	if err := c.BodyParser(decisionRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	decisionRequest.ReturnID = c.Params("returnId")
+
+	returnResponse, err := controller.ReturnService.Reject(c.Context(), middleware.Principal(c), *decisionRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   returnResponse,
+	})
+}
@@ -0,0 +1,13 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type StockTransferController interface {
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Cancel(c *fiber.Ctx) error
+	Dispatch(c *fiber.Ctx) error
+	Receive(c *fiber.Ctx) error
+	FindById(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type StockTransferControllerImpl struct {
+	StockTransferService service.StockTransferService
+}
+
+func NewStockTransferController(stockTransferService service.StockTransferService) StockTransferController {
+	return &StockTransferControllerImpl{
+		StockTransferService: stockTransferService,
+	}
+}
+
+// Create Stock Transfer
+func (controller *StockTransferControllerImpl) Create(c *fiber.Ctx) error {
+	transferCreateRequest := new(web.StockTransferCreateRequest)
+	if err := c.BodyParser(transferCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	transferResponse, err := controller.StockTransferService.Create(c.Context(), middleware.Principal(c), *transferCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   transferResponse,
+	})
+}
+
+// Update Stock Transfer
+func (controller *StockTransferControllerImpl) Update(c *fiber.Ctx) error {
+	transferUpdateRequest := new(web.StockTransferUpdateRequest)
+	if err := c.BodyParser(transferUpdateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	transferUpdateRequest.TransferID = c.Params("transferId")
+
+	transferResponse, err := controller.StockTransferService.Update(c.Context(), middleware.Principal(c), *transferUpdateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   transferResponse,
+	})
+}
+
+// Cancel Stock Transfer
+func (controller *StockTransferControllerImpl) Cancel(c *fiber.Ctx) error {
+	transferResponse, err := controller.StockTransferService.Cancel(c.Context(), middleware.Principal(c), c.Params("transferId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   transferResponse,
+	})
+}
+
+// Dispatch Stock Transfer
+func (controller *StockTransferControllerImpl) Dispatch(c *fiber.Ctx) error {
+	transferResponse, err := controller.StockTransferService.Dispatch(c.Context(), middleware.Principal(c), c.Params("transferId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   transferResponse,
+	})
+}
+
+// Receive Stock Transfer
+func (controller *StockTransferControllerImpl) Receive(c *fiber.Ctx) error {
+	receiveRequest := new(web.StockTransferReceiveRequest)
+	if err := c.BodyParser(receiveRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	receiveRequest.TransferID = c.Params("transferId")
+
+	transferResponse, err := controller.StockTransferService.Receive(c.Context(), middleware.Principal(c), *receiveRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   transferResponse,
+	})
+}
+
+// Find Stock Transfer By ID
+func (controller *StockTransferControllerImpl) FindById(c *fiber.Ctx) error {
+	transferResponse, err := controller.StockTransferService.FindById(c.Context(), middleware.Principal(c), c.Params("transferId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   transferResponse,
+	})
+}
+
+// Find All Stock Transfers of the store context, filtered by the status query parameter
+func (controller *StockTransferControllerImpl) FindAll(c *fiber.Ctx) error {
+	transferResponses, err := controller.StockTransferService.FindAll(c.Context(), middleware.Store(c), c.Query("status"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   transferResponses,
+	})
+}
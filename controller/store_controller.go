@@ -0,0 +1,13 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type StoreController interface {
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindById(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	FindStock(c *fiber.Ctx) error
+	UpdateStock(c *fiber.Ctx) error
+}
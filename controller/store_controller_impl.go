@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type StoreControllerImpl struct {
+	StoreService service.StoreService
+}
+
+func NewStoreController(storeService service.StoreService) StoreController {
+	return &StoreControllerImpl{
+		StoreService: storeService,
+	}
+}
+
+// Create Store
+func (controller *StoreControllerImpl) Create(c *fiber.Ctx) error {
+	storeCreateRequest := new(web.StoreCreateRequest)
+	if err := c.BodyParser(storeCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	storeResponse, err := controller.StoreService.Create(c.Context(), middleware.Principal(c), *storeCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   storeResponse,
+	})
+}
+
+// Update Store
+func (controller *StoreControllerImpl) Update(c *fiber.Ctx) error {
+	storeUpdateRequest := new(web.StoreUpdateRequest)
+	if err := c.BodyParser(storeUpdateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	storeUpdateRequest.StoreID = c.Params("storeId")
+
+	storeResponse, err := controller.StoreService.Update(c.Context(), middleware.Principal(c), *storeUpdateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   storeResponse,
+	})
+}
+
+// Delete Store
+func (controller *StoreControllerImpl) Delete(c *fiber.Ctx) error {
+	if err := controller.StoreService.Delete(c.Context(), middleware.Principal(c), c.Params("storeId")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Find Store By ID
+func (controller *StoreControllerImpl) FindById(c *fiber.Ctx) error {
+	storeResponse, err := controller.StoreService.FindById(c.Context(), middleware.Principal(c), c.Params("storeId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   storeResponse,
+	})
+}
+
+// Find All Stores the caller may access
+func (controller *StoreControllerImpl) FindAll(c *fiber.Ctx) error {
+	storeResponses, err := controller.StoreService.FindAll(c.Context(), middleware.Principal(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   storeResponses,
+	})
+}
+
+// Find Stock of a Store
+func (controller *StoreControllerImpl) FindStock(c *fiber.Ctx) error {
+	stockResponses, err := controller.StoreService.FindStock(c.Context(), middleware.Principal(c), c.Params("storeId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   stockResponses,
+	})
+}
+
+// Update Stock of a Product in a Store
+func (controller *StoreControllerImpl) UpdateStock(c *fiber.Ctx) error {
+	stockUpdateRequest := new(web.StoreStockUpdateRequest)
+	if err := c.BodyParser(stockUpdateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	stockUpdateRequest.StoreID = c.Params("storeId")
+	stockUpdateRequest.ProductID = c.Params("productId")
+
+	stockResponse, err := controller.StoreService.UpdateStock(c.Context(), middleware.Principal(c), *stockUpdateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   stockResponse,
+	})
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/stream"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reconnectDelay is the retry delay announced to EventSource clients
+const reconnectDelay = 3 * time.Second
+
+type StreamControllerImpl struct {
+	StreamService service.StreamService
+	Heartbeat     time.Duration
+	ReplayBatch   int
+}
+
+func NewStreamController(streamService service.StreamService, heartbeat time.Duration) StreamController {
+	return &StreamControllerImpl{
+		StreamService: streamService,
+		Heartbeat:     heartbeat,
+		ReplayBatch:   100,
+	}
+}
+
+// Stream sends the changes of the requested topics as Server-Sent Events, the id of an event is
+// the sequence it was published with. A client reconnecting with Last-Event-ID first receives
+// the events it missed, then the live ones.
+func (controller *StreamControllerImpl) Stream(c *fiber.Ctx) error {
+	var lastSequence uint64
+	resume := false
+	if raw := c.Get("Last-Event-ID"); raw != "" {
+		sequence, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return errorResponse(c, exception.NewBadRequestError("Last-Event-ID must be the id of a received event"))
+		}
+		lastSequence, resume = sequence, true
+	}
+
+	subscription, err := controller.StreamService.Subscribe(c.Context(), middleware.Principal(c), c.Query("topics"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Disable response buffering of nginx style proxies
+	c.Set("X-Accel-Buffering", "no")
+
+	// The writer runs after this handler returned, it must not touch c
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Close()
+		controller.write(w, subscription, lastSequence, resume)
+	})
+	return nil
+}
+
+func (controller *StreamControllerImpl) write(w *bufio.Writer, subscription *stream.Subscription, lastSequence uint64, resume bool) {
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	if w.Flush() != nil {
+		return
+	}
+
+	// The events published while replaying are received live too, those the replay sent are
+	// skipped. Nothing else is: a live event is new even with a sequence below the last one sent.
+	replayed := map[uint64]bool{}
+	if resume {
+		for {
+			events, err := controller.StreamService.Replay(context.Background(), subscription.Topics(), lastSequence, controller.ReplayBatch)
+			if err != nil {
+				// End the stream, the client reconnects and retries from the same id
+				fmt.Fprintf(w, ": replay failed\n\n")
+				w.Flush()
+				return
+			}
+			for _, e := range events {
+				if writeEvent(w, e) != nil {
+					return
+				}
+				replayed[e.Sequence] = true
+				lastSequence = e.Sequence
+			}
+			if len(events) < controller.ReplayBatch {
+				break
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(controller.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case e, ok := <-subscription.Events():
+			if !ok {
+				if subscription.Lagged() {
+					fmt.Fprintf(w, ": too slow, reconnect with Last-Event-ID to catch up\n\n")
+					w.Flush()
+				}
+				return
+			}
+			if replayed[e.Sequence] {
+				delete(replayed, e.Sequence)
+				continue
+			}
+			if writeEvent(w, e) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// Keeps proxies from closing an idle connection and detects gone clients
+			fmt.Fprintf(w, ": heartbeat\n\n")
+			if w.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, e event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Sequence, e.Type, data)
+	return w.Flush()
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookControllerImpl struct {
+	WebhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) WebhookController {
+	return &WebhookControllerImpl{
+		WebhookService: webhookService,
+	}
+}
+
+// Create Webhook
+func (controller *WebhookControllerImpl) Create(c *fiber.Ctx) error {
+	webhookCreateRequest := new(web.WebhookCreateRequest)
+	if err := c.BodyParser(webhookCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	webhookResponse, err := controller.WebhookService.Create(c.Context(), *webhookCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   webhookResponse,
+	})
+}
+
+// Update Webhook
+func (controller *WebhookControllerImpl) Update(c *fiber.Ctx) error {
+	webhookUpdateRequest := new(web.WebhookUpdateRequest)
+	if err := c.BodyParser(webhookUpdateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	webhookUpdateRequest.WebhookID = c.Params("webhookId")
+
+	webhookResponse, err := controller.WebhookService.Update(c.Context(), *webhookUpdateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   webhookResponse,
+	})
+}
+
+// Delete Webhook
+func (controller *WebhookControllerImpl) Delete(c *fiber.Ctx) error {
+	if err := controller.WebhookService.Delete(c.Context(), c.Params("webhookId")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Find Webhook By ID
+func (controller *WebhookControllerImpl) FindById(c *fiber.Ctx) error {
+	webhookResponse, err := controller.WebhookService.FindById(c.Context(), c.Params("webhookId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   webhookResponse,
+	})
+}
+
+// Find All Webhooks
+func (controller *WebhookControllerImpl) FindAll(c *fiber.Ctx) error {
+	webhookResponses, err := controller.WebhookService.FindAll(c.Context())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   webhookResponses,
+	})
+}
+
+// Find Deliveries of a Webhook
+func (controller *WebhookControllerImpl) FindDeliveries(c *fiber.Ctx) error {
+	deliveryResponses, err := controller.WebhookService.FindDeliveries(c.Context(), c.Params("webhookId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   deliveryResponses,
+	})
+}
+
+// Redeliver a Webhook Delivery
+func (controller *WebhookControllerImpl) Redeliver(c *fiber.Ctx) error {
+	deliveryResponse, err := controller.WebhookService.Redeliver(c.Context(), c.Params("webhookId"), c.Params("deliveryId"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(web.WebResponse{
+		Code:   fiber.StatusAccepted,
+		Status: "Accepted",
+		Data:   deliveryResponse,
+	})
+}
@@ -0,0 +1,134 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+// Types of the events emitted by the services
+const (
+	CategoryCreated = "category.created"
+	CategoryUpdated = "category.updated"
+	CategoryDeleted = "category.deleted"
+
+	CustomerCreated = "customer.created"
+	CustomerUpdated = "customer.updated"
+	CustomerDeleted = "customer.deleted"
+
+	EmployeeCreated = "employee.created"
+	EmployeeUpdated = "employee.updated"
+	EmployeeDeleted = "employee.deleted"
+
+	ProductCreated      = "product.created"
+	ProductUpdated      = "product.updated"
+	ProductDeleted      = "product.deleted"
+	ProductPriceChanged = "product.price_changed"
+	ProductStockChanged = "product.stock_changed"
+
+	StoreCreated      = "store.created"
+	StoreUpdated      = "store.updated"
+	StoreDeleted      = "store.deleted"
+	StoreStockChanged = "store.stock_changed"
+
+	TransferCreated    = "transfer.created"
+	TransferUpdated    = "transfer.updated"
+	TransferDispatched = "transfer.dispatched"
+	TransferReceived   = "transfer.received"
+	TransferCancelled  = "transfer.cancelled"
+
+	PromotionCreated = "promotion.created"
+	PromotionUpdated = "promotion.updated"
+	PromotionDeleted = "promotion.deleted"
+
+	OrderCreated = "order.created"
+
+	PaymentCreated = "payment.created"
+	PaymentSettled = "payment.settled"
+	PaymentFailed  = "payment.failed"
+
+	ReturnCreated   = "return.created"
+	ReturnCompleted = "return.completed"
+	ReturnRejected  = "return.rejected"
+
+	CashSessionOpened    = "cash_session.opened"
+	CashSessionCashMoved = "cash_session.cash_moved"
+	CashSessionClosed    = "cash_session.closed"
+)
+
+// Wildcard subscribes to every event type
+const Wildcard = "*"
+
+var types = []string{
+	CategoryCreated, CategoryUpdated, CategoryDeleted,
+	CustomerCreated, CustomerUpdated, CustomerDeleted,
+	EmployeeCreated, EmployeeUpdated, EmployeeDeleted,
+	ProductCreated, ProductUpdated, ProductDeleted, ProductPriceChanged, ProductStockChanged,
+	StoreCreated, StoreUpdated, StoreDeleted, StoreStockChanged,
+	TransferCreated, TransferUpdated, TransferDispatched, TransferReceived, TransferCancelled,
+	PromotionCreated, PromotionUpdated, PromotionDeleted,
+	OrderCreated,
+	PaymentCreated, PaymentSettled, PaymentFailed,
+	ReturnCreated, ReturnCompleted, ReturnRejected,
+	CashSessionOpened, CashSessionCashMoved, CashSessionClosed,
+}
+
+// Types lists every event type emitted by the application
+func Types() []string {
+	return append([]string(nil), types...)
+}
+
+// IsKnown tells whether eventType is emitted by the application or is the wildcard
+func IsKnown(eventType string) bool {
+	if eventType == Wildcard {
+		return true
+	}
+	for _, known := range types {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches tells whether a subscription to subscribed receives eventType
+func Matches(subscribed []string, eventType string) bool {
+	for _, candidate := range subscribed {
+		if candidate == Wildcard || candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the envelope sent to the subscribers. Sequence is the order in which the event was
+// published, see domain.OutboxEvent.
+type Event struct {
+	ID         string          `json:"id"`
+	Sequence   uint64          `json:"sequence,omitempty"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func FromOutbox(outboxEvent domain.OutboxEvent) Event {
+	e := Event{
+		ID:         strconv.FormatUint(outboxEvent.ID, 10),
+		Type:       outboxEvent.Type,
+		OccurredAt: outboxEvent.OccurredAt.UTC(),
+		Data:       json.RawMessage(outboxEvent.Payload),
+	}
+	if outboxEvent.Sequence != nil {
+		e.Sequence = *outboxEvent.Sequence
+	}
+	return e
+}
+
+// Publisher records an event as part of the change made with ctx. When ctx carries a
+// transaction the event is only relayed if that transaction commits.
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{}) error
+}
@@ -0,0 +1,40 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository"
+)
+
+// OutboxPublisher writes events to the outbox table, the Relay delivers them afterwards
+type OutboxPublisher struct {
+	outbox repository.OutboxRepository
+	relay  *Relay
+}
+
+func NewOutboxPublisher(outbox repository.OutboxRepository, relay *Relay) Publisher {
+	return &OutboxPublisher{outbox: outbox, relay: relay}
+}
+
+func (publisher *OutboxPublisher) Publish(ctx context.Context, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = publisher.outbox.Save(ctx, domain.OutboxEvent{
+		Type:       eventType,
+		Payload:    string(payload),
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Relay right after the commit instead of waiting for the next poll
+	repository.AfterCommit(ctx, publisher.relay.Notify)
+	return nil
+}
@@ -0,0 +1,106 @@
+package event
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/repository"
+)
+
+// Handler receives a relayed event inside the transaction that marks it published, an error
+// rolls both back and the event is relayed again later
+type Handler func(ctx context.Context, event Event) error
+
+// Relay moves events from the outbox to the handlers. Several instances may run against the
+// same database, an event is handled by the one that marks it published.
+type Relay struct {
+	transactor repository.Transactor
+	outbox     repository.OutboxRepository
+	handlers   []Handler
+	interval   time.Duration
+	batchSize  int
+	wake       chan struct{}
+}
+
+func NewRelay(transactor repository.Transactor, outbox repository.OutboxRepository, interval time.Duration) *Relay {
+	return &Relay{
+		transactor: transactor,
+		outbox:     outbox,
+		interval:   interval,
+		batchSize:  100,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Handle registers handler, it must be called before Run
+func (relay *Relay) Handle(handler Handler) {
+	relay.handlers = append(relay.handlers, handler)
+}
+
+// Notify wakes the relay up without waiting for the next poll
+func (relay *Relay) Notify() {
+	select {
+	case relay.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunOnce relays one batch of events and returns how many were handled
+func (relay *Relay) RunOnce(ctx context.Context) (int, error) {
+	outboxEvents, err := relay.outbox.FindUnpublished(ctx, relay.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for _, outboxEvent := range outboxEvents {
+		published := false
+		err := relay.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			sequence, ok, err := relay.outbox.MarkPublished(ctx, outboxEvent.ID, time.Now().UTC())
+			if err != nil || !ok {
+				return err
+			}
+			published = true
+			outboxEvent.Sequence = &sequence
+			for _, handler := range relay.handlers {
+				if err := handler(ctx, FromOutbox(outboxEvent)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			// Keep the order, the next run starts again from this event
+			return handled, err
+		}
+		if published {
+			handled++
+		}
+	}
+	return handled, nil
+}
+
+// Run relays events until ctx is cancelled
+func (relay *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		handled, err := relay.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Outbox relay failed: %v", err)
+		}
+		if err == nil && handled == relay.batchSize {
+			// More events are probably waiting
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-relay.wake:
+		case <-ticker.C:
+		}
+	}
+}
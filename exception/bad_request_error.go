@@ -0,0 +1,13 @@
+package exception
+
+type BadRequestError struct {
+	Message string
+}
+
+func (e BadRequestError) Error() string {
+	return e.Message
+}
+
+func NewBadRequestError(message string) error {
+	return BadRequestError{Message: message}
+}
@@ -0,0 +1,17 @@
+package fixture
+
+import (
+	_ "embed"
+
+	"github.com/aronipurwanto/go-restful-api/helper"
+)
+
+//go:embed demo.yaml
+var demoYAML []byte
+
+// Demo is the built-in dataset of an Indonesian minimarket
+func Demo() Set {
+	set, err := Parse(demoYAML)
+	helper.PanicIfError(err)
+	return set
+}
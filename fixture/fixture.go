@@ -0,0 +1,102 @@
+package fixture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// Set is the content of a fixtures file. Rows are identified by their natural key: the name of
+// a category, the SKU of a product and the email of a customer or an employee. Products refer
+// to their category by name.
+type Set struct {
+	Categories []Category `yaml:"categories" json:"categories" validate:"dive"`
+	Products   []Product  `yaml:"products" json:"products" validate:"dive"`
+	Customers  []Customer `yaml:"customers" json:"customers" validate:"dive"`
+	Employees  []Employee `yaml:"employees" json:"employees" validate:"dive"`
+}
+
+type Category struct {
+	Name string `yaml:"name" json:"name" validate:"required,max=100"`
+}
+
+type Product struct {
+	SKU         string  `yaml:"sku" json:"sku" validate:"required,max=50"`
+	Name        string  `yaml:"name" json:"name" validate:"required,max=100"`
+	Description string  `yaml:"description" json:"description" validate:"max=500"`
+	Price       float64 `yaml:"price" json:"price" validate:"min=0"`
+	StockQty    int     `yaml:"stock_qty" json:"stock_qty" validate:"min=0"`
+	Category    string  `yaml:"category" json:"category" validate:"required"`
+	// TaxClass is the ID of a tax class, without it the product gets the default class of TaxRate
+	TaxClass string  `yaml:"tax_class" json:"tax_class" validate:"max=20"`
+	TaxRate  float64 `yaml:"tax_rate" json:"tax_rate" validate:"min=0"`
+}
+
+type Customer struct {
+	Email      string `yaml:"email" json:"email" validate:"required,email"`
+	Name       string `yaml:"name" json:"name" validate:"required,max=100"`
+	Phone      string `yaml:"phone" json:"phone" validate:"required,max=20"`
+	Address    string `yaml:"address" json:"address" validate:"max=255"`
+	LoyaltyPts int    `yaml:"loyalty_points" json:"loyalty_points" validate:"min=0"`
+}
+
+type Employee struct {
+	Email     string `yaml:"email" json:"email" validate:"required,email"`
+	Name      string `yaml:"name" json:"name" validate:"required,max=100"`
+	Role      string `yaml:"role" json:"role" validate:"required,max=50"`
+	Phone     string `yaml:"phone" json:"phone" validate:"required,max=20"`
+	DateHired string `yaml:"date_hired" json:"date_hired" validate:"required"`
+}
+
+// Parse reads a YAML or JSON fixtures document, unknown fields are rejected
+func Parse(data []byte) (Set, error) {
+	var set Set
+	// JSON is a subset of YAML
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&set); err != nil && !errors.Is(err, io.EOF) {
+		return Set{}, fmt.Errorf("fixture: %w", err)
+	}
+	return set, nil
+}
+
+// ReadFile parses the fixtures file at path
+func ReadFile(path string) (Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Set{}, err
+	}
+	return Parse(data)
+}
+
+// Validate checks every row and reports the natural keys defined twice. References to
+// categories are checked by the Loader, they may also point to existing rows.
+func (set Set) Validate(validate *validator.Validate) error {
+	if err := validate.Struct(set); err != nil {
+		return err
+	}
+
+	var errs []error
+	errs = append(errs, duplicates("category", set.Categories, func(category Category) string { return category.Name })...)
+	errs = append(errs, duplicates("product", set.Products, func(product Product) string { return product.SKU })...)
+	errs = append(errs, duplicates("customer", set.Customers, func(customer Customer) string { return customer.Email })...)
+	errs = append(errs, duplicates("employee", set.Employees, func(employee Employee) string { return employee.Email })...)
+	return errors.Join(errs...)
+}
+
+func duplicates[T any](kind string, rows []T, key func(T) string) []error {
+	var errs []error
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if seen[key(row)] {
+			errs = append(errs, fmt.Errorf("fixture: %s %q is defined twice", kind, key(row)))
+		}
+		seen[key(row)] = true
+	}
+	return errs
+}
@@ -0,0 +1,161 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupLoader(t *testing.T) (*Loader, *gorm.DB) {
+	dsn := fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"), time.Now().UnixNano())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&domain.Category{}, &domain.Product{}, &domain.Customer{}, &domain.Employee{}))
+	return NewLoader(db, validator.New()), db
+}
+
+func TestParse(t *testing.T) {
+	yamlSet, err := Parse([]byte(`
+categories:
+  - name: Minuman
+products:
+  - {sku: TEH-350, name: Teh Botol, price: 5000, stock_qty: 10, category: Minuman, tax_rate: 11}
+`))
+	require.NoError(t, err)
+
+	jsonSet, err := Parse([]byte(`{"categories": [{"name": "Minuman"}], "products": [
+		{"sku": "TEH-350", "name": "Teh Botol", "price": 5000, "stock_qty": 10, "category": "Minuman", "tax_rate": 11}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, yamlSet, jsonSet)
+
+	empty, err := Parse(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Set{}, empty)
+
+	_, err = Parse([]byte("products:\n  - {sku: A, harga: 5000}\n"))
+	assert.ErrorContains(t, err, "field harga not found")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		set         Set
+		expectError string
+	}{
+		{name: "demo", set: Demo()},
+		{
+			name:        "duplicate SKU",
+			set:         Set{Products: []Product{{SKU: "A", Name: "A", Category: "X"}, {SKU: "A", Name: "B", Category: "X"}}},
+			expectError: `product "A" is defined twice`,
+		},
+		{
+			name:        "invalid email",
+			set:         Set{Customers: []Customer{{Email: "bukan-email", Name: "Budi", Phone: "0812"}}},
+			expectError: "Email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.set.Validate(validator.New())
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestLoadIsIdempotent(t *testing.T) {
+	loader, db := setupLoader(t)
+	demo := Demo()
+
+	report, err := loader.Load(context.Background(), demo)
+	require.NoError(t, err)
+	assert.Equal(t, Counts{Created: len(demo.Products)}, report.Products)
+	assert.Equal(t, Counts{Created: len(demo.Customers)}, report.Customers)
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "sku = ?", "MNM-AIR-600").Error)
+
+	report, err = loader.Load(context.Background(), demo)
+	require.NoError(t, err)
+	assert.Equal(t, Report{
+		Categories: Counts{Unchanged: len(demo.Categories)},
+		Products:   Counts{Unchanged: len(demo.Products)},
+		Customers:  Counts{Unchanged: len(demo.Customers)},
+		Employees:  Counts{Unchanged: len(demo.Employees)},
+	}, report)
+
+	demo.Products[11].Price = 4000
+	report, err = loader.Load(context.Background(), demo)
+	require.NoError(t, err)
+	assert.Equal(t, Counts{Updated: 1, Unchanged: len(demo.Products) - 1}, report.Products)
+
+	var updated domain.Product
+	require.NoError(t, db.First(&updated, "sku = ?", "MNM-AIR-600").Error)
+	assert.Equal(t, product.ProductID, updated.ProductID)
+	assert.Equal(t, money.IDR(4000), updated.Price)
+
+	var count int64
+	require.NoError(t, db.Model(&domain.Product{}).Count(&count).Error)
+	assert.Equal(t, int64(len(demo.Products)), count)
+}
+
+func TestLoadCategoryReferences(t *testing.T) {
+	loader, db := setupLoader(t)
+	require.NoError(t, db.Create(&domain.Category{Name: "Minuman"}).Error)
+
+	// The category already exists in the database
+	_, err := loader.Load(context.Background(), Set{Products: []Product{{SKU: "TEH-350", Name: "Teh Botol", Category: "Minuman"}}})
+	require.NoError(t, err)
+
+	_, err = loader.Load(context.Background(), Set{
+		Customers: []Customer{{Email: "budi@example.com", Name: "Budi", Phone: "0812"}},
+		Products:  []Product{{SKU: "KRP-01", Name: "Keripik", Category: "Camilan"}},
+	})
+	assert.ErrorContains(t, err, `unknown categories ["Camilan"]`)
+
+	// Nothing of a rejected set is written
+	var count int64
+	require.NoError(t, db.Model(&domain.Customer{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestGenerate(t *testing.T) {
+	sizes := Sizes{Products: 2000, Customers: 1500, Employees: 30}
+	set := Generate(sizes, 42)
+	assert.Len(t, set.Products, sizes.Products)
+	assert.Len(t, set.Customers, sizes.Customers)
+	assert.Len(t, set.Employees, sizes.Employees)
+	assert.Equal(t, set, Generate(sizes, 42))
+	assert.NotEqual(t, set.Customers, Generate(sizes, 7).Customers)
+	require.NoError(t, set.Validate(validator.New()))
+
+	loader, _ := setupLoader(t)
+	report, err := loader.Load(context.Background(), set)
+	require.NoError(t, err)
+	assert.Equal(t, sizes.Products, report.Products.Created)
+
+	report, err = loader.Load(context.Background(), set)
+	require.NoError(t, err)
+	assert.Equal(t, Counts{Unchanged: sizes.Customers}, report.Customers)
+}
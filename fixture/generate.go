@@ -0,0 +1,92 @@
+package fixture
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Sizes is the number of rows created by Generate
+type Sizes struct {
+	Products  int
+	Customers int
+	Employees int
+}
+
+var (
+	firstNames = []string{"Budi", "Sari", "Agus", "Rina", "Dedi", "Putu", "Fajar", "Siti", "Andi", "Maria", "Yusuf", "Dewi",
+		"Eko", "Wati", "Joko", "Ratna", "Hendra", "Indah", "Bambang", "Lina", "Rizky", "Ayu", "Teguh", "Nanda"}
+	lastNames = []string{"Santoso", "Wijaya", "Pratama", "Lestari", "Kurniawan", "Saputra", "Hidayat", "Nugroho", "Rahmawati",
+		"Siregar", "Simanjuntak", "Setiawan", "Gunawan", "Susanto", "Halim", "Purnomo", "Wibowo", "Hasibuan"}
+	cities  = []string{"Jakarta", "Bandung", "Surabaya", "Medan", "Semarang", "Makassar", "Yogyakarta", "Denpasar", "Palembang", "Balikpapan"}
+	streets = []string{"Jl. Sudirman", "Jl. Ahmad Yani", "Jl. Diponegoro", "Jl. Gatot Subroto", "Jl. Merdeka", "Jl. Pahlawan", "Jl. Kartini", "Jl. Imam Bonjol"}
+	roles   = []string{"cashier", "cashier", "cashier", "stock keeper", "stock keeper", "supervisor", "manager"}
+
+	productNames = map[string][]string{
+		"Sembako":          {"Beras", "Minyak Goreng", "Gula Pasir", "Tepung Terigu", "Telur Ayam"},
+		"Makanan Ringan":   {"Keripik Singkong", "Keripik Kentang", "Wafer", "Biskuit", "Kacang Atom"},
+		"Minuman":          {"Air Mineral", "Teh Botol", "Kopi Susu", "Jus Jeruk", "Minuman Isotonik"},
+		"Mi Instan":        {"Mi Goreng", "Mi Kuah Soto", "Mi Kuah Kari", "Bihun Instan"},
+		"Susu & Olahan":    {"Susu UHT", "Susu Kental Manis", "Keju", "Yoghurt"},
+		"Bumbu Dapur":      {"Kecap Manis", "Saus Sambal", "Bumbu Instan", "Kaldu Bubuk"},
+		"Perawatan Diri":   {"Sabun Mandi", "Sampo", "Pasta Gigi", "Deodoran"},
+		"Kebersihan Rumah": {"Deterjen", "Sabun Cuci Piring", "Pewangi Pakaian", "Pembersih Lantai"},
+		"Bayi & Anak":      {"Popok Bayi", "Bubur Bayi", "Tisu Basah"},
+		"Rokok & Korek":    {"Korek Api"},
+	}
+	variants = []string{"Original", "Pedas", "Jeruk", "Stroberi", "Cokelat", "Vanila", "Melati", "Lemon", "Ekstra"}
+	packs    = []string{"50 g", "100 g", "250 g", "500 g", "1 kg", "250 ml", "600 ml", "1 L", "isi 5", "isi 10"}
+)
+
+// Generate creates random but realistic looking rows for performance tests, on top of the
+// categories of Demo. The same seed gives the same set, so loading it again changes nothing.
+func Generate(sizes Sizes, seed int64) Set {
+	random := rand.New(rand.NewSource(seed))
+	pick := func(values []string) string {
+		return values[random.Intn(len(values))]
+	}
+
+	var set Set
+	set.Categories = Demo().Categories
+	for i := 1; i <= sizes.Products; i++ {
+		category := set.Categories[random.Intn(len(set.Categories))].Name
+		taxRate := 11.0
+		if category == "Sembako" {
+			taxRate = 0
+		}
+		set.Products = append(set.Products, Product{
+			SKU:         fmt.Sprintf("GEN-%07d", i),
+			Name:        fmt.Sprintf("%s %s %s", pick(productNames[category]), pick(variants), pick(packs)),
+			Description: "Produk hasil generator untuk uji performa",
+			Price:       float64(random.Intn(1000)+2) * 500,
+			StockQty:    random.Intn(500),
+			Category:    category,
+			TaxRate:     taxRate,
+		})
+	}
+
+	for i := 1; i <= sizes.Customers; i++ {
+		first, last := pick(firstNames), pick(lastNames)
+		set.Customers = append(set.Customers, Customer{
+			Email:      fmt.Sprintf("%s.%s.%07d@example.com", strings.ToLower(first), strings.ToLower(last), i),
+			Name:       first + " " + last,
+			Phone:      fmt.Sprintf("08%d%09d", 11+random.Intn(89), random.Intn(1000000000)),
+			Address:    fmt.Sprintf("%s No. %d, %s", pick(streets), random.Intn(200)+1, pick(cities)),
+			LoyaltyPts: random.Intn(1000),
+		})
+	}
+
+	firstHire := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= sizes.Employees; i++ {
+		first, last := pick(firstNames), pick(lastNames)
+		set.Employees = append(set.Employees, Employee{
+			Email:     fmt.Sprintf("%s.%s.%07d@tokomaju.co.id", strings.ToLower(first), strings.ToLower(last), i),
+			Name:      first + " " + last,
+			Role:      pick(roles),
+			Phone:     fmt.Sprintf("08%d%09d", 11+random.Intn(89), random.Intn(1000000000)),
+			DateHired: firstHire.AddDate(0, 0, random.Intn(3650)).Format("2006-01-02"),
+		})
+	}
+	return set
+}
@@ -0,0 +1,300 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// batchSize bounds the number of keys per lookup query and of rows per insert
+const batchSize = 500
+
+// Counts is the outcome of a load for one kind of rows
+type Counts struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// Report is the outcome of Loader.Load
+type Report struct {
+	Categories Counts
+	Products   Counts
+	Customers  Counts
+	Employees  Counts
+}
+
+func (report Report) String() string {
+	var lines []string
+	for _, kind := range []struct {
+		name   string
+		counts Counts
+	}{
+		{"categories", report.Categories},
+		{"products", report.Products},
+		{"customers", report.Customers},
+		{"employees", report.Employees},
+	} {
+		lines = append(lines, fmt.Sprintf("%-10s %d created, %d updated, %d unchanged", kind.name, kind.counts.Created, kind.counts.Updated, kind.counts.Unchanged))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Loader upserts fixtures by natural key, loading the same set twice changes nothing. Rows are
+// written directly with GORM in one transaction: no outbox events are published and the caches
+// of running servers are only refreshed once CACHE_TTL expires.
+type Loader struct {
+	DB       *gorm.DB
+	Validate *validator.Validate
+}
+
+func NewLoader(db *gorm.DB, validate *validator.Validate) *Loader {
+	return &Loader{DB: db, Validate: validate}
+}
+
+// Load validates set and upserts its rows, categories first so that products can refer to them
+func (loader *Loader) Load(ctx context.Context, set Set) (Report, error) {
+	if err := set.Validate(loader.Validate); err != nil {
+		return Report{}, err
+	}
+
+	var report Report
+	err := loader.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if report.Categories, err = upsertCategories(tx, set.Categories); err != nil {
+			return err
+		}
+		if err = checkCategories(tx, set.Products); err != nil {
+			return err
+		}
+		if report.Products, err = upsert(tx, "sku", toProducts(set.Products), productKey, updateProduct); err != nil {
+			return err
+		}
+		if report.Customers, err = upsert(tx, "email", toCustomers(set.Customers), customerKey, updateCustomer); err != nil {
+			return err
+		}
+		report.Employees, err = upsert(tx, "email", toEmployees(set.Employees), employeeKey, updateEmployee)
+		return err
+	})
+	return report, err
+}
+
+func upsertCategories(tx *gorm.DB, fixtures []Category) (Counts, error) {
+	categories := make([]domain.Category, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		categories = append(categories, domain.Category{Name: fixture.Name})
+	}
+	// A category has nothing but its name, an existing one is always unchanged
+	return upsert(tx, "name", categories, func(category domain.Category) string { return category.Name },
+		func(existing *domain.Category, fixture domain.Category) bool { return false })
+}
+
+// checkCategories reports the products whose category is neither in the set nor in the database
+func checkCategories(tx *gorm.DB, products []Product) error {
+	names := map[string]bool{}
+	for _, product := range products {
+		names[product.Category] = true
+	}
+
+	var missing []string
+	for _, batch := range chunks(keys(names), batchSize) {
+		var found []string
+		if err := tx.Model(&domain.Category{}).Where("name IN ?", batch).Pluck("name", &found).Error; err != nil {
+			return err
+		}
+		for _, name := range found {
+			delete(names, name)
+		}
+	}
+	for name := range names {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		return fmt.Errorf("fixture: unknown categories %q, define them under categories", missing)
+	}
+	return nil
+}
+
+// upsert creates the rows whose natural key is not in the table yet and applies the fixture to
+// the other ones, update tells whether it changed the existing row
+func upsert[T any](tx *gorm.DB, column string, rows []T, key func(T) string, update func(existing *T, fixture T) bool) (Counts, error) {
+	var counts Counts
+	if len(rows) == 0 {
+		return counts, nil
+	}
+
+	existing := map[string]T{}
+	for _, batch := range chunks(mapKeys(rows, key), batchSize) {
+		var found []T
+		if err := tx.Where(column+" IN ?", batch).Find(&found).Error; err != nil {
+			return counts, err
+		}
+		for _, row := range found {
+			existing[key(row)] = row
+		}
+	}
+
+	var created []T
+	for _, row := range rows {
+		current, ok := existing[key(row)]
+		if !ok {
+			created = append(created, row)
+			continue
+		}
+		if !update(&current, row) {
+			counts.Unchanged++
+			continue
+		}
+		if err := tx.Save(&current).Error; err != nil {
+			return counts, err
+		}
+		counts.Updated++
+	}
+
+	if len(created) > 0 {
+		if err := tx.CreateInBatches(&created, batchSize).Error; err != nil {
+			return counts, err
+		}
+	}
+	counts.Created = len(created)
+	return counts, nil
+}
+
+func toProducts(fixtures []Product) []domain.Product {
+	products := make([]domain.Product, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		products = append(products, domain.Product{
+			Name:        fixture.Name,
+			Description: fixture.Description,
+			Price:       money.FromFloat(fixture.Price, money.DefaultCurrency),
+			Currency:    money.DefaultCurrency,
+			StockQty:    fixture.StockQty,
+			Category:    fixture.Category,
+			SKU:         fixture.SKU,
+			TaxClassID:  fixture.TaxClass,
+			TaxRate:     fixture.TaxRate,
+		})
+		setTaxClass(&products[len(products)-1])
+	}
+	return products
+}
+
+// setTaxClass completes the tax class or the tax rate of a product from the default tax classes.
+// The rates no default class has are left to the migration, which creates a class for them.
+func setTaxClass(product *domain.Product) {
+	for _, class := range tax.DefaultClasses() {
+		if product.TaxClassID == class.TaxClassID || (product.TaxClassID == "" && product.TaxRate == class.Percent()) {
+			product.TaxClassID = class.TaxClassID
+			product.TaxRate = class.Percent()
+			return
+		}
+	}
+}
+
+func productKey(product domain.Product) string {
+	return product.SKU
+}
+
+func updateProduct(existing *domain.Product, fixture domain.Product) bool {
+	// Fixtures do not define variants, those made through the API are kept with their stock
+	fixture.ProductID = existing.ProductID
+	fixture.Options = existing.Options
+	fixture.Variants = existing.Variants
+	if len(existing.Variants) > 0 {
+		fixture.StockQty = existing.StockQty
+	}
+	if reflect.DeepEqual(*existing, fixture) {
+		return false
+	}
+	*existing = fixture
+	return true
+}
+
+func toCustomers(fixtures []Customer) []domain.Customer {
+	customers := make([]domain.Customer, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		customers = append(customers, domain.Customer{
+			Name:       fixture.Name,
+			Email:      fixture.Email,
+			Phone:      fixture.Phone,
+			Address:    fixture.Address,
+			LoyaltyPts: fixture.LoyaltyPts,
+		})
+	}
+	return customers
+}
+
+func customerKey(customer domain.Customer) string {
+	return customer.Email
+}
+
+func updateCustomer(existing *domain.Customer, fixture domain.Customer) bool {
+	fixture.CustomerID = existing.CustomerID
+	if *existing == fixture {
+		return false
+	}
+	*existing = fixture
+	return true
+}
+
+func toEmployees(fixtures []Employee) []domain.Employee {
+	employees := make([]domain.Employee, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		employees = append(employees, domain.Employee{
+			Name:      fixture.Name,
+			Role:      fixture.Role,
+			Email:     fixture.Email,
+			Phone:     fixture.Phone,
+			DateHired: fixture.DateHired,
+		})
+	}
+	return employees
+}
+
+func employeeKey(employee domain.Employee) string {
+	return employee.Email
+}
+
+func updateEmployee(existing *domain.Employee, fixture domain.Employee) bool {
+	// Fixtures do not assign stores, the assignments made through the API are kept
+	fixture.EmployeeID = existing.EmployeeID
+	fixture.Stores = existing.Stores
+	if reflect.DeepEqual(*existing, fixture) {
+		return false
+	}
+	*existing = fixture
+	return true
+}
+
+func mapKeys[T any](rows []T, key func(T) string) []string {
+	result := make([]string, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, key(row))
+	}
+	return result
+}
+
+func keys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	return result
+}
+
+func chunks(values []string, size int) [][]string {
+	var result [][]string
+	for start := 0; start < len(values); start += size {
+		result = append(result, values[start:min(start+size, len(values))])
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package gql
+
+import (
+	"errors"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/go-playground/validator/v10"
+)
+
+// Error codes reported in the extensions of the GraphQL errors
+const (
+	CodeBadRequest = "BAD_REQUEST"
+	CodeForbidden  = "FORBIDDEN"
+	CodeNotFound   = "NOT_FOUND"
+	CodeInternal   = "INTERNAL"
+)
+
+// resolverError carries the code of a service error to the client, like the HTTP status of the REST API
+type resolverError struct {
+	err  error
+	code string
+}
+
+func (e resolverError) Error() string {
+	return e.err.Error()
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// toResolverError maps a service error the same way the REST controllers do
+func toResolverError(err error) error {
+	var notFoundError exception.NotFoundError
+	var badRequestError exception.BadRequestError
+	var forbiddenError exception.ForbiddenError
+	var validationErrors validator.ValidationErrors
+
+	switch {
+	case errors.As(err, &notFoundError):
+		return resolverError{err: err, code: CodeNotFound}
+	case errors.As(err, &badRequestError), errors.As(err, &validationErrors):
+		return resolverError{err: err, code: CodeBadRequest}
+	case errors.As(err, &forbiddenError):
+		return resolverError{err: err, code: CodeForbidden}
+	default:
+		return resolverError{err: err, code: CodeInternal}
+	}
+}
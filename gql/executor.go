@@ -0,0 +1,97 @@
+package gql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// GraphQLRequest is the JSON body of a GraphQL request
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is the JSON body of a GraphQL response, data is absent when the request was rejected
+// before execution
+type GraphQLResponse struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// Executor runs requests against the schema, see NewSchema
+type Executor struct {
+	schema   graphql.Schema
+	services Services
+	limits   Limits
+}
+
+func NewExecutor(services Services, limits Limits) (*Executor, error) {
+	schema, err := NewSchema(services)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, services: services, limits: limits}, nil
+}
+
+// Execute parses, validates and checks the limits of the request before running it, in the
+// store context of ctx if any, see WithStore. The returned flag is false when the request was
+// rejected without being executed.
+func (executor *Executor) Execute(ctx context.Context, request GraphQLRequest) (GraphQLResponse, bool) {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return GraphQLResponse{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+
+	validation := graphql.ValidateDocument(&executor.schema, document, nil)
+	if !validation.IsValid {
+		return GraphQLResponse{Errors: validation.Errors}, false
+	}
+
+	operation, err := selectOperation(document, request.OperationName)
+	if err != nil {
+		return GraphQLResponse{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+	if err := executor.limits.check(executor.schema, document, operation); err != nil {
+		return GraphQLResponse{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        executor.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       context.WithValue(ctx, loadersKey{}, newLoaders(executor.services, storeFrom(ctx))),
+	})
+	return GraphQLResponse{Data: result.Data, Errors: result.Errors}, true
+}
+
+func selectOperation(document *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var selected *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if selected != nil {
+				return nil, errors.New("operationName is required when the document has several operations")
+			}
+			selected = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			selected = operation
+		}
+	}
+	if selected == nil {
+		return nil, errors.New("unknown operation " + name)
+	}
+	return selected, nil
+}
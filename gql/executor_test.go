@@ -0,0 +1,181 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServices struct {
+	category *mocks.MockCategoryService
+	customer *mocks.MockCustomerService
+	employee *mocks.MockEmployeeService
+	product  *mocks.MockProductService
+	store    *mocks.MockStoreService
+}
+
+func setupExecutor(t *testing.T, limits Limits) (*Executor, testServices) {
+	ctrl := gomock.NewController(t)
+	services := testServices{
+		category: mocks.NewMockCategoryService(ctrl),
+		customer: mocks.NewMockCustomerService(ctrl),
+		employee: mocks.NewMockEmployeeService(ctrl),
+		product:  mocks.NewMockProductService(ctrl),
+		store:    mocks.NewMockStoreService(ctrl),
+	}
+	executor, err := NewExecutor(Services{
+		Category: services.category,
+		Customer: services.customer,
+		Employee: services.employee,
+		Product:  services.product,
+		Store:    services.store,
+	}, limits)
+	require.NoError(t, err)
+	return executor, services
+}
+
+func dataJSON(t *testing.T, response GraphQLResponse) string {
+	encoded, err := json.Marshal(response.Data)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestRelatedObjectsAreBatched(t *testing.T) {
+	executor, services := setupExecutor(t, Limits{})
+
+	services.product.EXPECT().FindAll(gomock.Any()).Return([]web.ProductResponse{
+		{ProductID: "P1", Name: "Laptop", Category: "Electronics"},
+		{ProductID: "P2", Name: "Kopi", Category: "Food"},
+		{ProductID: "P3", Name: "Mouse", Category: "Electronics"},
+		{ProductID: "P4", Name: "Lama", Category: "Dihapus"},
+	}, nil)
+	// One query for every category of the list, each name once
+	services.category.EXPECT().FindByNames(gomock.Any(), []string{"Electronics", "Food", "Dihapus"}).Return([]web.CategoryResponse{
+		{Id: 1, Name: "Electronics"}, {Id: 2, Name: "Food"},
+	}, nil).Times(1)
+	services.product.EXPECT().FindByCategories(gomock.Any(), []string{"Electronics", "Food"}).Return([]web.ProductResponse{
+		{ProductID: "P1", Category: "Electronics"}, {ProductID: "P2", Category: "Food"}, {ProductID: "P3", Category: "Electronics"},
+	}, nil).Times(1)
+
+	response, executed := executor.Execute(context.Background(), GraphQLRequest{
+		Query: `{ products { product_id category_name category { id products { product_id } } } }`,
+	})
+	require.True(t, executed)
+	require.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"products": [
+		{"product_id": "P1", "category_name": "Electronics", "category": {"id": 1, "products": [{"product_id": "P1"}, {"product_id": "P3"}]}},
+		{"product_id": "P2", "category_name": "Food", "category": {"id": 2, "products": [{"product_id": "P2"}]}},
+		{"product_id": "P3", "category_name": "Electronics", "category": {"id": 1, "products": [{"product_id": "P1"}, {"product_id": "P3"}]}},
+		{"product_id": "P4", "category_name": "Dihapus", "category": null}
+	]}`, dataJSON(t, response))
+}
+
+func TestStoreContext(t *testing.T) {
+	executor, services := setupExecutor(t, Limits{})
+	ctx := WithStore(context.Background(), "JKT01")
+
+	services.store.EXPECT().FindProducts(gomock.Any(), "JKT01", nil).Return([]web.ProductResponse{
+		{ProductID: "P1", Name: "Laptop", Category: "Electronics", StockQty: 2},
+		{ProductID: "P2", Name: "Kopi", Category: "Food", StockQty: 7},
+	}, nil).Times(2)
+	services.category.EXPECT().FindByNames(gomock.Any(), []string{"Electronics", "Food"}).Return([]web.CategoryResponse{
+		{Id: 1, Name: "Electronics"}, {Id: 2, Name: "Food"},
+	}, nil)
+	response, _ := executor.Execute(ctx, GraphQLRequest{
+		Query: `{ products { product_id stock_qty category { products { product_id stock_qty } } } }`,
+	})
+	require.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"products": [
+		{"product_id": "P1", "stock_qty": 2, "category": {"products": [{"product_id": "P1", "stock_qty": 2}]}},
+		{"product_id": "P2", "stock_qty": 7, "category": {"products": [{"product_id": "P2", "stock_qty": 7}]}}
+	]}`, dataJSON(t, response))
+
+	services.store.EXPECT().FindEmployee(gomock.Any(), "JKT01", "E2").Return(web.EmployeeResponse{}, exception.NewNotFoundError("Employee not found"))
+	response, _ = executor.Execute(ctx, GraphQLRequest{Query: `{ employee(id: "E2") { employee_id } }`})
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, CodeNotFound, response.Errors[0].Extensions["code"])
+}
+
+func TestMutationsGoThroughTheServices(t *testing.T) {
+	executor, services := setupExecutor(t, Limits{})
+
+	services.customer.EXPECT().Update(gomock.Any(), web.CustomerUpdateRequest{CustomerID: "C1", Name: "Budi", Email: "budi@example.com", Phone: "0812", LoyaltyPts: 5}).
+		Return(web.CustomerResponse{CustomerID: "C1", Name: "Budi", Email: "budi@example.com", Phone: "0812", LoyaltyPts: 5}, nil)
+	response, _ := executor.Execute(context.Background(), GraphQLRequest{
+		Query:     `mutation ($input: CustomerInput!) { updateCustomer(id: "C1", input: $input) { customer_id loyalty_points } }`,
+		Variables: map[string]interface{}{"input": map[string]interface{}{"name": "Budi", "email": "budi@example.com", "phone": "0812", "loyalty_points": 5}},
+	})
+	require.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"updateCustomer": {"customer_id": "C1", "loyalty_points": 5}}`, dataJSON(t, response))
+
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{name: "not found", err: exception.NewNotFoundError("employee not found"), code: CodeNotFound},
+		{name: "invalid input", err: exception.NewBadRequestError("invalid date"), code: CodeBadRequest},
+		{name: "failure", err: errors.New("database error"), code: CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services.employee.EXPECT().Delete(gomock.Any(), "E1").Return(tt.err)
+			response, executed := executor.Execute(context.Background(), GraphQLRequest{Query: `mutation { deleteEmployee(id: "E1") }`})
+			assert.True(t, executed)
+			require.Len(t, response.Errors, 1)
+			assert.Equal(t, tt.err.Error(), response.Errors[0].Message)
+			assert.Equal(t, tt.code, response.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestRejectedRequests(t *testing.T) {
+	executor, _ := setupExecutor(t, Limits{MaxDepth: 3, MaxComplexity: 50, ListFactor: 10})
+
+	tests := []struct {
+		name    string
+		request GraphQLRequest
+		message string
+	}{
+		{name: "syntax error", request: GraphQLRequest{Query: `{ products {`}, message: "Syntax Error"},
+		{name: "unknown field", request: GraphQLRequest{Query: `{ products { harga } }`}, message: `Cannot query field "harga"`},
+		{name: "too deep", request: GraphQLRequest{Query: `{ products { category { products { name } } } }`}, message: "query depth 4 exceeds the limit of 3"},
+		{
+			name:    "too deep through a fragment",
+			request: GraphQLRequest{Query: `{ categories { ...withProducts } } fragment withProducts on Category { products { category { name } } }`},
+			message: "query depth 4 exceeds the limit of 3",
+		},
+		// 1 + 10 * (1 + 1 + 10 * 1)
+		{name: "too complex", request: GraphQLRequest{Query: `{ categories { name products { name } } }`}, message: "query complexity 121 exceeds the limit of 50"},
+		{name: "ambiguous operation", request: GraphQLRequest{Query: `query A { categories { name } } query B { customers { name } }`}, message: "operationName is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, executed := executor.Execute(context.Background(), tt.request)
+			assert.False(t, executed)
+			assert.Nil(t, response.Data)
+			require.NotEmpty(t, response.Errors)
+			assert.Contains(t, response.Errors[0].Message, tt.message)
+		})
+	}
+}
+
+func TestIntrospectionIsNotLimited(t *testing.T) {
+	executor, _ := setupExecutor(t, Limits{MaxDepth: 2, MaxComplexity: 5, ListFactor: 10})
+
+	response, executed := executor.Execute(context.Background(), GraphQLRequest{
+		Query: `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`,
+	})
+	assert.True(t, executed)
+	assert.Empty(t, response.Errors)
+	assert.Contains(t, dataJSON(t, response), `"name":"Product"`)
+}
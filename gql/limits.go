@@ -0,0 +1,117 @@
+package gql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the cost of a query before it is executed
+type Limits struct {
+	// MaxDepth is the deepest allowed field nesting, e.g. 3 for products { category { name } }
+	MaxDepth int
+	// MaxComplexity bounds the estimated number of resolved fields, every field counts 1 and
+	// the fields below a list count ListFactor times
+	MaxComplexity int
+	ListFactor    int
+}
+
+// cost is the depth and complexity of a selection set
+type cost struct {
+	depth      int
+	complexity int
+}
+
+// check measures operation and reports the limit it exceeds. Introspection fields are left out,
+// their size is bounded by the schema rather than by the data.
+func (limits Limits) check(schema graphql.Schema, document *ast.Document, operation *ast.OperationDefinition) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	measured := limits.measure(schema, fragments, root, operation.SelectionSet)
+	if limits.MaxDepth > 0 && measured.depth > limits.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", measured.depth, limits.MaxDepth)
+	}
+	if limits.MaxComplexity > 0 && measured.complexity > limits.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", measured.complexity, limits.MaxComplexity)
+	}
+	return nil
+}
+
+func (limits Limits) measure(schema graphql.Schema, fragments map[string]*ast.FragmentDefinition, parent graphql.Type, selectionSet *ast.SelectionSet) cost {
+	var total cost
+	if selectionSet == nil {
+		return total
+	}
+
+	for _, selection := range selectionSet.Selections {
+		var measured cost
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			fieldType, isList := fieldType(parent, selection.Name.Value)
+			children := limits.measure(schema, fragments, fieldType, selection.SelectionSet)
+			factor := 1
+			if isList && limits.ListFactor > 0 {
+				factor = limits.ListFactor
+			}
+			measured = cost{depth: children.depth + 1, complexity: 1 + children.complexity*factor}
+		case *ast.InlineFragment:
+			measured = limits.measure(schema, fragments, conditionType(schema, selection.TypeCondition, parent), selection.SelectionSet)
+		case *ast.FragmentSpread:
+			// Fragment cycles are rejected by the validation that runs first
+			if fragment, ok := fragments[selection.Name.Value]; ok {
+				measured = limits.measure(schema, fragments, conditionType(schema, fragment.TypeCondition, parent), fragment.SelectionSet)
+			}
+		}
+		total.depth = max(total.depth, measured.depth)
+		total.complexity += measured.complexity
+	}
+	return total
+}
+
+// fieldType returns the named type of a field and whether it is a list
+func fieldType(parent graphql.Type, name string) (graphql.Type, bool) {
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil, false
+	}
+	field, ok := object.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+
+	isList := false
+	t := field.Type
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			isList = true
+			t = wrapped.OfType
+		default:
+			return t, isList
+		}
+	}
+}
+
+func conditionType(schema graphql.Schema, condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	return schema.Type(condition.Name.Value)
+}
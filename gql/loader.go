@@ -0,0 +1,64 @@
+package gql
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc loads every key of a batch at once; keys missing from the result resolve to the zero value
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys requested by the resolvers of one query level and loads them with a
+// single BatchFunc call when the first result is needed, in the manner of DataLoader. A loader
+// also caches its results, it lives for one request only.
+type Loader[K comparable, V any] struct {
+	batch   BatchFunc[K, V]
+	mu      sync.Mutex
+	pending []K
+	results map[K]*loaded[V]
+}
+
+type loaded[V any] struct {
+	value V
+	err   error
+}
+
+func NewLoader[K comparable, V any](batch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{batch: batch, results: map[K]*loaded[V]{}}
+}
+
+// Load queues key and returns a thunk resolving it, the executor calls the thunks once every
+// field of the level has been resolved
+func (loader *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	loader.mu.Lock()
+	result, ok := loader.results[key]
+	if !ok {
+		result = &loaded[V]{}
+		loader.results[key] = result
+		loader.pending = append(loader.pending, key)
+	}
+	loader.mu.Unlock()
+
+	return func() (V, error) {
+		loader.dispatch(ctx)
+		return result.value, result.err
+	}
+}
+
+// dispatch loads the pending keys, the lock is held meanwhile so that a concurrent thunk waits
+// for the batch holding its key
+func (loader *Loader[K, V]) dispatch(ctx context.Context) {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+	if len(loader.pending) == 0 {
+		return
+	}
+
+	keys := loader.pending
+	loader.pending = nil
+	values, err := loader.batch(ctx, keys)
+	for _, key := range keys {
+		loader.results[key].value = values[key]
+		loader.results[key].err = err
+	}
+}
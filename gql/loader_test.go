@@ -0,0 +1,62 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoaderBatchesAndCaches(t *testing.T) {
+	var batches [][]string
+	loader := NewLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		batches = append(batches, keys)
+		values := map[string]int{}
+		for _, key := range keys {
+			if key != "missing" {
+				values[key] = len(key)
+			}
+		}
+		return values, nil
+	})
+	ctx := context.Background()
+
+	first := loader.Load(ctx, "kopi")
+	second := loader.Load(ctx, "laptop")
+	duplicate := loader.Load(ctx, "kopi")
+	missing := loader.Load(ctx, "missing")
+
+	value, err := second()
+	assert.NoError(t, err)
+	assert.Equal(t, 6, value)
+	value, _ = first()
+	assert.Equal(t, 4, value)
+	value, _ = duplicate()
+	assert.Equal(t, 4, value)
+	value, _ = missing()
+	assert.Equal(t, 0, value)
+	assert.Equal(t, [][]string{{"kopi", "laptop", "missing"}}, batches)
+
+	// Known keys come from the cache, new ones form the next batch
+	cached := loader.Load(ctx, "laptop")
+	next := loader.Load(ctx, "teh")
+	value, _ = cached()
+	assert.Equal(t, 6, value)
+	value, _ = next()
+	assert.Equal(t, 3, value)
+	assert.Equal(t, [][]string{{"kopi", "laptop", "missing"}, {"teh"}}, batches)
+}
+
+func TestLoaderError(t *testing.T) {
+	loader := NewLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		return nil, errors.New("database error")
+	})
+	first := loader.Load(context.Background(), "a")
+	second := loader.Load(context.Background(), "b")
+
+	_, err := first()
+	assert.EqualError(t, err, "database error")
+	_, err = second()
+	assert.EqualError(t, err, "database error")
+}
@@ -0,0 +1,451 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/graphql-go/graphql"
+)
+
+// Services are the application services behind the resolvers, so that GraphQL requests go
+// through the same validation and events as the REST API
+type Services struct {
+	Category service.CategoryService
+	Customer service.CustomerService
+	Employee service.EmployeeService
+	Product  service.ProductService
+	// Store answers the reads of products and employees in a store context, see WithStore
+	Store service.StoreService
+}
+
+type storeKey struct{}
+
+// WithStore sets the store context of a request, resolved and granted like that of the REST
+// API. Products then carry the stock and the price of the store, and only its employees are
+// found.
+func WithStore(ctx context.Context, storeId string) context.Context {
+	return context.WithValue(ctx, storeKey{}, storeId)
+}
+
+func storeFrom(ctx context.Context) string {
+	storeId, _ := ctx.Value(storeKey{}).(string)
+	return storeId
+}
+
+// loaders batch the lookups of related objects, see Loader
+type loaders struct {
+	categoryByName     *Loader[string, *web.CategoryResponse]
+	productsByCategory *Loader[string, []web.ProductResponse]
+}
+
+type loadersKey struct{}
+
+func newLoaders(services Services, storeId string) *loaders {
+	return &loaders{
+		categoryByName: NewLoader(func(ctx context.Context, names []string) (map[string]*web.CategoryResponse, error) {
+			categories, err := services.Category.FindByNames(ctx, names)
+			if err != nil {
+				return nil, err
+			}
+			byName := map[string]*web.CategoryResponse{}
+			for i := range categories {
+				byName[categories[i].Name] = &categories[i]
+			}
+			return byName, nil
+		}),
+		productsByCategory: NewLoader(func(ctx context.Context, names []string) (map[string][]web.ProductResponse, error) {
+			var products []web.ProductResponse
+			var err error
+			if storeId != "" {
+				products, err = services.Store.FindProducts(ctx, storeId, nil)
+			} else {
+				products, err = services.Product.FindByCategories(ctx, names)
+			}
+			if err != nil {
+				return nil, err
+			}
+			requested := map[string]bool{}
+			for _, name := range names {
+				requested[name] = true
+			}
+			byCategory := map[string][]web.ProductResponse{}
+			for _, product := range products {
+				if requested[product.Category] {
+					byCategory[product.Category] = append(byCategory[product.Category], product)
+				}
+			}
+			return byCategory, nil
+		}),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// nonNull shortens graphql.NewNonNull in the field tables below
+func nonNull(t graphql.Type) graphql.Type {
+	return graphql.NewNonNull(t)
+}
+
+// listOf is a non null list of non null items
+func listOf(t graphql.Type) graphql.Type {
+	return nonNull(graphql.NewList(nonNull(t)))
+}
+
+// decodeInput converts an input object argument to its web request, the field names of the
+// input types are the JSON names of the requests
+func decodeInput(arg interface{}, request interface{}) error {
+	encoded, err := json.Marshal(arg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, request)
+}
+
+// productInputOf gives the price_amount of a ProductInput, when set, as the price of the request
+// it decodes to: the decimal string is read exactly where the Float of price is not
+func productInputOf(arg interface{}) interface{} {
+	input, ok := arg.(map[string]interface{})
+	if !ok || input["price_amount"] == nil {
+		return arg
+	}
+	request := make(map[string]interface{}, len(input))
+	for name, value := range input {
+		request[name] = value
+	}
+	request["price"] = input["price_amount"]
+	delete(request, "price_amount")
+	return request
+}
+
+// resolved maps the error of a service call, see toResolverError
+func resolved(value interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return value, nil
+}
+
+// NewSchema builds the schema of categories, customers, employees and products. Fields are
+// snake_case like the JSON of the REST API.
+func NewSchema(services Services) (graphql.Schema, error) {
+	var categoryType, productType *graphql.Object
+
+	categoryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":   {Type: nonNull(graphql.Int)},
+				"name": {Type: nonNull(graphql.String)},
+				"products": {
+					Type:        listOf(productType),
+					Description: "Products of the category",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						category := p.Source.(web.CategoryResponse)
+						thunk := loadersFrom(p.Context).productsByCategory.Load(p.Context, category.Name)
+						return func() (interface{}, error) {
+							products, err := thunk()
+							if products == nil {
+								products = []web.ProductResponse{}
+							}
+							return resolved(products, err)
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	productType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"product_id":  {Type: nonNull(graphql.String)},
+				"name":        {Type: nonNull(graphql.String)},
+				"description": {Type: nonNull(graphql.String)},
+				"price":       {Type: nonNull(graphql.Float)},
+				"price_amount": {
+					Type:        nonNull(graphql.String),
+					Description: "Exact price as a decimal string in currency, price is kept for the clients reading a Float",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source.(web.ProductResponse).PriceAmount.String(), nil
+					},
+				},
+				"currency":  {Type: nonNull(graphql.String)},
+				"stock_qty": {Type: nonNull(graphql.Int)},
+				"sku":       {Type: nonNull(graphql.String)},
+				"tax_rate":  {Type: nonNull(graphql.Float)},
+				"category_name": {
+					Type: nonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source.(web.ProductResponse).Category, nil
+					},
+				},
+				"category": {
+					Type:        categoryType,
+					Description: "Category named by category_name, null when it does not exist",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						product := p.Source.(web.ProductResponse)
+						thunk := loadersFrom(p.Context).categoryByName.Load(p.Context, product.Category)
+						return func() (interface{}, error) {
+							category, err := thunk()
+							if err != nil || category == nil {
+								return resolved(nil, err)
+							}
+							return *category, nil
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	customerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Customer",
+		Fields: graphql.Fields{
+			"customer_id":    {Type: nonNull(graphql.String)},
+			"name":           {Type: nonNull(graphql.String)},
+			"email":          {Type: nonNull(graphql.String)},
+			"phone":          {Type: nonNull(graphql.String)},
+			"address":        {Type: nonNull(graphql.String)},
+			"loyalty_points": {Type: nonNull(graphql.Int)},
+		},
+	})
+
+	employeeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Employee",
+		Fields: graphql.Fields{
+			"employee_id": {Type: nonNull(graphql.String)},
+			"name":        {Type: nonNull(graphql.String)},
+			"role":        {Type: nonNull(graphql.String)},
+			"email":       {Type: nonNull(graphql.String)},
+			"phone":       {Type: nonNull(graphql.String)},
+			"date_hired":  {Type: nonNull(graphql.String)},
+		},
+	})
+
+	categoryInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CategoryInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name": {Type: nonNull(graphql.String)},
+		},
+	})
+
+	productInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ProductInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: nonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"price":       {Type: graphql.Float},
+			"price_amount": {
+				Type:        graphql.String,
+				Description: "Exact price as a decimal string in the default currency, used instead of price when set",
+			},
+			"stock_qty": {Type: nonNull(graphql.Int)},
+			"category":  {Type: nonNull(graphql.String)},
+			"sku":       {Type: nonNull(graphql.String)},
+			"tax_rate":  {Type: graphql.Float},
+		},
+	})
+
+	customerInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CustomerInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":           {Type: nonNull(graphql.String)},
+			"email":          {Type: nonNull(graphql.String)},
+			"phone":          {Type: nonNull(graphql.String)},
+			"address":        {Type: graphql.String},
+			"loyalty_points": {Type: graphql.Int},
+		},
+	})
+
+	employeeInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "EmployeeInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":       {Type: nonNull(graphql.String)},
+			"role":       {Type: nonNull(graphql.String)},
+			"email":      {Type: nonNull(graphql.String)},
+			"phone":      {Type: nonNull(graphql.String)},
+			"date_hired": {Type: nonNull(graphql.String)},
+		},
+	})
+
+	intId := graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.Int)}}
+	stringId := graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.String)}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"category": {Type: nonNull(categoryType), Args: intId, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(services.Category.FindById(p.Context, p.Args["id"].(int)))
+			}},
+			"categories": {Type: listOf(categoryType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(services.Category.FindAll(p.Context))
+			}},
+			"customer": {Type: nonNull(customerType), Args: stringId, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(services.Customer.FindById(p.Context, p.Args["id"].(string)))
+			}},
+			"customers": {Type: listOf(customerType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return resolved(services.Customer.FindAll(p.Context))
+			}},
+			"employee": {Type: nonNull(employeeType), Args: stringId, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if storeId := storeFrom(p.Context); storeId != "" {
+					return resolved(services.Store.FindEmployee(p.Context, storeId, p.Args["id"].(string)))
+				}
+				return resolved(services.Employee.FindById(p.Context, p.Args["id"].(string)))
+			}},
+			"employees": {Type: listOf(employeeType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if storeId := storeFrom(p.Context); storeId != "" {
+					return resolved(services.Store.FindEmployees(p.Context, storeId))
+				}
+				return resolved(services.Employee.FindAll(p.Context))
+			}},
+			"product": {Type: nonNull(productType), Args: stringId, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if storeId := storeFrom(p.Context); storeId != "" {
+					return resolved(services.Store.FindProduct(p.Context, storeId, p.Args["id"].(string)))
+				}
+				return resolved(services.Product.FindById(p.Context, p.Args["id"].(string)))
+			}},
+			"products": {Type: listOf(productType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if storeId := storeFrom(p.Context); storeId != "" {
+					return resolved(services.Store.FindProducts(p.Context, storeId, nil))
+				}
+				return resolved(services.Product.FindAll(p.Context))
+			}},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCategory": {
+				Type: nonNull(categoryType),
+				Args: graphql.FieldConfigArgument{"input": {Type: nonNull(categoryInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.CategoryCreateRequest
+					if err := decodeInput(p.Args["input"], &request); err != nil {
+						return nil, err
+					}
+					return resolved(services.Category.Create(p.Context, request))
+				},
+			},
+			"updateCategory": {
+				Type: nonNull(categoryType),
+				Args: graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.Int)}, "input": {Type: nonNull(categoryInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.CategoryUpdateRequest
+					if err := decodeInput(p.Args["input"], &request); err != nil {
+						return nil, err
+					}
+					request.Id = p.Args["id"].(int)
+					return resolved(services.Category.Update(p.Context, request))
+				},
+			},
+			"deleteCategory": {
+				Type: nonNull(graphql.Boolean),
+				Args: intId,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolved(true, services.Category.Delete(p.Context, p.Args["id"].(int)))
+				},
+			},
+
+			"createCustomer": {
+				Type: nonNull(customerType),
+				Args: graphql.FieldConfigArgument{"input": {Type: nonNull(customerInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.CustomerCreateRequest
+					if err := decodeInput(p.Args["input"], &request); err != nil {
+						return nil, err
+					}
+					return resolved(services.Customer.Create(p.Context, request))
+				},
+			},
+			"updateCustomer": {
+				Type: nonNull(customerType),
+				Args: graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.String)}, "input": {Type: nonNull(customerInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.CustomerUpdateRequest
+					if err := decodeInput(p.Args["input"], &request); err != nil {
+						return nil, err
+					}
+					request.CustomerID = p.Args["id"].(string)
+					return resolved(services.Customer.Update(p.Context, request))
+				},
+			},
+			"deleteCustomer": {
+				Type: nonNull(graphql.Boolean),
+				Args: stringId,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolved(true, services.Customer.Delete(p.Context, p.Args["id"].(string)))
+				},
+			},
+
+			"createEmployee": {
+				Type: nonNull(employeeType),
+				Args: graphql.FieldConfigArgument{"input": {Type: nonNull(employeeInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.EmployeeCreateRequest
+					if err := decodeInput(p.Args["input"], &request); err != nil {
+						return nil, err
+					}
+					return resolved(services.Employee.Create(p.Context, request))
+				},
+			},
+			"updateEmployee": {
+				Type: nonNull(employeeType),
+				Args: graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.String)}, "input": {Type: nonNull(employeeInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.EmployeeUpdateRequest
+					if err := decodeInput(p.Args["input"], &request); err != nil {
+						return nil, err
+					}
+					request.EmployeeID = p.Args["id"].(string)
+					return resolved(services.Employee.Update(p.Context, request))
+				},
+			},
+			"deleteEmployee": {
+				Type: nonNull(graphql.Boolean),
+				Args: stringId,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolved(true, services.Employee.Delete(p.Context, p.Args["id"].(string)))
+				},
+			},
+
+			"createProduct": {
+				Type: nonNull(productType),
+				Args: graphql.FieldConfigArgument{"input": {Type: nonNull(productInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.ProductCreateRequest
+					if err := decodeInput(productInputOf(p.Args["input"]), &request); err != nil {
+						return nil, err
+					}
+					return resolved(services.Product.Create(p.Context, request))
+				},
+			},
+			"updateProduct": {
+				Type: nonNull(productType),
+				Args: graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.String)}, "input": {Type: nonNull(productInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var request web.ProductUpdateRequest
+					if err := decodeInput(productInputOf(p.Args["input"]), &request); err != nil {
+						return nil, err
+					}
+					request.ProductID = p.Args["id"].(string)
+					return resolved(services.Product.Update(p.Context, request))
+				},
+			},
+			"deleteProduct": {
+				Type: nonNull(graphql.Boolean),
+				Args: stringId,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return resolved(true, services.Product.Delete(p.Context, p.Args["id"].(string)))
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
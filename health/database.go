@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DatabaseChecker pings the database through the connection pool
+type DatabaseChecker struct {
+	db *gorm.DB
+}
+
+func NewDatabaseChecker(db *gorm.DB) Checker {
+	return &DatabaseChecker{db: db}
+}
+
+func (checker *DatabaseChecker) Name() string {
+	return "database"
+}
+
+func (checker *DatabaseChecker) Check(ctx context.Context) error {
+	sqlDB, err := checker.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// MigrationChecker verifies that the table and every column of the given models exist,
+// i.e. that the schema has been migrated to what the running binary expects
+type MigrationChecker struct {
+	db     *gorm.DB
+	models []interface{}
+}
+
+func NewMigrationChecker(db *gorm.DB, models ...interface{}) Checker {
+	return &MigrationChecker{db: db, models: models}
+}
+
+func (checker *MigrationChecker) Name() string {
+	return "migrations"
+}
+
+func (checker *MigrationChecker) Check(ctx context.Context) error {
+	migrator := checker.db.WithContext(ctx).Migrator()
+	cache := &sync.Map{}
+
+	for _, model := range checker.models {
+		modelSchema, err := schema.Parse(model, cache, checker.db.NamingStrategy)
+		if err != nil {
+			return err
+		}
+		if !migrator.HasTable(model) {
+			return fmt.Errorf("table %s does not exist", modelSchema.Table)
+		}
+		for _, field := range modelSchema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			if !migrator.HasColumn(model, field.DBName) {
+				return fmt.Errorf("column %s.%s does not exist", modelSchema.Table, field.DBName)
+			}
+		}
+	}
+	return ctx.Err()
+}
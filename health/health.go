@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Checker is implemented by every subsystem that can report whether it is able to serve traffic
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// ErrShuttingDown is reported by the readiness check once the graceful shutdown has started
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Registry keeps the registered checkers and runs them concurrently, each bounded by the timeout
+type Registry struct {
+	mu           sync.RWMutex
+	checkers     []Checker
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds checkers that must all pass before the application is considered ready
+func (registry *Registry) Register(checkers ...Checker) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.checkers = append(registry.checkers, checkers...)
+}
+
+// MarkShuttingDown makes every following readiness check fail so that load balancers
+// stop routing new requests while the in-flight ones are drained
+func (registry *Registry) MarkShuttingDown() {
+	registry.shuttingDown.Store(true)
+}
+
+// Check runs every registered checker and aggregates the results
+func (registry *Registry) Check(ctx context.Context) Report {
+	if registry.shuttingDown.Load() {
+		return Report{
+			Status: StatusDown,
+			Checks: []CheckResult{{Name: "shutdown", Status: StatusDown, Error: ErrShuttingDown.Error()}},
+		}
+	}
+
+	registry.mu.RLock()
+	checkers := append([]Checker(nil), registry.checkers...)
+	registry.mu.RUnlock()
+
+	results := make([]CheckResult, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = registry.run(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (registry *Registry) run(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, registry.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Name:       checker.Name(),
+		Status:     StatusUp,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
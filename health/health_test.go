@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubChecker struct {
+	name  string
+	err   error
+	delay time.Duration
+}
+
+func (checker stubChecker) Name() string {
+	return checker.name
+}
+
+func (checker stubChecker) Check(ctx context.Context) error {
+	select {
+	case <-time.After(checker.delay):
+		return checker.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestRegistryCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		checkers       []Checker
+		expectedStatus string
+		expectedChecks []string
+	}{
+		{
+			name:           "no checkers",
+			checkers:       nil,
+			expectedStatus: StatusUp,
+			expectedChecks: []string{},
+		},
+		{
+			name:           "all up",
+			checkers:       []Checker{stubChecker{name: "database"}, stubChecker{name: "cache"}},
+			expectedStatus: StatusUp,
+			expectedChecks: []string{StatusUp, StatusUp},
+		},
+		{
+			name:           "one down",
+			checkers:       []Checker{stubChecker{name: "database", err: errors.New("connection refused")}, stubChecker{name: "cache"}},
+			expectedStatus: StatusDown,
+			expectedChecks: []string{StatusDown, StatusUp},
+		},
+		{
+			name:           "timeout",
+			checkers:       []Checker{stubChecker{name: "slow", delay: time.Second}},
+			expectedStatus: StatusDown,
+			expectedChecks: []string{StatusDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(50 * time.Millisecond)
+			registry.Register(tt.checkers...)
+
+			report := registry.Check(context.Background())
+			assert.Equal(t, tt.expectedStatus, report.Status)
+
+			statuses := []string{}
+			for _, check := range report.Checks {
+				statuses = append(statuses, check.Status)
+			}
+			assert.Equal(t, tt.expectedChecks, statuses)
+		})
+	}
+}
+
+func TestRegistryCheckShuttingDown(t *testing.T) {
+	registry := NewRegistry(50 * time.Millisecond)
+	registry.Register(stubChecker{name: "database"})
+	assert.Equal(t, StatusUp, registry.Check(context.Background()).Status)
+
+	registry.MarkShuttingDown()
+
+	report := registry.Check(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, ErrShuttingDown.Error(), report.Checks[0].Error)
+}
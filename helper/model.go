@@ -3,6 +3,10 @@ package helper
 import (
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/pricing"
+	"github.com/aronipurwanto/go-restful-api/qris"
+	"github.com/aronipurwanto/go-restful-api/tax"
 )
 
 func ToCategoryResponse(category domain.Category) web.CategoryResponse {
@@ -28,9 +32,19 @@ func ToEmployeeResponse(employee domain.Employee) web.EmployeeResponse {
 		Email:      employee.Email,
 		Phone:      employee.Phone,
 		DateHired:  employee.DateHired,
+		StoreIDs:   ToStoreIds(employee.Stores),
 	}
 }
 
+// ToStoreIds lists the stores of an employee, nil when it is not assigned to any
+func ToStoreIds(stores []domain.EmployeeStore) []string {
+	var storeIds []string
+	for _, store := range stores {
+		storeIds = append(storeIds, store.StoreID)
+	}
+	return storeIds
+}
+
 func ToEmployeeResponses(employees []domain.Employee) []web.EmployeeResponse {
 	var employeeResponses []web.EmployeeResponse
 	for _, employee := range employees {
@@ -40,16 +54,56 @@ func ToEmployeeResponses(employees []domain.Employee) []web.EmployeeResponse {
 }
 
 func ToProductResponse(product domain.Product) web.ProductResponse {
-	return web.ProductResponse{
+	response := web.ProductResponse{
 		ProductID:   product.ProductID,
 		Name:        product.Name,
 		Description: product.Description,
-		Price:       product.Price,
+		Price:       product.Price.Float64(),
+		PriceAmount: product.Price,
+		Currency:    product.Price.Currency(),
 		StockQty:    product.StockQty,
 		Category:    product.Category,
 		SKU:         product.SKU,
+		TaxClass:    product.TaxClassID,
 		TaxRate:     product.TaxRate,
 	}
+	for _, option := range product.Options {
+		response.Options = append(response.Options, option.Name)
+	}
+	for _, variant := range product.Variants {
+		response.Variants = append(response.Variants, ToProductVariantResponse(product, variant))
+	}
+	return response
+}
+
+func ToProductVariantResponse(product domain.Product, variant domain.ProductVariant) web.ProductVariantResponse {
+	response := web.ProductVariantResponse{
+		VariantID:           variant.VariantID,
+		SKU:                 variant.SKU,
+		Barcode:             variant.Barcode,
+		Price:               product.Price.Float64(),
+		PriceAmount:         product.Price,
+		PriceOverride:       toPriceNumber(variant.PriceOverride),
+		PriceOverrideAmount: variant.PriceOverride,
+		StockQty:            variant.StockQty,
+		Options:             make(map[string]string, len(variant.Options)),
+	}
+	if variant.PriceOverride != nil {
+		response.Price, response.PriceAmount = variant.PriceOverride.Float64(), *variant.PriceOverride
+	}
+	for _, option := range variant.Options {
+		response.Options[option.Name] = option.Value
+	}
+	return response
+}
+
+// toPriceNumber is the number still sent next to the amount of a price during the transition
+func toPriceNumber(price *money.Money) *float64 {
+	if price == nil {
+		return nil
+	}
+	number := price.Float64()
+	return &number
 }
 
 func ToProductResponses(products []domain.Product) []web.ProductResponse {
@@ -78,3 +132,488 @@ func ToCustomerResponses(customers []domain.Customer) []web.CustomerResponse {
 	}
 	return customerResponses
 }
+
+func ToWebhookResponse(webhook domain.Webhook) web.WebhookResponse {
+	return web.WebhookResponse{
+		WebhookID:  webhook.WebhookID,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+		CreatedAt:  webhook.CreatedAt,
+		UpdatedAt:  webhook.UpdatedAt,
+	}
+}
+
+func ToWebhookResponses(webhooks []domain.Webhook) []web.WebhookResponse {
+	var webhookResponses []web.WebhookResponse
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, ToWebhookResponse(webhook))
+	}
+	return webhookResponses
+}
+
+func ToWebhookDeliveryResponse(delivery domain.WebhookDelivery) web.WebhookDeliveryResponse {
+	return web.WebhookDeliveryResponse{
+		DeliveryID:    delivery.DeliveryID,
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		ResponseCode:  delivery.ResponseCode,
+		ResponseBody:  delivery.ResponseBody,
+		Error:         delivery.Error,
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+}
+
+func ToWebhookDeliveryResponses(deliveries []domain.WebhookDelivery) []web.WebhookDeliveryResponse {
+	var deliveryResponses []web.WebhookDeliveryResponse
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, ToWebhookDeliveryResponse(delivery))
+	}
+	return deliveryResponses
+}
+
+func ToAPIKeyResponse(apiKey domain.APIKey) web.APIKeyResponse {
+	return web.APIKeyResponse{
+		KeyID:       apiKey.KeyID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Permissions: apiKey.Permissions,
+		CreatedAt:   apiKey.CreatedAt,
+		RevokedAt:   apiKey.RevokedAt,
+	}
+}
+
+func ToAPIKeyResponses(apiKeys []domain.APIKey) []web.APIKeyResponse {
+	var apiKeyResponses []web.APIKeyResponse
+	for _, apiKey := range apiKeys {
+		apiKeyResponses = append(apiKeyResponses, ToAPIKeyResponse(apiKey))
+	}
+	return apiKeyResponses
+}
+
+func ToStoreResponse(store domain.Store) web.StoreResponse {
+	return web.StoreResponse{
+		StoreID:       store.StoreID,
+		Name:          store.Name,
+		Address:       store.Address,
+		Phone:         store.Phone,
+		PriceMode:     store.PriceMode,
+		TaxRounding:   store.TaxRounding,
+		ReceiptHeader: store.ReceiptHeader,
+		ReceiptFooter: store.ReceiptFooter,
+		ReceiptQR:     store.ReceiptQR,
+		CreatedAt:     store.CreatedAt,
+		UpdatedAt:     store.UpdatedAt,
+	}
+}
+
+func ToStoreResponses(stores []domain.Store) []web.StoreResponse {
+	var storeResponses []web.StoreResponse
+	for _, store := range stores {
+		storeResponses = append(storeResponses, ToStoreResponse(store))
+	}
+	return storeResponses
+}
+
+// ToStoreStockResponse is the stock of a product in a store with that of its variants, the
+// variants of other products are skipped
+func ToStoreStockResponse(stock domain.StoreStock, variants []domain.StoreVariantStock) web.StoreStockResponse {
+	response := web.StoreStockResponse{
+		StoreID:             stock.StoreID,
+		ProductID:           stock.ProductID,
+		StockQty:            stock.StockQty,
+		PriceOverride:       toPriceNumber(stock.PriceOverride),
+		PriceOverrideAmount: stock.PriceOverride,
+		UpdatedAt:           stock.UpdatedAt,
+	}
+	for _, variant := range variants {
+		if variant.ProductID == stock.ProductID {
+			response.Variants = append(response.Variants, web.StoreVariantStockResponse{VariantID: variant.VariantID, StockQty: variant.StockQty})
+		}
+	}
+	return response
+}
+
+func ToStoreStockResponses(stocks []domain.StoreStock, variants []domain.StoreVariantStock) []web.StoreStockResponse {
+	var stockResponses []web.StoreStockResponse
+	for _, stock := range stocks {
+		stockResponses = append(stockResponses, ToStoreStockResponse(stock, variants))
+	}
+	return stockResponses
+}
+
+// ToStoreProductResponse is the product as seen from a store: the stock of the store and its
+// price override if any. A product or a variant the store never stocked has no stock there.
+func ToStoreProductResponse(storeId string, product domain.Product, stock domain.StoreStock, variants []domain.StoreVariantStock) web.ProductResponse {
+	response := ToProductResponse(product)
+	response.StoreID = storeId
+	response.StockQty = stock.StockQty
+	if stock.PriceOverride != nil {
+		response.Price, response.PriceAmount = stock.PriceOverride.Float64(), *stock.PriceOverride
+	}
+	variantStock := make(map[string]int, len(variants))
+	for _, variant := range variants {
+		variantStock[variant.VariantID] = variant.StockQty
+	}
+	// the variants without a price of their own follow the price of the store
+	for i := range response.Variants {
+		response.Variants[i].StockQty = variantStock[response.Variants[i].VariantID]
+		if response.Variants[i].PriceOverride == nil {
+			response.Variants[i].Price, response.Variants[i].PriceAmount = response.Price, response.PriceAmount
+		}
+	}
+	return response
+}
+
+func ToStockTransferResponse(transfer domain.StockTransfer) web.StockTransferResponse {
+	lines := make([]web.StockTransferLineResponse, 0, len(transfer.Lines))
+	for _, line := range transfer.Lines {
+		lines = append(lines, web.StockTransferLineResponse{
+			ProductID:         line.ProductID,
+			VariantID:         line.VariantID,
+			Quantity:          line.Quantity,
+			ReceivedQty:       line.ReceivedQty,
+			DiscrepancyQty:    line.DiscrepancyQty,
+			DiscrepancyReason: line.DiscrepancyReason,
+		})
+	}
+	return web.StockTransferResponse{
+		TransferID:         transfer.TransferID,
+		SourceStoreID:      transfer.SourceStoreID,
+		DestinationStoreID: transfer.DestinationStoreID,
+		Status:             transfer.Status,
+		Note:               transfer.Note,
+		Lines:              lines,
+		CreatedAt:          transfer.CreatedAt,
+		UpdatedAt:          transfer.UpdatedAt,
+		DispatchedAt:       transfer.DispatchedAt,
+		ReceivedAt:         transfer.ReceivedAt,
+	}
+}
+
+func ToStockTransferResponses(transfers []domain.StockTransfer) []web.StockTransferResponse {
+	var transferResponses []web.StockTransferResponse
+	for _, transfer := range transfers {
+		transferResponses = append(transferResponses, ToStockTransferResponse(transfer))
+	}
+	return transferResponses
+}
+
+func ToPromotionResponse(promotion domain.Promotion) web.PromotionResponse {
+	promotionResponse := web.PromotionResponse{
+		PromotionID: promotion.PromotionID,
+		Name:        promotion.Name,
+		Type:        promotion.Type,
+		Value:       promotion.Value,
+		BuyQty:      promotion.BuyQty,
+		GetQty:      promotion.GetQty,
+		ProductIDs:  promotion.ProductIDs,
+		Categories:  promotion.Categories,
+		CustomerIDs: promotion.CustomerIDs,
+		StartsAt:    promotion.StartsAt,
+		EndsAt:      promotion.EndsAt,
+		Priority:    promotion.Priority,
+		Stackable:   promotion.Stackable,
+		UsageLimit:  promotion.UsageLimit,
+		UsageCount:  promotion.UsageCount,
+		Active:      promotion.Active,
+		CreatedAt:   promotion.CreatedAt,
+		UpdatedAt:   promotion.UpdatedAt,
+	}
+	if domain.HasAmount(promotion.Type) {
+		amount := promotion.Amount
+		promotionResponse.Value = amount.Float64()
+		promotionResponse.Amount = &amount
+	}
+	return promotionResponse
+}
+
+func ToPromotionResponses(promotions []domain.Promotion) []web.PromotionResponse {
+	var promotionResponses []web.PromotionResponse
+	for _, promotion := range promotions {
+		promotionResponses = append(promotionResponses, ToPromotionResponse(promotion))
+	}
+	return promotionResponses
+}
+
+func ToQuoteResponse(storeId string, customerId string, result pricing.Result) web.QuoteResponse {
+	response := web.QuoteResponse{
+		StoreID:    storeId,
+		CustomerID: customerId,
+		Lines:      make([]web.QuoteLineResponse, 0, len(result.Lines)),
+		Promotions: toAppliedPromotionResponses(result.Promotions),
+		PriceMode:  result.PriceMode,
+		Currency:   result.Total.Currency(),
+		Subtotal:   result.Subtotal,
+		Discount:   result.Discount,
+		Tax:        result.Tax,
+		Taxes:      ToTaxAmountResponses(result.Taxes),
+		Total:      result.Total,
+		CashTotal:  result.Total.CashRound(),
+	}
+	for _, line := range result.Lines {
+		response.Lines = append(response.Lines, web.QuoteLineResponse{
+			ProductID:  line.ProductID,
+			VariantID:  line.VariantID,
+			Name:       line.Name,
+			UnitPrice:  line.UnitPrice,
+			Quantity:   line.Quantity,
+			Subtotal:   line.Subtotal,
+			Discount:   line.Discount,
+			TaxClass:   line.TaxClass,
+			TaxRate:    domain.TaxClass{Rates: line.TaxRates}.Percent(),
+			Tax:        line.Tax,
+			Taxes:      ToTaxAmountResponses(line.Taxes),
+			Total:      line.Total,
+			Promotions: toAppliedPromotionResponses(line.Promotions),
+		})
+	}
+	return response
+}
+
+func toAppliedPromotionResponses(applied []pricing.Applied) []web.AppliedPromotionResponse {
+	responses := make([]web.AppliedPromotionResponse, 0, len(applied))
+	for _, promotion := range applied {
+		responses = append(responses, web.AppliedPromotionResponse{PromotionID: promotion.PromotionID, Name: promotion.Name, Discount: promotion.Discount})
+	}
+	return responses
+}
+
+func ToTaxClassResponse(class domain.TaxClass) web.TaxClassResponse {
+	response := web.TaxClassResponse{
+		TaxClassID: class.TaxClassID,
+		Name:       class.Name,
+		Percent:    class.Percent(),
+		Rates:      make([]web.TaxRateResponse, 0, len(class.Rates)),
+	}
+	for _, rate := range class.Rates {
+		response.Rates = append(response.Rates, web.TaxRateResponse{Code: rate.Code, Name: rate.Name, Percent: rate.Percent})
+	}
+	return response
+}
+
+func ToTaxClassResponses(classes []domain.TaxClass) []web.TaxClassResponse {
+	var classResponses []web.TaxClassResponse
+	for _, class := range classes {
+		classResponses = append(classResponses, ToTaxClassResponse(class))
+	}
+	return classResponses
+}
+
+func ToTaxAmountResponses(amounts []tax.Amount) []web.TaxAmountResponse {
+	responses := make([]web.TaxAmountResponse, 0, len(amounts))
+	for _, amount := range amounts {
+		responses = append(responses, web.TaxAmountResponse{Code: amount.Code, Name: amount.Name, Percent: amount.Percent, Base: amount.Base, Tax: amount.Tax})
+	}
+	return responses
+}
+
+func ToOrderResponse(order domain.Order) web.OrderResponse {
+	response := web.OrderResponse{
+		OrderID:       order.OrderID,
+		StoreID:       order.StoreID,
+		EmployeeID:    order.EmployeeID,
+		CustomerID:    order.CustomerID,
+		PriceMode:     order.PriceMode,
+		Currency:      order.Currency,
+		Lines:         make([]web.OrderLineResponse, 0, len(order.Lines)),
+		Promotions:    make([]web.AppliedPromotionResponse, 0, len(order.Promotions)),
+		Subtotal:      order.Subtotal,
+		Discount:      order.Discount,
+		Tax:           order.Tax,
+		Taxes:         make([]web.TaxAmountResponse, 0, len(order.Taxes)),
+		Total:         order.Total,
+		PointsEarned:  order.PointsEarned,
+		PointsBalance: order.PointsBalance,
+		CreatedAt:     order.CreatedAt,
+	}
+	for _, line := range order.Lines {
+		response.Lines = append(response.Lines, web.OrderLineResponse{
+			Position:  line.Position,
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Name:      line.Name,
+			TaxClass:  line.TaxClassID,
+			UnitPrice: line.UnitPrice,
+			Quantity:  line.Quantity,
+			Subtotal:  line.Subtotal,
+			Discount:  line.Discount,
+			Tax:       line.Tax,
+			Total:     line.Total,
+		})
+	}
+	for _, promotion := range order.Promotions {
+		response.Promotions = append(response.Promotions, web.AppliedPromotionResponse{PromotionID: promotion.PromotionID, Name: promotion.Name, Discount: promotion.Discount})
+	}
+	for _, amount := range order.Taxes {
+		response.Taxes = append(response.Taxes, web.TaxAmountResponse{Code: amount.Code, Name: amount.Name, Percent: amount.Percent, Base: amount.Base, Tax: amount.Tax})
+	}
+	return response
+}
+
+func ToOrderResponses(orders []domain.Order) []web.OrderResponse {
+	var orderResponses []web.OrderResponse
+	for _, order := range orders {
+		orderResponses = append(orderResponses, ToOrderResponse(order))
+	}
+	return orderResponses
+}
+
+func ToQRISResponse(text string, payload qris.Payload, merchant qris.Merchant) web.QRISResponse {
+	return web.QRISResponse{
+		Payload:      text,
+		Amount:       payload.Amount,
+		Currency:     payload.Amount.Currency(),
+		Reference:    payload.Reference,
+		MerchantName: payload.Merchant.Name,
+		MerchantCity: payload.Merchant.City,
+		NMID:         payload.Merchant.NMID,
+		OwnMerchant:  payload.Merchant.NMID == merchant.NMID && payload.Merchant.PAN == merchant.PAN,
+	}
+}
+
+func ToPaymentResponse(payment domain.Payment) web.PaymentResponse {
+	paymentResponse := web.PaymentResponse{
+		PaymentID:  payment.PaymentID,
+		OrderID:    payment.OrderID,
+		StoreID:    payment.StoreID,
+		CustomerID: payment.CustomerID,
+		Amount:     payment.Amount,
+		Status:     payment.Status,
+		Tenders:    make([]web.TenderResponse, 0, len(payment.Tenders)),
+		CreatedAt:  payment.CreatedAt,
+		UpdatedAt:  payment.UpdatedAt,
+	}
+	for _, tender := range payment.Tenders {
+		paymentResponse.Tenders = append(paymentResponse.Tenders, web.TenderResponse{
+			Method:        tender.Method,
+			Amount:        tender.Amount,
+			Tendered:      tender.Tendered,
+			Change:        tender.Change,
+			Points:        tender.Points,
+			Refunded:      tender.Refunded,
+			Provider:      tender.Provider,
+			ChargeID:      tender.ChargeID,
+			Status:        tender.Status,
+			DeclineReason: tender.DeclineReason,
+		})
+	}
+	return paymentResponse
+}
+
+func ToPaymentResponses(payments []domain.Payment) []web.PaymentResponse {
+	var paymentResponses []web.PaymentResponse
+	for _, payment := range payments {
+		paymentResponses = append(paymentResponses, ToPaymentResponse(payment))
+	}
+	return paymentResponses
+}
+
+func ToReturnResponse(orderReturn domain.Return) web.ReturnResponse {
+	returnResponse := web.ReturnResponse{
+		ReturnID:       orderReturn.ReturnID,
+		OrderID:        orderReturn.OrderID,
+		StoreID:        orderReturn.StoreID,
+		CustomerID:     orderReturn.CustomerID,
+		EmployeeID:     orderReturn.EmployeeID,
+		ApprovedBy:     orderReturn.ApprovedBy,
+		Status:         orderReturn.Status,
+		Note:           orderReturn.Note,
+		Lines:          make([]web.ReturnLineResponse, 0, len(orderReturn.Lines)),
+		Subtotal:       orderReturn.Subtotal,
+		Discount:       orderReturn.Discount,
+		Tax:            orderReturn.Tax,
+		Refund:         orderReturn.Refund,
+		PointsReversed: orderReturn.PointsReversed,
+		Refunds:        make([]web.ReturnRefundResponse, 0, len(orderReturn.Refunds)),
+		CreatedAt:      orderReturn.CreatedAt,
+		UpdatedAt:      orderReturn.UpdatedAt,
+		CompletedAt:    orderReturn.CompletedAt,
+	}
+	for _, line := range orderReturn.Lines {
+		returnResponse.Lines = append(returnResponse.Lines, web.ReturnLineResponse{
+			OrderPosition: line.OrderPosition,
+			ProductID:     line.ProductID,
+			VariantID:     line.VariantID,
+			Name:          line.Name,
+			Quantity:      line.Quantity,
+			Reason:        line.Reason,
+			Disposition:   line.Disposition,
+			Subtotal:      line.Subtotal,
+			Discount:      line.Discount,
+			Tax:           line.Tax,
+			Refund:        line.Refund,
+		})
+	}
+	for _, refund := range orderReturn.Refunds {
+		returnResponse.Refunds = append(returnResponse.Refunds, web.ReturnRefundResponse{
+			Method:   refund.Method,
+			Amount:   refund.Amount,
+			Points:   refund.Points,
+			ChargeID: refund.ChargeID,
+			Pending:  refund.Pending,
+		})
+	}
+	return returnResponse
+}
+
+func ToReturnResponses(orderReturns []domain.Return) []web.ReturnResponse {
+	var returnResponses []web.ReturnResponse
+	for _, orderReturn := range orderReturns {
+		returnResponses = append(returnResponses, ToReturnResponse(orderReturn))
+	}
+	return returnResponses
+}
+
+func ToCashSessionResponse(session domain.CashSession) web.CashSessionResponse {
+	sessionResponse := web.CashSessionResponse{
+		SessionID:    session.SessionID,
+		StoreID:      session.StoreID,
+		EmployeeID:   session.EmployeeID,
+		ClosedBy:     session.ClosedBy,
+		Status:       session.Status,
+		OpeningFloat: session.OpeningFloat,
+		Counts:       make([]web.CashCountResponse, 0, len(session.Counts)),
+		Note:         session.Note,
+		Movements:    make([]web.CashMovementResponse, 0, len(session.Movements)),
+		OpenedAt:     session.OpenedAt,
+		ClosedAt:     session.ClosedAt,
+	}
+	if session.Status == domain.CashSessionClosed {
+		expected, counted, variance := session.Expected, session.Counted, session.Variance
+		sessionResponse.Expected = &expected
+		sessionResponse.Counted = &counted
+		sessionResponse.Variance = &variance
+	}
+	for _, count := range session.Counts {
+		sessionResponse.Counts = append(sessionResponse.Counts, web.CashCountResponse{
+			Denomination: count.Denomination,
+			Quantity:     count.Quantity,
+			Amount:       count.Denomination.Mul(int64(count.Quantity)),
+		})
+	}
+	for _, movement := range session.Movements {
+		sessionResponse.Movements = append(sessionResponse.Movements, web.CashMovementResponse{
+			Position:   movement.Position,
+			Type:       movement.Type,
+			Amount:     movement.Amount,
+			Reason:     movement.Reason,
+			EmployeeID: movement.EmployeeID,
+			CreatedAt:  movement.CreatedAt,
+		})
+	}
+	return sessionResponse
+}
+
+func ToCashSessionResponses(sessions []domain.CashSession) []web.CashSessionResponse {
+	var sessionResponses []web.CashSessionResponse
+	for _, session := range sessions {
+		sessionResponses = append(sessionResponses, ToCashSessionResponse(session))
+	}
+	return sessionResponses
+}
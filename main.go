@@ -1,57 +1,18 @@
 package main
 
 import (
-	"github.com/aronipurwanto/go-restful-api/app"
-	"github.com/aronipurwanto/go-restful-api/controller"
-	"github.com/aronipurwanto/go-restful-api/helper"
-	"github.com/aronipurwanto/go-restful-api/model/domain"
-	"github.com/aronipurwanto/go-restful-api/repository"
-	"github.com/aronipurwanto/go-restful-api/service"
-	"github.com/go-playground/validator/v10"
+	"context"
+	"github.com/aronipurwanto/go-restful-api/cli"
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/gofiber/fiber/v2"
-	"log"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-
-	server := fiber.New()
-
-	// Initialize Database
-	db := app.NewDB()
-
-	// Run Auto Migration (Opsional, bisa dihapus jika tidak diperlukan)
-	err := db.AutoMigrate(&domain.Category{}, &domain.Customer{}, &domain.Product{}, &domain.Employee{})
-	helper.PanicIfError(err)
-
-	// Initialize Validator
-	validate := validator.New()
-
-	// Initialize Repository, Service, and Controller
-	categoryRepository := repository.NewCategoryRepository(db)
-	categoryService := service.NewCategoryService(categoryRepository, validate)
-	categoryController := controller.NewCategoryController(categoryService)
-
-	// Initialize Repository, Service, and Controller for Customer
-	customerRepository := repository.NewCustomerRepository(db)
-	customerService := service.NewCustomerService(customerRepository, validate)
-	customerController := controller.NewCustomerController(customerService)
-
-	// Initialize Repository, Service, and Controller for Employee
-	employeeRepository := repository.NewEmployeeRepository(db)
-	employeeService := service.NewEmployeeService(employeeRepository, validate)
-	employeeController := controller.NewEmployeeController(employeeService)
-
-	// Initialize Repository, Service, and Controller for Product
-	productRepository := repository.NewProductRepository(db)
-	productService := service.NewProductService(productRepository, validate)
-	productController := controller.NewProductController(productService)
-
-	// Setup Routes
-	app.NewRouter(server, categoryController, customerController, employeeController, productController)
-
-	// Start Server
-	log.Println("Server running on port 8080")
-	err = server.Listen(":8080")
-	helper.PanicIfError(err)
+	// Tanpa argumen server dijalankan, lihat "go-restful-api --help" untuk command lainnya
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := cli.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
 }
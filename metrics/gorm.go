@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startTimeKey = "metrics:start_time"
+
+// GormPlugin records the duration and errors of every query executed through GORM
+type GormPlugin struct {
+	metrics *Metrics
+}
+
+func (m *Metrics) GormPlugin() gorm.Plugin {
+	return &GormPlugin{metrics: m}
+}
+
+func (plugin *GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (plugin *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+
+	if err := callback.Create().Before("gorm:create").Register("metrics:before_create", plugin.before); err != nil {
+		return err
+	}
+	if err := callback.Create().After("gorm:create").Register("metrics:after_create", plugin.after("create")); err != nil {
+		return err
+	}
+	if err := callback.Query().Before("gorm:query").Register("metrics:before_query", plugin.before); err != nil {
+		return err
+	}
+	if err := callback.Query().After("gorm:query").Register("metrics:after_query", plugin.after("query")); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("metrics:before_update", plugin.before); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("metrics:after_update", plugin.after("update")); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("metrics:before_delete", plugin.before); err != nil {
+		return err
+	}
+	if err := callback.Delete().After("gorm:delete").Register("metrics:after_delete", plugin.after("delete")); err != nil {
+		return err
+	}
+	if err := callback.Row().Before("gorm:row").Register("metrics:before_row", plugin.before); err != nil {
+		return err
+	}
+	if err := callback.Row().After("gorm:row").Register("metrics:after_row", plugin.after("row")); err != nil {
+		return err
+	}
+	if err := callback.Raw().Before("gorm:raw").Register("metrics:before_raw", plugin.before); err != nil {
+		return err
+	}
+	return callback.Raw().After("gorm:raw").Register("metrics:after_raw", plugin.after("raw"))
+}
+
+func (plugin *GormPlugin) before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func (plugin *GormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		start := value.(time.Time)
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		plugin.metrics.queryDuration.WithLabelValues(table, operation).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			plugin.metrics.queryErrors.WithLabelValues(table, operation).Inc()
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// unmatchedRoute labels requests that did not hit any registered route, so that
+// scanners probing random URLs cannot blow up the label cardinality
+const unmatchedRoute = "unmatched"
+
+// Middleware records the request count and latency of every request, labelled by route template
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		middlewareRoute := c.Route()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+
+		// The route only advances past this middleware when a registered handler matched
+		route := c.Route().Path
+		if c.Route() == middlewareRoute {
+			route = unmatchedRoute
+		}
+
+		// c.Method() points into the request buffer that fasthttp reuses, the registry keeps the label
+		labels := []string{utils.CopyString(c.Method()), route, strconv.Itoa(status)}
+		m.httpRequests.WithLabelValues(labels...).Inc()
+		m.httpDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
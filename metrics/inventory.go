@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// InventoryCollector exposes business gauges computed from the products and store stock tables
+// on every scrape. products_below_reorder_level counts the central (warehouse) stock of the
+// products table, store_products_below_reorder_level the stock of each store.
+type InventoryCollector struct {
+	db           *gorm.DB
+	reorderLevel int
+
+	productsTotal       *prometheus.Desc
+	productsBelowLevel  *prometheus.Desc
+	storeBelowLevel     *prometheus.Desc
+	inventoryScrapeFail *prometheus.Desc
+}
+
+// storeBelow is the number of products of a store below the reorder level
+type storeBelow struct {
+	StoreID string
+	Below   int64
+}
+
+func NewInventoryCollector(db *gorm.DB, reorderLevel int) prometheus.Collector {
+	return &InventoryCollector{
+		db:           db,
+		reorderLevel: reorderLevel,
+		productsTotal: prometheus.NewDesc("products_total",
+			"Number of products in the catalogue.", nil, nil),
+		productsBelowLevel: prometheus.NewDesc("products_below_reorder_level",
+			"Number of products whose central stock quantity is below the reorder level.", nil, nil),
+		storeBelowLevel: prometheus.NewDesc("store_products_below_reorder_level",
+			"Number of products stocked by a store whose stock quantity there is below the reorder level.", []string{"store"}, nil),
+		inventoryScrapeFail: prometheus.NewDesc("inventory_scrape_error",
+			"1 if the last inventory scrape failed, 0 otherwise.", nil, nil),
+	}
+}
+
+func (collector *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.productsTotal
+	ch <- collector.productsBelowLevel
+	ch <- collector.storeBelowLevel
+	ch <- collector.inventoryScrapeFail
+}
+
+func (collector *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var total, below int64
+	db := collector.db.WithContext(ctx).Model(&domain.Product{})
+	err := db.Count(&total).Error
+	if err == nil {
+		err = collector.db.WithContext(ctx).Model(&domain.Product{}).
+			Where("stock_qty < ?", collector.reorderLevel).Count(&below).Error
+	}
+	// Every store gets a series, those with nothing below the level a 0
+	var stores []storeBelow
+	if err == nil {
+		err = collector.db.WithContext(ctx).Model(&domain.Store{}).
+			Select("stores.store_id AS store_id, COUNT(store_stocks.product_id) AS below").
+			Joins("LEFT JOIN store_stocks ON store_stocks.store_id = stores.store_id AND store_stocks.stock_qty < ?", collector.reorderLevel).
+			Group("stores.store_id").Scan(&stores).Error
+	}
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(collector.inventoryScrapeFail, prometheus.GaugeValue, 1)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(collector.productsTotal, prometheus.GaugeValue, float64(total))
+	ch <- prometheus.MustNewConstMetric(collector.productsBelowLevel, prometheus.GaugeValue, float64(below))
+	for _, store := range stores {
+		ch <- prometheus.MustNewConstMetric(collector.storeBelowLevel, prometheus.GaugeValue, float64(store.Below), store.StoreID)
+	}
+	ch <- prometheus.MustNewConstMetric(collector.inventoryScrapeFail, prometheus.GaugeValue, 0)
+}
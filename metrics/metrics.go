@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics owns the Prometheus registry and every collector exposed on /metrics
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+	cacheRequests *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		Registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route template and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gorm_query_duration_seconds",
+			Help:    "GORM query latency by table and operation.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"table", "operation"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gorm_query_errors_total",
+			Help: "Number of failed GORM queries by table and operation.",
+		}, []string{"table", "operation"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_requests_total",
+			Help: "Number of read-through cache lookups by cache name and result (hit or miss).",
+		}, []string{"cache", "result"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.queryDuration,
+		m.queryErrors,
+		m.cacheRequests,
+	)
+	return m
+}
+
+// RegisterDBStats exposes the connection pool statistics of the given sql.DB
+func (m *Metrics) RegisterDBStats(sqlDB *sql.DB, dbName string) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
+
+// CacheHit counts a lookup answered by the cache, see cache.Recorder
+func (m *Metrics) CacheHit(name string) {
+	m.cacheRequests.WithLabelValues(name, "hit").Inc()
+}
+
+// CacheMiss counts a lookup that went to the database, see cache.Recorder
+func (m *Metrics) CacheMiss(name string) {
+	m.cacheRequests.WithLabelValues(name, "miss").Inc()
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+// When token is not empty the request must carry "Authorization: Bearer <token>".
+func (m *Metrics) Handler(token string) fiber.Handler {
+	handler := adaptor.HTTPHandler(promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+
+	return func(c *fiber.Ctx) error {
+		if token != "" {
+			expected := []byte("Bearer " + token)
+			if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+				return c.SendStatus(fiber.StatusUnauthorized)
+			}
+		}
+		return handler(c)
+	}
+}
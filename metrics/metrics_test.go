@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTestAppMetrics(token string) *fiber.App {
+	m := NewMetrics()
+	app := fiber.New()
+	app.Use(m.Middleware())
+	app.Get("/api/products/:productId", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/metrics", m.Handler(token))
+	return app
+}
+
+func TestMetricsHandler(t *testing.T) {
+	app := setupTestAppMetrics("")
+
+	for _, url := range []string{"/api/products/1", "/api/products/2", "/does-not-exist"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/api/products/:productId",status="200"} 2`)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, string(body), "http_request_duration_seconds_bucket")
+}
+
+func TestMetricsHandlerToken(t *testing.T) {
+	app := setupTestAppMetrics("secret")
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "missing token", authorization: "", expectedStatus: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer wrong", expectedStatus: http.StatusUnauthorized},
+		{name: "valid token", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
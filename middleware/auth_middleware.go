@@ -1,15 +1,28 @@
 package middleware
 
 import (
+	"github.com/aronipurwanto/go-restful-api/auth"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/gofiber/fiber/v2"
 )
 
+const principalKey = "principal"
+
 type AuthMiddleware struct{}
 
-func NewAuthMiddleware() fiber.Handler {
+// NewAuthMiddleware resolves the X-API-Key header to its principal, see Principal
+func NewAuthMiddleware(authenticator auth.Authenticator) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if c.Get("X-API-Key") == "RAHASIA" {
+		principal, ok, err := authenticator.Authenticate(c.Context(), c.Get("X-API-Key"))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+				Code:   fiber.StatusInternalServerError,
+				Status: "Internal Server Error",
+				Data:   err.Error(),
+			})
+		}
+		if ok {
+			c.Locals(principalKey, principal)
 			return c.Next()
 		}
 
@@ -19,3 +32,9 @@ func NewAuthMiddleware() fiber.Handler {
 		})
 	}
 }
+
+// Principal returns the caller authenticated by NewAuthMiddleware
+func Principal(c *fiber.Ctx) auth.Principal {
+	principal, _ := c.Locals(principalKey).(auth.Principal)
+	return principal
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/gofiber/fiber/v2"
+)
+
+// StoreHeader selects the store a request works on
+const StoreHeader = "X-Store-ID"
+
+const storeKey = "store"
+
+// NewStoreMiddleware resolves the store context of a request: the store named by the X-Store-ID
+// header, which the caller must be granted, or else the single store the caller's key is bound
+// to. Only callers holding auth.CrossStore may go without a store context, see Store.
+func NewStoreMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := Principal(c)
+		storeId, err := principal.StoreContext(c.Get(StoreHeader))
+		if errors.Is(err, auth.ErrStoreForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(web.WebResponse{
+				Code:   fiber.StatusForbidden,
+				Status: "Forbidden",
+				Data:   fmt.Sprintf("%s is not allowed to access store %s", principal.Name, c.Get(StoreHeader)),
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Bad Request",
+				Data:   "a store context is required, send the " + StoreHeader + " header",
+			})
+		}
+
+		c.Locals(storeKey, storeId)
+		return c.Next()
+	}
+}
+
+// Store returns the store context resolved by NewStoreMiddleware, empty when the request covers
+// every store
+func Store(c *fiber.Ctx) string {
+	storeId, _ := c.Locals(storeKey).(string)
+	return storeId
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// APIKey is a key issued from the command line. Only the SHA-256 hash of the key is stored,
+// the key itself is shown once when it is created.
+type APIKey struct {
+	KeyID       string     `gorm:"primaryKey;column:key_id" json:"key_id"`
+	Name        string     `gorm:"column:name;size:100" json:"name"`
+	Prefix      string     `gorm:"column:prefix;size:16" json:"prefix"`
+	Hash        string     `gorm:"column:hash;size:64;uniqueIndex" json:"-"`
+	Permissions []string   `gorm:"column:permissions;serializer:json" json:"permissions"`
+	CreatedAt   time.Time  `gorm:"column:created_at" json:"created_at"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+}
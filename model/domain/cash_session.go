@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Status of a CashSession
+const (
+	CashSessionOpen   = "open"
+	CashSessionClosed = "closed"
+)
+
+// Types of a CashMovement
+const (
+	// CashIn puts cash in the drawer that was not taken for a sale, e.g. change from the safe
+	CashIn = "cash_in"
+	// CashOut takes cash out of the drawer that is not a refund, e.g. a drop to the safe
+	CashOut = "cash_out"
+)
+
+// CashSession is the shift of an employee at the cash drawer of a store. It is opened with a
+// float, and closed with the cash counted in the drawer: the variance is what was counted minus
+// what was expected from the float, the cash sales, the cash refunds and the movements.
+type CashSession struct {
+	SessionID  string `gorm:"primaryKey;column:session_id" json:"session_id"`
+	StoreID    string `gorm:"column:store_id;size:20;index" json:"store_id"`
+	EmployeeID string `gorm:"column:employee_id;index" json:"employee_id"`
+	// ClosedBy is the employee who counted the drawer, the supervisor or the cashier
+	ClosedBy     string      `gorm:"column:closed_by" json:"closed_by"`
+	Status       string      `gorm:"column:status;size:10;index" json:"status"`
+	OpeningFloat money.Money `gorm:"column:opening_float;type:bigint" json:"opening_float"`
+	// Expected, Counted and Variance are recorded when the session is closed
+	Expected money.Money `gorm:"column:expected;type:bigint" json:"expected"`
+	Counted  money.Money `gorm:"column:counted;type:bigint" json:"counted"`
+	Variance money.Money `gorm:"column:variance;type:bigint" json:"variance"`
+	// Counts are the notes and coins counted in the drawer
+	Counts    []CashCount    `gorm:"column:counts;serializer:json" json:"counts"`
+	Note      string         `gorm:"column:note;size:255" json:"note"`
+	Version   int            `gorm:"column:version" json:"version"`
+	Movements []CashMovement `gorm:"foreignKey:SessionID" json:"movements"`
+	OpenedAt  time.Time      `gorm:"column:opened_at;index" json:"opened_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at" json:"updated_at"`
+	ClosedAt  *time.Time     `gorm:"column:closed_at" json:"closed_at"`
+	// OpenKey is the store and the employee of an open session, its unique index keeps an
+	// employee from having two sessions open in a store. It is cleared when the session closes.
+	OpenKey *string `gorm:"column:open_key;size:100;uniqueIndex" json:"-"`
+}
+
+// CashSessionOpenKey is the OpenKey of the open session of an employee in a store
+func CashSessionOpenKey(storeId string, employeeId string) string {
+	return storeId + "/" + employeeId
+}
+
+// CashMovement is cash put in or taken out of the drawer during a session
+type CashMovement struct {
+	SessionID  string      `gorm:"primaryKey;column:session_id" json:"session_id"`
+	Position   int         `gorm:"primaryKey;column:position" json:"position"`
+	Type       string      `gorm:"column:type;size:10" json:"type"`
+	Amount     money.Money `gorm:"column:amount;type:bigint" json:"amount"`
+	Reason     string      `gorm:"column:reason;size:255" json:"reason"`
+	EmployeeID string      `gorm:"column:employee_id" json:"employee_id"`
+	CreatedAt  time.Time   `gorm:"column:created_at" json:"created_at"`
+}
+
+// CashCount is how many notes or coins of a denomination were counted
+type CashCount struct {
+	Denomination money.Money `json:"denomination"`
+	Quantity     int         `json:"quantity"`
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (customer *Customer) BeforeCreate(tx *gorm.DB) error {
+	if customer.CustomerID == "" {
+		customer.CustomerID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (employee *Employee) BeforeCreate(tx *gorm.DB) error {
+	if employee.EmployeeID == "" {
+		employee.EmployeeID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (product *Product) BeforeCreate(tx *gorm.DB) error {
+	if product.ProductID == "" {
+		product.ProductID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeSave stores the currency of the price next to its minor units
+func (product *Product) BeforeSave(tx *gorm.DB) error {
+	product.Currency = product.Price.Currency()
+	return nil
+}
+
+// AfterFind gives the prices of the product and of its variants the currency of the product
+func (product *Product) AfterFind(tx *gorm.DB) error {
+	if product.Currency == "" || product.Currency == product.Price.Currency() {
+		return nil
+	}
+	if _, ok := money.Lookup(product.Currency); !ok {
+		return fmt.Errorf("product %s has an unsupported currency %q", product.ProductID, product.Currency)
+	}
+	product.Price = money.New(product.Price.Amount(), product.Currency)
+	for i := range product.Variants {
+		if override := product.Variants[i].PriceOverride; override != nil {
+			inCurrency := money.New(override.Amount(), product.Currency)
+			product.Variants[i].PriceOverride = &inCurrency
+		}
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (webhook *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if webhook.WebhookID == "" {
+		webhook.WebhookID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (delivery *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if delivery.DeliveryID == "" {
+		delivery.DeliveryID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (apiKey *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if apiKey.KeyID == "" {
+		apiKey.KeyID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (transfer *StockTransfer) BeforeCreate(tx *gorm.DB) error {
+	if transfer.TransferID == "" {
+		transfer.TransferID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (variant *ProductVariant) BeforeCreate(tx *gorm.DB) error {
+	if variant.VariantID == "" {
+		variant.VariantID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (promotion *Promotion) BeforeCreate(tx *gorm.DB) error {
+	if promotion.PromotionID == "" {
+		promotion.PromotionID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (order *Order) BeforeCreate(tx *gorm.DB) error {
+	if order.OrderID == "" {
+		order.OrderID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (payment *Payment) BeforeCreate(tx *gorm.DB) error {
+	if payment.PaymentID == "" {
+		payment.PaymentID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (orderReturn *Return) BeforeCreate(tx *gorm.DB) error {
+	if orderReturn.ReturnID == "" {
+		orderReturn.ReturnID = uuid.NewString()
+	}
+	return nil
+}
+
+// BeforeCreate assigns a generated ID when the service did not provide one
+func (session *CashSession) BeforeCreate(tx *gorm.DB) error {
+	if session.SessionID == "" {
+		session.SessionID = uuid.NewString()
+	}
+	return nil
+}
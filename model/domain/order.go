@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Order is a sale recorded in a store. The amounts are those the sale was priced at, they are
+// not recomputed when prices, promotions or tax classes change later.
+type Order struct {
+	OrderID    string `gorm:"primaryKey;column:order_id" json:"order_id"`
+	StoreID    string `gorm:"column:store_id;size:20;index" json:"store_id"`
+	EmployeeID string `gorm:"column:employee_id;index" json:"employee_id"`
+	// CustomerID is empty for an anonymous sale
+	CustomerID string      `gorm:"column:customer_id;index" json:"customer_id"`
+	PriceMode  string      `gorm:"column:price_mode;size:10" json:"price_mode"`
+	Currency   string      `gorm:"column:currency;size:3" json:"currency"`
+	Subtotal   money.Money `gorm:"column:subtotal;type:bigint" json:"subtotal"`
+	Discount   money.Money `gorm:"column:discount;type:bigint" json:"discount"`
+	Tax        money.Money `gorm:"column:tax;type:bigint" json:"tax"`
+	Total      money.Money `gorm:"column:total;type:bigint" json:"total"`
+	// Taxes break Tax down per rate, Promotions list the discount of every promotion applied
+	Taxes      []OrderTax       `gorm:"column:taxes;serializer:json" json:"taxes"`
+	Promotions []OrderPromotion `gorm:"column:promotions;serializer:json" json:"promotions"`
+	// PointsEarned are the loyalty points the sale gave the customer, PointsBalance the points
+	// the customer had right after it
+	PointsEarned  int         `gorm:"column:points_earned" json:"points_earned"`
+	PointsBalance int         `gorm:"column:points_balance" json:"points_balance"`
+	Lines         []OrderLine `gorm:"foreignKey:OrderID" json:"lines"`
+	CreatedAt     time.Time   `gorm:"column:created_at;index" json:"created_at"`
+}
+
+// OrderLine is a product, or a variant of it, sold on an order
+type OrderLine struct {
+	OrderID    string      `gorm:"primaryKey;column:order_id" json:"order_id"`
+	Position   int         `gorm:"primaryKey;column:position" json:"position"`
+	ProductID  string      `gorm:"column:product_id;index" json:"product_id"`
+	VariantID  string      `gorm:"column:variant_id" json:"variant_id"`
+	Name       string      `gorm:"column:name;size:100" json:"name"`
+	TaxClassID string      `gorm:"column:tax_class_id;size:20" json:"tax_class_id"`
+	UnitPrice  money.Money `gorm:"column:unit_price;type:bigint" json:"unit_price"`
+	Quantity   int         `gorm:"column:quantity" json:"quantity"`
+	Subtotal   money.Money `gorm:"column:subtotal;type:bigint" json:"subtotal"`
+	Discount   money.Money `gorm:"column:discount;type:bigint" json:"discount"`
+	Tax        money.Money `gorm:"column:tax;type:bigint" json:"tax"`
+	Total      money.Money `gorm:"column:total;type:bigint" json:"total"`
+}
+
+// OrderTax is the tax of one rate on an order and the base it was computed on
+type OrderTax struct {
+	Code    string      `json:"code"`
+	Name    string      `json:"name"`
+	Percent float64     `json:"percent"`
+	Base    money.Money `json:"base"`
+	Tax     money.Money `json:"tax"`
+}
+
+// OrderPromotion is the discount a promotion gave on an order
+type OrderPromotion struct {
+	PromotionID string      `json:"promotion_id"`
+	Name        string      `json:"name"`
+	Discount    money.Money `json:"discount"`
+}
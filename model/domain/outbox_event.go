@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// OutboxEvent is a change event written in the same transaction as the change itself and
+// relayed to the subscribers afterwards
+type OutboxEvent struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Type        string     `gorm:"column:type;size:100;index" json:"type"`
+	Payload     string     `gorm:"column:payload;type:text" json:"payload"`
+	OccurredAt  time.Time  `gorm:"column:occurred_at" json:"occurred_at"`
+	PublishedAt *time.Time `gorm:"column:published_at;index" json:"published_at"`
+	// Sequence numbers the events in the order they were published, which is not the order of
+	// their IDs: an ID is taken when the event is written, not when its transaction commits
+	Sequence *uint64 `gorm:"column:sequence;uniqueIndex" json:"sequence"`
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Status of a Payment
+const (
+	// PaymentPending payments wait for a card charge the gateway has not settled yet
+	PaymentPending = "pending"
+	PaymentPaid    = "paid"
+	PaymentFailed  = "failed"
+)
+
+// Methods of a PaymentTender
+const (
+	TenderCash   = "cash"
+	TenderCard   = "card"
+	TenderPoints = "points"
+)
+
+// Payment settles an order with one or more tenders, e.g. cash and card and loyalty points of
+// the customer of the order
+type Payment struct {
+	PaymentID  string          `gorm:"primaryKey;column:payment_id" json:"payment_id"`
+	OrderID    string          `gorm:"column:order_id;index" json:"order_id"`
+	StoreID    string          `gorm:"column:store_id;size:20;index" json:"store_id"`
+	CustomerID string          `gorm:"column:customer_id;index" json:"customer_id"`
+	Amount     money.Money     `gorm:"column:amount;type:bigint" json:"amount"`
+	Status     string          `gorm:"column:status;size:10;index" json:"status"`
+	Version    int             `gorm:"column:version" json:"version"`
+	Tenders    []PaymentTender `gorm:"foreignKey:PaymentID" json:"tenders"`
+	CreatedAt  time.Time       `gorm:"column:created_at;index" json:"created_at"`
+	UpdatedAt  time.Time       `gorm:"column:updated_at" json:"updated_at"`
+	// ActiveOrderID is OrderID until the payment fails, its unique index keeps one payment per
+	// order that is not failed
+	ActiveOrderID *string `gorm:"column:active_order_id;size:64;uniqueIndex" json:"-"`
+}
+
+// PaymentTender is the part of a payment made with one method. Cash records what the customer
+// handed over and the change, points the loyalty points redeemed and card the charge of the
+// gateway.
+type PaymentTender struct {
+	PaymentID string      `gorm:"primaryKey;column:payment_id" json:"payment_id"`
+	Position  int         `gorm:"primaryKey;column:position" json:"position"`
+	Method    string      `gorm:"column:method;size:10" json:"method"`
+	Amount    money.Money `gorm:"column:amount;type:bigint" json:"amount"`
+	Tendered  money.Money `gorm:"column:tendered;type:bigint" json:"tendered"`
+	Change    money.Money `gorm:"column:change_due;type:bigint" json:"change"`
+	Points    int         `gorm:"column:points" json:"points"`
+	// Refunded is the part of Amount given back by returns
+	Refunded money.Money `gorm:"column:refunded;type:bigint" json:"refunded"`
+	Provider string      `gorm:"column:provider;size:20" json:"provider"`
+	ChargeID string      `gorm:"column:charge_id;size:64;index" json:"charge_id"`
+	// Status is one of the payment.Status* values
+	Status        string `gorm:"column:status;size:10" json:"status"`
+	DeclineReason string `gorm:"column:decline_reason;size:100" json:"decline_reason"`
+}
+
+// PaymentNotification records the notifications of the gateways already processed, a
+// notification delivered again is ignored
+type PaymentNotification struct {
+	Provider       string    `gorm:"primaryKey;column:provider;size:20" json:"provider"`
+	NotificationID string    `gorm:"primaryKey;column:notification_id;size:64" json:"notification_id"`
+	ChargeID       string    `gorm:"column:charge_id;size:64" json:"charge_id"`
+	Status         string    `gorm:"column:status;size:10" json:"status"`
+	ReceivedAt     time.Time `gorm:"column:received_at" json:"received_at"`
+}
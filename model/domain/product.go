@@ -1,12 +1,96 @@
 package domain
 
+import (
+	"encoding/json"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
 type Product struct {
-	ProductID   string  `gorm:"primaryKey;column:product_id" json:"product_id"`
-	Name        string  `gorm:"column:name" json:"name"`
-	Description string  `gorm:"column:description" json:"description"`
-	Price       float64 `gorm:"column:price" json:"price"`
-	StockQty    int     `gorm:"column:stock_qty" json:"stock_qty"`
-	Category    string  `gorm:"column:category" json:"category"`
-	SKU         string  `gorm:"column:sku" json:"sku"`
-	TaxRate     float64 `gorm:"column:tax_rate" json:"tax_rate"`
+	ProductID   string      `gorm:"primaryKey;column:product_id" json:"product_id"`
+	Name        string      `gorm:"column:name" json:"name"`
+	Description string      `gorm:"column:description" json:"description"`
+	Price       money.Money `gorm:"column:price;type:bigint" json:"price"`
+	// Currency is the currency of Price and of the prices of the variants, the price columns
+	// only hold minor units. It is kept in step with Price by the hooks.
+	Currency string `gorm:"column:currency;size:3;default:IDR" json:"currency"`
+	StockQty int    `gorm:"column:stock_qty" json:"stock_qty"`
+	Category string `gorm:"column:category" json:"category"`
+	SKU      string `gorm:"column:sku" json:"sku"`
+	// TaxClassID refers to the TaxClass of the product, TaxRate is the total percent of that
+	// class kept alongside for the readers that only need one number
+	TaxClassID string  `gorm:"column:tax_class_id;size:20;index" json:"tax_class_id"`
+	TaxRate    float64 `gorm:"column:tax_rate" json:"tax_rate"`
+	// Options are the axes the variants differ by, e.g. size and colour
+	Options  []ProductOption  `gorm:"foreignKey:ProductID" json:"options"`
+	Variants []ProductVariant `gorm:"foreignKey:ProductID" json:"variants"`
+}
+
+// UnmarshalJSON reads the prices in the currency of the product, a price alone has none
+func (product *Product) UnmarshalJSON(data []byte) error {
+	type plain Product
+	var decoded struct {
+		plain
+		Price    json.RawMessage `json:"price"`
+		Variants []struct {
+			ProductVariant
+			PriceOverride json.RawMessage `json:"price_override"`
+		} `json:"variants"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	currency := money.DefaultCurrency
+	if _, ok := money.Lookup(decoded.Currency); ok {
+		currency = decoded.Currency
+	}
+	price := func(raw json.RawMessage) (*money.Money, error) {
+		if len(raw) == 0 || string(raw) == "null" {
+			return nil, nil
+		}
+		amount := money.New(0, currency)
+		return &amount, json.Unmarshal(raw, &amount)
+	}
+
+	*product = Product(decoded.plain)
+	if amount, err := price(decoded.Price); err != nil {
+		return err
+	} else if amount != nil {
+		product.Price = *amount
+	}
+	product.Variants = nil
+	for _, variant := range decoded.Variants {
+		override, err := price(variant.PriceOverride)
+		if err != nil {
+			return err
+		}
+		variant.ProductVariant.PriceOverride = override
+		product.Variants = append(product.Variants, variant.ProductVariant)
+	}
+	return nil
+}
+
+type ProductOption struct {
+	ProductID string `gorm:"primaryKey;column:product_id" json:"product_id"`
+	Name      string `gorm:"primaryKey;column:name;size:30" json:"name"`
+	Position  int    `gorm:"column:position" json:"position"`
+}
+
+// ProductVariant is one sellable combination of option values of a product, with its own SKU
+// and stock. A nil PriceOverride sells it at the price of the product.
+type ProductVariant struct {
+	VariantID     string                 `gorm:"primaryKey;column:variant_id" json:"variant_id"`
+	ProductID     string                 `gorm:"column:product_id;index" json:"product_id"`
+	SKU           string                 `gorm:"column:sku;size:50;uniqueIndex" json:"sku"`
+	Barcode       string                 `gorm:"column:barcode;size:50" json:"barcode"`
+	PriceOverride *money.Money           `gorm:"column:price_override;type:bigint" json:"price_override"`
+	StockQty      int                    `gorm:"column:stock_qty" json:"stock_qty"`
+	Position      int                    `gorm:"column:position" json:"position"`
+	Options       []ProductVariantOption `gorm:"foreignKey:VariantID" json:"options"`
+}
+
+type ProductVariantOption struct {
+	VariantID string `gorm:"primaryKey;column:variant_id" json:"variant_id"`
+	Name      string `gorm:"primaryKey;column:name;size:30" json:"name"`
+	Value     string `gorm:"column:value;size:50;index" json:"value"`
 }
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Types of Promotion
+const (
+	// PromotionPercentage takes Value percent off the targeted lines
+	PromotionPercentage = "percentage"
+	// PromotionFixedAmount takes Amount off every targeted unit
+	PromotionFixedAmount = "fixed_amount"
+	// PromotionBuyXGetY gives GetQty units free for every BuyQty units bought of a product
+	PromotionBuyXGetY = "buy_x_get_y"
+	// PromotionBundlePrice sells every BuyQty units of a product for Amount
+	PromotionBundlePrice = "bundle_price"
+)
+
+// Promotion is a discount rule. It targets the products of ProductIDs and Categories, every
+// product when both are empty, and the customers of CustomerIDs, everyone when empty.
+type Promotion struct {
+	PromotionID string `gorm:"primaryKey;column:promotion_id" json:"promotion_id"`
+	Name        string `gorm:"column:name;size:100" json:"name"`
+	Type        string `gorm:"column:type;size:20" json:"type"`
+	// Value is the percent of a percentage promotion
+	Value float64 `gorm:"column:value" json:"value"`
+	// Amount is the amount off or the price of the bundle of the promotions of an amount, in
+	// DefaultCurrency: they do not apply to the products of another currency
+	Amount      money.Money `gorm:"column:amount;type:bigint;default:0" json:"amount"`
+	BuyQty      int         `gorm:"column:buy_qty" json:"buy_qty"`
+	GetQty      int         `gorm:"column:get_qty" json:"get_qty"`
+	ProductIDs  []string    `gorm:"column:product_ids;serializer:json" json:"product_ids"`
+	Categories  []string    `gorm:"column:categories;serializer:json" json:"categories"`
+	CustomerIDs []string    `gorm:"column:customer_ids;serializer:json" json:"customer_ids"`
+	// StartsAt and EndsAt bound the validity window, nil leaves it open on that side
+	StartsAt *time.Time `gorm:"column:starts_at" json:"starts_at"`
+	EndsAt   *time.Time `gorm:"column:ends_at" json:"ends_at"`
+	// Priority orders the promotions, the highest is applied first
+	Priority int `gorm:"column:priority" json:"priority"`
+	// Stackable promotions combine on a line, the others only apply to a line no promotion
+	// discounted yet and keep the later ones off it
+	Stackable bool `gorm:"column:stackable" json:"stackable"`
+	// UsageLimit is the number of sales the promotion can be used in, 0 is unlimited
+	UsageLimit int       `gorm:"column:usage_limit" json:"usage_limit"`
+	UsageCount int       `gorm:"column:usage_count" json:"usage_count"`
+	Active     bool      `gorm:"column:active" json:"active"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// HasAmount tells whether a type of promotion is defined by an Amount rather than a Value
+func HasAmount(promotionType string) bool {
+	return promotionType == PromotionFixedAmount || promotionType == PromotionBundlePrice
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Status of a Return
+const (
+	// ReturnPending returns refund more than the approval threshold, they wait for a supervisor
+	ReturnPending = "pending"
+	// ReturnRefunding returns are restocked and wait for the gateway to give the cards back
+	ReturnRefunding = "refunding"
+	ReturnCompleted = "completed"
+	ReturnRejected  = "rejected"
+)
+
+// Reasons of a ReturnLine
+const (
+	ReasonDamaged        = "damaged"
+	ReasonDefective      = "defective"
+	ReasonWrongItem      = "wrong_item"
+	ReasonNotAsDescribed = "not_as_described"
+	ReasonExpired        = "expired"
+	ReasonChangedMind    = "changed_mind"
+)
+
+// Dispositions of a ReturnLine: the returned goods go back on the shelf or are written off
+const (
+	DispositionRestock  = "restock"
+	DispositionWriteOff = "write_off"
+)
+
+// Return takes back products sold on an order. The refund is the part of what the customer paid
+// for the lines returned, after their discount and with their tax, and the customer loses the
+// loyalty points the refunded amount had earned.
+type Return struct {
+	ReturnID   string `gorm:"primaryKey;column:return_id" json:"return_id"`
+	OrderID    string `gorm:"column:order_id;index" json:"order_id"`
+	StoreID    string `gorm:"column:store_id;size:20;index" json:"store_id"`
+	CustomerID string `gorm:"column:customer_id;index" json:"customer_id"`
+	EmployeeID string `gorm:"column:employee_id" json:"employee_id"`
+	// ApprovedBy is the supervisor who approved or rejected the return, empty below the threshold
+	ApprovedBy string      `gorm:"column:approved_by" json:"approved_by"`
+	Status     string      `gorm:"column:status;size:10;index" json:"status"`
+	Note       string      `gorm:"column:note;size:255" json:"note"`
+	Subtotal   money.Money `gorm:"column:subtotal;type:bigint" json:"subtotal"`
+	Discount   money.Money `gorm:"column:discount;type:bigint" json:"discount"`
+	Tax        money.Money `gorm:"column:tax;type:bigint" json:"tax"`
+	Refund     money.Money `gorm:"column:refund;type:bigint" json:"refund"`
+	// PointsReversed are the loyalty points taken back from the customer
+	PointsReversed int `gorm:"column:points_reversed" json:"points_reversed"`
+	// Refunds tell how the refund was paid back, once the return is completed
+	Refunds     []ReturnRefund `gorm:"column:refunds;serializer:json" json:"refunds"`
+	Version     int            `gorm:"column:version" json:"version"`
+	Lines       []ReturnLine   `gorm:"foreignKey:ReturnID" json:"lines"`
+	CreatedAt   time.Time      `gorm:"column:created_at;index" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at" json:"updated_at"`
+	CompletedAt *time.Time     `gorm:"column:completed_at" json:"completed_at"`
+}
+
+// ReturnLine is a quantity of an order line returned
+type ReturnLine struct {
+	ReturnID string `gorm:"primaryKey;column:return_id" json:"return_id"`
+	Position int    `gorm:"primaryKey;column:position" json:"position"`
+	// OrderPosition is the position of the order line returned
+	OrderPosition int         `gorm:"column:order_position" json:"order_position"`
+	ProductID     string      `gorm:"column:product_id;index" json:"product_id"`
+	VariantID     string      `gorm:"column:variant_id" json:"variant_id"`
+	Name          string      `gorm:"column:name;size:100" json:"name"`
+	Quantity      int         `gorm:"column:quantity" json:"quantity"`
+	Reason        string      `gorm:"column:reason;size:20" json:"reason"`
+	Disposition   string      `gorm:"column:disposition;size:10" json:"disposition"`
+	Subtotal      money.Money `gorm:"column:subtotal;type:bigint" json:"subtotal"`
+	Discount      money.Money `gorm:"column:discount;type:bigint" json:"discount"`
+	Tax           money.Money `gorm:"column:tax;type:bigint" json:"tax"`
+	Refund        money.Money `gorm:"column:refund;type:bigint" json:"refund"`
+}
+
+// ReturnRefund is the part of a refund paid back with one method of the payment of the order
+type ReturnRefund struct {
+	Method   string      `json:"method"`
+	Amount   money.Money `json:"amount"`
+	Points   int         `json:"points,omitempty"`
+	ChargeID string      `json:"charge_id,omitempty"`
+	// Pending card refunds are not given back by the gateway yet
+	Pending bool `json:"pending,omitempty"`
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Store is an outlet of the business, identified by a short code such as JKT01
+type Store struct {
+	StoreID string `gorm:"primaryKey;column:store_id" json:"store_id"`
+	Name    string `gorm:"column:name;size:100" json:"name"`
+	Address string `gorm:"column:address" json:"address"`
+	Phone   string `gorm:"column:phone;size:20" json:"phone"`
+	// PriceMode tells whether the prices of the store include the tax, see package tax
+	PriceMode string `gorm:"column:price_mode;size:10;default:exclusive" json:"price_mode"`
+	// TaxRounding rounds the tax of every line or once per rate over the whole sale
+	TaxRounding string `gorm:"column:tax_rounding;size:10;default:line" json:"tax_rounding"`
+	// ReceiptHeader and ReceiptFooter are printed below the address and at the bottom of the
+	// receipts, one line per line of text. ReceiptQR is the content of the QR code printed at the
+	// bottom, {order_id} is replaced by the order; no QR code is printed when it is empty.
+	ReceiptHeader string    `gorm:"column:receipt_header;size:500" json:"receipt_header"`
+	ReceiptFooter string    `gorm:"column:receipt_footer;size:500" json:"receipt_footer"`
+	ReceiptQR     string    `gorm:"column:receipt_qr;size:255" json:"receipt_qr"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// StoreStock is the stock of a product in one store. Product.StockQty stays the central stock.
+// The stock of a product with variants is the total of its StoreVariantStock rows.
+type StoreStock struct {
+	StoreID   string `gorm:"primaryKey;column:store_id" json:"store_id"`
+	ProductID string `gorm:"primaryKey;column:product_id;index" json:"product_id"`
+	StockQty  int    `gorm:"column:stock_qty" json:"stock_qty"`
+	// PriceOverride replaces Product.Price in this store when set
+	PriceOverride *money.Money `gorm:"column:price_override;type:bigint" json:"price_override"`
+	UpdatedAt     time.Time    `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// StoreVariantStock is the stock of a variant in one store, ProductVariant.StockQty stays the
+// central stock
+type StoreVariantStock struct {
+	StoreID   string    `gorm:"primaryKey;column:store_id" json:"store_id"`
+	VariantID string    `gorm:"primaryKey;column:variant_id" json:"variant_id"`
+	ProductID string    `gorm:"column:product_id;index" json:"product_id"`
+	StockQty  int       `gorm:"column:stock_qty" json:"stock_qty"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// EmployeeStore assigns an employee to a store, an employee may work in several stores
+type EmployeeStore struct {
+	EmployeeID string `gorm:"primaryKey;column:employee_id" json:"employee_id"`
+	StoreID    string `gorm:"primaryKey;column:store_id;index" json:"store_id"`
+}
+
+// Status of a StockTransfer
+const (
+	TransferDraft             = "draft"
+	TransferDispatched        = "dispatched"
+	TransferPartiallyReceived = "partially_received"
+	TransferReceived          = "received"
+	TransferCancelled         = "cancelled"
+)
+
+// OpenTransferStatuses are the statuses of the transfers that still expect an action
+var OpenTransferStatuses = []string{TransferDraft, TransferDispatched, TransferPartiallyReceived}
+
+// StockTransfer moves stock between two locations. An empty store ID stands for the central
+// stock kept in Product.StockQty. Stock leaves the source on dispatch and reaches the
+// destination as it is received.
+type StockTransfer struct {
+	TransferID         string              `gorm:"primaryKey;column:transfer_id" json:"transfer_id"`
+	SourceStoreID      string              `gorm:"column:source_store_id;size:20;index" json:"source_store_id"`
+	DestinationStoreID string              `gorm:"column:destination_store_id;size:20;index" json:"destination_store_id"`
+	Status             string              `gorm:"column:status;size:20;index" json:"status"`
+	Note               string              `gorm:"column:note;size:500" json:"note"`
+	Version            int                 `gorm:"column:version" json:"version"`
+	Lines              []StockTransferLine `gorm:"foreignKey:TransferID" json:"lines"`
+	CreatedAt          time.Time           `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt          time.Time           `gorm:"column:updated_at" json:"updated_at"`
+	DispatchedAt       *time.Time          `gorm:"column:dispatched_at" json:"dispatched_at"`
+	ReceivedAt         *time.Time          `gorm:"column:received_at" json:"received_at"`
+}
+
+// StockTransferLine is the quantity of one product, or a variant of it, on a transfer.
+// DiscrepancyQty is what was dispatched but never received, it is recorded when the transfer is
+// closed.
+type StockTransferLine struct {
+	TransferID        string `gorm:"primaryKey;column:transfer_id" json:"transfer_id"`
+	ProductID         string `gorm:"primaryKey;column:product_id" json:"product_id"`
+	VariantID         string `gorm:"primaryKey;column:variant_id;size:36;default:''" json:"variant_id"`
+	Quantity          int    `gorm:"column:quantity" json:"quantity"`
+	ReceivedQty       int    `gorm:"column:received_qty" json:"received_qty"`
+	DiscrepancyQty    int    `gorm:"column:discrepancy_qty" json:"discrepancy_qty"`
+	DiscrepancyReason string `gorm:"column:discrepancy_reason;size:255" json:"discrepancy_reason"`
+}
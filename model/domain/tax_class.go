@@ -0,0 +1,25 @@
+package domain
+
+// TaxClass groups the taxes charged on a product, e.g. PPN alone for most goods or PPN and
+// PPnBM for luxury goods. Products refer to it by TaxClassID.
+type TaxClass struct {
+	TaxClassID string    `gorm:"primaryKey;column:tax_class_id;size:20" json:"tax_class_id"`
+	Name       string    `gorm:"column:name;size:100" json:"name"`
+	Rates      []TaxRate `gorm:"column:rates;serializer:json" json:"rates"`
+}
+
+// TaxRate is one tax of a class, Percent is a percentage of the price before tax: 11 is 11%
+type TaxRate struct {
+	Code    string  `json:"code"`
+	Name    string  `json:"name"`
+	Percent float64 `json:"percent"`
+}
+
+// Percent is the total of the rates of the class
+func (class TaxClass) Percent() float64 {
+	total := 0.0
+	for _, rate := range class.Rates {
+		total += rate.Percent
+	}
+	return total
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Webhook is a subscription of an external URL to a set of event types
+type Webhook struct {
+	WebhookID  string    `gorm:"primaryKey;column:webhook_id" json:"webhook_id"`
+	URL        string    `gorm:"column:url;size:2048" json:"url"`
+	Secret     string    `gorm:"column:secret" json:"-"`
+	EventTypes []string  `gorm:"column:event_types;serializer:json" json:"event_types"`
+	Active     bool      `gorm:"column:active" json:"active"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// Status of a WebhookDelivery
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent to one webhook, with the outcome of its last attempt
+type WebhookDelivery struct {
+	DeliveryID    string    `gorm:"primaryKey;column:delivery_id" json:"delivery_id"`
+	WebhookID     string    `gorm:"column:webhook_id;index" json:"webhook_id"`
+	EventID       uint64    `gorm:"column:event_id" json:"event_id"`
+	EventType     string    `gorm:"column:event_type;size:100" json:"event_type"`
+	Payload       string    `gorm:"column:payload;type:text" json:"payload"`
+	Status        string    `gorm:"column:status;size:20;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts      int       `gorm:"column:attempts" json:"attempts"`
+	NextAttemptAt time.Time `gorm:"column:next_attempt_at;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	ResponseCode  int       `gorm:"column:response_code" json:"response_code"`
+	ResponseBody  string    `gorm:"column:response_body;type:text" json:"response_body"`
+	Error         string    `gorm:"column:error;type:text" json:"error"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package web
+
+import "time"
+
+type APIKeyCreateRequest struct {
+	Name        string   `validate:"required,min=1,max=100" json:"name"`
+	Permissions []string `validate:"required,min=1,dive,required" json:"permissions"`
+}
+
+type APIKeyResponse struct {
+	KeyID       string   `json:"key_id"`
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	Permissions []string `json:"permissions"`
+	// Key is only returned when the key is created
+	Key       string     `json:"key,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
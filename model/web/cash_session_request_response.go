@@ -0,0 +1,131 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+type CashSessionOpenRequest struct {
+	// StoreID is the store context of the request, not part of the body
+	StoreID string `json:"-"`
+	// EmployeeID may be left out when the key is bound to a single employee
+	EmployeeID   string      `json:"employee_id"`
+	OpeningFloat money.Money `json:"opening_float"`
+}
+
+// CashMovementRequest puts cash in or takes it out of the drawer of a session
+type CashMovementRequest struct {
+	SessionID  string      `json:"-"`
+	EmployeeID string      `json:"employee_id"`
+	Type       string      `validate:"required,oneof=cash_in cash_out" json:"type"`
+	Amount     money.Money `json:"amount"`
+	Reason     string      `validate:"required,max=255" json:"reason"`
+}
+
+// CashSessionCloseRequest closes a session with the cash counted in the drawer, a denomination
+// not listed was not found
+type CashSessionCloseRequest struct {
+	SessionID string `json:"-"`
+	// EmployeeID counts the drawer, the cashier or a supervisor
+	EmployeeID string             `json:"employee_id"`
+	Counts     []CashCountRequest `validate:"dive" json:"counts"`
+	Note       string             `validate:"max=255" json:"note,omitempty"`
+}
+
+type CashCountRequest struct {
+	Denomination money.Money `json:"denomination"`
+	Quantity     int         `validate:"min=0" json:"quantity"`
+}
+
+type CashMovementResponse struct {
+	Position   int         `json:"position"`
+	Type       string      `json:"type"`
+	Amount     money.Money `json:"amount"`
+	Reason     string      `json:"reason"`
+	EmployeeID string      `json:"employee_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type CashCountResponse struct {
+	Denomination money.Money `json:"denomination"`
+	Quantity     int         `json:"quantity"`
+	Amount       money.Money `json:"amount"`
+}
+
+type CashSessionResponse struct {
+	SessionID    string      `json:"session_id"`
+	StoreID      string      `json:"store_id"`
+	EmployeeID   string      `json:"employee_id"`
+	ClosedBy     string      `json:"closed_by,omitempty"`
+	Status       string      `json:"status"`
+	OpeningFloat money.Money `json:"opening_float"`
+	// Expected, Counted and Variance are only known once the session is closed, the X report of
+	// an open session tells what is expected so far
+	Expected  *money.Money           `json:"expected,omitempty"`
+	Counted   *money.Money           `json:"counted,omitempty"`
+	Variance  *money.Money           `json:"variance,omitempty"`
+	Counts    []CashCountResponse    `json:"counts"`
+	Note      string                 `json:"note,omitempty"`
+	Movements []CashMovementResponse `json:"movements"`
+	OpenedAt  time.Time              `json:"opened_at"`
+	ClosedAt  *time.Time             `json:"closed_at,omitempty"`
+}
+
+// CashReportResponse is the X report, while the sessions it covers are open, or the Z report,
+// once they are all closed, of a session or of the sessions of a store on a day
+type CashReportResponse struct {
+	Kind       string    `json:"kind"`
+	StoreID    string    `json:"store_id"`
+	SessionID  string    `json:"session_id,omitempty"`
+	EmployeeID string    `json:"employee_id,omitempty"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	// Orders are the sales of the period, Subtotal to Sales their amounts
+	Orders   int         `json:"orders"`
+	Subtotal money.Money `json:"subtotal"`
+	Discount money.Money `json:"discount"`
+	Tax      money.Money `json:"tax"`
+	Sales    money.Money `json:"sales"`
+	// Returns are the returns completed in the period, Refunds and RefundedTax their amounts
+	Returns     int                         `json:"returns"`
+	Refunds     money.Money                 `json:"refunds"`
+	RefundedTax money.Money                 `json:"refunded_tax"`
+	NetSales    money.Money                 `json:"net_sales"`
+	NetTax      money.Money                 `json:"net_tax"`
+	Taxes       []CashReportTaxResponse     `json:"taxes"`
+	Payments    []CashReportPaymentResponse `json:"payments"`
+	Cash        CashReportCashResponse      `json:"cash"`
+	// Sessions are the sessions of a store report
+	Sessions    []CashSessionResponse `json:"sessions,omitempty"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
+
+// CashReportTaxResponse is the tax of one rate collected on the sales
+type CashReportTaxResponse struct {
+	Code    string      `json:"code"`
+	Name    string      `json:"name"`
+	Percent float64     `json:"percent"`
+	Base    money.Money `json:"base"`
+	Tax     money.Money `json:"tax"`
+}
+
+// CashReportPaymentResponse is what was paid and refunded with a payment method
+type CashReportPaymentResponse struct {
+	Method  string      `json:"method"`
+	Sales   money.Money `json:"sales"`
+	Refunds money.Money `json:"refunds"`
+	Net     money.Money `json:"net"`
+}
+
+// CashReportCashResponse reconciles the cash of the drawers
+type CashReportCashResponse struct {
+	OpeningFloat money.Money  `json:"opening_float"`
+	Sales        money.Money  `json:"sales"`
+	Refunds      money.Money  `json:"refunds"`
+	CashIn       money.Money  `json:"cash_in"`
+	CashOut      money.Money  `json:"cash_out"`
+	Expected     money.Money  `json:"expected"`
+	Counted      *money.Money `json:"counted,omitempty"`
+	Variance     *money.Money `json:"variance,omitempty"`
+}
@@ -1,20 +1,22 @@
 package web
 
 type EmployeeCreateRequest struct {
-	Name      string `validate:"required,min=1,max=100" json:"name"`
-	Role      string `validate:"required,max=50" json:"role"`
-	Email     string `validate:"required,email" json:"email"`
-	Phone     string `validate:"required,max=20" json:"phone"`
-	DateHired string `validate:"required" json:"date_hired"`
+	Name      string   `validate:"required,min=1,max=100" json:"name"`
+	Role      string   `validate:"required,max=50" json:"role"`
+	Email     string   `validate:"required,email" json:"email"`
+	Phone     string   `validate:"required,max=20" json:"phone"`
+	DateHired string   `validate:"required" json:"date_hired"`
+	StoreIDs  []string `validate:"omitempty,dive,required" json:"store_ids,omitempty"`
 }
 
 type EmployeeResponse struct {
-	EmployeeID string `json:"employee_id"`
-	Name       string `json:"name"`
-	Role       string `json:"role"`
-	Email      string `json:"email"`
-	Phone      string `json:"phone"`
-	DateHired  string `json:"date_hired"`
+	EmployeeID string   `json:"employee_id"`
+	Name       string   `json:"name"`
+	Role       string   `json:"role"`
+	Email      string   `json:"email"`
+	Phone      string   `json:"phone"`
+	DateHired  string   `json:"date_hired"`
+	StoreIDs   []string `json:"store_ids"`
 }
 
 type EmployeeUpdateRequest struct {
@@ -24,4 +26,6 @@ type EmployeeUpdateRequest struct {
 	Email      string `validate:"required,email" json:"email"`
 	Phone      string `validate:"required,max=20" json:"phone"`
 	DateHired  string `validate:"required" json:"date_hired"`
+	// StoreIDs replaces the stores of the employee, they are kept when it is null
+	StoreIDs []string `validate:"omitempty,dive,required" json:"store_ids,omitempty"`
 }
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+type OrderCreateRequest struct {
+	// StoreID is taken from the store context, the order is priced like a quote of the store
+	StoreID    string `json:"-"`
+	EmployeeID string `validate:"required" json:"employee_id"`
+	// CustomerID earns the customer loyalty points and the promotions reserved to them
+	CustomerID string             `json:"customer_id,omitempty"`
+	Lines      []QuoteLineRequest `validate:"required,min=1,dive" json:"lines"`
+}
+
+type OrderLineResponse struct {
+	// Position numbers the lines from 1, a return refers to a line by its position
+	Position  int         `json:"position"`
+	ProductID string      `json:"product_id"`
+	VariantID string      `json:"variant_id,omitempty"`
+	Name      string      `json:"name"`
+	TaxClass  string      `json:"tax_class"`
+	UnitPrice money.Money `json:"unit_price"`
+	Quantity  int         `json:"quantity"`
+	Subtotal  money.Money `json:"subtotal"`
+	Discount  money.Money `json:"discount"`
+	Tax       money.Money `json:"tax"`
+	Total     money.Money `json:"total"`
+}
+
+type OrderResponse struct {
+	OrderID    string                     `json:"order_id"`
+	StoreID    string                     `json:"store_id"`
+	EmployeeID string                     `json:"employee_id"`
+	CustomerID string                     `json:"customer_id,omitempty"`
+	PriceMode  string                     `json:"price_mode"`
+	Currency   string                     `json:"currency"`
+	Lines      []OrderLineResponse        `json:"lines"`
+	Promotions []AppliedPromotionResponse `json:"promotions"`
+	Subtotal   money.Money                `json:"subtotal"`
+	Discount   money.Money                `json:"discount"`
+	Tax        money.Money                `json:"tax"`
+	Taxes      []TaxAmountResponse        `json:"taxes"`
+	Total      money.Money                `json:"total"`
+	// PointsEarned are the loyalty points of the order, PointsBalance those of the customer
+	// right after it
+	PointsEarned  int       `json:"points_earned"`
+	PointsBalance int       `json:"points_balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,86 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+type QRISCreateRequest struct {
+	// OrderID asks for the total of an order, Amount for any amount; without either the payload
+	// is static and the customer enters the amount
+	OrderID string       `json:"order_id,omitempty"`
+	Amount  *money.Money `json:"amount,omitempty"`
+	// Reference is the bill number of the payment, the order number by default
+	Reference string `validate:"max=25" json:"reference,omitempty"`
+	// Image adds the QR code as a data URI
+	Image string `validate:"omitempty,oneof=png svg" json:"image,omitempty"`
+}
+
+type QRISVerifyRequest struct {
+	Payload string `validate:"required" json:"payload"`
+}
+
+type QRISResponse struct {
+	Payload string `json:"payload"`
+	// Amount is zero in a static payload
+	Amount       money.Money `json:"amount"`
+	Currency     string      `json:"currency"`
+	Reference    string      `json:"reference,omitempty"`
+	MerchantName string      `json:"merchant_name"`
+	MerchantCity string      `json:"merchant_city"`
+	NMID         string      `json:"nmid"`
+	// OwnMerchant tells whether the payload pays the merchant of this application
+	OwnMerchant bool   `json:"own_merchant"`
+	Image       string `json:"image,omitempty"`
+}
+
+type PaymentCreateRequest struct {
+	OrderID string          `validate:"required" json:"order_id"`
+	Tenders []TenderRequest `validate:"required,min=1,dive" json:"tenders"`
+}
+
+// TenderRequest is a part of a payment: an amount in cash or by card, or loyalty points
+type TenderRequest struct {
+	Method string       `validate:"required,oneof=cash card points" json:"method"`
+	Amount *money.Money `json:"amount,omitempty"`
+	// Tendered is the cash handed over by the customer, the amount by default
+	Tendered *money.Money `json:"tendered,omitempty"`
+	// Token is the card as tokenized by the terminal
+	Token  string `json:"token,omitempty"`
+	Points int    `validate:"min=0" json:"points,omitempty"`
+}
+
+type TenderResponse struct {
+	Method   string      `json:"method"`
+	Amount   money.Money `json:"amount"`
+	Tendered money.Money `json:"tendered"`
+	Change   money.Money `json:"change"`
+	Points   int         `json:"points"`
+	// Refunded is the part of Amount given back by returns
+	Refunded money.Money `json:"refunded"`
+	Provider string      `json:"provider,omitempty"`
+	ChargeID string      `json:"charge_id,omitempty"`
+	Status   string      `json:"status"`
+	// DeclineReason tells why the gateway declined a card
+	DeclineReason string `json:"decline_reason,omitempty"`
+}
+
+type PaymentResponse struct {
+	PaymentID  string           `json:"payment_id"`
+	OrderID    string           `json:"order_id"`
+	StoreID    string           `json:"store_id"`
+	CustomerID string           `json:"customer_id,omitempty"`
+	Amount     money.Money      `json:"amount"`
+	Status     string           `json:"status"`
+	Tenders    []TenderResponse `json:"tenders"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+type PaymentNotificationResponse struct {
+	NotificationID string `json:"notification_id"`
+	PaymentID      string `json:"payment_id"`
+	// Duplicate notifications were processed before and are ignored
+	Duplicate bool `json:"duplicate"`
+}
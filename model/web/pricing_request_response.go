@@ -0,0 +1,60 @@
+package web
+
+import "github.com/aronipurwanto/go-restful-api/money"
+
+type QuoteLineRequest struct {
+	ProductID string `validate:"required" json:"product_id"`
+	// VariantID prices a variant of the product instead of the product itself
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int    `validate:"required,min=1" json:"quantity"`
+}
+
+type QuoteRequest struct {
+	// StoreID is taken from the store context, the quote then uses the prices and the tax
+	// settings of the store
+	StoreID string `json:"-"`
+	// CustomerID enables the promotions reserved to the customer
+	CustomerID string             `json:"customer_id,omitempty"`
+	Lines      []QuoteLineRequest `validate:"required,min=1,dive" json:"lines"`
+}
+
+type AppliedPromotionResponse struct {
+	PromotionID string      `json:"promotion_id"`
+	Name        string      `json:"name"`
+	Discount    money.Money `json:"discount"`
+}
+
+type QuoteLineResponse struct {
+	ProductID  string                     `json:"product_id"`
+	VariantID  string                     `json:"variant_id,omitempty"`
+	Name       string                     `json:"name"`
+	UnitPrice  money.Money                `json:"unit_price"`
+	Quantity   int                        `json:"quantity"`
+	Subtotal   money.Money                `json:"subtotal"`
+	Discount   money.Money                `json:"discount"`
+	TaxClass   string                     `json:"tax_class"`
+	TaxRate    float64                    `json:"tax_rate"`
+	Tax        money.Money                `json:"tax"`
+	Taxes      []TaxAmountResponse        `json:"taxes"`
+	Total      money.Money                `json:"total"`
+	Promotions []AppliedPromotionResponse `json:"promotions"`
+}
+
+type QuoteResponse struct {
+	StoreID    string                     `json:"store_id,omitempty"`
+	CustomerID string                     `json:"customer_id,omitempty"`
+	Lines      []QuoteLineResponse        `json:"lines"`
+	Promotions []AppliedPromotionResponse `json:"promotions"`
+	// PriceMode is inclusive when the unit prices and the subtotal already contain the tax
+	PriceMode string `json:"price_mode"`
+	// Currency is the currency of every amount of the quote
+	Currency string      `json:"currency"`
+	Subtotal money.Money `json:"subtotal"`
+	Discount money.Money `json:"discount"`
+	Tax      money.Money `json:"tax"`
+	// Taxes break Tax down per rate
+	Taxes []TaxAmountResponse `json:"taxes"`
+	Total money.Money         `json:"total"`
+	// CashTotal is Total rounded to what can be paid in cash
+	CashTotal money.Money `json:"cash_total"`
+}
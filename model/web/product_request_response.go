@@ -1,33 +1,84 @@
 package web
 
+import "github.com/aronipurwanto/go-restful-api/money"
+
 type ProductCreateRequest struct {
-	Name        string  `validate:"required,min=1,max=100" json:"name"`
-	Description string  `validate:"max=500" json:"description"`
-	Price       float64 `validate:"required,min=0" json:"price"`
-	StockQty    int     `validate:"required,min=0" json:"stock_qty"`
-	Category    string  `validate:"required" json:"category"`
-	SKU         string  `validate:"required,max=50" json:"sku"`
-	TaxRate     float64 `validate:"min=0" json:"tax_rate"`
+	Name        string `validate:"required,min=1,max=100" json:"name"`
+	Description string `validate:"max=500" json:"description"`
+	// Price is a decimal string such as "35000", a number is still accepted. The service checks
+	// it is positive, the validator does not see into a Money.
+	Price money.Money `json:"price"`
+	// StockQty is the total of the variants when the product has variants
+	StockQty int    `validate:"required_without=Variants,min=0" json:"stock_qty"`
+	Category string `validate:"required" json:"category"`
+	SKU      string `validate:"required,max=50" json:"sku"`
+	// TaxClass is the ID of a tax class. Without it the product takes the class whose total
+	// rate is TaxRate, so 0 is exempt and 11 is PPN.
+	TaxClass string  `validate:"max=20" json:"tax_class"`
+	TaxRate  float64 `validate:"min=0" json:"tax_rate"`
+	// Options name the axes the variants differ by, e.g. ["size", "colour"]
+	Options  []string                `validate:"required_with=Variants,unique,dive,required,max=30" json:"options,omitempty"`
+	Variants []ProductVariantRequest `validate:"dive" json:"variants,omitempty"`
+}
+
+type ProductVariantRequest struct {
+	// VariantID keeps an existing variant on update, a new one is generated when empty
+	VariantID     string       `json:"variant_id,omitempty"`
+	SKU           string       `validate:"required,max=50" json:"sku"`
+	Barcode       string       `validate:"max=50" json:"barcode"`
+	PriceOverride *money.Money `json:"price_override"`
+	StockQty      int          `validate:"min=0" json:"stock_qty"`
+	// Options give the value of every option of the product, e.g. {"size": "M", "colour": "Merah"}
+	Options map[string]string `validate:"required,dive,keys,required,max=30,endkeys,required,max=50" json:"options"`
 }
 
 type ProductResponse struct {
-	ProductID   string  `json:"product_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	StockQty    int     `json:"stock_qty"`
-	Category    string  `json:"category"`
-	SKU         string  `json:"sku"`
-	TaxRate     float64 `json:"tax_rate"`
+	ProductID   string `json:"product_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Price is a number until the clients have moved to PriceAmount, the exact decimal string
+	Price       float64     `json:"price"`
+	PriceAmount money.Money `json:"price_amount"`
+	Currency    string      `json:"currency"`
+	StockQty    int         `json:"stock_qty"`
+	Category    string      `json:"category"`
+	SKU         string      `json:"sku"`
+	TaxClass    string      `json:"tax_class"`
+	// TaxRate is the total percent of the tax class
+	TaxRate float64 `json:"tax_rate"`
+	// StoreID is set when the product is read in a store context, StockQty and Price are then
+	// the stock and the price of that store
+	StoreID string `json:"store_id,omitempty"`
+	// Options and Variants are left out for a product without variants
+	Options  []string                 `json:"options,omitempty"`
+	Variants []ProductVariantResponse `json:"variants,omitempty"`
+}
+
+type ProductVariantResponse struct {
+	VariantID string `json:"variant_id"`
+	SKU       string `json:"sku"`
+	Barcode   string `json:"barcode"`
+	// Price is PriceOverride or else the price of the product, the numbers stay until the
+	// clients have moved to the amounts
+	Price               float64           `json:"price"`
+	PriceAmount         money.Money       `json:"price_amount"`
+	PriceOverride       *float64          `json:"price_override"`
+	PriceOverrideAmount *money.Money      `json:"price_override_amount"`
+	StockQty            int               `json:"stock_qty"`
+	Options             map[string]string `json:"options"`
 }
 
 type ProductUpdateRequest struct {
-	ProductID   string  `validate:"required" json:"product_id"`
-	Name        string  `validate:"required,max=100,min=1" json:"name"`
-	Description string  `validate:"max=500" json:"description"`
-	Price       float64 `validate:"required,min=0" json:"price"`
-	StockQty    int     `validate:"required,min=0" json:"stock_qty"`
-	Category    string  `validate:"required" json:"category"`
-	SKU         string  `validate:"required,max=50" json:"sku"`
-	TaxRate     float64 `validate:"min=0" json:"tax_rate"`
+	ProductID   string      `validate:"required" json:"product_id"`
+	Name        string      `validate:"required,max=100,min=1" json:"name"`
+	Description string      `validate:"max=500" json:"description"`
+	Price       money.Money `json:"price"`
+	StockQty    int         `validate:"required_without=Variants,min=0" json:"stock_qty"`
+	Category    string      `validate:"required" json:"category"`
+	SKU         string      `validate:"required,max=50" json:"sku"`
+	TaxClass    string      `validate:"max=20" json:"tax_class"`
+	TaxRate     float64     `validate:"min=0" json:"tax_rate"`
+	// Options and Variants replace those of the product, nil Variants keep them unchanged
+	Options  []string                `validate:"required_with=Variants,unique,dive,required,max=30" json:"options,omitempty"`
+	Variants []ProductVariantRequest `validate:"dive" json:"variants,omitempty"`
 }
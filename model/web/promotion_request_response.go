@@ -0,0 +1,71 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+type PromotionCreateRequest struct {
+	Name string `validate:"required,max=100" json:"name"`
+	Type string `validate:"required,oneof=percentage fixed_amount buy_x_get_y bundle_price" json:"type"`
+	// Value is the percentage of a percentage promotion. It is still read as the amount when
+	// Amount is left out, as sent by the clients written before Amount.
+	Value float64 `validate:"min=0" json:"value"`
+	// Amount is the amount off per unit of fixed_amount or the price of a bundle_price, a
+	// decimal string
+	Amount money.Money `json:"amount"`
+	// BuyQty and GetQty are X and Y of buy_x_get_y, BuyQty is the size of a bundle
+	BuyQty      int        `validate:"min=0" json:"buy_qty"`
+	GetQty      int        `validate:"min=0" json:"get_qty"`
+	ProductIDs  []string   `validate:"dive,required" json:"product_ids"`
+	Categories  []string   `validate:"dive,required" json:"categories"`
+	CustomerIDs []string   `validate:"dive,required" json:"customer_ids"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Priority    int        `json:"priority"`
+	Stackable   bool       `json:"stackable"`
+	UsageLimit  int        `validate:"min=0" json:"usage_limit"`
+}
+
+type PromotionUpdateRequest struct {
+	PromotionID string      `validate:"required" json:"promotion_id"`
+	Name        string      `validate:"required,max=100" json:"name"`
+	Type        string      `validate:"required,oneof=percentage fixed_amount buy_x_get_y bundle_price" json:"type"`
+	Value       float64     `validate:"min=0" json:"value"`
+	Amount      money.Money `json:"amount"`
+	BuyQty      int         `validate:"min=0" json:"buy_qty"`
+	GetQty      int         `validate:"min=0" json:"get_qty"`
+	ProductIDs  []string    `validate:"dive,required" json:"product_ids"`
+	Categories  []string    `validate:"dive,required" json:"categories"`
+	CustomerIDs []string    `validate:"dive,required" json:"customer_ids"`
+	StartsAt    *time.Time  `json:"starts_at"`
+	EndsAt      *time.Time  `json:"ends_at"`
+	Priority    int         `json:"priority"`
+	Stackable   bool        `json:"stackable"`
+	UsageLimit  int         `validate:"min=0" json:"usage_limit"`
+	Active      bool        `json:"active"`
+}
+
+type PromotionResponse struct {
+	PromotionID string `json:"promotion_id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	// Value is the percentage, or the amount as a number until the clients have moved to Amount
+	Value       float64      `json:"value"`
+	Amount      *money.Money `json:"amount,omitempty"`
+	BuyQty      int          `json:"buy_qty"`
+	GetQty      int          `json:"get_qty"`
+	ProductIDs  []string     `json:"product_ids"`
+	Categories  []string     `json:"categories"`
+	CustomerIDs []string     `json:"customer_ids"`
+	StartsAt    *time.Time   `json:"starts_at"`
+	EndsAt      *time.Time   `json:"ends_at"`
+	Priority    int          `json:"priority"`
+	Stackable   bool         `json:"stackable"`
+	UsageLimit  int          `json:"usage_limit"`
+	UsageCount  int          `json:"usage_count"`
+	Active      bool         `json:"active"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+type ReturnCreateRequest struct {
+	OrderID string `validate:"required" json:"order_id"`
+	// EmployeeID processes the return, a supervisor also approves it
+	EmployeeID string              `validate:"required" json:"employee_id"`
+	Note       string              `validate:"max=255" json:"note,omitempty"`
+	Lines      []ReturnLineRequest `validate:"required,min=1,dive" json:"lines"`
+}
+
+// ReturnLineRequest returns a quantity of the order line at Position
+type ReturnLineRequest struct {
+	Position    int    `validate:"required,min=1" json:"position"`
+	Quantity    int    `validate:"required,min=1" json:"quantity"`
+	Reason      string `validate:"required,oneof=damaged defective wrong_item not_as_described expired changed_mind" json:"reason"`
+	Disposition string `validate:"required,oneof=restock write_off" json:"disposition"`
+}
+
+// ReturnDecisionRequest approves or rejects a pending return
+type ReturnDecisionRequest struct {
+	ReturnID string `json:"-"`
+	// EmployeeID is the supervisor deciding, the key must be bound to them. It defaults to the
+	// employee the key is bound to.
+	EmployeeID string `json:"employee_id"`
+	Note       string `validate:"max=255" json:"note,omitempty"`
+}
+
+type ReturnLineResponse struct {
+	OrderPosition int         `json:"order_position"`
+	ProductID     string      `json:"product_id"`
+	VariantID     string      `json:"variant_id,omitempty"`
+	Name          string      `json:"name"`
+	Quantity      int         `json:"quantity"`
+	Reason        string      `json:"reason"`
+	Disposition   string      `json:"disposition"`
+	Subtotal      money.Money `json:"subtotal"`
+	Discount      money.Money `json:"discount"`
+	Tax           money.Money `json:"tax"`
+	Refund        money.Money `json:"refund"`
+}
+
+type ReturnRefundResponse struct {
+	Method   string      `json:"method"`
+	Amount   money.Money `json:"amount"`
+	Points   int         `json:"points,omitempty"`
+	ChargeID string      `json:"charge_id,omitempty"`
+	Pending  bool        `json:"pending,omitempty"`
+}
+
+type ReturnResponse struct {
+	ReturnID       string                 `json:"return_id"`
+	OrderID        string                 `json:"order_id"`
+	StoreID        string                 `json:"store_id"`
+	CustomerID     string                 `json:"customer_id,omitempty"`
+	EmployeeID     string                 `json:"employee_id"`
+	ApprovedBy     string                 `json:"approved_by,omitempty"`
+	Status         string                 `json:"status"`
+	Note           string                 `json:"note,omitempty"`
+	Lines          []ReturnLineResponse   `json:"lines"`
+	Subtotal       money.Money            `json:"subtotal"`
+	Discount       money.Money            `json:"discount"`
+	Tax            money.Money            `json:"tax"`
+	Refund         money.Money            `json:"refund"`
+	PointsReversed int                    `json:"points_reversed"`
+	Refunds        []ReturnRefundResponse `json:"refunds"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,61 @@
+package web
+
+import "time"
+
+type StockTransferLineRequest struct {
+	ProductID string `validate:"required" json:"product_id"`
+	// VariantID is required for a product with variants, each variant is moved on its own line
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int    `validate:"required,min=1" json:"quantity"`
+}
+
+type StockTransferCreateRequest struct {
+	// SourceStoreID and DestinationStoreID are left empty for the central stock
+	SourceStoreID      string                     `validate:"max=20" json:"source_store_id"`
+	DestinationStoreID string                     `validate:"max=20,nefield=SourceStoreID" json:"destination_store_id"`
+	Note               string                     `validate:"max=500" json:"note"`
+	Lines              []StockTransferLineRequest `validate:"required,min=1,dive" json:"lines"`
+}
+
+type StockTransferUpdateRequest struct {
+	TransferID string                     `validate:"required" json:"transfer_id"`
+	Note       string                     `validate:"max=500" json:"note"`
+	Lines      []StockTransferLineRequest `validate:"required,min=1,dive" json:"lines"`
+}
+
+type StockTransferReceiptLine struct {
+	ProductID string `validate:"required" json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int    `validate:"min=0" json:"quantity"`
+	// Reason explains why the line is short, it is kept as the discrepancy reason
+	Reason string `validate:"max=255" json:"reason,omitempty"`
+}
+
+type StockTransferReceiveRequest struct {
+	TransferID string                     `validate:"required" json:"transfer_id"`
+	Lines      []StockTransferReceiptLine `validate:"dive" json:"lines,omitempty"`
+	// Complete closes the transfer, the quantities still missing are recorded as discrepancies
+	Complete bool `json:"complete,omitempty"`
+}
+
+type StockTransferLineResponse struct {
+	ProductID         string `json:"product_id"`
+	VariantID         string `json:"variant_id,omitempty"`
+	Quantity          int    `json:"quantity"`
+	ReceivedQty       int    `json:"received_qty"`
+	DiscrepancyQty    int    `json:"discrepancy_qty"`
+	DiscrepancyReason string `json:"discrepancy_reason,omitempty"`
+}
+
+type StockTransferResponse struct {
+	TransferID         string                      `json:"transfer_id"`
+	SourceStoreID      string                      `json:"source_store_id"`
+	DestinationStoreID string                      `json:"destination_store_id"`
+	Status             string                      `json:"status"`
+	Note               string                      `json:"note"`
+	Lines              []StockTransferLineResponse `json:"lines"`
+	CreatedAt          time.Time                   `json:"created_at"`
+	UpdatedAt          time.Time                   `json:"updated_at"`
+	DispatchedAt       *time.Time                  `json:"dispatched_at"`
+	ReceivedAt         *time.Time                  `json:"received_at"`
+}
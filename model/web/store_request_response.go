@@ -0,0 +1,76 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+type StoreCreateRequest struct {
+	StoreID string `validate:"required,alphanum,max=20" json:"store_id"`
+	Name    string `validate:"required,min=1,max=100" json:"name"`
+	Address string `validate:"max=500" json:"address"`
+	Phone   string `validate:"max=20" json:"phone"`
+	// PriceMode and TaxRounding default to exclusive and line
+	PriceMode   string `validate:"omitempty,oneof=exclusive inclusive" json:"price_mode,omitempty"`
+	TaxRounding string `validate:"omitempty,oneof=line invoice" json:"tax_rounding,omitempty"`
+	// ReceiptQR is the content of the QR code of the receipts, {order_id} is replaced by the order
+	ReceiptHeader string `validate:"max=500" json:"receipt_header"`
+	ReceiptFooter string `validate:"max=500" json:"receipt_footer"`
+	ReceiptQR     string `validate:"max=255" json:"receipt_qr"`
+}
+
+type StoreUpdateRequest struct {
+	StoreID string `validate:"required" json:"store_id"`
+	Name    string `validate:"required,min=1,max=100" json:"name"`
+	Address string `validate:"max=500" json:"address"`
+	Phone   string `validate:"max=20" json:"phone"`
+	// PriceMode and TaxRounding are kept when empty
+	PriceMode     string `validate:"omitempty,oneof=exclusive inclusive" json:"price_mode,omitempty"`
+	TaxRounding   string `validate:"omitempty,oneof=line invoice" json:"tax_rounding,omitempty"`
+	ReceiptHeader string `validate:"max=500" json:"receipt_header"`
+	ReceiptFooter string `validate:"max=500" json:"receipt_footer"`
+	ReceiptQR     string `validate:"max=255" json:"receipt_qr"`
+}
+
+type StoreResponse struct {
+	StoreID       string    `json:"store_id"`
+	Name          string    `json:"name"`
+	Address       string    `json:"address"`
+	Phone         string    `json:"phone"`
+	PriceMode     string    `json:"price_mode"`
+	TaxRounding   string    `json:"tax_rounding"`
+	ReceiptHeader string    `json:"receipt_header"`
+	ReceiptFooter string    `json:"receipt_footer"`
+	ReceiptQR     string    `json:"receipt_qr"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type StoreStockUpdateRequest struct {
+	StoreID   string `validate:"required" json:"store_id"`
+	ProductID string `validate:"required" json:"product_id"`
+	// VariantID sets the stock of a variant, it is required for a product with variants whose
+	// stock is the total of its variants
+	VariantID string `json:"variant_id,omitempty"`
+	StockQty  int    `validate:"min=0" json:"stock_qty"`
+	// PriceOverride replaces the product price in the store, null removes the override
+	PriceOverride *money.Money `json:"price_override"`
+}
+
+type StoreStockResponse struct {
+	StoreID   string `json:"store_id"`
+	ProductID string `json:"product_id"`
+	StockQty  int    `json:"stock_qty"`
+	// PriceOverride is a number until the clients have moved to PriceOverrideAmount
+	PriceOverride       *float64     `json:"price_override"`
+	PriceOverrideAmount *money.Money `json:"price_override_amount"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+	// Variants are left out for a product without variants
+	Variants []StoreVariantStockResponse `json:"variants,omitempty"`
+}
+
+type StoreVariantStockResponse struct {
+	VariantID string `json:"variant_id"`
+	StockQty  int    `json:"stock_qty"`
+}
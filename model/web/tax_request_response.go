@@ -0,0 +1,26 @@
+package web
+
+import "github.com/aronipurwanto/go-restful-api/money"
+
+type TaxRateResponse struct {
+	Code    string  `json:"code"`
+	Name    string  `json:"name"`
+	Percent float64 `json:"percent"`
+}
+
+type TaxClassResponse struct {
+	TaxClassID string `json:"tax_class_id"`
+	Name       string `json:"name"`
+	// Percent is the total of the rates
+	Percent float64           `json:"percent"`
+	Rates   []TaxRateResponse `json:"rates"`
+}
+
+// TaxAmountResponse is the tax of one rate and the base it is computed on
+type TaxAmountResponse struct {
+	Code    string      `json:"code"`
+	Name    string      `json:"name"`
+	Percent float64     `json:"percent"`
+	Base    money.Money `json:"base"`
+	Tax     money.Money `json:"tax"`
+}
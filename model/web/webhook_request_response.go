@@ -0,0 +1,43 @@
+package web
+
+import "time"
+
+type WebhookCreateRequest struct {
+	URL        string   `validate:"required,url,max=2048" json:"url"`
+	Secret     string   `validate:"max=255" json:"secret"`
+	EventTypes []string `validate:"required,min=1,dive,required" json:"event_types"`
+}
+
+type WebhookUpdateRequest struct {
+	WebhookID  string   `validate:"required" json:"webhook_id"`
+	URL        string   `validate:"required,url,max=2048" json:"url"`
+	Secret     string   `validate:"max=255" json:"secret"`
+	EventTypes []string `validate:"required,min=1,dive,required" json:"event_types"`
+	Active     bool     `json:"active"`
+}
+
+type WebhookResponse struct {
+	WebhookID  string   `json:"webhook_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	// Secret is only returned when the webhook is created or its secret is rotated
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	DeliveryID    string    `json:"delivery_id"`
+	WebhookID     string    `json:"webhook_id"`
+	EventID       uint64    `json:"event_id"`
+	EventType     string    `json:"event_type"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	ResponseCode  int       `json:"response_code"`
+	ResponseBody  string    `json:"response_body"`
+	Error         string    `json:"error"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
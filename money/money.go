@@ -0,0 +1,344 @@
+// Package money represents amounts of money exactly, as an integer number of minor units of a
+// currency, so that sums and discounts never drift the way float64 prices do.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of the catalogue, and of a Money that does not name one
+const DefaultCurrency = "IDR"
+
+// Currency describes how amounts of a currency are written and paid in cash
+type Currency struct {
+	Code string
+	// Exponent is the number of decimals of the minor unit: 2 for cents, 0 when there is none
+	Exponent int
+	// CashIncrement is the smallest amount, in minor units, that can be paid in cash
+	CashIncrement int64
+	// Denominations are the notes and coins in circulation in minor units, largest first
+	Denominations []int64
+}
+
+// currencies are the supported currencies. Rupiah has no minor unit in practice, prices and
+// taxes are whole rupiah, and the smallest coin in circulation is Rp 100.
+var currencies = map[string]Currency{
+	"IDR": {Code: "IDR", Exponent: 0, CashIncrement: 100, Denominations: []int64{100000, 50000, 20000, 10000, 5000, 2000, 1000, 500, 200, 100}},
+	"USD": {Code: "USD", Exponent: 2, CashIncrement: 1, Denominations: []int64{10000, 5000, 2000, 1000, 500, 200, 100, 25, 10, 5, 1}},
+}
+
+// IsDenomination tells whether amount minor units is a note or a coin of the currency
+func (currency Currency) IsDenomination(amount int64) bool {
+	for _, denomination := range currency.Denominations {
+		if denomination == amount {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns a supported currency
+func Lookup(code string) (Currency, bool) {
+	currency, ok := currencies[code]
+	return currency, ok
+}
+
+// Money is an amount in minor units of a currency. The zero value is zero DefaultCurrency.
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New is amount minor units of currency, it panics on an unsupported currency
+func New(amount int64, currency string) Money {
+	if _, ok := currencies[currency]; !ok {
+		panic(fmt.Sprintf("money: unsupported currency %q", currency))
+	}
+	if currency == DefaultCurrency {
+		currency = ""
+	}
+	return Money{amount: amount, currency: currency}
+}
+
+// IDR is an amount of rupiah
+func IDR(rupiah int64) Money {
+	return New(rupiah, "IDR")
+}
+
+// FromFloat converts a float amount in major units, such as a legacy float64 price, rounding
+// half away from zero to the minor unit
+func FromFloat(value float64, currency string) Money {
+	return New(int64(math.Round(value*math.Pow10(exponent(currency)))), currency)
+}
+
+// Parse reads a decimal string in major units, e.g. "35000" or "12.50". More decimals than the
+// currency has are refused rather than rounded.
+func Parse(text string, currency string) (Money, error) {
+	digits := exponent(currency)
+	whole, fraction, hasFraction := strings.Cut(strings.TrimSpace(text), ".")
+	if hasFraction && fraction == "" {
+		return Money{}, fmt.Errorf("money: %q is not a decimal amount", text)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > digits {
+		return Money{}, fmt.Errorf("money: %q has more than %d decimals for %s", text, digits, currencyOf(currency))
+	}
+	negative := strings.HasPrefix(whole, "-")
+	units, err := strconv.ParseUint(strings.TrimPrefix(whole, "-"), 10, 63)
+	if err != nil {
+		return Money{}, fmt.Errorf("money: %q is not a decimal amount", text)
+	}
+	minor := int64(0)
+	if fraction != "" {
+		parsed, err := strconv.ParseUint(fraction, 10, 63)
+		if err != nil {
+			return Money{}, fmt.Errorf("money: %q is not a decimal amount", text)
+		}
+		minor = int64(parsed) * int64(math.Pow10(digits-len(fraction)))
+	}
+	amount := int64(units)*int64(math.Pow10(digits)) + minor
+	if negative {
+		amount = -amount
+	}
+	return New(amount, currencyOf(currency)), nil
+}
+
+// Amount is the number of minor units
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() string {
+	return currencyOf(m.currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) Add(other Money) Money {
+	m.same(other)
+	return Money{amount: m.amount + other.amount, currency: m.currency}
+}
+
+func (m Money) Sub(other Money) Money {
+	m.same(other)
+	return Money{amount: m.amount - other.amount, currency: m.currency}
+}
+
+// Mul multiplies by a quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{amount: m.amount * quantity, currency: m.currency}
+}
+
+// Percent is percent of the amount, rounded half away from zero to the minor unit
+func (m Money) Percent(percent float64) Money {
+	return m.Scale(percent / 100)
+}
+
+// Scale multiplies by factor, rounded half away from zero to the minor unit
+func (m Money) Scale(factor float64) Money {
+	return Money{amount: int64(math.Round(float64(m.amount) * factor)), currency: m.currency}
+}
+
+// Min returns the smaller of two amounts
+func (m Money) Min(other Money) Money {
+	m.same(other)
+	if other.amount < m.amount {
+		return other
+	}
+	return m
+}
+
+// Cmp returns -1, 0 or 1 when m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) int {
+	m.same(other)
+	switch {
+	case m.amount < other.amount:
+		return -1
+	case m.amount > other.amount:
+		return 1
+	}
+	return 0
+}
+
+// Allocate splits the amount in parts proportional to weights. The parts always add up to the
+// amount: the units left by the rounding down go one by one to the parts with the largest
+// remainders, the first part on a tie. Every weight being zero splits evenly.
+func (m Money) Allocate(weights ...int64) []Money {
+	parts := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+	total := int64(0)
+	for _, weight := range weights {
+		if weight < 0 {
+			panic("money: negative allocation weight")
+		}
+		total += weight
+	}
+	if total == 0 {
+		weights = make([]int64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = int64(len(weights))
+	}
+
+	sign := int64(1)
+	amount := m.amount
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+	remainders := make([]int64, len(weights))
+	left := amount
+	for i, weight := range weights {
+		share := amount * weight / total
+		remainders[i] = amount * weight % total
+		parts[i] = Money{amount: share, currency: m.currency}
+		left -= share
+	}
+	for ; left > 0; left-- {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		parts[largest].amount++
+		remainders[largest] = -1
+	}
+	for i := range parts {
+		parts[i].amount *= sign
+	}
+	return parts
+}
+
+// CashRound rounds to the smallest amount that can be paid in cash, half up: Rp 35.050 is paid
+// Rp 35.100 and Rp 35.049 is paid Rp 35.000
+func (m Money) CashRound() Money {
+	increment := currencies[m.Currency()].CashIncrement
+	if increment <= 1 {
+		return m
+	}
+	remainder := m.amount % increment
+	if remainder < 0 {
+		remainder += increment
+	}
+	rounded := m.amount - remainder
+	if remainder*2 >= increment {
+		rounded += increment
+	}
+	return Money{amount: rounded, currency: m.currency}
+}
+
+// Float64 is the amount in major units, only for the clients that still read numbers
+func (m Money) Float64() float64 {
+	return float64(m.amount) / math.Pow10(exponent(m.currency))
+}
+
+// String is the amount in major units as a decimal string, e.g. "35000" or "12.50"
+func (m Money) String() string {
+	digits := exponent(m.currency)
+	if digits == 0 {
+		return strconv.FormatInt(m.amount, 10)
+	}
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	scale := int64(math.Pow10(digits))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, digits, amount%scale)
+}
+
+// MarshalJSON encodes the amount as a decimal string
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON reads a decimal string, or a number as sent by the clients written when prices
+// were float64, in the currency m already has
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var number float64
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("money: %s is neither a decimal string nor a number", data)
+		}
+		*m = FromFloat(number, m.Currency())
+		return nil
+	}
+	parsed, err := Parse(text, m.Currency())
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the minor units
+func (m Money) Value() (driver.Value, error) {
+	return m.amount, nil
+}
+
+// Scan reads minor units of DefaultCurrency, a column holds no currency: the models keep it in a
+// column of their own and give it to their amounts once read, see domain.Product. Floats come
+// from the rows written when prices were float64 rupiah, which are also the minor units of IDR.
+func (m *Money) Scan(value interface{}) error {
+	switch value := value.(type) {
+	case int64:
+		*m = Money{amount: value}
+	case float64:
+		*m = Money{amount: int64(math.Round(value))}
+	case []byte:
+		return m.scanText(string(value))
+	case string:
+		return m.scanText(value)
+	case nil:
+		*m = Money{}
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+	return nil
+}
+
+func (m *Money) scanText(text string) error {
+	amount, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q", text)
+	}
+	*m = Money{amount: int64(math.Round(amount))}
+	return nil
+}
+
+// same panics when two amounts are not in the same currency, mixing currencies is a bug
+func (m Money) same(other Money) {
+	if m.Currency() != other.Currency() {
+		panic(fmt.Sprintf("money: %s and %s amounts mixed", m.Currency(), other.Currency()))
+	}
+}
+
+func currencyOf(code string) string {
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+func exponent(currency string) int {
+	return currencies[currencyOf(currency)].Exponent
+}
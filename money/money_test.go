@@ -0,0 +1,125 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAndString(t *testing.T) {
+	tests := []struct {
+		text     string
+		currency string
+		amount   int64
+		string   string
+		invalid  bool
+	}{
+		{text: "35000", currency: "IDR", amount: 35000, string: "35000"},
+		{text: "35000.00", currency: "IDR", amount: 35000, string: "35000"},
+		{text: "-1500", currency: "IDR", amount: -1500, string: "-1500"},
+		{text: "12.5", currency: "USD", amount: 1250, string: "12.50"},
+		{text: "0.07", currency: "USD", amount: 7, string: "0.07"},
+		{text: "-0.07", currency: "USD", amount: -7, string: "-0.07"},
+		{text: "35000.5", currency: "IDR", invalid: true},
+		{text: "1.234", currency: "USD", invalid: true},
+		{text: "12.", currency: "USD", invalid: true},
+		{text: "Rp 100", currency: "IDR", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.text, func(t *testing.T) {
+			m, err := Parse(tt.text, tt.currency)
+			if tt.invalid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, m.Amount())
+			assert.Equal(t, tt.currency, m.Currency())
+			assert.Equal(t, tt.string, m.String())
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var request struct {
+		Price Money `json:"price"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price": "35000"}`), &request))
+	assert.Equal(t, IDR(35000), request.Price)
+
+	// Numbers from the clients written for float prices
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 15000000}`), &request))
+	assert.Equal(t, IDR(15000000), request.Price)
+	assert.Error(t, json.Unmarshal([]byte(`{"price": "35000.5"}`), &request))
+	assert.Error(t, json.Unmarshal([]byte(`{"price": true}`), &request))
+
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": "15000000"}`, string(data))
+}
+
+func TestArithmetic(t *testing.T) {
+	price := IDR(35000)
+	assert.Equal(t, IDR(105000), price.Mul(3))
+	assert.Equal(t, IDR(70000), price.Mul(3).Sub(price))
+	assert.Equal(t, IDR(3850), price.Percent(11))
+	// 11% of 6305 is 693.55
+	assert.Equal(t, IDR(694), IDR(6305).Percent(11))
+	assert.Equal(t, IDR(-694), IDR(-6305).Percent(11))
+	assert.Equal(t, IDR(1000), IDR(1000).Min(IDR(4000)))
+	assert.Equal(t, 1, price.Cmp(IDR(1)))
+	assert.Equal(t, Money{}, IDR(0))
+	assert.Equal(t, 12.5, New(1250, "USD").Float64())
+	assert.Panics(t, func() { price.Add(New(100, "USD")) })
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []int64
+		parts   []Money
+	}{
+		{name: "even", amount: IDR(100), weights: []int64{1, 1, 1}, parts: []Money{IDR(34), IDR(33), IDR(33)}},
+		{name: "largest remainder", amount: IDR(10000), weights: []int64{15000000, 105000}, parts: []Money{IDR(9930), IDR(70)}},
+		{name: "zero weight gets nothing", amount: IDR(5), weights: []int64{1, 0, 1}, parts: []Money{IDR(3), IDR(0), IDR(2)}},
+		{name: "all zero weights", amount: IDR(5), weights: []int64{0, 0}, parts: []Money{IDR(3), IDR(2)}},
+		{name: "negative", amount: IDR(-100), weights: []int64{1, 1, 1}, parts: []Money{IDR(-34), IDR(-33), IDR(-33)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := tt.amount.Allocate(tt.weights...)
+			assert.Equal(t, tt.parts, parts)
+			total := Money{}
+			for _, part := range parts {
+				total = total.Add(part)
+			}
+			assert.Equal(t, tt.amount, total)
+		})
+	}
+}
+
+func TestCashRound(t *testing.T) {
+	assert.Equal(t, IDR(35100), IDR(35050).CashRound())
+	assert.Equal(t, IDR(35000), IDR(35049).CashRound())
+	assert.Equal(t, IDR(77700), IDR(77700).CashRound())
+	assert.Equal(t, IDR(-35000), IDR(-35049).CashRound())
+	assert.Equal(t, New(1999, "USD"), New(1999, "USD").CashRound())
+}
+
+func TestScan(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan(int64(35000)))
+	assert.Equal(t, IDR(35000), m)
+	require.NoError(t, m.Scan(float64(15000000)))
+	assert.Equal(t, IDR(15000000), m)
+	require.NoError(t, m.Scan([]byte("42000")))
+	assert.Equal(t, IDR(42000), m)
+	value, err := IDR(35000).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(35000), value)
+}
@@ -0,0 +1,266 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Endpoint documents one route registered on the Fiber application
+type Endpoint struct {
+	Method  string
+	Path    string // Fiber route template, e.g. /api/products/:productId
+	Tag     string
+	Summary string
+	// PathParams overrides the schema type ("integer", "number", ...) of path parameters, string by default
+	PathParams map[string]string
+	Query      []Parameter
+	// Request is a value of the JSON body type; RequestOmit lists Go fields that are bound from the path
+	Request     interface{}
+	RequestOmit []string
+	// Response is a value of the type placed in the data field of the response envelope
+	Response interface{}
+	Status   int
+	// Responses documents additional status codes with the type of their data field
+	Responses map[int]interface{}
+	// ContentType is set for endpoints that do not answer with the JSON envelope, e.g. text/plain
+	ContentType string
+	// ContentTypes lists the media types of an endpoint answering one of several, e.g. per a query
+	ContentTypes []string
+	// Unwrapped endpoints answer JSON without the envelope, Response and Responses are the whole body
+	Unwrapped bool
+	// Secured requires SecurityScheme on an endpoint outside SecuredPrefix
+	Secured bool
+}
+
+// Builder assembles a Document from the route table and the endpoint documentation
+type Builder struct {
+	Info    Info
+	Servers []Server
+	// Envelope wraps the schema of the response data into the schema of the full response body
+	Envelope func(data *Schema) *Schema
+	// SecuredPrefix marks every path below it as requiring SecurityScheme
+	SecuredPrefix  string
+	SecurityName   string
+	SecurityScheme *SecurityScheme
+	// Types documents the types with a custom JSON encoding, see Generator.Types
+	Types map[reflect.Type]*Schema
+}
+
+var paramPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)\??`)
+
+// NormalizePath converts a Fiber route template into an OpenAPI path template
+func NormalizePath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return paramPattern.ReplaceAllString(path, "{$1}")
+}
+
+func endpointKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + NormalizePath(path)
+}
+
+func documentedRoutes(routes []fiber.Route) []fiber.Route {
+	seen := map[string]bool{}
+	var result []fiber.Route
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead || route.Method == fiber.MethodConnect || route.Method == fiber.MethodTrace {
+			continue
+		}
+		key := endpointKey(route.Method, route.Path)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, route)
+	}
+	return result
+}
+
+// Undocumented lists the routes ("METHOD /path") for which no endpoint documentation exists
+func Undocumented(routes []fiber.Route, endpoints []Endpoint) []string {
+	documented := map[string]bool{}
+	for _, endpoint := range endpoints {
+		documented[endpointKey(endpoint.Method, endpoint.Path)] = true
+	}
+
+	var missing []string
+	for _, route := range documentedRoutes(routes) {
+		key := endpointKey(route.Method, route.Path)
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Build generates the document for every route of the table
+func (builder *Builder) Build(routes []fiber.Route, endpoints []Endpoint) *Document {
+	generator := NewGenerator()
+	generator.Types = builder.Types
+	document := &Document{
+		OpenAPI:    "3.0.3",
+		Info:       builder.Info,
+		Servers:    builder.Servers,
+		Paths:      map[string]*PathItem{},
+		Components: Components{Schemas: generator.Schemas},
+	}
+	if builder.SecurityScheme != nil {
+		document.Components.SecuritySchemes = map[string]*SecurityScheme{builder.SecurityName: builder.SecurityScheme}
+	}
+
+	byKey := map[string]Endpoint{}
+	for _, endpoint := range endpoints {
+		byKey[endpointKey(endpoint.Method, endpoint.Path)] = endpoint
+	}
+
+	seenTags := map[string]bool{}
+	for _, route := range documentedRoutes(routes) {
+		path := NormalizePath(route.Path)
+		endpoint, ok := byKey[endpointKey(route.Method, route.Path)]
+		if !ok {
+			endpoint = Endpoint{Method: route.Method, Path: route.Path, Summary: "Undocumented"}
+		}
+
+		operation := builder.operation(generator, route, endpoint)
+		if endpoint.Tag != "" && !seenTags[endpoint.Tag] {
+			seenTags[endpoint.Tag] = true
+			document.Tags = append(document.Tags, Tag{Name: endpoint.Tag})
+		}
+
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			document.Paths[path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = operation
+	}
+	return document
+}
+
+func (builder *Builder) operation(generator *Generator, route fiber.Route, endpoint Endpoint) *Operation {
+	path := NormalizePath(route.Path)
+	operation := &Operation{
+		Summary:     endpoint.Summary,
+		OperationID: operationID(route.Method, path),
+		Responses:   map[string]*Response{},
+	}
+	if endpoint.Tag != "" {
+		operation.Tags = []string{endpoint.Tag}
+	}
+
+	for _, name := range route.Params {
+		paramType := endpoint.PathParams[name]
+		if paramType == "" {
+			paramType = "string"
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: paramType},
+		})
+	}
+	operation.Parameters = append(operation.Parameters, endpoint.Query...)
+
+	envelope := builder.envelope
+	if endpoint.Unwrapped {
+		envelope = func(data *Schema) *Schema { return data }
+	}
+
+	if endpoint.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				fiber.MIMEApplicationJSON: {Schema: generator.SchemaOf(endpoint.Request, endpoint.RequestOmit...)},
+			},
+		}
+	}
+
+	status := endpoint.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	contentTypes := endpoint.ContentTypes
+	if endpoint.ContentType != "" {
+		contentTypes = append([]string{endpoint.ContentType}, contentTypes...)
+	}
+	if len(contentTypes) > 0 {
+		success.Content = map[string]*MediaType{}
+		for _, contentType := range contentTypes {
+			schema := &Schema{Type: "string"}
+			if contentType == fiber.MIMEOctetStream {
+				schema.Format = "binary"
+			}
+			success.Content[contentType] = &MediaType{Schema: schema}
+		}
+	} else {
+		success.Content = map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: envelope(generator.SchemaOf(endpoint.Response))}}
+	}
+	operation.Responses[strconv.Itoa(status)] = success
+	for extraStatus, data := range endpoint.Responses {
+		operation.Responses[strconv.Itoa(extraStatus)] = &Response{
+			Description: http.StatusText(extraStatus),
+			Content:     map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: envelope(generator.SchemaOf(data))}},
+		}
+	}
+
+	secured := endpoint.Secured || (builder.SecuredPrefix != "" && strings.HasPrefix(path, builder.SecuredPrefix))
+	if secured {
+		operation.Security = []map[string][]string{{builder.SecurityName: {}}}
+		operation.Responses[strconv.Itoa(fiber.StatusUnauthorized)] = builder.errorResponse(fiber.StatusUnauthorized)
+	}
+	// Secured endpoints answer errors with the JSON envelope whatever their success content type
+	if len(contentTypes) == 0 || secured {
+		if endpoint.Request != nil || len(route.Params) > 0 || len(endpoint.Query) > 0 {
+			builder.defaultErrorResponse(operation, fiber.StatusBadRequest)
+		}
+		if len(route.Params) > 0 {
+			builder.defaultErrorResponse(operation, fiber.StatusNotFound)
+		}
+		builder.defaultErrorResponse(operation, fiber.StatusInternalServerError)
+	}
+	return operation
+}
+
+func (builder *Builder) envelope(data *Schema) *Schema {
+	if builder.Envelope == nil {
+		return data
+	}
+	return builder.Envelope(data)
+}
+
+// defaultErrorResponse documents the error envelope for status unless the endpoint documents its own body
+func (builder *Builder) defaultErrorResponse(operation *Operation, status int) {
+	if _, ok := operation.Responses[strconv.Itoa(status)]; !ok {
+		operation.Responses[strconv.Itoa(status)] = builder.errorResponse(status)
+	}
+}
+
+func (builder *Builder) errorResponse(status int) *Response {
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{
+			fiber.MIMEApplicationJSON: {Schema: builder.envelope(&Schema{})},
+		},
+	}
+}
+
+func operationID(method string, path string) string {
+	var builder strings.Builder
+	builder.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.'
+	}) {
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return builder.String()
+}
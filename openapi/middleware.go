@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MiddlewareConfig configures the contract validation middleware
+type MiddlewareConfig struct {
+	// Document returns the contract; it is called once, on the first request
+	Document func() *Document
+	// ValidateResponses also checks outgoing bodies, meant for tests and development
+	ValidateResponses bool
+	// ErrorHandler writes the response for an invalid request (status 400) or, when responses are
+	// validated, for a response that does not match the contract (status 500)
+	ErrorHandler func(c *fiber.Ctx, status int, errs []ValidationError) error
+}
+
+type compiledOperation struct {
+	method     string
+	pattern    *regexp.Regexp
+	paramNames []string
+	operation  *Operation
+}
+
+type contract struct {
+	document   *Document
+	operations []compiledOperation
+	request    *SchemaValidator
+	response   *SchemaValidator
+}
+
+var templateParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func compileContract(document *Document) *contract {
+	c := &contract{
+		document: document,
+		request:  NewSchemaValidator(document, false),
+		response: NewSchemaValidator(document, true),
+	}
+	for path, item := range document.Paths {
+		pattern, names := compilePath(path)
+		for method, operation := range *item {
+			c.operations = append(c.operations, compiledOperation{
+				method:     strings.ToUpper(method),
+				pattern:    pattern,
+				paramNames: names,
+				operation:  operation,
+			})
+		}
+	}
+	// Static paths win over templated ones, e.g. /orders/open before /orders/{orderId}
+	sort.SliceStable(c.operations, func(i, j int) bool {
+		return len(c.operations[i].paramNames) < len(c.operations[j].paramNames)
+	})
+	return c
+}
+
+// compilePath turns an OpenAPI path template into a regular expression capturing its parameters
+func compilePath(path string) (*regexp.Regexp, []string) {
+	var names []string
+	var expression strings.Builder
+	expression.WriteString("^")
+	last := 0
+	for _, loc := range templateParam.FindAllStringSubmatchIndex(path, -1) {
+		expression.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		expression.WriteString("([^/]+)")
+		names = append(names, path[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	expression.WriteString(regexp.QuoteMeta(path[last:]))
+	expression.WriteString("/?$")
+	return regexp.MustCompile(expression.String()), names
+}
+
+func (c *contract) find(method string, path string) (*Operation, map[string]string) {
+	for _, candidate := range c.operations {
+		if candidate.method != method {
+			continue
+		}
+		matches := candidate.pattern.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+		params := map[string]string{}
+		for i, name := range candidate.paramNames {
+			params[name] = matches[i+1]
+		}
+		return candidate.operation, params
+	}
+	return nil, nil
+}
+
+// NewValidationMiddleware validates path parameters, query parameters and JSON bodies of the
+// requests against the contract and, optionally, the JSON bodies of the responses
+func NewValidationMiddleware(config MiddlewareConfig) fiber.Handler {
+	var once sync.Once
+	var compiled *contract
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = func(c *fiber.Ctx, status int, errs []ValidationError) error {
+			return c.Status(status).JSON(errs)
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		once.Do(func() {
+			compiled = compileContract(config.Document())
+		})
+
+		operation, params := compiled.find(c.Method(), c.Path())
+		if operation == nil {
+			return c.Next()
+		}
+
+		if errs := compiled.validateRequest(c, operation, params); len(errs) > 0 {
+			return config.ErrorHandler(c, fiber.StatusBadRequest, errs)
+		}
+
+		err := c.Next()
+		if err != nil || !config.ValidateResponses {
+			return err
+		}
+
+		if errs := compiled.validateResponse(c, operation); len(errs) > 0 {
+			c.Response().ResetBody()
+			return config.ErrorHandler(c, fiber.StatusInternalServerError, errs)
+		}
+		return nil
+	}
+}
+
+func (c *contract) validateRequest(ctx *fiber.Ctx, operation *Operation, params map[string]string) []ValidationError {
+	var errs []ValidationError
+	for _, parameter := range operation.Parameters {
+		var raw string
+		var present bool
+		switch parameter.In {
+		case "path":
+			raw, present = params[parameter.Name]
+		case "query":
+			raw = ctx.Query(parameter.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		pointer := "/" + escapePointer(parameter.Name)
+		if !present {
+			if parameter.Required {
+				errs = append(errs, ValidationError{In: parameter.In, Pointer: pointer, Message: "is required"})
+			}
+			continue
+		}
+		value, message := ParseParameter(c.request.resolve(parameter.Schema), raw)
+		if message != "" {
+			errs = append(errs, ValidationError{In: parameter.In, Pointer: pointer, Message: message})
+			continue
+		}
+		errs = append(errs, c.request.Validate(parameter.In, parameter.Schema, value, pointer)...)
+	}
+
+	if operation.RequestBody != nil {
+		media := operation.RequestBody.Content[fiber.MIMEApplicationJSON]
+		body := ctx.Body()
+		switch {
+		case len(body) == 0:
+			if operation.RequestBody.Required {
+				errs = append(errs, ValidationError{In: "body", Pointer: "", Message: "request body is required"})
+			}
+		case media != nil:
+			value, err := DecodeJSON(body)
+			if err != nil {
+				errs = append(errs, ValidationError{In: "body", Pointer: "", Message: "invalid JSON: " + err.Error()})
+				break
+			}
+			errs = append(errs, c.request.Validate("body", media.Schema, value, "")...)
+		}
+	}
+	return errs
+}
+
+func (c *contract) validateResponse(ctx *fiber.Ctx, operation *Operation) []ValidationError {
+	status := ctx.Response().StatusCode()
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return []ValidationError{{In: "response", Pointer: "", Message: "status " + strconv.Itoa(status) + " is not documented"}}
+	}
+
+	media := response.Content[fiber.MIMEApplicationJSON]
+	contentType := string(ctx.Response().Header.ContentType())
+	if media == nil || !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return nil
+	}
+
+	value, err := DecodeJSON(ctx.Response().Body())
+	if err != nil {
+		return []ValidationError{{In: "response", Pointer: "", Message: "invalid JSON: " + err.Error()}}
+	}
+	return c.response.Validate("response", media.Schema, value, "")
+}
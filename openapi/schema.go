@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generator derives JSON schemas from Go types using their json and validate struct tags.
+// Named struct types are registered once as components and referenced with $ref.
+type Generator struct {
+	Schemas map[string]*Schema
+	// Types are the schemas of the types with a custom JSON encoding, they replace the schema
+	// derived from the Go type
+	Types map[reflect.Type]*Schema
+}
+
+func NewGenerator() *Generator {
+	return &Generator{Schemas: map[string]*Schema{}}
+}
+
+// SchemaOf returns the schema of value's type. Fields listed in omit (Go field names) are left
+// out of the top-level struct, which is used for request fields that are bound from the path.
+func (generator *Generator) SchemaOf(value interface{}, omit ...string) *Schema {
+	if value == nil {
+		return &Schema{}
+	}
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t.Name() != "" && len(omit) > 0 {
+		if _, ok := generator.Schemas[t.Name()]; !ok {
+			generator.Schemas[t.Name()] = generator.structSchema(t, omit)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return generator.schemaOfType(t)
+}
+
+func (generator *Generator) schemaOfType(t reflect.Type) *Schema {
+	if schema, ok := generator.Types[t]; ok {
+		copied := *schema
+		return &copied
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := *generator.schemaOfType(t.Elem())
+		if schema.Ref != "" {
+			// $ref siblings are ignored by OpenAPI 3.0, nullability needs an allOf wrapper
+			return &Schema{AllOf: []*Schema{&schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return &schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		// A nil slice is encoded as null by encoding/json
+		return &Schema{Type: "array", Items: generator.schemaOfType(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generator.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return generator.structSchema(t, nil)
+		}
+		if _, ok := generator.Schemas[t.Name()]; !ok {
+			// Register a placeholder first so that recursive types terminate
+			generator.Schemas[t.Name()] = &Schema{}
+			*generator.Schemas[t.Name()] = *generator.structSchema(t, nil)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		return &Schema{}
+	}
+}
+
+func (generator *Generator) structSchema(t reflect.Type, omit []string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	generator.addFields(schema, t, omit)
+	return schema
+}
+
+func (generator *Generator) addFields(schema *Schema, t reflect.Type, omit []string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || contains(omit, field.Name) {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				generator.addFields(schema, fieldType, omit)
+				continue
+			}
+		}
+
+		property := generator.schemaOfType(fieldType)
+		required := applyValidateTag(property, field.Tag.Get("validate"))
+		if property.Ref != "" && hasConstraints(property) {
+			// $ref siblings are ignored by OpenAPI 3.0, keep the reference only
+			property = &Schema{Ref: property.Ref}
+		}
+		schema.Properties[name] = property
+		if required && !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func jsonName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// applyValidateTag translates go-playground/validator rules into schema constraints
+// and reports whether the field is required
+func applyValidateTag(schema *Schema, tag string) bool {
+	required := false
+	if tag == "" {
+		return required
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "dive":
+			// Rules after dive apply to the elements, which are not described here
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, enumValue(schema, option))
+			}
+		case "min", "gte":
+			setBound(schema, value, true, false)
+		case "max", "lte":
+			setBound(schema, value, false, false)
+		case "gt":
+			setBound(schema, value, true, true)
+		case "lt":
+			setBound(schema, value, false, true)
+		case "len":
+			setBound(schema, value, true, false)
+			setBound(schema, value, false, false)
+		}
+	}
+	return required
+}
+
+func setBound(schema *Schema, value string, lower bool, exclusive bool) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "string":
+		length := int(number)
+		if exclusive && lower {
+			length++
+		}
+		if lower {
+			schema.MinLength = &length
+		} else {
+			schema.MaxLength = &length
+		}
+	case "array":
+		length := int(number)
+		if exclusive && lower {
+			length++
+		}
+		if lower {
+			schema.MinItems = &length
+		} else {
+			schema.MaxItems = &length
+		}
+	case "integer":
+		if exclusive && lower {
+			number++
+		} else if exclusive {
+			number--
+		}
+		if lower {
+			schema.Minimum = &number
+		} else {
+			schema.Maximum = &number
+		}
+	case "number":
+		if lower {
+			schema.Minimum = &number
+		} else {
+			schema.Maximum = &number
+		}
+	}
+}
+
+func enumValue(schema *Schema, option string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if value, err := strconv.ParseInt(option, 10, 64); err == nil {
+			return value
+		}
+	case "number":
+		if value, err := strconv.ParseFloat(option, 64); err == nil {
+			return value
+		}
+	}
+	return option
+}
+
+func hasConstraints(schema *Schema) bool {
+	return schema.Format != "" || schema.Enum != nil || schema.MinLength != nil || schema.MaxLength != nil ||
+		schema.Minimum != nil || schema.Maximum != nil || schema.MinItems != nil || schema.MaxItems != nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
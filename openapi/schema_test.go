@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sampleAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type sampleRequest struct {
+	ID        string          `validate:"required" json:"id"`
+	Name      string          `validate:"required,min=1,max=100" json:"name"`
+	Email     string          `validate:"required,email" json:"email"`
+	Price     float64         `validate:"min=0" json:"price"`
+	Quantity  int             `validate:"gt=0" json:"quantity"`
+	Status    string          `validate:"oneof=draft sent" json:"status"`
+	Tags      []string        `validate:"max=3" json:"tags"`
+	Address   *sampleAddress  `json:"address"`
+	Addresses []sampleAddress `json:"addresses"`
+	CreatedAt time.Time       `json:"created_at"`
+	Note      string          `json:"note,omitempty" validate:"required"`
+	Internal  string          `json:"-"`
+	NoTag     int
+}
+
+func TestGeneratorSchemaOf(t *testing.T) {
+	generator := NewGenerator()
+
+	schema := generator.SchemaOf(sampleRequest{}, "ID")
+	assert.Equal(t, "#/components/schemas/sampleRequest", schema.Ref)
+
+	component := generator.Schemas["sampleRequest"]
+	assert.Equal(t, []string{"name", "email"}, component.Required)
+	assert.NotContains(t, component.Properties, "id")
+	assert.NotContains(t, component.Properties, "Internal")
+	assert.Contains(t, component.Properties, "NoTag")
+
+	name := component.Properties["name"]
+	assert.Equal(t, "string", name.Type)
+	assert.Equal(t, 1, *name.MinLength)
+	assert.Equal(t, 100, *name.MaxLength)
+
+	assert.Equal(t, "email", component.Properties["email"].Format)
+	assert.Equal(t, float64(0), *component.Properties["price"].Minimum)
+	assert.Equal(t, float64(1), *component.Properties["quantity"].Minimum)
+	assert.Equal(t, []interface{}{"draft", "sent"}, component.Properties["status"].Enum)
+	assert.Equal(t, 3, *component.Properties["tags"].MaxItems)
+	assert.Equal(t, "#/components/schemas/sampleAddress", component.Properties["address"].AllOf[0].Ref)
+	assert.True(t, component.Properties["address"].Nullable)
+	assert.Equal(t, "#/components/schemas/sampleAddress", component.Properties["addresses"].Items.Ref)
+	assert.Equal(t, "date-time", component.Properties["created_at"].Format)
+	assert.Equal(t, []string{"city"}, generator.Schemas["sampleAddress"].Required)
+}
+
+type sampleAmount struct {
+	units int64
+}
+
+func TestGeneratorTypes(t *testing.T) {
+	generator := NewGenerator()
+	generator.Types = map[reflect.Type]*Schema{reflect.TypeOf(sampleAmount{}): {Type: "string", Description: "decimal"}}
+
+	schema := generator.SchemaOf(struct {
+		Price    sampleAmount  `json:"price"`
+		Discount *sampleAmount `json:"discount"`
+	}{})
+	assert.Equal(t, &Schema{Type: "string", Description: "decimal"}, schema.Properties["price"])
+	assert.True(t, schema.Properties["discount"].Nullable)
+	assert.False(t, generator.Types[reflect.TypeOf(sampleAmount{})].Nullable, "the registered schema is left as is")
+	assert.NotContains(t, generator.Schemas, "sampleAmount")
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path   string
+		expect string
+	}{
+		{path: "/", expect: "/"},
+		{path: "/api/products/", expect: "/api/products"},
+		{path: "/api/products/:productId", expect: "/api/products/{productId}"},
+		{path: "/api/orders/:orderId/lines/:lineId?", expect: "/api/orders/{orderId}/lines/{lineId}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expect, NormalizePath(tt.path))
+		})
+	}
+}
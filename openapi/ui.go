@@ -0,0 +1,16 @@
+package openapi
+
+import (
+	_ "embed"
+	"html"
+	"strings"
+)
+
+//go:embed ui.html
+var uiTemplate string
+
+// UIPage renders the bundled documentation page; it has no external dependencies and loads the
+// document from specURL
+func UIPage(specURL string) string {
+	return strings.ReplaceAll(uiTemplate, "{{SPEC_URL}}", html.EscapeString(specURL))
+}
@@ -0,0 +1,270 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes one mismatch between a value and its schema. Pointer is a JSON
+// pointer (RFC 6901) into the document identified by In: path, query, body or response.
+type ValidationError struct {
+	In      string `json:"in"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.In, e.Pointer, e.Message)
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// SchemaValidator checks decoded JSON values against the schemas of a document
+type SchemaValidator struct {
+	document *Document
+	// Strict rejects object properties that are not declared by the schema
+	Strict bool
+}
+
+func NewSchemaValidator(document *Document, strict bool) *SchemaValidator {
+	return &SchemaValidator{document: document, Strict: strict}
+}
+
+// DecodeJSON decodes data keeping numbers as json.Number so integers can be told apart from floats
+func DecodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Validate returns every mismatch of value against schema, pointers are prefixed with pointer
+func (validator *SchemaValidator) Validate(in string, schema *Schema, value interface{}, pointer string) []ValidationError {
+	var errs []ValidationError
+	validator.validate(in, schema, value, pointer, &errs)
+	return errs
+}
+
+func (validator *SchemaValidator) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		schema = validator.document.Components.Schemas[name]
+	}
+	if schema == nil {
+		return &Schema{}
+	}
+	return schema
+}
+
+func (validator *SchemaValidator) validate(in string, schema *Schema, value interface{}, pointer string, errs *[]ValidationError) {
+	schema = validator.resolve(schema)
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, ValidationError{In: in, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(schema.AllOf) > 0 {
+		if value == nil && schema.Nullable {
+			return
+		}
+		for _, subSchema := range schema.AllOf {
+			validator.validate(in, subSchema, value, pointer, errs)
+		}
+		return
+	}
+
+	if value == nil {
+		if schema.Type != "" && !schema.Nullable {
+			fail("must be %s, got null", schema.Type)
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "":
+		// Any value is accepted
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("must be object, got %s", jsonType(value))
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				*errs = append(*errs, ValidationError{In: in, Pointer: pointer + "/" + escapePointer(name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, declared := schema.Properties[name]
+			switch {
+			case declared:
+				validator.validate(in, property, object[name], pointer+"/"+escapePointer(name), errs)
+			case schema.AdditionalProperties != nil:
+				validator.validate(in, schema.AdditionalProperties, object[name], pointer+"/"+escapePointer(name), errs)
+			case validator.Strict && schema.Properties != nil:
+				*errs = append(*errs, ValidationError{In: in, Pointer: pointer + "/" + escapePointer(name), Message: "is not declared by the schema"})
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			fail("must be array, got %s", jsonType(value))
+			return
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			fail("must contain at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			fail("must contain at most %d items", *schema.MaxItems)
+		}
+		for i, item := range array {
+			validator.validate(in, schema.Items, item, pointer+"/"+strconv.Itoa(i), errs)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("must be string, got %s", jsonType(value))
+			return
+		}
+		length := utf8.RuneCountInString(text)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			fail("must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			fail("must be at most %d characters long", *schema.MaxLength)
+		}
+		if message := checkFormat(schema.Format, text); message != "" {
+			fail(message)
+		}
+		validator.checkEnum(schema, text, fail)
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("must be %s, got %s", schema.Type, jsonType(value))
+			return
+		}
+		if schema.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				fail("must be integer, got %s", number.String())
+				return
+			}
+		}
+		float, err := number.Float64()
+		if err != nil {
+			fail("must be %s, got %s", schema.Type, number.String())
+			return
+		}
+		if schema.Minimum != nil && float < *schema.Minimum {
+			fail("must be greater than or equal to %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && float > *schema.Maximum {
+			fail("must be less than or equal to %v", *schema.Maximum)
+		}
+		validator.checkEnum(schema, float, fail)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be boolean, got %s", jsonType(value))
+		}
+	}
+}
+
+func (validator *SchemaValidator) checkEnum(schema *Schema, value interface{}, fail func(string, ...interface{})) {
+	if len(schema.Enum) == 0 {
+		return
+	}
+	for _, option := range schema.Enum {
+		if fmt.Sprint(option) == fmt.Sprint(value) {
+			return
+		}
+	}
+	fail("must be one of %v", schema.Enum)
+}
+
+func checkFormat(format string, value string) string {
+	switch format {
+	case "email":
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			return "must be a valid email address"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "must be an RFC 3339 date-time"
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "must be a date formatted as YYYY-MM-DD"
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(value) {
+			return "must be a UUID"
+		}
+	}
+	return ""
+}
+
+// ParseParameter converts a path or query string to the JSON value described by schema
+func ParseParameter(schema *Schema, raw string) (interface{}, string) {
+	switch schema.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, "must be integer, got " + strconv.Quote(raw)
+		}
+		return json.Number(raw), ""
+	case "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, "must be number, got " + strconv.Quote(raw)
+		}
+		return json.Number(raw), ""
+	case "boolean":
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, "must be boolean, got " + strconv.Quote(raw)
+		}
+		return value, ""
+	case "array":
+		items := []interface{}{}
+		for _, item := range strings.Split(raw, ",") {
+			items = append(items, item)
+		}
+		return items, ""
+	default:
+		return raw, ""
+	}
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
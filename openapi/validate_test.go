@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaValidatorValidate(t *testing.T) {
+	generator := NewGenerator()
+	schema := generator.SchemaOf(sampleRequest{})
+	document := &Document{Components: Components{Schemas: generator.Schemas}}
+
+	tests := []struct {
+		name     string
+		strict   bool
+		body     string
+		expected []ValidationError
+	}{
+		{
+			name: "valid",
+			body: `{"id":"1","name":"Laptop","email":"a@b.co","price":10.5,"quantity":2,"status":"draft","tags":["a"],"address":null,"addresses":[{"city":"Bandung"}],"created_at":"2024-01-02T03:04:05Z"}`,
+		},
+		{
+			name: "missing required and wrong types",
+			body: `{"name":"","email":"not-an-email","price":-1,"quantity":1.5,"status":"paid","tags":["a","b","c","d"],"addresses":[{}],"created_at":"yesterday"}`,
+			expected: []ValidationError{
+				{In: "body", Pointer: "/id", Message: "is required"},
+				{In: "body", Pointer: "/addresses/0/city", Message: "is required"},
+				{In: "body", Pointer: "/created_at", Message: "must be an RFC 3339 date-time"},
+				{In: "body", Pointer: "/email", Message: "must be a valid email address"},
+				{In: "body", Pointer: "/name", Message: "must be at least 1 characters long"},
+				{In: "body", Pointer: "/price", Message: "must be greater than or equal to 0"},
+				{In: "body", Pointer: "/quantity", Message: "must be integer, got 1.5"},
+				{In: "body", Pointer: "/status", Message: "must be one of [draft sent]"},
+				{In: "body", Pointer: "/tags", Message: "must contain at most 3 items"},
+			},
+		},
+		{
+			name:     "not an object",
+			body:     `[1]`,
+			expected: []ValidationError{{In: "body", Pointer: "", Message: "must be object, got array"}},
+		},
+		{
+			name:     "undeclared property in strict mode",
+			strict:   true,
+			body:     `{"id":"1","name":"a","email":"a@b.co","category_id":1}`,
+			expected: []ValidationError{{In: "body", Pointer: "/category_id", Message: "is not declared by the schema"}},
+		},
+		{
+			name: "undeclared property in lenient mode",
+			body: `{"id":"1","name":"a","email":"a@b.co","category_id":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := DecodeJSON([]byte(tt.body))
+			assert.NoError(t, err)
+
+			errs := NewSchemaValidator(document, tt.strict).Validate("body", schema, value, "")
+			assert.Equal(t, tt.expected, errs)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Card tokens of the Fake gateway, any other token is authorized
+const (
+	TokenSuccess = "tok_success"
+	// TokenDecline is declined for insufficient funds
+	TokenDecline = "tok_decline"
+	// TokenTimeout is authorized but the first attempt of every key times out
+	TokenTimeout = "tok_timeout"
+	// TokenDelayed stays pending until Settle
+	TokenDelayed = "tok_delayed"
+)
+
+// Headers of the notifications of the Fake gateway
+const (
+	HeaderTimestamp = "X-Payment-Timestamp"
+	HeaderSignature = "X-Payment-Signature"
+)
+
+// Fake is an in-memory gateway that simulates authorizations, declines, timeouts and delayed
+// settlements so that the payment flow runs offline
+type Fake struct {
+	secret    string
+	tolerance time.Duration
+	// Clock returns the current time, time.Now by default
+	Clock func() time.Time
+
+	mu       sync.Mutex
+	sequence int
+	charges  map[string]*Charge
+	keys     map[string]string
+	timedOut map[string]bool
+}
+
+// NewFake returns a gateway that signs its notifications with secret and accepts them within
+// tolerance of their timestamp
+func NewFake(secret string, tolerance time.Duration) *Fake {
+	return &Fake{
+		secret:    secret,
+		tolerance: tolerance,
+		Clock:     time.Now,
+		charges:   map[string]*Charge{},
+		keys:      map[string]string{},
+		timedOut:  map[string]bool{},
+	}
+}
+
+func (fake *Fake) Name() string {
+	return "fake"
+}
+
+func (fake *Fake) CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if id, ok := fake.keys[request.Key]; ok && request.Key != "" {
+		return *fake.charges[id], nil
+	}
+
+	fake.sequence++
+	charge := &Charge{
+		ID:       "ch_" + strconv.Itoa(fake.sequence),
+		Key:      request.Key,
+		Status:   StatusAuthorized,
+		Amount:   request.Amount,
+		Refunded: money.New(0, request.Amount.Currency()),
+	}
+	switch request.Token {
+	case TokenDecline:
+		charge.Status = StatusDeclined
+		charge.DeclineReason = "insufficient funds"
+	case TokenDelayed:
+		charge.Status = StatusPending
+	}
+	fake.charges[charge.ID] = charge
+	if request.Key != "" {
+		fake.keys[request.Key] = charge.ID
+	}
+	if request.Token == TokenTimeout && !fake.timedOut[request.Key] {
+		fake.timedOut[request.Key] = true
+		return Charge{}, ErrTimeout
+	}
+	return *charge, nil
+}
+
+func (fake *Fake) Capture(ctx context.Context, chargeId string) (Charge, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	charge, ok := fake.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrUnknownCharge
+	}
+	switch charge.Status {
+	case StatusAuthorized:
+		charge.Status = StatusCaptured
+	case StatusCaptured:
+	default:
+		return Charge{}, ErrInvalidState
+	}
+	return *charge, nil
+}
+
+func (fake *Fake) Refund(ctx context.Context, chargeId string, amount money.Money) (Charge, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	charge, ok := fake.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrUnknownCharge
+	}
+	switch charge.Status {
+	case StatusAuthorized, StatusPending:
+		charge.Status = StatusRefunded
+	case StatusCaptured:
+		refunded := charge.Refunded.Add(amount)
+		if !amount.IsPositive() || refunded.Cmp(charge.Amount) > 0 {
+			return Charge{}, fmt.Errorf("%w: refund of %s on a charge of %s", ErrInvalidState, amount, charge.Amount)
+		}
+		charge.Refunded = refunded
+		if refunded.Cmp(charge.Amount) == 0 {
+			charge.Status = StatusRefunded
+		}
+	default:
+		return Charge{}, ErrInvalidState
+	}
+	return *charge, nil
+}
+
+func (fake *Fake) Status(ctx context.Context, chargeId string) (Charge, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	charge, ok := fake.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrUnknownCharge
+	}
+	return *charge, nil
+}
+
+// Webhook is a signed request the Fake gateway would post to the webhook of the application
+type Webhook struct {
+	Header map[string]string
+	Body   []byte
+}
+
+// Settle ends a pending charge, captured or declined, and returns the notification of the
+// settlement
+func (fake *Fake) Settle(chargeId string, status string) (Webhook, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	charge, ok := fake.charges[chargeId]
+	if !ok {
+		return Webhook{}, ErrUnknownCharge
+	}
+	if charge.Status != StatusPending || (status != StatusCaptured && status != StatusDeclined) {
+		return Webhook{}, ErrInvalidState
+	}
+	charge.Status = status
+	if status == StatusDeclined {
+		charge.DeclineReason = "declined by the issuer"
+	}
+
+	fake.sequence++
+	return fake.sign(Notification{
+		ID:            "evt_" + strconv.Itoa(fake.sequence),
+		ChargeID:      charge.ID,
+		Key:           charge.Key,
+		Status:        charge.Status,
+		DeclineReason: charge.DeclineReason,
+	})
+}
+
+func (fake *Fake) sign(notification Notification) (Webhook, error) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return Webhook{}, err
+	}
+	now := fake.Clock()
+	return Webhook{
+		Header: map[string]string{
+			HeaderTimestamp: strconv.FormatInt(now.Unix(), 10),
+			HeaderSignature: Sign(fake.secret, now, body),
+		},
+		Body: body,
+	}, nil
+}
+
+func (fake *Fake) ParseNotification(header func(key string) string, body []byte) (Notification, error) {
+	if err := Verify(fake.secret, header(HeaderTimestamp), header(HeaderSignature), body, fake.tolerance, fake.Clock()); err != nil {
+		return Notification{}, err
+	}
+	var notification Notification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return Notification{}, fmt.Errorf("payment: invalid notification: %w", err)
+	}
+	return notification, nil
+}
@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeCharges(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake("rahasia", time.Minute)
+
+	charge, err := fake.CreateCharge(ctx, ChargeRequest{Key: "P1-1", Amount: money.IDR(50000), Token: TokenSuccess})
+	require.NoError(t, err)
+	assert.Equal(t, StatusAuthorized, charge.Status)
+	again, err := fake.CreateCharge(ctx, ChargeRequest{Key: "P1-1", Amount: money.IDR(50000), Token: TokenSuccess})
+	require.NoError(t, err)
+	assert.Equal(t, charge.ID, again.ID, "the key makes the request idempotent")
+
+	charge, err = fake.Capture(ctx, charge.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCaptured, charge.Status)
+	charge, err = fake.Refund(ctx, charge.ID, money.IDR(20000))
+	require.NoError(t, err)
+	assert.Equal(t, StatusCaptured, charge.Status)
+	assert.Equal(t, money.IDR(20000), charge.Refunded)
+	_, err = fake.Refund(ctx, charge.ID, money.IDR(40000))
+	assert.ErrorIs(t, err, ErrInvalidState)
+	charge, err = fake.Refund(ctx, charge.ID, money.IDR(30000))
+	require.NoError(t, err)
+	assert.Equal(t, StatusRefunded, charge.Status)
+
+	declined, err := fake.CreateCharge(ctx, ChargeRequest{Key: "P2-1", Amount: money.IDR(50000), Token: TokenDecline})
+	require.NoError(t, err)
+	assert.Equal(t, StatusDeclined, declined.Status)
+	assert.Equal(t, "insufficient funds", declined.DeclineReason)
+	_, err = fake.Capture(ctx, declined.ID)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	_, err = fake.CreateCharge(ctx, ChargeRequest{Key: "P3-1", Amount: money.IDR(50000), Token: TokenTimeout})
+	assert.ErrorIs(t, err, ErrTimeout)
+	retried, err := fake.CreateCharge(ctx, ChargeRequest{Key: "P3-1", Amount: money.IDR(50000), Token: TokenTimeout})
+	require.NoError(t, err)
+	assert.Equal(t, StatusAuthorized, retried.Status, "the charge was made despite the timeout")
+
+	_, err = fake.Status(ctx, "ch_404")
+	assert.ErrorIs(t, err, ErrUnknownCharge)
+}
+
+func TestFakeSettlement(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	fake := NewFake("rahasia", 5*time.Minute)
+	fake.Clock = func() time.Time { return now }
+
+	charge, err := fake.CreateCharge(ctx, ChargeRequest{Key: "P1-2", Amount: money.IDR(75000), Token: TokenDelayed})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, charge.Status)
+	_, err = fake.Capture(ctx, charge.ID)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	webhook, err := fake.Settle(charge.ID, StatusCaptured)
+	require.NoError(t, err)
+	header := func(key string) string { return webhook.Header[key] }
+	notification, err := fake.ParseNotification(header, webhook.Body)
+	require.NoError(t, err)
+	assert.Equal(t, charge.ID, notification.ChargeID)
+	assert.Equal(t, "P1-2", notification.Key)
+	assert.Equal(t, StatusCaptured, notification.Status)
+	status, err := fake.Status(ctx, charge.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCaptured, status.Status)
+	_, err = fake.Settle(charge.ID, StatusDeclined)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	_, err = fake.ParseNotification(header, append(webhook.Body, ' '))
+	assert.ErrorIs(t, err, ErrInvalidSignature, "the body is signed")
+	now = now.Add(6 * time.Minute)
+	_, err = fake.ParseNotification(header, webhook.Body)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "a notification older than the tolerance is a replay")
+}
@@ -0,0 +1,108 @@
+// Package payment is the boundary with the gateways that charge cards. A Provider authorizes a
+// charge, captures it, refunds it and reports its status; asynchronous changes, such as a charge
+// settled later, arrive as signed notifications on a webhook.
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Status of a Charge
+const (
+	// StatusPending charges wait for the gateway, a notification tells how they end
+	StatusPending    = "pending"
+	StatusAuthorized = "authorized"
+	StatusCaptured   = "captured"
+	StatusDeclined   = "declined"
+	// StatusRefunded charges were refunded in full, or voided before their capture
+	StatusRefunded = "refunded"
+)
+
+var (
+	// ErrTimeout is returned when the gateway did not answer in time, the charge may or may not
+	// exist: retry with the same key to find out
+	ErrTimeout          = errors.New("payment: the gateway did not answer in time")
+	ErrUnknownCharge    = errors.New("payment: unknown charge")
+	ErrInvalidState     = errors.New("payment: the charge does not allow this operation")
+	ErrInvalidSignature = errors.New("payment: invalid notification signature")
+)
+
+type ChargeRequest struct {
+	// Key makes the request idempotent, a retry with the same key returns the same charge
+	Key string
+	// Reference is shown to the customer, e.g. the order number
+	Reference string
+	Amount    money.Money
+	// Token is the card as tokenized by the terminal or the payment page
+	Token      string
+	CustomerID string
+}
+
+type Charge struct {
+	ID       string
+	Key      string
+	Status   string
+	Amount   money.Money
+	Refunded money.Money
+	// DeclineReason tells the cashier why a declined charge was declined
+	DeclineReason string
+}
+
+// Notification is a change of a charge sent by the gateway
+type Notification struct {
+	// ID is the same on every redelivery of a notification
+	ID            string `json:"id"`
+	ChargeID      string `json:"charge_id"`
+	Key           string `json:"key"`
+	Status        string `json:"status"`
+	DeclineReason string `json:"decline_reason,omitempty"`
+}
+
+// Provider is a payment gateway
+type Provider interface {
+	// Name is the name of the gateway in the URL of its webhook
+	Name() string
+	// CreateCharge authorizes a charge, a decline is a charge in StatusDeclined and not an error
+	CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error)
+	Capture(ctx context.Context, chargeId string) (Charge, error)
+	// Refund gives back part of a captured charge, or voids a charge not captured yet
+	Refund(ctx context.Context, chargeId string, amount money.Money) (Charge, error)
+	Status(ctx context.Context, chargeId string) (Charge, error)
+	// ParseNotification checks the signature and the age of a webhook request and reads it,
+	// header returns the value of a request header
+	ParseNotification(header func(key string) string, body []byte) (Notification, error)
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret, the timestamp is
+// signed so that a captured request cannot be replayed later with a fresh one
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature made by Sign and that its timestamp is within tolerance of now
+func Verify(secret string, timestampHeader string, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	timestamp := time.Unix(seconds, 0)
+	if now.Sub(timestamp) > tolerance || timestamp.Sub(now) > tolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package pricing prices a basket: it applies the promotions to the lines and computes the tax
+// of what is left with package tax.
+package pricing
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/tax"
+)
+
+// Line is one product of a basket
+type Line struct {
+	ProductID string
+	VariantID string
+	Name      string
+	Category  string
+	// UnitPrice includes the tax when the prices are tax inclusive
+	UnitPrice money.Money
+	Quantity  int
+	TaxClass  string
+	TaxRates  []domain.TaxRate
+}
+
+// Applied is the discount given by one promotion
+type Applied struct {
+	PromotionID string
+	Name        string
+	Discount    money.Money
+}
+
+type LineResult struct {
+	Line
+	Subtotal   money.Money
+	Discount   money.Money
+	Promotions []Applied
+	Tax        money.Money
+	Taxes      []tax.Amount
+	Total      money.Money
+}
+
+type Result struct {
+	Lines []LineResult
+	// Promotions sums the discount of every promotion over the lines, in the order applied
+	Promotions []Applied
+	// PriceMode is tax.Inclusive when the prices, and so Subtotal, include the tax
+	PriceMode string
+	Subtotal  money.Money
+	Discount  money.Money
+	Tax       money.Money
+	// Taxes is the breakdown of Tax per rate
+	Taxes []tax.Amount
+	Total money.Money
+}
+
+// Quote prices lines for a customer, customerId may be empty for an anonymous sale. Only the
+// promotions in effect at at are applied, the tax follows settings. The lines are all priced
+// in one currency, the promotions of an amount in another currency give them no discount.
+func Quote(lines []Line, customerId string, promotions []domain.Promotion, at time.Time, settings tax.Settings) Result {
+	currency := money.DefaultCurrency
+	if len(lines) > 0 {
+		currency = lines[0].UnitPrice.Currency()
+	}
+	zero := money.New(0, currency)
+	result := Result{Lines: make([]LineResult, 0, len(lines)), PriceMode: settings.Mode, Subtotal: zero, Discount: zero}
+	for _, line := range lines {
+		result.Lines = append(result.Lines, LineResult{Line: line, Subtotal: line.UnitPrice.Mul(int64(line.Quantity)), Discount: zero})
+	}
+
+	// exclusive marks the lines taken by a promotion that does not stack
+	exclusive := make([]bool, len(lines))
+	for _, promotion := range inEffect(promotions, customerId, at) {
+		applied := Applied{PromotionID: promotion.PromotionID, Name: promotion.Name, Discount: zero}
+		for i := range result.Lines {
+			line := &result.Lines[i]
+			if exclusive[i] || !targets(promotion, line.Line) {
+				continue
+			}
+			if !promotion.Stackable && line.Discount.IsPositive() {
+				continue
+			}
+			discount := discountOf(promotion, *line).Min(line.Subtotal.Sub(line.Discount))
+			if !discount.IsPositive() {
+				continue
+			}
+			line.Discount = line.Discount.Add(discount)
+			line.Promotions = append(line.Promotions, Applied{PromotionID: promotion.PromotionID, Name: promotion.Name, Discount: discount})
+			applied.Discount = applied.Discount.Add(discount)
+			exclusive[i] = !promotion.Stackable
+		}
+		if applied.Discount.IsPositive() {
+			result.Promotions = append(result.Promotions, applied)
+		}
+	}
+
+	taxLines := make([]tax.Line, 0, len(lines))
+	for _, line := range result.Lines {
+		taxLines = append(taxLines, tax.Line{Amount: line.Subtotal.Sub(line.Discount), Rates: line.TaxRates})
+		result.Subtotal = result.Subtotal.Add(line.Subtotal)
+		result.Discount = result.Discount.Add(line.Discount)
+	}
+	taxes := tax.Calculate(taxLines, settings)
+	for i := range result.Lines {
+		result.Lines[i].Tax = taxes.Lines[i].Tax
+		result.Lines[i].Taxes = taxes.Lines[i].Taxes
+		result.Lines[i].Total = taxes.Lines[i].Gross
+	}
+	result.Tax = taxes.Tax
+	result.Taxes = taxes.Taxes
+	result.Total = taxes.Gross
+	return result
+}
+
+// inEffect keeps the promotions a customer can use at a time, highest priority first
+func inEffect(promotions []domain.Promotion, customerId string, at time.Time) []domain.Promotion {
+	var usable []domain.Promotion
+	for _, promotion := range promotions {
+		switch {
+		case !promotion.Active:
+		case promotion.StartsAt != nil && at.Before(*promotion.StartsAt):
+		case promotion.EndsAt != nil && !at.Before(*promotion.EndsAt):
+		case promotion.UsageLimit > 0 && promotion.UsageCount >= promotion.UsageLimit:
+		case len(promotion.CustomerIDs) > 0 && !contains(promotion.CustomerIDs, customerId):
+		default:
+			usable = append(usable, promotion)
+		}
+	}
+	sort.SliceStable(usable, func(i, j int) bool {
+		if usable[i].Priority != usable[j].Priority {
+			return usable[i].Priority > usable[j].Priority
+		}
+		return usable[i].PromotionID < usable[j].PromotionID
+	})
+	return usable
+}
+
+func targets(promotion domain.Promotion, line Line) bool {
+	if len(promotion.ProductIDs) == 0 && len(promotion.Categories) == 0 {
+		return true
+	}
+	return contains(promotion.ProductIDs, line.ProductID) || contains(promotion.Categories, line.Category)
+}
+
+// discountOf is the discount of a promotion on a whole line, before the cap to what is left of
+// its price. A percentage applies to the price left by the promotions applied before, an amount
+// only to the lines of its currency.
+func discountOf(promotion domain.Promotion, line LineResult) money.Money {
+	currency := line.UnitPrice.Currency()
+	switch promotion.Type {
+	case domain.PromotionPercentage:
+		return line.Subtotal.Sub(line.Discount).Percent(promotion.Value)
+	case domain.PromotionFixedAmount:
+		if promotion.Amount.Currency() == currency {
+			return promotion.Amount.Mul(int64(line.Quantity))
+		}
+	case domain.PromotionBuyXGetY:
+		if group := promotion.BuyQty + promotion.GetQty; group > 0 {
+			return line.UnitPrice.Mul(int64(line.Quantity / group * promotion.GetQty))
+		}
+	case domain.PromotionBundlePrice:
+		if promotion.BuyQty > 0 && promotion.Amount.Currency() == currency {
+			bundle := line.UnitPrice.Mul(int64(promotion.BuyQty)).Sub(promotion.Amount)
+			return bundle.Mul(int64(line.Quantity / promotion.BuyQty))
+		}
+	}
+	return money.New(0, currency)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	yesterday, tomorrow := now.Add(-24*time.Hour), now.Add(24*time.Hour)
+
+	ppn := []domain.TaxRate{{Code: "PPN", Percent: 11}}
+	laptop := Line{ProductID: "P001", Category: "Gaming Laptop", UnitPrice: money.IDR(15000000), Quantity: 1, TaxRates: ppn}
+	coffee := Line{ProductID: "P002", Category: "Food", UnitPrice: money.IDR(35000), Quantity: 3, TaxRates: ppn}
+	promotion := func(id string, promotionType string, value float64) domain.Promotion {
+		if domain.HasAmount(promotionType) {
+			return domain.Promotion{PromotionID: id, Name: id, Type: promotionType, Amount: money.IDR(int64(value)), Active: true}
+		}
+		return domain.Promotion{PromotionID: id, Name: id, Type: promotionType, Value: value, Active: true}
+	}
+
+	tests := []struct {
+		name       string
+		lines      []Line
+		customerId string
+		promotions []domain.Promotion
+		discounts  []int64
+		applied    []string
+	}{
+		{
+			name:      "no promotion",
+			lines:     []Line{laptop, coffee},
+			discounts: []int64{0, 0},
+		},
+		{
+			name:  "percentage on a category",
+			lines: []Line{laptop, coffee},
+			promotions: []domain.Promotion{func() domain.Promotion {
+				p := promotion("weekend", domain.PromotionPercentage, 10)
+				p.Categories = []string{"Gaming Laptop"}
+				return p
+			}()},
+			discounts: []int64{1500000, 0},
+			applied:   []string{"weekend"},
+		},
+		{
+			name:  "buy 2 get 1",
+			lines: []Line{coffee},
+			promotions: []domain.Promotion{func() domain.Promotion {
+				p := promotion("b2g1", domain.PromotionBuyXGetY, 0)
+				p.BuyQty, p.GetQty, p.ProductIDs = 2, 1, []string{"P002"}
+				return p
+			}()},
+			discounts: []int64{35000},
+			applied:   []string{"b2g1"},
+		},
+		{
+			name:  "bundle price",
+			lines: []Line{{ProductID: "P002", UnitPrice: money.IDR(35000), Quantity: 5}},
+			promotions: []domain.Promotion{func() domain.Promotion {
+				p := promotion("2for60", domain.PromotionBundlePrice, 60000)
+				p.BuyQty = 2
+				return p
+			}()},
+			discounts: []int64{20000},
+			applied:   []string{"2for60"},
+		},
+		{
+			name:       "fixed amount capped to the price",
+			lines:      []Line{{ProductID: "P003", UnitPrice: money.IDR(4000), Quantity: 2}},
+			promotions: []domain.Promotion{promotion("5k", domain.PromotionFixedAmount, 5000)},
+			discounts:  []int64{8000},
+			applied:    []string{"5k"},
+		},
+		{
+			name:  "exclusive promotion of higher priority wins",
+			lines: []Line{coffee},
+			promotions: []domain.Promotion{
+				func() domain.Promotion {
+					p := promotion("small", domain.PromotionPercentage, 5)
+					p.Priority = 1
+					return p
+				}(),
+				func() domain.Promotion {
+					p := promotion("big", domain.PromotionPercentage, 20)
+					p.Priority = 9
+					return p
+				}(),
+			},
+			discounts: []int64{21000},
+			applied:   []string{"big"},
+		},
+		{
+			name:  "stackable promotions combine on what is left",
+			lines: []Line{coffee},
+			promotions: []domain.Promotion{
+				func() domain.Promotion {
+					p := promotion("first", domain.PromotionFixedAmount, 5000)
+					p.Priority, p.Stackable = 2, true
+					return p
+				}(),
+				func() domain.Promotion {
+					p := promotion("second", domain.PromotionPercentage, 10)
+					p.Priority, p.Stackable = 1, true
+					return p
+				}(),
+			},
+			// 15000 off 105000, then 10% of 90000
+			discounts: []int64{24000},
+			applied:   []string{"first", "second"},
+		},
+		{
+			name:  "outside the window, exhausted or inactive",
+			lines: []Line{coffee},
+			promotions: []domain.Promotion{
+				func() domain.Promotion {
+					p := promotion("later", domain.PromotionPercentage, 10)
+					p.StartsAt = &tomorrow
+					return p
+				}(),
+				func() domain.Promotion {
+					p := promotion("over", domain.PromotionPercentage, 10)
+					p.EndsAt = &yesterday
+					return p
+				}(),
+				func() domain.Promotion {
+					p := promotion("used", domain.PromotionPercentage, 10)
+					p.UsageLimit, p.UsageCount = 5, 5
+					return p
+				}(),
+				func() domain.Promotion {
+					p := promotion("off", domain.PromotionPercentage, 10)
+					p.Active = false
+					return p
+				}(),
+			},
+			discounts: []int64{0},
+		},
+		{
+			name:       "member only",
+			lines:      []Line{coffee},
+			customerId: "C001",
+			promotions: []domain.Promotion{
+				func() domain.Promotion {
+					p := promotion("member", domain.PromotionPercentage, 10)
+					p.CustomerIDs = []string{"C001"}
+					return p
+				}(),
+				func() domain.Promotion {
+					p := promotion("other", domain.PromotionPercentage, 50)
+					p.CustomerIDs = []string{"C002"}
+					return p
+				}(),
+			},
+			discounts: []int64{10500},
+			applied:   []string{"member"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Quote(tt.lines, tt.customerId, tt.promotions, now, tax.Default)
+			require.Len(t, result.Lines, len(tt.lines))
+
+			var applied []string
+			for _, promotion := range result.Promotions {
+				applied = append(applied, promotion.PromotionID)
+			}
+			assert.Equal(t, tt.applied, applied)
+
+			var subtotal, discount, tax money.Money
+			for i, line := range result.Lines {
+				assert.Equal(t, money.IDR(tt.discounts[i]), line.Discount, "line %d", i)
+				if len(line.TaxRates) > 0 {
+					assert.Equal(t, line.Subtotal.Sub(line.Discount).Percent(11), line.Tax)
+				}
+				subtotal = subtotal.Add(line.Subtotal)
+				discount = discount.Add(line.Discount)
+				tax = tax.Add(line.Tax)
+			}
+			assert.Equal(t, subtotal, result.Subtotal)
+			assert.Equal(t, discount, result.Discount)
+			assert.Equal(t, subtotal.Sub(discount).Add(tax), result.Total)
+		})
+	}
+}
+
+func TestQuoteTax(t *testing.T) {
+	lines := []Line{{ProductID: "P002", UnitPrice: money.IDR(35000), Quantity: 3, TaxRates: []domain.TaxRate{{Code: "PPN", Percent: 11}}}}
+	promotions := []domain.Promotion{{PromotionID: "b2g1", Type: domain.PromotionBuyXGetY, BuyQty: 2, GetQty: 1, Active: true}}
+
+	t.Run("exclusive", func(t *testing.T) {
+		result := Quote(lines, "", promotions, time.Now(), tax.Default)
+		assert.Equal(t, money.IDR(105000), result.Subtotal)
+		assert.Equal(t, money.IDR(35000), result.Discount)
+		assert.Equal(t, money.IDR(7700), result.Tax)
+		assert.Equal(t, money.IDR(77700), result.Total)
+		require.Len(t, result.Taxes, 1)
+		assert.Equal(t, money.IDR(70000), result.Taxes[0].Base)
+	})
+
+	t.Run("inclusive", func(t *testing.T) {
+		result := Quote(lines, "", promotions, time.Now(), tax.Settings{Mode: tax.Inclusive, Rounding: tax.RoundLine})
+		assert.Equal(t, tax.Inclusive, result.PriceMode)
+		assert.Equal(t, money.IDR(105000), result.Subtotal)
+		assert.Equal(t, money.IDR(35000), result.Discount)
+		// 70000 includes 11% of 63063.06
+		assert.Equal(t, money.IDR(6937), result.Tax)
+		assert.Equal(t, money.IDR(70000), result.Total)
+	})
+}
+
+func TestQuotePromotionOfAnotherCurrency(t *testing.T) {
+	lines := []Line{{ProductID: "P010", UnitPrice: money.New(1500, "USD"), Quantity: 2}}
+	promotions := []domain.Promotion{{PromotionID: "5k", Type: domain.PromotionFixedAmount, Amount: money.IDR(5000), Active: true}}
+
+	result := Quote(lines, "", promotions, time.Now(), tax.Default)
+	assert.Empty(t, result.Promotions)
+	assert.Equal(t, money.New(0, "USD"), result.Discount)
+	assert.Equal(t, money.New(3000, "USD"), result.Lines[0].Subtotal)
+}
@@ -0,0 +1,34 @@
+package qris
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PNG draws a QR code of content as a PNG image of size by size pixels, with error correction
+// level M like the printed payment codes
+func PNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// SVG draws a QR code of content with error correction level M as an SVG document, one unit
+// per module and the quiet zone around them
+func SVG(content string) (string, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	bitmap := code.Bitmap()
+	var path strings.Builder
+	for y, modules := range bitmap {
+		for x, dark := range modules {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	size := len(bitmap)
+	return fmt.Sprintf(`<svg class="qr" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges"><path d="%s"/></svg>`, size, size, path.String()), nil
+}
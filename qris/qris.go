@@ -0,0 +1,331 @@
+// Package qris builds and reads QRIS payloads, the Indonesian standard for the merchant
+// presented QR codes of EMVCo. A payload is a string of tag-length-value fields closed by a
+// CRC16 checksum; a static payload lets the customer enter the amount, a dynamic one carries the
+// amount of a sale.
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Tags of the root fields
+const (
+	tagFormat         = "00"
+	tagInitiation     = "01"
+	tagAcquirer       = "26"
+	tagQRIS           = "51"
+	tagCategory       = "52"
+	tagCurrency       = "53"
+	tagAmount         = "54"
+	tagCountry        = "58"
+	tagName           = "59"
+	tagCity           = "60"
+	tagPostalCode     = "61"
+	tagAdditional     = "62"
+	tagCRC            = "63"
+	tagBillNumber     = "01"
+	tagGlobalID       = "00"
+	tagPAN            = "01"
+	tagMerchantID     = "02"
+	tagCriteria       = "03"
+	initiationStatic  = "11"
+	initiationDynamic = "12"
+	// qrisGlobalID identifies the national merchant repository in the field of the NMID
+	qrisGlobalID = "ID.CO.QRIS.WWW"
+)
+
+// currencies maps the supported currencies to their ISO 4217 numeric code
+var currencies = map[string]string{"IDR": "360", "USD": "840"}
+
+// Criteria of a merchant, by business size
+var criteria = []string{"UMI", "UKE", "UME", "UBE", "URE"}
+
+// Merchant is the merchant paid by the payloads, as registered with its acquirer
+type Merchant struct {
+	// AcquirerID is the reverse domain name of the acquirer, e.g. ID.CO.BANKDEMO.WWW
+	AcquirerID string
+	// PAN is the merchant PAN issued by the acquirer, 16 to 19 digits
+	PAN        string
+	MerchantID string
+	// NMID is the national merchant ID, e.g. ID1020012345678
+	NMID string
+	// Criteria is UMI, UKE, UME, UBE or URE
+	Criteria string
+	// CategoryCode is the ISO 18245 merchant category code, e.g. 5411 for groceries
+	CategoryCode string
+	Name         string
+	City         string
+	PostalCode   string
+}
+
+// Validate reports the fields of the merchant that cannot be encoded
+func (merchant Merchant) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	check(merchant.AcquirerID != "" && len(merchant.AcquirerID) <= 32, "acquirer id must have 1 to 32 characters")
+	check(isDigits(merchant.PAN) && len(merchant.PAN) >= 16 && len(merchant.PAN) <= 19, "merchant PAN must have 16 to 19 digits")
+	check(len(merchant.MerchantID) <= 15, "merchant id must have at most 15 characters")
+	check(merchant.NMID != "" && len(merchant.NMID) <= 15, "NMID must have 1 to 15 characters")
+	check(contains(criteria, merchant.Criteria), "merchant criteria must be one of %s", strings.Join(criteria, ", "))
+	check(isDigits(merchant.CategoryCode) && len(merchant.CategoryCode) == 4, "merchant category code must have 4 digits")
+	check(merchant.Name != "" && len(merchant.Name) <= 25, "merchant name must have 1 to 25 characters")
+	check(merchant.City != "" && len(merchant.City) <= 15, "merchant city must have 1 to 15 characters")
+	check(len(merchant.PostalCode) <= 10, "postal code must have at most 10 characters")
+	return errors.Join(errs...)
+}
+
+// Payload is what a QR code tells the payment app of the customer
+type Payload struct {
+	Merchant Merchant
+	// Amount is zero in a static payload, the customer then enters it
+	Amount money.Money
+	// Reference is the bill number the payment is reconciled with, e.g. the order number
+	Reference string
+}
+
+// Dynamic tells whether the payload carries an amount
+func (payload Payload) Dynamic() bool {
+	return !payload.Amount.IsZero()
+}
+
+// Encode writes a payload with its checksum
+func Encode(payload Payload) (string, error) {
+	if err := payload.Merchant.Validate(); err != nil {
+		return "", err
+	}
+	if payload.Amount.IsNegative() {
+		return "", errors.New("amount must not be negative")
+	}
+	currency, ok := currencies[payload.Amount.Currency()]
+	if !ok {
+		return "", fmt.Errorf("currency %s is not supported", payload.Amount.Currency())
+	}
+	if len(payload.Reference) > 25 {
+		return "", errors.New("reference must have at most 25 characters")
+	}
+
+	merchant := payload.Merchant
+	var out strings.Builder
+	writeField(&out, tagFormat, "01")
+	if payload.Dynamic() {
+		writeField(&out, tagInitiation, initiationDynamic)
+	} else {
+		writeField(&out, tagInitiation, initiationStatic)
+	}
+	writeField(&out, tagAcquirer, template(
+		tagGlobalID, merchant.AcquirerID,
+		tagPAN, merchant.PAN,
+		tagMerchantID, merchant.MerchantID,
+		tagCriteria, merchant.Criteria,
+	))
+	writeField(&out, tagQRIS, template(
+		tagGlobalID, qrisGlobalID,
+		tagMerchantID, merchant.NMID,
+		tagCriteria, merchant.Criteria,
+	))
+	writeField(&out, tagCategory, merchant.CategoryCode)
+	writeField(&out, tagCurrency, currency)
+	if payload.Dynamic() {
+		amount := payload.Amount.String()
+		if len(amount) > 13 {
+			return "", fmt.Errorf("amount %s is too large", amount)
+		}
+		writeField(&out, tagAmount, amount)
+	}
+	writeField(&out, tagCountry, "ID")
+	writeField(&out, tagName, merchant.Name)
+	writeField(&out, tagCity, merchant.City)
+	if merchant.PostalCode != "" {
+		writeField(&out, tagPostalCode, merchant.PostalCode)
+	}
+	if payload.Reference != "" {
+		writeField(&out, tagAdditional, template(tagBillNumber, payload.Reference))
+	}
+	out.WriteString(tagCRC + "04")
+	return out.String() + checksum(out.String()), nil
+}
+
+// Parse reads a payload scanned back and checks its checksum and its mandatory fields
+func Parse(text string) (Payload, error) {
+	if len(text) < 8 || text[len(text)-8:len(text)-4] != tagCRC+"04" {
+		return Payload{}, errors.New("payload does not end with a checksum")
+	}
+	if want, got := checksum(text[:len(text)-4]), strings.ToUpper(text[len(text)-4:]); want != got {
+		return Payload{}, fmt.Errorf("checksum is %s, expected %s", got, want)
+	}
+	fields, err := decode(text[:len(text)-8])
+	if err != nil {
+		return Payload{}, err
+	}
+	if len(fields) == 0 || fields[0].tag != tagFormat || fields[0].value != "01" {
+		return Payload{}, errors.New("payload does not start with the format indicator 01")
+	}
+	root := index(fields)
+
+	var payload Payload
+	currencyCode := ""
+	for code, numeric := range currencies {
+		if numeric == root[tagCurrency] {
+			currencyCode = code
+		}
+	}
+	if currencyCode == "" {
+		return Payload{}, fmt.Errorf("currency %q is not supported", root[tagCurrency])
+	}
+	if root[tagCountry] != "ID" {
+		return Payload{}, fmt.Errorf("country is %q, expected ID", root[tagCountry])
+	}
+
+	initiation := root[tagInitiation]
+	if raw, ok := root[tagAmount]; ok {
+		amount, err := money.Parse(raw, currencyCode)
+		if err != nil || !amount.IsPositive() {
+			return Payload{}, fmt.Errorf("amount %q is not a positive amount", raw)
+		}
+		payload.Amount = amount
+	} else {
+		payload.Amount = money.New(0, currencyCode)
+	}
+	if payload.Dynamic() != (initiation == initiationDynamic) || (initiation != initiationStatic && initiation != initiationDynamic) {
+		return Payload{}, fmt.Errorf("point of initiation %q does not match the amount", initiation)
+	}
+
+	acquirer, err := subfields(root, tagAcquirer)
+	if err != nil {
+		return Payload{}, err
+	}
+	national, err := subfields(root, tagQRIS)
+	if err != nil {
+		return Payload{}, err
+	}
+	if national[tagGlobalID] != qrisGlobalID {
+		return Payload{}, fmt.Errorf("field %s is not a QRIS merchant field", tagQRIS)
+	}
+	additional, err := subfields(root, tagAdditional)
+	if err != nil {
+		return Payload{}, err
+	}
+
+	payload.Merchant = Merchant{
+		AcquirerID:   acquirer[tagGlobalID],
+		PAN:          acquirer[tagPAN],
+		MerchantID:   acquirer[tagMerchantID],
+		NMID:         national[tagMerchantID],
+		Criteria:     national[tagCriteria],
+		CategoryCode: root[tagCategory],
+		Name:         root[tagName],
+		City:         root[tagCity],
+		PostalCode:   root[tagPostalCode],
+	}
+	payload.Reference = additional[tagBillNumber]
+	if err := payload.Merchant.Validate(); err != nil {
+		return Payload{}, err
+	}
+	return payload, nil
+}
+
+// checksum is the CRC16 of data as 4 uppercase hexadecimal digits
+func checksum(data string) string {
+	return fmt.Sprintf("%04X", CRC16([]byte(data)))
+}
+
+// CRC16 is the CRC-16/CCITT-FALSE of data: polynomial 0x1021, initial value 0xFFFF
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+type field struct {
+	tag   string
+	value string
+}
+
+func writeField(out *strings.Builder, tag string, value string) {
+	fmt.Fprintf(out, "%s%02d%s", tag, len(value), value)
+}
+
+// template writes tag and value pairs as the value of a template field, empty values are left out
+func template(pairs ...string) string {
+	var out strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			writeField(&out, pairs[i], pairs[i+1])
+		}
+	}
+	return out.String()
+}
+
+// decode splits data in its fields
+func decode(data string) ([]field, error) {
+	var fields []field
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("truncated field %q", data)
+		}
+		tag := data[:2]
+		length, err := strconv.Atoi(data[2:4])
+		if err != nil || !isDigits(tag) || !isDigits(data[2:4]) {
+			return nil, fmt.Errorf("invalid field header %q", data[:4])
+		}
+		if len(data) < 4+length {
+			return nil, fmt.Errorf("field %s is truncated", tag)
+		}
+		fields = append(fields, field{tag: tag, value: data[4 : 4+length]})
+		data = data[4+length:]
+	}
+	return fields, nil
+}
+
+func index(fields []field) map[string]string {
+	values := make(map[string]string, len(fields))
+	for _, field := range fields {
+		values[field.tag] = field.value
+	}
+	return values
+}
+
+// subfields decodes the template field tag, empty when it is absent
+func subfields(root map[string]string, tag string) (map[string]string, error) {
+	fields, err := decode(root[tag])
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", tag, err)
+	}
+	return index(fields), nil
+}
+
+func isDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return text != ""
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
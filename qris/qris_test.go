@@ -0,0 +1,120 @@
+package qris
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var merchant = Merchant{
+	AcquirerID:   "ID.CO.BANKDEMO.WWW",
+	PAN:          "9360000812345678901",
+	MerchantID:   "000195000123",
+	NMID:         "ID1020012345678",
+	Criteria:     "UMI",
+	CategoryCode: "5411",
+	Name:         "Toko Maju Jaya",
+	City:         "JAKARTA",
+	PostalCode:   "10310",
+}
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), CRC16([]byte("123456789")))
+}
+
+func TestEncode(t *testing.T) {
+	payload, err := Encode(Payload{Merchant: merchant, Amount: money.IDR(116550), Reference: "1A2B3C4D"})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(payload, "000201"+"010212"+"2668"+"0018ID.CO.BANKDEMO.WWW"+"01199360000812345678901"+"0212000195000123"+"0303UMI"))
+	assert.Contains(t, payload, "5144"+"0014ID.CO.QRIS.WWW"+"0215ID1020012345678"+"0303UMI")
+	assert.Contains(t, payload, "52045411"+"5303360"+"5406116550"+"5802ID"+"5914Toko Maju Jaya"+"6007JAKARTA"+"610510310")
+	assert.Contains(t, payload, "6212"+"01081A2B3C4D"+"6304")
+	assert.Equal(t, checksum(payload[:len(payload)-4]), payload[len(payload)-4:])
+
+	static, err := Encode(Payload{Merchant: merchant})
+	require.NoError(t, err)
+	assert.Contains(t, static, "010211")
+	assert.NotContains(t, static, "5406")
+}
+
+func TestEncodeRejects(t *testing.T) {
+	long := merchant
+	long.Name = "Toko Maju Jaya Sentosa Abadi"
+	tests := map[string]Payload{
+		"long name":       {Merchant: long},
+		"no NMID":         {Merchant: Merchant{AcquirerID: merchant.AcquirerID, PAN: merchant.PAN, Criteria: "UMI", CategoryCode: "5411", Name: "Toko", City: "JAKARTA"}},
+		"negative amount": {Merchant: merchant, Amount: money.IDR(-1)},
+		"large amount":    {Merchant: merchant, Amount: money.IDR(12345678901234)},
+		"long reference":  {Merchant: merchant, Amount: money.IDR(1000), Reference: strings.Repeat("X", 26)},
+	}
+	for name, payload := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Encode(payload)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, payload := range []Payload{
+		{Merchant: merchant, Amount: money.IDR(116550), Reference: "1A2B3C4D"},
+		{Merchant: merchant, Amount: money.IDR(0)},
+		{Merchant: merchant, Amount: money.New(1250, "USD")},
+	} {
+		text, err := Encode(payload)
+		require.NoError(t, err)
+		parsed, err := Parse(text)
+		require.NoError(t, err)
+		assert.Equal(t, payload, parsed)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	valid, err := Encode(Payload{Merchant: merchant, Amount: money.IDR(116550)})
+	require.NoError(t, err)
+	withChecksum := func(data string) string { return data + checksum(data) }
+
+	tests := map[string]string{
+		"empty":            "",
+		"no checksum":      valid[:len(valid)-8],
+		"tampered amount":  strings.Replace(valid, "5406116550", "5406916550", 1),
+		"tampered digit":   valid[:len(valid)-1] + "X",
+		"truncated field":  withChecksum("000201010212" + "5910Toko" + "6304"),
+		"format indicator": withChecksum(strings.Replace(valid[:len(valid)-4], "000201", "000202", 1)),
+		"static amount":    withChecksum(strings.Replace(valid[:len(valid)-4], "010212", "010211", 1)),
+		"country":          withChecksum(strings.Replace(valid[:len(valid)-4], "5802ID", "5802MY", 1)),
+		"currency":         withChecksum(strings.Replace(valid[:len(valid)-4], "5303360", "5303458", 1)),
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(text)
+			assert.Error(t, err)
+		})
+	}
+
+	lower := valid[:len(valid)-4] + strings.ToLower(valid[len(valid)-4:])
+	_, err = Parse(lower)
+	assert.NoError(t, err, "the checksum may be in lower case")
+}
+
+func TestImages(t *testing.T) {
+	payload, err := Encode(Payload{Merchant: merchant, Amount: money.IDR(116550)})
+	require.NoError(t, err)
+
+	image, err := PNG(payload, 256)
+	require.NoError(t, err)
+	decoded, err := png.Decode(bytes.NewReader(image))
+	require.NoError(t, err)
+	assert.Equal(t, 256, decoded.Bounds().Dx())
+
+	svg, err := SVG(payload)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(svg, `<svg class="qr"`))
+	assert.Contains(t, svg, `<path d="M`)
+}
@@ -0,0 +1,99 @@
+package receipt
+
+import (
+	"bytes"
+)
+
+// ESC/POS commands, see the Epson ESC/POS command reference
+var (
+	escInit        = []byte{0x1b, '@'}
+	escAlignLeft   = []byte{0x1b, 'a', 0}
+	escAlignCenter = []byte{0x1b, 'a', 1}
+	escBoldOn      = []byte{0x1b, 'E', 1}
+	escBoldOff     = []byte{0x1b, 'E', 0}
+	gsDoubleHeight = []byte{0x1d, '!', 0x01}
+	gsNormalSize   = []byte{0x1d, '!', 0x00}
+	escFeed4       = []byte{0x1b, 'd', 4}
+	gsPartialCut   = []byte{0x1d, 'V', 66, 0}
+)
+
+// ESCPOS renders the receipt as ESC/POS commands for a thermal printer loaded with paper.
+// Characters outside of ASCII are printed as '?', the code pages of the printers differ.
+func ESCPOS(receipt Receipt, paper Paper) []byte {
+	var out bytes.Buffer
+	out.Write(escInit)
+	centered := false
+	for _, row := range layout(receipt) {
+		if wantCentered := row.kind == rowCenter; wantCentered != centered {
+			if wantCentered {
+				out.Write(escAlignCenter)
+			} else {
+				out.Write(escAlignLeft)
+			}
+			centered = wantCentered
+		}
+		if row.strong {
+			out.Write(escBoldOn)
+			if row.kind == rowCenter {
+				out.Write(gsDoubleHeight)
+			}
+		}
+		for _, line := range renderRow(row, paper.Columns) {
+			if row.kind == rowCenter {
+				// the printer centers the line itself
+				line = trimLeft(line)
+			}
+			writeASCII(&out, line)
+			out.WriteByte('\n')
+		}
+		if row.strong {
+			if row.kind == rowCenter {
+				out.Write(gsNormalSize)
+			}
+			out.Write(escBoldOff)
+		}
+	}
+
+	if receipt.QR != "" {
+		if !centered {
+			out.Write(escAlignCenter)
+			centered = true
+		}
+		writeQR(&out, receipt.QR, paper.QRSize)
+	}
+	if centered {
+		out.Write(escAlignLeft)
+	}
+	out.Write(escFeed4)
+	out.Write(gsPartialCut)
+	return out.Bytes()
+}
+
+// writeQR prints a QR code of model 2 with error correction level M, modules of size dots
+func writeQR(out *bytes.Buffer, content string, size int) {
+	data := []byte(content)
+	length := len(data) + 3
+	out.Write([]byte{0x1d, '(', 'k', 4, 0, '1', 'A', '2', 0})
+	out.Write([]byte{0x1d, '(', 'k', 3, 0, '1', 'C', byte(size)})
+	out.Write([]byte{0x1d, '(', 'k', 3, 0, '1', 'E', '1'})
+	out.Write([]byte{0x1d, '(', 'k', byte(length % 256), byte(length / 256), '1', 'P', '0'})
+	out.Write(data)
+	out.Write([]byte{0x1d, '(', 'k', 3, 0, '1', 'Q', '0'})
+	out.WriteByte('\n')
+}
+
+func writeASCII(out *bytes.Buffer, text string) {
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			r = '?'
+		}
+		out.WriteByte(byte(r))
+	}
+}
+
+func trimLeft(line string) string {
+	for len(line) > 0 && line[0] == ' ' {
+		line = line[1:]
+	}
+	return line
+}
@@ -0,0 +1,89 @@
+package receipt
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/aronipurwanto/go-restful-api/qris"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Struk {{.Number}}</title>
+<style>
+body { margin: 0; }
+.receipt { width: {{.Paper}}; margin: 0 auto; padding: 2mm; font-family: monospace; font-size: 12px; }
+.receipt p { margin: 0; }
+.center { text-align: center; }
+.pair { display: flex; justify-content: space-between; white-space: pre; }
+.strong { font-weight: bold; }
+.store { font-size: 16px; }
+.qr { display: block; width: 40mm; margin: 2mm auto 0; }
+hr { border: 0; border-top: 1px dashed #000; }
+</style>
+</head>
+<body>
+<div class="receipt">
+{{- range .Rows}}
+{{- if eq .Kind "rule"}}
+<hr>
+{{- else if eq .Kind "pair"}}
+<div class="pair{{if .Strong}} strong{{end}}"><span>{{.Left}}</span><span>{{.Right}}</span></div>
+{{- else if eq .Kind "center"}}
+<p class="center{{if .Strong}} strong store{{end}}">{{.Left}}</p>
+{{- else}}
+<p>{{.Left}}</p>
+{{- end}}
+{{- end}}
+{{- if .QR}}
+{{.QR}}
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+type htmlRow struct {
+	Kind   string
+	Left   string
+	Right  string
+	Strong bool
+}
+
+var rowKinds = map[int]string{rowText: "text", rowCenter: "center", rowPair: "pair", rowRule: "rule"}
+
+// HTML renders the receipt as a page as wide as paper, the QR code is an inline SVG
+func HTML(receipt Receipt, paper Paper) ([]byte, error) {
+	data := struct {
+		Number string
+		Paper  string
+		Rows   []htmlRow
+		QR     template.HTML
+	}{Number: receipt.Number, Paper: paper.Name}
+	for _, row := range layout(receipt) {
+		data.Rows = append(data.Rows, htmlRow{Kind: rowKinds[row.kind], Left: strings.TrimSpace(row.left), Right: row.right, Strong: row.strong})
+	}
+	if receipt.QR != "" {
+		svg, err := qrSVG(receipt.QR)
+		if err != nil {
+			return nil, err
+		}
+		data.QR = svg
+	}
+
+	var out bytes.Buffer
+	if err := htmlTemplate.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// qrSVG draws a QR code as an inline SVG
+func qrSVG(content string) (template.HTML, error) {
+	svg, err := qris.SVG(content)
+	// the path only holds numbers and commands, it cannot inject markup
+	return template.HTML(svg), err
+}
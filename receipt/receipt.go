@@ -0,0 +1,273 @@
+// Package receipt renders the receipt of an order as HTML, plain text or ESC/POS commands for
+// thermal printers. The three formats share the same layout: the header of the store, the
+// order, its lines, the totals, the tax breakdown, the loyalty points, the footer and a QR code.
+// Reports, such as the end of day report of a cash drawer, are printed as plain text on the same
+// paper.
+package receipt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Formats of a receipt
+const (
+	FormatHTML   = "html"
+	FormatText   = "text"
+	FormatESCPOS = "escpos"
+)
+
+// Receipt is what is printed for an order
+type Receipt struct {
+	// Header are the lines on top of the receipt, the first one is the name of the store
+	Header   []string
+	Number   string
+	Date     time.Time
+	Cashier  string
+	Customer string
+	Lines    []Line
+	// PriceMode is inclusive when the prices already contain the tax
+	PriceMode string
+	Subtotal  money.Money
+	Discount  money.Money
+	Tax       money.Money
+	Total     money.Money
+	Taxes     []Tax
+	// PointsEarned and PointsBalance are only printed for a customer
+	PointsEarned  int
+	PointsBalance int
+	Footer        []string
+	// QR is the content of the QR code printed at the bottom, none when empty
+	QR string
+}
+
+// Line is a product sold
+type Line struct {
+	Name      string
+	Quantity  int
+	UnitPrice money.Money
+	Subtotal  money.Money
+	Discount  money.Money
+}
+
+// Tax is the tax of one rate and the base it was computed on
+type Tax struct {
+	Name string
+	Base money.Money
+	Tax  money.Money
+}
+
+// Paper is the width of the roll of a thermal printer
+type Paper struct {
+	Name string
+	// Columns is the number of characters of a line in the default font
+	Columns int
+	// QRSize is the size of a module of the QR code in dots
+	QRSize int
+}
+
+var (
+	Paper58 = Paper{Name: "58mm", Columns: 32, QRSize: 5}
+	Paper80 = Paper{Name: "80mm", Columns: 48, QRSize: 6}
+)
+
+// ParsePaper returns the paper of a width in millimetres, 58 or 80
+func ParsePaper(width string) (Paper, error) {
+	switch width {
+	case "58":
+		return Paper58, nil
+	case "80":
+		return Paper80, nil
+	}
+	return Paper{}, fmt.Errorf("unknown paper width %q, expected 58 or 80", width)
+}
+
+// ContentType is the media type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatESCPOS:
+		return "application/octet-stream"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Render a receipt in format
+func Render(receipt Receipt, format string, paper Paper) ([]byte, error) {
+	switch format {
+	case FormatHTML:
+		return HTML(receipt, paper)
+	case FormatText:
+		return Text(receipt, paper), nil
+	case FormatESCPOS:
+		return ESCPOS(receipt, paper), nil
+	}
+	return nil, fmt.Errorf("unknown receipt format %q, expected html, text or escpos", format)
+}
+
+// Kinds of row
+const (
+	rowText = iota
+	rowCenter
+	rowPair
+	rowRule
+)
+
+// row is a line of the layout: a text, a centered text, a label with an amount aligned on the
+// right or a rule. Strong rows stand out, the name of the store and the total.
+type row struct {
+	kind   int
+	left   string
+	right  string
+	strong bool
+}
+
+// layout lays the receipt out in rows, the formats then render them
+func layout(receipt Receipt) []row {
+	var rows []row
+	for i, line := range receipt.Header {
+		rows = append(rows, row{kind: rowCenter, left: line, strong: i == 0})
+	}
+	rows = append(rows,
+		row{kind: rowRule},
+		row{kind: rowPair, left: "No.", right: receipt.Number},
+		row{kind: rowPair, left: "Tanggal", right: receipt.Date.Format("02/01/2006 15:04")},
+		row{kind: rowPair, left: "Kasir", right: receipt.Cashier},
+	)
+	if receipt.Customer != "" {
+		rows = append(rows, row{kind: rowPair, left: "Pelanggan", right: receipt.Customer})
+	}
+
+	rows = append(rows, row{kind: rowRule})
+	for _, line := range receipt.Lines {
+		rows = append(rows,
+			row{kind: rowText, left: line.Name},
+			row{kind: rowPair, left: fmt.Sprintf("  %d x %s", line.Quantity, Amount(line.UnitPrice)), right: Amount(line.Subtotal)},
+		)
+		if !line.Discount.IsZero() {
+			rows = append(rows, row{kind: rowPair, left: "  Diskon", right: "-" + Amount(line.Discount)})
+		}
+	}
+
+	rows = append(rows, row{kind: rowRule}, row{kind: rowPair, left: "Subtotal", right: Amount(receipt.Subtotal)})
+	if !receipt.Discount.IsZero() {
+		rows = append(rows, row{kind: rowPair, left: "Diskon", right: "-" + Amount(receipt.Discount)})
+	}
+	if receipt.PriceMode != "inclusive" {
+		rows = append(rows, row{kind: rowPair, left: "Pajak", right: Amount(receipt.Tax)})
+	}
+	rows = append(rows, row{kind: rowPair, left: "TOTAL", right: Amount(receipt.Total), strong: true})
+
+	if len(receipt.Taxes) > 0 {
+		rows = append(rows, row{kind: rowRule})
+		if receipt.PriceMode == "inclusive" {
+			rows = append(rows, row{kind: rowText, left: "Harga termasuk pajak"})
+		}
+		for _, tax := range receipt.Taxes {
+			rows = append(rows,
+				row{kind: rowPair, left: "DPP " + tax.Name, right: Amount(tax.Base)},
+				row{kind: rowPair, left: tax.Name, right: Amount(tax.Tax)},
+			)
+		}
+	}
+
+	if receipt.Customer != "" {
+		rows = append(rows,
+			row{kind: rowRule},
+			row{kind: rowPair, left: "Poin didapat", right: "+" + strconv.Itoa(receipt.PointsEarned)},
+			row{kind: rowPair, left: "Saldo poin", right: strconv.Itoa(receipt.PointsBalance)},
+		)
+	}
+
+	if len(receipt.Footer) > 0 {
+		rows = append(rows, row{kind: rowRule})
+		for _, line := range receipt.Footer {
+			rows = append(rows, row{kind: rowCenter, left: line})
+		}
+	}
+	return rows
+}
+
+// Amount writes an amount the Indonesian way, with dots between the thousands and a decimal
+// comma: 15.000.000 or 12,50
+func Amount(amount money.Money) string {
+	text := amount.String()
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	whole, decimals, hasDecimals := strings.Cut(text, ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	if hasDecimals {
+		return sign + grouped.String() + "," + decimals
+	}
+	return sign + grouped.String()
+}
+
+// wrap splits text in lines of at most width characters, breaking between words when it can
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for len([]rune(word)) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pair puts left and right on a line of width characters, or on two lines when they do not fit
+func pair(left string, right string, width int) []string {
+	gap := width - len([]rune(left)) - len([]rune(right))
+	if gap >= 1 {
+		return []string{left + strings.Repeat(" ", gap) + right}
+	}
+	lines := wrap(left, width)
+	return append(lines, pad(right, width))
+}
+
+// pad aligns text on the right of a line of width characters
+func pad(text string, width int) string {
+	if n := width - len([]rune(text)); n > 0 {
+		return strings.Repeat(" ", n) + text
+	}
+	return text
+}
+
+// center text on a line of width characters, trailing spaces are left out
+func center(text string, width int) string {
+	if n := (width - len([]rune(text))) / 2; n > 0 {
+		return strings.Repeat(" ", n) + text
+	}
+	return text
+}
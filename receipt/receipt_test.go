@@ -0,0 +1,161 @@
+package receipt
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files: go test ./receipt -update
+var update = flag.Bool("update", false, "update the golden files")
+
+var sale = Receipt{
+	Header:   []string{"Toko Maju Jaya", "Jl. Merdeka No. 1, Bandung", "022-555-0101", "NPWP 01.234.567.8-901.000"},
+	Number:   "1A2B3C4D",
+	Date:     time.Date(2026, 10, 18, 14, 5, 0, 0, time.UTC),
+	Cashier:  "Andi Wijaya",
+	Customer: "Budi Santoso",
+	Lines: []Line{
+		{Name: "Laptop Gaming", Quantity: 1, UnitPrice: money.IDR(15000000), Subtotal: money.IDR(15000000), Discount: money.IDR(750000)},
+		{Name: "Kopi Bubuk Arabika Gayo Premium 250 gram", Quantity: 2, UnitPrice: money.IDR(35000), Subtotal: money.IDR(70000)},
+	},
+	PriceMode: "exclusive",
+	Subtotal:  money.IDR(15070000),
+	Discount:  money.IDR(750000),
+	Tax:       money.IDR(1575200),
+	Total:     money.IDR(15895200),
+	Taxes: []Tax{
+		{Name: "PPN 11%", Base: money.IDR(14320000), Tax: money.IDR(1575200)},
+	},
+	PointsEarned:  1589,
+	PointsBalance: 1709,
+	Footer:        []string{"Terima kasih atas kunjungan Anda", "Barang yang sudah dibeli tidak dapat ditukar"},
+	QR:            "https://example.com/r/1A2B3C4D",
+}
+
+// anonymous is an inclusive sale without a customer, a footer nor a QR code
+var anonymous = Receipt{
+	Header:    []string{"Kafe Kopi Kita"},
+	Number:    "9F00AB12",
+	Date:      time.Date(2026, 1, 2, 8, 30, 0, 0, time.UTC),
+	Cashier:   "Sari Dewi",
+	Lines:     []Line{{Name: "Café Latte", Quantity: 3, UnitPrice: money.IDR(27750), Subtotal: money.IDR(83250)}},
+	PriceMode: "inclusive",
+	Subtotal:  money.IDR(83250),
+	Tax:       money.IDR(8250),
+	Total:     money.IDR(83250),
+	Taxes:     []Tax{{Name: "PPN 11%", Base: money.IDR(75000), Tax: money.IDR(8250)}},
+}
+
+func TestGolden(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		receipt Receipt
+		format  string
+		paper   Paper
+	}{
+		{"sale_58.txt", sale, FormatText, Paper58},
+		{"sale_80.txt", sale, FormatText, Paper80},
+		{"sale_58.escpos", sale, FormatESCPOS, Paper58},
+		{"sale_80.escpos", sale, FormatESCPOS, Paper80},
+		{"sale_80.html", sale, FormatHTML, Paper80},
+		{"anonymous_58.txt", anonymous, FormatText, Paper58},
+		{"anonymous_58.escpos", anonymous, FormatESCPOS, Paper58},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Render(test.receipt, test.format, test.paper)
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", test.name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(want, got), "%s differs from %s, run go test ./receipt -update after checking the change", test.name, golden)
+		})
+	}
+}
+
+// zReport is the end of day report of a cash drawer
+var zReport = Report{
+	Header: []string{"Toko Maju Jaya", "Jl. Merdeka No. 1, Bandung"},
+	Title:  "LAPORAN Z",
+	Sections: []Section{
+		{Entries: []Entry{{"Kasir", "Andi Wijaya"}, {"Buka", "18/10/2026 08:00"}, {"Tutup", "18/10/2026 16:05"}}},
+		{Title: "PENJUALAN", Entries: []Entry{{"Transaksi", "12"}, {"Penjualan", Amount(money.IDR(1165500))}, {"Refund", "-" + Amount(money.IDR(38850))}, {"Penjualan bersih", Amount(money.IDR(1126650))}}},
+		{Title: "KAS", Entries: []Entry{{"Modal awal", Amount(money.IDR(500000))}, {"Diharapkan", Amount(money.IDR(1611550))}, {"Dihitung", Amount(money.IDR(1610000))}, {"Selisih", Amount(money.IDR(-1550))}}},
+	},
+}
+
+func TestReportText(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		paper Paper
+	}{
+		{"zreport_58.txt", Paper58},
+		{"zreport_80.txt", Paper80},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := ReportText(zReport, test.paper)
+
+			golden := filepath.Join("testdata", test.name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(want, got), "%s differs from %s, run go test ./receipt -update after checking the change", test.name, golden)
+		})
+	}
+}
+
+func TestTextFitsThePaper(t *testing.T) {
+	for _, paper := range []Paper{Paper58, Paper80} {
+		for _, line := range bytes.Split(Text(sale, paper), []byte("\n")) {
+			assert.LessOrEqual(t, len([]rune(string(line))), paper.Columns, "%q", line)
+		}
+	}
+}
+
+func TestESCPOS(t *testing.T) {
+	got := ESCPOS(sale, Paper58)
+
+	assert.True(t, bytes.HasPrefix(got, []byte{0x1b, '@', 0x1b, 'a', 1, 0x1b, 'E', 1, 0x1d, '!', 1, 'T', 'o', 'k', 'o'}))
+	assert.True(t, bytes.HasSuffix(got, []byte{0x1b, 'd', 4, 0x1d, 'V', 66, 0}))
+
+	store := append([]byte{0x1d, '(', 'k', 33, 0, '1', 'P', '0'}, sale.QR...)
+	assert.True(t, bytes.Contains(got, store), "the QR code is stored with its length")
+	assert.True(t, bytes.Contains(got, []byte{0x1d, '(', 'k', 3, 0, '1', 'C', 5}), "the module size is that of the paper")
+
+	latte := ESCPOS(anonymous, Paper58)
+	assert.True(t, bytes.Contains(latte, []byte("Caf? Latte\n")), "characters outside of ASCII are replaced")
+	assert.False(t, bytes.Contains(latte, []byte{0x1d, '(', 'k'}), "no QR code without content")
+}
+
+func TestAmount(t *testing.T) {
+	assert.Equal(t, "0", Amount(money.IDR(0)))
+	assert.Equal(t, "500", Amount(money.IDR(500)))
+	assert.Equal(t, "35.000", Amount(money.IDR(35000)))
+	assert.Equal(t, "15.000.000", Amount(money.IDR(15000000)))
+	assert.Equal(t, "-750.000", Amount(money.IDR(-750000)))
+	assert.Equal(t, "1.234,50", Amount(money.New(123450, "USD")))
+}
+
+func TestRenderRejectsUnknownFormats(t *testing.T) {
+	_, err := Render(sale, "pdf", Paper80)
+	assert.Error(t, err)
+
+	_, err = ParsePaper("110")
+	assert.Error(t, err)
+	paper, err := ParsePaper("58")
+	require.NoError(t, err)
+	assert.Equal(t, Paper58, paper)
+}
@@ -0,0 +1,53 @@
+package receipt
+
+import "strings"
+
+// Report is a summary printed on the same paper as the receipts, such as the X or Z report of
+// a cash drawer: a title under the header of the store, then sections of labelled values
+type Report struct {
+	Header   []string
+	Title    string
+	Sections []Section
+}
+
+// Section is a group of entries of a Report, under a title unless it is empty
+type Section struct {
+	Title   string
+	Entries []Entry
+}
+
+// Entry is a label and its value, amounts are written with Amount
+type Entry struct {
+	Label string
+	Value string
+}
+
+// ReportText renders a report as plain text in the columns of paper
+func ReportText(report Report, paper Paper) []byte {
+	var out strings.Builder
+	for _, row := range reportLayout(report) {
+		for _, line := range renderRow(row, paper.Columns) {
+			out.WriteString(line)
+			out.WriteByte('\n')
+		}
+	}
+	return []byte(out.String())
+}
+
+func reportLayout(report Report) []row {
+	var rows []row
+	for i, line := range report.Header {
+		rows = append(rows, row{kind: rowCenter, left: line, strong: i == 0})
+	}
+	rows = append(rows, row{kind: rowRule}, row{kind: rowCenter, left: report.Title, strong: true})
+	for _, section := range report.Sections {
+		rows = append(rows, row{kind: rowRule})
+		if section.Title != "" {
+			rows = append(rows, row{kind: rowText, left: section.Title})
+		}
+		for _, entry := range section.Entries {
+			rows = append(rows, row{kind: rowPair, left: entry.Label, right: entry.Value})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,46 @@
+package receipt
+
+import (
+	"strings"
+)
+
+// Text renders the receipt as plain text in the columns of paper, for a screen or a printer
+// driver. The QR code is replaced by its content.
+func Text(receipt Receipt, paper Paper) []byte {
+	var out strings.Builder
+	for _, line := range textLines(receipt, paper) {
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return []byte(out.String())
+}
+
+func textLines(receipt Receipt, paper Paper) []string {
+	var lines []string
+	for _, row := range layout(receipt) {
+		lines = append(lines, renderRow(row, paper.Columns)...)
+	}
+	if receipt.QR != "" {
+		for _, line := range wrap(receipt.QR, paper.Columns) {
+			lines = append(lines, center(line, paper.Columns))
+		}
+	}
+	return lines
+}
+
+// renderRow writes a row in lines of width characters
+func renderRow(row row, width int) []string {
+	switch row.kind {
+	case rowRule:
+		return []string{strings.Repeat("-", width)}
+	case rowPair:
+		return pair(row.left, row.right, width)
+	case rowCenter:
+		var lines []string
+		for _, line := range wrap(row.left, width) {
+			lines = append(lines, center(line, width))
+		}
+		return lines
+	}
+	return wrap(row.left, width)
+}
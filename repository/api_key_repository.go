@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error)
+	Update(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error)
+	FindById(ctx context.Context, keyId string) (domain.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (domain.APIKey, error)
+	FindAll(ctx context.Context) ([]domain.APIKey, error)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+// Save API key
+func (repository *APIKeyRepositoryImpl) Save(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+	if err := conn(ctx, repository.db).Create(&apiKey).Error; err != nil {
+		return domain.APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// Update API key
+func (repository *APIKeyRepositoryImpl) Update(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+	if err := conn(ctx, repository.db).Save(&apiKey).Error; err != nil {
+		return domain.APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+// FindById - Get API key by ID
+func (repository *APIKeyRepositoryImpl) FindById(ctx context.Context, keyId string) (domain.APIKey, error) {
+	var apiKey domain.APIKey
+	err := conn(ctx, repository.db).First(&apiKey, "key_id = ?", keyId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apiKey, fmt.Errorf("API key is not found: %w", err)
+	}
+	return apiKey, err
+}
+
+// FindByHash - Get API key by the hash of the key
+func (repository *APIKeyRepositoryImpl) FindByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	var apiKey domain.APIKey
+	err := conn(ctx, repository.db).First(&apiKey, "hash = ?", hash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apiKey, fmt.Errorf("API key is not found: %w", err)
+	}
+	return apiKey, err
+}
+
+// FindAll - Get all API keys, revoked ones included
+func (repository *APIKeyRepositoryImpl) FindAll(ctx context.Context) ([]domain.APIKey, error) {
+	var apiKeys []domain.APIKey
+	err := conn(ctx, repository.db).Order("created_at").Find(&apiKeys).Error
+	return apiKeys, err
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type CashSessionRepository interface {
+	Save(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error)
+	Update(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error)
+	SaveMovement(ctx context.Context, movement domain.CashMovement) error
+	FindById(ctx context.Context, sessionId string) (domain.CashSession, error)
+	FindOpen(ctx context.Context, storeId string, employeeId string) (domain.CashSession, error)
+	FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.CashSession, error)
+	FindOpened(ctx context.Context, storeId string, from time.Time, to time.Time) ([]domain.CashSession, error)
+}
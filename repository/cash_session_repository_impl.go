@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CashSessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCashSessionRepository(db *gorm.DB) CashSessionRepository {
+	return &CashSessionRepositoryImpl{db: db}
+}
+
+// Save session with its movements. ok is false and nothing is written when the employee already
+// has a session open in the store.
+func (repository *CashSessionRepositoryImpl) Save(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+	db := conn(ctx, repository.db)
+	session.OpenKey = nil
+	if session.Status == domain.CashSessionOpen {
+		openKey := domain.CashSessionOpenKey(session.StoreID, session.EmployeeID)
+		session.OpenKey = &openKey
+	}
+	result := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&session)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return domain.CashSession{}, false, result.Error
+	}
+	for i := range session.Movements {
+		session.Movements[i].SessionID = session.SessionID
+	}
+	if len(session.Movements) > 0 {
+		if err := db.Create(&session.Movements).Error; err != nil {
+			return domain.CashSession{}, false, err
+		}
+	}
+	return session, true, nil
+}
+
+// Update the closing of a session, unless it was updated since it was read: ok is then false and
+// nothing is written. The movements are saved with SaveMovement.
+func (repository *CashSessionRepositoryImpl) Update(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+	version := session.Version
+	session.Version++
+	session.UpdatedAt = time.Now()
+	if session.Status != domain.CashSessionOpen {
+		session.OpenKey = nil
+	}
+	result := conn(ctx, repository.db).Model(&domain.CashSession{}).
+		Where("session_id = ? AND version = ?", session.SessionID, version).
+		Select("closed_by", "status", "expected", "counted", "variance", "counts", "note", "version", "updated_at", "closed_at", "open_key").
+		Updates(&session)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return domain.CashSession{}, false, result.Error
+	}
+	return session, true, nil
+}
+
+// SaveMovement adds a movement to a session
+func (repository *CashSessionRepositoryImpl) SaveMovement(ctx context.Context, movement domain.CashMovement) error {
+	return conn(ctx, repository.db).Create(&movement).Error
+}
+
+// FindById - Get session by ID with its movements
+func (repository *CashSessionRepositoryImpl) FindById(ctx context.Context, sessionId string) (domain.CashSession, error) {
+	var session domain.CashSession
+	err := conn(ctx, repository.db).Preload("Movements", positionOrder).First(&session, "session_id = ?", sessionId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return session, fmt.Errorf("cash session is not found: %w", err)
+	}
+	return session, err
+}
+
+// FindOpen - Get the open session of an employee in a store
+func (repository *CashSessionRepositoryImpl) FindOpen(ctx context.Context, storeId string, employeeId string) (domain.CashSession, error) {
+	var session domain.CashSession
+	err := conn(ctx, repository.db).Preload("Movements", positionOrder).
+		First(&session, "store_id = ? AND employee_id = ? AND status = ?", storeId, employeeId, domain.CashSessionOpen).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return session, fmt.Errorf("cash session is not found: %w", err)
+	}
+	return session, err
+}
+
+// FindAll - Get the sessions of a store in one of statuses, an empty ID matches every store and
+// no status every status, newest first
+func (repository *CashSessionRepositoryImpl) FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.CashSession, error) {
+	query := conn(ctx, repository.db).Preload("Movements", positionOrder)
+	if storeId != "" {
+		query = query.Where("store_id = ?", storeId)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	var sessions []domain.CashSession
+	err := query.Order("opened_at DESC").Order("session_id").Find(&sessions).Error
+	return sessions, err
+}
+
+// FindOpened - Get the sessions of a store opened from from until before to, oldest first
+func (repository *CashSessionRepositoryImpl) FindOpened(ctx context.Context, storeId string, from time.Time, to time.Time) ([]domain.CashSession, error) {
+	var sessions []domain.CashSession
+	err := conn(ctx, repository.db).Preload("Movements", positionOrder).
+		Where("store_id = ? AND opened_at >= ? AND opened_at < ?", storeId, from, to).
+		Order("opened_at").Order("session_id").Find(&sessions).Error
+	return sessions, err
+}
@@ -11,4 +11,5 @@ type CategoryRepository interface {
 	Delete(ctx context.Context, category domain.Category) error
 	FindById(ctx context.Context, categoryId int) (domain.Category, error)
 	FindAll(ctx context.Context) ([]domain.Category, error)
+	FindByNames(ctx context.Context, names []string) ([]domain.Category, error)
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aronipurwanto/go-restful-api/cache"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+// CachedCategoryRepository serves FindById and FindAll from a read-through cache and
+// invalidates it on every write. Reads inside a transaction bypass the cache, they may see
+// rows that are not committed yet.
+type CachedCategoryRepository struct {
+	CategoryRepository
+	cache *cache.Cache
+}
+
+func NewCachedCategoryRepository(categoryRepository CategoryRepository, categoryCache *cache.Cache) CategoryRepository {
+	return &CachedCategoryRepository{CategoryRepository: categoryRepository, cache: categoryCache}
+}
+
+// Save category
+func (repository *CachedCategoryRepository) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
+	category, err := repository.CategoryRepository.Save(ctx, category)
+	if err != nil {
+		return category, err
+	}
+	return category, repository.invalidate(ctx, "all")
+}
+
+// Update category
+func (repository *CachedCategoryRepository) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	category, err := repository.CategoryRepository.Update(ctx, category)
+	if err != nil {
+		return category, err
+	}
+	return category, repository.invalidate(ctx, "id:"+strconv.Itoa(category.Id), "all")
+}
+
+// Delete category
+func (repository *CachedCategoryRepository) Delete(ctx context.Context, category domain.Category) error {
+	if err := repository.CategoryRepository.Delete(ctx, category); err != nil {
+		return err
+	}
+	return repository.invalidate(ctx, "id:"+strconv.Itoa(category.Id), "all")
+}
+
+// FindById - Get category by ID
+func (repository *CachedCategoryRepository) FindById(ctx context.Context, categoryId int) (domain.Category, error) {
+	if InTransaction(ctx) {
+		return repository.CategoryRepository.FindById(ctx, categoryId)
+	}
+
+	var category domain.Category
+	err := repository.cache.Load(ctx, "id:"+strconv.Itoa(categoryId), &category, func(ctx context.Context) (interface{}, error) {
+		return repository.CategoryRepository.FindById(ctx, categoryId)
+	})
+	return category, err
+}
+
+// FindAll - Get all categories
+func (repository *CachedCategoryRepository) FindAll(ctx context.Context) ([]domain.Category, error) {
+	if InTransaction(ctx) {
+		return repository.CategoryRepository.FindAll(ctx)
+	}
+
+	var categories []domain.Category
+	err := repository.cache.Load(ctx, "all", &categories, func(ctx context.Context) (interface{}, error) {
+		return repository.CategoryRepository.FindAll(ctx)
+	})
+	return categories, err
+}
+
+// invalidate drops keys now and once more after the surrounding transaction commits, so a
+// reader cannot put the old row back in between
+func (repository *CachedCategoryRepository) invalidate(ctx context.Context, keys ...string) error {
+	AfterCommit(ctx, func() {
+		_ = repository.cache.Invalidate(context.WithoutCancel(ctx), keys...)
+	})
+	return repository.cache.Invalidate(ctx, keys...)
+}
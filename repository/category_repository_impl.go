@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
 )
@@ -17,7 +18,7 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 
 // Save category
 func (repository *CategoryRepositoryImpl) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
-	if err := repository.db.WithContext(ctx).Create(&category).Error; err != nil {
+	if err := conn(ctx, repository.db).Create(&category).Error; err != nil {
 		return domain.Category{}, err
 	}
 	return category, nil
@@ -25,7 +26,7 @@ func (repository *CategoryRepositoryImpl) Save(ctx context.Context, category dom
 
 // Update category
 func (repository *CategoryRepositoryImpl) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
-	if err := repository.db.WithContext(ctx).Save(&category).Error; err != nil {
+	if err := conn(ctx, repository.db).Save(&category).Error; err != nil {
 		return domain.Category{}, err
 	}
 	return category, nil
@@ -33,7 +34,7 @@ func (repository *CategoryRepositoryImpl) Update(ctx context.Context, category d
 
 // Delete category
 func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, category domain.Category) error {
-	if err := repository.db.WithContext(ctx).Delete(&category).Error; err != nil {
+	if err := conn(ctx, repository.db).Delete(&category).Error; err != nil {
 		return err
 	}
 	return nil
@@ -42,9 +43,9 @@ func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, category d
 // FindById - Get category by ID
 func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, categoryId int) (domain.Category, error) {
 	var category domain.Category
-	err := repository.db.WithContext(ctx).First(&category, categoryId).Error
+	err := conn(ctx, repository.db).First(&category, categoryId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return category, errors.New("category is not found")
+		return category, fmt.Errorf("category is not found: %w", err)
 	}
 	return category, err
 }
@@ -52,6 +53,13 @@ func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, category
 // FindAll - Get all categories
 func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context) ([]domain.Category, error) {
 	var categories []domain.Category
-	err := repository.db.WithContext(ctx).Find(&categories).Error
+	err := conn(ctx, repository.db).Find(&categories).Error
+	return categories, err
+}
+
+// FindByNames - Get the categories with one of the given names
+func (repository *CategoryRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]domain.Category, error) {
+	var categories []domain.Category
+	err := conn(ctx, repository.db).Where("name IN ?", names).Find(&categories).Error
 	return categories, err
 }
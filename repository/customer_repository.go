@@ -11,4 +11,5 @@ type CustomerRepository interface {
 	Delete(ctx context.Context, customer domain.Customer) error
 	FindById(ctx context.Context, customerId string) (domain.Customer, error)
 	FindAll(ctx context.Context) ([]domain.Customer, error)
+	AdjustLoyaltyPoints(ctx context.Context, customerId string, delta int) (bool, error)
 }
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
 )
@@ -17,7 +19,7 @@ func NewCustomerRepository(db *gorm.DB) CustomerRepository {
 
 // Save customer
 func (repository *CustomerRepositoryImpl) Save(ctx context.Context, customer domain.Customer) (domain.Customer, error) {
-	if err := repository.db.WithContext(ctx).Create(&customer).Error; err != nil {
+	if err := conn(ctx, repository.db).Create(&customer).Error; err != nil {
 		return domain.Customer{}, err
 	}
 	return customer, nil
@@ -25,7 +27,7 @@ func (repository *CustomerRepositoryImpl) Save(ctx context.Context, customer dom
 
 // Update customer
 func (repository *CustomerRepositoryImpl) Update(ctx context.Context, customer domain.Customer) (domain.Customer, error) {
-	if err := repository.db.WithContext(ctx).Save(&customer).Error; err != nil {
+	if err := conn(ctx, repository.db).Save(&customer).Error; err != nil {
 		return domain.Customer{}, err
 	}
 	return customer, nil
@@ -33,7 +35,7 @@ func (repository *CustomerRepositoryImpl) Update(ctx context.Context, customer d
 
 // Delete customer
 func (repository *CustomerRepositoryImpl) Delete(ctx context.Context, customer domain.Customer) error {
-	if err := repository.db.WithContext(ctx).Delete(&customer).Error; err != nil {
+	if err := conn(ctx, repository.db).Delete(&customer).Error; err != nil {
 		return err
 	}
 	return nil
@@ -42,9 +44,9 @@ func (repository *CustomerRepositoryImpl) Delete(ctx context.Context, customer d
 // FindById - Get customer by ID
 func (repository *CustomerRepositoryImpl) FindById(ctx context.Context, customerId string) (domain.Customer, error) {
 	var customer domain.Customer
-	err := repository.db.WithContext(ctx).First(&customer, "customer_id = ?", customerId).Error
+	err := conn(ctx, repository.db).First(&customer, "customer_id = ?", customerId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return customer, errors.New("customer not found")
+		return customer, fmt.Errorf("customer not found: %w", err)
 	}
 	return customer, err
 }
@@ -52,6 +54,15 @@ func (repository *CustomerRepositoryImpl) FindById(ctx context.Context, customer
 // FindAll - Get all customers
 func (repository *CustomerRepositoryImpl) FindAll(ctx context.Context) ([]domain.Customer, error) {
 	var customers []domain.Customer
-	err := repository.db.WithContext(ctx).Find(&customers).Error
+	err := conn(ctx, repository.db).Find(&customers).Error
 	return customers, err
 }
+
+// AdjustLoyaltyPoints adds delta to the loyalty points of a customer. ok is false when the
+// customer does not exist or the points would become negative.
+func (repository *CustomerRepositoryImpl) AdjustLoyaltyPoints(ctx context.Context, customerId string, delta int) (bool, error) {
+	result := conn(ctx, repository.db).Model(&domain.Customer{}).
+		Where("customer_id = ? AND loyalty_points + ? >= 0", customerId, delta).
+		Update("loyalty_points", gorm.Expr("loyalty_points + ?", delta))
+	return result.RowsAffected == 1, result.Error
+}
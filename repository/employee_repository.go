@@ -11,4 +11,5 @@ type EmployeeRepository interface {
 	Delete(ctx context.Context, employee domain.Employee) error
 	FindById(ctx context.Context, employeeId string) (domain.Employee, error)
 	FindAll(ctx context.Context) ([]domain.Employee, error)
+	FindByStore(ctx context.Context, storeId string) ([]domain.Employee, error)
 }
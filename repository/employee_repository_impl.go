@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EmployeeRepositoryImpl struct {
@@ -15,25 +17,41 @@ func NewEmployeeRepository(db *gorm.DB) EmployeeRepository {
 	return &EmployeeRepositoryImpl{db: db}
 }
 
-// Save employee
+// Save employee with its store assignments
 func (repository *EmployeeRepositoryImpl) Save(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
-	if err := repository.db.WithContext(ctx).Create(&employee).Error; err != nil {
+	if err := conn(ctx, repository.db).Create(&employee).Error; err != nil {
 		return domain.Employee{}, err
 	}
 	return employee, nil
 }
 
-// Update employee
+// Update employee, its store assignments are replaced by employee.Stores
 func (repository *EmployeeRepositoryImpl) Update(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
-	if err := repository.db.WithContext(ctx).Save(&employee).Error; err != nil {
+	db := conn(ctx, repository.db)
+	if err := db.Omit(clause.Associations).Save(&employee).Error; err != nil {
 		return domain.Employee{}, err
 	}
+	if err := db.Where("employee_id = ?", employee.EmployeeID).Delete(&domain.EmployeeStore{}).Error; err != nil {
+		return domain.Employee{}, err
+	}
+	for i := range employee.Stores {
+		employee.Stores[i].EmployeeID = employee.EmployeeID
+	}
+	if len(employee.Stores) > 0 {
+		if err := db.Create(&employee.Stores).Error; err != nil {
+			return domain.Employee{}, err
+		}
+	}
 	return employee, nil
 }
 
-// Delete employee
+// Delete employee with its store assignments
 func (repository *EmployeeRepositoryImpl) Delete(ctx context.Context, employee domain.Employee) error {
-	if err := repository.db.WithContext(ctx).Delete(&employee).Error; err != nil {
+	db := conn(ctx, repository.db)
+	if err := db.Where("employee_id = ?", employee.EmployeeID).Delete(&domain.EmployeeStore{}).Error; err != nil {
+		return err
+	}
+	if err := db.Omit(clause.Associations).Delete(&employee).Error; err != nil {
 		return err
 	}
 	return nil
@@ -42,9 +60,9 @@ func (repository *EmployeeRepositoryImpl) Delete(ctx context.Context, employee d
 // FindById - Get employee by ID
 func (repository *EmployeeRepositoryImpl) FindById(ctx context.Context, employeeId string) (domain.Employee, error) {
 	var employee domain.Employee
-	err := repository.db.WithContext(ctx).First(&employee, "employee_id = ?", employeeId).Error
+	err := conn(ctx, repository.db).Preload("Stores").First(&employee, "employee_id = ?", employeeId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return employee, errors.New("employee not found")
+		return employee, fmt.Errorf("employee not found: %w", err)
 	}
 	return employee, err
 }
@@ -52,6 +70,15 @@ func (repository *EmployeeRepositoryImpl) FindById(ctx context.Context, employee
 // FindAll - Get all employees
 func (repository *EmployeeRepositoryImpl) FindAll(ctx context.Context) ([]domain.Employee, error) {
 	var employees []domain.Employee
-	err := repository.db.WithContext(ctx).Find(&employees).Error
+	err := conn(ctx, repository.db).Preload("Stores").Find(&employees).Error
+	return employees, err
+}
+
+// FindByStore - Get the employees assigned to a store
+func (repository *EmployeeRepositoryImpl) FindByStore(ctx context.Context, storeId string) ([]domain.Employee, error) {
+	var employees []domain.Employee
+	err := conn(ctx, repository.db).Preload("Stores").
+		Where("employee_id IN (?)", conn(ctx, repository.db).Model(&domain.EmployeeStore{}).Select("employee_id").Where("store_id = ?", storeId)).
+		Find(&employees).Error
 	return employees, err
 }
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/cash_session_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCashSessionRepository is a mock of CashSessionRepository interface.
+type MockCashSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCashSessionRepositoryMockRecorder
+}
+
+// MockCashSessionRepositoryMockRecorder is the mock recorder for MockCashSessionRepository.
+type MockCashSessionRepositoryMockRecorder struct {
+	mock *MockCashSessionRepository
+}
+
+// NewMockCashSessionRepository creates a new mock instance.
+func NewMockCashSessionRepository(ctrl *gomock.Controller) *MockCashSessionRepository {
+	mock := &MockCashSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockCashSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCashSessionRepository) EXPECT() *MockCashSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockCashSessionRepository) FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.CashSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, statuses)
+	ret0, _ := ret[0].([]domain.CashSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockCashSessionRepositoryMockRecorder) FindAll(ctx, storeId, statuses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockCashSessionRepository)(nil).FindAll), ctx, storeId, statuses)
+}
+
+// FindById mocks base method.
+func (m *MockCashSessionRepository) FindById(ctx context.Context, sessionId string) (domain.CashSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, sessionId)
+	ret0, _ := ret[0].(domain.CashSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCashSessionRepositoryMockRecorder) FindById(ctx, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCashSessionRepository)(nil).FindById), ctx, sessionId)
+}
+
+// FindOpen mocks base method.
+func (m *MockCashSessionRepository) FindOpen(ctx context.Context, storeId, employeeId string) (domain.CashSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOpen", ctx, storeId, employeeId)
+	ret0, _ := ret[0].(domain.CashSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOpen indicates an expected call of FindOpen.
+func (mr *MockCashSessionRepositoryMockRecorder) FindOpen(ctx, storeId, employeeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOpen", reflect.TypeOf((*MockCashSessionRepository)(nil).FindOpen), ctx, storeId, employeeId)
+}
+
+// FindOpened mocks base method.
+func (m *MockCashSessionRepository) FindOpened(ctx context.Context, storeId string, from, to time.Time) ([]domain.CashSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOpened", ctx, storeId, from, to)
+	ret0, _ := ret[0].([]domain.CashSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOpened indicates an expected call of FindOpened.
+func (mr *MockCashSessionRepositoryMockRecorder) FindOpened(ctx, storeId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOpened", reflect.TypeOf((*MockCashSessionRepository)(nil).FindOpened), ctx, storeId, from, to)
+}
+
+// Save mocks base method.
+func (m *MockCashSessionRepository) Save(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, session)
+	ret0, _ := ret[0].(domain.CashSession)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCashSessionRepositoryMockRecorder) Save(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCashSessionRepository)(nil).Save), ctx, session)
+}
+
+// SaveMovement mocks base method.
+func (m *MockCashSessionRepository) SaveMovement(ctx context.Context, movement domain.CashMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMovement", ctx, movement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMovement indicates an expected call of SaveMovement.
+func (mr *MockCashSessionRepositoryMockRecorder) SaveMovement(ctx, movement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMovement", reflect.TypeOf((*MockCashSessionRepository)(nil).SaveMovement), ctx, movement)
+}
+
+// Update mocks base method.
+func (m *MockCashSessionRepository) Update(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, session)
+	ret0, _ := ret[0].(domain.CashSession)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCashSessionRepositoryMockRecorder) Update(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCashSessionRepository)(nil).Update), ctx, session)
+}
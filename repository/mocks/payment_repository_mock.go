@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/payment_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentRepository is a mock of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+}
+
+// MockPaymentRepositoryMockRecorder is the mock recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mock instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockPaymentRepository) FindAll(ctx context.Context, storeId, customerId string) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, customerId)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockPaymentRepositoryMockRecorder) FindAll(ctx, storeId, customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockPaymentRepository)(nil).FindAll), ctx, storeId, customerId)
+}
+
+// FindByCharge mocks base method.
+func (m *MockPaymentRepository) FindByCharge(ctx context.Context, chargeId string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCharge", ctx, chargeId)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCharge indicates an expected call of FindByCharge.
+func (mr *MockPaymentRepositoryMockRecorder) FindByCharge(ctx, chargeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCharge", reflect.TypeOf((*MockPaymentRepository)(nil).FindByCharge), ctx, chargeId)
+}
+
+// FindById mocks base method.
+func (m *MockPaymentRepository) FindById(ctx context.Context, paymentId string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, paymentId)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockPaymentRepositoryMockRecorder) FindById(ctx, paymentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockPaymentRepository)(nil).FindById), ctx, paymentId)
+}
+
+// FindByOrder mocks base method.
+func (m *MockPaymentRepository) FindByOrder(ctx context.Context, orderId string) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOrder", ctx, orderId)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOrder indicates an expected call of FindByOrder.
+func (mr *MockPaymentRepositoryMockRecorder) FindByOrder(ctx, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOrder", reflect.TypeOf((*MockPaymentRepository)(nil).FindByOrder), ctx, orderId)
+}
+
+// FindByOrders mocks base method.
+func (m *MockPaymentRepository) FindByOrders(ctx context.Context, orderIds []string) ([]domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOrders", ctx, orderIds)
+	ret0, _ := ret[0].([]domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOrders indicates an expected call of FindByOrders.
+func (mr *MockPaymentRepositoryMockRecorder) FindByOrders(ctx, orderIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOrders", reflect.TypeOf((*MockPaymentRepository)(nil).FindByOrders), ctx, orderIds)
+}
+
+// Save mocks base method.
+func (m *MockPaymentRepository) Save(ctx context.Context, payment domain.Payment) (domain.Payment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, payment)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPaymentRepositoryMockRecorder) Save(ctx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPaymentRepository)(nil).Save), ctx, payment)
+}
+
+// SaveNotification mocks base method.
+func (m *MockPaymentRepository) SaveNotification(ctx context.Context, notification domain.PaymentNotification) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveNotification", ctx, notification)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveNotification indicates an expected call of SaveNotification.
+func (mr *MockPaymentRepositoryMockRecorder) SaveNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNotification", reflect.TypeOf((*MockPaymentRepository)(nil).SaveNotification), ctx, notification)
+}
+
+// Update mocks base method.
+func (m *MockPaymentRepository) Update(ctx context.Context, payment domain.Payment) (domain.Payment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, payment)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPaymentRepositoryMockRecorder) Update(ctx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentRepository)(nil).Update), ctx, payment)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/return_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReturnRepository is a mock of ReturnRepository interface.
+type MockReturnRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReturnRepositoryMockRecorder
+}
+
+// MockReturnRepositoryMockRecorder is the mock recorder for MockReturnRepository.
+type MockReturnRepositoryMockRecorder struct {
+	mock *MockReturnRepository
+}
+
+// NewMockReturnRepository creates a new mock instance.
+func NewMockReturnRepository(ctrl *gomock.Controller) *MockReturnRepository {
+	mock := &MockReturnRepository{ctrl: ctrl}
+	mock.recorder = &MockReturnRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReturnRepository) EXPECT() *MockReturnRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockReturnRepository) FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, statuses)
+	ret0, _ := ret[0].([]domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockReturnRepositoryMockRecorder) FindAll(ctx, storeId, statuses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockReturnRepository)(nil).FindAll), ctx, storeId, statuses)
+}
+
+// FindById mocks base method.
+func (m *MockReturnRepository) FindById(ctx context.Context, returnId string) (domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, returnId)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockReturnRepositoryMockRecorder) FindById(ctx, returnId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockReturnRepository)(nil).FindById), ctx, returnId)
+}
+
+// FindByOrder mocks base method.
+func (m *MockReturnRepository) FindByOrder(ctx context.Context, orderId string) ([]domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOrder", ctx, orderId)
+	ret0, _ := ret[0].([]domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOrder indicates an expected call of FindByOrder.
+func (mr *MockReturnRepositoryMockRecorder) FindByOrder(ctx, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOrder", reflect.TypeOf((*MockReturnRepository)(nil).FindByOrder), ctx, orderId)
+}
+
+// FindCompleted mocks base method.
+func (m *MockReturnRepository) FindCompleted(ctx context.Context, storeId, employeeId string, from, to time.Time) ([]domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCompleted", ctx, storeId, employeeId, from, to)
+	ret0, _ := ret[0].([]domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCompleted indicates an expected call of FindCompleted.
+func (mr *MockReturnRepositoryMockRecorder) FindCompleted(ctx, storeId, employeeId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCompleted", reflect.TypeOf((*MockReturnRepository)(nil).FindCompleted), ctx, storeId, employeeId, from, to)
+}
+
+// Save mocks base method.
+func (m *MockReturnRepository) Save(ctx context.Context, orderReturn domain.Return) (domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, orderReturn)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockReturnRepositoryMockRecorder) Save(ctx, orderReturn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockReturnRepository)(nil).Save), ctx, orderReturn)
+}
+
+// Update mocks base method.
+func (m *MockReturnRepository) Update(ctx context.Context, orderReturn domain.Return) (domain.Return, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, orderReturn)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReturnRepositoryMockRecorder) Update(ctx, orderReturn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReturnRepository)(nil).Update), ctx, orderReturn)
+}
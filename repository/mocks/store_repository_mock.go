@@ -0,0 +1,337 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/store_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStoreRepository is a mock of StoreRepository interface.
+type MockStoreRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreRepositoryMockRecorder
+}
+
+// MockStoreRepositoryMockRecorder is the mock recorder for MockStoreRepository.
+type MockStoreRepositoryMockRecorder struct {
+	mock *MockStoreRepository
+}
+
+// NewMockStoreRepository creates a new mock instance.
+func NewMockStoreRepository(ctrl *gomock.Controller) *MockStoreRepository {
+	mock := &MockStoreRepository{ctrl: ctrl}
+	mock.recorder = &MockStoreRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreRepository) EXPECT() *MockStoreRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockStoreRepository) Delete(ctx context.Context, store domain.Store) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, store)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStoreRepositoryMockRecorder) Delete(ctx, store interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStoreRepository)(nil).Delete), ctx, store)
+}
+
+// FindAll mocks base method.
+func (m *MockStoreRepository) FindAll(ctx context.Context) ([]domain.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]domain.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockStoreRepositoryMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockStoreRepository)(nil).FindAll), ctx)
+}
+
+// FindById mocks base method.
+func (m *MockStoreRepository) FindById(ctx context.Context, storeId string) (domain.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, storeId)
+	ret0, _ := ret[0].(domain.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStoreRepositoryMockRecorder) FindById(ctx, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStoreRepository)(nil).FindById), ctx, storeId)
+}
+
+// FindByIds mocks base method.
+func (m *MockStoreRepository) FindByIds(ctx context.Context, storeIds []string) ([]domain.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIds", ctx, storeIds)
+	ret0, _ := ret[0].([]domain.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIds indicates an expected call of FindByIds.
+func (mr *MockStoreRepositoryMockRecorder) FindByIds(ctx, storeIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIds", reflect.TypeOf((*MockStoreRepository)(nil).FindByIds), ctx, storeIds)
+}
+
+// Save mocks base method.
+func (m *MockStoreRepository) Save(ctx context.Context, store domain.Store) (domain.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, store)
+	ret0, _ := ret[0].(domain.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStoreRepositoryMockRecorder) Save(ctx, store interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStoreRepository)(nil).Save), ctx, store)
+}
+
+// Update mocks base method.
+func (m *MockStoreRepository) Update(ctx context.Context, store domain.Store) (domain.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, store)
+	ret0, _ := ret[0].(domain.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStoreRepositoryMockRecorder) Update(ctx, store interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStoreRepository)(nil).Update), ctx, store)
+}
+
+// MockStoreStockRepository is a mock of StoreStockRepository interface.
+type MockStoreStockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreStockRepositoryMockRecorder
+}
+
+// MockStoreStockRepositoryMockRecorder is the mock recorder for MockStoreStockRepository.
+type MockStoreStockRepositoryMockRecorder struct {
+	mock *MockStoreStockRepository
+}
+
+// NewMockStoreStockRepository creates a new mock instance.
+func NewMockStoreStockRepository(ctrl *gomock.Controller) *MockStoreStockRepository {
+	mock := &MockStoreStockRepository{ctrl: ctrl}
+	mock.recorder = &MockStoreStockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreStockRepository) EXPECT() *MockStoreStockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Adjust mocks base method.
+func (m *MockStoreStockRepository) Adjust(ctx context.Context, storeId, productId string, delta int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", ctx, storeId, productId, delta)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockStoreStockRepositoryMockRecorder) Adjust(ctx, storeId, productId, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockStoreStockRepository)(nil).Adjust), ctx, storeId, productId, delta)
+}
+
+// AdjustVariant mocks base method.
+func (m *MockStoreStockRepository) AdjustVariant(ctx context.Context, storeId, productId, variantId string, delta int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustVariant", ctx, storeId, productId, variantId, delta)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustVariant indicates an expected call of AdjustVariant.
+func (mr *MockStoreStockRepositoryMockRecorder) AdjustVariant(ctx, storeId, productId, variantId, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustVariant", reflect.TypeOf((*MockStoreStockRepository)(nil).AdjustVariant), ctx, storeId, productId, variantId, delta)
+}
+
+// FindById mocks base method.
+func (m *MockStoreStockRepository) FindById(ctx context.Context, storeId, productId string) (domain.StoreStock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, storeId, productId)
+	ret0, _ := ret[0].(domain.StoreStock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStoreStockRepositoryMockRecorder) FindById(ctx, storeId, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStoreStockRepository)(nil).FindById), ctx, storeId, productId)
+}
+
+// FindByStore mocks base method.
+func (m *MockStoreStockRepository) FindByStore(ctx context.Context, storeId string) ([]domain.StoreStock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByStore", ctx, storeId)
+	ret0, _ := ret[0].([]domain.StoreStock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByStore indicates an expected call of FindByStore.
+func (mr *MockStoreStockRepositoryMockRecorder) FindByStore(ctx, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByStore", reflect.TypeOf((*MockStoreStockRepository)(nil).FindByStore), ctx, storeId)
+}
+
+// FindVariants mocks base method.
+func (m *MockStoreStockRepository) FindVariants(ctx context.Context, storeId, productId string) ([]domain.StoreVariantStock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVariants", ctx, storeId, productId)
+	ret0, _ := ret[0].([]domain.StoreVariantStock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVariants indicates an expected call of FindVariants.
+func (mr *MockStoreStockRepositoryMockRecorder) FindVariants(ctx, storeId, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVariants", reflect.TypeOf((*MockStoreStockRepository)(nil).FindVariants), ctx, storeId, productId)
+}
+
+// Save mocks base method.
+func (m *MockStoreStockRepository) Save(ctx context.Context, stock domain.StoreStock) (domain.StoreStock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, stock)
+	ret0, _ := ret[0].(domain.StoreStock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStoreStockRepositoryMockRecorder) Save(ctx, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStoreStockRepository)(nil).Save), ctx, stock)
+}
+
+// SaveVariant mocks base method.
+func (m *MockStoreStockRepository) SaveVariant(ctx context.Context, stock domain.StoreVariantStock) (domain.StoreVariantStock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVariant", ctx, stock)
+	ret0, _ := ret[0].(domain.StoreVariantStock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVariant indicates an expected call of SaveVariant.
+func (mr *MockStoreStockRepositoryMockRecorder) SaveVariant(ctx, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVariant", reflect.TypeOf((*MockStoreStockRepository)(nil).SaveVariant), ctx, stock)
+}
+
+// MockStockTransferRepository is a mock of StockTransferRepository interface.
+type MockStockTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockTransferRepositoryMockRecorder
+}
+
+// MockStockTransferRepositoryMockRecorder is the mock recorder for MockStockTransferRepository.
+type MockStockTransferRepositoryMockRecorder struct {
+	mock *MockStockTransferRepository
+}
+
+// NewMockStockTransferRepository creates a new mock instance.
+func NewMockStockTransferRepository(ctrl *gomock.Controller) *MockStockTransferRepository {
+	mock := &MockStockTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockStockTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockTransferRepository) EXPECT() *MockStockTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockStockTransferRepository) FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.StockTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, statuses)
+	ret0, _ := ret[0].([]domain.StockTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockStockTransferRepositoryMockRecorder) FindAll(ctx, storeId, statuses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockStockTransferRepository)(nil).FindAll), ctx, storeId, statuses)
+}
+
+// FindById mocks base method.
+func (m *MockStockTransferRepository) FindById(ctx context.Context, transferId string) (domain.StockTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, transferId)
+	ret0, _ := ret[0].(domain.StockTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStockTransferRepositoryMockRecorder) FindById(ctx, transferId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStockTransferRepository)(nil).FindById), ctx, transferId)
+}
+
+// Save mocks base method.
+func (m *MockStockTransferRepository) Save(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, transfer)
+	ret0, _ := ret[0].(domain.StockTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStockTransferRepositoryMockRecorder) Save(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStockTransferRepository)(nil).Save), ctx, transfer)
+}
+
+// Update mocks base method.
+func (m *MockStockTransferRepository) Update(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, transfer)
+	ret0, _ := ret[0].(domain.StockTransfer)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStockTransferRepositoryMockRecorder) Update(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStockTransferRepository)(nil).Update), ctx, transfer)
+}
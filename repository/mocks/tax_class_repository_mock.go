@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/tax_class_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTaxClassRepository is a mock of TaxClassRepository interface.
+type MockTaxClassRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxClassRepositoryMockRecorder
+}
+
+// MockTaxClassRepositoryMockRecorder is the mock recorder for MockTaxClassRepository.
+type MockTaxClassRepositoryMockRecorder struct {
+	mock *MockTaxClassRepository
+}
+
+// NewMockTaxClassRepository creates a new mock instance.
+func NewMockTaxClassRepository(ctrl *gomock.Controller) *MockTaxClassRepository {
+	mock := &MockTaxClassRepository{ctrl: ctrl}
+	mock.recorder = &MockTaxClassRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxClassRepository) EXPECT() *MockTaxClassRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockTaxClassRepository) FindAll(ctx context.Context) ([]domain.TaxClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]domain.TaxClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockTaxClassRepositoryMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTaxClassRepository)(nil).FindAll), ctx)
+}
+
+// FindById mocks base method.
+func (m *MockTaxClassRepository) FindById(ctx context.Context, taxClassId string) (domain.TaxClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, taxClassId)
+	ret0, _ := ret[0].(domain.TaxClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockTaxClassRepositoryMockRecorder) FindById(ctx, taxClassId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockTaxClassRepository)(nil).FindById), ctx, taxClassId)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type OrderRepository interface {
+	Save(ctx context.Context, order domain.Order) (domain.Order, error)
+	FindById(ctx context.Context, orderId string) (domain.Order, error)
+	FindAll(ctx context.Context, storeId string) ([]domain.Order, error)
+	FindBetween(ctx context.Context, storeId string, employeeId string, from time.Time, to time.Time) ([]domain.Order, error)
+	Lock(ctx context.Context, orderId string) error
+}
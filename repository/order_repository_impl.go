@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &OrderRepositoryImpl{db: db}
+}
+
+// Save order with its lines
+func (repository *OrderRepositoryImpl) Save(ctx context.Context, order domain.Order) (domain.Order, error) {
+	if err := conn(ctx, repository.db).Create(&order).Error; err != nil {
+		return domain.Order{}, err
+	}
+	return order, nil
+}
+
+// FindById - Get order by ID with its lines
+func (repository *OrderRepositoryImpl) FindById(ctx context.Context, orderId string) (domain.Order, error) {
+	var order domain.Order
+	err := conn(ctx, repository.db).Preload("Lines", positionOrder).First(&order, "order_id = ?", orderId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, fmt.Errorf("order is not found: %w", err)
+	}
+	return order, err
+}
+
+// FindAll - Get the orders of a store, of every store when storeId is empty, newest first
+func (repository *OrderRepositoryImpl) FindAll(ctx context.Context, storeId string) ([]domain.Order, error) {
+	query := conn(ctx, repository.db).Preload("Lines", positionOrder)
+	if storeId != "" {
+		query = query.Where("store_id = ?", storeId)
+	}
+	var orders []domain.Order
+	err := query.Order("created_at DESC").Order("order_id").Find(&orders).Error
+	return orders, err
+}
+
+// FindBetween - Get the orders of a store created from from until before to, of one employee or
+// of every employee when employeeId is empty, oldest first
+func (repository *OrderRepositoryImpl) FindBetween(ctx context.Context, storeId string, employeeId string, from time.Time, to time.Time) ([]domain.Order, error) {
+	query := conn(ctx, repository.db).Preload("Lines", positionOrder).
+		Where("store_id = ? AND created_at >= ? AND created_at < ?", storeId, from, to)
+	if employeeId != "" {
+		query = query.Where("employee_id = ?", employeeId)
+	}
+	var orders []domain.Order
+	err := query.Order("created_at").Order("order_id").Find(&orders).Error
+	return orders, err
+}
+
+func positionOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// Lock takes the row of an order until the transaction of ctx ends, the writers of what depends
+// on the order, such as its returns, then take turns. SQLite has no row locks, its writers
+// take turns on the whole database already.
+func (repository *OrderRepositoryImpl) Lock(ctx context.Context, orderId string) error {
+	var order domain.Order
+	err := conn(ctx, repository.db).Clauses(clause.Locking{Strength: "UPDATE"}).Select("order_id").First(&order, "order_id = ?", orderId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("order is not found: %w", err)
+	}
+	return err
+}
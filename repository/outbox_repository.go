@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type OutboxRepository interface {
+	Save(ctx context.Context, event domain.OutboxEvent) (domain.OutboxEvent, error)
+	FindUnpublished(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, eventId uint64, publishedAt time.Time) (uint64, bool, error)
+	FindPublishedAfter(ctx context.Context, sequence uint64, types []string, limit int) ([]domain.OutboxEvent, error)
+}
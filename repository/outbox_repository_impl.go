@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+type OutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &OutboxRepositoryImpl{db: db}
+}
+
+// Save event
+func (repository *OutboxRepositoryImpl) Save(ctx context.Context, event domain.OutboxEvent) (domain.OutboxEvent, error) {
+	if err := conn(ctx, repository.db).Create(&event).Error; err != nil {
+		return domain.OutboxEvent{}, err
+	}
+	return event, nil
+}
+
+// FindUnpublished - Get the oldest events not relayed yet
+func (repository *OutboxRepositoryImpl) FindUnpublished(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := conn(ctx, repository.db).Where("published_at IS NULL").Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MarkPublished gives the event the sequence following the last one published and returns it;
+// ok is false when another relay already published the event. Two relays taking the same
+// sequence at once break its unique index: the second one fails and publishes the event again
+// later, so a sequence is only seen once all the sequences before it are.
+func (repository *OutboxRepositoryImpl) MarkPublished(ctx context.Context, eventId uint64, publishedAt time.Time) (uint64, bool, error) {
+	var last uint64
+	err := conn(ctx, repository.db).Model(&domain.OutboxEvent{}).Select("COALESCE(MAX(sequence), 0)").Scan(&last).Error
+	if err != nil {
+		return 0, false, err
+	}
+	sequence := last + 1
+	result := conn(ctx, repository.db).Model(&domain.OutboxEvent{}).
+		Where("id = ? AND published_at IS NULL", eventId).
+		Updates(map[string]interface{}{"published_at": publishedAt, "sequence": sequence})
+	if result.Error != nil || result.RowsAffected != 1 {
+		return 0, false, result.Error
+	}
+	return sequence, true, nil
+}
+
+// FindPublishedAfter - Get the relayed events of the given types following sequence, in the order they were published
+func (repository *OutboxRepositoryImpl) FindPublishedAfter(ctx context.Context, sequence uint64, types []string, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := conn(ctx, repository.db).
+		Where("sequence > ? AND type IN ?", sequence, types).
+		Order("sequence").Limit(limit).Find(&events).Error
+	return events, err
+}
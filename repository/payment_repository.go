@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type PaymentRepository interface {
+	Save(ctx context.Context, payment domain.Payment) (domain.Payment, bool, error)
+	Update(ctx context.Context, payment domain.Payment) (domain.Payment, bool, error)
+	FindById(ctx context.Context, paymentId string) (domain.Payment, error)
+	FindByOrder(ctx context.Context, orderId string) ([]domain.Payment, error)
+	FindByOrders(ctx context.Context, orderIds []string) ([]domain.Payment, error)
+	FindByCharge(ctx context.Context, chargeId string) (domain.Payment, error)
+	FindAll(ctx context.Context, storeId string, customerId string) ([]domain.Payment, error)
+	SaveNotification(ctx context.Context, notification domain.PaymentNotification) (bool, error)
+}
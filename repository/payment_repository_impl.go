@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &PaymentRepositoryImpl{db: db}
+}
+
+// Save payment with its tenders, unless its order has a payment that did not fail: ok is then
+// false and nothing is written
+func (repository *PaymentRepositoryImpl) Save(ctx context.Context, payment domain.Payment) (domain.Payment, bool, error) {
+	db := conn(ctx, repository.db)
+	payment.ActiveOrderID = nil
+	if payment.Status != domain.PaymentFailed {
+		payment.ActiveOrderID = &payment.OrderID
+	}
+	result := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&payment)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return domain.Payment{}, false, result.Error
+	}
+	for i := range payment.Tenders {
+		payment.Tenders[i].PaymentID = payment.PaymentID
+	}
+	if len(payment.Tenders) > 0 {
+		if err := db.Create(&payment.Tenders).Error; err != nil {
+			return domain.Payment{}, false, err
+		}
+	}
+	return payment, true, nil
+}
+
+// Update payment and replace its tenders, unless it was updated since it was read: ok is then
+// false and nothing is written
+func (repository *PaymentRepositoryImpl) Update(ctx context.Context, payment domain.Payment) (domain.Payment, bool, error) {
+	db := conn(ctx, repository.db)
+	version := payment.Version
+	payment.Version++
+	payment.UpdatedAt = time.Now()
+	columns := map[string]interface{}{
+		"status":     payment.Status,
+		"version":    payment.Version,
+		"updated_at": payment.UpdatedAt,
+	}
+	if payment.Status == domain.PaymentFailed {
+		// the order can be paid again
+		payment.ActiveOrderID = nil
+		columns["active_order_id"] = nil
+	}
+	result := db.Model(&domain.Payment{}).
+		Where("payment_id = ? AND version = ?", payment.PaymentID, version).
+		Updates(columns)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return domain.Payment{}, false, result.Error
+	}
+
+	if err := db.Where("payment_id = ?", payment.PaymentID).Delete(&domain.PaymentTender{}).Error; err != nil {
+		return domain.Payment{}, false, err
+	}
+	for i := range payment.Tenders {
+		payment.Tenders[i].PaymentID = payment.PaymentID
+	}
+	if len(payment.Tenders) > 0 {
+		if err := db.Create(&payment.Tenders).Error; err != nil {
+			return domain.Payment{}, false, err
+		}
+	}
+	return payment, true, nil
+}
+
+// FindById - Get payment by ID with its tenders
+func (repository *PaymentRepositoryImpl) FindById(ctx context.Context, paymentId string) (domain.Payment, error) {
+	var payment domain.Payment
+	err := conn(ctx, repository.db).Preload("Tenders", positionOrder).First(&payment, "payment_id = ?", paymentId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return payment, fmt.Errorf("payment is not found: %w", err)
+	}
+	return payment, err
+}
+
+// FindByOrder - Get the payments of an order, oldest first
+func (repository *PaymentRepositoryImpl) FindByOrder(ctx context.Context, orderId string) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	err := conn(ctx, repository.db).Preload("Tenders", positionOrder).
+		Where("order_id = ?", orderId).Order("created_at").Order("payment_id").Find(&payments).Error
+	return payments, err
+}
+
+// FindByOrders - Get the payments of orders, oldest first
+func (repository *PaymentRepositoryImpl) FindByOrders(ctx context.Context, orderIds []string) ([]domain.Payment, error) {
+	if len(orderIds) == 0 {
+		return nil, nil
+	}
+	var payments []domain.Payment
+	err := conn(ctx, repository.db).Preload("Tenders", positionOrder).
+		Where("order_id IN ?", orderIds).Order("created_at").Order("payment_id").Find(&payments).Error
+	return payments, err
+}
+
+// FindByCharge - Get the payment with a tender charged as chargeId by a gateway
+func (repository *PaymentRepositoryImpl) FindByCharge(ctx context.Context, chargeId string) (domain.Payment, error) {
+	var tender domain.PaymentTender
+	err := conn(ctx, repository.db).First(&tender, "charge_id = ?", chargeId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Payment{}, fmt.Errorf("payment is not found: %w", err)
+	} else if err != nil {
+		return domain.Payment{}, err
+	}
+	return repository.FindById(ctx, tender.PaymentID)
+}
+
+// FindAll - Get the payments of a store and of a customer, an empty ID matches every store or
+// customer, newest first
+func (repository *PaymentRepositoryImpl) FindAll(ctx context.Context, storeId string, customerId string) ([]domain.Payment, error) {
+	query := conn(ctx, repository.db).Preload("Tenders", positionOrder)
+	if storeId != "" {
+		query = query.Where("store_id = ?", storeId)
+	}
+	if customerId != "" {
+		query = query.Where("customer_id = ?", customerId)
+	}
+	var payments []domain.Payment
+	err := query.Order("created_at DESC").Order("payment_id").Find(&payments).Error
+	return payments, err
+}
+
+// SaveNotification records a notification of a gateway, ok is false when it was already recorded
+func (repository *PaymentRepositoryImpl) SaveNotification(ctx context.Context, notification domain.PaymentNotification) (bool, error) {
+	result := conn(ctx, repository.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&notification)
+	return result.RowsAffected == 1, result.Error
+}
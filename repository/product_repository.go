@@ -11,4 +11,9 @@ type ProductRepository interface {
 	Delete(ctx context.Context, product domain.Product) error
 	FindById(ctx context.Context, productId string) (domain.Product, error)
 	FindAll(ctx context.Context) ([]domain.Product, error)
+	FindByCategories(ctx context.Context, categories []string) ([]domain.Product, error)
+	FindByOptions(ctx context.Context, options map[string]string) ([]domain.Product, error)
+	FindVariantBySKU(ctx context.Context, sku string) (domain.ProductVariant, error)
+	AdjustStock(ctx context.Context, productId string, delta int) (bool, error)
+	AdjustVariantStock(ctx context.Context, productId string, variantId string, delta int) (bool, error)
 }
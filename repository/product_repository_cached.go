@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/cache"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+// CachedProductRepository serves FindById and FindAll from a read-through cache and
+// invalidates it on every write. Reads inside a transaction bypass the cache, they may see
+// rows that are not committed yet.
+type CachedProductRepository struct {
+	ProductRepository
+	cache *cache.Cache
+}
+
+func NewCachedProductRepository(productRepository ProductRepository, productCache *cache.Cache) ProductRepository {
+	return &CachedProductRepository{ProductRepository: productRepository, cache: productCache}
+}
+
+// Save product
+func (repository *CachedProductRepository) Save(ctx context.Context, product domain.Product) (domain.Product, error) {
+	product, err := repository.ProductRepository.Save(ctx, product)
+	if err != nil {
+		return product, err
+	}
+	return product, repository.invalidate(ctx, "all")
+}
+
+// Update product
+func (repository *CachedProductRepository) Update(ctx context.Context, product domain.Product) (domain.Product, error) {
+	product, err := repository.ProductRepository.Update(ctx, product)
+	if err != nil {
+		return product, err
+	}
+	return product, repository.invalidate(ctx, "id:"+product.ProductID, "all")
+}
+
+// Delete product
+func (repository *CachedProductRepository) Delete(ctx context.Context, product domain.Product) error {
+	if err := repository.ProductRepository.Delete(ctx, product); err != nil {
+		return err
+	}
+	return repository.invalidate(ctx, "id:"+product.ProductID, "all")
+}
+
+// AdjustStock of a product
+func (repository *CachedProductRepository) AdjustStock(ctx context.Context, productId string, delta int) (bool, error) {
+	ok, err := repository.ProductRepository.AdjustStock(ctx, productId, delta)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return ok, repository.invalidate(ctx, "id:"+productId, "all")
+}
+
+// AdjustVariantStock of a variant and its product
+func (repository *CachedProductRepository) AdjustVariantStock(ctx context.Context, productId string, variantId string, delta int) (bool, error) {
+	ok, err := repository.ProductRepository.AdjustVariantStock(ctx, productId, variantId, delta)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return ok, repository.invalidate(ctx, "id:"+productId, "all")
+}
+
+// FindById - Get product by ID
+func (repository *CachedProductRepository) FindById(ctx context.Context, productId string) (domain.Product, error) {
+	if InTransaction(ctx) {
+		return repository.ProductRepository.FindById(ctx, productId)
+	}
+
+	var product domain.Product
+	err := repository.cache.Load(ctx, "id:"+productId, &product, func(ctx context.Context) (interface{}, error) {
+		return repository.ProductRepository.FindById(ctx, productId)
+	})
+	return product, err
+}
+
+// FindAll - Get all products
+func (repository *CachedProductRepository) FindAll(ctx context.Context) ([]domain.Product, error) {
+	if InTransaction(ctx) {
+		return repository.ProductRepository.FindAll(ctx)
+	}
+
+	var products []domain.Product
+	err := repository.cache.Load(ctx, "all", &products, func(ctx context.Context) (interface{}, error) {
+		return repository.ProductRepository.FindAll(ctx)
+	})
+	return products, err
+}
+
+// invalidate drops keys now and once more after the surrounding transaction commits, so a
+// reader cannot put the old row back in between
+func (repository *CachedProductRepository) invalidate(ctx context.Context, keys ...string) error {
+	AfterCommit(ctx, func() {
+		_ = repository.cache.Invalidate(context.WithoutCancel(ctx), keys...)
+	})
+	return repository.cache.Invalidate(ctx, keys...)
+}
@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/cache"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedProductRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mocks.NewMockProductRepository(ctrl)
+	ctx := context.Background()
+	laptop := domain.Product{ProductID: "P001", Name: "Laptop", Price: money.IDR(15000000)}
+
+	tests := []struct {
+		name   string
+		mock   func()
+		method func(repo repository.ProductRepository) (interface{}, error)
+		expect interface{}
+	}{
+		{
+			name: "FindById is loaded once",
+			mock: func() {
+				inner.EXPECT().FindById(gomock.Any(), "P001").Return(laptop, nil).Times(1)
+			},
+			method: func(repo repository.ProductRepository) (interface{}, error) {
+				repo.FindById(ctx, "P001")
+				return repo.FindById(ctx, "P001")
+			},
+			expect: laptop,
+		},
+		{
+			name: "FindById keeps the currency of the prices",
+			mock: func() {
+				override := money.New(1450, "USD")
+				tea := domain.Product{ProductID: "P003", Name: "Tea", Price: money.New(1250, "USD"), Currency: "USD",
+					Variants: []domain.ProductVariant{{VariantID: "V1", ProductID: "P003", PriceOverride: &override}, {VariantID: "V2", ProductID: "P003"}}}
+				inner.EXPECT().FindById(gomock.Any(), "P003").Return(tea, nil).Times(1)
+			},
+			method: func(repo repository.ProductRepository) (interface{}, error) {
+				repo.FindById(ctx, "P003")
+				return repo.FindById(ctx, "P003")
+			},
+			expect: func() domain.Product {
+				override := money.New(1450, "USD")
+				return domain.Product{ProductID: "P003", Name: "Tea", Price: money.New(1250, "USD"), Currency: "USD",
+					Variants: []domain.ProductVariant{{VariantID: "V1", ProductID: "P003", PriceOverride: &override}, {VariantID: "V2", ProductID: "P003"}}}
+			}(),
+		},
+		{
+			name: "Update invalidates FindById",
+			mock: func() {
+				updated := domain.Product{ProductID: "P001", Name: "Laptop", Price: money.IDR(14000000)}
+				gomock.InOrder(
+					inner.EXPECT().FindById(gomock.Any(), "P001").Return(laptop, nil),
+					inner.EXPECT().Update(ctx, updated).Return(updated, nil),
+					inner.EXPECT().FindById(gomock.Any(), "P001").Return(updated, nil),
+				)
+			},
+			method: func(repo repository.ProductRepository) (interface{}, error) {
+				repo.FindById(ctx, "P001")
+				repo.Update(ctx, domain.Product{ProductID: "P001", Name: "Laptop", Price: money.IDR(14000000)})
+				return repo.FindById(ctx, "P001")
+			},
+			expect: domain.Product{ProductID: "P001", Name: "Laptop", Price: money.IDR(14000000)},
+		},
+		{
+			name: "Save invalidates FindAll",
+			mock: func() {
+				kopi := domain.Product{ProductID: "P002", Name: "Kopi"}
+				gomock.InOrder(
+					inner.EXPECT().FindAll(gomock.Any()).Return([]domain.Product{laptop}, nil),
+					inner.EXPECT().Save(ctx, kopi).Return(kopi, nil),
+					inner.EXPECT().FindAll(gomock.Any()).Return([]domain.Product{laptop, kopi}, nil),
+				)
+			},
+			method: func(repo repository.ProductRepository) (interface{}, error) {
+				repo.FindAll(ctx)
+				repo.Save(ctx, domain.Product{ProductID: "P002", Name: "Kopi"})
+				return repo.FindAll(ctx)
+			},
+			expect: []domain.Product{laptop, {ProductID: "P002", Name: "Kopi"}},
+		},
+		{
+			name: "Delete invalidates FindById",
+			mock: func() {
+				gomock.InOrder(
+					inner.EXPECT().FindById(gomock.Any(), "P001").Return(laptop, nil),
+					inner.EXPECT().Delete(ctx, laptop).Return(nil),
+					inner.EXPECT().FindById(gomock.Any(), "P001").Return(domain.Product{}, errors.New("product is not found")),
+				)
+			},
+			method: func(repo repository.ProductRepository) (interface{}, error) {
+				repo.FindById(ctx, "P001")
+				repo.Delete(ctx, laptop)
+				return repo.FindById(ctx, "P001")
+			},
+			expect: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := repository.NewCachedProductRepository(inner, cache.New("products", cache.NewLRU(100), time.Minute, nil))
+			result, err := tt.method(repo)
+			if tt.expect == nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, result)
+			}
+		})
+	}
+}
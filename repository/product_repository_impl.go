@@ -3,8 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepositoryImpl struct {
@@ -15,25 +19,52 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &ProductRepositoryImpl{db: db}
 }
 
-// Save product
+// Save product with its options and variants
 func (repository *ProductRepositoryImpl) Save(ctx context.Context, product domain.Product) (domain.Product, error) {
-	if err := repository.db.WithContext(ctx).Create(&product).Error; err != nil {
+	if err := conn(ctx, repository.db).Create(&product).Error; err != nil {
 		return domain.Product{}, err
 	}
 	return product, nil
 }
 
-// Update product
+// Update product, its options and variants are replaced by product.Options and product.Variants
 func (repository *ProductRepositoryImpl) Update(ctx context.Context, product domain.Product) (domain.Product, error) {
-	if err := repository.db.WithContext(ctx).Save(&product).Error; err != nil {
+	db := conn(ctx, repository.db)
+	if err := db.Omit(clause.Associations).Save(&product).Error; err != nil {
+		return domain.Product{}, err
+	}
+	if err := deleteVariants(db, product.ProductID); err != nil {
 		return domain.Product{}, err
 	}
+	for i := range product.Options {
+		product.Options[i].ProductID = product.ProductID
+	}
+	if len(product.Options) > 0 {
+		if err := db.Create(&product.Options).Error; err != nil {
+			return domain.Product{}, err
+		}
+	}
+	for i := range product.Variants {
+		product.Variants[i].ProductID = product.ProductID
+	}
+	if len(product.Variants) > 0 {
+		if err := db.Create(&product.Variants).Error; err != nil {
+			return domain.Product{}, err
+		}
+	}
 	return product, nil
 }
 
-// Delete product
+// Delete product with its variants and its stock in the stores
 func (repository *ProductRepositoryImpl) Delete(ctx context.Context, product domain.Product) error {
-	if err := repository.db.WithContext(ctx).Delete(&product).Error; err != nil {
+	db := conn(ctx, repository.db)
+	if err := db.Where("product_id = ?", product.ProductID).Delete(&domain.StoreStock{}).Error; err != nil {
+		return err
+	}
+	if err := deleteVariants(db, product.ProductID); err != nil {
+		return err
+	}
+	if err := db.Omit(clause.Associations).Delete(&product).Error; err != nil {
 		return err
 	}
 	return nil
@@ -42,9 +73,9 @@ func (repository *ProductRepositoryImpl) Delete(ctx context.Context, product dom
 // FindById - Get product by ID
 func (repository *ProductRepositoryImpl) FindById(ctx context.Context, productId string) (domain.Product, error) {
 	var product domain.Product
-	err := repository.db.WithContext(ctx).First(&product, "product_id = ?", productId).Error
+	err := withVariants(conn(ctx, repository.db)).First(&product, "product_id = ?", productId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return product, errors.New("product is not found")
+		return product, fmt.Errorf("product is not found: %w", err)
 	}
 	return product, err
 }
@@ -52,6 +83,93 @@ func (repository *ProductRepositoryImpl) FindById(ctx context.Context, productId
 // FindAll - Get all products
 func (repository *ProductRepositoryImpl) FindAll(ctx context.Context) ([]domain.Product, error) {
 	var products []domain.Product
-	err := repository.db.WithContext(ctx).Find(&products).Error
+	err := withVariants(conn(ctx, repository.db)).Find(&products).Error
+	return products, err
+}
+
+// FindByCategories - Get the products of the given categories
+func (repository *ProductRepositoryImpl) FindByCategories(ctx context.Context, categories []string) ([]domain.Product, error) {
+	var products []domain.Product
+	err := withVariants(conn(ctx, repository.db)).Where("category IN ?", categories).Find(&products).Error
+	return products, err
+}
+
+// FindByOptions - Get the products having a variant with all the given option values, only the
+// matching variants are loaded
+func (repository *ProductRepositoryImpl) FindByOptions(ctx context.Context, options map[string]string) ([]domain.Product, error) {
+	db := conn(ctx, repository.db)
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// An option is set once per variant, a variant matches when every condition found a row
+	matching := db.Session(&gorm.Session{NewDB: true}).Model(&domain.ProductVariantOption{}).Select("variant_id")
+	conditions := db.Session(&gorm.Session{NewDB: true})
+	for _, name := range names {
+		conditions = conditions.Or("name = ? AND value = ?", name, options[name])
+	}
+	matching = matching.Where(conditions).Group("variant_id").Having("COUNT(*) = ?", len(names))
+
+	var products []domain.Product
+	err := db.Preload("Options", optionOrder).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Where("variant_id IN (?)", matching).Order("position")
+		}).
+		Preload("Variants.Options").
+		Where("product_id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&domain.ProductVariant{}).Select("product_id").Where("variant_id IN (?)", matching)).
+		Find(&products).Error
 	return products, err
 }
+
+// FindVariantBySKU - Get a variant of any product by its SKU
+func (repository *ProductRepositoryImpl) FindVariantBySKU(ctx context.Context, sku string) (domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	err := conn(ctx, repository.db).Preload("Options").First(&variant, "sku = ?", sku).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return variant, fmt.Errorf("product variant is not found: %w", err)
+	}
+	return variant, err
+}
+
+// AdjustStock adds delta to the central stock of a product, ok is false when the product does
+// not exist or the stock would become negative
+func (repository *ProductRepositoryImpl) AdjustStock(ctx context.Context, productId string, delta int) (bool, error) {
+	result := conn(ctx, repository.db).Model(&domain.Product{}).
+		Where("product_id = ? AND stock_qty + ? >= 0", productId, delta).
+		UpdateColumn("stock_qty", gorm.Expr("stock_qty + ?", delta))
+	return result.RowsAffected == 1, result.Error
+}
+
+// AdjustVariantStock adds delta to the central stock of a variant and of its product, ok is false
+// when the variant does not exist or either stock would become negative
+func (repository *ProductRepositoryImpl) AdjustVariantStock(ctx context.Context, productId string, variantId string, delta int) (bool, error) {
+	result := conn(ctx, repository.db).Model(&domain.ProductVariant{}).
+		Where("variant_id = ? AND product_id = ? AND stock_qty + ? >= 0", variantId, productId, delta).
+		UpdateColumn("stock_qty", gorm.Expr("stock_qty + ?", delta))
+	if result.Error != nil || result.RowsAffected != 1 {
+		return false, result.Error
+	}
+	return repository.AdjustStock(ctx, productId, delta)
+}
+
+func optionOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// withVariants preloads the options and the variants of the products in their order
+func withVariants(db *gorm.DB) *gorm.DB {
+	return db.Preload("Options", optionOrder).Preload("Variants", optionOrder).Preload("Variants.Options")
+}
+
+func deleteVariants(db *gorm.DB, productId string) error {
+	variants := db.Session(&gorm.Session{NewDB: true}).Model(&domain.ProductVariant{}).Select("variant_id").Where("product_id = ?", productId)
+	if err := db.Where("variant_id IN (?)", variants).Delete(&domain.ProductVariantOption{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("product_id = ?", productId).Delete(&domain.ProductVariant{}).Error; err != nil {
+		return err
+	}
+	return db.Where("product_id = ?", productId).Delete(&domain.ProductOption{}).Error
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
 	"github.com/aronipurwanto/go-restful-api/repository/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -28,13 +29,13 @@ func TestProductRepository(t *testing.T) {
 		{
 			name: "Save Success",
 			mock: func() {
-				product := domain.Product{ProductID: "1", Name: "Laptop", Price: 15000000}
+				product := domain.Product{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)}
 				repo.EXPECT().Save(ctx, product).Return(product, nil)
 			},
 			method: func() (interface{}, error) {
-				return repo.Save(ctx, domain.Product{ProductID: "1", Name: "Laptop", Price: 15000000})
+				return repo.Save(ctx, domain.Product{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)})
 			},
-			expect:    domain.Product{ProductID: "1", Name: "Laptop", Price: 15000000},
+			expect:    domain.Product{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)},
 			expectErr: false,
 		},
 		{
@@ -43,7 +44,7 @@ func TestProductRepository(t *testing.T) {
 				repo.EXPECT().Save(ctx, gomock.Any()).Return(domain.Product{}, errors.New("error saving"))
 			},
 			method: func() (interface{}, error) {
-				return repo.Save(ctx, domain.Product{Name: "Invalid", Price: 0})
+				return repo.Save(ctx, domain.Product{Name: "Invalid", Price: money.IDR(0)})
 			},
 			expect:    domain.Product{},
 			expectErr: true,
@@ -51,12 +52,12 @@ func TestProductRepository(t *testing.T) {
 		{
 			name: "FindById Success",
 			mock: func() {
-				repo.EXPECT().FindById(ctx, "1").Return(domain.Product{ProductID: "1", Name: "Laptop", Price: 15000000}, nil)
+				repo.EXPECT().FindById(ctx, "1").Return(domain.Product{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)}, nil)
 			},
 			method: func() (interface{}, error) {
 				return repo.FindById(ctx, "1")
 			},
-			expect:    domain.Product{ProductID: "1", Name: "Laptop", Price: 15000000},
+			expect:    domain.Product{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)},
 			expectErr: false,
 		},
 		{
@@ -73,12 +74,12 @@ func TestProductRepository(t *testing.T) {
 		{
 			name: "FindAll Success",
 			mock: func() {
-				repo.EXPECT().FindAll(ctx).Return([]domain.Product{{ProductID: "1", Name: "Laptop", Price: 15000000}}, nil)
+				repo.EXPECT().FindAll(ctx).Return([]domain.Product{{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)}}, nil)
 			},
 			method: func() (interface{}, error) {
 				return repo.FindAll(ctx)
 			},
-			expect:    []domain.Product{{ProductID: "1", Name: "Laptop", Price: 15000000}},
+			expect:    []domain.Product{{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000)}},
 			expectErr: false,
 		},
 		{
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type PromotionRepository interface {
+	Save(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	Update(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	Delete(ctx context.Context, promotion domain.Promotion) error
+	FindById(ctx context.Context, promotionId string) (domain.Promotion, error)
+	FindAll(ctx context.Context) ([]domain.Promotion, error)
+	FindActive(ctx context.Context, at time.Time) ([]domain.Promotion, error)
+	IncrementUsage(ctx context.Context, promotionId string) (bool, error)
+}
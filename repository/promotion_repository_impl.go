@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+type PromotionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &PromotionRepositoryImpl{db: db}
+}
+
+// Save promotion
+func (repository *PromotionRepositoryImpl) Save(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	if err := conn(ctx, repository.db).Create(&promotion).Error; err != nil {
+		return domain.Promotion{}, err
+	}
+	return promotion, nil
+}
+
+// Update promotion
+func (repository *PromotionRepositoryImpl) Update(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+	if err := conn(ctx, repository.db).Save(&promotion).Error; err != nil {
+		return domain.Promotion{}, err
+	}
+	return promotion, nil
+}
+
+// Delete promotion
+func (repository *PromotionRepositoryImpl) Delete(ctx context.Context, promotion domain.Promotion) error {
+	return conn(ctx, repository.db).Delete(&promotion).Error
+}
+
+// FindById - Get promotion by ID
+func (repository *PromotionRepositoryImpl) FindById(ctx context.Context, promotionId string) (domain.Promotion, error) {
+	var promotion domain.Promotion
+	err := conn(ctx, repository.db).First(&promotion, "promotion_id = ?", promotionId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return promotion, fmt.Errorf("promotion is not found: %w", err)
+	}
+	return promotion, err
+}
+
+// FindAll - Get all promotions, highest priority first
+func (repository *PromotionRepositoryImpl) FindAll(ctx context.Context) ([]domain.Promotion, error) {
+	var promotions []domain.Promotion
+	err := conn(ctx, repository.db).Order("priority DESC").Order("promotion_id").Find(&promotions).Error
+	return promotions, err
+}
+
+// FindActive - Get the active promotions whose validity window contains at
+func (repository *PromotionRepositoryImpl) FindActive(ctx context.Context, at time.Time) ([]domain.Promotion, error) {
+	var promotions []domain.Promotion
+	err := conn(ctx, repository.db).
+		Where("active = ?", true).
+		Where("starts_at IS NULL OR starts_at <= ?", at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Order("priority DESC").Order("promotion_id").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// IncrementUsage counts a sale against the usage limit of a promotion. ok is false when the
+// promotion does not exist or reached its limit.
+func (repository *PromotionRepositoryImpl) IncrementUsage(ctx context.Context, promotionId string) (bool, error) {
+	result := conn(ctx, repository.db).Model(&domain.Promotion{}).
+		Where("promotion_id = ? AND (usage_limit = 0 OR usage_count < usage_limit)", promotionId).
+		Updates(map[string]interface{}{"usage_count": gorm.Expr("usage_count + 1"), "updated_at": time.Now()})
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type ReturnRepository interface {
+	Save(ctx context.Context, orderReturn domain.Return) (domain.Return, error)
+	Update(ctx context.Context, orderReturn domain.Return) (domain.Return, bool, error)
+	FindById(ctx context.Context, returnId string) (domain.Return, error)
+	FindByOrder(ctx context.Context, orderId string) ([]domain.Return, error)
+	FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.Return, error)
+	FindCompleted(ctx context.Context, storeId string, employeeId string, from time.Time, to time.Time) ([]domain.Return, error)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+type ReturnRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReturnRepository(db *gorm.DB) ReturnRepository {
+	return &ReturnRepositoryImpl{db: db}
+}
+
+// Save return with its lines
+func (repository *ReturnRepositoryImpl) Save(ctx context.Context, orderReturn domain.Return) (domain.Return, error) {
+	if err := conn(ctx, repository.db).Create(&orderReturn).Error; err != nil {
+		return domain.Return{}, err
+	}
+	return orderReturn, nil
+}
+
+// Update the decision and the refund of a return, unless it was updated since it was read: ok
+// is then false and nothing is written. The lines of a return do not change.
+func (repository *ReturnRepositoryImpl) Update(ctx context.Context, orderReturn domain.Return) (domain.Return, bool, error) {
+	version := orderReturn.Version
+	orderReturn.Version++
+	orderReturn.UpdatedAt = time.Now()
+	result := conn(ctx, repository.db).Model(&domain.Return{}).
+		Where("return_id = ? AND version = ?", orderReturn.ReturnID, version).
+		Select("approved_by", "status", "note", "points_reversed", "refunds", "version", "updated_at", "completed_at").
+		Updates(&orderReturn)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return domain.Return{}, false, result.Error
+	}
+	return orderReturn, true, nil
+}
+
+// FindById - Get return by ID with its lines
+func (repository *ReturnRepositoryImpl) FindById(ctx context.Context, returnId string) (domain.Return, error) {
+	var orderReturn domain.Return
+	err := conn(ctx, repository.db).Preload("Lines", positionOrder).First(&orderReturn, "return_id = ?", returnId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return orderReturn, fmt.Errorf("return is not found: %w", err)
+	}
+	return orderReturn, err
+}
+
+// FindByOrder - Get the returns of an order, oldest first
+func (repository *ReturnRepositoryImpl) FindByOrder(ctx context.Context, orderId string) ([]domain.Return, error) {
+	var orderReturns []domain.Return
+	err := conn(ctx, repository.db).Preload("Lines", positionOrder).
+		Where("order_id = ?", orderId).Order("created_at").Order("return_id").Find(&orderReturns).Error
+	return orderReturns, err
+}
+
+// FindAll - Get the returns of a store in one of statuses, an empty ID matches every store and
+// no status every status, newest first
+func (repository *ReturnRepositoryImpl) FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.Return, error) {
+	query := conn(ctx, repository.db).Preload("Lines", positionOrder)
+	if storeId != "" {
+		query = query.Where("store_id = ?", storeId)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	var orderReturns []domain.Return
+	err := query.Order("created_at DESC").Order("return_id").Find(&orderReturns).Error
+	return orderReturns, err
+}
+
+// FindCompleted - Get the returns of a store completed from from until before to, processed by
+// one employee or by every employee when employeeId is empty, oldest first
+func (repository *ReturnRepositoryImpl) FindCompleted(ctx context.Context, storeId string, employeeId string, from time.Time, to time.Time) ([]domain.Return, error) {
+	query := conn(ctx, repository.db).Preload("Lines", positionOrder).
+		Where("store_id = ? AND status = ? AND completed_at >= ? AND completed_at < ?", storeId, domain.ReturnCompleted, from, to)
+	if employeeId != "" {
+		query = query.Where("employee_id = ?", employeeId)
+	}
+	var orderReturns []domain.Return
+	err := query.Order("completed_at").Order("return_id").Find(&orderReturns).Error
+	return orderReturns, err
+}
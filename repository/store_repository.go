@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type StoreRepository interface {
+	Save(ctx context.Context, store domain.Store) (domain.Store, error)
+	Update(ctx context.Context, store domain.Store) (domain.Store, error)
+	Delete(ctx context.Context, store domain.Store) error
+	FindById(ctx context.Context, storeId string) (domain.Store, error)
+	FindByIds(ctx context.Context, storeIds []string) ([]domain.Store, error)
+	FindAll(ctx context.Context) ([]domain.Store, error)
+}
+
+type StoreStockRepository interface {
+	Save(ctx context.Context, stock domain.StoreStock) (domain.StoreStock, error)
+	FindById(ctx context.Context, storeId string, productId string) (domain.StoreStock, error)
+	FindByStore(ctx context.Context, storeId string) ([]domain.StoreStock, error)
+	Adjust(ctx context.Context, storeId string, productId string, delta int) (bool, error)
+	SaveVariant(ctx context.Context, stock domain.StoreVariantStock) (domain.StoreVariantStock, error)
+	FindVariants(ctx context.Context, storeId string, productId string) ([]domain.StoreVariantStock, error)
+	AdjustVariant(ctx context.Context, storeId string, productId string, variantId string, delta int) (bool, error)
+}
+
+type StockTransferRepository interface {
+	Save(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, error)
+	Update(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, bool, error)
+	FindById(ctx context.Context, transferId string) (domain.StockTransfer, error)
+	FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.StockTransfer, error)
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoreRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewStoreRepository(db *gorm.DB) StoreRepository {
+	return &StoreRepositoryImpl{db: db}
+}
+
+// Save store
+func (repository *StoreRepositoryImpl) Save(ctx context.Context, store domain.Store) (domain.Store, error) {
+	if err := conn(ctx, repository.db).Create(&store).Error; err != nil {
+		return domain.Store{}, err
+	}
+	return store, nil
+}
+
+// Update store
+func (repository *StoreRepositoryImpl) Update(ctx context.Context, store domain.Store) (domain.Store, error) {
+	if err := conn(ctx, repository.db).Save(&store).Error; err != nil {
+		return domain.Store{}, err
+	}
+	return store, nil
+}
+
+// Delete store together with its stock and its employee assignments
+func (repository *StoreRepositoryImpl) Delete(ctx context.Context, store domain.Store) error {
+	db := conn(ctx, repository.db)
+	if err := db.Where("store_id = ?", store.StoreID).Delete(&domain.StoreStock{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("store_id = ?", store.StoreID).Delete(&domain.EmployeeStore{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(&store).Error
+}
+
+// FindById - Get store by ID
+func (repository *StoreRepositoryImpl) FindById(ctx context.Context, storeId string) (domain.Store, error) {
+	var store domain.Store
+	err := conn(ctx, repository.db).First(&store, "store_id = ?", storeId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return store, fmt.Errorf("store is not found: %w", err)
+	}
+	return store, err
+}
+
+// FindByIds - Get the stores among storeIds that exist
+func (repository *StoreRepositoryImpl) FindByIds(ctx context.Context, storeIds []string) ([]domain.Store, error) {
+	var stores []domain.Store
+	err := conn(ctx, repository.db).Where("store_id IN ?", storeIds).Order("store_id").Find(&stores).Error
+	return stores, err
+}
+
+// FindAll - Get all stores
+func (repository *StoreRepositoryImpl) FindAll(ctx context.Context) ([]domain.Store, error) {
+	var stores []domain.Store
+	err := conn(ctx, repository.db).Order("store_id").Find(&stores).Error
+	return stores, err
+}
+
+type StoreStockRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewStoreStockRepository(db *gorm.DB) StoreStockRepository {
+	return &StoreStockRepositoryImpl{db: db}
+}
+
+// Save the stock of a product in a store, inserting or replacing it
+func (repository *StoreStockRepositoryImpl) Save(ctx context.Context, stock domain.StoreStock) (domain.StoreStock, error) {
+	err := conn(ctx, repository.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "store_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"stock_qty", "price_override", "updated_at"}),
+	}).Create(&stock).Error
+	if err != nil {
+		return domain.StoreStock{}, err
+	}
+	return stock, nil
+}
+
+// FindById - Get the stock of a product in a store
+func (repository *StoreStockRepositoryImpl) FindById(ctx context.Context, storeId string, productId string) (domain.StoreStock, error) {
+	var stock domain.StoreStock
+	err := conn(ctx, repository.db).First(&stock, "store_id = ? AND product_id = ?", storeId, productId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return stock, fmt.Errorf("store stock is not found: %w", err)
+	}
+	return stock, err
+}
+
+// FindByStore - Get the stock of every product a store holds
+func (repository *StoreStockRepositoryImpl) FindByStore(ctx context.Context, storeId string) ([]domain.StoreStock, error) {
+	var stocks []domain.StoreStock
+	err := conn(ctx, repository.db).Where("store_id = ?", storeId).Order("product_id").Find(&stocks).Error
+	return stocks, err
+}
+
+// Adjust adds delta to the stock of a product in a store, creating the row for a positive delta.
+// ok is false when the stock would become negative.
+func (repository *StoreStockRepositoryImpl) Adjust(ctx context.Context, storeId string, productId string, delta int) (bool, error) {
+	db := conn(ctx, repository.db)
+	if delta < 0 {
+		result := db.Model(&domain.StoreStock{}).
+			Where("store_id = ? AND product_id = ? AND stock_qty + ? >= 0", storeId, productId, delta).
+			Updates(map[string]interface{}{"stock_qty": gorm.Expr("stock_qty + ?", delta), "updated_at": time.Now()})
+		return result.RowsAffected == 1, result.Error
+	}
+
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "store_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"stock_qty":  gorm.Expr("store_stocks.stock_qty + ?", delta),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&domain.StoreStock{StoreID: storeId, ProductID: productId, StockQty: delta}).Error
+	return err == nil, err
+}
+
+// SaveVariant - Save the stock of a variant in a store, inserting or replacing it. The stock of
+// the product is left as is.
+func (repository *StoreStockRepositoryImpl) SaveVariant(ctx context.Context, stock domain.StoreVariantStock) (domain.StoreVariantStock, error) {
+	err := conn(ctx, repository.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "store_id"}, {Name: "variant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"product_id", "stock_qty", "updated_at"}),
+	}).Create(&stock).Error
+	if err != nil {
+		return domain.StoreVariantStock{}, err
+	}
+	return stock, nil
+}
+
+// FindVariants - Get the stock of the variants of a product in a store, of every variant the
+// store holds when productId is empty
+func (repository *StoreStockRepositoryImpl) FindVariants(ctx context.Context, storeId string, productId string) ([]domain.StoreVariantStock, error) {
+	query := conn(ctx, repository.db).Where("store_id = ?", storeId)
+	if productId != "" {
+		query = query.Where("product_id = ?", productId)
+	}
+	var stocks []domain.StoreVariantStock
+	err := query.Order("product_id").Order("variant_id").Find(&stocks).Error
+	return stocks, err
+}
+
+// AdjustVariant adds delta to the stock of a variant in a store and to the stock of its product,
+// creating the rows for a positive delta. ok is false when either stock would become negative,
+// the caller rolls the transaction back then.
+func (repository *StoreStockRepositoryImpl) AdjustVariant(ctx context.Context, storeId string, productId string, variantId string, delta int) (bool, error) {
+	db := conn(ctx, repository.db)
+	if delta < 0 {
+		result := db.Model(&domain.StoreVariantStock{}).
+			Where("store_id = ? AND variant_id = ? AND stock_qty + ? >= 0", storeId, variantId, delta).
+			Updates(map[string]interface{}{"stock_qty": gorm.Expr("stock_qty + ?", delta), "updated_at": time.Now()})
+		if result.Error != nil || result.RowsAffected != 1 {
+			return false, result.Error
+		}
+	} else {
+		err := db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "store_id"}, {Name: "variant_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"stock_qty":  gorm.Expr("store_variant_stocks.stock_qty + ?", delta),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&domain.StoreVariantStock{StoreID: storeId, VariantID: variantId, ProductID: productId, StockQty: delta}).Error
+		if err != nil {
+			return false, err
+		}
+	}
+	return repository.Adjust(ctx, storeId, productId, delta)
+}
+
+type StockTransferRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewStockTransferRepository(db *gorm.DB) StockTransferRepository {
+	return &StockTransferRepositoryImpl{db: db}
+}
+
+// Save transfer with its lines
+func (repository *StockTransferRepositoryImpl) Save(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, error) {
+	if err := conn(ctx, repository.db).Create(&transfer).Error; err != nil {
+		return domain.StockTransfer{}, err
+	}
+	return transfer, nil
+}
+
+// Update transfer and replace its lines, unless it was updated since it was read: ok is then
+// false and nothing is written
+func (repository *StockTransferRepositoryImpl) Update(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, bool, error) {
+	db := conn(ctx, repository.db)
+	version := transfer.Version
+	transfer.Version++
+	transfer.UpdatedAt = time.Now()
+	result := db.Model(&domain.StockTransfer{}).
+		Where("transfer_id = ? AND version = ?", transfer.TransferID, version).
+		Updates(map[string]interface{}{
+			"status":        transfer.Status,
+			"note":          transfer.Note,
+			"version":       transfer.Version,
+			"updated_at":    transfer.UpdatedAt,
+			"dispatched_at": transfer.DispatchedAt,
+			"received_at":   transfer.ReceivedAt,
+		})
+	if result.Error != nil || result.RowsAffected != 1 {
+		return domain.StockTransfer{}, false, result.Error
+	}
+
+	if err := db.Where("transfer_id = ?", transfer.TransferID).Delete(&domain.StockTransferLine{}).Error; err != nil {
+		return domain.StockTransfer{}, false, err
+	}
+	for i := range transfer.Lines {
+		transfer.Lines[i].TransferID = transfer.TransferID
+	}
+	if len(transfer.Lines) > 0 {
+		if err := db.Create(&transfer.Lines).Error; err != nil {
+			return domain.StockTransfer{}, false, err
+		}
+	}
+	return transfer, true, nil
+}
+
+// FindById - Get transfer by ID with its lines
+func (repository *StockTransferRepositoryImpl) FindById(ctx context.Context, transferId string) (domain.StockTransfer, error) {
+	var transfer domain.StockTransfer
+	err := conn(ctx, repository.db).Preload("Lines", lineOrder).First(&transfer, "transfer_id = ?", transferId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return transfer, fmt.Errorf("stock transfer is not found: %w", err)
+	}
+	return transfer, err
+}
+
+// FindAll - Get the transfers leaving or reaching a store with one of statuses, newest first.
+// An empty storeId or statuses does not filter.
+func (repository *StockTransferRepositoryImpl) FindAll(ctx context.Context, storeId string, statuses []string) ([]domain.StockTransfer, error) {
+	query := conn(ctx, repository.db).Preload("Lines", lineOrder)
+	if storeId != "" {
+		query = query.Where("(source_store_id = ? OR destination_store_id = ?)", storeId, storeId)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	var transfers []domain.StockTransfer
+	err := query.Order("created_at DESC").Order("transfer_id").Find(&transfers).Error
+	return transfers, err
+}
+
+func lineOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("product_id").Order("variant_id")
+}
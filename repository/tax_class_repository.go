@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type TaxClassRepository interface {
+	FindById(ctx context.Context, taxClassId string) (domain.TaxClass, error)
+	FindAll(ctx context.Context) ([]domain.TaxClass, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+type TaxClassRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTaxClassRepository(db *gorm.DB) TaxClassRepository {
+	return &TaxClassRepositoryImpl{db: db}
+}
+
+// FindById - Get tax class by ID
+func (repository *TaxClassRepositoryImpl) FindById(ctx context.Context, taxClassId string) (domain.TaxClass, error) {
+	var class domain.TaxClass
+	err := conn(ctx, repository.db).First(&class, "tax_class_id = ?", taxClassId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return class, fmt.Errorf("tax class is not found: %w", err)
+	}
+	return class, err
+}
+
+// FindAll - Get all tax classes
+func (repository *TaxClassRepositoryImpl) FindAll(ctx context.Context) ([]domain.TaxClass, error) {
+	var classes []domain.TaxClass
+	err := conn(ctx, repository.db).Order("tax_class_id").Find(&classes).Error
+	return classes, err
+}
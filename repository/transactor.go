@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor runs several repository calls in one database transaction. The transaction
+// travels in the context, so repositories called with that context join it.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TransactorImpl struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &TransactorImpl{db: db}
+}
+
+type txKey struct{}
+
+type txState struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+// WithinTransaction commits when fn returns nil and rolls back otherwise. A nested call
+// joins the outer transaction.
+func (transactor *TransactorImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	state := &txState{}
+	err := transactor.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, callback := range state.afterCommit {
+		callback()
+	}
+	return nil
+}
+
+// AfterCommit runs callback once the transaction in ctx is committed, or right away when ctx
+// carries no transaction. Callbacks of a rolled back transaction are dropped.
+func AfterCommit(ctx context.Context, callback func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, callback)
+		return
+	}
+	callback()
+}
+
+// InTransaction tells whether ctx carries a transaction started by WithinTransaction
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// conn returns the transaction carried by ctx, or db bound to ctx
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type WebhookRepository interface {
+	Save(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	Update(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	Delete(ctx context.Context, webhook domain.Webhook) error
+	FindById(ctx context.Context, webhookId string) (domain.Webhook, error)
+	FindAll(ctx context.Context) ([]domain.Webhook, error)
+	FindActive(ctx context.Context) ([]domain.Webhook, error)
+}
+
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	Update(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	FindById(ctx context.Context, deliveryId string) (domain.WebhookDelivery, error)
+	FindByWebhook(ctx context.Context, webhookId string, limit int) ([]domain.WebhookDelivery, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	Claim(ctx context.Context, delivery domain.WebhookDelivery, until time.Time) (bool, error)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &WebhookRepositoryImpl{db: db}
+}
+
+// Save webhook
+func (repository *WebhookRepositoryImpl) Save(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := conn(ctx, repository.db).Create(&webhook).Error; err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Update webhook
+func (repository *WebhookRepositoryImpl) Update(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := conn(ctx, repository.db).Save(&webhook).Error; err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Delete webhook
+func (repository *WebhookRepositoryImpl) Delete(ctx context.Context, webhook domain.Webhook) error {
+	return conn(ctx, repository.db).Delete(&webhook).Error
+}
+
+// FindById - Get webhook by ID
+func (repository *WebhookRepositoryImpl) FindById(ctx context.Context, webhookId string) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := conn(ctx, repository.db).First(&webhook, "webhook_id = ?", webhookId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return webhook, fmt.Errorf("webhook is not found: %w", err)
+	}
+	return webhook, err
+}
+
+// FindAll - Get all webhooks
+func (repository *WebhookRepositoryImpl) FindAll(ctx context.Context) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := conn(ctx, repository.db).Order("created_at").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// FindActive - Get the webhooks that receive deliveries
+func (repository *WebhookRepositoryImpl) FindActive(ctx context.Context) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := conn(ctx, repository.db).Where("active = ?", true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+type WebhookDeliveryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryImpl{db: db}
+}
+
+// Save delivery
+func (repository *WebhookDeliveryRepositoryImpl) Save(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	if err := conn(ctx, repository.db).Create(&delivery).Error; err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// Update delivery
+func (repository *WebhookDeliveryRepositoryImpl) Update(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	if err := conn(ctx, repository.db).Save(&delivery).Error; err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// FindById - Get delivery by ID
+func (repository *WebhookDeliveryRepositoryImpl) FindById(ctx context.Context, deliveryId string) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := conn(ctx, repository.db).First(&delivery, "delivery_id = ?", deliveryId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return delivery, fmt.Errorf("delivery is not found: %w", err)
+	}
+	return delivery, err
+}
+
+// FindByWebhook - Get the latest deliveries of a webhook, newest first
+func (repository *WebhookDeliveryRepositoryImpl) FindByWebhook(ctx context.Context, webhookId string, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := conn(ctx, repository.db).Where("webhook_id = ?", webhookId).
+		Order("created_at DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDue - Get pending deliveries whose next attempt is due
+func (repository *WebhookDeliveryRepositoryImpl) FindDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := conn(ctx, repository.db).Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// Claim starts the next attempt of delivery: it counts the attempt and pushes the next one to
+// until, unless another worker claimed the same attempt first. If the winner dies before
+// recording the outcome, the delivery becomes due again at until.
+func (repository *WebhookDeliveryRepositoryImpl) Claim(ctx context.Context, delivery domain.WebhookDelivery, until time.Time) (bool, error) {
+	result := conn(ctx, repository.db).Model(&domain.WebhookDelivery{}).
+		Where("delivery_id = ? AND status = ? AND attempts = ?", delivery.DeliveryID, domain.DeliveryPending, delivery.Attempts).
+		Updates(map[string]interface{}{"attempts": delivery.Attempts + 1, "next_attempt_at": until})
+	return result.RowsAffected == 1, result.Error
+}
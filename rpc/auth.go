@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the metadata key carrying the API key, the counterpart of the X-API-Key header
+const APIKeyMetadata = "x-api-key"
+
+// StoreMetadata is the metadata key carrying the store context, the counterpart of the X-Store-ID header
+const StoreMetadata = "x-store-id"
+
+type principalKey struct{}
+
+// NewAuthInterceptor resolves the x-api-key metadata to its principal, see Principal
+func NewAuthInterceptor(authenticator auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var key string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(APIKeyMetadata); len(values) > 0 {
+				key = values[0]
+			}
+		}
+
+		principal, ok, err := authenticator.Authenticate(ctx, key)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid or missing API key")
+		}
+		return handler(context.WithValue(ctx, principalKey{}, principal), req)
+	}
+}
+
+// Principal returns the caller authenticated by NewAuthInterceptor
+func Principal(ctx context.Context) auth.Principal {
+	principal, _ := ctx.Value(principalKey{}).(auth.Principal)
+	return principal
+}
+
+// Store resolves the store context of the call like the Store middleware of the REST API: the
+// x-store-id metadata or the single store the caller is bound to, empty for cross-store callers
+// without one
+func Store(ctx context.Context) (string, error) {
+	var requested string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(StoreMetadata); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	principal := Principal(ctx)
+	storeId, err := principal.StoreContext(requested)
+	if errors.Is(err, auth.ErrStoreForbidden) {
+		return "", exception.NewForbiddenError(fmt.Sprintf("%s is not allowed to access store %s", principal.Name, requested))
+	}
+	if err != nil {
+		return "", exception.NewBadRequestError("a store context is required, send the x-store-id metadata")
+	}
+	return storeId, nil
+}
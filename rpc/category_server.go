@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// CategoryServer serves pb.CategoryService on top of service.CategoryService
+type CategoryServer struct {
+	pb.UnimplementedCategoryServiceServer
+	CategoryService service.CategoryService
+}
+
+func NewCategoryServer(categoryService service.CategoryService) *CategoryServer {
+	return &CategoryServer{CategoryService: categoryService}
+}
+
+func (server *CategoryServer) CreateCategory(ctx context.Context, request *pb.CreateCategoryRequest) (*pb.Category, error) {
+	categoryResponse, err := server.CategoryService.Create(ctx, web.CategoryCreateRequest{Name: request.GetName()})
+	if err != nil {
+		return nil, err
+	}
+	return toCategory(categoryResponse), nil
+}
+
+func (server *CategoryServer) UpdateCategory(ctx context.Context, request *pb.UpdateCategoryRequest) (*pb.Category, error) {
+	categoryResponse, err := server.CategoryService.Update(ctx, web.CategoryUpdateRequest{
+		Id:   int(request.GetId()),
+		Name: request.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toCategory(categoryResponse), nil
+}
+
+func (server *CategoryServer) DeleteCategory(ctx context.Context, request *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	if err := server.CategoryService.Delete(ctx, int(request.GetId())); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteCategoryResponse{}, nil
+}
+
+func (server *CategoryServer) GetCategory(ctx context.Context, request *pb.GetCategoryRequest) (*pb.Category, error) {
+	categoryResponse, err := server.CategoryService.FindById(ctx, int(request.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	return toCategory(categoryResponse), nil
+}
+
+func (server *CategoryServer) ListCategories(ctx context.Context, request *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categoryResponses, err := server.CategoryService.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListCategoriesResponse{Categories: make([]*pb.Category, 0, len(categoryResponses))}
+	for _, categoryResponse := range categoryResponses {
+		response.Categories = append(response.Categories, toCategory(categoryResponse))
+	}
+	return response, nil
+}
+
+func toCategory(category web.CategoryResponse) *pb.Category {
+	return &pb.Category{Id: int32(category.Id), Name: category.Name}
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// CustomerServer serves pb.CustomerService on top of service.CustomerService
+type CustomerServer struct {
+	pb.UnimplementedCustomerServiceServer
+	CustomerService service.CustomerService
+}
+
+func NewCustomerServer(customerService service.CustomerService) *CustomerServer {
+	return &CustomerServer{CustomerService: customerService}
+}
+
+func (server *CustomerServer) CreateCustomer(ctx context.Context, request *pb.CreateCustomerRequest) (*pb.Customer, error) {
+	customerResponse, err := server.CustomerService.Create(ctx, web.CustomerCreateRequest{
+		Name:       request.GetName(),
+		Email:      request.GetEmail(),
+		Phone:      request.GetPhone(),
+		Address:    request.GetAddress(),
+		LoyaltyPts: int(request.GetLoyaltyPoints()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(customerResponse), nil
+}
+
+func (server *CustomerServer) UpdateCustomer(ctx context.Context, request *pb.UpdateCustomerRequest) (*pb.Customer, error) {
+	customerResponse, err := server.CustomerService.Update(ctx, web.CustomerUpdateRequest{
+		CustomerID: request.GetCustomerId(),
+		Name:       request.GetName(),
+		Email:      request.GetEmail(),
+		Phone:      request.GetPhone(),
+		Address:    request.GetAddress(),
+		LoyaltyPts: int(request.GetLoyaltyPoints()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(customerResponse), nil
+}
+
+func (server *CustomerServer) DeleteCustomer(ctx context.Context, request *pb.DeleteCustomerRequest) (*pb.DeleteCustomerResponse, error) {
+	if err := server.CustomerService.Delete(ctx, request.GetCustomerId()); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteCustomerResponse{}, nil
+}
+
+func (server *CustomerServer) GetCustomer(ctx context.Context, request *pb.GetCustomerRequest) (*pb.Customer, error) {
+	customerResponse, err := server.CustomerService.FindById(ctx, request.GetCustomerId())
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(customerResponse), nil
+}
+
+func (server *CustomerServer) ListCustomers(ctx context.Context, request *pb.ListCustomersRequest) (*pb.ListCustomersResponse, error) {
+	customerResponses, err := server.CustomerService.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListCustomersResponse{Customers: make([]*pb.Customer, 0, len(customerResponses))}
+	for _, customerResponse := range customerResponses {
+		response.Customers = append(response.Customers, toCustomer(customerResponse))
+	}
+	return response, nil
+}
+
+func toCustomer(customer web.CustomerResponse) *pb.Customer {
+	return &pb.Customer{
+		CustomerId:    customer.CustomerID,
+		Name:          customer.Name,
+		Email:         customer.Email,
+		Phone:         customer.Phone,
+		Address:       customer.Address,
+		LoyaltyPoints: int32(customer.LoyaltyPts),
+	}
+}
@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// EmployeeServer serves pb.EmployeeService on top of service.EmployeeService, reads in a store
+// context go through service.StoreService, see Store
+type EmployeeServer struct {
+	pb.UnimplementedEmployeeServiceServer
+	EmployeeService service.EmployeeService
+	StoreService    service.StoreService
+}
+
+func NewEmployeeServer(employeeService service.EmployeeService, storeService service.StoreService) *EmployeeServer {
+	return &EmployeeServer{EmployeeService: employeeService, StoreService: storeService}
+}
+
+func (server *EmployeeServer) CreateEmployee(ctx context.Context, request *pb.CreateEmployeeRequest) (*pb.Employee, error) {
+	employeeResponse, err := server.EmployeeService.Create(ctx, web.EmployeeCreateRequest{
+		Name:      request.GetName(),
+		Role:      request.GetRole(),
+		Email:     request.GetEmail(),
+		Phone:     request.GetPhone(),
+		DateHired: request.GetDateHired(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employeeResponse), nil
+}
+
+func (server *EmployeeServer) UpdateEmployee(ctx context.Context, request *pb.UpdateEmployeeRequest) (*pb.Employee, error) {
+	employeeResponse, err := server.EmployeeService.Update(ctx, web.EmployeeUpdateRequest{
+		EmployeeID: request.GetEmployeeId(),
+		Name:       request.GetName(),
+		Role:       request.GetRole(),
+		Email:      request.GetEmail(),
+		Phone:      request.GetPhone(),
+		DateHired:  request.GetDateHired(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employeeResponse), nil
+}
+
+func (server *EmployeeServer) DeleteEmployee(ctx context.Context, request *pb.DeleteEmployeeRequest) (*pb.DeleteEmployeeResponse, error) {
+	if err := server.EmployeeService.Delete(ctx, request.GetEmployeeId()); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteEmployeeResponse{}, nil
+}
+
+func (server *EmployeeServer) GetEmployee(ctx context.Context, request *pb.GetEmployeeRequest) (*pb.Employee, error) {
+	storeId, err := Store(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var employeeResponse web.EmployeeResponse
+	if storeId != "" {
+		employeeResponse, err = server.StoreService.FindEmployee(ctx, storeId, request.GetEmployeeId())
+	} else {
+		employeeResponse, err = server.EmployeeService.FindById(ctx, request.GetEmployeeId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employeeResponse), nil
+}
+
+func (server *EmployeeServer) ListEmployees(ctx context.Context, request *pb.ListEmployeesRequest) (*pb.ListEmployeesResponse, error) {
+	storeId, err := Store(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var employeeResponses []web.EmployeeResponse
+	if storeId != "" {
+		employeeResponses, err = server.StoreService.FindEmployees(ctx, storeId)
+	} else {
+		employeeResponses, err = server.EmployeeService.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListEmployeesResponse{Employees: make([]*pb.Employee, 0, len(employeeResponses))}
+	for _, employeeResponse := range employeeResponses {
+		response.Employees = append(response.Employees, toEmployee(employeeResponse))
+	}
+	return response, nil
+}
+
+func toEmployee(employee web.EmployeeResponse) *pb.Employee {
+	return &pb.Employee{
+		EmployeeId: employee.EmployeeID,
+		Name:       employee.Name,
+		Role:       employee.Role,
+		Email:      employee.Email,
+		Phone:      employee.Phone,
+		DateHired:  employee.DateHired,
+	}
+}
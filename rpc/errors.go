@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps a service error the same way the REST controllers do: not found errors to
+// NotFound, invalid input to InvalidArgument, missing permissions to PermissionDenied and
+// everything else to Internal
+func toStatus(err error) error {
+	var notFoundError exception.NotFoundError
+	var badRequestError exception.BadRequestError
+	var forbiddenError exception.ForbiddenError
+	var validationErrors validator.ValidationErrors
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.As(err, &notFoundError):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &badRequestError), errors.As(err, &validationErrors):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &forbiddenError):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// ErrorInterceptor converts the errors returned by the handlers to gRPC statuses, see toStatus
+func ErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return resp, nil
+}
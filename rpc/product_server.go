@@ -0,0 +1,140 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"github.com/aronipurwanto/go-restful-api/service"
+)
+
+// ProductServer serves pb.ProductService on top of service.ProductService, reads in a store
+// context go through service.StoreService, see Store
+type ProductServer struct {
+	pb.UnimplementedProductServiceServer
+	ProductService service.ProductService
+	StoreService   service.StoreService
+}
+
+func NewProductServer(productService service.ProductService, storeService service.StoreService) *ProductServer {
+	return &ProductServer{ProductService: productService, StoreService: storeService}
+}
+
+func (server *ProductServer) CreateProduct(ctx context.Context, request *pb.CreateProductRequest) (*pb.Product, error) {
+	price, err := priceOf(request.GetPriceAmount(), request.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+	productResponse, err := server.ProductService.Create(ctx, web.ProductCreateRequest{
+		Name:        request.GetName(),
+		Description: request.GetDescription(),
+		Price:       price,
+		StockQty:    int(request.GetStockQty()),
+		Category:    request.GetCategory(),
+		SKU:         request.GetSku(),
+		TaxRate:     request.GetTaxRate(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toProduct(productResponse), nil
+}
+
+func (server *ProductServer) UpdateProduct(ctx context.Context, request *pb.UpdateProductRequest) (*pb.Product, error) {
+	price, err := priceOf(request.GetPriceAmount(), request.GetPrice())
+	if err != nil {
+		return nil, err
+	}
+	productResponse, err := server.ProductService.Update(ctx, web.ProductUpdateRequest{
+		ProductID:   request.GetProductId(),
+		Name:        request.GetName(),
+		Description: request.GetDescription(),
+		Price:       price,
+		StockQty:    int(request.GetStockQty()),
+		Category:    request.GetCategory(),
+		SKU:         request.GetSku(),
+		TaxRate:     request.GetTaxRate(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toProduct(productResponse), nil
+}
+
+func (server *ProductServer) DeleteProduct(ctx context.Context, request *pb.DeleteProductRequest) (*pb.DeleteProductResponse, error) {
+	if err := server.ProductService.Delete(ctx, request.GetProductId()); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteProductResponse{}, nil
+}
+
+func (server *ProductServer) GetProduct(ctx context.Context, request *pb.GetProductRequest) (*pb.Product, error) {
+	storeId, err := Store(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var productResponse web.ProductResponse
+	if storeId != "" {
+		productResponse, err = server.StoreService.FindProduct(ctx, storeId, request.GetProductId())
+	} else {
+		productResponse, err = server.ProductService.FindById(ctx, request.GetProductId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toProduct(productResponse), nil
+}
+
+func (server *ProductServer) ListProducts(ctx context.Context, request *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	storeId, err := Store(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var productResponses []web.ProductResponse
+	if storeId != "" {
+		productResponses, err = server.StoreService.FindProducts(ctx, storeId, nil)
+	} else {
+		productResponses, err = server.ProductService.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListProductsResponse{Products: make([]*pb.Product, 0, len(productResponses))}
+	for _, productResponse := range productResponses {
+		response.Products = append(response.Products, toProduct(productResponse))
+	}
+	return response, nil
+}
+
+func toProduct(product web.ProductResponse) *pb.Product {
+	return &pb.Product{
+		ProductId:   product.ProductID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		StockQty:    int32(product.StockQty),
+		Category:    product.Category,
+		Sku:         product.SKU,
+		TaxRate:     product.TaxRate,
+		PriceAmount: product.PriceAmount.String(),
+		Currency:    product.Currency,
+	}
+}
+
+// priceOf is the price of a request in the default currency: the exact priceAmount when set,
+// the double of the clients written before it otherwise
+func priceOf(priceAmount string, price float64) (money.Money, error) {
+	if priceAmount == "" {
+		return money.FromFloat(price, money.DefaultCurrency), nil
+	}
+	parsed, err := money.Parse(priceAmount, money.DefaultCurrency)
+	if err != nil {
+		return money.Money{}, exception.NewBadRequestError(err.Error())
+	}
+	return parsed, nil
+}
@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"google.golang.org/grpc"
+)
+
+// Server is the gRPC counterpart of the REST API, every call is authenticated and delegated
+// to the service layer
+type Server struct {
+	*grpc.Server
+}
+
+func NewServer(authenticator auth.Authenticator, category *CategoryServer, customer *CustomerServer, employee *EmployeeServer, product *ProductServer) *Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(NewAuthInterceptor(authenticator), ErrorInterceptor))
+	pb.RegisterCategoryServiceServer(server, category)
+	pb.RegisterCustomerServiceServer(server, customer)
+	pb.RegisterEmployeeServiceServer(server, employee)
+	pb.RegisterProductServiceServer(server, product)
+	return &Server{Server: server}
+}
+
+// Listen serves on addr until the server is stopped
+func (server *Server) Listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// ShutdownWithContext stops accepting calls and waits for the pending ones, they are cancelled
+// once ctx expires
+func (server *Server) ShutdownWithContext(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testServices struct {
+	category *mocks.MockCategoryService
+	customer *mocks.MockCustomerService
+	employee *mocks.MockEmployeeService
+	product  *mocks.MockProductService
+	store    *mocks.MockStoreService
+}
+
+// setupServer serves the mocked services over an in-memory connection
+func setupServer(t *testing.T) (*grpc.ClientConn, testServices) {
+	ctrl := gomock.NewController(t)
+	services := testServices{
+		category: mocks.NewMockCategoryService(ctrl),
+		customer: mocks.NewMockCustomerService(ctrl),
+		employee: mocks.NewMockEmployeeService(ctrl),
+		product:  mocks.NewMockProductService(ctrl),
+		store:    mocks.NewMockStoreService(ctrl),
+	}
+	server := NewServer(auth.StaticKeys{
+		"RAHASIA": {Name: "admin", Permissions: []string{"*"}},
+		"KASIR":   {Name: "kasir-jkt", Permissions: []string{auth.StorePermission("JKT01")}},
+	},
+		NewCategoryServer(services.category),
+		NewCustomerServer(services.customer),
+		NewEmployeeServer(services.employee, services.store),
+		NewProductServer(services.product, services.store),
+	)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, services
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, key)
+}
+
+func TestAuthentication(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		mock       func(services testServices)
+		expectCode codes.Code
+	}{
+		{
+			name:       "missing key",
+			ctx:        context.Background(),
+			expectCode: codes.Unauthenticated,
+		},
+		{
+			name:       "unknown key",
+			ctx:        withKey("SALAH"),
+			expectCode: codes.Unauthenticated,
+		},
+		{
+			name: "valid key",
+			ctx:  withKey("RAHASIA"),
+			mock: func(services testServices) {
+				services.category.EXPECT().FindAll(gomock.Any()).Return([]web.CategoryResponse{}, nil)
+			},
+			expectCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, services := setupServer(t)
+			if tt.mock != nil {
+				tt.mock(services)
+			}
+
+			_, err := pb.NewCategoryServiceClient(conn).ListCategories(tt.ctx, &pb.ListCategoriesRequest{})
+			assert.Equal(t, tt.expectCode, status.Code(err))
+		})
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	validationErr := validator.New().Struct(web.ProductCreateRequest{})
+
+	tests := []struct {
+		name       string
+		err        error
+		expectCode codes.Code
+	}{
+		{name: "not found", err: exception.NewNotFoundError("product not found"), expectCode: codes.NotFound},
+		{name: "bad request", err: exception.NewBadRequestError("category does not exist"), expectCode: codes.InvalidArgument},
+		{name: "validation", err: validationErr, expectCode: codes.InvalidArgument},
+		{name: "forbidden", err: exception.NewForbiddenError("missing permission"), expectCode: codes.PermissionDenied},
+		{name: "internal", err: errors.New("database is down"), expectCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, services := setupServer(t)
+			services.product.EXPECT().FindById(gomock.Any(), "P001").Return(web.ProductResponse{}, tt.err)
+
+			_, err := pb.NewProductServiceClient(conn).GetProduct(withKey("RAHASIA"), &pb.GetProductRequest{ProductId: "P001"})
+			assert.Equal(t, tt.expectCode, status.Code(err))
+			assert.Equal(t, tt.err.Error(), status.Convert(err).Message())
+		})
+	}
+}
+
+func TestProductServer(t *testing.T) {
+	conn, services := setupServer(t)
+	client := pb.NewProductServiceClient(conn)
+	ctx := withKey("RAHASIA")
+
+	services.product.EXPECT().Create(gomock.Any(), web.ProductCreateRequest{
+		Name: "Kopi", Price: money.IDR(25000), StockQty: 10, Category: "Food", SKU: "KP-01", TaxRate: 11,
+	}).Return(web.ProductResponse{
+		ProductID: "P001", Name: "Kopi", Price: 25000, PriceAmount: money.IDR(25000), Currency: "IDR", StockQty: 10, Category: "Food", SKU: "KP-01", TaxRate: 11,
+	}, nil)
+	created, err := client.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Kopi", Price: 25000, StockQty: 10, Category: "Food", Sku: "KP-01", TaxRate: 11,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "P001", created.GetProductId())
+	assert.Equal(t, int32(10), created.GetStockQty())
+	assert.Equal(t, "KP-01", created.GetSku())
+
+	services.product.EXPECT().FindAll(gomock.Any()).Return([]web.ProductResponse{
+		{ProductID: "P001", Name: "Kopi"}, {ProductID: "P002", Name: "Teh"},
+	}, nil)
+	list, err := client.ListProducts(ctx, &pb.ListProductsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetProducts(), 2)
+	assert.Equal(t, "Teh", list.GetProducts()[1].GetName())
+
+	services.product.EXPECT().Delete(gomock.Any(), "P001").Return(nil)
+	_, err = client.DeleteProduct(ctx, &pb.DeleteProductRequest{ProductId: "P001"})
+	assert.NoError(t, err)
+}
+
+func TestStoreContext(t *testing.T) {
+	conn, services := setupServer(t)
+	products := pb.NewProductServiceClient(conn)
+	employees := pb.NewEmployeeServiceClient(conn)
+
+	// A key bound to a single store reads in that store without asking for it
+	services.store.EXPECT().FindProducts(gomock.Any(), "JKT01", nil).Return([]web.ProductResponse{{ProductID: "P001", StockQty: 3}}, nil)
+	list, err := products.ListProducts(withKey("KASIR"), &pb.ListProductsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetProducts(), 1)
+	assert.Equal(t, int32(3), list.GetProducts()[0].GetStockQty())
+
+	services.store.EXPECT().FindEmployee(gomock.Any(), "JKT01", "E002").Return(web.EmployeeResponse{}, exception.NewNotFoundError("Employee not found"))
+	_, err = employees.GetEmployee(withKey("KASIR"), &pb.GetEmployeeRequest{EmployeeId: "E002"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(withKey("KASIR"), StoreMetadata, "BDG01")
+	_, err = employees.ListEmployees(ctx, &pb.ListEmployeesRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(withKey("RAHASIA"), StoreMetadata, "BDG01")
+	services.store.EXPECT().FindEmployees(gomock.Any(), "BDG01").Return([]web.EmployeeResponse{{EmployeeID: "E002"}}, nil)
+	employeeList, err := employees.ListEmployees(ctx, &pb.ListEmployeesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "E002", employeeList.GetEmployees()[0].GetEmployeeId())
+}
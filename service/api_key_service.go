@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+// APIKeyService manages the keys stored in the database, it also authenticates them
+type APIKeyService interface {
+	auth.Authenticator
+	Create(ctx context.Context, request web.APIKeyCreateRequest) (web.APIKeyResponse, error)
+	Revoke(ctx context.Context, keyId string) (web.APIKeyResponse, error)
+	FindAll(ctx context.Context) ([]web.APIKeyResponse, error)
+}
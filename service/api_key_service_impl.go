@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefixSize is the number of leading characters kept in clear to recognise a key
+const apiKeyPrefixSize = 12
+
+type APIKeyServiceImpl struct {
+	APIKeyRepository repository.APIKeyRepository
+	Validate         *validator.Validate
+}
+
+func NewAPIKeyService(apiKeyRepository repository.APIKeyRepository, validate *validator.Validate) APIKeyService {
+	return &APIKeyServiceImpl{APIKeyRepository: apiKeyRepository, Validate: validate}
+}
+
+// Create API key, the key is only part of this response
+func (service *APIKeyServiceImpl) Create(ctx context.Context, request web.APIKeyCreateRequest) (web.APIKeyResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.APIKeyResponse{}, err
+	}
+
+	key := newAPIKey()
+	apiKey, err := service.APIKeyRepository.Save(ctx, domain.APIKey{
+		Name:        request.Name,
+		Prefix:      key[:apiKeyPrefixSize],
+		Hash:        hashAPIKey(key),
+		Permissions: request.Permissions,
+	})
+	if err != nil {
+		return web.APIKeyResponse{}, err
+	}
+
+	response := helper.ToAPIKeyResponse(apiKey)
+	response.Key = key
+	return response, nil
+}
+
+// Revoke API key, revoking a key twice keeps the first revocation time
+func (service *APIKeyServiceImpl) Revoke(ctx context.Context, keyId string) (web.APIKeyResponse, error) {
+	apiKey, err := service.APIKeyRepository.FindById(ctx, keyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.APIKeyResponse{}, exception.NewNotFoundError("API key not found")
+	} else if err != nil {
+		return web.APIKeyResponse{}, err
+	}
+	if apiKey.RevokedAt != nil {
+		return helper.ToAPIKeyResponse(apiKey), nil
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	revokedKey, err := service.APIKeyRepository.Update(ctx, apiKey)
+	if err != nil {
+		return web.APIKeyResponse{}, err
+	}
+	return helper.ToAPIKeyResponse(revokedKey), nil
+}
+
+// Find All API keys
+func (service *APIKeyServiceImpl) FindAll(ctx context.Context) ([]web.APIKeyResponse, error) {
+	apiKeys, err := service.APIKeyRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToAPIKeyResponses(apiKeys), nil
+}
+
+// Authenticate resolves a key that was created and not revoked since
+func (service *APIKeyServiceImpl) Authenticate(ctx context.Context, key string) (auth.Principal, bool, error) {
+	if key == "" {
+		return auth.Principal{}, false, nil
+	}
+
+	apiKey, err := service.APIKeyRepository.FindByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return auth.Principal{}, false, nil
+	} else if err != nil {
+		return auth.Principal{}, false, err
+	}
+	if apiKey.RevokedAt != nil {
+		return auth.Principal{}, false, nil
+	}
+	return auth.Principal{Name: apiKey.Name, Permissions: apiKey.Permissions}, true, nil
+}
+
+func newAPIKey() string {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return "pos_" + hex.EncodeToString(key)
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func hashOf(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	apiKeyService := service.NewAPIKeyService(mockRepo, validator.New())
+
+	var saved domain.APIKey
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+		saved = apiKey
+		apiKey.KeyID = "K1"
+		return apiKey, nil
+	})
+
+	response, err := apiKeyService.Create(context.Background(), web.APIKeyCreateRequest{Name: "ops", Permissions: []string{"*"}})
+	require.NoError(t, err)
+	assert.Equal(t, "K1", response.KeyID)
+	assert.True(t, strings.HasPrefix(response.Key, "pos_"))
+	assert.Equal(t, response.Key[:12], saved.Prefix)
+	assert.Equal(t, hashOf(response.Key), saved.Hash)
+
+	_, err = apiKeyService.Create(context.Background(), web.APIKeyCreateRequest{Name: "ops"})
+	assert.Error(t, err)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	revokedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		mock      func(mockRepo *mocks.MockAPIKeyRepository)
+		expectErr error
+		expectAt  *time.Time
+	}{
+		{
+			name: "success",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), "K1").Return(domain.APIKey{KeyID: "K1"}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, apiKey domain.APIKey) (domain.APIKey, error) {
+					return apiKey, nil
+				})
+			},
+		},
+		{
+			name: "already revoked",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), "K1").Return(domain.APIKey{KeyID: "K1", RevokedAt: &revokedAt}, nil)
+			},
+			expectAt: &revokedAt,
+		},
+		{
+			name: "not found",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), "K1").Return(domain.APIKey{}, fmt.Errorf("API key is not found: %w", gorm.ErrRecordNotFound))
+			},
+			expectErr: exception.NewNotFoundError("API key not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.mock(mockRepo)
+
+			response, err := service.NewAPIKeyService(mockRepo, validator.New()).Revoke(context.Background(), "K1")
+			if tt.expectErr != nil {
+				assert.Equal(t, tt.expectErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, response.RevokedAt)
+			if tt.expectAt != nil {
+				assert.Equal(t, *tt.expectAt, *response.RevokedAt)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		key       string
+		mock      func(mockRepo *mocks.MockAPIKeyRepository)
+		expect    auth.Principal
+		expectOk  bool
+		expectErr bool
+	}{
+		{
+			name: "active key",
+			key:  "pos_abc",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindByHash(gomock.Any(), hashOf("pos_abc")).Return(domain.APIKey{Name: "ops", Permissions: []string{"*"}}, nil)
+			},
+			expect:   auth.Principal{Name: "ops", Permissions: []string{"*"}},
+			expectOk: true,
+		},
+		{
+			name: "revoked key",
+			key:  "pos_abc",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindByHash(gomock.Any(), hashOf("pos_abc")).Return(domain.APIKey{Name: "ops", RevokedAt: &revokedAt}, nil)
+			},
+		},
+		{
+			name: "unknown key",
+			key:  "pos_abc",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).Return(domain.APIKey{}, fmt.Errorf("API key is not found: %w", gorm.ErrRecordNotFound))
+			},
+		},
+		{
+			name: "empty key skips the database",
+			key:  "",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {},
+		},
+		{
+			name: "database error",
+			key:  "pos_abc",
+			mock: func(mockRepo *mocks.MockAPIKeyRepository) {
+				mockRepo.EXPECT().FindByHash(gomock.Any(), gomock.Any()).Return(domain.APIKey{}, errors.New("connection refused"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.mock(mockRepo)
+
+			principal, ok, err := service.NewAPIKeyService(mockRepo, validator.New()).Authenticate(context.Background(), tt.key)
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expectOk, ok)
+			assert.Equal(t, tt.expect, principal)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+)
+
+type CashSessionService interface {
+	Open(ctx context.Context, principal auth.Principal, request web.CashSessionOpenRequest) (web.CashSessionResponse, error)
+	RecordMovement(ctx context.Context, principal auth.Principal, request web.CashMovementRequest) (web.CashSessionResponse, error)
+	Close(ctx context.Context, principal auth.Principal, request web.CashSessionCloseRequest) (web.CashSessionResponse, error)
+	FindById(ctx context.Context, principal auth.Principal, sessionId string) (web.CashSessionResponse, error)
+	FindAll(ctx context.Context, storeId string, status string) ([]web.CashSessionResponse, error)
+	Report(ctx context.Context, principal auth.Principal, sessionId string) (web.CashReportResponse, error)
+	PrintReport(ctx context.Context, principal auth.Principal, sessionId string) (receipt.Report, error)
+	StoreReport(ctx context.Context, principal auth.Principal, storeId string, date string) (web.CashReportResponse, error)
+	PrintStoreReport(ctx context.Context, principal auth.Principal, storeId string, date string) (receipt.Report, error)
+}
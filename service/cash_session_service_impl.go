@@ -0,0 +1,703 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Kinds of a cash report: an X report is read while the drawers are still open, a Z report once
+// they are all closed
+const (
+	ReportX = "X"
+	ReportZ = "Z"
+)
+
+// reportMethods are the payment methods in the order they are reported, cash is always reported
+var reportMethods = []string{domain.TenderCash, domain.TenderCard, domain.TenderPoints}
+
+// methodLabels are the names of the payment methods on a printed report
+var methodLabels = map[string]string{
+	domain.TenderCash:   "Tunai",
+	domain.TenderCard:   "Kartu",
+	domain.TenderPoints: "Poin",
+}
+
+type CashSessionServiceImpl struct {
+	CashSessionRepository repository.CashSessionRepository
+	OrderRepository       repository.OrderRepository
+	PaymentRepository     repository.PaymentRepository
+	ReturnRepository      repository.ReturnRepository
+	EmployeeRepository    repository.EmployeeRepository
+	StoreRepository       repository.StoreRepository
+	Transactor            repository.Transactor
+	Events                event.Publisher
+	Validate              *validator.Validate
+}
+
+func NewCashSessionService(cashSessionRepository repository.CashSessionRepository, orderRepository repository.OrderRepository, paymentRepository repository.PaymentRepository, returnRepository repository.ReturnRepository, employeeRepository repository.EmployeeRepository, storeRepository repository.StoreRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) CashSessionService {
+	return &CashSessionServiceImpl{
+		CashSessionRepository: cashSessionRepository,
+		OrderRepository:       orderRepository,
+		PaymentRepository:     paymentRepository,
+		ReturnRepository:      returnRepository,
+		EmployeeRepository:    employeeRepository,
+		StoreRepository:       storeRepository,
+		Transactor:            transactor,
+		Events:                events,
+		Validate:              validate,
+	}
+}
+
+// Open a session at the cash drawer of the store context for the employee bound to the key, with
+// the float put in the drawer. An employee has one session open at a time in a store.
+func (service *CashSessionServiceImpl) Open(ctx context.Context, principal auth.Principal, request web.CashSessionOpenRequest) (web.CashSessionResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	if request.StoreID == "" {
+		return web.CashSessionResponse{}, exception.NewBadRequestError("a cash session is opened in a store, a store context is required")
+	}
+	if err := requireStore(principal, request.StoreID); err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	if request.OpeningFloat.IsNegative() {
+		return web.CashSessionResponse{}, exception.NewBadRequestError("the opening float must not be negative")
+	}
+	if _, err := service.findStore(ctx, request.StoreID); err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	employee, err := service.boundEmployee(ctx, principal, request.EmployeeID)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	open, err := service.CashSessionRepository.FindOpen(ctx, request.StoreID, employee.EmployeeID)
+	if err == nil {
+		return web.CashSessionResponse{}, exception.NewBadRequestError(fmt.Sprintf("employee %s already has cash session %s open in store %s, close it first", employee.EmployeeID, open.SessionID, request.StoreID))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.CashSessionResponse{}, err
+	}
+
+	session := domain.CashSession{
+		StoreID:      request.StoreID,
+		EmployeeID:   employee.EmployeeID,
+		Status:       domain.CashSessionOpen,
+		OpeningFloat: request.OpeningFloat,
+		Counts:       []domain.CashCount{},
+		OpenedAt:     time.Now(),
+	}
+	var response web.CashSessionResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedSession, ok, err := service.CashSessionRepository.Save(ctx, session)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exception.NewBadRequestError(fmt.Sprintf("employee %s already has a cash session open in store %s, close it first", employee.EmployeeID, request.StoreID))
+		}
+		response = helper.ToCashSessionResponse(savedSession)
+		return service.Events.Publish(ctx, event.CashSessionOpened, response)
+	})
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	return response, nil
+}
+
+// RecordMovement puts cash in or takes cash out of the drawer of an open session, by the employee
+// bound to the key. More than the cash expected in the drawer cannot be taken out.
+func (service *CashSessionServiceImpl) RecordMovement(ctx context.Context, principal auth.Principal, request web.CashMovementRequest) (web.CashSessionResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	if !request.Amount.IsPositive() {
+		return web.CashSessionResponse{}, exception.NewBadRequestError("the amount of a cash movement must be positive")
+	}
+	session, err := service.findOpenSession(ctx, principal, request.SessionID)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	if request.Amount.Currency() != session.OpeningFloat.Currency() {
+		return web.CashSessionResponse{}, exception.NewBadRequestError(fmt.Sprintf("the drawer holds %s, not %s", session.OpeningFloat.Currency(), request.Amount.Currency()))
+	}
+	employee, err := service.boundEmployee(ctx, principal, request.EmployeeID)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	now := time.Now()
+	if request.Type == domain.CashOut {
+		report, err := service.summarize(ctx, session.StoreID, session.EmployeeID, session.OpenedAt, now)
+		if err != nil {
+			return web.CashSessionResponse{}, err
+		}
+		if expected := drawer(session, report).Expected; request.Amount.Cmp(expected) > 0 {
+			return web.CashSessionResponse{}, exception.NewBadRequestError(fmt.Sprintf("%s is taken out of the drawer, only %s is expected in it", request.Amount, expected))
+		}
+	}
+
+	movement := domain.CashMovement{
+		SessionID:  session.SessionID,
+		Position:   len(session.Movements) + 1,
+		Type:       request.Type,
+		Amount:     request.Amount,
+		Reason:     request.Reason,
+		EmployeeID: employee.EmployeeID,
+		CreatedAt:  now,
+	}
+	var response web.CashSessionResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the new version keeps two movements from taking the same position
+		updated, err := service.update(ctx, session)
+		if err != nil {
+			return err
+		}
+		if err := service.CashSessionRepository.SaveMovement(ctx, movement); err != nil {
+			return err
+		}
+		updated.Movements = append(session.Movements, movement)
+		response = helper.ToCashSessionResponse(updated)
+		return service.Events.Publish(ctx, event.CashSessionCashMoved, response)
+	})
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	return response, nil
+}
+
+// Close a session with the notes and coins counted in the drawer by the employee bound to the
+// key. The cash expected is recorded with the variance, what was counted minus what was expected.
+func (service *CashSessionServiceImpl) Close(ctx context.Context, principal auth.Principal, request web.CashSessionCloseRequest) (web.CashSessionResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	session, err := service.findOpenSession(ctx, principal, request.SessionID)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	employee, err := service.boundEmployee(ctx, principal, request.EmployeeID)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	counts, counted, err := toCashCounts(session.OpeningFloat.Currency(), request.Counts)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	now := time.Now()
+	report, err := service.summarize(ctx, session.StoreID, session.EmployeeID, session.OpenedAt, now)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+
+	session.Expected = drawer(session, report).Expected
+	session.Counted = counted
+	session.Variance = counted.Sub(session.Expected)
+	session.Counts = counts
+	session.Status = domain.CashSessionClosed
+	session.ClosedBy = employee.EmployeeID
+	session.Note = request.Note
+	session.ClosedAt = &now
+	var response web.CashSessionResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err := service.update(ctx, session)
+		if err != nil {
+			return err
+		}
+		response = helper.ToCashSessionResponse(updated)
+		return service.Events.Publish(ctx, event.CashSessionClosed, response)
+	})
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	return response, nil
+}
+
+// FindById returns a session of a store the principal is granted
+func (service *CashSessionServiceImpl) FindById(ctx context.Context, principal auth.Principal, sessionId string) (web.CashSessionResponse, error) {
+	session, err := service.findSession(ctx, principal, sessionId)
+	if err != nil {
+		return web.CashSessionResponse{}, err
+	}
+	return helper.ToCashSessionResponse(session), nil
+}
+
+// FindAll lists the sessions of a store, open, closed or both, newest first
+func (service *CashSessionServiceImpl) FindAll(ctx context.Context, storeId string, status string) ([]web.CashSessionResponse, error) {
+	var statuses []string
+	switch status {
+	case "":
+	case domain.CashSessionOpen, domain.CashSessionClosed:
+		statuses = []string{status}
+	default:
+		return nil, exception.NewBadRequestError(fmt.Sprintf("unknown cash session status %q", status))
+	}
+
+	sessions, err := service.CashSessionRepository.FindAll(ctx, storeId, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToCashSessionResponses(sessions), nil
+}
+
+// Report of a session: the X report of the sales and the cash so far while it is open, its Z
+// report once it is closed
+func (service *CashSessionServiceImpl) Report(ctx context.Context, principal auth.Principal, sessionId string) (web.CashReportResponse, error) {
+	session, err := service.findSession(ctx, principal, sessionId)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+	return service.sessionReport(ctx, session)
+}
+
+// PrintReport is the report of a session laid out for a printer
+func (service *CashSessionServiceImpl) PrintReport(ctx context.Context, principal auth.Principal, sessionId string) (receipt.Report, error) {
+	report, err := service.Report(ctx, principal, sessionId)
+	if err != nil {
+		return receipt.Report{}, err
+	}
+	return service.print(ctx, report)
+}
+
+// StoreReport is the report of a store on a day, 2006-01-02 in the time zone of the server and
+// today when empty. The sales and the payments are those of every order of the day, the cash is
+// that of the sessions opened on the day. It is a Z report once they are all closed.
+func (service *CashSessionServiceImpl) StoreReport(ctx context.Context, principal auth.Principal, storeId string, date string) (web.CashReportResponse, error) {
+	if storeId == "" {
+		return web.CashReportResponse{}, exception.NewBadRequestError("a cash report covers a store, a store context is required")
+	}
+	if err := requireStore(principal, storeId); err != nil {
+		return web.CashReportResponse{}, err
+	}
+	from, err := reportDay(date)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+	if _, err := service.findStore(ctx, storeId); err != nil {
+		return web.CashReportResponse{}, err
+	}
+	to := from.AddDate(0, 0, 1)
+	report, err := service.summarize(ctx, storeId, "", from, to)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+	sessions, err := service.CashSessionRepository.FindOpened(ctx, storeId, from, to)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+
+	report.Kind = ReportX
+	if len(sessions) > 0 {
+		report.Kind = ReportZ
+	}
+	zero := money.Money{}
+	cash := web.CashReportCashResponse{OpeningFloat: zero, Sales: zero, Refunds: zero, CashIn: zero, CashOut: zero, Expected: zero}
+	counted := zero
+	report.Sessions = []web.CashSessionResponse{}
+	for _, session := range sessions {
+		sessionReport, err := service.sessionReport(ctx, session)
+		if err != nil {
+			return web.CashReportResponse{}, err
+		}
+		if session.Status == domain.CashSessionOpen {
+			report.Kind = ReportX
+		}
+		cash.OpeningFloat = cash.OpeningFloat.Add(sessionReport.Cash.OpeningFloat)
+		cash.Sales = cash.Sales.Add(sessionReport.Cash.Sales)
+		cash.Refunds = cash.Refunds.Add(sessionReport.Cash.Refunds)
+		cash.CashIn = cash.CashIn.Add(sessionReport.Cash.CashIn)
+		cash.CashOut = cash.CashOut.Add(sessionReport.Cash.CashOut)
+		cash.Expected = cash.Expected.Add(sessionReport.Cash.Expected)
+		counted = counted.Add(session.Counted)
+		report.Sessions = append(report.Sessions, helper.ToCashSessionResponse(session))
+	}
+	if report.Kind == ReportZ {
+		variance := counted.Sub(cash.Expected)
+		cash.Counted = &counted
+		cash.Variance = &variance
+	}
+	report.Cash = cash
+	report.GeneratedAt = time.Now()
+	return report, nil
+}
+
+// PrintStoreReport is the report of a store on a day laid out for a printer
+func (service *CashSessionServiceImpl) PrintStoreReport(ctx context.Context, principal auth.Principal, storeId string, date string) (receipt.Report, error) {
+	report, err := service.StoreReport(ctx, principal, storeId, date)
+	if err != nil {
+		return receipt.Report{}, err
+	}
+	return service.print(ctx, report)
+}
+
+func (service *CashSessionServiceImpl) sessionReport(ctx context.Context, session domain.CashSession) (web.CashReportResponse, error) {
+	kind, to := ReportX, time.Now()
+	if session.ClosedAt != nil {
+		kind, to = ReportZ, *session.ClosedAt
+	}
+	report, err := service.summarize(ctx, session.StoreID, session.EmployeeID, session.OpenedAt, to)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+	report.Kind = kind
+	report.SessionID = session.SessionID
+	report.Cash = drawer(session, report)
+	report.GeneratedAt = time.Now()
+	return report, nil
+}
+
+// summarize adds up the orders recorded in a store from from until before to, by an employee or
+// by every employee when employeeId is empty, and the returns completed in that time. An order
+// without any payment was paid outside of the payments API and is counted as cash, as its
+// returns are refunded in cash. An order whose payment is pending or failed has not brought in
+// anything yet.
+func (service *CashSessionServiceImpl) summarize(ctx context.Context, storeId string, employeeId string, from time.Time, to time.Time) (web.CashReportResponse, error) {
+	orders, err := service.OrderRepository.FindBetween(ctx, storeId, employeeId, from, to)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		orderIds = append(orderIds, order.OrderID)
+	}
+	payments, err := service.PaymentRepository.FindByOrders(ctx, orderIds)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+	paid := map[string]domain.Payment{}
+	withPayment := map[string]bool{}
+	for _, payment := range payments {
+		withPayment[payment.OrderID] = true
+		if payment.Status == domain.PaymentPaid {
+			paid[payment.OrderID] = payment
+		}
+	}
+	orderReturns, err := service.ReturnRepository.FindCompleted(ctx, storeId, employeeId, from, to)
+	if err != nil {
+		return web.CashReportResponse{}, err
+	}
+
+	zero := money.Money{}
+	report := web.CashReportResponse{
+		StoreID:     storeId,
+		EmployeeID:  employeeId,
+		From:        from,
+		To:          to,
+		Orders:      len(orders),
+		Subtotal:    zero,
+		Discount:    zero,
+		Tax:         zero,
+		Sales:       zero,
+		Returns:     len(orderReturns),
+		Refunds:     zero,
+		RefundedTax: zero,
+		Taxes:       []web.CashReportTaxResponse{},
+	}
+	methods := map[string]*web.CashReportPaymentResponse{}
+	method := func(name string) *web.CashReportPaymentResponse {
+		if methods[name] == nil {
+			methods[name] = &web.CashReportPaymentResponse{Method: name, Sales: zero, Refunds: zero}
+		}
+		return methods[name]
+	}
+	method(domain.TenderCash)
+	taxes := map[string]int{}
+	for _, order := range orders {
+		report.Subtotal = report.Subtotal.Add(order.Subtotal)
+		report.Discount = report.Discount.Add(order.Discount)
+		report.Tax = report.Tax.Add(order.Tax)
+		report.Sales = report.Sales.Add(order.Total)
+		for _, amount := range order.Taxes {
+			i, ok := taxes[amount.Code]
+			if !ok {
+				i = len(report.Taxes)
+				taxes[amount.Code] = i
+				report.Taxes = append(report.Taxes, web.CashReportTaxResponse{Code: amount.Code, Name: amount.Name, Percent: amount.Percent, Base: zero, Tax: zero})
+			}
+			report.Taxes[i].Base = report.Taxes[i].Base.Add(amount.Base)
+			report.Taxes[i].Tax = report.Taxes[i].Tax.Add(amount.Tax)
+		}
+		payment, ok := paid[order.OrderID]
+		if !ok {
+			if !withPayment[order.OrderID] {
+				method(domain.TenderCash).Sales = method(domain.TenderCash).Sales.Add(order.Total)
+			}
+			continue
+		}
+		for _, tender := range payment.Tenders {
+			method(tender.Method).Sales = method(tender.Method).Sales.Add(tender.Amount)
+		}
+	}
+	for _, orderReturn := range orderReturns {
+		report.Refunds = report.Refunds.Add(orderReturn.Refund)
+		report.RefundedTax = report.RefundedTax.Add(orderReturn.Tax)
+		for _, refund := range orderReturn.Refunds {
+			method(refund.Method).Refunds = method(refund.Method).Refunds.Add(refund.Amount)
+		}
+	}
+	report.NetSales = report.Sales.Sub(report.Refunds)
+	report.NetTax = report.Tax.Sub(report.RefundedTax)
+
+	for _, name := range reportMethods {
+		if payment, ok := methods[name]; ok {
+			payment.Net = payment.Sales.Sub(payment.Refunds)
+			report.Payments = append(report.Payments, *payment)
+		}
+	}
+	return report, nil
+}
+
+// drawer reconciles the cash of a session with the cash sales and refunds of its report. The
+// cash counted of a closed session is that recorded when it was closed.
+func drawer(session domain.CashSession, report web.CashReportResponse) web.CashReportCashResponse {
+	zero := money.New(0, session.OpeningFloat.Currency())
+	cash := web.CashReportCashResponse{OpeningFloat: session.OpeningFloat, Sales: zero, Refunds: zero, CashIn: zero, CashOut: zero}
+	for _, payment := range report.Payments {
+		if payment.Method == domain.TenderCash {
+			cash.Sales, cash.Refunds = payment.Sales, payment.Refunds
+		}
+	}
+	for _, movement := range session.Movements {
+		switch movement.Type {
+		case domain.CashIn:
+			cash.CashIn = cash.CashIn.Add(movement.Amount)
+		case domain.CashOut:
+			cash.CashOut = cash.CashOut.Add(movement.Amount)
+		}
+	}
+	cash.Expected = cash.OpeningFloat.Add(cash.Sales).Sub(cash.Refunds).Add(cash.CashIn).Sub(cash.CashOut)
+	if session.Status == domain.CashSessionClosed {
+		expected, counted, variance := session.Expected, session.Counted, session.Variance
+		cash.Expected, cash.Counted, cash.Variance = expected, &counted, &variance
+	}
+	return cash
+}
+
+// print lays a report out with the header of the store, the names of the employees and the
+// labels in Indonesian
+func (service *CashSessionServiceImpl) print(ctx context.Context, report web.CashReportResponse) (receipt.Report, error) {
+	store, err := service.findStore(ctx, report.StoreID)
+	if err != nil {
+		return receipt.Report{}, err
+	}
+	printed := receipt.Report{Title: "LAPORAN " + report.Kind}
+	for _, line := range []string{store.Name, store.Address, store.Phone} {
+		if line != "" {
+			printed.Header = append(printed.Header, line)
+		}
+	}
+
+	const dateTime = "02/01/2006 15:04"
+	var info receipt.Section
+	if report.SessionID != "" {
+		name, err := service.employeeName(ctx, report.EmployeeID)
+		if err != nil {
+			return receipt.Report{}, err
+		}
+		until := "Sampai"
+		if report.Kind == ReportZ {
+			until = "Tutup"
+		}
+		info.Entries = []receipt.Entry{
+			{Label: "Sesi", Value: orderNumber(report.SessionID)},
+			{Label: "Kasir", Value: name},
+			{Label: "Buka", Value: report.From.Format(dateTime)},
+			{Label: until, Value: report.To.Format(dateTime)},
+		}
+	} else {
+		printed.Title += " HARIAN"
+		info.Entries = []receipt.Entry{
+			{Label: "Tanggal", Value: report.From.Format("02/01/2006")},
+			{Label: "Sesi kas", Value: strconv.Itoa(len(report.Sessions))},
+		}
+	}
+	info.Entries = append(info.Entries, receipt.Entry{Label: "Dicetak", Value: report.GeneratedAt.Format(dateTime)})
+	printed.Sections = append(printed.Sections, info, receipt.Section{Title: "PENJUALAN", Entries: []receipt.Entry{
+		{Label: "Transaksi", Value: strconv.Itoa(report.Orders)},
+		{Label: "Subtotal", Value: receipt.Amount(report.Subtotal)},
+		{Label: "Diskon", Value: negative(report.Discount)},
+		{Label: "Pajak", Value: receipt.Amount(report.Tax)},
+		{Label: "Penjualan", Value: receipt.Amount(report.Sales)},
+		{Label: "Retur", Value: strconv.Itoa(report.Returns)},
+		{Label: "Refund", Value: negative(report.Refunds)},
+		{Label: "Penjualan bersih", Value: receipt.Amount(report.NetSales)},
+	}})
+
+	taxes := receipt.Section{Title: "PAJAK"}
+	for _, amount := range report.Taxes {
+		taxes.Entries = append(taxes.Entries,
+			receipt.Entry{Label: "DPP " + amount.Name, Value: receipt.Amount(amount.Base)},
+			receipt.Entry{Label: amount.Name, Value: receipt.Amount(amount.Tax)},
+		)
+	}
+	taxes.Entries = append(taxes.Entries,
+		receipt.Entry{Label: "Pajak refund", Value: negative(report.RefundedTax)},
+		receipt.Entry{Label: "Pajak bersih", Value: receipt.Amount(report.NetTax)},
+	)
+	payments := receipt.Section{Title: "PEMBAYARAN"}
+	for _, payment := range report.Payments {
+		payments.Entries = append(payments.Entries, receipt.Entry{Label: methodLabels[payment.Method], Value: receipt.Amount(payment.Sales)})
+		if !payment.Refunds.IsZero() {
+			payments.Entries = append(payments.Entries, receipt.Entry{Label: "  Refund", Value: negative(payment.Refunds)})
+		}
+	}
+	cash := receipt.Section{Title: "KAS", Entries: []receipt.Entry{
+		{Label: "Modal awal", Value: receipt.Amount(report.Cash.OpeningFloat)},
+		{Label: "Penjualan tunai", Value: receipt.Amount(report.Cash.Sales)},
+		{Label: "Refund tunai", Value: negative(report.Cash.Refunds)},
+		{Label: "Kas masuk", Value: receipt.Amount(report.Cash.CashIn)},
+		{Label: "Kas keluar", Value: negative(report.Cash.CashOut)},
+		{Label: "Diharapkan", Value: receipt.Amount(report.Cash.Expected)},
+	}}
+	if report.Cash.Counted != nil {
+		cash.Entries = append(cash.Entries,
+			receipt.Entry{Label: "Dihitung", Value: receipt.Amount(*report.Cash.Counted)},
+			receipt.Entry{Label: "Selisih", Value: receipt.Amount(*report.Cash.Variance)},
+		)
+	}
+	printed.Sections = append(printed.Sections, taxes, payments, cash)
+
+	if report.SessionID == "" && len(report.Sessions) > 0 {
+		sessions := receipt.Section{Title: "SESI KAS"}
+		for _, session := range report.Sessions {
+			name, err := service.employeeName(ctx, session.EmployeeID)
+			if err != nil {
+				return receipt.Report{}, err
+			}
+			variance := "buka"
+			if session.Variance != nil {
+				variance = receipt.Amount(*session.Variance)
+			}
+			sessions.Entries = append(sessions.Entries, receipt.Entry{Label: orderNumber(session.SessionID) + " " + name, Value: variance})
+		}
+		printed.Sections = append(printed.Sections, sessions)
+	}
+	return printed, nil
+}
+
+// negative writes an amount taken off, e.g. -38.850
+func negative(amount money.Money) string {
+	if amount.IsZero() {
+		return receipt.Amount(amount)
+	}
+	return "-" + receipt.Amount(amount)
+}
+
+func (service *CashSessionServiceImpl) update(ctx context.Context, session domain.CashSession) (domain.CashSession, error) {
+	updated, ok, err := service.CashSessionRepository.Update(ctx, session)
+	if err != nil {
+		return domain.CashSession{}, err
+	}
+	if !ok {
+		return domain.CashSession{}, exception.NewBadRequestError(fmt.Sprintf("cash session %s was changed by another request, reload it and retry", session.SessionID))
+	}
+	return updated, nil
+}
+
+func (service *CashSessionServiceImpl) findSession(ctx context.Context, principal auth.Principal, sessionId string) (domain.CashSession, error) {
+	session, err := service.CashSessionRepository.FindById(ctx, sessionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.CashSession{}, exception.NewNotFoundError("Cash session not found")
+	} else if err != nil {
+		return domain.CashSession{}, err
+	}
+	if err := requireStore(principal, session.StoreID); err != nil {
+		return domain.CashSession{}, err
+	}
+	return session, nil
+}
+
+func (service *CashSessionServiceImpl) findOpenSession(ctx context.Context, principal auth.Principal, sessionId string) (domain.CashSession, error) {
+	session, err := service.findSession(ctx, principal, sessionId)
+	if err != nil {
+		return domain.CashSession{}, err
+	}
+	if session.Status != domain.CashSessionOpen {
+		return domain.CashSession{}, exception.NewBadRequestError(fmt.Sprintf("cash session %s is closed", session.SessionID))
+	}
+	return session, nil
+}
+
+func (service *CashSessionServiceImpl) findStore(ctx context.Context, storeId string) (domain.Store, error) {
+	store, err := service.StoreRepository.FindById(ctx, storeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Store{}, exception.NewNotFoundError("Store not found")
+	}
+	return store, err
+}
+
+// boundEmployee is the employee bound to the key of the principal, requested or the only one
+func (service *CashSessionServiceImpl) boundEmployee(ctx context.Context, principal auth.Principal, requested string) (domain.Employee, error) {
+	employeeId, err := requireEmployee(principal, requested)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	return service.findEmployee(ctx, employeeId)
+}
+
+func (service *CashSessionServiceImpl) findEmployee(ctx context.Context, employeeId string) (domain.Employee, error) {
+	employee, err := service.EmployeeRepository.FindById(ctx, employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Employee{}, exception.NewBadRequestError(fmt.Sprintf("unknown employee %s", employeeId))
+	}
+	return employee, err
+}
+
+// employeeName is the name of an employee today, or the ID of an employee deleted since
+func (service *CashSessionServiceImpl) employeeName(ctx context.Context, employeeId string) (string, error) {
+	employee, err := service.EmployeeRepository.FindById(ctx, employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return employeeId, nil
+	} else if err != nil {
+		return "", err
+	}
+	return employee.Name, nil
+}
+
+// toCashCounts checks that the counts are notes and coins of currency, each counted once, and
+// adds them up
+func toCashCounts(currency string, requests []web.CashCountRequest) ([]domain.CashCount, money.Money, error) {
+	cash, _ := money.Lookup(currency)
+	counted := money.New(0, currency)
+	counts := []domain.CashCount{}
+	seen := map[int64]bool{}
+	for _, request := range requests {
+		denomination := request.Denomination
+		if denomination.Currency() != currency || !cash.IsDenomination(denomination.Amount()) {
+			return nil, money.Money{}, exception.NewBadRequestError(fmt.Sprintf("%s is not a note or a coin of %s", denomination, currency))
+		}
+		if seen[denomination.Amount()] {
+			return nil, money.Money{}, exception.NewBadRequestError(fmt.Sprintf("%s is counted twice", denomination))
+		}
+		seen[denomination.Amount()] = true
+		counts = append(counts, domain.CashCount{Denomination: denomination, Quantity: request.Quantity})
+		counted = counted.Add(denomination.Mul(int64(request.Quantity)))
+	}
+	return counts, counted, nil
+}
+
+// reportDay is the start of a day written 2006-01-02 in the time zone of the server, of today
+// when date is empty
+func reportDay(date string) (time.Time, error) {
+	if date == "" {
+		year, month, day := time.Now().Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local), nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return time.Time{}, exception.NewBadRequestError(fmt.Sprintf("date %q is not a day like 2006-01-02", date))
+	}
+	return day, nil
+}
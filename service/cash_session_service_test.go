@@ -0,0 +1,330 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type cashSessionMocks struct {
+	sessions  *mocks.MockCashSessionRepository
+	orders    *mocks.MockOrderRepository
+	payments  *mocks.MockPaymentRepository
+	returns   *mocks.MockReturnRepository
+	employees *mocks.MockEmployeeRepository
+	stores    *mocks.MockStoreRepository
+}
+
+func setupCashSessionService(t *testing.T, publisher *recordingPublisher) (service.CashSessionService, cashSessionMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := cashSessionMocks{
+		sessions:  mocks.NewMockCashSessionRepository(ctrl),
+		orders:    mocks.NewMockOrderRepository(ctrl),
+		payments:  mocks.NewMockPaymentRepository(ctrl),
+		returns:   mocks.NewMockReturnRepository(ctrl),
+		employees: mocks.NewMockEmployeeRepository(ctrl),
+		stores:    mocks.NewMockStoreRepository(ctrl),
+	}
+	cashSessionService := service.NewCashSessionService(repositories.sessions, repositories.orders, repositories.payments, repositories.returns, repositories.employees, repositories.stores, fakeTransactor{}, publisher, validator.New())
+	return cashSessionService, repositories
+}
+
+// budi is the key of the cashier E001 at JKT01
+var budi = auth.Principal{Name: "budi", Permissions: []string{auth.StorePermission("JKT01"), auth.EmployeePermission("E001")}}
+
+// openSession was opened at JKT01 by E001 with a float of 200000, 50000 was put in the drawer since
+func openSession() domain.CashSession {
+	return domain.CashSession{
+		SessionID: "S1", StoreID: "JKT01", EmployeeID: "E001", Status: domain.CashSessionOpen, OpeningFloat: money.IDR(200000), Version: 1,
+		Counts:    []domain.CashCount{},
+		Movements: []domain.CashMovement{{SessionID: "S1", Position: 1, Type: domain.CashIn, Amount: money.IDR(50000), Reason: "kembalian", EmployeeID: "E001"}},
+		OpenedAt:  time.Now().Add(-4 * time.Hour),
+	}
+}
+
+// expectSales sells 116550 of Kopi in the session, 16550 paid in cash and 100000 with a card,
+// and 5000 in cash without the payments API; 38850 of the Kopi was refunded on the card
+func expectSales(repositories cashSessionMocks) {
+	tax := func(base int64, amount int64) []domain.OrderTax {
+		return []domain.OrderTax{{Code: "PPN", Name: "PPN 11%", Percent: 11, Base: money.IDR(base), Tax: money.IDR(amount)}}
+	}
+	repositories.orders.EXPECT().FindBetween(gomock.Any(), "JKT01", "E001", gomock.Any(), gomock.Any()).Return([]domain.Order{
+		{OrderID: "O1", StoreID: "JKT01", EmployeeID: "E001", Subtotal: money.IDR(105000), Discount: money.IDR(0), Tax: money.IDR(11550), Total: money.IDR(116550), Taxes: tax(105000, 11550)},
+		{OrderID: "O2", StoreID: "JKT01", EmployeeID: "E001", Subtotal: money.IDR(4505), Discount: money.IDR(0), Tax: money.IDR(495), Total: money.IDR(5000), Taxes: tax(4505, 495)},
+	}, nil)
+	repositories.payments.EXPECT().FindByOrders(gomock.Any(), []string{"O1", "O2"}).Return([]domain.Payment{
+		{PaymentID: "PAY1", OrderID: "O1", Status: domain.PaymentPaid, Tenders: []domain.PaymentTender{
+			{Position: 1, Method: domain.TenderCash, Amount: money.IDR(16550)},
+			{Position: 2, Method: domain.TenderCard, Amount: money.IDR(100000)},
+		}},
+	}, nil)
+	repositories.returns.EXPECT().FindCompleted(gomock.Any(), "JKT01", "E001", gomock.Any(), gomock.Any()).Return([]domain.Return{
+		{ReturnID: "R1", OrderID: "O1", Status: domain.ReturnCompleted, Tax: money.IDR(3850), Refund: money.IDR(38850), Refunds: []domain.ReturnRefund{{Method: domain.TenderCard, Amount: money.IDR(38850)}}},
+	}, nil)
+}
+
+func TestOpenCashSession(t *testing.T) {
+	request := web.CashSessionOpenRequest{StoreID: "JKT01", EmployeeID: "E001", OpeningFloat: money.IDR(200000)}
+
+	t.Run("opens a session with a float", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		cashSessionService, repositories := setupCashSessionService(t, publisher)
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		repositories.sessions.EXPECT().FindOpen(gomock.Any(), "JKT01", "E001").Return(domain.CashSession{}, gorm.ErrRecordNotFound)
+		repositories.sessions.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+			session.SessionID = "S1"
+			return session, true, nil
+		})
+
+		opened, err := cashSessionService.Open(context.Background(), budi, request)
+		require.NoError(t, err)
+		assert.Equal(t, "S1", opened.SessionID)
+		assert.Equal(t, domain.CashSessionOpen, opened.Status)
+		assert.Equal(t, money.IDR(200000), opened.OpeningFloat)
+		assert.Nil(t, opened.Expected, "nothing is expected before the session is closed")
+		assert.Equal(t, []string{event.CashSessionOpened}, publisher.types)
+	})
+
+	t.Run("one open session per employee and store", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		repositories.sessions.EXPECT().FindOpen(gomock.Any(), "JKT01", "E001").Return(openSession(), nil)
+
+		_, err := cashSessionService.Open(context.Background(), budi, request)
+		require.ErrorAs(t, err, &exception.BadRequestError{})
+		assert.Contains(t, err.Error(), "already has cash session S1 open")
+	})
+
+	t.Run("opened meanwhile", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		repositories.sessions.EXPECT().FindOpen(gomock.Any(), "JKT01", "E001").Return(domain.CashSession{}, gorm.ErrRecordNotFound)
+		repositories.sessions.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.CashSession{}, false, nil)
+
+		_, err := cashSessionService.Open(context.Background(), budi, request)
+		require.ErrorAs(t, err, &exception.BadRequestError{})
+		assert.Contains(t, err.Error(), "already has a cash session open")
+	})
+
+	t.Run("store outside the key", func(t *testing.T) {
+		cashSessionService, _ := setupCashSessionService(t, &recordingPublisher{})
+		bandung := request
+		bandung.StoreID = "BDG01"
+
+		_, err := cashSessionService.Open(context.Background(), budi, bandung)
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+
+	t.Run("employee of another key", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil).Times(2)
+		other := request
+		other.EmployeeID = "E002"
+
+		_, err := cashSessionService.Open(context.Background(), budi, other)
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+		_, err = cashSessionService.Open(context.Background(), cashier, web.CashSessionOpenRequest{StoreID: "JKT01", OpeningFloat: money.IDR(200000)})
+		assert.ErrorAs(t, err, &exception.BadRequestError{}, "the key is bound to no employee")
+	})
+}
+
+func TestRecordCashMovement(t *testing.T) {
+	t.Run("takes cash out of the drawer", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		cashSessionService, repositories := setupCashSessionService(t, publisher)
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		expectSales(repositories)
+		repositories.sessions.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+			session.Version++
+			return session, true, nil
+		})
+		repositories.sessions.EXPECT().SaveMovement(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, movement domain.CashMovement) error {
+			assert.Equal(t, 2, movement.Position)
+			return nil
+		})
+
+		moved, err := cashSessionService.RecordMovement(context.Background(), budi, web.CashMovementRequest{SessionID: "S1", EmployeeID: "E001", Type: domain.CashOut, Amount: money.IDR(271550), Reason: "setor ke brankas"})
+		require.NoError(t, err)
+		require.Len(t, moved.Movements, 2)
+		assert.Equal(t, domain.CashOut, moved.Movements[1].Type)
+		assert.Equal(t, []string{event.CashSessionCashMoved}, publisher.types)
+	})
+
+	t.Run("not more than the drawer holds", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		expectSales(repositories)
+
+		_, err := cashSessionService.RecordMovement(context.Background(), budi, web.CashMovementRequest{SessionID: "S1", EmployeeID: "E001", Type: domain.CashOut, Amount: money.IDR(271551), Reason: "setor ke brankas"})
+		require.ErrorAs(t, err, &exception.BadRequestError{})
+		assert.Contains(t, err.Error(), "only 271550 is expected")
+	})
+
+	t.Run("closed session", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		session := openSession()
+		session.Status = domain.CashSessionClosed
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(session, nil)
+
+		_, err := cashSessionService.RecordMovement(context.Background(), budi, web.CashMovementRequest{SessionID: "S1", EmployeeID: "E001", Type: domain.CashIn, Amount: money.IDR(1000), Reason: "receh"})
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+}
+
+func TestCloseCashSession(t *testing.T) {
+	t.Run("records the variance of the counted cash", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		cashSessionService, repositories := setupCashSessionService(t, publisher)
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		expectSales(repositories)
+		repositories.sessions.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, session domain.CashSession) (domain.CashSession, bool, error) {
+			return session, true, nil
+		})
+
+		closed, err := cashSessionService.Close(context.Background(), budi, web.CashSessionCloseRequest{SessionID: "S1", EmployeeID: "E001", Counts: []web.CashCountRequest{
+			{Denomination: money.IDR(100000), Quantity: 2},
+			{Denomination: money.IDR(50000), Quantity: 1},
+			{Denomination: money.IDR(20000), Quantity: 1},
+			{Denomination: money.IDR(500), Quantity: 3},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.CashSessionClosed, closed.Status)
+		require.NotNil(t, closed.Expected)
+		assert.Equal(t, money.IDR(271550), *closed.Expected, "200000 float, 16550 and 5000 in cash sales and 50000 put in")
+		assert.Equal(t, money.IDR(271500), *closed.Counted)
+		assert.Equal(t, money.IDR(-50), *closed.Variance)
+		assert.Equal(t, money.IDR(1500), closed.Counts[3].Amount)
+		assert.NotNil(t, closed.ClosedAt)
+		assert.Equal(t, []string{event.CashSessionClosed}, publisher.types)
+	})
+
+	t.Run("counted on a key of another employee", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+
+		_, err := cashSessionService.Close(context.Background(), cashier, web.CashSessionCloseRequest{SessionID: "S1", EmployeeID: "E001"})
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+
+	for name, counts := range map[string][]web.CashCountRequest{
+		"not a note":     {{Denomination: money.IDR(30000), Quantity: 1}},
+		"counted twice":  {{Denomination: money.IDR(1000), Quantity: 1}, {Denomination: money.IDR(1000), Quantity: 2}},
+		"other currency": {{Denomination: money.New(100, "USD"), Quantity: 1}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+			repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+			repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+
+			_, err := cashSessionService.Close(context.Background(), budi, web.CashSessionCloseRequest{SessionID: "S1", EmployeeID: "E001", Counts: counts})
+			assert.ErrorAs(t, err, &exception.BadRequestError{})
+		})
+	}
+}
+
+func TestCashSessionReport(t *testing.T) {
+	t.Run("x report of an open session", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+		expectSales(repositories)
+
+		report, err := cashSessionService.Report(context.Background(), cashier, "S1")
+		require.NoError(t, err)
+		assert.Equal(t, service.ReportX, report.Kind)
+		assert.Equal(t, 2, report.Orders)
+		assert.Equal(t, money.IDR(121550), report.Sales)
+		assert.Equal(t, money.IDR(82700), report.NetSales)
+		assert.Equal(t, money.IDR(8195), report.NetTax)
+		assert.Equal(t, []web.CashReportTaxResponse{{Code: "PPN", Name: "PPN 11%", Percent: 11, Base: money.IDR(109505), Tax: money.IDR(12045)}}, report.Taxes)
+		assert.Equal(t, []web.CashReportPaymentResponse{
+			{Method: domain.TenderCash, Sales: money.IDR(21550), Refunds: money.IDR(0), Net: money.IDR(21550)},
+			{Method: domain.TenderCard, Sales: money.IDR(100000), Refunds: money.IDR(38850), Net: money.IDR(61150)},
+		}, report.Payments)
+		assert.Equal(t, money.IDR(271550), report.Cash.Expected)
+		assert.Nil(t, report.Cash.Counted)
+	})
+
+	t.Run("orders not paid yet", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(openSession(), nil)
+		repositories.orders.EXPECT().FindBetween(gomock.Any(), "JKT01", "E001", gomock.Any(), gomock.Any()).Return([]domain.Order{
+			{OrderID: "O1", StoreID: "JKT01", EmployeeID: "E001", Subtotal: money.IDR(50000), Tax: money.IDR(0), Total: money.IDR(50000)},
+			{OrderID: "O2", StoreID: "JKT01", EmployeeID: "E001", Subtotal: money.IDR(30000), Tax: money.IDR(0), Total: money.IDR(30000)},
+			{OrderID: "O3", StoreID: "JKT01", EmployeeID: "E001", Subtotal: money.IDR(5000), Tax: money.IDR(0), Total: money.IDR(5000)},
+		}, nil)
+		repositories.payments.EXPECT().FindByOrders(gomock.Any(), []string{"O1", "O2", "O3"}).Return([]domain.Payment{
+			{PaymentID: "PAY1", OrderID: "O1", Status: domain.PaymentPending, Tenders: []domain.PaymentTender{{Position: 1, Method: domain.TenderCard, Amount: money.IDR(50000)}}},
+			{PaymentID: "PAY2", OrderID: "O2", Status: domain.PaymentFailed, Tenders: []domain.PaymentTender{{Position: 1, Method: domain.TenderCard, Amount: money.IDR(30000)}}},
+		}, nil)
+		repositories.returns.EXPECT().FindCompleted(gomock.Any(), "JKT01", "E001", gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		report, err := cashSessionService.Report(context.Background(), cashier, "S1")
+		require.NoError(t, err)
+		assert.Equal(t, []web.CashReportPaymentResponse{
+			{Method: domain.TenderCash, Sales: money.IDR(5000), Refunds: money.IDR(0), Net: money.IDR(5000)},
+		}, report.Payments, "only the order without a payment is cash")
+		assert.Equal(t, money.IDR(255000), report.Cash.Expected)
+	})
+
+	t.Run("z report of a closed session", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		session := openSession()
+		closedAt := time.Now()
+		session.Status, session.ClosedAt = domain.CashSessionClosed, &closedAt
+		session.Expected, session.Counted, session.Variance = money.IDR(271550), money.IDR(271500), money.IDR(-50)
+		repositories.sessions.EXPECT().FindById(gomock.Any(), "S1").Return(session, nil)
+		expectSales(repositories)
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01", Name: "Toko Jakarta"}, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001", Name: "Budi"}, nil)
+
+		printed, err := cashSessionService.PrintReport(context.Background(), cashier, "S1")
+		require.NoError(t, err)
+		assert.Equal(t, "LAPORAN Z", printed.Title)
+		assert.Equal(t, []string{"Toko Jakarta"}, printed.Header)
+		cash := printed.Sections[len(printed.Sections)-1]
+		assert.Equal(t, "KAS", cash.Title)
+		assert.Contains(t, cash.Entries, receipt.Entry{Label: "Selisih", Value: "-50"})
+	})
+
+	t.Run("store report of a day with an open session", func(t *testing.T) {
+		cashSessionService, repositories := setupCashSessionService(t, &recordingPublisher{})
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+		repositories.orders.EXPECT().FindBetween(gomock.Any(), "JKT01", "", gomock.Any(), gomock.Any()).Return(nil, nil)
+		repositories.payments.EXPECT().FindByOrders(gomock.Any(), []string{}).Return(nil, nil)
+		repositories.returns.EXPECT().FindCompleted(gomock.Any(), "JKT01", "", gomock.Any(), gomock.Any()).Return(nil, nil)
+		repositories.sessions.EXPECT().FindOpened(gomock.Any(), "JKT01", time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)).Return([]domain.CashSession{openSession()}, nil)
+		expectSales(repositories)
+
+		report, err := cashSessionService.StoreReport(context.Background(), cashier, "JKT01", "2026-10-19")
+		require.NoError(t, err)
+		assert.Equal(t, service.ReportX, report.Kind, "the session is still open")
+		assert.Equal(t, 0, report.Orders)
+		assert.Len(t, report.Sessions, 1)
+		assert.Equal(t, money.IDR(271550), report.Cash.Expected)
+		assert.Nil(t, report.Cash.Variance)
+
+		_, err = cashSessionService.StoreReport(context.Background(), cashier, "JKT01", "19/10/2026")
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+}
@@ -11,4 +11,5 @@ type CategoryService interface {
 	Delete(ctx context.Context, categoryId int) error
 	FindById(ctx context.Context, categoryId int) (web.CategoryResponse, error)
 	FindAll(ctx context.Context) ([]web.CategoryResponse, error)
+	FindByNames(ctx context.Context, names []string) ([]web.CategoryResponse, error)
 }
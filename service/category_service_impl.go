@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"github.com/aronipurwanto/go-restful-api/event"
 	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
@@ -14,12 +15,16 @@ import (
 
 type CategoryServiceImpl struct {
 	CategoryRepository repository.CategoryRepository
+	Transactor         repository.Transactor
+	Events             event.Publisher
 	Validate           *validator.Validate
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository, validate *validator.Validate) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) CategoryService {
 	return &CategoryServiceImpl{
 		CategoryRepository: categoryRepository,
+		Transactor:         transactor,
+		Events:             events,
 		Validate:           validate,
 	}
 }
@@ -31,12 +36,19 @@ func (service *CategoryServiceImpl) Create(ctx context.Context, request web.Cate
 	}
 
 	category := domain.Category{Name: request.Name}
-	savedCategory, err := service.CategoryRepository.Save(ctx, category)
+	var response web.CategoryResponse
+	err := service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedCategory, err := service.CategoryRepository.Save(ctx, category)
+		if err != nil {
+			return err
+		}
+		response = helper.ToCategoryResponse(savedCategory)
+		return service.Events.Publish(ctx, event.CategoryCreated, response)
+	})
 	if err != nil {
 		return web.CategoryResponse{}, err
 	}
-
-	return helper.ToCategoryResponse(savedCategory), nil
+	return response, nil
 }
 
 // Update Category
@@ -53,12 +65,19 @@ func (service *CategoryServiceImpl) Update(ctx context.Context, request web.Cate
 	}
 
 	category.Name = request.Name
-	updatedCategory, err := service.CategoryRepository.Update(ctx, category)
+	var response web.CategoryResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updatedCategory, err := service.CategoryRepository.Update(ctx, category)
+		if err != nil {
+			return err
+		}
+		response = helper.ToCategoryResponse(updatedCategory)
+		return service.Events.Publish(ctx, event.CategoryUpdated, response)
+	})
 	if err != nil {
 		return web.CategoryResponse{}, err
 	}
-
-	return helper.ToCategoryResponse(updatedCategory), nil
+	return response, nil
 }
 
 // Delete Category
@@ -70,7 +89,12 @@ func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId int)
 		return err
 	}
 
-	return service.CategoryRepository.Delete(ctx, category)
+	return service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.CategoryRepository.Delete(ctx, category); err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.CategoryDeleted, helper.ToCategoryResponse(category))
+	})
 }
 
 // Find Category By ID
@@ -94,3 +118,16 @@ func (service *CategoryServiceImpl) FindAll(ctx context.Context) ([]web.Category
 
 	return helper.ToCategoryResponses(categories), nil
 }
+
+// FindByNames Category, unknown names are left out
+func (service *CategoryServiceImpl) FindByNames(ctx context.Context, names []string) ([]web.CategoryResponse, error) {
+	if len(names) == 0 {
+		return []web.CategoryResponse{}, nil
+	}
+	categories, err := service.CategoryRepository.FindByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToCategoryResponses(categories), nil
+}
@@ -20,7 +20,7 @@ func TestCreateCategory(t *testing.T) {
 
 	mockRepo := mocks.NewMockCategoryRepository(ctrl)
 	mockValidator := validator.New()
-	categoryService := service.NewCategoryService(mockRepo, mockValidator)
+	categoryService := service.NewCategoryService(mockRepo, fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -75,7 +75,7 @@ func TestDeleteCategory(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockCategoryRepository(ctrl)
-	categoryService := service.NewCategoryService(mockRepo, validator.New())
+	categoryService := service.NewCategoryService(mockRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	tests := []struct {
 		name       string
@@ -148,7 +148,7 @@ func TestUpdateCategory(t *testing.T) {
 			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 			tt.mock(mockCategoryRepo)
 
-			service := service.NewCategoryService(mockCategoryRepo, validator.New())
+			service := service.NewCategoryService(mockCategoryRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 			_, err := service.Update(context.Background(), tt.input)
 			assert.Equal(t, tt.expects, err)
 		})
@@ -187,7 +187,7 @@ func TestFindAllCategories(t *testing.T) {
 			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 			tt.mock(mockCategoryRepo)
 
-			service := service.NewCategoryService(mockCategoryRepo, validator.New())
+			service := service.NewCategoryService(mockCategoryRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 			result, err := service.FindAll(context.Background())
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
@@ -195,6 +195,56 @@ func TestFindAllCategories(t *testing.T) {
 	}
 }
 
+func TestFindByNamesCategories(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		mock    func(mockCategoryRepo *mocks.MockCategoryRepository)
+		expects []web.CategoryResponse
+		err     error
+	}{
+		{
+			name:  "Success",
+			names: []string{"Category 1", "Unknown"},
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindByNames(gomock.Any(), []string{"Category 1", "Unknown"}).Return([]domain.Category{{Id: 1, Name: "Category 1"}}, nil)
+			},
+			expects: []web.CategoryResponse{{Id: 1, Name: "Category 1"}},
+			err:     nil,
+		},
+		{
+			name:    "No Names",
+			names:   nil,
+			mock:    func(mockCategoryRepo *mocks.MockCategoryRepository) {},
+			expects: []web.CategoryResponse{},
+			err:     nil,
+		},
+		{
+			name:  "Database Error",
+			names: []string{"Category 1"},
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expects: nil,
+			err:     errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			tt.mock(mockCategoryRepo)
+
+			service := service.NewCategoryService(mockCategoryRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
+			result, err := service.FindByNames(context.Background(), tt.names)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestFindByIdCategory(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -230,7 +280,7 @@ func TestFindByIdCategory(t *testing.T) {
 			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 			tt.mock(mockCategoryRepo)
 
-			service := service.NewCategoryService(mockCategoryRepo, validator.New())
+			service := service.NewCategoryService(mockCategoryRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 			result, err := service.FindById(context.Background(), int(tt.input))
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
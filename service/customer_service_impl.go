@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"github.com/aronipurwanto/go-restful-api/event"
 	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
@@ -14,12 +15,16 @@ import (
 
 type CustomerServiceImpl struct {
 	CustomerRepository repository.CustomerRepository
+	Transactor         repository.Transactor
+	Events             event.Publisher
 	Validate           *validator.Validate
 }
 
-func NewCustomerService(customerRepository repository.CustomerRepository, validate *validator.Validate) CustomerService {
+func NewCustomerService(customerRepository repository.CustomerRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) CustomerService {
 	return &CustomerServiceImpl{
 		CustomerRepository: customerRepository,
+		Transactor:         transactor,
+		Events:             events,
 		Validate:           validate,
 	}
 }
@@ -37,12 +42,19 @@ func (service *CustomerServiceImpl) Create(ctx context.Context, request web.Cust
 		Address:    request.Address,
 		LoyaltyPts: request.LoyaltyPts,
 	}
-	savedCustomer, err := service.CustomerRepository.Save(ctx, customer)
+	var response web.CustomerResponse
+	err := service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedCustomer, err := service.CustomerRepository.Save(ctx, customer)
+		if err != nil {
+			return err
+		}
+		response = helper.ToCustomerResponse(savedCustomer)
+		return service.Events.Publish(ctx, event.CustomerCreated, response)
+	})
 	if err != nil {
 		return web.CustomerResponse{}, err
 	}
-
-	return helper.ToCustomerResponse(savedCustomer), nil
+	return response, nil
 }
 
 // Update Customer
@@ -65,12 +77,19 @@ func (service *CustomerServiceImpl) Update(ctx context.Context, request web.Cust
 	customer.Address = request.Address
 	customer.LoyaltyPts = request.LoyaltyPts
 
-	updatedCustomer, err := service.CustomerRepository.Update(ctx, customer)
+	var response web.CustomerResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updatedCustomer, err := service.CustomerRepository.Update(ctx, customer)
+		if err != nil {
+			return err
+		}
+		response = helper.ToCustomerResponse(updatedCustomer)
+		return service.Events.Publish(ctx, event.CustomerUpdated, response)
+	})
 	if err != nil {
 		return web.CustomerResponse{}, err
 	}
-
-	return helper.ToCustomerResponse(updatedCustomer), nil
+	return response, nil
 }
 
 // Delete Customer
@@ -82,7 +101,12 @@ func (service *CustomerServiceImpl) Delete(ctx context.Context, customerId strin
 		return err
 	}
 
-	return service.CustomerRepository.Delete(ctx, customer)
+	return service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.CustomerRepository.Delete(ctx, customer); err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.CustomerDeleted, helper.ToCustomerResponse(customer))
+	})
 }
 
 // Find Customer By ID
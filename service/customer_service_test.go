@@ -20,7 +20,7 @@ func TestCreateCustomer(t *testing.T) {
 
 	mockRepo := mocks.NewMockCustomerRepository(ctrl)
 	mockValidator := validator.New()
-	customerService := service.NewCustomerService(mockRepo, mockValidator)
+	customerService := service.NewCustomerService(mockRepo, fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -109,7 +109,7 @@ func TestUpdateCustomer(t *testing.T) {
 			mockCustomerRepo := mocks.NewMockCustomerRepository(ctrl)
 			tt.mock(mockCustomerRepo)
 
-			service := service.NewCustomerService(mockCustomerRepo, validator.New())
+			service := service.NewCustomerService(mockCustomerRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 			resp, err := service.Update(context.Background(), tt.input)
 
 			if tt.expectErr {
@@ -127,7 +127,7 @@ func TestDeleteCustomer(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockCustomerRepository(ctrl)
-	customerService := service.NewCustomerService(mockRepo, validator.New())
+	customerService := service.NewCustomerService(mockRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	tests := []struct {
 		name       string
@@ -171,7 +171,7 @@ func TestFindCustomerById(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockCustomerRepository(ctrl)
-	customerService := service.NewCustomerService(mockRepo, validator.New())
+	customerService := service.NewCustomerService(mockRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	tests := []struct {
 		name       string
@@ -219,7 +219,7 @@ func TestFindAllCustomers(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockCustomerRepository(ctrl)
-	customerService := service.NewCustomerService(mockRepo, validator.New())
+	customerService := service.NewCustomerService(mockRepo, fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	mockRepo.EXPECT().FindAll(gomock.Any()).Return([]domain.Customer{
 		{CustomerID: "1", Name: "John Doe"},
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/event"
 	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
@@ -10,16 +12,23 @@ import (
 	"github.com/aronipurwanto/go-restful-api/repository"
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
+	"sort"
 )
 
 type EmployeeServiceImpl struct {
 	EmployeeRepository repository.EmployeeRepository
+	StoreRepository    repository.StoreRepository
+	Transactor         repository.Transactor
+	Events             event.Publisher
 	Validate           *validator.Validate
 }
 
-func NewEmployeeService(employeeRepository repository.EmployeeRepository, validate *validator.Validate) EmployeeService {
+func NewEmployeeService(employeeRepository repository.EmployeeRepository, storeRepository repository.StoreRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) EmployeeService {
 	return &EmployeeServiceImpl{
 		EmployeeRepository: employeeRepository,
+		StoreRepository:    storeRepository,
+		Transactor:         transactor,
+		Events:             events,
 		Validate:           validate,
 	}
 }
@@ -37,13 +46,25 @@ func (service *EmployeeServiceImpl) Create(ctx context.Context, request web.Empl
 		Phone:     request.Phone,
 		DateHired: request.DateHired,
 	}
-
-	savedEmployee, err := service.EmployeeRepository.Save(ctx, employee)
+	stores, err := service.findStores(ctx, request.StoreIDs)
 	if err != nil {
 		return web.EmployeeResponse{}, err
 	}
-
-	return helper.ToEmployeeResponse(savedEmployee), nil
+	employee.Stores = stores
+
+	var response web.EmployeeResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedEmployee, err := service.EmployeeRepository.Save(ctx, employee)
+		if err != nil {
+			return err
+		}
+		response = helper.ToEmployeeResponse(savedEmployee)
+		return service.Events.Publish(ctx, event.EmployeeCreated, response)
+	})
+	if err != nil {
+		return web.EmployeeResponse{}, err
+	}
+	return response, nil
 }
 
 // Update Employee
@@ -65,13 +86,27 @@ func (service *EmployeeServiceImpl) Update(ctx context.Context, request web.Empl
 	employee.Email = request.Email
 	employee.Phone = request.Phone
 	employee.DateHired = request.DateHired
+	if request.StoreIDs != nil {
+		stores, err := service.findStores(ctx, request.StoreIDs)
+		if err != nil {
+			return web.EmployeeResponse{}, err
+		}
+		employee.Stores = stores
+	}
 
-	updatedEmployee, err := service.EmployeeRepository.Update(ctx, employee)
+	var response web.EmployeeResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updatedEmployee, err := service.EmployeeRepository.Update(ctx, employee)
+		if err != nil {
+			return err
+		}
+		response = helper.ToEmployeeResponse(updatedEmployee)
+		return service.Events.Publish(ctx, event.EmployeeUpdated, response)
+	})
 	if err != nil {
 		return web.EmployeeResponse{}, err
 	}
-
-	return helper.ToEmployeeResponse(updatedEmployee), nil
+	return response, nil
 }
 
 // Delete Employee
@@ -83,7 +118,12 @@ func (service *EmployeeServiceImpl) Delete(ctx context.Context, employeeId strin
 		return err
 	}
 
-	return service.EmployeeRepository.Delete(ctx, employee)
+	return service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.EmployeeRepository.Delete(ctx, employee); err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.EmployeeDeleted, helper.ToEmployeeResponse(employee))
+	})
 }
 
 // Find Employee By ID
@@ -107,3 +147,37 @@ func (service *EmployeeServiceImpl) FindAll(ctx context.Context) ([]web.Employee
 
 	return helper.ToEmployeeResponses(employees), nil
 }
+
+// findStores checks that the stores exist and returns the assignments of an employee to them
+func (service *EmployeeServiceImpl) findStores(ctx context.Context, storeIds []string) ([]domain.EmployeeStore, error) {
+	if len(storeIds) == 0 {
+		return nil, nil
+	}
+	stores, err := service.StoreRepository.FindByIds(ctx, storeIds)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(stores))
+	for _, store := range stores {
+		found[store.StoreID] = true
+	}
+	var unknown []string
+	var assignments []domain.EmployeeStore
+	assigned := map[string]bool{}
+	for _, storeId := range storeIds {
+		if !found[storeId] {
+			unknown = append(unknown, storeId)
+			continue
+		}
+		if !assigned[storeId] {
+			assigned[storeId] = true
+			assignments = append(assignments, domain.EmployeeStore{StoreID: storeId})
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, exception.NewBadRequestError(fmt.Sprintf("unknown stores %v", unknown))
+	}
+	return assignments, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/repository/mocks"
@@ -20,7 +21,7 @@ func TestCreateEmployee(t *testing.T) {
 
 	mockRepo := mocks.NewMockEmployeeRepository(ctrl)
 	mockValidator := validator.New()
-	employeeService := service.NewEmployeeService(mockRepo, mockValidator)
+	employeeService := service.NewEmployeeService(mockRepo, mocks.NewMockStoreRepository(ctrl), fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -75,7 +76,7 @@ func TestDeleteEmployee(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockEmployeeRepository(ctrl)
-	employeeService := service.NewEmployeeService(mockRepo, validator.New())
+	employeeService := service.NewEmployeeService(mockRepo, mocks.NewMockStoreRepository(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	tests := []struct {
 		name      string
@@ -121,7 +122,7 @@ func TestFindByIdEmployee(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockEmployeeRepository(ctrl)
-	employeeService := service.NewEmployeeService(mockRepo, validator.New())
+	employeeService := service.NewEmployeeService(mockRepo, mocks.NewMockStoreRepository(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	tests := []struct {
 		name      string
@@ -170,7 +171,7 @@ func TestUpdateEmployee(t *testing.T) {
 
 	mockRepo := mocks.NewMockEmployeeRepository(ctrl)
 	mockValidator := validator.New()
-	employeeService := service.NewEmployeeService(mockRepo, mockValidator)
+	employeeService := service.NewEmployeeService(mockRepo, mocks.NewMockStoreRepository(ctrl), fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -221,7 +222,7 @@ func TestFindAllEmployees(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockEmployeeRepository(ctrl)
-	employeeService := service.NewEmployeeService(mockRepo, validator.New())
+	employeeService := service.NewEmployeeService(mockRepo, mocks.NewMockStoreRepository(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	mockRepo.EXPECT().FindAll(gomock.Any()).Return([]domain.Employee{{EmployeeID: "1", Name: "Alice"}}, nil)
 
@@ -231,3 +232,37 @@ func TestFindAllEmployees(t *testing.T) {
 	assert.Len(t, resp, 1)
 	assert.Equal(t, "Alice", resp[0].Name)
 }
+
+func TestEmployeeStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockEmployeeRepository(ctrl)
+	mockStores := mocks.NewMockStoreRepository(ctrl)
+	employeeService := service.NewEmployeeService(mockRepo, mockStores, fakeTransactor{}, &recordingPublisher{}, validator.New())
+	ctx := context.Background()
+
+	request := web.EmployeeCreateRequest{Name: "Andi", Role: "cashier", Email: "andi@example.com", Phone: "0812", DateHired: "2024-01-01", StoreIDs: []string{"JKT01", "BDG01", "JKT01"}}
+	mockStores.EXPECT().FindByIds(ctx, request.StoreIDs).Return([]domain.Store{{StoreID: "BDG01"}, {StoreID: "JKT01"}}, nil)
+	mockRepo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
+		employee.EmployeeID = "E1"
+		return employee, nil
+	})
+	created, err := employeeService.Create(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"JKT01", "BDG01"}, created.StoreIDs)
+
+	request.StoreIDs = []string{"JKT01", "SBY01"}
+	mockStores.EXPECT().FindByIds(ctx, request.StoreIDs).Return([]domain.Store{{StoreID: "JKT01"}}, nil)
+	_, err = employeeService.Create(ctx, request)
+	assert.ErrorAs(t, err, &exception.BadRequestError{})
+	assert.ErrorContains(t, err, "unknown stores [SBY01]")
+
+	// Without store_ids an update keeps the stores of the employee
+	existing := domain.Employee{EmployeeID: "E1", Name: "Andi", Stores: []domain.EmployeeStore{{EmployeeID: "E1", StoreID: "JKT01"}}}
+	mockRepo.EXPECT().FindById(ctx, "E1").Return(existing, nil)
+	mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
+		return employee, nil
+	})
+	updated, err := employeeService.Update(ctx, web.EmployeeUpdateRequest{EmployeeID: "E1", Name: "Andi", Role: "supervisor", Email: "andi@example.com", Phone: "0812", DateHired: "2024-01-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"JKT01"}, updated.StoreIDs)
+}
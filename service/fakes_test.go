@@ -0,0 +1,24 @@
+package service_test
+
+import "context"
+
+// fakeTransactor runs fn without a database transaction
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// recordingPublisher keeps the type of every published event
+type recordingPublisher struct {
+	types []string
+	err   error
+}
+
+func (publisher *recordingPublisher) Publish(ctx context.Context, eventType string, data interface{}) error {
+	if publisher.err != nil {
+		return publisher.err
+	}
+	publisher.types = append(publisher.types, eventType)
+	return nil
+}
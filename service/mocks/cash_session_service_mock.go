@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/cash_session_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	receipt "github.com/aronipurwanto/go-restful-api/receipt"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCashSessionService is a mock of CashSessionService interface.
+type MockCashSessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCashSessionServiceMockRecorder
+}
+
+// MockCashSessionServiceMockRecorder is the mock recorder for MockCashSessionService.
+type MockCashSessionServiceMockRecorder struct {
+	mock *MockCashSessionService
+}
+
+// NewMockCashSessionService creates a new mock instance.
+func NewMockCashSessionService(ctrl *gomock.Controller) *MockCashSessionService {
+	mock := &MockCashSessionService{ctrl: ctrl}
+	mock.recorder = &MockCashSessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCashSessionService) EXPECT() *MockCashSessionServiceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockCashSessionService) Close(ctx context.Context, principal auth.Principal, request web.CashSessionCloseRequest) (web.CashSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, principal, request)
+	ret0, _ := ret[0].(web.CashSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockCashSessionServiceMockRecorder) Close(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCashSessionService)(nil).Close), ctx, principal, request)
+}
+
+// FindAll mocks base method.
+func (m *MockCashSessionService) FindAll(ctx context.Context, storeId, status string) ([]web.CashSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, status)
+	ret0, _ := ret[0].([]web.CashSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockCashSessionServiceMockRecorder) FindAll(ctx, storeId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockCashSessionService)(nil).FindAll), ctx, storeId, status)
+}
+
+// FindById mocks base method.
+func (m *MockCashSessionService) FindById(ctx context.Context, principal auth.Principal, sessionId string) (web.CashSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, principal, sessionId)
+	ret0, _ := ret[0].(web.CashSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCashSessionServiceMockRecorder) FindById(ctx, principal, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCashSessionService)(nil).FindById), ctx, principal, sessionId)
+}
+
+// Open mocks base method.
+func (m *MockCashSessionService) Open(ctx context.Context, principal auth.Principal, request web.CashSessionOpenRequest) (web.CashSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, principal, request)
+	ret0, _ := ret[0].(web.CashSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockCashSessionServiceMockRecorder) Open(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockCashSessionService)(nil).Open), ctx, principal, request)
+}
+
+// PrintReport mocks base method.
+func (m *MockCashSessionService) PrintReport(ctx context.Context, principal auth.Principal, sessionId string) (receipt.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrintReport", ctx, principal, sessionId)
+	ret0, _ := ret[0].(receipt.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrintReport indicates an expected call of PrintReport.
+func (mr *MockCashSessionServiceMockRecorder) PrintReport(ctx, principal, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrintReport", reflect.TypeOf((*MockCashSessionService)(nil).PrintReport), ctx, principal, sessionId)
+}
+
+// PrintStoreReport mocks base method.
+func (m *MockCashSessionService) PrintStoreReport(ctx context.Context, principal auth.Principal, storeId, date string) (receipt.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrintStoreReport", ctx, principal, storeId, date)
+	ret0, _ := ret[0].(receipt.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrintStoreReport indicates an expected call of PrintStoreReport.
+func (mr *MockCashSessionServiceMockRecorder) PrintStoreReport(ctx, principal, storeId, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrintStoreReport", reflect.TypeOf((*MockCashSessionService)(nil).PrintStoreReport), ctx, principal, storeId, date)
+}
+
+// RecordMovement mocks base method.
+func (m *MockCashSessionService) RecordMovement(ctx context.Context, principal auth.Principal, request web.CashMovementRequest) (web.CashSessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMovement", ctx, principal, request)
+	ret0, _ := ret[0].(web.CashSessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMovement indicates an expected call of RecordMovement.
+func (mr *MockCashSessionServiceMockRecorder) RecordMovement(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMovement", reflect.TypeOf((*MockCashSessionService)(nil).RecordMovement), ctx, principal, request)
+}
+
+// Report mocks base method.
+func (m *MockCashSessionService) Report(ctx context.Context, principal auth.Principal, sessionId string) (web.CashReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, principal, sessionId)
+	ret0, _ := ret[0].(web.CashReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockCashSessionServiceMockRecorder) Report(ctx, principal, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockCashSessionService)(nil).Report), ctx, principal, sessionId)
+}
+
+// StoreReport mocks base method.
+func (m *MockCashSessionService) StoreReport(ctx context.Context, principal auth.Principal, storeId, date string) (web.CashReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreReport", ctx, principal, storeId, date)
+	ret0, _ := ret[0].(web.CashReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreReport indicates an expected call of StoreReport.
+func (mr *MockCashSessionServiceMockRecorder) StoreReport(ctx, principal, storeId, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreReport", reflect.TypeOf((*MockCashSessionService)(nil).StoreReport), ctx, principal, storeId, date)
+}
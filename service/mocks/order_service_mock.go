@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/order_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	receipt "github.com/aronipurwanto/go-restful-api/receipt"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderService is a mock of OrderService interface.
+type MockOrderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderServiceMockRecorder
+}
+
+// MockOrderServiceMockRecorder is the mock recorder for MockOrderService.
+type MockOrderServiceMockRecorder struct {
+	mock *MockOrderService
+}
+
+// NewMockOrderService creates a new mock instance.
+func NewMockOrderService(ctrl *gomock.Controller) *MockOrderService {
+	mock := &MockOrderService{ctrl: ctrl}
+	mock.recorder = &MockOrderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrderService) Create(ctx context.Context, principal auth.Principal, request web.OrderCreateRequest) (web.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, principal, request)
+	ret0, _ := ret[0].(web.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderServiceMockRecorder) Create(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderService)(nil).Create), ctx, principal, request)
+}
+
+// FindAll mocks base method.
+func (m *MockOrderService) FindAll(ctx context.Context, storeId string) ([]web.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId)
+	ret0, _ := ret[0].([]web.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockOrderServiceMockRecorder) FindAll(ctx, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockOrderService)(nil).FindAll), ctx, storeId)
+}
+
+// FindById mocks base method.
+func (m *MockOrderService) FindById(ctx context.Context, principal auth.Principal, orderId string) (web.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, principal, orderId)
+	ret0, _ := ret[0].(web.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockOrderServiceMockRecorder) FindById(ctx, principal, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockOrderService)(nil).FindById), ctx, principal, orderId)
+}
+
+// Receipt mocks base method.
+func (m *MockOrderService) Receipt(ctx context.Context, principal auth.Principal, orderId string) (receipt.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receipt", ctx, principal, orderId)
+	ret0, _ := ret[0].(receipt.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Receipt indicates an expected call of Receipt.
+func (mr *MockOrderServiceMockRecorder) Receipt(ctx, principal, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receipt", reflect.TypeOf((*MockOrderService)(nil).Receipt), ctx, principal, orderId)
+}
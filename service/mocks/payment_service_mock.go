@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/payment_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentService is a mock of PaymentService interface.
+type MockPaymentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentServiceMockRecorder
+}
+
+// MockPaymentServiceMockRecorder is the mock recorder for MockPaymentService.
+type MockPaymentServiceMockRecorder struct {
+	mock *MockPaymentService
+}
+
+// NewMockPaymentService creates a new mock instance.
+func NewMockPaymentService(ctrl *gomock.Controller) *MockPaymentService {
+	mock := &MockPaymentService{ctrl: ctrl}
+	mock.recorder = &MockPaymentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentService) EXPECT() *MockPaymentServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPaymentService) Create(ctx context.Context, principal auth.Principal, request web.PaymentCreateRequest) (web.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, principal, request)
+	ret0, _ := ret[0].(web.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentServiceMockRecorder) Create(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentService)(nil).Create), ctx, principal, request)
+}
+
+// CreateQRIS mocks base method.
+func (m *MockPaymentService) CreateQRIS(ctx context.Context, principal auth.Principal, request web.QRISCreateRequest) (web.QRISResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQRIS", ctx, principal, request)
+	ret0, _ := ret[0].(web.QRISResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateQRIS indicates an expected call of CreateQRIS.
+func (mr *MockPaymentServiceMockRecorder) CreateQRIS(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQRIS", reflect.TypeOf((*MockPaymentService)(nil).CreateQRIS), ctx, principal, request)
+}
+
+// FindAll mocks base method.
+func (m *MockPaymentService) FindAll(ctx context.Context, storeId, customerId string) ([]web.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, customerId)
+	ret0, _ := ret[0].([]web.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockPaymentServiceMockRecorder) FindAll(ctx, storeId, customerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockPaymentService)(nil).FindAll), ctx, storeId, customerId)
+}
+
+// FindById mocks base method.
+func (m *MockPaymentService) FindById(ctx context.Context, principal auth.Principal, paymentId string) (web.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, principal, paymentId)
+	ret0, _ := ret[0].(web.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockPaymentServiceMockRecorder) FindById(ctx, principal, paymentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockPaymentService)(nil).FindById), ctx, principal, paymentId)
+}
+
+// Notify mocks base method.
+func (m *MockPaymentService) Notify(ctx context.Context, provider string, header func(string) string, body []byte) (web.PaymentNotificationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, provider, header, body)
+	ret0, _ := ret[0].(web.PaymentNotificationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockPaymentServiceMockRecorder) Notify(ctx, provider, header, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockPaymentService)(nil).Notify), ctx, provider, header, body)
+}
+
+// Sync mocks base method.
+func (m *MockPaymentService) Sync(ctx context.Context, principal auth.Principal, paymentId string) (web.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, principal, paymentId)
+	ret0, _ := ret[0].(web.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockPaymentServiceMockRecorder) Sync(ctx, principal, paymentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockPaymentService)(nil).Sync), ctx, principal, paymentId)
+}
+
+// VerifyQRIS mocks base method.
+func (m *MockPaymentService) VerifyQRIS(ctx context.Context, request web.QRISVerifyRequest) (web.QRISResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyQRIS", ctx, request)
+	ret0, _ := ret[0].(web.QRISResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyQRIS indicates an expected call of VerifyQRIS.
+func (mr *MockPaymentServiceMockRecorder) VerifyQRIS(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyQRIS", reflect.TypeOf((*MockPaymentService)(nil).VerifyQRIS), ctx, request)
+}
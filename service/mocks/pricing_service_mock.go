@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/pricing_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPricingService is a mock of PricingService interface.
+type MockPricingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPricingServiceMockRecorder
+}
+
+// MockPricingServiceMockRecorder is the mock recorder for MockPricingService.
+type MockPricingServiceMockRecorder struct {
+	mock *MockPricingService
+}
+
+// NewMockPricingService creates a new mock instance.
+func NewMockPricingService(ctrl *gomock.Controller) *MockPricingService {
+	mock := &MockPricingService{ctrl: ctrl}
+	mock.recorder = &MockPricingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPricingService) EXPECT() *MockPricingServiceMockRecorder {
+	return m.recorder
+}
+
+// FindTaxClasses mocks base method.
+func (m *MockPricingService) FindTaxClasses(ctx context.Context) ([]web.TaxClassResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTaxClasses", ctx)
+	ret0, _ := ret[0].([]web.TaxClassResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTaxClasses indicates an expected call of FindTaxClasses.
+func (mr *MockPricingServiceMockRecorder) FindTaxClasses(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTaxClasses", reflect.TypeOf((*MockPricingService)(nil).FindTaxClasses), ctx)
+}
+
+// Quote mocks base method.
+func (m *MockPricingService) Quote(ctx context.Context, request web.QuoteRequest) (web.QuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, request)
+	ret0, _ := ret[0].(web.QuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockPricingServiceMockRecorder) Quote(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockPricingService)(nil).Quote), ctx, request)
+}
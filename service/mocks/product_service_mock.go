@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/product_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProductService is a mock of ProductService interface.
+type MockProductService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductServiceMockRecorder
+}
+
+// MockProductServiceMockRecorder is the mock recorder for MockProductService.
+type MockProductServiceMockRecorder struct {
+	mock *MockProductService
+}
+
+// NewMockProductService creates a new mock instance.
+func NewMockProductService(ctrl *gomock.Controller) *MockProductService {
+	mock := &MockProductService{ctrl: ctrl}
+	mock.recorder = &MockProductServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductService) Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductServiceMockRecorder) Create(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductService)(nil).Create), ctx, request)
+}
+
+// Delete mocks base method.
+func (m *MockProductService) Delete(ctx context.Context, productId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, productId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductServiceMockRecorder) Delete(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductService)(nil).Delete), ctx, productId)
+}
+
+// FindAll mocks base method.
+func (m *MockProductService) FindAll(ctx context.Context) ([]web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockProductServiceMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductService)(nil).FindAll), ctx)
+}
+
+// FindByCategories mocks base method.
+func (m *MockProductService) FindByCategories(ctx context.Context, categories []string) ([]web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCategories", ctx, categories)
+	ret0, _ := ret[0].([]web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCategories indicates an expected call of FindByCategories.
+func (mr *MockProductServiceMockRecorder) FindByCategories(ctx, categories interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCategories", reflect.TypeOf((*MockProductService)(nil).FindByCategories), ctx, categories)
+}
+
+// FindById mocks base method.
+func (m *MockProductService) FindById(ctx context.Context, productId string) (web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, productId)
+	ret0, _ := ret[0].(web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockProductServiceMockRecorder) FindById(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockProductService)(nil).FindById), ctx, productId)
+}
+
+// FindByOptions mocks base method.
+func (m *MockProductService) FindByOptions(ctx context.Context, options map[string]string) ([]web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOptions", ctx, options)
+	ret0, _ := ret[0].([]web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOptions indicates an expected call of FindByOptions.
+func (mr *MockProductServiceMockRecorder) FindByOptions(ctx, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOptions", reflect.TypeOf((*MockProductService)(nil).FindByOptions), ctx, options)
+}
+
+// Update mocks base method.
+func (m *MockProductService) Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, request)
+	ret0, _ := ret[0].(web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductServiceMockRecorder) Update(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductService)(nil).Update), ctx, request)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/return_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReturnService is a mock of ReturnService interface.
+type MockReturnService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReturnServiceMockRecorder
+}
+
+// MockReturnServiceMockRecorder is the mock recorder for MockReturnService.
+type MockReturnServiceMockRecorder struct {
+	mock *MockReturnService
+}
+
+// NewMockReturnService creates a new mock instance.
+func NewMockReturnService(ctrl *gomock.Controller) *MockReturnService {
+	mock := &MockReturnService{ctrl: ctrl}
+	mock.recorder = &MockReturnServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReturnService) EXPECT() *MockReturnServiceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockReturnService) Approve(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest) (web.ReturnResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, principal, request)
+	ret0, _ := ret[0].(web.ReturnResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockReturnServiceMockRecorder) Approve(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockReturnService)(nil).Approve), ctx, principal, request)
+}
+
+// Create mocks base method.
+func (m *MockReturnService) Create(ctx context.Context, principal auth.Principal, request web.ReturnCreateRequest) (web.ReturnResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, principal, request)
+	ret0, _ := ret[0].(web.ReturnResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReturnServiceMockRecorder) Create(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReturnService)(nil).Create), ctx, principal, request)
+}
+
+// FindAll mocks base method.
+func (m *MockReturnService) FindAll(ctx context.Context, storeId, status string) ([]web.ReturnResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, status)
+	ret0, _ := ret[0].([]web.ReturnResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockReturnServiceMockRecorder) FindAll(ctx, storeId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockReturnService)(nil).FindAll), ctx, storeId, status)
+}
+
+// FindById mocks base method.
+func (m *MockReturnService) FindById(ctx context.Context, principal auth.Principal, returnId string) (web.ReturnResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, principal, returnId)
+	ret0, _ := ret[0].(web.ReturnResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockReturnServiceMockRecorder) FindById(ctx, principal, returnId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockReturnService)(nil).FindById), ctx, principal, returnId)
+}
+
+// Reject mocks base method.
+func (m *MockReturnService) Reject(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest) (web.ReturnResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, principal, request)
+	ret0, _ := ret[0].(web.ReturnResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockReturnServiceMockRecorder) Reject(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockReturnService)(nil).Reject), ctx, principal, request)
+}
@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/stock_transfer_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStockTransferService is a mock of StockTransferService interface.
+type MockStockTransferService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockTransferServiceMockRecorder
+}
+
+// MockStockTransferServiceMockRecorder is the mock recorder for MockStockTransferService.
+type MockStockTransferServiceMockRecorder struct {
+	mock *MockStockTransferService
+}
+
+// NewMockStockTransferService creates a new mock instance.
+func NewMockStockTransferService(ctrl *gomock.Controller) *MockStockTransferService {
+	mock := &MockStockTransferService{ctrl: ctrl}
+	mock.recorder = &MockStockTransferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockTransferService) EXPECT() *MockStockTransferServiceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockStockTransferService) Cancel(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, principal, transferId)
+	ret0, _ := ret[0].(web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockStockTransferServiceMockRecorder) Cancel(ctx, principal, transferId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockStockTransferService)(nil).Cancel), ctx, principal, transferId)
+}
+
+// Create mocks base method.
+func (m *MockStockTransferService) Create(ctx context.Context, principal auth.Principal, request web.StockTransferCreateRequest) (web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, principal, request)
+	ret0, _ := ret[0].(web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStockTransferServiceMockRecorder) Create(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStockTransferService)(nil).Create), ctx, principal, request)
+}
+
+// Dispatch mocks base method.
+func (m *MockStockTransferService) Dispatch(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", ctx, principal, transferId)
+	ret0, _ := ret[0].(web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockStockTransferServiceMockRecorder) Dispatch(ctx, principal, transferId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockStockTransferService)(nil).Dispatch), ctx, principal, transferId)
+}
+
+// FindAll mocks base method.
+func (m *MockStockTransferService) FindAll(ctx context.Context, storeId, status string) ([]web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, storeId, status)
+	ret0, _ := ret[0].([]web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockStockTransferServiceMockRecorder) FindAll(ctx, storeId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockStockTransferService)(nil).FindAll), ctx, storeId, status)
+}
+
+// FindById mocks base method.
+func (m *MockStockTransferService) FindById(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, principal, transferId)
+	ret0, _ := ret[0].(web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStockTransferServiceMockRecorder) FindById(ctx, principal, transferId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStockTransferService)(nil).FindById), ctx, principal, transferId)
+}
+
+// Receive mocks base method.
+func (m *MockStockTransferService) Receive(ctx context.Context, principal auth.Principal, request web.StockTransferReceiveRequest) (web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receive", ctx, principal, request)
+	ret0, _ := ret[0].(web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Receive indicates an expected call of Receive.
+func (mr *MockStockTransferServiceMockRecorder) Receive(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receive", reflect.TypeOf((*MockStockTransferService)(nil).Receive), ctx, principal, request)
+}
+
+// Update mocks base method.
+func (m *MockStockTransferService) Update(ctx context.Context, principal auth.Principal, request web.StockTransferUpdateRequest) (web.StockTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, principal, request)
+	ret0, _ := ret[0].(web.StockTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStockTransferServiceMockRecorder) Update(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStockTransferService)(nil).Update), ctx, principal, request)
+}
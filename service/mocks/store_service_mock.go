@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/store_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStoreService is a mock of StoreService interface.
+type MockStoreService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreServiceMockRecorder
+}
+
+// MockStoreServiceMockRecorder is the mock recorder for MockStoreService.
+type MockStoreServiceMockRecorder struct {
+	mock *MockStoreService
+}
+
+// NewMockStoreService creates a new mock instance.
+func NewMockStoreService(ctrl *gomock.Controller) *MockStoreService {
+	mock := &MockStoreService{ctrl: ctrl}
+	mock.recorder = &MockStoreServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreService) EXPECT() *MockStoreServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockStoreService) Create(ctx context.Context, principal auth.Principal, request web.StoreCreateRequest) (web.StoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, principal, request)
+	ret0, _ := ret[0].(web.StoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStoreServiceMockRecorder) Create(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStoreService)(nil).Create), ctx, principal, request)
+}
+
+// Delete mocks base method.
+func (m *MockStoreService) Delete(ctx context.Context, principal auth.Principal, storeId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, principal, storeId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStoreServiceMockRecorder) Delete(ctx, principal, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStoreService)(nil).Delete), ctx, principal, storeId)
+}
+
+// FindAll mocks base method.
+func (m *MockStoreService) FindAll(ctx context.Context, principal auth.Principal) ([]web.StoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, principal)
+	ret0, _ := ret[0].([]web.StoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockStoreServiceMockRecorder) FindAll(ctx, principal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockStoreService)(nil).FindAll), ctx, principal)
+}
+
+// FindById mocks base method.
+func (m *MockStoreService) FindById(ctx context.Context, principal auth.Principal, storeId string) (web.StoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, principal, storeId)
+	ret0, _ := ret[0].(web.StoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockStoreServiceMockRecorder) FindById(ctx, principal, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockStoreService)(nil).FindById), ctx, principal, storeId)
+}
+
+// FindEmployee mocks base method.
+func (m *MockStoreService) FindEmployee(ctx context.Context, storeId, employeeId string) (web.EmployeeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEmployee", ctx, storeId, employeeId)
+	ret0, _ := ret[0].(web.EmployeeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEmployee indicates an expected call of FindEmployee.
+func (mr *MockStoreServiceMockRecorder) FindEmployee(ctx, storeId, employeeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEmployee", reflect.TypeOf((*MockStoreService)(nil).FindEmployee), ctx, storeId, employeeId)
+}
+
+// FindEmployees mocks base method.
+func (m *MockStoreService) FindEmployees(ctx context.Context, storeId string) ([]web.EmployeeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEmployees", ctx, storeId)
+	ret0, _ := ret[0].([]web.EmployeeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEmployees indicates an expected call of FindEmployees.
+func (mr *MockStoreServiceMockRecorder) FindEmployees(ctx, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEmployees", reflect.TypeOf((*MockStoreService)(nil).FindEmployees), ctx, storeId)
+}
+
+// FindProduct mocks base method.
+func (m *MockStoreService) FindProduct(ctx context.Context, storeId, productId string) (web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProduct", ctx, storeId, productId)
+	ret0, _ := ret[0].(web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProduct indicates an expected call of FindProduct.
+func (mr *MockStoreServiceMockRecorder) FindProduct(ctx, storeId, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProduct", reflect.TypeOf((*MockStoreService)(nil).FindProduct), ctx, storeId, productId)
+}
+
+// FindProducts mocks base method.
+func (m *MockStoreService) FindProducts(ctx context.Context, storeId string, options map[string]string) ([]web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProducts", ctx, storeId, options)
+	ret0, _ := ret[0].([]web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProducts indicates an expected call of FindProducts.
+func (mr *MockStoreServiceMockRecorder) FindProducts(ctx, storeId, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProducts", reflect.TypeOf((*MockStoreService)(nil).FindProducts), ctx, storeId, options)
+}
+
+// FindStock mocks base method.
+func (m *MockStoreService) FindStock(ctx context.Context, principal auth.Principal, storeId string) ([]web.StoreStockResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStock", ctx, principal, storeId)
+	ret0, _ := ret[0].([]web.StoreStockResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStock indicates an expected call of FindStock.
+func (mr *MockStoreServiceMockRecorder) FindStock(ctx, principal, storeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStock", reflect.TypeOf((*MockStoreService)(nil).FindStock), ctx, principal, storeId)
+}
+
+// Update mocks base method.
+func (m *MockStoreService) Update(ctx context.Context, principal auth.Principal, request web.StoreUpdateRequest) (web.StoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, principal, request)
+	ret0, _ := ret[0].(web.StoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStoreServiceMockRecorder) Update(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStoreService)(nil).Update), ctx, principal, request)
+}
+
+// UpdateStock mocks base method.
+func (m *MockStoreService) UpdateStock(ctx context.Context, principal auth.Principal, request web.StoreStockUpdateRequest) (web.StoreStockResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStock", ctx, principal, request)
+	ret0, _ := ret[0].(web.StoreStockResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStock indicates an expected call of UpdateStock.
+func (mr *MockStoreServiceMockRecorder) UpdateStock(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockStoreService)(nil).UpdateStock), ctx, principal, request)
+}
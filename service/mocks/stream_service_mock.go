@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/stream_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/aronipurwanto/go-restful-api/auth"
+	event "github.com/aronipurwanto/go-restful-api/event"
+	stream "github.com/aronipurwanto/go-restful-api/stream"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStreamService is a mock of StreamService interface.
+type MockStreamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamServiceMockRecorder
+}
+
+// MockStreamServiceMockRecorder is the mock recorder for MockStreamService.
+type MockStreamServiceMockRecorder struct {
+	mock *MockStreamService
+}
+
+// NewMockStreamService creates a new mock instance.
+func NewMockStreamService(ctrl *gomock.Controller) *MockStreamService {
+	mock := &MockStreamService{ctrl: ctrl}
+	mock.recorder = &MockStreamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamService) EXPECT() *MockStreamServiceMockRecorder {
+	return m.recorder
+}
+
+// Replay mocks base method.
+func (m *MockStreamService) Replay(ctx context.Context, topics []string, sequence uint64, limit int) ([]event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, topics, sequence, limit)
+	ret0, _ := ret[0].([]event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockStreamServiceMockRecorder) Replay(ctx, topics, sequence, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockStreamService)(nil).Replay), ctx, topics, sequence, limit)
+}
+
+// Subscribe mocks base method.
+func (m *MockStreamService) Subscribe(ctx context.Context, principal auth.Principal, topics string) (*stream.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, principal, topics)
+	ret0, _ := ret[0].(*stream.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockStreamServiceMockRecorder) Subscribe(ctx, principal, topics interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockStreamService)(nil).Subscribe), ctx, principal, topics)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+)
+
+// OrderService records the sales of the stores. An order is priced like a quote of its store
+// and requires the permission of the store.
+type OrderService interface {
+	Create(ctx context.Context, principal auth.Principal, request web.OrderCreateRequest) (web.OrderResponse, error)
+	FindById(ctx context.Context, principal auth.Principal, orderId string) (web.OrderResponse, error)
+	// FindAll lists the orders of a store, of every store when storeId is empty
+	FindAll(ctx context.Context, storeId string) ([]web.OrderResponse, error)
+	// Receipt returns the receipt of an order with the receipt settings of its store
+	Receipt(ctx context.Context, principal auth.Principal, orderId string) (receipt.Receipt, error)
+}
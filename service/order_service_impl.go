@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/qris"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// pointValue is the amount of a sale that earns a customer one loyalty point
+var pointValue = money.IDR(10000)
+
+type OrderServiceImpl struct {
+	PricingService       PricingService
+	OrderRepository      repository.OrderRepository
+	EmployeeRepository   repository.EmployeeRepository
+	CustomerRepository   repository.CustomerRepository
+	PromotionRepository  repository.PromotionRepository
+	StoreRepository      repository.StoreRepository
+	StoreStockRepository repository.StoreStockRepository
+	Merchant             qris.Merchant
+	Transactor           repository.Transactor
+	Events               event.Publisher
+	Validate             *validator.Validate
+}
+
+func NewOrderService(pricingService PricingService, orderRepository repository.OrderRepository, employeeRepository repository.EmployeeRepository, customerRepository repository.CustomerRepository, promotionRepository repository.PromotionRepository, storeRepository repository.StoreRepository, storeStockRepository repository.StoreStockRepository, merchant qris.Merchant, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) OrderService {
+	return &OrderServiceImpl{
+		PricingService:       pricingService,
+		OrderRepository:      orderRepository,
+		EmployeeRepository:   employeeRepository,
+		CustomerRepository:   customerRepository,
+		PromotionRepository:  promotionRepository,
+		StoreRepository:      storeRepository,
+		StoreStockRepository: storeStockRepository,
+		Merchant:             merchant,
+		Transactor:           transactor,
+		Events:               events,
+		Validate:             validate,
+	}
+}
+
+// Create an order at the prices of a quote of the store. The sold quantities leave the stock of
+// the store, the promotions applied count against their usage limit and the customer earns a
+// loyalty point for every Rp 10.000 of the total.
+func (service *OrderServiceImpl) Create(ctx context.Context, principal auth.Principal, request web.OrderCreateRequest) (web.OrderResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.OrderResponse{}, err
+	}
+	if request.StoreID == "" {
+		return web.OrderResponse{}, exception.NewBadRequestError("an order is recorded in a store, a store context is required")
+	}
+	if err := requireStore(principal, request.StoreID); err != nil {
+		return web.OrderResponse{}, err
+	}
+	if _, err := service.EmployeeRepository.FindById(ctx, request.EmployeeID); errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.OrderResponse{}, exception.NewBadRequestError(fmt.Sprintf("unknown employee %s", request.EmployeeID))
+	} else if err != nil {
+		return web.OrderResponse{}, err
+	}
+
+	quote, err := service.PricingService.Quote(ctx, web.QuoteRequest{StoreID: request.StoreID, CustomerID: request.CustomerID, Lines: request.Lines})
+	if err != nil {
+		return web.OrderResponse{}, err
+	}
+	order := toOrder(request, quote)
+	if request.CustomerID != "" {
+		order.PointsEarned = int(order.Total.Amount() / pointValue.Amount())
+	}
+
+	var response web.OrderResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		quantities := soldQuantities(order.Lines)
+		for _, line := range order.Lines {
+			item := stockKey{line.ProductID, line.VariantID}
+			quantity, ok := quantities[item]
+			if !ok {
+				continue
+			}
+			delete(quantities, item)
+			if err := service.takeStock(ctx, order.StoreID, line.ProductID, line.VariantID, quantity); err != nil {
+				return err
+			}
+		}
+		for _, promotion := range order.Promotions {
+			ok, err := service.PromotionRepository.IncrementUsage(ctx, promotion.PromotionID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return exception.NewBadRequestError(fmt.Sprintf("promotion %s reached its usage limit", promotion.PromotionID))
+			}
+		}
+		if order.CustomerID != "" {
+			balance, err := service.earnPoints(ctx, order.CustomerID, order.PointsEarned)
+			if err != nil {
+				return err
+			}
+			order.PointsBalance = balance
+		}
+
+		savedOrder, err := service.OrderRepository.Save(ctx, order)
+		if err != nil {
+			return err
+		}
+		response = helper.ToOrderResponse(savedOrder)
+		return service.Events.Publish(ctx, event.OrderCreated, response)
+	})
+	if err != nil {
+		return web.OrderResponse{}, err
+	}
+	return response, nil
+}
+
+// FindById returns an order of a store the principal is granted
+func (service *OrderServiceImpl) FindById(ctx context.Context, principal auth.Principal, orderId string) (web.OrderResponse, error) {
+	order, err := service.findOrder(ctx, principal, orderId)
+	if err != nil {
+		return web.OrderResponse{}, err
+	}
+	return helper.ToOrderResponse(order), nil
+}
+
+// FindAll lists the orders of a store, newest first
+func (service *OrderServiceImpl) FindAll(ctx context.Context, storeId string) ([]web.OrderResponse, error) {
+	orders, err := service.OrderRepository.FindAll(ctx, storeId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToOrderResponses(orders), nil
+}
+
+// Receipt returns the receipt of an order: the name, the address and the phone of the store
+// followed by its receipt header, the names of the cashier and the customer, and the receipt
+// footer and QR code of the store. In the QR code {order_id} stands for the order ID and {qris}
+// for the QRIS payload paying the order.
+func (service *OrderServiceImpl) Receipt(ctx context.Context, principal auth.Principal, orderId string) (receipt.Receipt, error) {
+	order, err := service.findOrder(ctx, principal, orderId)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+	store, err := service.StoreRepository.FindById(ctx, order.StoreID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	result := receipt.Receipt{
+		Number:        orderNumber(order.OrderID),
+		Date:          order.CreatedAt,
+		Cashier:       order.EmployeeID,
+		PriceMode:     order.PriceMode,
+		Subtotal:      order.Subtotal,
+		Discount:      order.Discount,
+		Tax:           order.Tax,
+		Total:         order.Total,
+		PointsEarned:  order.PointsEarned,
+		PointsBalance: order.PointsBalance,
+		Footer:        receiptLines(store.ReceiptFooter),
+		QR:            strings.ReplaceAll(store.ReceiptQR, "{order_id}", order.OrderID),
+	}
+	if strings.Contains(result.QR, "{qris}") {
+		payload, err := qris.Encode(orderPayload(service.Merchant, order))
+		if err != nil {
+			return receipt.Receipt{}, err
+		}
+		result.QR = strings.ReplaceAll(result.QR, "{qris}", payload)
+	}
+	for _, line := range []string{store.Name, store.Address, store.Phone} {
+		if line != "" {
+			result.Header = append(result.Header, line)
+		}
+	}
+	result.Header = append(result.Header, receiptLines(store.ReceiptHeader)...)
+
+	// the names are those of today, the order keeps the IDs of an employee or a customer deleted since
+	if employee, err := service.EmployeeRepository.FindById(ctx, order.EmployeeID); err == nil {
+		result.Cashier = employee.Name
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return receipt.Receipt{}, err
+	}
+	if order.CustomerID != "" {
+		result.Customer = order.CustomerID
+		if customer, err := service.CustomerRepository.FindById(ctx, order.CustomerID); err == nil {
+			result.Customer = customer.Name
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return receipt.Receipt{}, err
+		}
+	}
+
+	for _, line := range order.Lines {
+		result.Lines = append(result.Lines, receipt.Line{Name: line.Name, Quantity: line.Quantity, UnitPrice: line.UnitPrice, Subtotal: line.Subtotal, Discount: line.Discount})
+	}
+	for _, amount := range order.Taxes {
+		result.Taxes = append(result.Taxes, receipt.Tax{Name: amount.Name, Base: amount.Base, Tax: amount.Tax})
+	}
+	return result, nil
+}
+
+func (service *OrderServiceImpl) findOrder(ctx context.Context, principal auth.Principal, orderId string) (domain.Order, error) {
+	order, err := service.OrderRepository.FindById(ctx, orderId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Order{}, exception.NewNotFoundError("Order not found")
+	} else if err != nil {
+		return domain.Order{}, err
+	}
+	if err := requireStore(principal, order.StoreID); err != nil {
+		return domain.Order{}, err
+	}
+	return order, nil
+}
+
+func (service *OrderServiceImpl) takeStock(ctx context.Context, storeId string, productId string, variantId string, quantity int) error {
+	ok, err := adjustStoreStock(ctx, service.StoreStockRepository, service.Events, storeId, productId, variantId, -quantity)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return exception.NewBadRequestError(fmt.Sprintf("insufficient stock of %s in store %s", stockItem(productId, variantId), storeId))
+	}
+	return nil
+}
+
+// earnPoints adds points to a customer and returns their balance. An order earning no points
+// does not update the customer: MySQL counts an update changing nothing as no row affected.
+func (service *OrderServiceImpl) earnPoints(ctx context.Context, customerId string, points int) (int, error) {
+	if points != 0 {
+		ok, err := service.CustomerRepository.AdjustLoyaltyPoints(ctx, customerId, points)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, exception.NewBadRequestError(fmt.Sprintf("unknown customer %s", customerId))
+		}
+	}
+	customer, err := service.CustomerRepository.FindById(ctx, customerId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, exception.NewBadRequestError(fmt.Sprintf("unknown customer %s", customerId))
+	} else if err != nil {
+		return 0, err
+	}
+	return customer.LoyaltyPts, nil
+}
+
+func toOrder(request web.OrderCreateRequest, quote web.QuoteResponse) domain.Order {
+	order := domain.Order{
+		StoreID:    request.StoreID,
+		EmployeeID: request.EmployeeID,
+		CustomerID: request.CustomerID,
+		PriceMode:  quote.PriceMode,
+		Currency:   quote.Currency,
+		Subtotal:   quote.Subtotal,
+		Discount:   quote.Discount,
+		Tax:        quote.Tax,
+		Total:      quote.Total,
+		Taxes:      make([]domain.OrderTax, 0, len(quote.Taxes)),
+		Promotions: make([]domain.OrderPromotion, 0, len(quote.Promotions)),
+	}
+	for i, line := range quote.Lines {
+		order.Lines = append(order.Lines, domain.OrderLine{
+			Position:   i + 1,
+			ProductID:  line.ProductID,
+			VariantID:  line.VariantID,
+			Name:       line.Name,
+			TaxClassID: line.TaxClass,
+			UnitPrice:  line.UnitPrice,
+			Quantity:   line.Quantity,
+			Subtotal:   line.Subtotal,
+			Discount:   line.Discount,
+			Tax:        line.Tax,
+			Total:      line.Total,
+		})
+	}
+	for _, amount := range quote.Taxes {
+		order.Taxes = append(order.Taxes, domain.OrderTax{Code: amount.Code, Name: amount.Name, Percent: amount.Percent, Base: amount.Base, Tax: amount.Tax})
+	}
+	for _, promotion := range quote.Promotions {
+		order.Promotions = append(order.Promotions, domain.OrderPromotion{PromotionID: promotion.PromotionID, Name: promotion.Name, Discount: promotion.Discount})
+	}
+	return order
+}
+
+// stockKey is a product, or a variant of it, holding its own stock
+type stockKey struct {
+	ProductID string
+	VariantID string
+}
+
+// soldQuantities adds up the quantities of each product and variant
+func soldQuantities(lines []domain.OrderLine) map[stockKey]int {
+	quantities := map[stockKey]int{}
+	for _, line := range lines {
+		quantities[stockKey{line.ProductID, line.VariantID}] += line.Quantity
+	}
+	return quantities
+}
+
+// orderNumber is the short number printed on the receipt, the start of the order ID
+func orderNumber(orderId string) string {
+	if len(orderId) > 8 {
+		orderId = orderId[:8]
+	}
+	return strings.ToUpper(orderId)
+}
+
+// receiptLines splits a receipt setting in its lines
+func receiptLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\r\n"), "\n") {
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,331 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/qris"
+	"github.com/aronipurwanto/go-restful-api/receipt"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	servicemocks "github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type orderMocks struct {
+	pricing    *servicemocks.MockPricingService
+	orders     *mocks.MockOrderRepository
+	employees  *mocks.MockEmployeeRepository
+	customers  *mocks.MockCustomerRepository
+	promotions *mocks.MockPromotionRepository
+	stores     *mocks.MockStoreRepository
+	stocks     *mocks.MockStoreStockRepository
+}
+
+func setupOrderService(t *testing.T, publisher *recordingPublisher) (service.OrderService, orderMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := orderMocks{
+		pricing:    servicemocks.NewMockPricingService(ctrl),
+		orders:     mocks.NewMockOrderRepository(ctrl),
+		employees:  mocks.NewMockEmployeeRepository(ctrl),
+		customers:  mocks.NewMockCustomerRepository(ctrl),
+		promotions: mocks.NewMockPromotionRepository(ctrl),
+		stores:     mocks.NewMockStoreRepository(ctrl),
+		stocks:     mocks.NewMockStoreStockRepository(ctrl),
+	}
+	orderService := service.NewOrderService(repositories.pricing, repositories.orders, repositories.employees, repositories.customers, repositories.promotions, repositories.stores, repositories.stocks, merchant, fakeTransactor{}, publisher, validator.New())
+	return orderService, repositories
+}
+
+// orderQuote prices two laptops of a variant and a coffee, the laptops with a promotion
+var orderQuote = web.QuoteResponse{
+	StoreID:    "JKT01",
+	CustomerID: "C001",
+	Lines: []web.QuoteLineResponse{
+		{ProductID: "P001", VariantID: "V1", Name: "Laptop Gaming", UnitPrice: money.IDR(15000000), Quantity: 2, Subtotal: money.IDR(30000000), Discount: money.IDR(1500000), TaxClass: "standard", Tax: money.IDR(3135000), Total: money.IDR(31635000)},
+		{ProductID: "P002", Name: "Kopi Bubuk", UnitPrice: money.IDR(35000), Quantity: 1, Subtotal: money.IDR(35000), Discount: money.IDR(0), TaxClass: "standard", Tax: money.IDR(3850), Total: money.IDR(38850)},
+	},
+	Promotions: []web.AppliedPromotionResponse{{PromotionID: "PR1", Name: "Weekend Gaming", Discount: money.IDR(1500000)}},
+	PriceMode:  "exclusive",
+	Currency:   "IDR",
+	Subtotal:   money.IDR(30035000),
+	Discount:   money.IDR(1500000),
+	Tax:        money.IDR(3138850),
+	Taxes:      []web.TaxAmountResponse{{Code: "PPN", Name: "PPN 11%", Percent: 11, Base: money.IDR(28535000), Tax: money.IDR(3138850)}},
+	Total:      money.IDR(31673850),
+}
+
+func TestCreateOrder(t *testing.T) {
+	request := web.OrderCreateRequest{
+		StoreID:    "JKT01",
+		EmployeeID: "E001",
+		CustomerID: "C001",
+		Lines:      []web.QuoteLineRequest{{ProductID: "P001", VariantID: "V1", Quantity: 2}, {ProductID: "P002", Quantity: 1}},
+	}
+	quoteRequest := web.QuoteRequest{StoreID: "JKT01", CustomerID: "C001", Lines: request.Lines}
+
+	t.Run("success", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		orderService, repositories := setupOrderService(t, publisher)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		repositories.pricing.EXPECT().Quote(gomock.Any(), quoteRequest).Return(orderQuote, nil)
+		gomock.InOrder(
+			repositories.stocks.EXPECT().AdjustVariant(gomock.Any(), "JKT01", "P001", "V1", -2).Return(true, nil),
+			repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", "P001").Return(domain.StoreStock{StoreID: "JKT01", ProductID: "P001", StockQty: 3}, nil),
+			repositories.stocks.EXPECT().FindVariants(gomock.Any(), "JKT01", "P001").Return([]domain.StoreVariantStock{{StoreID: "JKT01", VariantID: "V1", ProductID: "P001", StockQty: 3}}, nil),
+			repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", "P002", -1).Return(true, nil),
+			repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", "P002").Return(domain.StoreStock{StoreID: "JKT01", ProductID: "P002", StockQty: 40}, nil),
+		)
+		repositories.promotions.EXPECT().IncrementUsage(gomock.Any(), "PR1").Return(true, nil)
+		repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", 3167).Return(true, nil)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 3287}, nil)
+		repositories.orders.EXPECT().Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, order domain.Order) (domain.Order, error) {
+				order.OrderID = "O1"
+				return order, nil
+			})
+
+		response, err := orderService.Create(context.Background(), cashier, request)
+		require.NoError(t, err)
+		assert.Equal(t, "O1", response.OrderID)
+		assert.Equal(t, "E001", response.EmployeeID)
+		assert.Equal(t, money.IDR(31673850), response.Total)
+		assert.Equal(t, 3167, response.PointsEarned)
+		assert.Equal(t, 3287, response.PointsBalance)
+		require.Len(t, response.Lines, 2)
+		assert.Equal(t, "V1", response.Lines[0].VariantID)
+		assert.Equal(t, money.IDR(1500000), response.Lines[0].Discount)
+		assert.Equal(t, []web.AppliedPromotionResponse{{PromotionID: "PR1", Name: "Weekend Gaming", Discount: money.IDR(1500000)}}, response.Promotions)
+		assert.Equal(t, orderQuote.Taxes, response.Taxes)
+		assert.Equal(t, []string{event.StoreStockChanged, event.StoreStockChanged, event.OrderCreated}, publisher.types)
+	})
+
+	tests := []struct {
+		name      string
+		request   web.OrderCreateRequest
+		mock      func(repositories orderMocks)
+		expectErr interface{}
+	}{
+		{
+			name:      "no store context",
+			request:   web.OrderCreateRequest{EmployeeID: "E001", Lines: request.Lines},
+			mock:      func(repositories orderMocks) {},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "other store",
+			request:   web.OrderCreateRequest{StoreID: "BDG01", EmployeeID: "E001", Lines: request.Lines},
+			mock:      func(repositories orderMocks) {},
+			expectErr: &exception.ForbiddenError{},
+		},
+		{
+			name:      "no lines",
+			request:   web.OrderCreateRequest{StoreID: "JKT01", EmployeeID: "E001"},
+			mock:      func(repositories orderMocks) {},
+			expectErr: &validator.ValidationErrors{},
+		},
+		{
+			name:    "unknown employee",
+			request: request,
+			mock: func(repositories orderMocks) {
+				repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{}, gorm.ErrRecordNotFound)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:    "insufficient stock",
+			request: request,
+			mock: func(repositories orderMocks) {
+				repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+				repositories.pricing.EXPECT().Quote(gomock.Any(), quoteRequest).Return(orderQuote, nil)
+				repositories.stocks.EXPECT().AdjustVariant(gomock.Any(), "JKT01", "P001", "V1", -2).Return(false, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:    "promotion used up",
+			request: request,
+			mock: func(repositories orderMocks) {
+				repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+				repositories.pricing.EXPECT().Quote(gomock.Any(), quoteRequest).Return(orderQuote, nil)
+				repositories.stocks.EXPECT().AdjustVariant(gomock.Any(), "JKT01", "P001", "V1", -2).Return(true, nil)
+				repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", "P002", -1).Return(true, nil)
+				repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", gomock.Any()).Return(domain.StoreStock{}, nil).Times(2)
+				repositories.stocks.EXPECT().FindVariants(gomock.Any(), "JKT01", "P001").Return(nil, nil)
+				repositories.promotions.EXPECT().IncrementUsage(gomock.Any(), "PR1").Return(false, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderService, repositories := setupOrderService(t, &recordingPublisher{})
+			tt.mock(repositories)
+
+			_, err := orderService.Create(context.Background(), cashier, tt.request)
+			assert.ErrorAs(t, err, tt.expectErr)
+		})
+	}
+}
+
+func TestCreateAnonymousOrderEarnsNoPoints(t *testing.T) {
+	publisher := &recordingPublisher{}
+	orderService, repositories := setupOrderService(t, publisher)
+	quote := orderQuote
+	quote.CustomerID = ""
+	quote.Lines = quote.Lines[1:]
+	quote.Promotions = nil
+
+	repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+	repositories.pricing.EXPECT().Quote(gomock.Any(), gomock.Any()).Return(quote, nil)
+	repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", "P002", -1).Return(true, nil)
+	repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", "P002").Return(domain.StoreStock{}, nil)
+	repositories.orders.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, order domain.Order) (domain.Order, error) { return order, nil })
+
+	response, err := orderService.Create(context.Background(), admin, web.OrderCreateRequest{StoreID: "JKT01", EmployeeID: "E001", Lines: []web.QuoteLineRequest{{ProductID: "P002", Quantity: 1}}})
+	require.NoError(t, err)
+	assert.Zero(t, response.PointsEarned)
+	assert.Zero(t, response.PointsBalance)
+}
+
+func TestCreateOrderEarningNoPoints(t *testing.T) {
+	quote := orderQuote
+	quote.Lines = []web.QuoteLineResponse{{ProductID: "P003", Name: "Permen", UnitPrice: money.IDR(5000), Quantity: 1, Subtotal: money.IDR(5000), Discount: money.IDR(0), Tax: money.IDR(0), Total: money.IDR(5000)}}
+	quote.Promotions = nil
+	quote.Subtotal, quote.Discount, quote.Tax, quote.Total = money.IDR(5000), money.IDR(0), money.IDR(0), money.IDR(5000)
+	request := web.OrderCreateRequest{StoreID: "JKT01", EmployeeID: "E001", CustomerID: "C001", Lines: []web.QuoteLineRequest{{ProductID: "P003", Quantity: 1}}}
+	// expect creates the order up to the customer, whose points are not updated
+	expect := func(repositories orderMocks) {
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001"}, nil)
+		repositories.pricing.EXPECT().Quote(gomock.Any(), gomock.Any()).Return(quote, nil)
+		repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", "P003", -1).Return(true, nil)
+		repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", "P003").Return(domain.StoreStock{}, nil)
+	}
+
+	t.Run("known customer", func(t *testing.T) {
+		orderService, repositories := setupOrderService(t, &recordingPublisher{})
+		expect(repositories)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 120}, nil)
+		repositories.orders.EXPECT().Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, order domain.Order) (domain.Order, error) { return order, nil })
+
+		response, err := orderService.Create(context.Background(), cashier, request)
+		require.NoError(t, err)
+		assert.Zero(t, response.PointsEarned)
+		assert.Equal(t, 120, response.PointsBalance)
+	})
+
+	t.Run("unknown customer", func(t *testing.T) {
+		orderService, repositories := setupOrderService(t, &recordingPublisher{})
+		expect(repositories)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{}, gorm.ErrRecordNotFound)
+
+		_, err := orderService.Create(context.Background(), cashier, request)
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+}
+
+func TestOrderReceipt(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 14, 5, 0, 0, time.UTC)
+	order := domain.Order{
+		OrderID:    "1a2b3c4d-0000-4000-8000-000000000000",
+		StoreID:    "JKT01",
+		EmployeeID: "E001",
+		CustomerID: "C001",
+		PriceMode:  "exclusive",
+		Currency:   "IDR",
+		Subtotal:   money.IDR(35000),
+		Tax:        money.IDR(3850),
+		Total:      money.IDR(38850),
+		Taxes:      []domain.OrderTax{{Code: "PPN", Name: "PPN 11%", Percent: 11, Base: money.IDR(35000), Tax: money.IDR(3850)}},
+		Lines: []domain.OrderLine{
+			{Position: 1, ProductID: "P002", Name: "Kopi Bubuk", UnitPrice: money.IDR(35000), Quantity: 1, Subtotal: money.IDR(35000), Tax: money.IDR(3850), Total: money.IDR(38850)},
+		},
+		PointsEarned:  3,
+		PointsBalance: 123,
+		CreatedAt:     createdAt,
+	}
+	store := domain.Store{
+		StoreID:       "JKT01",
+		Name:          "Jakarta Pusat",
+		Address:       "Jl. Thamrin 1",
+		ReceiptHeader: "NPWP 01.234.567.8-901.000\r\n",
+		ReceiptFooter: "Terima kasih\nSampai jumpa lagi",
+		ReceiptQR:     "https://example.com/r/{order_id}",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		orderService, repositories := setupOrderService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(store, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{EmployeeID: "E001", Name: "Andi Wijaya"}, nil)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{}, gorm.ErrRecordNotFound)
+
+		result, err := orderService.Receipt(context.Background(), cashier, order.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, receipt.Receipt{
+			Header:        []string{"Jakarta Pusat", "Jl. Thamrin 1", "NPWP 01.234.567.8-901.000"},
+			Number:        "1A2B3C4D",
+			Date:          createdAt,
+			Cashier:       "Andi Wijaya",
+			Customer:      "C001",
+			Lines:         []receipt.Line{{Name: "Kopi Bubuk", Quantity: 1, UnitPrice: money.IDR(35000), Subtotal: money.IDR(35000), Discount: money.IDR(0)}},
+			PriceMode:     "exclusive",
+			Subtotal:      money.IDR(35000),
+			Tax:           money.IDR(3850),
+			Total:         money.IDR(38850),
+			Taxes:         []receipt.Tax{{Name: "PPN 11%", Base: money.IDR(35000), Tax: money.IDR(3850)}},
+			PointsEarned:  3,
+			PointsBalance: 123,
+			Footer:        []string{"Terima kasih", "Sampai jumpa lagi"},
+			QR:            "https://example.com/r/1a2b3c4d-0000-4000-8000-000000000000",
+		}, result)
+	})
+
+	t.Run("QRIS payment code", func(t *testing.T) {
+		orderService, repositories := setupOrderService(t, &recordingPublisher{})
+		qrisStore := store
+		qrisStore.ReceiptQR = "{qris}"
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(qrisStore, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{}, gorm.ErrRecordNotFound)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{}, gorm.ErrRecordNotFound)
+
+		result, err := orderService.Receipt(context.Background(), cashier, order.OrderID)
+		require.NoError(t, err)
+		payload, err := qris.Parse(result.QR)
+		require.NoError(t, err)
+		assert.Equal(t, qris.Payload{Merchant: merchant, Amount: money.IDR(38850), Reference: "1A2B3C4D"}, payload)
+	})
+
+	t.Run("other store", func(t *testing.T) {
+		orderService, repositories := setupOrderService(t, &recordingPublisher{})
+		other := order
+		other.StoreID = "BDG01"
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(other, nil)
+
+		_, err := orderService.Receipt(context.Background(), cashier, order.OrderID)
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		orderService, repositories := setupOrderService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O404").Return(domain.Order{}, errors.Join(errors.New("order is not found"), gorm.ErrRecordNotFound))
+
+		_, err := orderService.Receipt(context.Background(), cashier, "O404")
+		assert.ErrorAs(t, err, &exception.NotFoundError{})
+	})
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type PaymentService interface {
+	Create(ctx context.Context, principal auth.Principal, request web.PaymentCreateRequest) (web.PaymentResponse, error)
+	FindById(ctx context.Context, principal auth.Principal, paymentId string) (web.PaymentResponse, error)
+	FindAll(ctx context.Context, storeId string, customerId string) ([]web.PaymentResponse, error)
+	Sync(ctx context.Context, principal auth.Principal, paymentId string) (web.PaymentResponse, error)
+	Notify(ctx context.Context, provider string, header func(key string) string, body []byte) (web.PaymentNotificationResponse, error)
+	CreateQRIS(ctx context.Context, principal auth.Principal, request web.QRISCreateRequest) (web.QRISResponse, error)
+	VerifyQRIS(ctx context.Context, request web.QRISVerifyRequest) (web.QRISResponse, error)
+}
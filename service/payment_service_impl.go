@@ -0,0 +1,620 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	gateway "github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/qris"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// qrImageSize is the width in pixels of the PNG QR codes
+const qrImageSize = 512
+
+// pointRedemptionValue is what a loyalty point pays for
+var pointRedemptionValue = money.IDR(100)
+
+type PaymentServiceImpl struct {
+	OrderRepository    repository.OrderRepository
+	PaymentRepository  repository.PaymentRepository
+	CustomerRepository repository.CustomerRepository
+	Provider           gateway.Provider
+	Merchant           qris.Merchant
+	Transactor         repository.Transactor
+	Events             event.Publisher
+	Validate           *validator.Validate
+}
+
+func NewPaymentService(orderRepository repository.OrderRepository, paymentRepository repository.PaymentRepository, customerRepository repository.CustomerRepository, provider gateway.Provider, merchant qris.Merchant, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) PaymentService {
+	return &PaymentServiceImpl{
+		OrderRepository:    orderRepository,
+		PaymentRepository:  paymentRepository,
+		CustomerRepository: customerRepository,
+		Provider:           provider,
+		Merchant:           merchant,
+		Transactor:         transactor,
+		Events:             events,
+		Validate:           validate,
+	}
+}
+
+// Create pays an order with tenders adding up to its total. The points are taken from the
+// customer and the payment recorded before the gateway charges the cards, so that no charge
+// exists without its payment. A declined card, or a charge that ends in an error, fails the
+// payment: the other cards are refunded and the points given back. A card the gateway settles
+// later leaves the payment pending until its notification, or a Sync, tells how it ended. The
+// gateway is called out of the transactions.
+func (service *PaymentServiceImpl) Create(ctx context.Context, principal auth.Principal, request web.PaymentCreateRequest) (web.PaymentResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.PaymentResponse{}, err
+	}
+	order, err := service.findOrder(ctx, principal, request.OrderID)
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+	payments, err := service.PaymentRepository.FindByOrder(ctx, order.OrderID)
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+	for _, payment := range payments {
+		if payment.Status != domain.PaymentFailed {
+			return web.PaymentResponse{}, exception.NewBadRequestError(fmt.Sprintf("order %s already has a %s payment", order.OrderID, payment.Status))
+		}
+	}
+	payment, err := service.toPayment(order, request.Tenders)
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The check above is for the usual case, two requests paying the order at the same
+		// time are told apart by the save
+		saved, ok, err := service.PaymentRepository.Save(ctx, payment)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exception.NewBadRequestError(fmt.Sprintf("order %s already has a payment", order.OrderID))
+		}
+		payment = saved
+		if points := redeemedPoints(payment); points > 0 {
+			ok, err := service.CustomerRepository.AdjustLoyaltyPoints(ctx, payment.CustomerID, -points)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return exception.NewBadRequestError(fmt.Sprintf("customer %s has fewer than %d loyalty points", payment.CustomerID, points))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+
+	var failure error
+	for i, tender := range payment.Tenders {
+		if tender.Method != domain.TenderCard {
+			continue
+		}
+		charge, err := service.charge(ctx, payment, tender, order, request.Tenders[i].Token)
+		if err == nil {
+			err = service.apply(ctx, &payment, tender.Position, charge)
+		}
+		if err != nil {
+			// A charge the gateway made nonetheless is refunded with its notification
+			failure = err
+			payment.Status = domain.PaymentFailed
+			break
+		}
+	}
+	// The failure is recorded even when a card could not be given back, the error is returned
+	// after
+	voidErr := service.conclude(ctx, &payment)
+
+	var response web.PaymentResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.restore(ctx, &payment); err != nil {
+			return err
+		}
+		updated, ok, err := service.PaymentRepository.Update(ctx, payment)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("payment %s was changed by a notification meanwhile", payment.PaymentID)
+		}
+		response = helper.ToPaymentResponse(updated)
+		if updated.Status == domain.PaymentFailed {
+			return service.Events.Publish(ctx, event.PaymentFailed, response)
+		}
+		return service.Events.Publish(ctx, event.PaymentCreated, response)
+	})
+	if err = errors.Join(err, failure, voidErr); err != nil {
+		return web.PaymentResponse{}, err
+	}
+	if response.Status == domain.PaymentFailed {
+		return web.PaymentResponse{}, exception.NewBadRequestError(fmt.Sprintf("payment %s failed: %s", response.PaymentID, declineReason(payment)))
+	}
+	return response, nil
+}
+
+// FindById returns a payment of a store the principal is granted
+func (service *PaymentServiceImpl) FindById(ctx context.Context, principal auth.Principal, paymentId string) (web.PaymentResponse, error) {
+	payment, err := service.findPayment(ctx, principal, paymentId)
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+	return helper.ToPaymentResponse(payment), nil
+}
+
+// FindAll lists the payments of a store and of a customer, newest first
+func (service *PaymentServiceImpl) FindAll(ctx context.Context, storeId string, customerId string) ([]web.PaymentResponse, error) {
+	payments, err := service.PaymentRepository.FindAll(ctx, storeId, customerId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToPaymentResponses(payments), nil
+}
+
+// Sync asks the gateway the status of the pending cards of a payment, for when a notification
+// is late or lost
+func (service *PaymentServiceImpl) Sync(ctx context.Context, principal auth.Principal, paymentId string) (web.PaymentResponse, error) {
+	payment, err := service.findPayment(ctx, principal, paymentId)
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+
+	previous := payment.Status
+	for _, tender := range payment.Tenders {
+		if tender.Method != domain.TenderCard || tender.Status != gateway.StatusPending || tender.ChargeID == "" {
+			continue
+		}
+		charge, err := service.Provider.Status(ctx, tender.ChargeID)
+		if err != nil {
+			return web.PaymentResponse{}, err
+		}
+		if err := service.apply(ctx, &payment, tender.Position, charge); err != nil {
+			return web.PaymentResponse{}, err
+		}
+	}
+	if err := service.conclude(ctx, &payment); err != nil {
+		return web.PaymentResponse{}, err
+	}
+
+	var response web.PaymentResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		response, err = service.save(ctx, payment, previous)
+		return err
+	})
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+	return response, nil
+}
+
+// Notify processes a notification posted by a gateway on its webhook. The gateway signs the
+// notification and dates it, and a notification processed before is acknowledged without
+// being processed again.
+func (service *PaymentServiceImpl) Notify(ctx context.Context, provider string, header func(key string) string, body []byte) (web.PaymentNotificationResponse, error) {
+	if provider != service.Provider.Name() {
+		return web.PaymentNotificationResponse{}, exception.NewNotFoundError(fmt.Sprintf("unknown payment provider %s", provider))
+	}
+	notification, err := service.Provider.ParseNotification(header, body)
+	if err != nil {
+		return web.PaymentNotificationResponse{}, err
+	}
+
+	// The gateway is called before the transaction, a duplicate finds its tender settled
+	// already and calls nothing
+	payment, position, err := service.findTender(ctx, notification)
+	if err != nil {
+		return web.PaymentNotificationResponse{}, err
+	}
+	previous := payment.Status
+	charge := gateway.Charge{ID: notification.ChargeID, Key: notification.Key, Status: notification.Status, DeclineReason: notification.DeclineReason}
+	if err := service.apply(ctx, &payment, position, charge); err != nil {
+		return web.PaymentNotificationResponse{}, err
+	}
+	if err := service.conclude(ctx, &payment); err != nil {
+		return web.PaymentNotificationResponse{}, err
+	}
+
+	response := web.PaymentNotificationResponse{NotificationID: notification.ID}
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := service.PaymentRepository.SaveNotification(ctx, domain.PaymentNotification{
+			Provider:       provider,
+			NotificationID: notification.ID,
+			ChargeID:       notification.ChargeID,
+			Status:         notification.Status,
+			ReceivedAt:     time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			response.Duplicate = true
+			return nil
+		}
+		response.PaymentID = payment.PaymentID
+		_, err = service.save(ctx, payment, previous)
+		return err
+	})
+	if err != nil {
+		return web.PaymentNotificationResponse{}, err
+	}
+	return response, nil
+}
+
+// CreateQRIS returns the QRIS payload paying the total of an order, an amount, or any amount
+// the customer enters when neither is given
+func (service *PaymentServiceImpl) CreateQRIS(ctx context.Context, principal auth.Principal, request web.QRISCreateRequest) (web.QRISResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.QRISResponse{}, err
+	}
+	if request.OrderID != "" && request.Amount != nil {
+		return web.QRISResponse{}, exception.NewBadRequestError("order_id and amount are exclusive")
+	}
+
+	payload := qris.Payload{Merchant: service.Merchant, Amount: money.IDR(0), Reference: request.Reference}
+	if request.Amount != nil {
+		if !request.Amount.IsPositive() {
+			return web.QRISResponse{}, exception.NewBadRequestError("amount must be positive")
+		}
+		payload.Amount = *request.Amount
+	}
+	if request.OrderID != "" {
+		order, err := service.OrderRepository.FindById(ctx, request.OrderID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.QRISResponse{}, exception.NewNotFoundError("Order not found")
+		} else if err != nil {
+			return web.QRISResponse{}, err
+		}
+		if err := requireStore(principal, order.StoreID); err != nil {
+			return web.QRISResponse{}, err
+		}
+		payload = orderPayload(service.Merchant, order)
+		if request.Reference != "" {
+			payload.Reference = request.Reference
+		}
+	}
+
+	text, err := qris.Encode(payload)
+	if err != nil {
+		return web.QRISResponse{}, exception.NewBadRequestError(err.Error())
+	}
+	response := helper.ToQRISResponse(text, payload, service.Merchant)
+	switch request.Image {
+	case "png":
+		image, err := qris.PNG(text, qrImageSize)
+		if err != nil {
+			return web.QRISResponse{}, err
+		}
+		response.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(image)
+	case "svg":
+		image, err := qris.SVG(text)
+		if err != nil {
+			return web.QRISResponse{}, err
+		}
+		response.Image = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(image))
+	}
+	return response, nil
+}
+
+// VerifyQRIS reads a payload scanned back, a payload that does not check out is a bad request
+func (service *PaymentServiceImpl) VerifyQRIS(ctx context.Context, request web.QRISVerifyRequest) (web.QRISResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.QRISResponse{}, err
+	}
+	payload, err := qris.Parse(request.Payload)
+	if err != nil {
+		return web.QRISResponse{}, exception.NewBadRequestError("invalid QRIS payload: " + err.Error())
+	}
+	return helper.ToQRISResponse(request.Payload, payload, service.Merchant), nil
+}
+
+// toPayment checks the tenders of a payment of order: they add up to its total, cash covers
+// its amount and points are those of the customer of the order. Cash settles what the other
+// tenders leave, exactly or rounded to the smallest amount paid in cash: Rp 35.050 left is also
+// settled with Rp 35.100. The payment amount is what the tenders collected.
+func (service *PaymentServiceImpl) toPayment(order domain.Order, tenders []web.TenderRequest) (domain.Payment, error) {
+	zero := money.New(0, order.Total.Currency())
+	payment := domain.Payment{
+		OrderID:    order.OrderID,
+		StoreID:    order.StoreID,
+		CustomerID: order.CustomerID,
+		Amount:     order.Total,
+		Status:     domain.PaymentPending,
+	}
+	paid, other, cash := zero, zero, false
+	for i, request := range tenders {
+		tender := domain.PaymentTender{Position: i + 1, Method: request.Method, Amount: zero, Tendered: zero, Change: zero, Refunded: zero, Status: gateway.StatusCaptured}
+		if request.Method == domain.TenderPoints {
+			if order.CustomerID == "" {
+				return domain.Payment{}, exception.NewBadRequestError("points are redeemed by the customer of the order, the order has none")
+			}
+			if request.Points == 0 {
+				return domain.Payment{}, exception.NewBadRequestError(fmt.Sprintf("tender %d redeems no points", tender.Position))
+			}
+			tender.Points = request.Points
+			tender.Amount = pointRedemptionValue.Mul(int64(request.Points))
+		} else {
+			if request.Amount == nil || !request.Amount.IsPositive() {
+				return domain.Payment{}, exception.NewBadRequestError(fmt.Sprintf("tender %d needs a positive amount", tender.Position))
+			}
+			tender.Amount = *request.Amount
+		}
+
+		switch request.Method {
+		case domain.TenderCash:
+			cash = true
+			tender.Tendered = tender.Amount
+			if request.Tendered != nil {
+				tender.Tendered = *request.Tendered
+			}
+			if tender.Tendered.Cmp(tender.Amount) < 0 {
+				return domain.Payment{}, exception.NewBadRequestError(fmt.Sprintf("tender %d: %s tendered for %s", tender.Position, tender.Tendered, tender.Amount))
+			}
+			tender.Change = tender.Tendered.Sub(tender.Amount)
+		case domain.TenderCard:
+			if request.Token == "" {
+				return domain.Payment{}, exception.NewBadRequestError(fmt.Sprintf("tender %d needs the token of the card", tender.Position))
+			}
+			tender.Provider = service.Provider.Name()
+			tender.Status = gateway.StatusPending
+		}
+		if request.Method != domain.TenderCash {
+			other = other.Add(tender.Amount)
+		}
+		paid = paid.Add(tender.Amount)
+		payment.Tenders = append(payment.Tenders, tender)
+	}
+	rounded := order.Total
+	if cash {
+		rounded = other.Add(order.Total.Sub(other).CashRound())
+	}
+	if paid.Cmp(order.Total) != 0 && paid.Cmp(rounded) != 0 {
+		if rounded.Cmp(order.Total) != 0 {
+			return domain.Payment{}, exception.NewBadRequestError(fmt.Sprintf("the tenders add up to %s, the order total is %s, %s with cash rounding", paid, order.Total, rounded))
+		}
+		return domain.Payment{}, exception.NewBadRequestError(fmt.Sprintf("the tenders add up to %s, the order total is %s", paid, order.Total))
+	}
+	payment.Amount = paid
+	return payment, nil
+}
+
+// charge authorizes a card tender. The key of the tender makes it safe to retry a request that
+// timed out; when the retry times out too the tender stays pending without a charge, the
+// notification of the gateway will tell.
+func (service *PaymentServiceImpl) charge(ctx context.Context, payment domain.Payment, tender domain.PaymentTender, order domain.Order, token string) (gateway.Charge, error) {
+	request := gateway.ChargeRequest{
+		Key:        chargeKey(payment.PaymentID, tender.Position),
+		Reference:  orderNumber(order.OrderID),
+		Amount:     tender.Amount,
+		Token:      token,
+		CustomerID: payment.CustomerID,
+	}
+	charge, err := service.Provider.CreateCharge(ctx, request)
+	if errors.Is(err, gateway.ErrTimeout) {
+		charge, err = service.Provider.CreateCharge(ctx, request)
+	}
+	if errors.Is(err, gateway.ErrTimeout) {
+		return gateway.Charge{Key: request.Key, Status: gateway.StatusPending}, nil
+	}
+	return charge, err
+}
+
+// apply records the status of the charge of a tender, capturing it once authorized. A charge
+// captured after its payment failed is refunded.
+func (service *PaymentServiceImpl) apply(ctx context.Context, payment *domain.Payment, position int, charge gateway.Charge) error {
+	tender := &payment.Tenders[position-1]
+	if tender.Status != gateway.StatusPending {
+		return nil
+	}
+	if charge.ID != "" {
+		tender.ChargeID = charge.ID
+	}
+	if charge.Status == gateway.StatusAuthorized {
+		captured, err := service.Provider.Capture(ctx, charge.ID)
+		if err != nil {
+			return err
+		}
+		charge = captured
+	}
+	tender.Status = charge.Status
+	tender.DeclineReason = charge.DeclineReason
+	if payment.Status == domain.PaymentFailed && tender.Status == gateway.StatusCaptured {
+		return service.refund(ctx, tender)
+	}
+	return nil
+}
+
+// conclude sets the status of a pending payment from those of its tenders. A payment fails with
+// its first declined card, the cards already charged are then given back: every card is tried
+// and the errors of those the gateway kept are returned. It only calls the gateway, restore
+// gives the other tenders back when the payment is saved.
+func (service *PaymentServiceImpl) conclude(ctx context.Context, payment *domain.Payment) error {
+	if payment.Status == domain.PaymentPending {
+		status := domain.PaymentPaid
+		for _, tender := range payment.Tenders {
+			if tender.Status == gateway.StatusDeclined {
+				status = domain.PaymentFailed
+				break
+			}
+			if tender.Status == gateway.StatusPending {
+				status = domain.PaymentPending
+			}
+		}
+		payment.Status = status
+	}
+	if payment.Status != domain.PaymentFailed {
+		return nil
+	}
+	var errs []error
+	for i := range payment.Tenders {
+		tender := &payment.Tenders[i]
+		if tender.Method == domain.TenderCard && tender.ChargeID != "" && (tender.Status == gateway.StatusCaptured || tender.Status == gateway.StatusPending) {
+			errs = append(errs, service.refund(ctx, tender))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restore gives the points and the cash of a failed payment back, within the transaction that
+// saves it
+func (service *PaymentServiceImpl) restore(ctx context.Context, payment *domain.Payment) error {
+	if payment.Status != domain.PaymentFailed {
+		return nil
+	}
+	for i := range payment.Tenders {
+		tender := &payment.Tenders[i]
+		switch {
+		case tender.Method == domain.TenderPoints && tender.Status == gateway.StatusCaptured:
+			if _, err := service.CustomerRepository.AdjustLoyaltyPoints(ctx, payment.CustomerID, tender.Points); err != nil {
+				return err
+			}
+			tender.Status = gateway.StatusRefunded
+		case tender.Method == domain.TenderCash && tender.Status == gateway.StatusCaptured:
+			tender.Status = gateway.StatusRefunded
+		}
+	}
+	return nil
+}
+
+// refund gives back the charge of a card tender in full, or voids it before its capture
+func (service *PaymentServiceImpl) refund(ctx context.Context, tender *domain.PaymentTender) error {
+	charge, err := service.Provider.Refund(ctx, tender.ChargeID, tender.Amount)
+	if err != nil {
+		return err
+	}
+	tender.Status = charge.Status
+	return nil
+}
+
+// save records a payment concluded after the gateway changed it from the previous status and
+// publishes its settlement or its failure
+func (service *PaymentServiceImpl) save(ctx context.Context, payment domain.Payment, previous string) (web.PaymentResponse, error) {
+	if err := service.restore(ctx, &payment); err != nil {
+		return web.PaymentResponse{}, err
+	}
+	updated, ok, err := service.PaymentRepository.Update(ctx, payment)
+	if err != nil {
+		return web.PaymentResponse{}, err
+	}
+	if !ok {
+		return web.PaymentResponse{}, fmt.Errorf("payment %s was changed meanwhile", payment.PaymentID)
+	}
+	response := helper.ToPaymentResponse(updated)
+	switch {
+	case previous == updated.Status:
+		return response, nil
+	case updated.Status == domain.PaymentPaid:
+		return response, service.Events.Publish(ctx, event.PaymentSettled, response)
+	default:
+		return response, service.Events.Publish(ctx, event.PaymentFailed, response)
+	}
+}
+
+func (service *PaymentServiceImpl) findOrder(ctx context.Context, principal auth.Principal, orderId string) (domain.Order, error) {
+	order, err := service.OrderRepository.FindById(ctx, orderId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Order{}, exception.NewNotFoundError("Order not found")
+	} else if err != nil {
+		return domain.Order{}, err
+	}
+	if err := requireStore(principal, order.StoreID); err != nil {
+		return domain.Order{}, err
+	}
+	return order, nil
+}
+
+func (service *PaymentServiceImpl) findPayment(ctx context.Context, principal auth.Principal, paymentId string) (domain.Payment, error) {
+	payment, err := service.PaymentRepository.FindById(ctx, paymentId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Payment{}, exception.NewNotFoundError("Payment not found")
+	} else if err != nil {
+		return domain.Payment{}, err
+	}
+	if err := requireStore(principal, payment.StoreID); err != nil {
+		return domain.Payment{}, err
+	}
+	return payment, nil
+}
+
+// findTender returns the payment and the position of the tender a notification is about, by
+// its charge or, for a charge whose creation timed out, by its key
+func (service *PaymentServiceImpl) findTender(ctx context.Context, notification gateway.Notification) (domain.Payment, int, error) {
+	payment, err := service.PaymentRepository.FindByCharge(ctx, notification.ChargeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if paymentId, position, ok := parseChargeKey(notification.Key); ok {
+			payment, err = service.PaymentRepository.FindById(ctx, paymentId)
+			if err == nil && position <= len(payment.Tenders) {
+				return payment, position, nil
+			}
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Payment{}, 0, exception.NewNotFoundError(fmt.Sprintf("no payment for charge %s", notification.ChargeID))
+	} else if err != nil {
+		return domain.Payment{}, 0, err
+	}
+	for _, tender := range payment.Tenders {
+		if tender.ChargeID == notification.ChargeID {
+			return payment, tender.Position, nil
+		}
+	}
+	return domain.Payment{}, 0, exception.NewNotFoundError(fmt.Sprintf("no payment for charge %s", notification.ChargeID))
+}
+
+// chargeKey is the idempotency key of the charge of a tender
+func chargeKey(paymentId string, position int) string {
+	return paymentId + "/" + strconv.Itoa(position)
+}
+
+func parseChargeKey(key string) (string, int, bool) {
+	paymentId, rawPosition, found := strings.Cut(key, "/")
+	position, err := strconv.Atoi(rawPosition)
+	if !found || err != nil || position < 1 {
+		return "", 0, false
+	}
+	return paymentId, position, true
+}
+
+// redeemedPoints adds up the loyalty points of the tenders of a payment
+func redeemedPoints(payment domain.Payment) int {
+	points := 0
+	for _, tender := range payment.Tenders {
+		points += tender.Points
+	}
+	return points
+}
+
+// declineReason is the reason of the first declined tender of a payment
+func declineReason(payment domain.Payment) string {
+	for _, tender := range payment.Tenders {
+		if tender.DeclineReason != "" {
+			return tender.DeclineReason
+		}
+	}
+	return "declined"
+}
+
+// orderPayload is the payload paying the total of an order, reconciled with its order number
+func orderPayload(merchant qris.Merchant, order domain.Order) qris.Payload {
+	return qris.Payload{Merchant: merchant, Amount: order.Total, Reference: orderNumber(order.OrderID)}
+}
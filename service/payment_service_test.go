@@ -0,0 +1,374 @@
+package service_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/qris"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var merchant = qris.Merchant{
+	AcquirerID:   "ID.CO.BANKDEMO.WWW",
+	PAN:          "9360000812345678901",
+	NMID:         "ID1020012345678",
+	Criteria:     "UMI",
+	CategoryCode: "5411",
+	Name:         "Toko Maju Jaya",
+	City:         "JAKARTA",
+}
+
+type paymentMocks struct {
+	orders    *mocks.MockOrderRepository
+	payments  *mocks.MockPaymentRepository
+	customers *mocks.MockCustomerRepository
+	gateway   *payment.Fake
+}
+
+func setupPaymentService(t *testing.T, publisher *recordingPublisher) (service.PaymentService, paymentMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := paymentMocks{
+		orders:    mocks.NewMockOrderRepository(ctrl),
+		payments:  mocks.NewMockPaymentRepository(ctrl),
+		customers: mocks.NewMockCustomerRepository(ctrl),
+		gateway:   payment.NewFake("rahasia", time.Minute),
+	}
+	paymentService := service.NewPaymentService(repositories.orders, repositories.payments, repositories.customers, repositories.gateway, merchant, fakeTransactor{}, publisher, validator.New())
+	return paymentService, repositories
+}
+
+// expectPaymentWrites saves the payment with an ID and returns the last payment updated
+func expectPaymentWrites(repositories paymentMocks) *domain.Payment {
+	updated := &domain.Payment{}
+	repositories.payments.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p domain.Payment) (domain.Payment, bool, error) {
+		p.PaymentID = "PAY1"
+		return p, true, nil
+	})
+	repositories.payments.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p domain.Payment) (domain.Payment, bool, error) {
+		p.Version++
+		*updated = p
+		return p, true, nil
+	})
+	return updated
+}
+
+// unreachableGateway fails the charges of tokenUnreachable with an error that is no timeout
+type unreachableGateway struct {
+	*payment.Fake
+}
+
+const tokenUnreachable = "tok_unreachable"
+
+func (gateway unreachableGateway) CreateCharge(ctx context.Context, request payment.ChargeRequest) (payment.Charge, error) {
+	if request.Token == tokenUnreachable {
+		return payment.Charge{}, errors.New("connection reset by peer")
+	}
+	return gateway.Fake.CreateCharge(ctx, request)
+}
+
+func TestCreatePayment(t *testing.T) {
+	order := domain.Order{OrderID: "1a2b3c4d-0000-4000-8000-000000000000", StoreID: "JKT01", CustomerID: "C001", Total: money.IDR(116550)}
+	cash, tendered, card := money.IDR(21550), money.IDR(50000), money.IDR(90000)
+	split := web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{
+		{Method: domain.TenderPoints, Points: 50},
+		{Method: domain.TenderCash, Amount: &cash, Tendered: &tendered},
+		{Method: domain.TenderCard, Amount: &card, Token: payment.TokenSuccess},
+	}}
+
+	t.Run("split tender", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		paymentService, repositories := setupPaymentService(t, publisher)
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(nil, nil)
+		repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -50).Return(true, nil)
+		expectPaymentWrites(repositories)
+
+		response, err := paymentService.Create(context.Background(), cashier, split)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentPaid, response.Status)
+		assert.Equal(t, money.IDR(5000), response.Tenders[0].Amount)
+		assert.Equal(t, money.IDR(28450), response.Tenders[1].Change)
+		assert.Equal(t, payment.StatusCaptured, response.Tenders[2].Status)
+		charge, err := repositories.gateway.Status(context.Background(), response.Tenders[2].ChargeID)
+		require.NoError(t, err)
+		assert.Equal(t, "PAY1/3", charge.Key)
+		assert.Equal(t, []string{event.PaymentCreated}, publisher.types)
+	})
+
+	t.Run("cash rounding", func(t *testing.T) {
+		paymentService, repositories := setupPaymentService(t, &recordingPublisher{})
+		rounded := money.IDR(116600)
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(domain.Order{OrderID: order.OrderID, StoreID: "JKT01", Total: order.Total}, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(nil, nil)
+		expectPaymentWrites(repositories)
+
+		response, err := paymentService.Create(context.Background(), cashier, web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{
+			{Method: domain.TenderCash, Amount: &rounded},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentPaid, response.Status)
+		assert.Equal(t, rounded, response.Amount)
+	})
+
+	t.Run("paid meanwhile", func(t *testing.T) {
+		paymentService, repositories := setupPaymentService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(nil, nil)
+		repositories.payments.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Payment{}, false, nil)
+
+		_, err := paymentService.Create(context.Background(), cashier, split)
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+
+	t.Run("declined card", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		paymentService, repositories := setupPaymentService(t, publisher)
+		declined := split
+		declined.Tenders = append([]web.TenderRequest{}, split.Tenders...)
+		declined.Tenders[2].Token = payment.TokenDecline
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(nil, nil)
+		gomock.InOrder(
+			repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -50).Return(true, nil),
+			repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", 50).Return(true, nil),
+		)
+		updated := expectPaymentWrites(repositories)
+
+		_, err := paymentService.Create(context.Background(), cashier, declined)
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+		assert.Contains(t, err.Error(), "insufficient funds")
+		assert.Equal(t, domain.PaymentFailed, updated.Status)
+		assert.Equal(t, payment.StatusRefunded, updated.Tenders[0].Status, "the points are given back")
+		assert.Equal(t, payment.StatusDeclined, updated.Tenders[2].Status)
+		assert.Equal(t, []string{event.PaymentFailed}, publisher.types)
+	})
+
+	t.Run("gateway error", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		_, repositories := setupPaymentService(t, publisher)
+		paymentService := service.NewPaymentService(repositories.orders, repositories.payments, repositories.customers, unreachableGateway{repositories.gateway}, merchant, fakeTransactor{}, publisher, validator.New())
+		first, second := money.IDR(21550), money.IDR(90000)
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(nil, nil)
+		gomock.InOrder(
+			repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -50).Return(true, nil),
+			repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", 50).Return(true, nil),
+		)
+		updated := expectPaymentWrites(repositories)
+
+		_, err := paymentService.Create(context.Background(), cashier, web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{
+			{Method: domain.TenderPoints, Points: 50},
+			{Method: domain.TenderCard, Amount: &first, Token: payment.TokenSuccess},
+			{Method: domain.TenderCard, Amount: &second, Token: tokenUnreachable},
+		}})
+		assert.ErrorContains(t, err, "connection reset by peer")
+		assert.Equal(t, domain.PaymentFailed, updated.Status)
+		assert.Equal(t, payment.StatusRefunded, updated.Tenders[0].Status, "the points are given back")
+		assert.Equal(t, payment.StatusRefunded, updated.Tenders[1].Status, "the captured card is refunded")
+		assert.Equal(t, payment.StatusPending, updated.Tenders[2].Status)
+		assert.Equal(t, []string{event.PaymentFailed}, publisher.types)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		paymentService, repositories := setupPaymentService(t, &recordingPublisher{})
+		total := order.Total
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(nil, nil)
+		expectPaymentWrites(repositories)
+
+		response, err := paymentService.Create(context.Background(), cashier, web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{
+			{Method: domain.TenderCard, Amount: &total, Token: payment.TokenTimeout},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentPaid, response.Status, "the charge is retried with the same key")
+		assert.Equal(t, "ch_1", response.Tenders[0].ChargeID)
+	})
+
+	short, roundedDown := money.IDR(100000), money.IDR(116500)
+	tests := []struct {
+		name    string
+		order   domain.Order
+		request web.PaymentCreateRequest
+		paid    []domain.Payment
+		err     interface{}
+	}{
+		{name: "no tenders", request: web.PaymentCreateRequest{OrderID: order.OrderID}, err: &validator.ValidationErrors{}},
+		{name: "short of the total", order: order, request: web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{{Method: domain.TenderCash, Amount: &short}}}, err: &exception.BadRequestError{}},
+		{name: "rounded the wrong way", order: order, request: web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{{Method: domain.TenderCash, Amount: &roundedDown}}}, err: &exception.BadRequestError{}},
+		{name: "tendered short of the amount", order: order, request: web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{{Method: domain.TenderCash, Amount: &order.Total, Tendered: &short}}}, err: &exception.BadRequestError{}},
+		{name: "card without token", order: order, request: web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{{Method: domain.TenderCard, Amount: &order.Total}}}, err: &exception.BadRequestError{}},
+		{name: "points without customer", order: domain.Order{OrderID: order.OrderID, StoreID: "JKT01", Total: money.IDR(5000)}, request: web.PaymentCreateRequest{OrderID: order.OrderID, Tenders: []web.TenderRequest{{Method: domain.TenderPoints, Points: 50}}}, err: &exception.BadRequestError{}},
+		{name: "already paid", order: order, request: split, paid: []domain.Payment{{PaymentID: "PAY0", Status: domain.PaymentPaid}}, err: &exception.BadRequestError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentService, repositories := setupPaymentService(t, &recordingPublisher{})
+			if tt.order.OrderID != "" {
+				repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(tt.order, nil)
+				repositories.payments.EXPECT().FindByOrder(gomock.Any(), order.OrderID).Return(tt.paid, nil)
+			}
+
+			_, err := paymentService.Create(context.Background(), cashier, tt.request)
+			assert.ErrorAs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNotifyPayment(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	paymentService, repositories := setupPaymentService(t, publisher)
+	charge, err := repositories.gateway.CreateCharge(ctx, payment.ChargeRequest{Key: "PAY1/1", Amount: money.IDR(116550), Token: payment.TokenDelayed})
+	require.NoError(t, err)
+	pending := domain.Payment{PaymentID: "PAY1", StoreID: "JKT01", Amount: money.IDR(116550), Status: domain.PaymentPending, Tenders: []domain.PaymentTender{
+		{PaymentID: "PAY1", Position: 1, Method: domain.TenderCard, Amount: money.IDR(116550), Provider: "fake", ChargeID: charge.ID, Status: payment.StatusPending},
+	}}
+	webhook, err := repositories.gateway.Settle(charge.ID, payment.StatusCaptured)
+	require.NoError(t, err)
+	header := func(key string) string { return webhook.Header[key] }
+
+	repositories.payments.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Return(true, nil)
+	repositories.payments.EXPECT().FindByCharge(gomock.Any(), charge.ID).Return(pending, nil)
+	var updated domain.Payment
+	repositories.payments.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p domain.Payment) (domain.Payment, bool, error) {
+		updated = p
+		return p, true, nil
+	})
+	response, err := paymentService.Notify(ctx, "fake", header, webhook.Body)
+	require.NoError(t, err)
+	assert.Equal(t, web.PaymentNotificationResponse{NotificationID: "evt_2", PaymentID: "PAY1"}, response)
+	assert.Equal(t, domain.PaymentPaid, updated.Status)
+	assert.Equal(t, []string{event.PaymentSettled}, publisher.types)
+
+	repositories.payments.EXPECT().FindByCharge(gomock.Any(), charge.ID).Return(updated, nil)
+	repositories.payments.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Return(false, nil)
+	response, err = paymentService.Notify(ctx, "fake", header, webhook.Body)
+	require.NoError(t, err)
+	assert.True(t, response.Duplicate, "a redelivery is not processed again")
+
+	_, err = paymentService.Notify(ctx, "fake", header, append(webhook.Body, ' '))
+	assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+	_, err = paymentService.Notify(ctx, "stripe", header, webhook.Body)
+	assert.ErrorAs(t, err, &exception.NotFoundError{})
+}
+
+func TestCreateQRIS(t *testing.T) {
+	order := domain.Order{OrderID: "1a2b3c4d-0000-4000-8000-000000000000", StoreID: "JKT01", Total: money.IDR(116550)}
+
+	t.Run("order", func(t *testing.T) {
+		paymentService, repositories := setupPaymentService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), order.OrderID).Return(order, nil)
+
+		response, err := paymentService.CreateQRIS(context.Background(), cashier, web.QRISCreateRequest{OrderID: order.OrderID, Image: "png"})
+		require.NoError(t, err)
+		assert.Equal(t, money.IDR(116550), response.Amount)
+		assert.Equal(t, "1A2B3C4D", response.Reference)
+		assert.True(t, response.OwnMerchant)
+		assert.True(t, strings.HasPrefix(response.Image, "data:image/png;base64,"))
+		payload, err := qris.Parse(response.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, qris.Payload{Merchant: merchant, Amount: money.IDR(116550), Reference: "1A2B3C4D"}, payload)
+	})
+
+	t.Run("amount", func(t *testing.T) {
+		paymentService, _ := setupPaymentService(t, &recordingPublisher{})
+		amount := money.IDR(50000)
+
+		response, err := paymentService.CreateQRIS(context.Background(), cashier, web.QRISCreateRequest{Amount: &amount, Reference: "INV-7", Image: "svg"})
+		require.NoError(t, err)
+		assert.Equal(t, amount, response.Amount)
+		assert.Equal(t, "INV-7", response.Reference)
+		svg, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(response.Image, "data:image/svg+xml;base64,"))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(svg), "<svg"))
+	})
+
+	t.Run("static", func(t *testing.T) {
+		paymentService, _ := setupPaymentService(t, &recordingPublisher{})
+
+		response, err := paymentService.CreateQRIS(context.Background(), cashier, web.QRISCreateRequest{})
+		require.NoError(t, err)
+		assert.True(t, response.Amount.IsZero())
+		assert.Contains(t, response.Payload, "010211")
+		assert.Empty(t, response.Image)
+	})
+
+	zero := money.IDR(0)
+	amount := money.IDR(1000)
+	tests := []struct {
+		name    string
+		request web.QRISCreateRequest
+		setup   func(orderRepository *mocks.MockOrderRepository)
+		err     interface{}
+	}{
+		{name: "order and amount", request: web.QRISCreateRequest{OrderID: order.OrderID, Amount: &amount}, err: &exception.BadRequestError{}},
+		{name: "zero amount", request: web.QRISCreateRequest{Amount: &zero}, err: &exception.BadRequestError{}},
+		{name: "unknown image format", request: web.QRISCreateRequest{Amount: &amount, Image: "gif"}, err: &validator.ValidationErrors{}},
+		{name: "unknown order", request: web.QRISCreateRequest{OrderID: "O404"}, setup: func(orderRepository *mocks.MockOrderRepository) {
+			orderRepository.EXPECT().FindById(gomock.Any(), "O404").Return(domain.Order{}, errors.Join(errors.New("order is not found"), gorm.ErrRecordNotFound))
+		}, err: &exception.NotFoundError{}},
+		{name: "order of another store", request: web.QRISCreateRequest{OrderID: order.OrderID}, setup: func(orderRepository *mocks.MockOrderRepository) {
+			other := order
+			other.StoreID = "BDG01"
+			orderRepository.EXPECT().FindById(gomock.Any(), order.OrderID).Return(other, nil)
+		}, err: &exception.ForbiddenError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentService, repositories := setupPaymentService(t, &recordingPublisher{})
+			if tt.setup != nil {
+				tt.setup(repositories.orders)
+			}
+
+			_, err := paymentService.CreateQRIS(context.Background(), cashier, tt.request)
+			assert.ErrorAs(t, err, tt.err)
+		})
+	}
+}
+
+func TestVerifyQRIS(t *testing.T) {
+	paymentService, _ := setupPaymentService(t, &recordingPublisher{})
+	text, err := qris.Encode(qris.Payload{Merchant: merchant, Amount: money.IDR(116550), Reference: "1A2B3C4D"})
+	require.NoError(t, err)
+
+	response, err := paymentService.VerifyQRIS(context.Background(), web.QRISVerifyRequest{Payload: text})
+	require.NoError(t, err)
+	assert.Equal(t, web.QRISResponse{
+		Payload:      text,
+		Amount:       money.IDR(116550),
+		Currency:     "IDR",
+		Reference:    "1A2B3C4D",
+		MerchantName: "Toko Maju Jaya",
+		MerchantCity: "JAKARTA",
+		NMID:         "ID1020012345678",
+		OwnMerchant:  true,
+	}, response)
+
+	other := merchant
+	other.NMID = "ID1020099999999"
+	text, err = qris.Encode(qris.Payload{Merchant: other})
+	require.NoError(t, err)
+	response, err = paymentService.VerifyQRIS(context.Background(), web.QRISVerifyRequest{Payload: text})
+	require.NoError(t, err)
+	assert.False(t, response.OwnMerchant)
+
+	tampered := strings.Replace(text, "Toko Maju Jaya", "Toko Maju Jaja", 1)
+	_, err = paymentService.VerifyQRIS(context.Background(), web.QRISVerifyRequest{Payload: tampered})
+	assert.ErrorAs(t, err, &exception.BadRequestError{})
+}
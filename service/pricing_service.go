@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type PricingService interface {
+	Quote(ctx context.Context, request web.QuoteRequest) (web.QuoteResponse, error)
+	FindTaxClasses(ctx context.Context) ([]web.TaxClassResponse, error)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/pricing"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type PricingServiceImpl struct {
+	ProductRepository    repository.ProductRepository
+	CustomerRepository   repository.CustomerRepository
+	PromotionRepository  repository.PromotionRepository
+	StoreRepository      repository.StoreRepository
+	StoreStockRepository repository.StoreStockRepository
+	TaxClassRepository   repository.TaxClassRepository
+	Validate             *validator.Validate
+}
+
+func NewPricingService(productRepository repository.ProductRepository, customerRepository repository.CustomerRepository, promotionRepository repository.PromotionRepository, storeRepository repository.StoreRepository, storeStockRepository repository.StoreStockRepository, taxClassRepository repository.TaxClassRepository, validate *validator.Validate) PricingService {
+	return &PricingServiceImpl{
+		ProductRepository:    productRepository,
+		CustomerRepository:   customerRepository,
+		PromotionRepository:  promotionRepository,
+		StoreRepository:      storeRepository,
+		StoreStockRepository: storeStockRepository,
+		TaxClassRepository:   taxClassRepository,
+		Validate:             validate,
+	}
+}
+
+// Quote prices a basket at the current prices with the promotions in effect now, nothing is
+// reserved nor counted against the usage limits. In a store context the prices and the tax
+// settings are those of the store.
+func (service *PricingServiceImpl) Quote(ctx context.Context, request web.QuoteRequest) (web.QuoteResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.QuoteResponse{}, err
+	}
+	settings := tax.Default
+	if request.StoreID != "" {
+		store, err := service.StoreRepository.FindById(ctx, request.StoreID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.QuoteResponse{}, exception.NewNotFoundError("Store not found")
+		} else if err != nil {
+			return web.QuoteResponse{}, err
+		}
+		settings = tax.Of(store)
+	}
+	if request.CustomerID != "" {
+		if _, err := service.CustomerRepository.FindById(ctx, request.CustomerID); errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.QuoteResponse{}, exception.NewBadRequestError(fmt.Sprintf("unknown customer %s", request.CustomerID))
+		} else if err != nil {
+			return web.QuoteResponse{}, err
+		}
+	}
+
+	classes, err := service.taxClasses(ctx)
+	if err != nil {
+		return web.QuoteResponse{}, err
+	}
+	lines := make([]pricing.Line, 0, len(request.Lines))
+	seen := map[string]bool{}
+	for _, requestLine := range request.Lines {
+		key := requestLine.ProductID + "/" + requestLine.VariantID
+		if seen[key] {
+			return web.QuoteResponse{}, exception.NewBadRequestError(fmt.Sprintf("product %s is listed twice", key))
+		}
+		seen[key] = true
+		line, err := service.toLine(ctx, request.StoreID, requestLine, classes)
+		if err != nil {
+			return web.QuoteResponse{}, err
+		}
+		lines = append(lines, line)
+	}
+
+	now := time.Now()
+	promotions, err := service.PromotionRepository.FindActive(ctx, now)
+	if err != nil {
+		return web.QuoteResponse{}, err
+	}
+	return helper.ToQuoteResponse(request.StoreID, request.CustomerID, pricing.Quote(lines, request.CustomerID, promotions, now, settings)), nil
+}
+
+// FindTaxClasses lists the tax classes
+func (service *PricingServiceImpl) FindTaxClasses(ctx context.Context) ([]web.TaxClassResponse, error) {
+	classes, err := service.TaxClassRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToTaxClassResponses(classes), nil
+}
+
+func (service *PricingServiceImpl) taxClasses(ctx context.Context) (map[string]domain.TaxClass, error) {
+	classes, err := service.TaxClassRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]domain.TaxClass, len(classes))
+	for _, class := range classes {
+		byId[class.TaxClassID] = class
+	}
+	return byId, nil
+}
+
+// toLine reads the price, the category and the tax class of a basket line, the price of the
+// store comes before the price of the product and the price of a variant before both. A
+// product with variants is sold by variant, each holds its own stock.
+func (service *PricingServiceImpl) toLine(ctx context.Context, storeId string, request web.QuoteLineRequest, classes map[string]domain.TaxClass) (pricing.Line, error) {
+	product, err := service.ProductRepository.FindById(ctx, request.ProductID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pricing.Line{}, exception.NewBadRequestError(fmt.Sprintf("unknown product %s", request.ProductID))
+	} else if err != nil {
+		return pricing.Line{}, err
+	}
+
+	if err := checkVariant(product, request.VariantID); err != nil {
+		return pricing.Line{}, err
+	}
+	class, ok := classes[product.TaxClassID]
+	if !ok {
+		return pricing.Line{}, fmt.Errorf("product %s has an unknown tax class %q", product.ProductID, product.TaxClassID)
+	}
+
+	line := pricing.Line{
+		ProductID: product.ProductID,
+		Name:      product.Name,
+		Category:  product.Category,
+		UnitPrice: product.Price,
+		Quantity:  request.Quantity,
+		TaxClass:  class.TaxClassID,
+		TaxRates:  class.Rates,
+	}
+	if storeId != "" {
+		stock, err := service.StoreStockRepository.FindById(ctx, storeId, product.ProductID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return pricing.Line{}, err
+		}
+		if stock.PriceOverride != nil {
+			line.UnitPrice = *stock.PriceOverride
+		}
+	}
+	for _, variant := range product.Variants {
+		if variant.VariantID == request.VariantID {
+			line.VariantID = variant.VariantID
+			if variant.PriceOverride != nil {
+				line.UnitPrice = *variant.PriceOverride
+			}
+		}
+	}
+	return line, nil
+}
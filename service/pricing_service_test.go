@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type pricingMocks struct {
+	products   *mocks.MockProductRepository
+	customers  *mocks.MockCustomerRepository
+	promotions *mocks.MockPromotionRepository
+	stores     *mocks.MockStoreRepository
+	stocks     *mocks.MockStoreStockRepository
+	taxClasses *mocks.MockTaxClassRepository
+}
+
+func setupPricingService(t *testing.T) (service.PricingService, pricingMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := pricingMocks{
+		products:   mocks.NewMockProductRepository(ctrl),
+		customers:  mocks.NewMockCustomerRepository(ctrl),
+		promotions: mocks.NewMockPromotionRepository(ctrl),
+		stores:     mocks.NewMockStoreRepository(ctrl),
+		stocks:     mocks.NewMockStoreStockRepository(ctrl),
+		taxClasses: mocks.NewMockTaxClassRepository(ctrl),
+	}
+	repositories.taxClasses.EXPECT().FindAll(gomock.Any()).Return(tax.DefaultClasses(), nil).AnyTimes()
+	pricingService := service.NewPricingService(repositories.products, repositories.customers, repositories.promotions, repositories.stores, repositories.stocks, repositories.taxClasses, validator.New())
+	return pricingService, repositories
+}
+
+func TestPricingQuote(t *testing.T) {
+	large := money.IDR(45000)
+	coffee := domain.Product{
+		ProductID: "P002", Name: "Kopi Bubuk", Price: money.IDR(35000), Category: "Food", TaxClassID: tax.ClassStandard, TaxRate: 11,
+		Variants: []domain.ProductVariant{{VariantID: "V1", SKU: "KOP250"}, {VariantID: "V2", SKU: "KOP500", PriceOverride: &large}},
+	}
+	fiveThousandOff := []domain.Promotion{
+		{PromotionID: "PR1", Type: domain.PromotionFixedAmount, Amount: money.IDR(5000), Categories: []string{"Food"}, Active: true},
+	}
+
+	tests := []struct {
+		name      string
+		request   web.QuoteRequest
+		mock      func(repositories pricingMocks)
+		expectErr interface{}
+		expected  money.Money
+	}{
+		{
+			name:    "variant at its own price",
+			request: web.QuoteRequest{Lines: []web.QuoteLineRequest{{ProductID: "P002", VariantID: "V2", Quantity: 2}}},
+			mock: func(repositories pricingMocks) {
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(coffee, nil)
+				repositories.promotions.EXPECT().FindActive(gomock.Any(), gomock.Any()).Return(fiveThousandOff, nil)
+			},
+			// (90000 - 10000) + 11%
+			expected: money.IDR(88800),
+		},
+		{
+			name:    "store price, tax included",
+			request: web.QuoteRequest{StoreID: "JKT01", Lines: []web.QuoteLineRequest{{ProductID: "P002", VariantID: "V1", Quantity: 2}}},
+			mock: func(repositories pricingMocks) {
+				storePrice := money.IDR(33300)
+				repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01", PriceMode: tax.Inclusive}, nil)
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(coffee, nil)
+				repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", "P002").Return(domain.StoreStock{StoreID: "JKT01", ProductID: "P002", PriceOverride: &storePrice}, nil)
+				repositories.promotions.EXPECT().FindActive(gomock.Any(), gomock.Any()).Return(fiveThousandOff, nil)
+			},
+			// 66600 - 10000, the tax is already in
+			expected: money.IDR(56600),
+		},
+		{
+			name:    "unknown store",
+			request: web.QuoteRequest{StoreID: "XXX", Lines: []web.QuoteLineRequest{{ProductID: "P002", Quantity: 1}}},
+			mock: func(repositories pricingMocks) {
+				repositories.stores.EXPECT().FindById(gomock.Any(), "XXX").Return(domain.Store{}, gorm.ErrRecordNotFound)
+			},
+			expectErr: &exception.NotFoundError{},
+		},
+		{
+			name:    "unknown variant",
+			request: web.QuoteRequest{Lines: []web.QuoteLineRequest{{ProductID: "P002", VariantID: "V9", Quantity: 1}}},
+			mock: func(repositories pricingMocks) {
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(coffee, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:    "variant required",
+			request: web.QuoteRequest{Lines: []web.QuoteLineRequest{{ProductID: "P002", Quantity: 1}}},
+			mock: func(repositories pricingMocks) {
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(coffee, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name: "product listed twice",
+			request: web.QuoteRequest{Lines: []web.QuoteLineRequest{
+				{ProductID: "P002", VariantID: "V1", Quantity: 1},
+				{ProductID: "P002", VariantID: "V1", Quantity: 2},
+			}},
+			mock: func(repositories pricingMocks) {
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(coffee, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "no lines",
+			request:   web.QuoteRequest{},
+			mock:      func(repositories pricingMocks) {},
+			expectErr: &validator.ValidationErrors{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricingService, repositories := setupPricingService(t)
+			tt.mock(repositories)
+
+			response, err := pricingService.Quote(context.Background(), tt.request)
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, response.Total)
+		})
+	}
+}
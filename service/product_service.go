@@ -11,4 +11,6 @@ type ProductService interface {
 	Delete(ctx context.Context, productId string) error
 	FindById(ctx context.Context, productId string) (web.ProductResponse, error)
 	FindAll(ctx context.Context) ([]web.ProductResponse, error)
+	FindByCategories(ctx context.Context, categories []string) ([]web.ProductResponse, error)
+	FindByOptions(ctx context.Context, options map[string]string) ([]web.ProductResponse, error)
 }
@@ -3,23 +3,32 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 
+	"github.com/aronipurwanto/go-restful-api/event"
 	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
 	"github.com/aronipurwanto/go-restful-api/repository"
 	"gorm.io/gorm"
 )
 
 type ProductServiceImpl struct {
-	ProductRepository repository.ProductRepository
-	Validate          *validator.Validate
+	ProductRepository  repository.ProductRepository
+	TaxClassRepository repository.TaxClassRepository
+	Transactor         repository.Transactor
+	Events             event.Publisher
+	Validate           *validator.Validate
 }
 
-func NewProductService(productRepository repository.ProductRepository, validate *validator.Validate) ProductService {
-	return &ProductServiceImpl{ProductRepository: productRepository, Validate: validate}
+func NewProductService(productRepository repository.ProductRepository, taxClassRepository repository.TaxClassRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) ProductService {
+	return &ProductServiceImpl{ProductRepository: productRepository, TaxClassRepository: taxClassRepository, Transactor: transactor, Events: events, Validate: validate}
 }
 
 // Create Product
@@ -29,6 +38,14 @@ func (service *ProductServiceImpl) Create(ctx context.Context, request web.Produ
 	if err != nil {
 		return web.ProductResponse{}, err
 	}
+	if err := checkPrices(request.Price, request.Variants); err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	class, err := service.taxClass(ctx, request.TaxClass, request.TaxRate)
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
 
 	product := domain.Product{
 		Name:        request.Name,
@@ -37,18 +54,37 @@ func (service *ProductServiceImpl) Create(ctx context.Context, request web.Produ
 		StockQty:    request.StockQty,
 		Category:    request.Category,
 		SKU:         request.SKU,
-		TaxRate:     request.TaxRate,
+		TaxClassID:  class.TaxClassID,
+		TaxRate:     class.Percent(),
+	}
+	if err := service.setVariants(ctx, &product, request.Options, request.Variants); err != nil {
+		return web.ProductResponse{}, err
 	}
 
-	newProduct, err := service.ProductRepository.Save(ctx, product)
+	var response web.ProductResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		newProduct, err := service.ProductRepository.Save(ctx, product)
+		if err != nil {
+			return err
+		}
+		response = helper.ToProductResponse(newProduct)
+		return service.Events.Publish(ctx, event.ProductCreated, response)
+	})
 	if err != nil {
 		return web.ProductResponse{}, err
 	}
-	return helper.ToProductResponse(newProduct), nil
+	return response, nil
 }
 
 // Update Product
 func (service *ProductServiceImpl) Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.ProductResponse{}, err
+	}
+	if err := checkPrices(request.Price, request.Variants); err != nil {
+		return web.ProductResponse{}, err
+	}
+
 	// Cari product berdasarkan ID
 	product, err := service.ProductRepository.FindById(ctx, request.ProductID)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -57,6 +93,12 @@ func (service *ProductServiceImpl) Update(ctx context.Context, request web.Produ
 		return web.ProductResponse{}, err
 	}
 
+	class, err := service.taxClass(ctx, request.TaxClass, request.TaxRate)
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+	previous := product
+
 	// Update field-field product
 	product.Name = request.Name
 	product.Description = request.Description
@@ -64,13 +106,41 @@ func (service *ProductServiceImpl) Update(ctx context.Context, request web.Produ
 	product.StockQty = request.StockQty
 	product.Category = request.Category
 	product.SKU = request.SKU
-	product.TaxRate = request.TaxRate
+	product.TaxClassID = class.TaxClassID
+	product.TaxRate = class.Percent()
+	if request.Variants != nil {
+		if err := service.setVariants(ctx, &product, request.Options, request.Variants); err != nil {
+			return web.ProductResponse{}, err
+		}
+	} else if len(product.Variants) > 0 {
+		product.StockQty = totalStock(product.Variants)
+	}
 
-	updatedProduct, err := service.ProductRepository.Update(ctx, product)
+	var response web.ProductResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updatedProduct, err := service.ProductRepository.Update(ctx, product)
+		if err != nil {
+			return err
+		}
+		response = helper.ToProductResponse(updatedProduct)
+		if err := service.Events.Publish(ctx, event.ProductUpdated, response); err != nil {
+			return err
+		}
+		// Narrower events for subscribers that only follow prices or stock levels
+		if previous.Price != product.Price || variantPricesChanged(previous, product) {
+			if err := service.Events.Publish(ctx, event.ProductPriceChanged, response); err != nil {
+				return err
+			}
+		}
+		if previous.StockQty != product.StockQty {
+			return service.Events.Publish(ctx, event.ProductStockChanged, response)
+		}
+		return nil
+	})
 	if err != nil {
 		return web.ProductResponse{}, err
 	}
-	return helper.ToProductResponse(updatedProduct), nil
+	return response, nil
 }
 
 // Delete Product
@@ -81,7 +151,12 @@ func (service *ProductServiceImpl) Delete(ctx context.Context, productId string)
 	} else if err != nil {
 		return err
 	}
-	return service.ProductRepository.Delete(ctx, product)
+	return service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.ProductRepository.Delete(ctx, product); err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.ProductDeleted, helper.ToProductResponse(product))
+	})
 }
 
 // Find Product By ID
@@ -103,3 +178,154 @@ func (service *ProductServiceImpl) FindAll(ctx context.Context) ([]web.ProductRe
 	}
 	return helper.ToProductResponses(products), nil
 }
+
+// FindByCategories Product, the products of several categories in one query
+func (service *ProductServiceImpl) FindByCategories(ctx context.Context, categories []string) ([]web.ProductResponse, error) {
+	if len(categories) == 0 {
+		return []web.ProductResponse{}, nil
+	}
+	products, err := service.ProductRepository.FindByCategories(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToProductResponses(products), nil
+}
+
+// FindByOptions Product, the products having a variant with all the given option values, each
+// with its matching variants only
+func (service *ProductServiceImpl) FindByOptions(ctx context.Context, options map[string]string) ([]web.ProductResponse, error) {
+	if len(options) == 0 {
+		return service.FindAll(ctx)
+	}
+	products, err := service.ProductRepository.FindByOptions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToProductResponses(products), nil
+}
+
+// taxClass finds the tax class of a product by its ID or, for the clients that still send a
+// bare rate, by its total rate
+func (service *ProductServiceImpl) taxClass(ctx context.Context, taxClassId string, taxRate float64) (domain.TaxClass, error) {
+	if taxClassId != "" {
+		class, err := service.TaxClassRepository.FindById(ctx, taxClassId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.TaxClass{}, exception.NewBadRequestError(fmt.Sprintf("unknown tax class %s", taxClassId))
+		}
+		return class, err
+	}
+
+	classes, err := service.TaxClassRepository.FindAll(ctx)
+	if err != nil {
+		return domain.TaxClass{}, err
+	}
+	for _, class := range classes {
+		if class.Percent() == taxRate {
+			return class, nil
+		}
+	}
+	return domain.TaxClass{}, exception.NewBadRequestError(fmt.Sprintf("no tax class has a rate of %v%%, set tax_class", taxRate))
+}
+
+// setVariants replaces the options and the variants of a product, the stock of a product with
+// variants is the total of its variants
+func (service *ProductServiceImpl) setVariants(ctx context.Context, product *domain.Product, optionNames []string, requests []web.ProductVariantRequest) error {
+	existing := make(map[string]bool, len(product.Variants))
+	for _, variant := range product.Variants {
+		existing[variant.VariantID] = true
+	}
+
+	options := make([]domain.ProductOption, 0, len(optionNames))
+	for i, name := range optionNames {
+		options = append(options, domain.ProductOption{ProductID: product.ProductID, Name: name, Position: i})
+	}
+	variants := make([]domain.ProductVariant, 0, len(requests))
+	skus := map[string]bool{}
+	combinations := map[string]bool{}
+	for i, request := range requests {
+		if request.VariantID != "" && !existing[request.VariantID] {
+			return exception.NewBadRequestError(fmt.Sprintf("unknown variant %s", request.VariantID))
+		}
+		if skus[request.SKU] {
+			return exception.NewBadRequestError(fmt.Sprintf("sku %s is used by several variants", request.SKU))
+		}
+		skus[request.SKU] = true
+		if other, err := service.ProductRepository.FindVariantBySKU(ctx, request.SKU); err == nil && other.ProductID != product.ProductID {
+			return exception.NewBadRequestError(fmt.Sprintf("sku %s is used by product %s", request.SKU, other.ProductID))
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if len(request.Options) != len(optionNames) {
+			return exception.NewBadRequestError(fmt.Sprintf("variant %s must set the options %s", request.SKU, strings.Join(optionNames, ", ")))
+		}
+		values := make([]string, 0, len(optionNames))
+		variantOptions := make([]domain.ProductVariantOption, 0, len(optionNames))
+		for _, name := range optionNames {
+			value, ok := request.Options[name]
+			if !ok {
+				return exception.NewBadRequestError(fmt.Sprintf("variant %s must set the options %s", request.SKU, strings.Join(optionNames, ", ")))
+			}
+			values = append(values, value)
+			variantOptions = append(variantOptions, domain.ProductVariantOption{VariantID: request.VariantID, Name: name, Value: value})
+		}
+		combination := strings.Join(values, "/")
+		if combinations[combination] {
+			return exception.NewBadRequestError(fmt.Sprintf("several variants are %s", combination))
+		}
+		combinations[combination] = true
+
+		variants = append(variants, domain.ProductVariant{
+			VariantID:     request.VariantID,
+			ProductID:     product.ProductID,
+			SKU:           request.SKU,
+			Barcode:       request.Barcode,
+			PriceOverride: request.PriceOverride,
+			StockQty:      request.StockQty,
+			Position:      i,
+			Options:       variantOptions,
+		})
+	}
+
+	product.Options = options
+	product.Variants = variants
+	if len(variants) > 0 {
+		product.StockQty = totalStock(variants)
+	}
+	return nil
+}
+
+// checkPrices checks what the validator cannot see inside money.Money: a price is positive, a
+// variant price override is not negative
+func checkPrices(price money.Money, variants []web.ProductVariantRequest) error {
+	if !price.IsPositive() {
+		return exception.NewBadRequestError("price must be greater than 0")
+	}
+	for _, variant := range variants {
+		if variant.PriceOverride != nil && variant.PriceOverride.IsNegative() {
+			return exception.NewBadRequestError(fmt.Sprintf("price_override of variant %s must not be negative", variant.SKU))
+		}
+	}
+	return nil
+}
+
+func totalStock(variants []domain.ProductVariant) int {
+	total := 0
+	for _, variant := range variants {
+		total += variant.StockQty
+	}
+	return total
+}
+
+// variantPricesChanged reports whether a variant was added, removed or repriced
+func variantPricesChanged(previous domain.Product, product domain.Product) bool {
+	prices := func(product domain.Product) []string {
+		var prices []string
+		for _, variant := range helper.ToProductResponse(product).Variants {
+			prices = append(prices, fmt.Sprintf("%s=%s", variant.SKU, variant.PriceAmount))
+		}
+		sort.Strings(prices)
+		return prices
+	}
+	return strings.Join(prices(previous), ",") != strings.Join(prices(product), ",")
+}
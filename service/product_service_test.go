@@ -3,24 +3,45 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
 	"github.com/aronipurwanto/go-restful-api/repository/mocks"
 	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/tax"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
+// taxClasses serves the default tax classes
+func taxClasses(ctrl *gomock.Controller) *mocks.MockTaxClassRepository {
+	repository := mocks.NewMockTaxClassRepository(ctrl)
+	repository.EXPECT().FindAll(gomock.Any()).Return(tax.DefaultClasses(), nil).AnyTimes()
+	repository.EXPECT().FindById(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, taxClassId string) (domain.TaxClass, error) {
+		for _, class := range tax.DefaultClasses() {
+			if class.TaxClassID == taxClassId {
+				return class, nil
+			}
+		}
+		return domain.TaxClass{}, fmt.Errorf("tax class is not found: %w", gorm.ErrRecordNotFound)
+	}).AnyTimes()
+	return repository
+}
+
 func TestCreateProduct(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
 	mockValidator := validator.New()
-	productService := service.NewProductService(mockRepo, mockValidator)
+	productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -31,16 +52,16 @@ func TestCreateProduct(t *testing.T) {
 	}{
 		{
 			name:  "success",
-			input: web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			input: web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
 			mock: func() {
-				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
 			},
-			expect:    web.ProductResponse{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			expect:    web.ProductResponse{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, PriceAmount: money.IDR(15000000), Currency: "IDR", StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
 			expectErr: false,
 		},
 		{
 			name:  "repository error",
-			input: web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", Price: 15000000},
+			input: web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000)},
 			mock: func() {
 				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{}, errors.New("repository error"))
 			},
@@ -49,7 +70,14 @@ func TestCreateProduct(t *testing.T) {
 		},
 		{
 			name:      "validation error",
-			input:     web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			input:     web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			mock:      func() {},
+			expect:    web.ProductResponse{},
+			expectErr: true,
+		},
+		{
+			name:      "price not given",
+			input:     web.ProductCreateRequest{Name: "Laptop", Description: "Gaming Laptop", StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
 			mock:      func() {},
 			expect:    web.ProductResponse{},
 			expectErr: true,
@@ -76,7 +104,7 @@ func TestDeleteProduct(t *testing.T) {
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
 	mockValidator := validator.New()
-	productService := service.NewProductService(mockRepo, mockValidator)
+	productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -131,7 +159,7 @@ func TestFindAllProduct(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
-	productService := service.NewProductService(mockRepo, validator.New())
+	productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	mockRepo.EXPECT().FindAll(gomock.Any()).Return([]domain.Product{{ProductID: "1", Name: "Alice"}}, nil)
 
@@ -142,13 +170,33 @@ func TestFindAllProduct(t *testing.T) {
 	assert.Equal(t, "Alice", resp[0].Name)
 }
 
+func TestFindByCategoriesProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
+
+	mockRepo.EXPECT().FindByCategories(gomock.Any(), []string{"Food", "Drink"}).Return([]domain.Product{{ProductID: "1", Name: "Kopi", Category: "Drink"}}, nil)
+
+	resp, err := productService.FindByCategories(context.Background(), []string{"Food", "Drink"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []web.ProductResponse{{ProductID: "1", Name: "Kopi", Currency: "IDR", Category: "Drink"}}, resp)
+
+	// No query without categories
+	resp, err = productService.FindByCategories(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, resp)
+}
+
 func TestUpdateProduct(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
 	mockValidator := validator.New()
-	productService := service.NewProductService(mockRepo, mockValidator)
+	productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, mockValidator)
 
 	tests := []struct {
 		name      string
@@ -159,20 +207,20 @@ func TestUpdateProduct(t *testing.T) {
 	}{
 		{
 			name:  "success",
-			input: web.ProductUpdateRequest{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			input: web.ProductUpdateRequest{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
 			mock: func() {
-				mockRepo.EXPECT().FindById(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
-				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
+				mockRepo.EXPECT().FindById(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
 
 			},
-			expect:    web.ProductResponse{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			expect:    web.ProductResponse{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, PriceAmount: money.IDR(15000000), Currency: "IDR", StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
 			expectErr: false,
 		},
 		{
 			name:  "repository error",
-			input: web.ProductUpdateRequest{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
+			input: web.ProductUpdateRequest{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"},
 			mock: func() {
-				mockRepo.EXPECT().FindById(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: 15000000, StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
+				mockRepo.EXPECT().FindById(gomock.Any(), gomock.Any()).Return(domain.Product{ProductID: "1", Name: "Laptop", Description: "Gaming Laptop", Price: money.IDR(15000000), StockQty: 100, Category: "Gaming Laptop", SKU: "4"}, nil)
 				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.Product{}, errors.New("repository error"))
 			},
 			expect:    web.ProductResponse{},
@@ -199,7 +247,7 @@ func TestFindByIdProduct(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
-	productService := service.NewProductService(mockRepo, validator.New())
+	productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
 
 	tests := []struct {
 		name      string
@@ -214,7 +262,7 @@ func TestFindByIdProduct(t *testing.T) {
 			mock: func() {
 				mockRepo.EXPECT().FindById(gomock.Any(), "1").Return(domain.Product{ProductID: "1", Name: "Alice"}, nil)
 			},
-			expect:    web.ProductResponse{ProductID: "1", Name: "Alice"},
+			expect:    web.ProductResponse{ProductID: "1", Name: "Alice", Currency: "IDR"},
 			expectErr: false,
 		},
 		{
@@ -241,3 +289,238 @@ func TestFindByIdProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestProductEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	existing := domain.Product{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP"}
+	update := web.ProductUpdateRequest{ProductID: "1", Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP"}
+
+	tests := []struct {
+		name       string
+		mock       func()
+		call       func(productService service.ProductService) error
+		publishErr error
+		expect     []string
+		expectErr  bool
+	}{
+		{
+			name: "create",
+			mock: func() {
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(existing, nil)
+			},
+			call: func(productService service.ProductService) error {
+				_, err := productService.Create(context.Background(), web.ProductCreateRequest{Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP"})
+				return err
+			},
+			expect: []string{"product.created"},
+		},
+		{
+			name: "update without price or stock change",
+			mock: func() {
+				mockRepo.EXPECT().FindById(gomock.Any(), "1").Return(existing, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, product domain.Product) (domain.Product, error) {
+					return product, nil
+				})
+			},
+			call: func(productService service.ProductService) error {
+				request := update
+				request.Name = "Laptop Gaming"
+				_, err := productService.Update(context.Background(), request)
+				return err
+			},
+			expect: []string{"product.updated"},
+		},
+		{
+			name: "update price and stock",
+			mock: func() {
+				mockRepo.EXPECT().FindById(gomock.Any(), "1").Return(existing, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, product domain.Product) (domain.Product, error) {
+					return product, nil
+				})
+			},
+			call: func(productService service.ProductService) error {
+				request := update
+				request.Price = money.IDR(14000000)
+				request.StockQty = 9
+				_, err := productService.Update(context.Background(), request)
+				return err
+			},
+			expect: []string{"product.updated", "product.price_changed", "product.stock_changed"},
+		},
+		{
+			name: "delete",
+			mock: func() {
+				mockRepo.EXPECT().FindById(gomock.Any(), "1").Return(existing, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), existing).Return(nil)
+			},
+			call: func(productService service.ProductService) error {
+				return productService.Delete(context.Background(), "1")
+			},
+			expect: []string{"product.deleted"},
+		},
+		{
+			name: "publish failure fails the write",
+			mock: func() {
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(existing, nil)
+			},
+			call: func(productService service.ProductService) error {
+				_, err := productService.Create(context.Background(), web.ProductCreateRequest{Name: "Laptop", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP"})
+				return err
+			},
+			publishErr: errors.New("outbox unavailable"),
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			publisher := &recordingPublisher{err: tt.publishErr}
+			productService := service.NewProductService(mockRepo, taxClasses(ctrl), fakeTransactor{}, publisher, validator.New())
+
+			err := tt.call(productService)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, publisher.types)
+		})
+	}
+}
+
+func TestCreateProductWithVariants(t *testing.T) {
+	notFound := fmt.Errorf("product variant is not found: %w", gorm.ErrRecordNotFound)
+	variant := func(sku string, size string) web.ProductVariantRequest {
+		return web.ProductVariantRequest{SKU: sku, StockQty: 2, Options: map[string]string{"size": size}}
+	}
+	request := func(variants ...web.ProductVariantRequest) web.ProductCreateRequest {
+		return web.ProductCreateRequest{Name: "Kaos", Price: money.IDR(89000), Category: "Apparel", SKU: "KAOS", Options: []string{"size"}, Variants: variants}
+	}
+
+	tests := []struct {
+		name      string
+		request   web.ProductCreateRequest
+		mock      func(repository *mocks.MockProductRepository)
+		expectErr interface{}
+	}{
+		{
+			name:    "success",
+			request: request(variant("KAOS-M", "M"), variant("KAOS-L", "L")),
+			mock: func(repository *mocks.MockProductRepository) {
+				repository.EXPECT().FindVariantBySKU(gomock.Any(), gomock.Any()).Return(domain.ProductVariant{}, notFound).Times(2)
+				repository.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, product domain.Product) (domain.Product, error) {
+					return product, nil
+				})
+			},
+		},
+		{
+			name:    "same values twice",
+			request: request(variant("KAOS-M", "M"), variant("KAOS-M2", "M")),
+			mock: func(repository *mocks.MockProductRepository) {
+				repository.EXPECT().FindVariantBySKU(gomock.Any(), gomock.Any()).Return(domain.ProductVariant{}, notFound).Times(2)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:    "unknown option",
+			request: request(web.ProductVariantRequest{SKU: "KAOS-M", Options: map[string]string{"colour": "Merah"}}),
+			mock: func(repository *mocks.MockProductRepository) {
+				repository.EXPECT().FindVariantBySKU(gomock.Any(), "KAOS-M").Return(domain.ProductVariant{}, notFound)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:    "sku of another product",
+			request: request(variant("KAOS-M", "M")),
+			mock: func(repository *mocks.MockProductRepository) {
+				repository.EXPECT().FindVariantBySKU(gomock.Any(), "KAOS-M").Return(domain.ProductVariant{ProductID: "P009", SKU: "KAOS-M"}, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name: "negative variant price",
+			request: request(variant("KAOS-M", "M"), func() web.ProductVariantRequest {
+				large, negative := variant("KAOS-L", "L"), money.IDR(-1)
+				large.PriceOverride = &negative
+				return large
+			}()),
+			mock:      func(repository *mocks.MockProductRepository) {},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "variants without options",
+			request:   web.ProductCreateRequest{Name: "Kaos", Price: money.IDR(89000), Category: "Apparel", SKU: "KAOS", Variants: []web.ProductVariantRequest{variant("KAOS-M", "M")}},
+			mock:      func(repository *mocks.MockProductRepository) {},
+			expectErr: &validator.ValidationErrors{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repository := mocks.NewMockProductRepository(ctrl)
+			productService := service.NewProductService(repository, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
+			tt.mock(repository)
+
+			response, err := productService.Create(context.Background(), tt.request)
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 4, response.StockQty)
+			require.Len(t, response.Variants, 2)
+			assert.Equal(t, map[string]string{"size": "L"}, response.Variants[1].Options)
+			assert.Equal(t, 89000.0, response.Variants[1].Price)
+		})
+	}
+}
+
+func TestProductTaxClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		taxClass  string
+		taxRate   float64
+		expect    string
+		expectErr interface{}
+	}{
+		{name: "by class", taxClass: tax.ClassLuxury, taxRate: 11, expect: tax.ClassLuxury},
+		{name: "by rate", taxRate: 11, expect: tax.ClassStandard},
+		{name: "no rate is exempt", expect: tax.ClassExempt},
+		{name: "unknown class", taxClass: "ppn-99", expectErr: &exception.BadRequestError{}},
+		// 1100 is not 11%, no class has it
+		{name: "rate of no class", taxRate: 1100, expectErr: &exception.BadRequestError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repository := mocks.NewMockProductRepository(ctrl)
+			productService := service.NewProductService(repository, taxClasses(ctrl), fakeTransactor{}, &recordingPublisher{}, validator.New())
+			if tt.expectErr == nil {
+				repository.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, product domain.Product) (domain.Product, error) {
+					return product, nil
+				})
+			}
+
+			response, err := productService.Create(context.Background(), web.ProductCreateRequest{
+				Name: "Laptop", Price: money.IDR(15000000), StockQty: 1, Category: "Electronics", SKU: "LAP", TaxClass: tt.taxClass, TaxRate: tt.taxRate,
+			})
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, response.TaxClass)
+			for _, class := range tax.DefaultClasses() {
+				if class.TaxClassID == tt.expect {
+					assert.Equal(t, class.Percent(), response.TaxRate)
+				}
+			}
+		})
+	}
+}
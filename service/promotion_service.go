@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type PromotionService interface {
+	Create(ctx context.Context, request web.PromotionCreateRequest) (web.PromotionResponse, error)
+	Update(ctx context.Context, request web.PromotionUpdateRequest) (web.PromotionResponse, error)
+	Delete(ctx context.Context, promotionId string) error
+	FindById(ctx context.Context, promotionId string) (web.PromotionResponse, error)
+	FindAll(ctx context.Context) ([]web.PromotionResponse, error)
+}
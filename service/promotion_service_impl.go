@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type PromotionServiceImpl struct {
+	PromotionRepository repository.PromotionRepository
+	Transactor          repository.Transactor
+	Events              event.Publisher
+	Validate            *validator.Validate
+}
+
+func NewPromotionService(promotionRepository repository.PromotionRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) PromotionService {
+	return &PromotionServiceImpl{
+		PromotionRepository: promotionRepository,
+		Transactor:          transactor,
+		Events:              events,
+		Validate:            validate,
+	}
+}
+
+// Create Promotion, active from its creation
+func (service *PromotionServiceImpl) Create(ctx context.Context, request web.PromotionCreateRequest) (web.PromotionResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.PromotionResponse{}, err
+	}
+	promotion := domain.Promotion{
+		Name:        request.Name,
+		Type:        request.Type,
+		BuyQty:      request.BuyQty,
+		GetQty:      request.GetQty,
+		ProductIDs:  request.ProductIDs,
+		Categories:  request.Categories,
+		CustomerIDs: request.CustomerIDs,
+		StartsAt:    request.StartsAt,
+		EndsAt:      request.EndsAt,
+		Priority:    request.Priority,
+		Stackable:   request.Stackable,
+		UsageLimit:  request.UsageLimit,
+		Active:      true,
+	}
+	promotion.Value, promotion.Amount = promotionValue(request.Type, request.Value, request.Amount)
+	if err := checkPromotion(promotion); err != nil {
+		return web.PromotionResponse{}, err
+	}
+
+	var response web.PromotionResponse
+	err := service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedPromotion, err := service.PromotionRepository.Save(ctx, promotion)
+		if err != nil {
+			return err
+		}
+		response = helper.ToPromotionResponse(savedPromotion)
+		return service.Events.Publish(ctx, event.PromotionCreated, response)
+	})
+	if err != nil {
+		return web.PromotionResponse{}, err
+	}
+	return response, nil
+}
+
+// Update Promotion, its usage count is kept
+func (service *PromotionServiceImpl) Update(ctx context.Context, request web.PromotionUpdateRequest) (web.PromotionResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.PromotionResponse{}, err
+	}
+	promotion, err := service.findPromotion(ctx, request.PromotionID)
+	if err != nil {
+		return web.PromotionResponse{}, err
+	}
+
+	promotion.Name = request.Name
+	promotion.Type = request.Type
+	promotion.Value, promotion.Amount = promotionValue(request.Type, request.Value, request.Amount)
+	promotion.BuyQty = request.BuyQty
+	promotion.GetQty = request.GetQty
+	promotion.ProductIDs = request.ProductIDs
+	promotion.Categories = request.Categories
+	promotion.CustomerIDs = request.CustomerIDs
+	promotion.StartsAt = request.StartsAt
+	promotion.EndsAt = request.EndsAt
+	promotion.Priority = request.Priority
+	promotion.Stackable = request.Stackable
+	promotion.UsageLimit = request.UsageLimit
+	promotion.Active = request.Active
+	if err := checkPromotion(promotion); err != nil {
+		return web.PromotionResponse{}, err
+	}
+
+	var response web.PromotionResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updatedPromotion, err := service.PromotionRepository.Update(ctx, promotion)
+		if err != nil {
+			return err
+		}
+		response = helper.ToPromotionResponse(updatedPromotion)
+		return service.Events.Publish(ctx, event.PromotionUpdated, response)
+	})
+	if err != nil {
+		return web.PromotionResponse{}, err
+	}
+	return response, nil
+}
+
+// Delete Promotion
+func (service *PromotionServiceImpl) Delete(ctx context.Context, promotionId string) error {
+	promotion, err := service.findPromotion(ctx, promotionId)
+	if err != nil {
+		return err
+	}
+	return service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.PromotionRepository.Delete(ctx, promotion); err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.PromotionDeleted, helper.ToPromotionResponse(promotion))
+	})
+}
+
+// Find Promotion By ID
+func (service *PromotionServiceImpl) FindById(ctx context.Context, promotionId string) (web.PromotionResponse, error) {
+	promotion, err := service.findPromotion(ctx, promotionId)
+	if err != nil {
+		return web.PromotionResponse{}, err
+	}
+	return helper.ToPromotionResponse(promotion), nil
+}
+
+// Find All Promotions, highest priority first
+func (service *PromotionServiceImpl) FindAll(ctx context.Context) ([]web.PromotionResponse, error) {
+	promotions, err := service.PromotionRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToPromotionResponses(promotions), nil
+}
+
+func (service *PromotionServiceImpl) findPromotion(ctx context.Context, promotionId string) (domain.Promotion, error) {
+	promotion, err := service.PromotionRepository.FindById(ctx, promotionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Promotion{}, exception.NewNotFoundError("Promotion not found")
+	}
+	return promotion, err
+}
+
+// promotionValue splits what a request gives into the percent and the amount of a promotion of
+// promotionType. A promotion of an amount sent without one takes value, a number of the default
+// currency as sent before amounts were exact.
+func promotionValue(promotionType string, value float64, amount money.Money) (float64, money.Money) {
+	zero := money.New(0, money.DefaultCurrency)
+	if !domain.HasAmount(promotionType) {
+		return value, zero
+	}
+	if amount.IsZero() {
+		amount = money.FromFloat(value, money.DefaultCurrency)
+	}
+	return 0, amount
+}
+
+// checkPromotion checks the fields each type of promotion needs
+func checkPromotion(promotion domain.Promotion) error {
+	switch promotion.Type {
+	case domain.PromotionPercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return exception.NewBadRequestError("the value of a percentage promotion is between 0 and 100")
+		}
+	case domain.PromotionFixedAmount:
+		if !promotion.Amount.IsPositive() {
+			return exception.NewBadRequestError("the amount of a fixed amount promotion is the amount off per unit")
+		}
+	case domain.PromotionBuyXGetY:
+		if promotion.BuyQty < 1 || promotion.GetQty < 1 {
+			return exception.NewBadRequestError("a buy x get y promotion needs buy_qty and get_qty")
+		}
+	case domain.PromotionBundlePrice:
+		if promotion.BuyQty < 2 || !promotion.Amount.IsPositive() {
+			return exception.NewBadRequestError("a bundle price promotion needs a buy_qty of 2 or more and the price of the bundle as amount")
+		}
+	}
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return exception.NewBadRequestError("ends_at must be after starts_at")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	starts := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	ends := starts.Add(48 * time.Hour)
+
+	tests := []struct {
+		name      string
+		request   web.PromotionCreateRequest
+		amount    money.Money
+		expectErr interface{}
+	}{
+		{
+			name:    "success",
+			request: web.PromotionCreateRequest{Name: "Weekend", Type: domain.PromotionPercentage, Value: 10, StartsAt: &starts, EndsAt: &ends},
+		},
+		{
+			name:      "percentage over 100",
+			request:   web.PromotionCreateRequest{Name: "Weekend", Type: domain.PromotionPercentage, Value: 110},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:    "fixed amount",
+			request: web.PromotionCreateRequest{Name: "5k", Type: domain.PromotionFixedAmount, Amount: money.IDR(5000)},
+			amount:  money.IDR(5000),
+		},
+		{
+			name:    "fixed amount sent as value",
+			request: web.PromotionCreateRequest{Name: "5k", Type: domain.PromotionFixedAmount, Value: 5000},
+			amount:  money.IDR(5000),
+		},
+		{
+			name:      "fixed amount without amount",
+			request:   web.PromotionCreateRequest{Name: "5k", Type: domain.PromotionFixedAmount},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "bundle of one",
+			request:   web.PromotionCreateRequest{Name: "Bundle", Type: domain.PromotionBundlePrice, Amount: money.IDR(60000), BuyQty: 1},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "ends before it starts",
+			request:   web.PromotionCreateRequest{Name: "Weekend", Type: domain.PromotionPercentage, Value: 10, StartsAt: &ends, EndsAt: &starts},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "unknown type",
+			request:   web.PromotionCreateRequest{Name: "Weekend", Type: "cashback", Value: 10},
+			expectErr: &validator.ValidationErrors{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			promotions := mocks.NewMockPromotionRepository(ctrl)
+			publisher := &recordingPublisher{}
+			promotionService := service.NewPromotionService(promotions, fakeTransactor{}, publisher, validator.New())
+			if tt.expectErr == nil {
+				promotions.EXPECT().Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error) {
+						promotion.PromotionID = "PR1"
+						return promotion, nil
+					})
+			}
+
+			response, err := promotionService.Create(context.Background(), tt.request)
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				assert.Empty(t, publisher.types)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "PR1", response.PromotionID)
+			assert.True(t, response.Active)
+			if domain.HasAmount(tt.request.Type) {
+				require.NotNil(t, response.Amount)
+				assert.Equal(t, tt.amount, *response.Amount)
+				assert.Equal(t, tt.amount.Float64(), response.Value)
+			} else {
+				assert.Nil(t, response.Amount)
+			}
+			assert.Equal(t, []string{event.PromotionCreated}, publisher.types)
+		})
+	}
+}
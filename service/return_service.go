@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type ReturnService interface {
+	Create(ctx context.Context, principal auth.Principal, request web.ReturnCreateRequest) (web.ReturnResponse, error)
+	Approve(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest) (web.ReturnResponse, error)
+	Reject(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest) (web.ReturnResponse, error)
+	FindById(ctx context.Context, principal auth.Principal, returnId string) (web.ReturnResponse, error)
+	FindAll(ctx context.Context, storeId string, status string) ([]web.ReturnResponse, error)
+}
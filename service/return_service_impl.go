@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	gateway "github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ReturnPolicy decides which returns a supervisor approves
+type ReturnPolicy struct {
+	// ApprovalThreshold is the refund above which a return waits for a supervisor
+	ApprovalThreshold money.Money
+	// ApproverRoles are the roles of the employees who approve returns, compared without case
+	ApproverRoles []string
+}
+
+// needsApproval tells whether a refund is above the threshold
+func (policy ReturnPolicy) needsApproval(refund money.Money) bool {
+	return refund.Currency() != policy.ApprovalThreshold.Currency() || refund.Cmp(policy.ApprovalThreshold) > 0
+}
+
+// approves tells whether an employee is a supervisor
+func (policy ReturnPolicy) approves(employee domain.Employee) bool {
+	for _, role := range policy.ApproverRoles {
+		if strings.EqualFold(strings.TrimSpace(role), strings.TrimSpace(employee.Role)) {
+			return true
+		}
+	}
+	return false
+}
+
+type ReturnServiceImpl struct {
+	OrderRepository      repository.OrderRepository
+	ReturnRepository     repository.ReturnRepository
+	PaymentRepository    repository.PaymentRepository
+	EmployeeRepository   repository.EmployeeRepository
+	CustomerRepository   repository.CustomerRepository
+	StoreStockRepository repository.StoreStockRepository
+	Provider             gateway.Provider
+	Policy               ReturnPolicy
+	Transactor           repository.Transactor
+	Events               event.Publisher
+	Validate             *validator.Validate
+}
+
+func NewReturnService(orderRepository repository.OrderRepository, returnRepository repository.ReturnRepository, paymentRepository repository.PaymentRepository, employeeRepository repository.EmployeeRepository, customerRepository repository.CustomerRepository, storeStockRepository repository.StoreStockRepository, provider gateway.Provider, policy ReturnPolicy, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) ReturnService {
+	return &ReturnServiceImpl{
+		OrderRepository:      orderRepository,
+		ReturnRepository:     returnRepository,
+		PaymentRepository:    paymentRepository,
+		EmployeeRepository:   employeeRepository,
+		CustomerRepository:   customerRepository,
+		StoreStockRepository: storeStockRepository,
+		Provider:             provider,
+		Policy:               policy,
+		Transactor:           transactor,
+		Events:               events,
+		Validate:             validate,
+	}
+}
+
+// Create a return of lines of an order. A return refunding more than the approval threshold
+// waits for a supervisor, unless the employee processing it is one and the key is bound to
+// them; the others are completed at once. The returns of the order are read under its lock,
+// two returns of the same line at once do not return more than was sold.
+//
+// A return is completed in two transactions: the first restocks it, takes the points back and
+// saves it refunding, the cards are refunded out of the transactions, and the second records
+// the refunds and completes it, see settle.
+func (service *ReturnServiceImpl) Create(ctx context.Context, principal auth.Principal, request web.ReturnCreateRequest) (web.ReturnResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.ReturnResponse{}, err
+	}
+	order, err := service.findOrder(ctx, principal, request.OrderID)
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	employee, err := service.findEmployee(ctx, request.EmployeeID)
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+
+	var savedReturn domain.Return
+	var response web.ReturnResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.OrderRepository.Lock(ctx, order.OrderID); err != nil {
+			return err
+		}
+		previous, err := service.ReturnRepository.FindByOrder(ctx, order.OrderID)
+		if err != nil {
+			return err
+		}
+		orderReturn, err := toReturn(order, previous, request)
+		if err != nil {
+			return err
+		}
+		if service.Policy.needsApproval(orderReturn.Refund) {
+			if !service.Policy.approves(employee) || !principal.Can(auth.EmployeePermission(employee.EmployeeID)) {
+				orderReturn.Status = domain.ReturnPending
+			} else {
+				orderReturn.ApprovedBy = employee.EmployeeID
+			}
+		}
+
+		if orderReturn.Status != domain.ReturnPending {
+			if err := service.complete(ctx, &orderReturn, order, previous); err != nil {
+				return err
+			}
+		}
+		savedReturn, err = service.ReturnRepository.Save(ctx, orderReturn)
+		if err != nil {
+			return err
+		}
+		response = helper.ToReturnResponse(savedReturn)
+		return service.Events.Publish(ctx, event.ReturnCreated, response)
+	})
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	if savedReturn.Status != domain.ReturnRefunding {
+		return response, nil
+	}
+	return service.settle(ctx, savedReturn)
+}
+
+// Approve a pending return by a supervisor, which completes it. Approving a refunding return
+// again gives back the card refunds the gateway failed to.
+func (service *ReturnServiceImpl) Approve(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest) (web.ReturnResponse, error) {
+	orderReturn, err := service.decide(ctx, principal, request, domain.ReturnPending, domain.ReturnRefunding)
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	if orderReturn.Status == domain.ReturnRefunding {
+		return service.settle(ctx, orderReturn)
+	}
+	order, err := service.OrderRepository.FindById(ctx, orderReturn.OrderID)
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The other returns of the order decide the points taken back, they are read under
+		// its lock as when a return is created
+		if err := service.OrderRepository.Lock(ctx, order.OrderID); err != nil {
+			return err
+		}
+		returns, err := service.ReturnRepository.FindByOrder(ctx, order.OrderID)
+		if err != nil {
+			return err
+		}
+		var previous []domain.Return
+		for _, other := range returns {
+			if other.ReturnID != orderReturn.ReturnID {
+				previous = append(previous, other)
+			}
+		}
+		if err := service.complete(ctx, &orderReturn, order, previous); err != nil {
+			return err
+		}
+		orderReturn, err = service.update(ctx, orderReturn)
+		return err
+	})
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	return service.settle(ctx, orderReturn)
+}
+
+// Reject a pending return by a supervisor, nothing is refunded
+func (service *ReturnServiceImpl) Reject(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest) (web.ReturnResponse, error) {
+	orderReturn, err := service.decide(ctx, principal, request, domain.ReturnPending)
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	orderReturn.Status = domain.ReturnRejected
+
+	var response web.ReturnResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		rejected, err := service.update(ctx, orderReturn)
+		if err != nil {
+			return err
+		}
+		response = helper.ToReturnResponse(rejected)
+		return service.Events.Publish(ctx, event.ReturnRejected, response)
+	})
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	return response, nil
+}
+
+// FindById returns a return of a store the principal is granted
+func (service *ReturnServiceImpl) FindById(ctx context.Context, principal auth.Principal, returnId string) (web.ReturnResponse, error) {
+	orderReturn, err := service.findReturn(ctx, principal, returnId)
+	if err != nil {
+		return web.ReturnResponse{}, err
+	}
+	return helper.ToReturnResponse(orderReturn), nil
+}
+
+// FindAll lists the returns of a store, of every status or of one, newest first
+func (service *ReturnServiceImpl) FindAll(ctx context.Context, storeId string, status string) ([]web.ReturnResponse, error) {
+	var statuses []string
+	switch status {
+	case "":
+	case domain.ReturnPending, domain.ReturnRefunding, domain.ReturnCompleted, domain.ReturnRejected:
+		statuses = []string{status}
+	default:
+		return nil, exception.NewBadRequestError(fmt.Sprintf("unknown return status %q", status))
+	}
+
+	orderReturns, err := service.ReturnRepository.FindAll(ctx, storeId, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToReturnResponses(orderReturns), nil
+}
+
+// decide checks that a supervisor decides on a return of one of statuses and records the
+// decision. The supervisor is the employee the key is bound to, see auth.EmployeePermission.
+func (service *ReturnServiceImpl) decide(ctx context.Context, principal auth.Principal, request web.ReturnDecisionRequest, statuses ...string) (domain.Return, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return domain.Return{}, err
+	}
+	orderReturn, err := service.findReturn(ctx, principal, request.ReturnID)
+	if err != nil {
+		return domain.Return{}, err
+	}
+	if !slices.Contains(statuses, orderReturn.Status) {
+		return domain.Return{}, exception.NewBadRequestError(fmt.Sprintf("return %s is %s, only a %s return is decided that way", orderReturn.ReturnID, orderReturn.Status, strings.Join(statuses, " or ")))
+	}
+	employeeId, err := requireEmployee(principal, request.EmployeeID)
+	if err != nil {
+		return domain.Return{}, err
+	}
+	employee, err := service.findEmployee(ctx, employeeId)
+	if err != nil {
+		return domain.Return{}, err
+	}
+	if !service.Policy.approves(employee) {
+		return domain.Return{}, exception.NewForbiddenError(fmt.Sprintf("employee %s is a %s, returns above %s are approved by a %s", employee.EmployeeID, employee.Role, service.Policy.ApprovalThreshold, strings.Join(service.Policy.ApproverRoles, " or ")))
+	}
+	if orderReturn.Status != domain.ReturnPending {
+		// the decision was taken already
+		return orderReturn, nil
+	}
+	orderReturn.ApprovedBy = employee.EmployeeID
+	if request.Note != "" {
+		orderReturn.Note = request.Note
+	}
+	return orderReturn, nil
+}
+
+// complete puts the restocked lines of a return back in the stock of the store, takes back the
+// loyalty points the refund had earned and pays the refund back, but for the cards: the return
+// is left refunding until settle has them refunded by the gateway
+func (service *ReturnServiceImpl) complete(ctx context.Context, orderReturn *domain.Return, order domain.Order, previous []domain.Return) error {
+	restocked := map[stockKey]int{}
+	for _, line := range orderReturn.Lines {
+		if line.Disposition == domain.DispositionRestock {
+			restocked[stockKey{line.ProductID, line.VariantID}] += line.Quantity
+		}
+	}
+	for _, line := range orderReturn.Lines {
+		item := stockKey{line.ProductID, line.VariantID}
+		quantity, ok := restocked[item]
+		if !ok {
+			continue
+		}
+		delete(restocked, item)
+		if err := service.restock(ctx, order.StoreID, line.ProductID, line.VariantID, quantity); err != nil {
+			return err
+		}
+	}
+
+	points, err := service.reversePoints(ctx, *orderReturn, order, previous)
+	if err != nil {
+		return err
+	}
+	refunds, err := service.refund(ctx, order, orderReturn.Refund)
+	if err != nil {
+		return err
+	}
+	orderReturn.Status = domain.ReturnRefunding
+	orderReturn.PointsReversed = points
+	orderReturn.Refunds = refunds
+	return nil
+}
+
+// settle has the gateway refund the pending card refunds of a refunding return, out of the
+// transactions, then records them on the payment and completes the return once none is left
+// pending. A refund the gateway fails stays pending and its error is returned: approving the
+// return again retries it.
+func (service *ReturnServiceImpl) settle(ctx context.Context, orderReturn domain.Return) (web.ReturnResponse, error) {
+	var refundErr error
+	refunded := map[string]bool{}
+	for i := range orderReturn.Refunds {
+		refund := &orderReturn.Refunds[i]
+		if !refund.Pending {
+			continue
+		}
+		if _, err := service.Provider.Refund(ctx, refund.ChargeID, refund.Amount); err != nil {
+			refundErr = err
+			break
+		}
+		refund.Pending = false
+		refunded[refund.ChargeID] = true
+	}
+	completed := !slices.ContainsFunc(orderReturn.Refunds, func(refund domain.ReturnRefund) bool { return refund.Pending })
+
+	var response web.ReturnResponse
+	err := service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.recordRefunds(ctx, orderReturn.OrderID, refunded); err != nil {
+			return err
+		}
+		if completed {
+			now := time.Now()
+			orderReturn.Status = domain.ReturnCompleted
+			orderReturn.CompletedAt = &now
+		}
+		updated, err := service.update(ctx, orderReturn)
+		if err != nil {
+			return err
+		}
+		response = helper.ToReturnResponse(updated)
+		if completed {
+			return service.Events.Publish(ctx, event.ReturnCompleted, response)
+		}
+		return nil
+	})
+	if err = errors.Join(err, refundErr); err != nil {
+		return web.ReturnResponse{}, err
+	}
+	return response, nil
+}
+
+// recordRefunds marks the card tenders of the payment of an order refunded by the gateway once
+// all of their amount is
+func (service *ReturnServiceImpl) recordRefunds(ctx context.Context, orderId string, refunded map[string]bool) error {
+	if len(refunded) == 0 {
+		return nil
+	}
+	payments, err := service.PaymentRepository.FindByOrder(ctx, orderId)
+	if err != nil {
+		return err
+	}
+	for _, payment := range payments {
+		changed := false
+		for i := range payment.Tenders {
+			tender := &payment.Tenders[i]
+			if tender.Method == domain.TenderCard && refunded[tender.ChargeID] && tender.Refunded.Cmp(tender.Amount) >= 0 {
+				tender.Status = gateway.StatusRefunded
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		_, ok, err := service.PaymentRepository.Update(ctx, payment)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exception.NewBadRequestError(fmt.Sprintf("payment %s was changed by another request, approve return again", payment.PaymentID))
+		}
+	}
+	return nil
+}
+
+func (service *ReturnServiceImpl) restock(ctx context.Context, storeId string, productId string, variantId string, quantity int) error {
+	ok, err := adjustStoreStock(ctx, service.StoreStockRepository, service.Events, storeId, productId, variantId, quantity)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return exception.NewBadRequestError(fmt.Sprintf("%s is not stocked in store %s", stockItem(productId, variantId), storeId))
+	}
+	return nil
+}
+
+// reversePoints takes back the points the order would not have earned without the refunds of
+// its completed returns, as far as the customer still has them, and returns how many
+func (service *ReturnServiceImpl) reversePoints(ctx context.Context, orderReturn domain.Return, order domain.Order, previous []domain.Return) (int, error) {
+	if order.CustomerID == "" || order.PointsEarned == 0 {
+		return 0, nil
+	}
+	kept := order.Total.Sub(orderReturn.Refund)
+	reversed := 0
+	for _, other := range previous {
+		if other.Status == domain.ReturnCompleted || other.Status == domain.ReturnRefunding {
+			kept = kept.Sub(other.Refund)
+			reversed += other.PointsReversed
+		}
+	}
+	points := order.PointsEarned - int(kept.Amount()/pointValue.Amount()) - reversed
+	if points <= 0 {
+		return 0, nil
+	}
+
+	customer, err := service.CustomerRepository.FindById(ctx, order.CustomerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if points > customer.LoyaltyPts {
+		points = customer.LoyaltyPts
+	}
+	if points == 0 {
+		return 0, nil
+	}
+	if _, err := service.CustomerRepository.AdjustLoyaltyPoints(ctx, order.CustomerID, -points); err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+// refund pays amount back with the tenders of the payment of an order: the cards first, then
+// the loyalty points redeemed, then cash. The card refunds are only reserved on their tenders
+// and left pending for settle. An order without any payment was paid outside of the payments
+// API and is refunded in cash; an order whose payment is pending or failed has nothing to refund.
+func (service *ReturnServiceImpl) refund(ctx context.Context, order domain.Order, amount money.Money) ([]domain.ReturnRefund, error) {
+	payments, err := service.PaymentRepository.FindByOrder(ctx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	var payment *domain.Payment
+	for i := range payments {
+		if payments[i].Status == domain.PaymentPaid {
+			payment = &payments[i]
+		}
+	}
+	if payment == nil && len(payments) > 0 {
+		last := payments[len(payments)-1]
+		return nil, exception.NewBadRequestError(fmt.Sprintf("order %s is not paid, its payment %s is %s", order.OrderID, last.PaymentID, last.Status))
+	}
+	if payment == nil {
+		return []domain.ReturnRefund{{Method: domain.TenderCash, Amount: amount}}, nil
+	}
+
+	var refunds []domain.ReturnRefund
+	left := amount
+	for _, method := range []string{domain.TenderCard, domain.TenderPoints, domain.TenderCash} {
+		for i := range payment.Tenders {
+			tender := &payment.Tenders[i]
+			if tender.Method != method || !left.IsPositive() {
+				continue
+			}
+			part := left.Min(tender.Amount.Sub(tender.Refunded))
+			if !part.IsPositive() {
+				continue
+			}
+			refund := domain.ReturnRefund{Method: method, Amount: part}
+			switch method {
+			case domain.TenderCard:
+				if tender.Status != gateway.StatusCaptured {
+					continue
+				}
+				refund.ChargeID = tender.ChargeID
+				refund.Pending = true
+			case domain.TenderPoints:
+				refund.Points = int(part.Amount() / pointRedemptionValue.Amount())
+				if refund.Points == 0 {
+					continue
+				}
+				part = pointRedemptionValue.Mul(int64(refund.Points))
+				refund.Amount = part
+				if _, err := service.CustomerRepository.AdjustLoyaltyPoints(ctx, payment.CustomerID, refund.Points); err != nil {
+					return nil, err
+				}
+			}
+			tender.Refunded = tender.Refunded.Add(part)
+			left = left.Sub(part)
+			refunds = append(refunds, refund)
+		}
+	}
+	if left.IsPositive() {
+		// what the rounding of the points left, paid in cash
+		refunds = append(refunds, domain.ReturnRefund{Method: domain.TenderCash, Amount: left})
+	}
+
+	_, ok, err := service.PaymentRepository.Update(ctx, *payment)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, exception.NewBadRequestError(fmt.Sprintf("payment %s was changed by another request, retry", payment.PaymentID))
+	}
+	return refunds, nil
+}
+
+func (service *ReturnServiceImpl) update(ctx context.Context, orderReturn domain.Return) (domain.Return, error) {
+	updated, ok, err := service.ReturnRepository.Update(ctx, orderReturn)
+	if err != nil {
+		return domain.Return{}, err
+	}
+	if !ok {
+		return domain.Return{}, exception.NewBadRequestError(fmt.Sprintf("return %s was changed by another request, reload it and retry", orderReturn.ReturnID))
+	}
+	return updated, nil
+}
+
+func (service *ReturnServiceImpl) findOrder(ctx context.Context, principal auth.Principal, orderId string) (domain.Order, error) {
+	order, err := service.OrderRepository.FindById(ctx, orderId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Order{}, exception.NewNotFoundError("Order not found")
+	} else if err != nil {
+		return domain.Order{}, err
+	}
+	if err := requireStore(principal, order.StoreID); err != nil {
+		return domain.Order{}, err
+	}
+	return order, nil
+}
+
+func (service *ReturnServiceImpl) findReturn(ctx context.Context, principal auth.Principal, returnId string) (domain.Return, error) {
+	orderReturn, err := service.ReturnRepository.FindById(ctx, returnId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Return{}, exception.NewNotFoundError("Return not found")
+	} else if err != nil {
+		return domain.Return{}, err
+	}
+	if err := requireStore(principal, orderReturn.StoreID); err != nil {
+		return domain.Return{}, err
+	}
+	return orderReturn, nil
+}
+
+func (service *ReturnServiceImpl) findEmployee(ctx context.Context, employeeId string) (domain.Employee, error) {
+	employee, err := service.EmployeeRepository.FindById(ctx, employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Employee{}, exception.NewBadRequestError(fmt.Sprintf("unknown employee %s", employeeId))
+	}
+	return employee, err
+}
+
+// toReturn prices the lines of a return. A line may not return more than what is left of its
+// order line after the other returns not rejected, and its amounts are the share of those of
+// the order line: the returns of a whole line add up to exactly what it was sold for.
+func toReturn(order domain.Order, previous []domain.Return, request web.ReturnCreateRequest) (domain.Return, error) {
+	returned := map[int]int{}
+	for _, other := range previous {
+		if other.Status == domain.ReturnRejected {
+			continue
+		}
+		for _, line := range other.Lines {
+			returned[line.OrderPosition] += line.Quantity
+		}
+	}
+
+	zero := money.New(0, order.Total.Currency())
+	orderReturn := domain.Return{
+		OrderID:    order.OrderID,
+		StoreID:    order.StoreID,
+		CustomerID: order.CustomerID,
+		EmployeeID: request.EmployeeID,
+		Status:     domain.ReturnCompleted,
+		Note:       request.Note,
+		Subtotal:   zero,
+		Discount:   zero,
+		Tax:        zero,
+		Refund:     zero,
+		Refunds:    []domain.ReturnRefund{},
+	}
+	requested := map[int]bool{}
+	for i, lineRequest := range request.Lines {
+		if lineRequest.Position > len(order.Lines) {
+			return domain.Return{}, exception.NewBadRequestError(fmt.Sprintf("order %s has no line %d", order.OrderID, lineRequest.Position))
+		}
+		if requested[lineRequest.Position] {
+			return domain.Return{}, exception.NewBadRequestError(fmt.Sprintf("line %d is returned twice", lineRequest.Position))
+		}
+		requested[lineRequest.Position] = true
+		sold := order.Lines[lineRequest.Position-1]
+		before := returned[sold.Position]
+		if lineRequest.Quantity > sold.Quantity-before {
+			return domain.Return{}, exception.NewBadRequestError(fmt.Sprintf("%d of %s are returned, %d of the %d sold are left to return", lineRequest.Quantity, sold.Name, sold.Quantity-before, sold.Quantity))
+		}
+
+		share := func(amount money.Money) money.Money {
+			after := before + lineRequest.Quantity
+			return prorate(amount, after, sold.Quantity).Sub(prorate(amount, before, sold.Quantity))
+		}
+		line := domain.ReturnLine{
+			Position:      i + 1,
+			OrderPosition: sold.Position,
+			ProductID:     sold.ProductID,
+			VariantID:     sold.VariantID,
+			Name:          sold.Name,
+			Quantity:      lineRequest.Quantity,
+			Reason:        lineRequest.Reason,
+			Disposition:   lineRequest.Disposition,
+			Subtotal:      share(sold.Subtotal),
+			Discount:      share(sold.Discount),
+			Tax:           share(sold.Tax),
+			Refund:        share(sold.Total),
+		}
+		orderReturn.Lines = append(orderReturn.Lines, line)
+		orderReturn.Subtotal = orderReturn.Subtotal.Add(line.Subtotal)
+		orderReturn.Discount = orderReturn.Discount.Add(line.Discount)
+		orderReturn.Tax = orderReturn.Tax.Add(line.Tax)
+		orderReturn.Refund = orderReturn.Refund.Add(line.Refund)
+	}
+	return orderReturn, nil
+}
+
+// prorate is the share of amount of quantity units out of total
+func prorate(amount money.Money, quantity int, total int) money.Money {
+	return amount.Allocate(int64(quantity), int64(total-quantity))[0]
+}
@@ -0,0 +1,429 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type returnMocks struct {
+	orders    *mocks.MockOrderRepository
+	returns   *mocks.MockReturnRepository
+	payments  *mocks.MockPaymentRepository
+	employees *mocks.MockEmployeeRepository
+	customers *mocks.MockCustomerRepository
+	stocks    *mocks.MockStoreStockRepository
+	gateway   *payment.Fake
+}
+
+var returnPolicy = service.ReturnPolicy{ApprovalThreshold: money.IDR(50000), ApproverRoles: []string{"supervisor", "manager"}}
+
+func setupReturnService(t *testing.T, publisher *recordingPublisher) (service.ReturnService, returnMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := returnMocks{
+		orders:    mocks.NewMockOrderRepository(ctrl),
+		returns:   mocks.NewMockReturnRepository(ctrl),
+		payments:  mocks.NewMockPaymentRepository(ctrl),
+		employees: mocks.NewMockEmployeeRepository(ctrl),
+		customers: mocks.NewMockCustomerRepository(ctrl),
+		stocks:    mocks.NewMockStoreStockRepository(ctrl),
+		gateway:   payment.NewFake("rahasia", time.Minute),
+	}
+	returnService := service.NewReturnService(repositories.orders, repositories.returns, repositories.payments, repositories.employees, repositories.customers, repositories.stocks, repositories.gateway, returnPolicy, fakeTransactor{}, publisher, validator.New())
+	return returnService, repositories
+}
+
+// returnedOrder sold 3 Kopi with a discount and a Roti, both taxed at 11%
+var returnedOrder = domain.Order{
+	OrderID: "O1", StoreID: "JKT01", CustomerID: "C001",
+	Subtotal: money.IDR(125000), Discount: money.IDR(10000), Tax: money.IDR(12650), Total: money.IDR(127650), PointsEarned: 12,
+	Lines: []domain.OrderLine{
+		{OrderID: "O1", Position: 1, ProductID: "P002", Name: "Kopi", Quantity: 3, Subtotal: money.IDR(105000), Discount: money.IDR(10000), Tax: money.IDR(10450), Total: money.IDR(105450)},
+		{OrderID: "O1", Position: 2, ProductID: "P003", Name: "Roti", Quantity: 1, Subtotal: money.IDR(20000), Discount: money.IDR(0), Tax: money.IDR(2200), Total: money.IDR(22200)},
+	},
+}
+
+var (
+	returnCashier    = domain.Employee{EmployeeID: "E001", Role: "cashier"}
+	returnSupervisor = domain.Employee{EmployeeID: "E002", Role: "Supervisor"}
+	// supervisor is the key bound to returnSupervisor
+	supervisor = auth.Principal{Name: "rina", Permissions: []string{auth.StorePermission("JKT01"), auth.EmployeePermission("E002")}}
+)
+
+// expectReturnsOf reads the returns of O1 under the lock of the order
+func expectReturnsOf(repositories returnMocks, returns []domain.Return) {
+	gomock.InOrder(
+		repositories.orders.EXPECT().Lock(gomock.Any(), "O1").Return(nil),
+		repositories.returns.EXPECT().FindByOrder(gomock.Any(), "O1").Return(returns, nil),
+	)
+}
+
+// expectReturnSave saves the return with an ID
+func expectReturnSave(repositories returnMocks) {
+	repositories.returns.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, r domain.Return) (domain.Return, error) {
+		r.ReturnID = "R1"
+		return r, nil
+	})
+}
+
+// expectReturnUpdate updates the return, once it is completed or refunding
+func expectReturnUpdate(repositories returnMocks) *gomock.Call {
+	return repositories.returns.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, r domain.Return) (domain.Return, bool, error) {
+		r.Version++
+		return r, true, nil
+	})
+}
+
+// expectRestock puts quantity of a product back in the stock of JKT01
+func expectRestock(repositories returnMocks, productId string, quantity int) {
+	repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", productId, quantity).Return(true, nil)
+	repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", productId).Return(domain.StoreStock{StoreID: "JKT01", ProductID: productId, StockQty: 10}, nil)
+}
+
+func TestCreateReturn(t *testing.T) {
+	kopi := func(quantity int, disposition string) web.ReturnCreateRequest {
+		return web.ReturnCreateRequest{OrderID: "O1", EmployeeID: "E001", Lines: []web.ReturnLineRequest{
+			{Position: 1, Quantity: quantity, Reason: domain.ReasonDamaged, Disposition: disposition},
+		}}
+	}
+
+	t.Run("partial returns add up to the line", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		returnService, repositories := setupReturnService(t, publisher)
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(returnCashier, nil)
+		expectReturnsOf(repositories, nil)
+		expectRestock(repositories, "P002", 1)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 50}, nil)
+		repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -3).Return(true, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").Return(nil, nil)
+		expectReturnSave(repositories)
+		expectReturnUpdate(repositories)
+
+		first, err := returnService.Create(context.Background(), cashier, kopi(1, domain.DispositionRestock))
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnCompleted, first.Status)
+		assert.Equal(t, money.IDR(35150), first.Refund)
+		assert.Equal(t, 3, first.PointsReversed, "12 points earned, 9 kept on 92500")
+		assert.Equal(t, []web.ReturnRefundResponse{{Method: domain.TenderCash, Amount: money.IDR(35150)}}, first.Refunds)
+		assert.Equal(t, []string{event.StoreStockChanged, event.ReturnCreated, event.ReturnCompleted}, publisher.types)
+
+		previous := domain.Return{ReturnID: "R1", Status: domain.ReturnCompleted, Refund: first.Refund, PointsReversed: first.PointsReversed, Lines: []domain.ReturnLine{
+			{OrderPosition: 1, Quantity: 1, Subtotal: first.Subtotal, Discount: first.Discount, Tax: first.Tax, Refund: first.Refund},
+		}}
+		returnService, repositories = setupReturnService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		expectReturnsOf(repositories, []domain.Return{previous})
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 4}, nil)
+		repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -4).Return(true, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").Return(nil, nil)
+		expectReturnSave(repositories)
+		expectReturnUpdate(repositories)
+
+		request := kopi(2, domain.DispositionWriteOff)
+		request.EmployeeID = "E002"
+		second, err := returnService.Create(context.Background(), supervisor, request)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnCompleted, second.Status, "a supervisor approves the return processing it")
+		assert.Equal(t, "E002", second.ApprovedBy)
+		assert.Equal(t, money.IDR(105450), first.Refund.Add(second.Refund))
+		assert.Equal(t, money.IDR(10000), first.Discount.Add(second.Discount))
+		assert.Equal(t, money.IDR(10450), first.Tax.Add(second.Tax))
+		assert.Equal(t, 4, second.PointsReversed, "7 more points are due, the customer only has 4")
+	})
+
+	t.Run("pending above the threshold", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		returnService, repositories := setupReturnService(t, publisher)
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(returnCashier, nil)
+		expectReturnsOf(repositories, nil)
+		expectReturnSave(repositories)
+
+		response, err := returnService.Create(context.Background(), cashier, kopi(2, domain.DispositionRestock))
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnPending, response.Status)
+		assert.Empty(t, response.ApprovedBy)
+		assert.Empty(t, response.Refunds, "nothing is refunded before the approval")
+		assert.Equal(t, []string{event.ReturnCreated}, publisher.types)
+	})
+
+	t.Run("supervisor on another key", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		expectReturnsOf(repositories, nil)
+		expectReturnSave(repositories)
+
+		request := kopi(2, domain.DispositionRestock)
+		request.EmployeeID = "E002"
+		response, err := returnService.Create(context.Background(), cashier, request)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnPending, response.Status, "the key is not bound to the supervisor")
+	})
+
+	pending := domain.Return{ReturnID: "R9", OrderID: "O1", StoreID: "JKT01", Status: domain.ReturnPending, Lines: []domain.ReturnLine{{OrderPosition: 1, Quantity: 2}}}
+	tests := []struct {
+		name     string
+		request  web.ReturnCreateRequest
+		previous []domain.Return
+		message  string
+	}{
+		{name: "more than sold", request: kopi(4, domain.DispositionRestock), message: "3 of the 3 sold are left"},
+		{name: "more than left", request: kopi(2, domain.DispositionRestock), previous: []domain.Return{pending}, message: "1 of the 3 sold are left"},
+		{name: "unknown line", request: web.ReturnCreateRequest{OrderID: "O1", EmployeeID: "E001", Lines: []web.ReturnLineRequest{
+			{Position: 3, Quantity: 1, Reason: domain.ReasonDamaged, Disposition: domain.DispositionRestock},
+		}}, message: "has no line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			returnService, repositories := setupReturnService(t, &recordingPublisher{})
+			repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+			repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(returnCashier, nil)
+			expectReturnsOf(repositories, tt.previous)
+
+			_, err := returnService.Create(context.Background(), cashier, tt.request)
+			assert.ErrorAs(t, err, &exception.BadRequestError{})
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+
+	t.Run("rejected returns are not counted", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		rejected := pending
+		rejected.Status = domain.ReturnRejected
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(returnCashier, nil)
+		expectReturnsOf(repositories, []domain.Return{rejected})
+		expectReturnSave(repositories)
+
+		response, err := returnService.Create(context.Background(), cashier, kopi(3, domain.DispositionRestock))
+		require.NoError(t, err)
+		assert.Equal(t, money.IDR(105450), response.Refund)
+	})
+
+	t.Run("unknown employee", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(domain.Employee{}, gorm.ErrRecordNotFound)
+
+		_, err := returnService.Create(context.Background(), cashier, kopi(1, domain.DispositionRestock))
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		order := returnedOrder
+		order.StoreID = "BDG01"
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(order, nil)
+
+		_, err := returnService.Create(context.Background(), cashier, kopi(1, domain.DispositionRestock))
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+}
+
+func TestApproveReturn(t *testing.T) {
+	pending := domain.Return{
+		ReturnID: "R1", OrderID: "O1", StoreID: "JKT01", CustomerID: "C001", EmployeeID: "E001", Status: domain.ReturnPending,
+		Subtotal: money.IDR(70000), Discount: money.IDR(6667), Tax: money.IDR(6967), Refund: money.IDR(70300), Refunds: []domain.ReturnRefund{},
+		Lines: []domain.ReturnLine{{ReturnID: "R1", Position: 1, OrderPosition: 1, ProductID: "P002", Name: "Kopi", Quantity: 2, Disposition: domain.DispositionRestock, Refund: money.IDR(70300)}},
+	}
+	decision := web.ReturnDecisionRequest{ReturnID: "R1", Note: "box opened"}
+
+	t.Run("refunds the card first", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		returnService, repositories := setupReturnService(t, publisher)
+		ctx := context.Background()
+		charge, err := repositories.gateway.CreateCharge(ctx, payment.ChargeRequest{Key: "PAY1/1", Amount: money.IDR(50000), Token: payment.TokenSuccess})
+		require.NoError(t, err)
+		_, err = repositories.gateway.Capture(ctx, charge.ID)
+		require.NoError(t, err)
+		paid := domain.Payment{PaymentID: "PAY1", OrderID: "O1", CustomerID: "C001", Status: domain.PaymentPaid, Tenders: []domain.PaymentTender{
+			{Position: 1, Method: domain.TenderCash, Amount: money.IDR(72650), Refunded: money.IDR(0)},
+			{Position: 2, Method: domain.TenderPoints, Amount: money.IDR(5000), Points: 50, Refunded: money.IDR(0)},
+			{Position: 3, Method: domain.TenderCard, Amount: money.IDR(50000), ChargeID: charge.ID, Status: payment.StatusCaptured, Refunded: money.IDR(0)},
+		}}
+
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(pending, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		expectReturnsOf(repositories, []domain.Return{pending})
+		expectRestock(repositories, "P002", 2)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 50}, nil)
+		gomock.InOrder(
+			repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -7).Return(true, nil),
+			repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", 50).Return(true, nil),
+		)
+		// the first transaction reserves the refunds on the tenders, the second records the
+		// card refunded by the gateway
+		var reserved, updated domain.Payment
+		gomock.InOrder(
+			repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").Return([]domain.Payment{paid}, nil),
+			repositories.payments.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p domain.Payment) (domain.Payment, bool, error) {
+				reserved = p
+				return p, true, nil
+			}),
+			expectReturnUpdate(repositories).Do(func(ctx context.Context, r domain.Return) {
+				assert.Equal(t, domain.ReturnRefunding, r.Status)
+				assert.True(t, r.Refunds[0].Pending)
+			}),
+			repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").DoAndReturn(func(ctx context.Context, orderId string) ([]domain.Payment, error) {
+				return []domain.Payment{reserved}, nil
+			}),
+			repositories.payments.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p domain.Payment) (domain.Payment, bool, error) {
+				updated = p
+				return p, true, nil
+			}),
+			expectReturnUpdate(repositories),
+		)
+
+		response, err := returnService.Approve(ctx, supervisor, decision)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnCompleted, response.Status)
+		assert.Equal(t, "E002", response.ApprovedBy)
+		assert.Equal(t, "box opened", response.Note)
+		assert.Equal(t, 7, response.PointsReversed)
+		assert.Equal(t, []web.ReturnRefundResponse{
+			{Method: domain.TenderCard, Amount: money.IDR(50000), ChargeID: charge.ID},
+			{Method: domain.TenderPoints, Amount: money.IDR(5000), Points: 50},
+			{Method: domain.TenderCash, Amount: money.IDR(15300)},
+		}, response.Refunds)
+		assert.Equal(t, payment.StatusRefunded, updated.Tenders[2].Status)
+		assert.Equal(t, money.IDR(15300), updated.Tenders[0].Refunded)
+		refunded, err := repositories.gateway.Status(ctx, charge.ID)
+		require.NoError(t, err)
+		assert.Equal(t, money.IDR(50000), refunded.Refunded)
+		assert.Equal(t, []string{event.StoreStockChanged, event.ReturnCompleted}, publisher.types)
+	})
+
+	t.Run("card refund retried", func(t *testing.T) {
+		ctx := context.Background()
+		card := domain.PaymentTender{Position: 1, Method: domain.TenderCard, Amount: money.IDR(127650), ChargeID: "ch_unknown", Status: payment.StatusCaptured, Refunded: money.IDR(0)}
+		paid := domain.Payment{PaymentID: "PAY1", OrderID: "O1", Status: domain.PaymentPaid, Tenders: []domain.PaymentTender{card}}
+
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(pending, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		expectReturnsOf(repositories, []domain.Return{pending})
+		expectRestock(repositories, "P002", 2)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 50}, nil)
+		repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -7).Return(true, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").Return([]domain.Payment{paid}, nil)
+		repositories.payments.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p domain.Payment) (domain.Payment, bool, error) {
+			return p, true, nil
+		})
+		var refunding domain.Return
+		expectReturnUpdate(repositories).Times(2).Do(func(ctx context.Context, r domain.Return) {
+			refunding = r
+		})
+
+		_, err := returnService.Approve(ctx, supervisor, decision)
+		assert.Error(t, err, "the gateway does not know the charge")
+		assert.Equal(t, domain.ReturnRefunding, refunding.Status, "the return stays refunding")
+		assert.Nil(t, refunding.CompletedAt)
+		assert.Equal(t, []domain.ReturnRefund{{Method: domain.TenderCard, Amount: money.IDR(70300), ChargeID: "ch_unknown", Pending: true}}, refunding.Refunds)
+
+		// approving it again once the gateway knows the charge refunds the card, and nothing else;
+		// the tender is partly refunded and stays captured
+		returnService, repositories = setupReturnService(t, &recordingPublisher{})
+		charge, err := repositories.gateway.CreateCharge(ctx, payment.ChargeRequest{Key: "PAY1/1", Amount: money.IDR(127650), Token: payment.TokenSuccess})
+		require.NoError(t, err)
+		_, err = repositories.gateway.Capture(ctx, charge.ID)
+		require.NoError(t, err)
+		refunding.Refunds[0].ChargeID = charge.ID
+		paid.Tenders[0].ChargeID = charge.ID
+		paid.Tenders[0].Refunded = money.IDR(70300)
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(refunding, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").Return([]domain.Payment{paid}, nil)
+		expectReturnUpdate(repositories)
+
+		response, err := returnService.Approve(ctx, supervisor, decision)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnCompleted, response.Status)
+		assert.Equal(t, "E002", response.ApprovedBy)
+		assert.Equal(t, []web.ReturnRefundResponse{{Method: domain.TenderCard, Amount: money.IDR(70300), ChargeID: charge.ID}}, response.Refunds)
+	})
+
+	t.Run("order not paid", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(pending, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		repositories.orders.EXPECT().FindById(gomock.Any(), "O1").Return(returnedOrder, nil)
+		expectReturnsOf(repositories, []domain.Return{pending})
+		expectRestock(repositories, "P002", 2)
+		repositories.customers.EXPECT().FindById(gomock.Any(), "C001").Return(domain.Customer{CustomerID: "C001", LoyaltyPts: 50}, nil)
+		repositories.customers.EXPECT().AdjustLoyaltyPoints(gomock.Any(), "C001", -7).Return(true, nil)
+		repositories.payments.EXPECT().FindByOrder(gomock.Any(), "O1").Return([]domain.Payment{
+			{PaymentID: "PAY1", OrderID: "O1", Status: domain.PaymentFailed},
+			{PaymentID: "PAY2", OrderID: "O1", Status: domain.PaymentPending},
+		}, nil)
+
+		_, err := returnService.Approve(context.Background(), supervisor, decision)
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+		assert.Contains(t, err.Error(), "payment PAY2 is pending", "nothing is refunded in cash")
+	})
+
+	t.Run("by a cashier", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(pending, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E001").Return(returnCashier, nil)
+		bound := auth.Principal{Name: "kasir", Permissions: []string{auth.StorePermission("JKT01"), auth.EmployeePermission("E001")}}
+
+		_, err := returnService.Approve(context.Background(), bound, decision)
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+
+	t.Run("on behalf of a supervisor", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(pending, nil).Times(2)
+
+		_, err := returnService.Approve(context.Background(), cashier, web.ReturnDecisionRequest{ReturnID: "R1", EmployeeID: "E002"})
+		assert.ErrorAs(t, err, &exception.ForbiddenError{}, "the key is not bound to the supervisor")
+		_, err = returnService.Approve(context.Background(), cashier, decision)
+		assert.ErrorAs(t, err, &exception.BadRequestError{}, "the key is bound to no employee")
+	})
+
+	t.Run("already decided", func(t *testing.T) {
+		returnService, repositories := setupReturnService(t, &recordingPublisher{})
+		completed := pending
+		completed.Status = domain.ReturnCompleted
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(completed, nil)
+
+		_, err := returnService.Reject(context.Background(), cashier, decision)
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		returnService, repositories := setupReturnService(t, publisher)
+		repositories.returns.EXPECT().FindById(gomock.Any(), "R1").Return(pending, nil)
+		repositories.employees.EXPECT().FindById(gomock.Any(), "E002").Return(returnSupervisor, nil)
+		repositories.returns.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, r domain.Return) (domain.Return, bool, error) {
+			return r, true, nil
+		})
+
+		response, err := returnService.Reject(context.Background(), supervisor, decision)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReturnRejected, response.Status)
+		assert.Empty(t, response.Refunds)
+		assert.Equal(t, []string{event.ReturnRejected}, publisher.types)
+	})
+}
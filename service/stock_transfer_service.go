@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+// StockTransferService moves stock between stores and the central stock. Preparing, cancelling
+// and dispatching a transfer requires the permission of its source, receiving it the permission
+// of its destination; the central stock requires auth.CrossStore.
+type StockTransferService interface {
+	Create(ctx context.Context, principal auth.Principal, request web.StockTransferCreateRequest) (web.StockTransferResponse, error)
+	Update(ctx context.Context, principal auth.Principal, request web.StockTransferUpdateRequest) (web.StockTransferResponse, error)
+	Cancel(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error)
+	Dispatch(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error)
+	Receive(ctx context.Context, principal auth.Principal, request web.StockTransferReceiveRequest) (web.StockTransferResponse, error)
+	FindById(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error)
+	// FindAll lists the transfers of a store, of every store when storeId is empty. status is a
+	// transfer status, "open" for the transfers still expecting an action, or empty for all.
+	FindAll(ctx context.Context, storeId string, status string) ([]web.StockTransferResponse, error)
+}
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// openTransfers is the status filter of FindAll for the transfers still expecting an action
+const openTransfers = "open"
+
+type StockTransferServiceImpl struct {
+	StockTransferRepository repository.StockTransferRepository
+	StoreRepository         repository.StoreRepository
+	StoreStockRepository    repository.StoreStockRepository
+	ProductRepository       repository.ProductRepository
+	Transactor              repository.Transactor
+	Events                  event.Publisher
+	Validate                *validator.Validate
+}
+
+func NewStockTransferService(stockTransferRepository repository.StockTransferRepository, storeRepository repository.StoreRepository, storeStockRepository repository.StoreStockRepository, productRepository repository.ProductRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) StockTransferService {
+	return &StockTransferServiceImpl{
+		StockTransferRepository: stockTransferRepository,
+		StoreRepository:         storeRepository,
+		StoreStockRepository:    storeStockRepository,
+		ProductRepository:       productRepository,
+		Transactor:              transactor,
+		Events:                  events,
+		Validate:                validate,
+	}
+}
+
+// Create a draft transfer, no stock moves until it is dispatched
+func (service *StockTransferServiceImpl) Create(ctx context.Context, principal auth.Principal, request web.StockTransferCreateRequest) (web.StockTransferResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireLocation(principal, request.SourceStoreID); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	for _, storeId := range []string{request.SourceStoreID, request.DestinationStoreID} {
+		if err := service.checkLocation(ctx, storeId); err != nil {
+			return web.StockTransferResponse{}, err
+		}
+	}
+	lines, err := service.toLines(ctx, request.Lines)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+
+	transfer := domain.StockTransfer{
+		SourceStoreID:      request.SourceStoreID,
+		DestinationStoreID: request.DestinationStoreID,
+		Status:             domain.TransferDraft,
+		Note:               request.Note,
+		Lines:              lines,
+	}
+
+	var response web.StockTransferResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedTransfer, err := service.StockTransferRepository.Save(ctx, transfer)
+		if err != nil {
+			return err
+		}
+		response = helper.ToStockTransferResponse(savedTransfer)
+		return service.Events.Publish(ctx, event.TransferCreated, response)
+	})
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	return response, nil
+}
+
+// Update the note and the lines of a draft transfer
+func (service *StockTransferServiceImpl) Update(ctx context.Context, principal auth.Principal, request web.StockTransferUpdateRequest) (web.StockTransferResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	transfer, err := service.findTransfer(ctx, request.TransferID)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireLocation(principal, transfer.SourceStoreID); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireStatus(transfer, domain.TransferDraft); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	lines, err := service.toLines(ctx, request.Lines)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	transfer.Note = request.Note
+	transfer.Lines = lines
+
+	var response web.StockTransferResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		response, err = service.update(ctx, transfer)
+		if err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.TransferUpdated, response)
+	})
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	return response, nil
+}
+
+// Cancel a draft transfer
+func (service *StockTransferServiceImpl) Cancel(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error) {
+	transfer, err := service.findTransfer(ctx, transferId)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireLocation(principal, transfer.SourceStoreID); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireStatus(transfer, domain.TransferDraft); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	transfer.Status = domain.TransferCancelled
+
+	var response web.StockTransferResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		response, err = service.update(ctx, transfer)
+		if err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.TransferCancelled, response)
+	})
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	return response, nil
+}
+
+// Dispatch a draft transfer, its quantities leave the source and are in transit until received
+func (service *StockTransferServiceImpl) Dispatch(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error) {
+	transfer, err := service.findTransfer(ctx, transferId)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireLocation(principal, transfer.SourceStoreID); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireStatus(transfer, domain.TransferDraft); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	now := time.Now()
+	transfer.Status = domain.TransferDispatched
+	transfer.DispatchedAt = &now
+
+	var response web.StockTransferResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, line := range transfer.Lines {
+			if err := service.moveStock(ctx, transfer.SourceStoreID, line.ProductID, line.VariantID, -line.Quantity); err != nil {
+				return err
+			}
+		}
+		response, err = service.update(ctx, transfer)
+		if err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.TransferDispatched, response)
+	})
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	return response, nil
+}
+
+// Receive adds the received quantities to the destination. The transfer is closed once every
+// line is complete or when the request completes it, what is still missing is then recorded as
+// a discrepancy.
+func (service *StockTransferServiceImpl) Receive(ctx context.Context, principal auth.Principal, request web.StockTransferReceiveRequest) (web.StockTransferResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	transfer, err := service.findTransfer(ctx, request.TransferID)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireLocation(principal, transfer.DestinationStoreID); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if err := requireStatus(transfer, domain.TransferDispatched, domain.TransferPartiallyReceived); err != nil {
+		return web.StockTransferResponse{}, err
+	}
+
+	lineIndex := make(map[stockKey]int, len(transfer.Lines))
+	for i, line := range transfer.Lines {
+		lineIndex[stockKey{line.ProductID, line.VariantID}] = i
+	}
+	for _, receipt := range request.Lines {
+		i, ok := lineIndex[stockKey{receipt.ProductID, receipt.VariantID}]
+		if !ok {
+			return web.StockTransferResponse{}, exception.NewBadRequestError(fmt.Sprintf("%s is not on transfer %s", stockItem(receipt.ProductID, receipt.VariantID), transfer.TransferID))
+		}
+		line := &transfer.Lines[i]
+		if line.ReceivedQty+receipt.Quantity > line.Quantity {
+			return web.StockTransferResponse{}, exception.NewBadRequestError(fmt.Sprintf("%s: %d received in total, only %d were dispatched", stockItem(receipt.ProductID, receipt.VariantID), line.ReceivedQty+receipt.Quantity, line.Quantity))
+		}
+		line.ReceivedQty += receipt.Quantity
+		if receipt.Reason != "" {
+			line.DiscrepancyReason = receipt.Reason
+		}
+	}
+
+	complete := request.Complete
+	if !complete {
+		complete = true
+		for _, line := range transfer.Lines {
+			if line.ReceivedQty < line.Quantity {
+				complete = false
+			}
+		}
+	}
+	eventType := event.TransferUpdated
+	transfer.Status = domain.TransferPartiallyReceived
+	if complete {
+		now := time.Now()
+		eventType = event.TransferReceived
+		transfer.Status = domain.TransferReceived
+		transfer.ReceivedAt = &now
+		for i := range transfer.Lines {
+			transfer.Lines[i].DiscrepancyQty = transfer.Lines[i].Quantity - transfer.Lines[i].ReceivedQty
+		}
+	}
+
+	var response web.StockTransferResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, receipt := range request.Lines {
+			if receipt.Quantity == 0 {
+				continue
+			}
+			if err := service.moveStock(ctx, transfer.DestinationStoreID, receipt.ProductID, receipt.VariantID, receipt.Quantity); err != nil {
+				return err
+			}
+		}
+		response, err = service.update(ctx, transfer)
+		if err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, eventType, response)
+	})
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	return response, nil
+}
+
+// Find Stock Transfer By ID, visible from its source and its destination
+func (service *StockTransferServiceImpl) FindById(ctx context.Context, principal auth.Principal, transferId string) (web.StockTransferResponse, error) {
+	transfer, err := service.findTransfer(ctx, transferId)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if requireLocation(principal, transfer.SourceStoreID) != nil {
+		if err := requireLocation(principal, transfer.DestinationStoreID); err != nil {
+			return web.StockTransferResponse{}, err
+		}
+	}
+	return helper.ToStockTransferResponse(transfer), nil
+}
+
+// Find All Stock Transfers of a store
+func (service *StockTransferServiceImpl) FindAll(ctx context.Context, storeId string, status string) ([]web.StockTransferResponse, error) {
+	var statuses []string
+	switch status {
+	case "":
+	case openTransfers:
+		statuses = domain.OpenTransferStatuses
+	case domain.TransferDraft, domain.TransferDispatched, domain.TransferPartiallyReceived, domain.TransferReceived, domain.TransferCancelled:
+		statuses = []string{status}
+	default:
+		return nil, exception.NewBadRequestError(fmt.Sprintf("unknown transfer status %q", status))
+	}
+
+	transfers, err := service.StockTransferRepository.FindAll(ctx, storeId, statuses)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToStockTransferResponses(transfers), nil
+}
+
+func (service *StockTransferServiceImpl) findTransfer(ctx context.Context, transferId string) (domain.StockTransfer, error) {
+	transfer, err := service.StockTransferRepository.FindById(ctx, transferId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.StockTransfer{}, exception.NewNotFoundError("Stock transfer not found")
+	}
+	return transfer, err
+}
+
+// update saves a transfer read earlier, failing when another request changed it in between
+func (service *StockTransferServiceImpl) update(ctx context.Context, transfer domain.StockTransfer) (web.StockTransferResponse, error) {
+	updated, ok, err := service.StockTransferRepository.Update(ctx, transfer)
+	if err != nil {
+		return web.StockTransferResponse{}, err
+	}
+	if !ok {
+		return web.StockTransferResponse{}, exception.NewBadRequestError(fmt.Sprintf("transfer %s was changed by another request, reload it and retry", transfer.TransferID))
+	}
+	return helper.ToStockTransferResponse(updated), nil
+}
+
+// checkLocation checks that a store exists, the central stock always does
+func (service *StockTransferServiceImpl) checkLocation(ctx context.Context, storeId string) error {
+	if storeId == "" {
+		return nil
+	}
+	_, err := service.StoreRepository.FindById(ctx, storeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.NewBadRequestError(fmt.Sprintf("unknown store %s", storeId))
+	}
+	return err
+}
+
+// toLines checks that every product and variant exists and is listed once, a product with
+// variants is moved by variant
+func (service *StockTransferServiceImpl) toLines(ctx context.Context, requests []web.StockTransferLineRequest) ([]domain.StockTransferLine, error) {
+	lines := make([]domain.StockTransferLine, 0, len(requests))
+	seen := map[stockKey]bool{}
+	for _, request := range requests {
+		item := stockKey{request.ProductID, request.VariantID}
+		if seen[item] {
+			return nil, exception.NewBadRequestError(fmt.Sprintf("%s is listed twice", stockItem(request.ProductID, request.VariantID)))
+		}
+		seen[item] = true
+		product, err := service.ProductRepository.FindById(ctx, request.ProductID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBadRequestError(fmt.Sprintf("unknown product %s", request.ProductID))
+		} else if err != nil {
+			return nil, err
+		}
+		if err := checkVariant(product, request.VariantID); err != nil {
+			return nil, err
+		}
+		lines = append(lines, domain.StockTransferLine{ProductID: request.ProductID, VariantID: request.VariantID, Quantity: request.Quantity})
+	}
+	return lines, nil
+}
+
+// moveStock adds delta to the stock of a product, or of a variant of it, at a location, the
+// central stock when storeId is empty, and publishes the new stock level
+func (service *StockTransferServiceImpl) moveStock(ctx context.Context, storeId string, productId string, variantId string, delta int) error {
+	if storeId == "" {
+		var ok bool
+		var err error
+		if variantId != "" {
+			ok, err = service.ProductRepository.AdjustVariantStock(ctx, productId, variantId, delta)
+		} else {
+			ok, err = service.ProductRepository.AdjustStock(ctx, productId, delta)
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exception.NewBadRequestError(fmt.Sprintf("insufficient central stock of %s", stockItem(productId, variantId)))
+		}
+		product, err := service.ProductRepository.FindById(ctx, productId)
+		if err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.ProductStockChanged, helper.ToProductResponse(product))
+	}
+
+	ok, err := adjustStoreStock(ctx, service.StoreStockRepository, service.Events, storeId, productId, variantId, delta)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return exception.NewBadRequestError(fmt.Sprintf("insufficient stock of %s in store %s", stockItem(productId, variantId), storeId))
+	}
+	return nil
+}
+
+// requireLocation checks the permission on a store, auth.CrossStore for the central stock
+func requireLocation(principal auth.Principal, storeId string) error {
+	if storeId == "" {
+		if !principal.Can(auth.CrossStore) {
+			return exception.NewForbiddenError(fmt.Sprintf("%s is not allowed to move the central stock", principal.Name))
+		}
+		return nil
+	}
+	return requireStore(principal, storeId)
+}
+
+func requireStatus(transfer domain.StockTransfer, statuses ...string) error {
+	for _, status := range statuses {
+		if transfer.Status == status {
+			return nil
+		}
+	}
+	return exception.NewBadRequestError(fmt.Sprintf("transfer %s is %s", transfer.TransferID, transfer.Status))
+}
@@ -0,0 +1,283 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transferMocks struct {
+	transfers *mocks.MockStockTransferRepository
+	stores    *mocks.MockStoreRepository
+	stocks    *mocks.MockStoreStockRepository
+	products  *mocks.MockProductRepository
+}
+
+func setupStockTransferService(t *testing.T, publisher *recordingPublisher) (service.StockTransferService, transferMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := transferMocks{
+		transfers: mocks.NewMockStockTransferRepository(ctrl),
+		stores:    mocks.NewMockStoreRepository(ctrl),
+		stocks:    mocks.NewMockStoreStockRepository(ctrl),
+		products:  mocks.NewMockProductRepository(ctrl),
+	}
+	transferService := service.NewStockTransferService(repositories.transfers, repositories.stores, repositories.stocks, repositories.products, fakeTransactor{}, publisher, validator.New())
+	return transferService, repositories
+}
+
+// savedTransfer makes the mocked Update return the transfer it is given
+func savedTransfer(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, bool, error) {
+	return transfer, true, nil
+}
+
+func TestCreateStockTransfer(t *testing.T) {
+	request := web.StockTransferCreateRequest{
+		DestinationStoreID: "JKT01",
+		Lines:              []web.StockTransferLineRequest{{ProductID: "P002", Quantity: 20}},
+	}
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		request   web.StockTransferCreateRequest
+		mock      func(repositories transferMocks)
+		expectErr interface{}
+	}{
+		{
+			name:      "success",
+			principal: admin,
+			request:   request,
+			mock: func(repositories transferMocks) {
+				repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(domain.Product{ProductID: "P002"}, nil)
+				repositories.transfers.EXPECT().Save(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, transfer domain.StockTransfer) (domain.StockTransfer, error) {
+						transfer.TransferID = "T1"
+						return transfer, nil
+					})
+			},
+		},
+		{
+			name:      "central stock needs cross store",
+			principal: cashier,
+			request:   request,
+			mock:      func(repositories transferMocks) {},
+			expectErr: &exception.ForbiddenError{},
+		},
+		{
+			name:      "product listed twice",
+			principal: admin,
+			request: web.StockTransferCreateRequest{
+				DestinationStoreID: "JKT01",
+				Lines:              []web.StockTransferLineRequest{{ProductID: "P002", Quantity: 20}, {ProductID: "P002", Quantity: 5}},
+			},
+			mock: func(repositories transferMocks) {
+				repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+				repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(domain.Product{ProductID: "P002"}, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "same source and destination",
+			principal: admin,
+			request:   web.StockTransferCreateRequest{Lines: request.Lines},
+			mock:      func(repositories transferMocks) {},
+			expectErr: &validator.ValidationErrors{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			transferService, repositories := setupStockTransferService(t, publisher)
+			tt.mock(repositories)
+
+			response, err := transferService.Create(context.Background(), tt.principal, tt.request)
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				assert.Empty(t, publisher.types)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "T1", response.TransferID)
+			assert.Equal(t, domain.TransferDraft, response.Status)
+			assert.Equal(t, []string{event.TransferCreated}, publisher.types)
+		})
+	}
+}
+
+func TestDispatchStockTransfer(t *testing.T) {
+	draft := domain.StockTransfer{
+		TransferID:         "T1",
+		SourceStoreID:      "JKT01",
+		DestinationStoreID: "BDG01",
+		Status:             domain.TransferDraft,
+		Lines:              []domain.StockTransferLine{{TransferID: "T1", ProductID: "P002", Quantity: 20}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		transferService, repositories := setupStockTransferService(t, publisher)
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(draft, nil)
+		repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", "P002", -20).Return(true, nil)
+		repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", "P002").Return(domain.StoreStock{StoreID: "JKT01", ProductID: "P002", StockQty: 5}, nil)
+		repositories.transfers.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(savedTransfer)
+
+		response, err := transferService.Dispatch(context.Background(), cashier, "T1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransferDispatched, response.Status)
+		assert.NotNil(t, response.DispatchedAt)
+		assert.Equal(t, []string{event.StoreStockChanged, event.TransferDispatched}, publisher.types)
+	})
+
+	t.Run("insufficient stock", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		transferService, repositories := setupStockTransferService(t, publisher)
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(draft, nil)
+		repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", "P002", -20).Return(false, nil)
+
+		_, err := transferService.Dispatch(context.Background(), cashier, "T1")
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+		assert.Empty(t, publisher.types)
+	})
+
+	t.Run("already dispatched", func(t *testing.T) {
+		transferService, repositories := setupStockTransferService(t, &recordingPublisher{})
+		dispatched := draft
+		dispatched.Status = domain.TransferDispatched
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(dispatched, nil)
+
+		_, err := transferService.Dispatch(context.Background(), cashier, "T1")
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+
+	t.Run("destination cannot dispatch", func(t *testing.T) {
+		transferService, repositories := setupStockTransferService(t, &recordingPublisher{})
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(draft, nil)
+
+		bandung := auth.Principal{Name: "kasir bandung", Permissions: []string{auth.StorePermission("BDG01")}}
+		_, err := transferService.Dispatch(context.Background(), bandung, "T1")
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+}
+
+func TestReceiveStockTransfer(t *testing.T) {
+	// dispatched returns a new transfer every time, Receive updates the lines in place
+	dispatched := func() domain.StockTransfer {
+		return domain.StockTransfer{
+			TransferID:         "T1",
+			SourceStoreID:      "BDG01",
+			DestinationStoreID: "JKT01",
+			Status:             domain.TransferDispatched,
+			Lines: []domain.StockTransferLine{
+				{TransferID: "T1", ProductID: "P001", Quantity: 2},
+				{TransferID: "T1", ProductID: "P002", Quantity: 20},
+			},
+		}
+	}
+	expectStock := func(repositories transferMocks, productId string, quantity int) {
+		repositories.stocks.EXPECT().Adjust(gomock.Any(), "JKT01", productId, quantity).Return(true, nil)
+		repositories.stocks.EXPECT().FindById(gomock.Any(), "JKT01", productId).Return(domain.StoreStock{StoreID: "JKT01", ProductID: productId, StockQty: quantity}, nil)
+	}
+
+	t.Run("partial", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		transferService, repositories := setupStockTransferService(t, publisher)
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(dispatched(), nil)
+		expectStock(repositories, "P002", 15)
+		repositories.transfers.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(savedTransfer)
+
+		response, err := transferService.Receive(context.Background(), cashier, web.StockTransferReceiveRequest{
+			TransferID: "T1",
+			Lines:      []web.StockTransferReceiptLine{{ProductID: "P002", Quantity: 15}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransferPartiallyReceived, response.Status)
+		assert.Equal(t, 15, response.Lines[1].ReceivedQty)
+		assert.Zero(t, response.Lines[1].DiscrepancyQty)
+		assert.Equal(t, []string{event.StoreStockChanged, event.TransferUpdated}, publisher.types)
+	})
+
+	t.Run("completed with discrepancy", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		transferService, repositories := setupStockTransferService(t, publisher)
+		partial := dispatched()
+		partial.Status = domain.TransferPartiallyReceived
+		partial.Lines = []domain.StockTransferLine{
+			{TransferID: "T1", ProductID: "P001", Quantity: 2},
+			{TransferID: "T1", ProductID: "P002", Quantity: 20, ReceivedQty: 15},
+		}
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(partial, nil)
+		expectStock(repositories, "P001", 2)
+		repositories.transfers.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(savedTransfer)
+
+		response, err := transferService.Receive(context.Background(), cashier, web.StockTransferReceiveRequest{
+			TransferID: "T1",
+			Lines: []web.StockTransferReceiptLine{
+				{ProductID: "P001", Quantity: 2},
+				{ProductID: "P002", Quantity: 0, Reason: "damaged"},
+			},
+			Complete: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransferReceived, response.Status)
+		assert.NotNil(t, response.ReceivedAt)
+		assert.Zero(t, response.Lines[0].DiscrepancyQty)
+		assert.Equal(t, 5, response.Lines[1].DiscrepancyQty)
+		assert.Equal(t, "damaged", response.Lines[1].DiscrepancyReason)
+		assert.Equal(t, []string{event.StoreStockChanged, event.TransferReceived}, publisher.types)
+	})
+
+	t.Run("over receipt", func(t *testing.T) {
+		transferService, repositories := setupStockTransferService(t, &recordingPublisher{})
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(dispatched(), nil)
+
+		_, err := transferService.Receive(context.Background(), cashier, web.StockTransferReceiveRequest{
+			TransferID: "T1",
+			Lines:      []web.StockTransferReceiptLine{{ProductID: "P002", Quantity: 21}},
+		})
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+
+	t.Run("changed concurrently", func(t *testing.T) {
+		transferService, repositories := setupStockTransferService(t, &recordingPublisher{})
+		repositories.transfers.EXPECT().FindById(gomock.Any(), "T1").Return(dispatched(), nil)
+		expectStock(repositories, "P001", 2)
+		repositories.transfers.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.StockTransfer{}, false, nil)
+
+		_, err := transferService.Receive(context.Background(), cashier, web.StockTransferReceiveRequest{
+			TransferID: "T1",
+			Lines:      []web.StockTransferReceiptLine{{ProductID: "P001", Quantity: 2}},
+		})
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+}
+
+func TestFindAllStockTransfers(t *testing.T) {
+	transferService, repositories := setupStockTransferService(t, &recordingPublisher{})
+	ctx := context.Background()
+
+	repositories.transfers.EXPECT().FindAll(ctx, "JKT01", domain.OpenTransferStatuses).Return([]domain.StockTransfer{{TransferID: "T1"}}, nil)
+	open, err := transferService.FindAll(ctx, "JKT01", "open")
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	repositories.transfers.EXPECT().FindAll(ctx, "", []string{domain.TransferReceived}).Return(nil, nil)
+	received, err := transferService.FindAll(ctx, "", domain.TransferReceived)
+	require.NoError(t, err)
+	assert.Empty(t, received)
+
+	_, err = transferService.FindAll(ctx, "", "lost")
+	assert.ErrorAs(t, err, &exception.BadRequestError{})
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+// StoreService manages the stores and their stock. Managing stores requires auth.CrossStore,
+// reading a store or its stock requires the permission of that store.
+type StoreService interface {
+	Create(ctx context.Context, principal auth.Principal, request web.StoreCreateRequest) (web.StoreResponse, error)
+	Update(ctx context.Context, principal auth.Principal, request web.StoreUpdateRequest) (web.StoreResponse, error)
+	Delete(ctx context.Context, principal auth.Principal, storeId string) error
+	FindById(ctx context.Context, principal auth.Principal, storeId string) (web.StoreResponse, error)
+	FindAll(ctx context.Context, principal auth.Principal) ([]web.StoreResponse, error)
+	FindStock(ctx context.Context, principal auth.Principal, storeId string) ([]web.StoreStockResponse, error)
+	UpdateStock(ctx context.Context, principal auth.Principal, request web.StoreStockUpdateRequest) (web.StoreStockResponse, error)
+
+	// The reads below run in a store context already granted to the caller, see middleware.Store
+	FindProducts(ctx context.Context, storeId string, options map[string]string) ([]web.ProductResponse, error)
+	FindProduct(ctx context.Context, storeId string, productId string) (web.ProductResponse, error)
+	FindEmployees(ctx context.Context, storeId string) ([]web.EmployeeResponse, error)
+	FindEmployee(ctx context.Context, storeId string, employeeId string) (web.EmployeeResponse, error)
+}
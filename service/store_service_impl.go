@@ -0,0 +1,430 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type StoreServiceImpl struct {
+	StoreRepository      repository.StoreRepository
+	StoreStockRepository repository.StoreStockRepository
+	ProductRepository    repository.ProductRepository
+	EmployeeRepository   repository.EmployeeRepository
+	Transactor           repository.Transactor
+	Events               event.Publisher
+	Validate             *validator.Validate
+}
+
+func NewStoreService(storeRepository repository.StoreRepository, storeStockRepository repository.StoreStockRepository, productRepository repository.ProductRepository, employeeRepository repository.EmployeeRepository, transactor repository.Transactor, events event.Publisher, validate *validator.Validate) StoreService {
+	return &StoreServiceImpl{
+		StoreRepository:      storeRepository,
+		StoreStockRepository: storeStockRepository,
+		ProductRepository:    productRepository,
+		EmployeeRepository:   employeeRepository,
+		Transactor:           transactor,
+		Events:               events,
+		Validate:             validate,
+	}
+}
+
+// Create Store
+func (service *StoreServiceImpl) Create(ctx context.Context, principal auth.Principal, request web.StoreCreateRequest) (web.StoreResponse, error) {
+	if err := requireCrossStore(principal); err != nil {
+		return web.StoreResponse{}, err
+	}
+	if err := service.Validate.Struct(request); err != nil {
+		return web.StoreResponse{}, err
+	}
+
+	_, err := service.StoreRepository.FindById(ctx, request.StoreID)
+	if err == nil {
+		return web.StoreResponse{}, exception.NewBadRequestError(fmt.Sprintf("store %s already exists", request.StoreID))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.StoreResponse{}, err
+	}
+
+	store := domain.Store{
+		StoreID:       request.StoreID,
+		Name:          request.Name,
+		Address:       request.Address,
+		Phone:         request.Phone,
+		PriceMode:     request.PriceMode,
+		TaxRounding:   request.TaxRounding,
+		ReceiptHeader: request.ReceiptHeader,
+		ReceiptFooter: request.ReceiptFooter,
+		ReceiptQR:     request.ReceiptQR,
+	}
+	settings := tax.Of(store)
+	store.PriceMode, store.TaxRounding = settings.Mode, settings.Rounding
+
+	var response web.StoreResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		savedStore, err := service.StoreRepository.Save(ctx, store)
+		if err != nil {
+			return err
+		}
+		response = helper.ToStoreResponse(savedStore)
+		return service.Events.Publish(ctx, event.StoreCreated, response)
+	})
+	if err != nil {
+		return web.StoreResponse{}, err
+	}
+	return response, nil
+}
+
+// Update Store
+func (service *StoreServiceImpl) Update(ctx context.Context, principal auth.Principal, request web.StoreUpdateRequest) (web.StoreResponse, error) {
+	if err := requireCrossStore(principal); err != nil {
+		return web.StoreResponse{}, err
+	}
+	if err := service.Validate.Struct(request); err != nil {
+		return web.StoreResponse{}, err
+	}
+
+	store, err := service.findStore(ctx, request.StoreID)
+	if err != nil {
+		return web.StoreResponse{}, err
+	}
+	store.Name = request.Name
+	store.Address = request.Address
+	store.Phone = request.Phone
+	store.ReceiptHeader = request.ReceiptHeader
+	store.ReceiptFooter = request.ReceiptFooter
+	store.ReceiptQR = request.ReceiptQR
+	if request.PriceMode != "" {
+		store.PriceMode = request.PriceMode
+	}
+	if request.TaxRounding != "" {
+		store.TaxRounding = request.TaxRounding
+	}
+
+	var response web.StoreResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updatedStore, err := service.StoreRepository.Update(ctx, store)
+		if err != nil {
+			return err
+		}
+		response = helper.ToStoreResponse(updatedStore)
+		return service.Events.Publish(ctx, event.StoreUpdated, response)
+	})
+	if err != nil {
+		return web.StoreResponse{}, err
+	}
+	return response, nil
+}
+
+// Delete Store with its stock and its employee assignments
+func (service *StoreServiceImpl) Delete(ctx context.Context, principal auth.Principal, storeId string) error {
+	if err := requireCrossStore(principal); err != nil {
+		return err
+	}
+	store, err := service.findStore(ctx, storeId)
+	if err != nil {
+		return err
+	}
+	return service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.StoreRepository.Delete(ctx, store); err != nil {
+			return err
+		}
+		return service.Events.Publish(ctx, event.StoreDeleted, helper.ToStoreResponse(store))
+	})
+}
+
+// Find Store By ID
+func (service *StoreServiceImpl) FindById(ctx context.Context, principal auth.Principal, storeId string) (web.StoreResponse, error) {
+	if err := requireStore(principal, storeId); err != nil {
+		return web.StoreResponse{}, err
+	}
+	store, err := service.findStore(ctx, storeId)
+	if err != nil {
+		return web.StoreResponse{}, err
+	}
+	return helper.ToStoreResponse(store), nil
+}
+
+// Find All Stores the principal may work on
+func (service *StoreServiceImpl) FindAll(ctx context.Context, principal auth.Principal) ([]web.StoreResponse, error) {
+	var stores []domain.Store
+	var err error
+	if principal.Can(auth.CrossStore) {
+		stores, err = service.StoreRepository.FindAll(ctx)
+	} else if storeIds := principal.Stores(); len(storeIds) > 0 {
+		stores, err = service.StoreRepository.FindByIds(ctx, storeIds)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToStoreResponses(stores), nil
+}
+
+// FindStock lists the stock of every product a store holds
+func (service *StoreServiceImpl) FindStock(ctx context.Context, principal auth.Principal, storeId string) ([]web.StoreStockResponse, error) {
+	if err := requireStore(principal, storeId); err != nil {
+		return nil, err
+	}
+	if _, err := service.findStore(ctx, storeId); err != nil {
+		return nil, err
+	}
+	stocks, err := service.StoreStockRepository.FindByStore(ctx, storeId)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := service.StoreStockRepository.FindVariants(ctx, storeId, "")
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToStoreStockResponses(stocks, variants), nil
+}
+
+// UpdateStock sets the stock of a product in a store and its price override. The stock of a
+// product with variants is set per variant, the product then holds the total of its variants.
+func (service *StoreServiceImpl) UpdateStock(ctx context.Context, principal auth.Principal, request web.StoreStockUpdateRequest) (web.StoreStockResponse, error) {
+	if err := requireStore(principal, request.StoreID); err != nil {
+		return web.StoreStockResponse{}, err
+	}
+	if err := service.Validate.Struct(request); err != nil {
+		return web.StoreStockResponse{}, err
+	}
+	if request.PriceOverride != nil && request.PriceOverride.IsNegative() {
+		return web.StoreStockResponse{}, exception.NewBadRequestError("price_override must not be negative")
+	}
+	if _, err := service.findStore(ctx, request.StoreID); err != nil {
+		return web.StoreStockResponse{}, err
+	}
+	product, err := service.ProductRepository.FindById(ctx, request.ProductID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.StoreStockResponse{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return web.StoreStockResponse{}, err
+	}
+	if err := checkVariant(product, request.VariantID); err != nil {
+		return web.StoreStockResponse{}, err
+	}
+
+	stock := domain.StoreStock{
+		StoreID:       request.StoreID,
+		ProductID:     request.ProductID,
+		StockQty:      request.StockQty,
+		PriceOverride: request.PriceOverride,
+	}
+
+	var response web.StoreStockResponse
+	err = service.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var variants []domain.StoreVariantStock
+		if request.VariantID != "" {
+			variant := domain.StoreVariantStock{StoreID: request.StoreID, VariantID: request.VariantID, ProductID: request.ProductID, StockQty: request.StockQty}
+			if _, err := service.StoreStockRepository.SaveVariant(ctx, variant); err != nil {
+				return err
+			}
+			variants, err = service.StoreStockRepository.FindVariants(ctx, request.StoreID, request.ProductID)
+			if err != nil {
+				return err
+			}
+			stock.StockQty = 0
+			for _, variant := range variants {
+				stock.StockQty += variant.StockQty
+			}
+		}
+		savedStock, err := service.StoreStockRepository.Save(ctx, stock)
+		if err != nil {
+			return err
+		}
+		response = helper.ToStoreStockResponse(savedStock, variants)
+		return service.Events.Publish(ctx, event.StoreStockChanged, response)
+	})
+	if err != nil {
+		return web.StoreStockResponse{}, err
+	}
+	return response, nil
+}
+
+// FindProducts lists the catalogue with the stock and the prices of a store, filtered by the
+// option values of the variants like ProductService.FindByOptions
+func (service *StoreServiceImpl) FindProducts(ctx context.Context, storeId string, options map[string]string) ([]web.ProductResponse, error) {
+	if _, err := service.findStore(ctx, storeId); err != nil {
+		return nil, err
+	}
+	var products []domain.Product
+	var err error
+	if len(options) > 0 {
+		products, err = service.ProductRepository.FindByOptions(ctx, options)
+	} else {
+		products, err = service.ProductRepository.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stocks, err := service.StoreStockRepository.FindByStore(ctx, storeId)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := service.StoreStockRepository.FindVariants(ctx, storeId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	stockByProduct := make(map[string]domain.StoreStock, len(stocks))
+	for _, stock := range stocks {
+		stockByProduct[stock.ProductID] = stock
+	}
+	variantsByProduct := map[string][]domain.StoreVariantStock{}
+	for _, variant := range variants {
+		variantsByProduct[variant.ProductID] = append(variantsByProduct[variant.ProductID], variant)
+	}
+	var responses []web.ProductResponse
+	for _, product := range products {
+		responses = append(responses, helper.ToStoreProductResponse(storeId, product, stockByProduct[product.ProductID], variantsByProduct[product.ProductID]))
+	}
+	return responses, nil
+}
+
+// FindProduct returns a product with the stock and the price of a store
+func (service *StoreServiceImpl) FindProduct(ctx context.Context, storeId string, productId string) (web.ProductResponse, error) {
+	if _, err := service.findStore(ctx, storeId); err != nil {
+		return web.ProductResponse{}, err
+	}
+	product, err := service.ProductRepository.FindById(ctx, productId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductResponse{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return web.ProductResponse{}, err
+	}
+	stock, err := service.StoreStockRepository.FindById(ctx, storeId, productId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductResponse{}, err
+	}
+	var variants []domain.StoreVariantStock
+	if len(product.Variants) > 0 {
+		variants, err = service.StoreStockRepository.FindVariants(ctx, storeId, productId)
+		if err != nil {
+			return web.ProductResponse{}, err
+		}
+	}
+	return helper.ToStoreProductResponse(storeId, product, stock, variants), nil
+}
+
+// FindEmployees lists the employees assigned to a store
+func (service *StoreServiceImpl) FindEmployees(ctx context.Context, storeId string) ([]web.EmployeeResponse, error) {
+	if _, err := service.findStore(ctx, storeId); err != nil {
+		return nil, err
+	}
+	employees, err := service.EmployeeRepository.FindByStore(ctx, storeId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToEmployeeResponses(employees), nil
+}
+
+// FindEmployee returns an employee assigned to a store, the others are not found in it
+func (service *StoreServiceImpl) FindEmployee(ctx context.Context, storeId string, employeeId string) (web.EmployeeResponse, error) {
+	employee, err := service.EmployeeRepository.FindById(ctx, employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.EmployeeResponse{}, exception.NewNotFoundError("Employee not found")
+	} else if err != nil {
+		return web.EmployeeResponse{}, err
+	}
+	for _, assignment := range employee.Stores {
+		if assignment.StoreID == storeId {
+			return helper.ToEmployeeResponse(employee), nil
+		}
+	}
+	return web.EmployeeResponse{}, exception.NewNotFoundError("Employee not found")
+}
+
+func (service *StoreServiceImpl) findStore(ctx context.Context, storeId string) (domain.Store, error) {
+	store, err := service.StoreRepository.FindById(ctx, storeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Store{}, exception.NewNotFoundError("Store not found")
+	}
+	return store, err
+}
+
+func requireCrossStore(principal auth.Principal) error {
+	if !principal.Can(auth.CrossStore) {
+		return exception.NewForbiddenError(fmt.Sprintf("%s is not allowed to manage stores", principal.Name))
+	}
+	return nil
+}
+
+func requireStore(principal auth.Principal, storeId string) error {
+	if !principal.Can(auth.StorePermission(storeId)) {
+		return exception.NewForbiddenError(fmt.Sprintf("%s is not allowed to access store %s", principal.Name, storeId))
+	}
+	return nil
+}
+
+// requireEmployee returns the employee the principal acts as, see auth.Principal.EmployeeContext
+func requireEmployee(principal auth.Principal, requested string) (string, error) {
+	employeeId, err := principal.EmployeeContext(requested)
+	if errors.Is(err, auth.ErrEmployeeForbidden) {
+		return "", exception.NewForbiddenError(fmt.Sprintf("%s is not bound to employee %s", principal.Name, requested))
+	} else if err != nil {
+		return "", exception.NewBadRequestError(fmt.Sprintf("%s is bound to no single employee, send the employee_id", principal.Name))
+	}
+	return employeeId, nil
+}
+
+// checkVariant checks that variantId is a variant of product, and that a variant is given for a
+// product with variants: its stock is the total of its variants
+func checkVariant(product domain.Product, variantId string) error {
+	if variantId == "" {
+		if len(product.Variants) > 0 {
+			return exception.NewBadRequestError(fmt.Sprintf("product %s has variants, a variant_id is required", product.ProductID))
+		}
+		return nil
+	}
+	for _, variant := range product.Variants {
+		if variant.VariantID == variantId {
+			return nil
+		}
+	}
+	return exception.NewBadRequestError(fmt.Sprintf("unknown variant %s of product %s", variantId, product.ProductID))
+}
+
+// stockItem names a product or a variant of it in the stock errors
+func stockItem(productId string, variantId string) string {
+	if variantId == "" {
+		return "product " + productId
+	}
+	return fmt.Sprintf("variant %s of product %s", variantId, productId)
+}
+
+// adjustStoreStock adds delta to the stock of a product, or of a variant of it, in a store and
+// publishes the new stock. ok is false when the stock would become negative.
+func adjustStoreStock(ctx context.Context, stocks repository.StoreStockRepository, events event.Publisher, storeId string, productId string, variantId string, delta int) (bool, error) {
+	var ok bool
+	var err error
+	if variantId != "" {
+		ok, err = stocks.AdjustVariant(ctx, storeId, productId, variantId, delta)
+	} else {
+		ok, err = stocks.Adjust(ctx, storeId, productId, delta)
+	}
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	stock, err := stocks.FindById(ctx, storeId, productId)
+	if err != nil {
+		return false, err
+	}
+	var variants []domain.StoreVariantStock
+	if variantId != "" {
+		variants, err = stocks.FindVariants(ctx, storeId, productId)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, events.Publish(ctx, event.StoreStockChanged, helper.ToStoreStockResponse(stock, variants))
+}
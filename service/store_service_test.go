@@ -0,0 +1,222 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type storeMocks struct {
+	stores    *mocks.MockStoreRepository
+	stocks    *mocks.MockStoreStockRepository
+	products  *mocks.MockProductRepository
+	employees *mocks.MockEmployeeRepository
+}
+
+func setupStoreService(t *testing.T, publisher *recordingPublisher) (service.StoreService, storeMocks) {
+	ctrl := gomock.NewController(t)
+	repositories := storeMocks{
+		stores:    mocks.NewMockStoreRepository(ctrl),
+		stocks:    mocks.NewMockStoreStockRepository(ctrl),
+		products:  mocks.NewMockProductRepository(ctrl),
+		employees: mocks.NewMockEmployeeRepository(ctrl),
+	}
+	storeService := service.NewStoreService(repositories.stores, repositories.stocks, repositories.products, repositories.employees, fakeTransactor{}, publisher, validator.New())
+	return storeService, repositories
+}
+
+var (
+	admin   = auth.Principal{Name: "admin", Permissions: []string{auth.Wildcard}}
+	cashier = auth.Principal{Name: "kasir", Permissions: []string{auth.StorePermission("JKT01")}}
+)
+
+func TestCreateStore(t *testing.T) {
+	notFound := fmt.Errorf("store is not found: %w", gorm.ErrRecordNotFound)
+	request := web.StoreCreateRequest{StoreID: "JKT01", Name: "Jakarta Pusat"}
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		request   web.StoreCreateRequest
+		mock      func(repositories storeMocks)
+		expectErr interface{}
+	}{
+		{
+			name:      "success",
+			principal: admin,
+			request:   request,
+			mock: func(repositories storeMocks) {
+				repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{}, notFound)
+				repositories.stores.EXPECT().Save(gomock.Any(), domain.Store{StoreID: "JKT01", Name: "Jakarta Pusat", PriceMode: tax.Exclusive, TaxRounding: tax.RoundLine}).Return(domain.Store{StoreID: "JKT01", Name: "Jakarta Pusat"}, nil)
+			},
+		},
+		{
+			name:      "store bound key",
+			principal: cashier,
+			request:   request,
+			mock:      func(repositories storeMocks) {},
+			expectErr: &exception.ForbiddenError{},
+		},
+		{
+			name:      "duplicate",
+			principal: admin,
+			request:   request,
+			mock: func(repositories storeMocks) {
+				repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+			},
+			expectErr: &exception.BadRequestError{},
+		},
+		{
+			name:      "invalid code",
+			principal: admin,
+			request:   web.StoreCreateRequest{StoreID: "JKT:01", Name: "Jakarta Pusat"},
+			mock:      func(repositories storeMocks) {},
+			expectErr: &validator.ValidationErrors{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			storeService, repositories := setupStoreService(t, publisher)
+			tt.mock(repositories)
+
+			response, err := storeService.Create(context.Background(), tt.principal, tt.request)
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				assert.Empty(t, publisher.types)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "JKT01", response.StoreID)
+			assert.Equal(t, []string{event.StoreCreated}, publisher.types)
+		})
+	}
+}
+
+func TestFindAllStores(t *testing.T) {
+	storeService, repositories := setupStoreService(t, &recordingPublisher{})
+	ctx := context.Background()
+
+	repositories.stores.EXPECT().FindAll(ctx).Return([]domain.Store{{StoreID: "BDG01"}, {StoreID: "JKT01"}}, nil)
+	all, err := storeService.FindAll(ctx, admin)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	repositories.stores.EXPECT().FindByIds(ctx, []string{"JKT01"}).Return([]domain.Store{{StoreID: "JKT01"}}, nil)
+	own, err := storeService.FindAll(ctx, cashier)
+	require.NoError(t, err)
+	assert.Equal(t, []web.StoreResponse{{StoreID: "JKT01"}}, own)
+
+	none, err := storeService.FindAll(ctx, auth.Principal{Name: "dashboard", Permissions: []string{"stream:products"}})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestUpdateStoreStock(t *testing.T) {
+	override := money.IDR(30000)
+	request := web.StoreStockUpdateRequest{StoreID: "JKT01", ProductID: "P002", StockQty: 25, PriceOverride: &override}
+
+	t.Run("success", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		storeService, repositories := setupStoreService(t, publisher)
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+		repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(domain.Product{ProductID: "P002"}, nil)
+		repositories.stocks.EXPECT().Save(gomock.Any(), domain.StoreStock{StoreID: "JKT01", ProductID: "P002", StockQty: 25, PriceOverride: &override}).
+			DoAndReturn(func(ctx context.Context, stock domain.StoreStock) (domain.StoreStock, error) {
+				return stock, nil
+			})
+
+		response, err := storeService.UpdateStock(context.Background(), cashier, request)
+		require.NoError(t, err)
+		assert.Equal(t, 25, response.StockQty)
+		assert.Equal(t, &override, response.PriceOverrideAmount)
+		assert.Equal(t, 30000.0, *response.PriceOverride)
+		assert.Equal(t, []string{event.StoreStockChanged}, publisher.types)
+	})
+
+	t.Run("variant", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		storeService, repositories := setupStoreService(t, publisher)
+		kaos := domain.Product{ProductID: "P009", Variants: []domain.ProductVariant{{VariantID: "V1"}, {VariantID: "V2"}}}
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil).Times(3)
+		repositories.products.EXPECT().FindById(gomock.Any(), "P009").Return(kaos, nil).Times(3)
+		repositories.stocks.EXPECT().SaveVariant(gomock.Any(), domain.StoreVariantStock{StoreID: "JKT01", VariantID: "V2", ProductID: "P009", StockQty: 4}).
+			DoAndReturn(func(ctx context.Context, stock domain.StoreVariantStock) (domain.StoreVariantStock, error) {
+				return stock, nil
+			})
+		repositories.stocks.EXPECT().FindVariants(gomock.Any(), "JKT01", "P009").Return([]domain.StoreVariantStock{
+			{StoreID: "JKT01", VariantID: "V1", ProductID: "P009", StockQty: 3},
+			{StoreID: "JKT01", VariantID: "V2", ProductID: "P009", StockQty: 4},
+		}, nil)
+		// the product holds the total of its variants
+		repositories.stocks.EXPECT().Save(gomock.Any(), domain.StoreStock{StoreID: "JKT01", ProductID: "P009", StockQty: 7}).
+			DoAndReturn(func(ctx context.Context, stock domain.StoreStock) (domain.StoreStock, error) {
+				return stock, nil
+			})
+
+		response, err := storeService.UpdateStock(context.Background(), cashier, web.StoreStockUpdateRequest{StoreID: "JKT01", ProductID: "P009", VariantID: "V2", StockQty: 4})
+		require.NoError(t, err)
+		assert.Equal(t, 7, response.StockQty)
+		assert.Equal(t, []web.StoreVariantStockResponse{{VariantID: "V1", StockQty: 3}, {VariantID: "V2", StockQty: 4}}, response.Variants)
+		assert.Equal(t, []string{event.StoreStockChanged}, publisher.types)
+
+		_, err = storeService.UpdateStock(context.Background(), cashier, web.StoreStockUpdateRequest{StoreID: "JKT01", ProductID: "P009", StockQty: 4})
+		assert.ErrorAs(t, err, &exception.BadRequestError{}, "the stock of a product with variants is set per variant")
+		_, err = storeService.UpdateStock(context.Background(), cashier, web.StoreStockUpdateRequest{StoreID: "JKT01", ProductID: "P009", VariantID: "V9", StockQty: 4})
+		assert.ErrorAs(t, err, &exception.BadRequestError{})
+	})
+
+	t.Run("other store", func(t *testing.T) {
+		storeService, _ := setupStoreService(t, &recordingPublisher{})
+		other := request
+		other.StoreID = "BDG01"
+		_, err := storeService.UpdateStock(context.Background(), cashier, other)
+		assert.ErrorAs(t, err, &exception.ForbiddenError{})
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		storeService, repositories := setupStoreService(t, &recordingPublisher{})
+		repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+		repositories.products.EXPECT().FindById(gomock.Any(), "P002").Return(domain.Product{}, fmt.Errorf("product is not found: %w", gorm.ErrRecordNotFound))
+		_, err := storeService.UpdateStock(context.Background(), cashier, request)
+		assert.ErrorAs(t, err, &exception.NotFoundError{})
+	})
+}
+
+func TestFindStoreProducts(t *testing.T) {
+	storeService, repositories := setupStoreService(t, &recordingPublisher{})
+	override := money.IDR(30000)
+
+	repositories.stores.EXPECT().FindById(gomock.Any(), "JKT01").Return(domain.Store{StoreID: "JKT01"}, nil)
+	repositories.products.EXPECT().FindAll(gomock.Any()).Return([]domain.Product{
+		{ProductID: "P001", Name: "Laptop Gaming", Price: money.IDR(15000000), StockQty: 10},
+		{ProductID: "P002", Name: "Kopi Bubuk", Price: money.IDR(35000), StockQty: 200},
+	}, nil)
+	repositories.stocks.EXPECT().FindByStore(gomock.Any(), "JKT01").Return([]domain.StoreStock{
+		{StoreID: "JKT01", ProductID: "P002", StockQty: 25, PriceOverride: &override},
+	}, nil)
+	repositories.stocks.EXPECT().FindVariants(gomock.Any(), "JKT01", "").Return(nil, nil)
+
+	products, err := storeService.FindProducts(context.Background(), "JKT01", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []web.ProductResponse{
+		{ProductID: "P001", Name: "Laptop Gaming", Price: 15000000, PriceAmount: money.IDR(15000000), Currency: "IDR", StockQty: 0, StoreID: "JKT01"},
+		{ProductID: "P002", Name: "Kopi Bubuk", Price: 30000, PriceAmount: money.IDR(30000), Currency: "IDR", StockQty: 25, StoreID: "JKT01"},
+	}, products)
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/stream"
+)
+
+type StreamService interface {
+	Subscribe(ctx context.Context, principal auth.Principal, topics string) (*stream.Subscription, error)
+	Replay(ctx context.Context, topics []string, sequence uint64, limit int) ([]event.Event, error)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/stream"
+)
+
+type StreamServiceImpl struct {
+	Broker           *stream.Broker
+	OutboxRepository repository.OutboxRepository
+}
+
+func NewStreamService(broker *stream.Broker, outboxRepository repository.OutboxRepository) StreamService {
+	return &StreamServiceImpl{
+		Broker:           broker,
+		OutboxRepository: outboxRepository,
+	}
+}
+
+// Subscribe to a comma separated list of topics, the principal needs the stream permission of each one
+func (service *StreamServiceImpl) Subscribe(ctx context.Context, principal auth.Principal, topics string) (*stream.Subscription, error) {
+	parsed, err := stream.ParseTopics(topics)
+	if err != nil {
+		return nil, exception.NewBadRequestError(err.Error())
+	}
+	for _, topic := range parsed {
+		if !principal.Can(stream.Permission(topic)) {
+			return nil, exception.NewForbiddenError(fmt.Sprintf("%s is not allowed to subscribe to %s", principal.Name, topic))
+		}
+	}
+	return service.Broker.Subscribe(parsed), nil
+}
+
+// Replay - Get the events of topics relayed after the one of sequence, to resume an interrupted stream
+func (service *StreamServiceImpl) Replay(ctx context.Context, topics []string, sequence uint64, limit int) ([]event.Event, error) {
+	outboxEvents, err := service.OutboxRepository.FindPublishedAfter(ctx, sequence, stream.EventTypes(topics), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]event.Event, 0, len(outboxEvents))
+	for _, outboxEvent := range outboxEvents {
+		events = append(events, event.FromOutbox(outboxEvent))
+	}
+	return events, nil
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/aronipurwanto/go-restful-api/stream"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	broker := stream.NewBroker(10)
+	streamService := service.NewStreamService(broker, mocks.NewMockOutboxRepository(ctrl))
+	dashboard := auth.Principal{Name: "dashboard", Permissions: []string{"stream:products"}}
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		topics    string
+		expect    []string
+		expectErr error
+	}{
+		{name: "allowed topic", principal: dashboard, topics: "products", expect: []string{stream.TopicProducts}},
+		{name: "admin", principal: auth.Principal{Name: "admin", Permissions: []string{auth.Wildcard}}, topics: "products,customers", expect: []string{stream.TopicProducts, stream.TopicCustomers}},
+		{name: "forbidden topic", principal: dashboard, topics: "products,customers", expectErr: exception.ForbiddenError{}},
+		{name: "unknown topic", principal: dashboard, topics: "weather", expectErr: exception.BadRequestError{}},
+		{name: "no topic", principal: dashboard, topics: "", expectErr: exception.BadRequestError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription, err := streamService.Subscribe(context.Background(), tt.principal, tt.topics)
+			if tt.expectErr != nil {
+				assert.IsType(t, tt.expectErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, subscription.Topics())
+			subscription.Close()
+		})
+	}
+	assert.Equal(t, 0, broker.Subscribers())
+}
+
+func TestReplayStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOutboxRepository(ctrl)
+	streamService := service.NewStreamService(stream.NewBroker(10), mockRepo)
+	occurredAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	// the event was written before one published earlier
+	sequence := uint64(42)
+
+	mockRepo.EXPECT().
+		FindPublishedAfter(gomock.Any(), uint64(41), []string{event.CustomerCreated, event.CustomerUpdated, event.CustomerDeleted}, 100).
+		Return([]domain.OutboxEvent{{ID: 40, Type: event.CustomerCreated, Payload: `{"customer_id":"C1"}`, OccurredAt: occurredAt, Sequence: &sequence}}, nil)
+	events, err := streamService.Replay(context.Background(), []string{stream.TopicCustomers}, 41, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []event.Event{{ID: "40", Sequence: 42, Type: event.CustomerCreated, OccurredAt: occurredAt, Data: []byte(`{"customer_id":"C1"}`)}}, events)
+
+	mockRepo.EXPECT().FindPublishedAfter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+	_, err = streamService.Replay(context.Background(), []string{stream.TopicCustomers}, 0, 100)
+	assert.Error(t, err)
+}
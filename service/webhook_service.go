@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, request web.WebhookCreateRequest) (web.WebhookResponse, error)
+	Update(ctx context.Context, request web.WebhookUpdateRequest) (web.WebhookResponse, error)
+	Delete(ctx context.Context, webhookId string) error
+	FindById(ctx context.Context, webhookId string) (web.WebhookResponse, error)
+	FindAll(ctx context.Context) ([]web.WebhookResponse, error)
+	FindDeliveries(ctx context.Context, webhookId string) ([]web.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, webhookId string, deliveryId string) (web.WebhookDeliveryResponse, error)
+}
+
+// DeliveryNotifier wakes the webhook sender up when a delivery is queued
+type DeliveryNotifier interface {
+	Notify()
+}
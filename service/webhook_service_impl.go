@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// deliveryLogSize is the number of deliveries returned by FindDeliveries
+const deliveryLogSize = 100
+
+type WebhookServiceImpl struct {
+	WebhookRepository         repository.WebhookRepository
+	WebhookDeliveryRepository repository.WebhookDeliveryRepository
+	Sender                    DeliveryNotifier
+	Validate                  *validator.Validate
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository, webhookDeliveryRepository repository.WebhookDeliveryRepository, sender DeliveryNotifier, validate *validator.Validate) WebhookService {
+	return &WebhookServiceImpl{
+		WebhookRepository:         webhookRepository,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+		Sender:                    sender,
+		Validate:                  validate,
+	}
+}
+
+// Create Webhook, a secret is generated when none is given
+func (service *WebhookServiceImpl) Create(ctx context.Context, request web.WebhookCreateRequest) (web.WebhookResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.WebhookResponse{}, err
+	}
+	if err := validateEventTypes(request.EventTypes); err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		secret = newWebhookSecret()
+	}
+
+	webhook, err := service.WebhookRepository.Save(ctx, domain.Webhook{
+		URL:        request.URL,
+		Secret:     secret,
+		EventTypes: request.EventTypes,
+		Active:     true,
+	})
+	if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	response := helper.ToWebhookResponse(webhook)
+	response.Secret = secret
+	return response, nil
+}
+
+// Update Webhook, the secret is only rotated when a new one is given
+func (service *WebhookServiceImpl) Update(ctx context.Context, request web.WebhookUpdateRequest) (web.WebhookResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.WebhookResponse{}, err
+	}
+	if err := validateEventTypes(request.EventTypes); err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	webhook, err := service.WebhookRepository.FindById(ctx, request.WebhookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.WebhookResponse{}, exception.NewNotFoundError("Webhook not found")
+	} else if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	webhook.URL = request.URL
+	webhook.EventTypes = request.EventTypes
+	webhook.Active = request.Active
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+
+	updatedWebhook, err := service.WebhookRepository.Update(ctx, webhook)
+	if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	response := helper.ToWebhookResponse(updatedWebhook)
+	response.Secret = request.Secret
+	return response, nil
+}
+
+// Delete Webhook, its pending deliveries are marked failed by the sender
+func (service *WebhookServiceImpl) Delete(ctx context.Context, webhookId string) error {
+	webhook, err := service.WebhookRepository.FindById(ctx, webhookId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.NewNotFoundError("Webhook not found")
+	} else if err != nil {
+		return err
+	}
+	return service.WebhookRepository.Delete(ctx, webhook)
+}
+
+// Find Webhook By ID
+func (service *WebhookServiceImpl) FindById(ctx context.Context, webhookId string) (web.WebhookResponse, error) {
+	webhook, err := service.WebhookRepository.FindById(ctx, webhookId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.WebhookResponse{}, exception.NewNotFoundError("Webhook not found")
+	} else if err != nil {
+		return web.WebhookResponse{}, err
+	}
+	return helper.ToWebhookResponse(webhook), nil
+}
+
+// Find All Webhooks
+func (service *WebhookServiceImpl) FindAll(ctx context.Context) ([]web.WebhookResponse, error) {
+	webhooks, err := service.WebhookRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToWebhookResponses(webhooks), nil
+}
+
+// FindDeliveries returns the delivery log of a webhook, newest first
+func (service *WebhookServiceImpl) FindDeliveries(ctx context.Context, webhookId string) ([]web.WebhookDeliveryResponse, error) {
+	if _, err := service.FindById(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := service.WebhookDeliveryRepository.FindByWebhook(ctx, webhookId, deliveryLogSize)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToWebhookDeliveryResponses(deliveries), nil
+}
+
+// Redeliver queues a new delivery of the same event and wakes the sender up, the original stays
+// in the log untouched
+func (service *WebhookServiceImpl) Redeliver(ctx context.Context, webhookId string, deliveryId string) (web.WebhookDeliveryResponse, error) {
+	delivery, err := service.WebhookDeliveryRepository.FindById(ctx, deliveryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && delivery.WebhookID != webhookId) {
+		return web.WebhookDeliveryResponse{}, exception.NewNotFoundError("Delivery not found")
+	} else if err != nil {
+		return web.WebhookDeliveryResponse{}, err
+	}
+
+	redelivery, err := service.WebhookDeliveryRepository.Save(ctx, domain.WebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return web.WebhookDeliveryResponse{}, err
+	}
+	repository.AfterCommit(ctx, service.Sender.Notify)
+	return helper.ToWebhookDeliveryResponse(redelivery), nil
+}
+
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !event.IsKnown(eventType) {
+			return exception.NewBadRequestError(fmt.Sprintf("unknown event type %q", eventType))
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() string {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return "whsec_" + hex.EncodeToString(secret)
+}
@@ -0,0 +1,135 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/repository"
+)
+
+// Broker fans the relayed events out to the subscribers of this instance. Publishing never
+// blocks: a subscriber whose buffer is full is dropped and marked as lagged, it is expected to
+// reconnect and catch up from the outbox with its last event id.
+type Broker struct {
+	mu          sync.Mutex
+	buffer      int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+func NewBroker(buffer int) *Broker {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &Broker{buffer: buffer, subscribers: map[*Subscription]struct{}{}}
+}
+
+// Subscription receives the events of its topics until it is closed
+type Subscription struct {
+	broker *Broker
+	topics []string
+	events chan event.Event
+	lagged bool
+	closed bool
+}
+
+// Subscribe registers a subscriber to topics; on a closed broker the subscription is closed
+// right away
+func (broker *Broker) Subscribe(topics []string) *Subscription {
+	subscription := &Subscription{
+		broker: broker,
+		topics: topics,
+		events: make(chan event.Event, broker.buffer),
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if broker.closed {
+		subscription.close()
+		return subscription
+	}
+	broker.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Publish sends e to every subscriber of its topic
+func (broker *Broker) Publish(e event.Event) {
+	topic := TopicOf(e.Type)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for subscription := range broker.subscribers {
+		if !containsTopic(subscription.topics, topic) {
+			continue
+		}
+		select {
+		case subscription.events <- e:
+		default:
+			// Slow consumer, never let it hold up the relay or the other subscribers
+			subscription.lagged = true
+			delete(broker.subscribers, subscription)
+			subscription.close()
+		}
+	}
+}
+
+// Handle is the relay handler feeding the broker once the relay transaction commits
+func (broker *Broker) Handle(ctx context.Context, e event.Event) error {
+	repository.AfterCommit(ctx, func() {
+		broker.Publish(e)
+	})
+	return nil
+}
+
+// Subscribers returns the number of open subscriptions
+func (broker *Broker) Subscribers() int {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return len(broker.subscribers)
+}
+
+// Close ends every subscription and refuses new ones, streams end so that the server can shut down
+func (broker *Broker) Close() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.closed = true
+	for subscription := range broker.subscribers {
+		delete(broker.subscribers, subscription)
+		subscription.close()
+	}
+}
+
+// Events is closed when the subscription ends, see Lagged
+func (subscription *Subscription) Events() <-chan event.Event {
+	return subscription.events
+}
+
+// Lagged tells whether the subscription was dropped because its buffer overflowed
+func (subscription *Subscription) Lagged() bool {
+	subscription.broker.mu.Lock()
+	defer subscription.broker.mu.Unlock()
+	return subscription.lagged
+}
+
+// Topics returns the subscribed topics
+func (subscription *Subscription) Topics() []string {
+	return subscription.topics
+}
+
+// Close unsubscribes, it may be called more than once
+func (subscription *Subscription) Close() {
+	broker := subscription.broker
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	delete(broker.subscribers, subscription)
+	subscription.close()
+}
+
+// close must be called with the broker lock held
+func (subscription *Subscription) close() {
+	if !subscription.closed {
+		subscription.closed = true
+		close(subscription.events)
+	}
+}
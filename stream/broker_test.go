@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(subscription *Subscription) []string {
+	var ids []string
+	for {
+		select {
+		case e, ok := <-subscription.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, e.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestBrokerRoutesByTopic(t *testing.T) {
+	broker := NewBroker(10)
+	products := broker.Subscribe([]string{TopicProducts})
+	both := broker.Subscribe([]string{TopicProducts, TopicCustomers})
+
+	broker.Publish(event.Event{ID: "1", Type: event.ProductPriceChanged})
+	broker.Publish(event.Event{ID: "2", Type: event.CustomerCreated})
+	broker.Publish(event.Event{ID: "3", Type: event.EmployeeDeleted})
+
+	assert.Equal(t, []string{"1"}, receive(products))
+	assert.Equal(t, []string{"1", "2"}, receive(both))
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker(2)
+	slow := broker.Subscribe([]string{TopicProducts})
+	fast := broker.Subscribe([]string{TopicProducts})
+
+	for _, id := range []string{"1", "2"} {
+		broker.Publish(event.Event{ID: id, Type: event.ProductUpdated})
+	}
+	assert.Equal(t, []string{"1", "2"}, receive(fast))
+
+	broker.Publish(event.Event{ID: "3", Type: event.ProductUpdated})
+
+	assert.True(t, slow.Lagged())
+	assert.False(t, fast.Lagged())
+	assert.Equal(t, 1, broker.Subscribers())
+	// The buffered events are still readable before the channel reports the end
+	assert.Equal(t, []string{"1", "2"}, receive(slow))
+	_, open := <-slow.Events()
+	assert.False(t, open)
+	assert.Equal(t, []string{"3"}, receive(fast))
+}
+
+func TestBrokerClose(t *testing.T) {
+	broker := NewBroker(1)
+	subscription := broker.Subscribe([]string{TopicCategories})
+	subscription.Close()
+	subscription.Close()
+	assert.Equal(t, 0, broker.Subscribers())
+
+	open := broker.Subscribe([]string{TopicCategories})
+	broker.Close()
+	_, ok := <-open.Events()
+	assert.False(t, ok)
+	assert.False(t, open.Lagged())
+
+	late := broker.Subscribe([]string{TopicCategories})
+	_, ok = <-late.Events()
+	assert.False(t, ok)
+}
+
+func TestParseTopics(t *testing.T) {
+	topics, err := ParseTopics("products, customers,products")
+	require.NoError(t, err)
+	assert.Equal(t, []string{TopicProducts, TopicCustomers}, topics)
+
+	_, err = ParseTopics("products,weather")
+	assert.Error(t, err)
+	_, err = ParseTopics(" , ")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{event.CustomerCreated, event.CustomerUpdated, event.CustomerDeleted}, EventTypes([]string{TopicCustomers}))
+	assert.Equal(t, TopicProducts, TopicOf(event.ProductStockChanged))
+	assert.Equal(t, "stream:products", Permission(TopicProducts))
+}
@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+)
+
+// Topics a client can subscribe to, each one groups the events of a resource
+const (
+	TopicCategories   = "categories"
+	TopicCustomers    = "customers"
+	TopicEmployees    = "employees"
+	TopicProducts     = "products"
+	TopicStores       = "stores"
+	TopicTransfers    = "transfers"
+	TopicPromotions   = "promotions"
+	TopicOrders       = "orders"
+	TopicPayments     = "payments"
+	TopicReturns      = "returns"
+	TopicCashSessions = "cash_sessions"
+)
+
+// resources maps the resource prefix of the event types to their topic
+var resources = map[string]string{
+	"category":     TopicCategories,
+	"customer":     TopicCustomers,
+	"employee":     TopicEmployees,
+	"product":      TopicProducts,
+	"store":        TopicStores,
+	"transfer":     TopicTransfers,
+	"promotion":    TopicPromotions,
+	"order":        TopicOrders,
+	"payment":      TopicPayments,
+	"return":       TopicReturns,
+	"cash_session": TopicCashSessions,
+}
+
+// TopicOf returns the topic of an event type, e.g. products for product.price_changed
+func TopicOf(eventType string) string {
+	resource, _, _ := strings.Cut(eventType, ".")
+	return resources[resource]
+}
+
+// Permission is the permission a principal needs to subscribe to topic
+func Permission(topic string) string {
+	return "stream:" + topic
+}
+
+// ParseTopics splits a comma separated list of topics, duplicates are ignored
+func ParseTopics(raw string) ([]string, error) {
+	var topics []string
+	seen := map[string]bool{}
+	for _, topic := range strings.Split(raw, ",") {
+		topic = strings.TrimSpace(topic)
+		if topic == "" || seen[topic] {
+			continue
+		}
+		if !isTopic(topic) {
+			return nil, fmt.Errorf("unknown topic %q", topic)
+		}
+		seen[topic] = true
+		topics = append(topics, topic)
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("at least one topic is required")
+	}
+	return topics, nil
+}
+
+// EventTypes lists the event types published on topics
+func EventTypes(topics []string) []string {
+	var eventTypes []string
+	for _, eventType := range event.Types() {
+		if containsTopic(topics, TopicOf(eventType)) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+func isTopic(topic string) bool {
+	for _, known := range resources {
+		if known == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTopic(topics []string, topic string) bool {
+	for _, candidate := range topics {
+		if candidate == topic {
+			return true
+		}
+	}
+	return false
+}
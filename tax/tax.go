@@ -0,0 +1,205 @@
+// Package tax computes the taxes of a sale from the tax classes of its products. Prices may
+// include the tax or not, and the tax may be rounded on every line or once over the sale.
+package tax
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+)
+
+// Price modes
+const (
+	// Exclusive prices are before tax, the tax is added on top of them
+	Exclusive = "exclusive"
+	// Inclusive prices already contain the tax, it is taken out of them
+	Inclusive = "inclusive"
+)
+
+// Rounding rules
+const (
+	// RoundLine rounds the tax of every line, the total is the sum of the rounded lines
+	RoundLine = "line"
+	// RoundInvoice sums the exact tax of the lines and rounds once per rate
+	RoundInvoice = "invoice"
+)
+
+// Tax classes created by the migration
+const (
+	// ClassStandard is PPN at the standard rate
+	ClassStandard = "standard"
+	// ClassExempt is for the goods free of PPN, such as basic necessities
+	ClassExempt = "exempt"
+	// ClassLuxury adds PPnBM on top of PPN
+	ClassLuxury = "luxury"
+)
+
+// DefaultClasses are the tax classes every database starts with
+func DefaultClasses() []domain.TaxClass {
+	ppn := domain.TaxRate{Code: "PPN", Name: "PPN 11%", Percent: 11}
+	return []domain.TaxClass{
+		{TaxClassID: ClassStandard, Name: "PPN", Rates: []domain.TaxRate{ppn}},
+		{TaxClassID: ClassExempt, Name: "Bebas PPN", Rates: []domain.TaxRate{}},
+		{TaxClassID: ClassLuxury, Name: "PPN dan PPnBM", Rates: []domain.TaxRate{ppn, {Code: "PPNBM", Name: "PPnBM 20%", Percent: 20}}},
+	}
+}
+
+// Settings are how a store prices and rounds
+type Settings struct {
+	Mode     string
+	Rounding string
+}
+
+// Default are the settings of the sales made outside of any store
+var Default = Settings{Mode: Exclusive, Rounding: RoundLine}
+
+// Of returns the settings of a store
+func Of(store domain.Store) Settings {
+	settings := Settings{Mode: store.PriceMode, Rounding: store.TaxRounding}
+	if settings.Mode == "" {
+		settings.Mode = Default.Mode
+	}
+	if settings.Rounding == "" {
+		settings.Rounding = Default.Rounding
+	}
+	return settings
+}
+
+// Line is one line of a sale
+type Line struct {
+	// Amount is the price of the line after its discounts, tax included in the Inclusive mode
+	Amount money.Money
+	Rates  []domain.TaxRate
+}
+
+// Amount is the tax of one rate and the base it is computed on
+type Amount struct {
+	Code    string
+	Name    string
+	Percent float64
+	Base    money.Money
+	Tax     money.Money
+}
+
+type LineResult struct {
+	// Net is the amount before tax and Gross the amount after tax, one of them is Line.Amount
+	Net   money.Money
+	Tax   money.Money
+	Gross money.Money
+	Taxes []Amount
+}
+
+type Result struct {
+	Lines []LineResult
+	// Taxes is the breakdown per rate, in the order the rates first appear on the lines. With
+	// RoundInvoice it may differ by a unit from the sum of the lines.
+	Taxes []Amount
+	Net   money.Money
+	Tax   money.Money
+	Gross money.Money
+}
+
+// total accumulates the exact base and tax of a rate, in minor units
+type total struct {
+	rate domain.TaxRate
+	base *big.Rat
+	tax  *big.Rat
+}
+
+// Calculate the taxes of the lines of a sale. The lines are all in one currency, the amounts are
+// exact fractions of a minor unit until they are rounded half away from zero: the tax of every
+// line, and with RoundInvoice the tax of every rate over the sale.
+func Calculate(lines []Line, settings Settings) Result {
+	result := Result{Lines: make([]LineResult, 0, len(lines))}
+	currency := money.DefaultCurrency
+	if len(lines) > 0 {
+		currency = lines[0].Amount.Currency()
+	}
+	totals := map[domain.TaxRate]*total{}
+	var rates []domain.TaxRate
+	amount := money.New(0, currency)
+	net := new(big.Rat)
+
+	for _, line := range lines {
+		lineNet := new(big.Rat).SetInt64(line.Amount.Amount())
+		if settings.Mode == Inclusive {
+			// net = amount * 100 / (100 + the percent of all the rates)
+			divisor := big.NewRat(100, 1)
+			for _, rate := range line.Rates {
+				divisor.Add(divisor, percent(rate))
+			}
+			lineNet.Mul(lineNet, big.NewRat(100, 1)).Quo(lineNet, divisor)
+		}
+
+		lineResult := LineResult{Tax: money.New(0, currency)}
+		for _, rate := range line.Rates {
+			exact := new(big.Rat).Mul(lineNet, percent(rate))
+			exact.Quo(exact, big.NewRat(100, 1))
+			tax := minor(exact, currency)
+			lineResult.Taxes = append(lineResult.Taxes, Amount{Code: rate.Code, Name: rate.Name, Percent: rate.Percent, Base: minor(lineNet, currency), Tax: tax})
+			lineResult.Tax = lineResult.Tax.Add(tax)
+
+			rateTotal, ok := totals[rate]
+			if !ok {
+				rateTotal = &total{rate: rate, base: new(big.Rat), tax: new(big.Rat)}
+				totals[rate] = rateTotal
+				rates = append(rates, rate)
+			}
+			rateTotal.base.Add(rateTotal.base, lineNet)
+			if settings.Rounding == RoundInvoice {
+				rateTotal.tax.Add(rateTotal.tax, exact)
+			} else {
+				rateTotal.tax.Add(rateTotal.tax, new(big.Rat).SetInt64(tax.Amount()))
+			}
+		}
+		if settings.Mode == Inclusive {
+			lineResult.Gross = line.Amount
+			lineResult.Net = line.Amount.Sub(lineResult.Tax)
+		} else {
+			lineResult.Net = line.Amount
+			lineResult.Gross = line.Amount.Add(lineResult.Tax)
+		}
+		result.Lines = append(result.Lines, lineResult)
+		amount = amount.Add(line.Amount)
+		net.Add(net, lineNet)
+	}
+
+	result.Tax = money.New(0, currency)
+	for _, rate := range rates {
+		rateTotal := totals[rate]
+		taxAmount := Amount{Code: rate.Code, Name: rate.Name, Percent: rate.Percent, Base: minor(rateTotal.base, currency), Tax: minor(rateTotal.tax, currency)}
+		result.Taxes = append(result.Taxes, taxAmount)
+		result.Tax = result.Tax.Add(taxAmount.Tax)
+	}
+	if settings.Mode == Inclusive {
+		result.Gross = amount
+		result.Net = amount.Sub(result.Tax)
+	} else {
+		result.Net = minor(net, currency)
+		result.Gross = result.Net.Add(result.Tax)
+	}
+	return result
+}
+
+// percent is the exact decimal percent of a rate, 12.5 is 25/2 and not the nearest binary
+// fraction of it
+func percent(rate domain.TaxRate) *big.Rat {
+	exact, ok := new(big.Rat).SetString(strconv.FormatFloat(rate.Percent, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return exact
+}
+
+// minor rounds an exact amount in minor units of currency half away from zero
+func minor(value *big.Rat, currency string) money.Money {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	// the remainder has the sign of the amount, it rounds away from zero from the half up
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	return money.New(quotient.Int64(), currency)
+}
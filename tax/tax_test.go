@@ -0,0 +1,123 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculate(t *testing.T) {
+	classes := map[string][]domain.TaxRate{}
+	for _, class := range DefaultClasses() {
+		classes[class.TaxClassID] = class.Rates
+	}
+	ppn := func(base money.Money, tax money.Money) Amount {
+		return Amount{Code: "PPN", Name: "PPN 11%", Percent: 11, Base: base, Tax: tax}
+	}
+	idr := money.IDR
+	usd := func(text string) money.Money {
+		amount, err := money.Parse(text, "USD")
+		require.NoError(t, err)
+		return amount
+	}
+
+	tests := []struct {
+		name     string
+		lines    []Line
+		settings Settings
+		taxes    []Amount
+		lineTax  []money.Money
+		net      money.Money
+		tax      money.Money
+		gross    money.Money
+	}{
+		{
+			name:     "exclusive",
+			lines:    []Line{{Amount: idr(10000), Rates: classes[ClassStandard]}, {Amount: idr(5000), Rates: classes[ClassExempt]}},
+			settings: Default,
+			taxes:    []Amount{ppn(idr(10000), idr(1100))},
+			lineTax:  []money.Money{idr(1100), idr(0)},
+			net:      idr(15000), tax: idr(1100), gross: idr(16100),
+		},
+		{
+			name:     "inclusive",
+			lines:    []Line{{Amount: idr(11100), Rates: classes[ClassStandard]}, {Amount: idr(5000), Rates: classes[ClassExempt]}},
+			settings: Settings{Mode: Inclusive, Rounding: RoundLine},
+			taxes:    []Amount{ppn(idr(10000), idr(1100))},
+			lineTax:  []money.Money{idr(1100), idr(0)},
+			net:      idr(15000), tax: idr(1100), gross: idr(16100),
+		},
+		{
+			name:     "luxury goods pay PPN and PPnBM on the same base",
+			lines:    []Line{{Amount: idr(100000), Rates: classes[ClassLuxury]}, {Amount: idr(20000), Rates: classes[ClassStandard]}},
+			settings: Default,
+			taxes:    []Amount{ppn(idr(120000), idr(13200)), {Code: "PPNBM", Name: "PPnBM 20%", Percent: 20, Base: idr(100000), Tax: idr(20000)}},
+			lineTax:  []money.Money{idr(31000), idr(2200)},
+			net:      idr(120000), tax: idr(33200), gross: idr(153200),
+		},
+		{
+			name: "rounded per line",
+			lines: []Line{
+				{Amount: usd("1000.05"), Rates: classes[ClassStandard]},
+				{Amount: usd("1000.05"), Rates: classes[ClassStandard]},
+				{Amount: usd("1000.05"), Rates: classes[ClassStandard]},
+			},
+			settings: Settings{Mode: Exclusive, Rounding: RoundLine},
+			taxes:    []Amount{ppn(usd("3000.15"), usd("330.03"))},
+			lineTax:  []money.Money{usd("110.01"), usd("110.01"), usd("110.01")},
+			net:      usd("3000.15"), tax: usd("330.03"), gross: usd("3330.18"),
+		},
+		{
+			name: "rounded per invoice",
+			lines: []Line{
+				{Amount: usd("1000.05"), Rates: classes[ClassStandard]},
+				{Amount: usd("1000.05"), Rates: classes[ClassStandard]},
+				{Amount: usd("1000.05"), Rates: classes[ClassStandard]},
+			},
+			settings: Settings{Mode: Exclusive, Rounding: RoundInvoice},
+			taxes:    []Amount{ppn(usd("3000.15"), usd("330.02"))},
+			lineTax:  []money.Money{usd("110.01"), usd("110.01"), usd("110.01")},
+			net:      usd("3000.15"), tax: usd("330.02"), gross: usd("3330.17"),
+		},
+		{
+			name:     "whole rupiah rounded per invoice",
+			lines:    []Line{{Amount: idr(1005), Rates: classes[ClassStandard]}, {Amount: idr(1005), Rates: classes[ClassStandard]}},
+			settings: Settings{Mode: Exclusive, Rounding: RoundInvoice},
+			// 110.55 per line
+			taxes:   []Amount{ppn(idr(2010), idr(221))},
+			lineTax: []money.Money{idr(111), idr(111)},
+			net:     idr(2010), tax: idr(221), gross: idr(2231),
+		},
+		{
+			name:     "an exact half is rounded up, 5500 * 0.7% is 38.5",
+			lines:    []Line{{Amount: idr(5500), Rates: []domain.TaxRate{{Code: "PB1", Name: "PB1 0.7%", Percent: 0.7}}}},
+			settings: Default,
+			taxes:    []Amount{{Code: "PB1", Name: "PB1 0.7%", Percent: 0.7, Base: idr(5500), Tax: idr(39)}},
+			lineTax:  []money.Money{idr(39)},
+			net:      idr(5500), tax: idr(39), gross: idr(5539),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Calculate(tt.lines, tt.settings)
+			require.Len(t, result.Lines, len(tt.lines))
+			for i, line := range result.Lines {
+				assert.Equal(t, tt.lineTax[i], line.Tax, "line %d", i)
+				assert.Equal(t, line.Gross, line.Net.Add(line.Tax), "line %d", i)
+			}
+			assert.Equal(t, tt.taxes, result.Taxes)
+			assert.Equal(t, tt.net, result.Net)
+			assert.Equal(t, tt.tax, result.Tax)
+			assert.Equal(t, tt.gross, result.Gross)
+		})
+	}
+}
+
+func TestOf(t *testing.T) {
+	assert.Equal(t, Default, Of(domain.Store{}))
+	assert.Equal(t, Settings{Mode: Inclusive, Rounding: RoundInvoice}, Of(domain.Store{PriceMode: Inclusive, TaxRounding: RoundInvoice}))
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// printed fetches a printed cash report and returns its status and body
+func (a *testApp) printed(url string, key string) (int, string) {
+	a.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-API-Key", key)
+	resp := a.send(req)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(a.t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestCashSession(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	code, response := testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P002", map[string]interface{}{"stock_qty": 10})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"), auth.EmployeePermission("E001"))
+
+	report := func(url string) web.CashReportResponse {
+		code, response := testApp.request(http.MethodGet, url, nil, "X-API-Key", cashier)
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+		var cashReport web.CashReportResponse
+		dataAs(t, response, &cashReport)
+		return cashReport
+	}
+	move := func(sessionId string, kind string, amount string) (int, web.WebResponse) {
+		return testApp.request(http.MethodPost, "/api/cash-sessions/"+sessionId+"/movements", map[string]interface{}{"employee_id": "E001", "type": kind, "amount": amount, "reason": "laci kasir"}, "X-API-Key", cashier)
+	}
+	order := func(customerId string) web.OrderResponse {
+		code, response := testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+			"employee_id": "E001",
+			"customer_id": customerId,
+			"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 3}},
+		}, "X-API-Key", cashier)
+		require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+		var created web.OrderResponse
+		dataAs(t, response, &created)
+		return created
+	}
+
+	code, response = testApp.request(http.MethodPost, "/api/cash-sessions/", map[string]interface{}{"employee_id": "E001", "opening_float": "200000"}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var session web.CashSessionResponse
+	dataAs(t, response, &session)
+	assert.Equal(t, domain.CashSessionOpen, session.Status)
+	assert.Equal(t, "JKT01", session.StoreID)
+	code, response = testApp.request(http.MethodPost, "/api/cash-sessions/", map[string]interface{}{"employee_id": "E001", "opening_float": "100000"}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code, "E001 already has a session open")
+	assert.Contains(t, response.Data, "already has cash session")
+	code, _ = testApp.request(http.MethodPost, "/api/cash-sessions/", map[string]interface{}{"employee_id": "E002", "opening_float": "100000"}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusForbidden, code, "the key is bound to E001")
+
+	// 3 Kopi paid with points, cash and a card, 3 more paid in cash outside of the payments API
+	// and returned in cash
+	paidOrder := order("C001")
+	code, response = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": paidOrder.OrderID, "tenders": []map[string]interface{}{
+		{"method": "points", "points": 50},
+		{"method": "cash", "amount": "11550"},
+		{"method": "card", "amount": "100000", "token": payment.TokenSuccess},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	cashOrder := order("")
+	code, response = testApp.request(http.MethodPost, "/api/returns/", map[string]interface{}{"order_id": cashOrder.OrderID, "employee_id": "E001", "lines": []map[string]interface{}{
+		{"position": 1, "quantity": 1, "reason": "damaged", "disposition": "write_off"},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+
+	code, response = move(session.SessionID, domain.CashIn, "50000")
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	code, response = move(session.SessionID, domain.CashOut, "100000")
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	dataAs(t, response, &session)
+	assert.Len(t, session.Movements, 2)
+	code, response = move(session.SessionID, domain.CashOut, "500000")
+	assert.Equal(t, http.StatusBadRequest, code, "the drawer does not hold that much")
+	assert.Contains(t, response.Data, "only 239250 is expected")
+
+	// 200000 float + 11550 and 116550 in cash - 38850 refunded + 50000 in - 100000 out
+	x := report("/api/cash-sessions/" + session.SessionID + "/report")
+	assert.Equal(t, service.ReportX, x.Kind)
+	assert.Equal(t, 2, x.Orders)
+	assert.Equal(t, money.IDR(233100), x.Sales)
+	assert.Equal(t, 1, x.Returns)
+	assert.Equal(t, money.IDR(194250), x.NetSales)
+	assert.Equal(t, []web.CashReportPaymentResponse{
+		{Method: domain.TenderCash, Sales: money.IDR(128100), Refunds: money.IDR(38850), Net: money.IDR(89250)},
+		{Method: domain.TenderCard, Sales: money.IDR(100000), Refunds: money.IDR(0), Net: money.IDR(100000)},
+		{Method: domain.TenderPoints, Sales: money.IDR(5000), Refunds: money.IDR(0), Net: money.IDR(5000)},
+	}, x.Payments)
+	assert.Equal(t, money.IDR(239250), x.Cash.Expected)
+	assert.Nil(t, x.Cash.Counted)
+
+	code, response = testApp.request(http.MethodPost, "/api/cash-sessions/"+session.SessionID+"/close", map[string]interface{}{"employee_id": "E001", "counts": []map[string]interface{}{
+		{"denomination": "30000", "quantity": 1},
+	}}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code, "there is no 30000 note")
+	code, response = testApp.request(http.MethodPost, "/api/cash-sessions/"+session.SessionID+"/close", map[string]interface{}{"employee_id": "E001", "note": "kurang receh", "counts": []map[string]interface{}{
+		{"denomination": "100000", "quantity": 2},
+		{"denomination": "20000", "quantity": 1},
+		{"denomination": "10000", "quantity": 1},
+		{"denomination": "5000", "quantity": 1},
+		{"denomination": "2000", "quantity": 2},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &session)
+	assert.Equal(t, domain.CashSessionClosed, session.Status)
+	require.NotNil(t, session.Variance)
+	assert.Equal(t, money.IDR(239000), *session.Counted)
+	assert.Equal(t, money.IDR(-250), *session.Variance)
+	code, _ = move(session.SessionID, domain.CashIn, "1000")
+	assert.Equal(t, http.StatusBadRequest, code, "the session is closed")
+
+	z := report("/api/cash-sessions/" + session.SessionID + "/report")
+	assert.Equal(t, service.ReportZ, z.Kind)
+	assert.Equal(t, x.Sales, z.Sales)
+	assert.Equal(t, money.IDR(-250), *z.Cash.Variance)
+	code, body := testApp.printed("/api/cash-sessions/"+session.SessionID+"/report/print?paper=58", cashier)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, "LAPORAN Z")
+	assert.Contains(t, body, "Andi Wijaya")
+	assert.Contains(t, body, "-250")
+
+	date := session.OpenedAt.In(time.Local).Format("2006-01-02")
+	day := report("/api/cash-reports/?date=" + date)
+	assert.Equal(t, service.ReportZ, day.Kind, "the only session of the day is closed")
+	assert.Equal(t, x.Sales, day.Sales)
+	assert.Len(t, day.Sessions, 1)
+	assert.Equal(t, money.IDR(-250), *day.Cash.Variance)
+	code, body = testApp.printed("/api/cash-reports/print?date="+date, cashier)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, "LAPORAN Z HARIAN")
+	empty := report("/api/cash-reports/?date=2020-01-01")
+	assert.Equal(t, service.ReportX, empty.Kind)
+	assert.Equal(t, 0, empty.Orders)
+
+	code, response = testApp.request(http.MethodGet, "/api/cash-sessions/?status=closed", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var sessions []web.CashSessionResponse
+	dataAs(t, response, &sessions)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, session.SessionID, sessions[0].SessionID)
+	bandung := testApp.issueKey("kasir-bdg", auth.StorePermission("BDG01"))
+	code, _ = testApp.request(http.MethodGet, "/api/cash-sessions/"+session.SessionID, nil, "X-API-Key", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestOpenCashSessionKey(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	opened := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	legacy := func(sessionId string, employeeId string, status string, minutes int) *domain.CashSession {
+		return &domain.CashSession{SessionID: sessionId, StoreID: "JKT01", EmployeeID: employeeId, Status: status, OpeningFloat: money.IDR(100000), Counts: []domain.CashCount{}, OpenedAt: opened.Add(time.Duration(minutes) * time.Minute)}
+	}
+	// Sessions opened before the open key, E001 opened two by concurrent requests
+	testApp.seed(
+		legacy("S1", "E001", domain.CashSessionClosed, 1),
+		legacy("S2", "E001", domain.CashSessionOpen, 2),
+		legacy("S3", "E001", domain.CashSessionOpen, 3),
+		legacy("S4", "E002", domain.CashSessionOpen, 1),
+	)
+	require.NoError(t, testApp.application.Migrate())
+	require.NoError(t, testApp.application.Migrate())
+
+	var sessions []domain.CashSession
+	require.NoError(t, testApp.db.Order("session_id").Find(&sessions).Error)
+	keys := map[string]string{}
+	for _, session := range sessions {
+		if session.OpenKey != nil {
+			keys[session.SessionID] = *session.OpenKey
+		}
+	}
+	assert.Equal(t, map[string]string{"S2": "JKT01/E001", "S4": "JKT01/E002"}, keys)
+
+	// The unique index keeps a second open session out, closing one frees the key
+	sessionRepository := repository.NewCashSessionRepository(testApp.db)
+	_, ok, err := sessionRepository.Save(context.Background(), *legacy("S5", "E001", domain.CashSessionOpen, 5))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	closing := *legacy("S2", "E001", domain.CashSessionClosed, 2)
+	_, ok, err = sessionRepository.Update(context.Background(), closing)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, ok, err = sessionRepository.Save(context.Background(), *legacy("S5", "E001", domain.CashSessionOpen, 5))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -1,352 +1,81 @@
 package test
 
 import (
-	"context"
-	"database/sql"
-	"encoding/json"
 	"fmt"
-	"github.com/go-playground/validator/v10"
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/stretchr/testify/assert"
-	"io"
 	"net/http"
-	"net/http/httptest"
-	"programmerzamannow/belajar-golang-restful-api/app"
-	"programmerzamannow/belajar-golang-restful-api/controller"
-	"programmerzamannow/belajar-golang-restful-api/helper"
-	"programmerzamannow/belajar-golang-restful-api/middleware"
-	"programmerzamannow/belajar-golang-restful-api/model/domain"
-	"programmerzamannow/belajar-golang-restful-api/repository"
-	"programmerzamannow/belajar-golang-restful-api/service"
-	"strconv"
-	"strings"
 	"testing"
-	"time"
-)
-
-func setupTestDB() *sql.DB {
-	db, err := sql.Open("mysql", "root@tcp(localhost:3306)/belajar_golang_restful_api_test")
-	helper.PanicIfError(err)
-
-	db.SetMaxIdleConns(5)
-	db.SetMaxOpenConns(20)
-	db.SetConnMaxLifetime(60 * time.Minute)
-	db.SetConnMaxIdleTime(10 * time.Minute)
-
-	return db
-}
-
-func setupRouter(db *sql.DB) http.Handler {
-	validate := validator.New()
-	categoryRepository := repository.NewCategoryRepository()
-	categoryService := service.NewCategoryService(categoryRepository, db, validate)
-	categoryController := controller.NewCategoryController(categoryService)
-	router := app.NewRouter(categoryController)
-
-	return middleware.NewAuthMiddleware(router)
-}
-
-func truncateCategory(db *sql.DB) {
-	db.Exec("TRUNCATE category")
-}
-
-func TestCreateCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
-
-	requestBody := strings.NewReader(`{"name" : "Gadget"}`)
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories", requestBody)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 200, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 200, int(responseBody["code"].(float64)))
-	assert.Equal(t, "OK", responseBody["status"])
-	assert.Equal(t, "Gadget", responseBody["data"].(map[string]interface{})["name"])
-}
-
-func TestCreateCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
-
-	requestBody := strings.NewReader(`{"name" : ""}`)
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories", requestBody)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 400, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 400, int(responseBody["code"].(float64)))
-	assert.Equal(t, "BAD REQUEST", responseBody["status"])
-}
-
-func TestUpdateCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-
-	tx, _ := db.Begin()
-	categoryRepository := repository.NewCategoryRepository()
-	category := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
-
-	router := setupRouter(db)
-
-	requestBody := strings.NewReader(`{"name" : "Gadget"}`)
-	request := httptest.NewRequest(http.MethodPut, "http://localhost:3000/api/categories/"+strconv.Itoa(category.Id), requestBody)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 200, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 200, int(responseBody["code"].(float64)))
-	assert.Equal(t, "OK", responseBody["status"])
-	assert.Equal(t, category.Id, int(responseBody["data"].(map[string]interface{})["id"].(float64)))
-	assert.Equal(t, "Gadget", responseBody["data"].(map[string]interface{})["name"])
-}
-
-func TestUpdateCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-
-	tx, _ := db.Begin()
-	categoryRepository := repository.NewCategoryRepository()
-	category := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
-
-	router := setupRouter(db)
-
-	requestBody := strings.NewReader(`{"name" : ""}`)
-	request := httptest.NewRequest(http.MethodPut, "http://localhost:3000/api/categories/"+strconv.Itoa(category.Id), requestBody)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 400, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 400, int(responseBody["code"].(float64)))
-	assert.Equal(t, "BAD REQUEST", responseBody["status"])
-}
-
-func TestGetCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
 
-	tx, _ := db.Begin()
-	categoryRepository := repository.NewCategoryRepository()
-	category := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
-
-	router := setupRouter(db)
-
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/"+strconv.Itoa(category.Id), nil)
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 200, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 200, int(responseBody["code"].(float64)))
-	assert.Equal(t, "OK", responseBody["status"])
-	assert.Equal(t, category.Id, int(responseBody["data"].(map[string]interface{})["id"].(float64)))
-	assert.Equal(t, category.Name, responseBody["data"].(map[string]interface{})["name"])
-}
-
-func TestGetCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
-
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/404", nil)
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 404, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 404, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
-}
-
-func TestDeleteCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-
-	tx, _ := db.Begin()
-	categoryRepository := repository.NewCategoryRepository()
-	category := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
-
-	router := setupRouter(db)
-
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/"+strconv.Itoa(category.Id), nil)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 200, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 200, int(responseBody["code"].(float64)))
-	assert.Equal(t, "OK", responseBody["status"])
-}
-
-func TestDeleteCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
-
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/404", nil)
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-API-Key", "RAHASIA")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 404, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
-	assert.Equal(t, 404, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
+func TestCategoryListAndGet(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	code, response := testApp.request(http.MethodGet, "/api/categories/", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var categories []web.CategoryResponse
+	dataAs(t, response, &categories)
+	assert.Equal(t, []web.CategoryResponse{{Id: 1, Name: "Electronics"}, {Id: 2, Name: "Food"}}, categories)
+
+	code, response = testApp.request(http.MethodGet, "/api/categories/2", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var category web.CategoryResponse
+	dataAs(t, response, &category)
+	assert.Equal(t, web.CategoryResponse{Id: 2, Name: "Food"}, category)
 }
 
-func TestListCategoriesSuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-
-	tx, _ := db.Begin()
-	categoryRepository := repository.NewCategoryRepository()
-	category1 := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	category2 := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Computer",
-	})
-	tx.Commit()
-
-	router := setupRouter(db)
+func TestCategoryLifecycle(t *testing.T) {
+	testApp := setupTestApp(t)
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories", nil)
-	request.Header.Add("X-API-Key", "RAHASIA")
+	code, response := testApp.request(http.MethodPost, "/api/categories/", web.CategoryCreateRequest{Name: "Gadget"})
+	require.Equal(t, http.StatusCreated, code)
+	var created web.CategoryResponse
+	dataAs(t, response, &created)
+	require.NotZero(t, created.Id)
+	assert.Equal(t, "Gadget", created.Name)
 
-	recorder := httptest.NewRecorder()
+	code, response = testApp.request(http.MethodPut, fmt.Sprintf("/api/categories/%d", created.Id), map[string]string{"name": "Gadget & Aksesoris"})
+	assert.Equal(t, http.StatusOK, code)
 
-	router.ServeHTTP(recorder, request)
+	code, response = testApp.request(http.MethodGet, fmt.Sprintf("/api/categories/%d", created.Id), nil)
+	assert.Equal(t, http.StatusOK, code)
+	var updated web.CategoryResponse
+	dataAs(t, response, &updated)
+	assert.Equal(t, "Gadget & Aksesoris", updated.Name)
 
-	response := recorder.Result()
-	assert.Equal(t, 200, response.StatusCode)
+	code, _ = testApp.request(http.MethodDelete, fmt.Sprintf("/api/categories/%d", created.Id), nil)
+	assert.Equal(t, http.StatusOK, code)
 
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 200, int(responseBody["code"].(float64)))
-	assert.Equal(t, "OK", responseBody["status"])
-
-	fmt.Println(responseBody)
-
-	var categories = responseBody["data"].([]interface{})
-
-	categoryResponse1 := categories[0].(map[string]interface{})
-	categoryResponse2 := categories[1].(map[string]interface{})
-
-	assert.Equal(t, category1.Id, int(categoryResponse1["id"].(float64)))
-	assert.Equal(t, category1.Name, categoryResponse1["name"])
-
-	assert.Equal(t, category2.Id, int(categoryResponse2["id"].(float64)))
-	assert.Equal(t, category2.Name, categoryResponse2["name"])
+	code, response = testApp.request(http.MethodGet, fmt.Sprintf("/api/categories/%d", created.Id), nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "Not Found", response.Status)
 }
 
-func TestUnauthorized(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
-
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories", nil)
-	request.Header.Add("X-API-Key", "SALAH")
-
-	recorder := httptest.NewRecorder()
-
-	router.ServeHTTP(recorder, request)
-
-	response := recorder.Result()
-	assert.Equal(t, 401, response.StatusCode)
-
-	body, _ := io.ReadAll(response.Body)
-	var responseBody map[string]interface{}
-	json.Unmarshal(body, &responseBody)
-
-	assert.Equal(t, 401, int(responseBody["code"].(float64)))
-	assert.Equal(t, "UNAUTHORIZED", responseBody["status"])
+func TestCategoryErrors(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		expectedCode int
+	}{
+		{name: "get missing", method: http.MethodGet, url: "/api/categories/99", expectedCode: http.StatusNotFound},
+		{name: "update missing", method: http.MethodPut, url: "/api/categories/99", body: map[string]string{"name": "Baru"}, expectedCode: http.StatusNotFound},
+		{name: "delete missing", method: http.MethodDelete, url: "/api/categories/99", expectedCode: http.StatusNotFound},
+		{name: "create without name", method: http.MethodPost, url: "/api/categories/", body: map[string]string{}, expectedCode: http.StatusBadRequest},
+		{name: "non numeric id", method: http.MethodGet, url: "/api/categories/abc", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := testApp.request(tt.method, tt.url, tt.body)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
 }
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerListAndGet(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	code, response := testApp.request(http.MethodGet, "/api/customers/", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var customers []web.CustomerResponse
+	dataAs(t, response, &customers)
+	require.Len(t, customers, 2)
+	assert.Equal(t, "C001", customers[0].CustomerID)
+
+	code, response = testApp.request(http.MethodGet, "/api/customers/C002", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var customer web.CustomerResponse
+	dataAs(t, response, &customer)
+	assert.Equal(t, web.CustomerResponse{
+		CustomerID: "C002", Name: "Sari Dewi", Email: "sari@example.com", Phone: "081298765432", Address: "Bandung",
+	}, customer)
+}
+
+func TestCustomerLifecycle(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, response := testApp.request(http.MethodPost, "/api/customers/", web.CustomerCreateRequest{
+		Name: "Rina Marlina", Email: "rina@example.com", Phone: "081377778888", Address: "Surabaya", LoyaltyPts: 5,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created web.CustomerResponse
+	dataAs(t, response, &created)
+	require.NotEmpty(t, created.CustomerID)
+
+	url := "/api/customers/" + created.CustomerID
+	code, response = testApp.request(http.MethodPut, url, web.CustomerUpdateRequest{
+		CustomerID: created.CustomerID, Name: "Rina Marlina", Email: "rina.m@example.com", Phone: "081377778888", Address: "Malang", LoyaltyPts: 25,
+	})
+	assert.Equal(t, http.StatusOK, code)
+
+	code, response = testApp.request(http.MethodGet, url, nil)
+	assert.Equal(t, http.StatusOK, code)
+	var updated web.CustomerResponse
+	dataAs(t, response, &updated)
+	assert.Equal(t, "rina.m@example.com", updated.Email)
+	assert.Equal(t, "Malang", updated.Address)
+	assert.Equal(t, 25, updated.LoyaltyPts)
+
+	code, _ = testApp.request(http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = testApp.request(http.MethodGet, url, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestCustomerErrors(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		expectedCode int
+	}{
+		{name: "get missing", method: http.MethodGet, url: "/api/customers/C999", expectedCode: http.StatusNotFound},
+		{
+			name: "update missing", method: http.MethodPut, url: "/api/customers/C999",
+			body:         web.CustomerUpdateRequest{CustomerID: "C999", Name: "X", Email: "x@example.com", Phone: "0812"},
+			expectedCode: http.StatusNotFound,
+		},
+		{name: "delete missing", method: http.MethodDelete, url: "/api/customers/C999", expectedCode: http.StatusNotFound},
+		{
+			name: "create with invalid email", method: http.MethodPost, url: "/api/customers/",
+			body:         map[string]interface{}{"name": "X", "email": "bukan-email", "phone": "0812"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := testApp.request(tt.method, tt.url, tt.body)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
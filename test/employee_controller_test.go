@@ -0,0 +1,87 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployeeListAndGet(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	code, response := testApp.request(http.MethodGet, "/api/employees/", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var employees []web.EmployeeResponse
+	dataAs(t, response, &employees)
+	require.Len(t, employees, 1)
+
+	code, response = testApp.request(http.MethodGet, "/api/employees/E001", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var employee web.EmployeeResponse
+	dataAs(t, response, &employee)
+	assert.Equal(t, web.EmployeeResponse{
+		EmployeeID: "E001", Name: "Andi Wijaya", Role: "cashier", Email: "andi@example.com", Phone: "081211112222", DateHired: "2023-01-15",
+	}, employee)
+}
+
+func TestEmployeeLifecycle(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, response := testApp.request(http.MethodPost, "/api/employees/", web.EmployeeCreateRequest{
+		Name: "Dewi Lestari", Role: "manager", Email: "dewi@example.com", Phone: "081355556666", DateHired: "2024-03-01",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created web.EmployeeResponse
+	dataAs(t, response, &created)
+	require.NotEmpty(t, created.EmployeeID)
+
+	url := "/api/employees/" + created.EmployeeID
+	code, response = testApp.request(http.MethodPut, url, web.EmployeeUpdateRequest{
+		EmployeeID: created.EmployeeID, Name: "Dewi Lestari", Role: "supervisor", Email: "dewi@example.com", Phone: "081355556666", DateHired: "2024-03-01",
+	})
+	assert.Equal(t, http.StatusOK, code)
+
+	code, response = testApp.request(http.MethodGet, url, nil)
+	assert.Equal(t, http.StatusOK, code)
+	var updated web.EmployeeResponse
+	dataAs(t, response, &updated)
+	assert.Equal(t, "supervisor", updated.Role)
+
+	code, _ = testApp.request(http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = testApp.request(http.MethodGet, url, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestEmployeeErrors(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		expectedCode int
+	}{
+		{name: "get missing", method: http.MethodGet, url: "/api/employees/E999", expectedCode: http.StatusNotFound},
+		{name: "delete missing", method: http.MethodDelete, url: "/api/employees/E999", expectedCode: http.StatusNotFound},
+		{
+			name: "create without role", method: http.MethodPost, url: "/api/employees/",
+			body:         map[string]interface{}{"name": "X", "email": "x@example.com", "phone": "0812", "date_hired": "2024-01-01"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := testApp.request(tt.method, tt.url, tt.body)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
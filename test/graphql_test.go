@@ -0,0 +1,128 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/gql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLResult struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// graphql posts a GraphQL request with the API key
+func (a *testApp) graphql(query string, variables map[string]interface{}, headers ...string) (int, graphQLResult) {
+	a.t.Helper()
+	body, err := json.Marshal(gql.GraphQLRequest{Query: query, Variables: variables})
+	require.NoError(a.t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp := a.send(req)
+	defer resp.Body.Close()
+	var result graphQLResult
+	require.NoError(a.t, json.NewDecoder(resp.Body).Decode(&result))
+	return resp.StatusCode, result
+}
+
+func TestGraphQLQueries(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	code, result := testApp.graphql(`query ($id: String!) {
+		product(id: $id) { name price price_amount currency category { id name products { product_id } } }
+		customers { customer_id loyalty_points }
+		employee(id: "E001") { name role }
+	}`, map[string]interface{}{"id": "P001"})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, result.Errors)
+	assert.JSONEq(t, `{
+		"product": {"name": "Laptop Gaming", "price": 15000000, "price_amount": "15000000", "currency": "IDR", "category": {"id": 1, "name": "Electronics", "products": [{"product_id": "P001"}]}},
+		"customers": [{"customer_id": "C001", "loyalty_points": 120}, {"customer_id": "C002", "loyalty_points": 0}],
+		"employee": {"name": "Andi Wijaya", "role": "cashier"}
+	}`, string(result.Data))
+
+	code, result = testApp.graphql(`{ product(id: "P404") { name } }`, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, gql.CodeNotFound, result.Errors[0].Extensions["code"])
+	assert.Equal(t, []interface{}{"product"}, result.Errors[0].Path)
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	code, result := testApp.graphql(`mutation ($input: ProductInput!) { createProduct(input: $input) { product_id name category { name } } }`, map[string]interface{}{
+		"input": map[string]interface{}{"name": "Teh Celup", "price": 12000, "stock_qty": 50, "category": "Food", "sku": "TEH025"},
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, result.Errors)
+	var created struct {
+		CreateProduct struct {
+			ProductID string `json:"product_id"`
+			Name      string `json:"name"`
+		} `json:"createProduct"`
+	}
+	require.NoError(t, json.Unmarshal(result.Data, &created))
+	assert.Equal(t, "Teh Celup", created.CreateProduct.Name)
+
+	// The REST API sees the same product
+	code, _ = testApp.request(http.MethodGet, "/api/products/"+created.CreateProduct.ProductID, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	// price_amount is read exactly and wins over price
+	code, result = testApp.graphql(`mutation ($id: String!, $input: ProductInput!) { updateProduct(id: $id, input: $input) { price price_amount } }`, map[string]interface{}{
+		"id":    created.CreateProduct.ProductID,
+		"input": map[string]interface{}{"name": "Teh Celup", "price": 1, "price_amount": "12500", "stock_qty": 50, "category": "Food", "sku": "TEH025"},
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, result.Errors)
+	assert.JSONEq(t, `{"updateProduct": {"price": 12500, "price_amount": "12500"}}`, string(result.Data))
+
+	code, result = testApp.graphql(`mutation { createProduct(input: {name: "Teh", price_amount: "12.5.0", stock_qty: 1, category: "Food", sku: "TEH001"}) { product_id } }`, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, result.Errors, 1)
+
+	// Validation of the services applies to GraphQL too
+	code, result = testApp.graphql(`mutation { updateCustomer(id: "C001", input: {name: "Budi", email: "bukan-email", phone: "0812"}) { name } }`, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, gql.CodeBadRequest, result.Errors[0].Extensions["code"])
+
+	code, result = testApp.graphql(`mutation { deleteCategory(id: 2) }`, nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"deleteCategory": true}`, string(result.Data))
+}
+
+func TestGraphQLRejectedRequests(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, _ := testApp.graphql(`{ categories { name } }`, nil, "X-API-Key", "SALAH")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, result := testApp.graphql(`{ categories { products { category { products { category { products { name } } } } } } }`, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, "query depth")
+
+	code, result = testApp.graphql(`{ categories { nama } }`, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.NotEmpty(t, result.Errors)
+}
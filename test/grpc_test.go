@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/rpc"
+	"github.com/aronipurwanto/go-restful-api/rpc/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcClient serves the gRPC server of the application over an in-memory connection
+func (a *testApp) grpcClient() *grpc.ClientConn {
+	a.t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	go a.application.GRPC.Serve(listener)
+	a.t.Cleanup(a.application.GRPC.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(a.t, err)
+	a.t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCSharesTheServiceLayer(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	conn := testApp.grpcClient()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), rpc.APIKeyMetadata, apiKey)
+
+	category, err := pb.NewCategoryServiceClient(conn).CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Beverages"})
+	require.NoError(t, err)
+	assert.Equal(t, "Beverages", category.GetName())
+
+	products := pb.NewProductServiceClient(conn)
+	product, err := products.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Teh Botol", Price: 5000, StockQty: 48, Category: "Beverages", Sku: "TEH330", TaxRate: 11,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, product.GetProductId())
+	assert.Equal(t, "5000", product.GetPriceAmount())
+	assert.Equal(t, "IDR", product.GetCurrency())
+
+	// price_amount is read exactly and wins over price
+	product, err = products.UpdateProduct(ctx, &pb.UpdateProductRequest{
+		ProductId: product.GetProductId(), Name: "Teh Botol", Price: 1, PriceAmount: "5500", StockQty: 48, Category: "Beverages", Sku: "TEH330", TaxRate: 11,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "5500", product.GetPriceAmount())
+	assert.Equal(t, 5500.0, product.GetPrice())
+
+	// Written over gRPC, read over REST
+	code, response := testApp.request(http.MethodGet, "/api/products/"+product.GetProductId(), nil)
+	require.Equal(t, http.StatusOK, code)
+	var productResponse web.ProductResponse
+	dataAs(t, response, &productResponse)
+	assert.Equal(t, "Teh Botol", productResponse.Name)
+	assert.Equal(t, 48, productResponse.StockQty)
+
+	list, err := products.ListProducts(ctx, &pb.ListProductsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.GetProducts(), 3)
+
+	employee, err := pb.NewEmployeeServiceClient(conn).GetEmployee(ctx, &pb.GetEmployeeRequest{EmployeeId: "E001"})
+	require.NoError(t, err)
+	assert.Equal(t, "Andi Wijaya", employee.GetName())
+
+	customers, err := pb.NewCustomerServiceClient(conn).ListCustomers(ctx, &pb.ListCustomersRequest{})
+	require.NoError(t, err)
+	assert.Len(t, customers.GetCustomers(), 2)
+}
+
+func TestGRPCErrors(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	conn := testApp.grpcClient()
+	products := pb.NewProductServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), rpc.APIKeyMetadata, apiKey)
+
+	tests := []struct {
+		name       string
+		call       func() error
+		expectCode codes.Code
+	}{
+		{
+			name: "missing API key",
+			call: func() error {
+				_, err := products.GetProduct(context.Background(), &pb.GetProductRequest{ProductId: "P001"})
+				return err
+			},
+			expectCode: codes.Unauthenticated,
+		},
+		{
+			name: "unknown product",
+			call: func() error {
+				_, err := products.GetProduct(ctx, &pb.GetProductRequest{ProductId: "P999"})
+				return err
+			},
+			expectCode: codes.NotFound,
+		},
+		{
+			name: "invalid product",
+			call: func() error {
+				_, err := products.CreateProduct(ctx, &pb.CreateProductRequest{Name: "Tanpa SKU", Price: 1000, StockQty: 1, Category: "Food"})
+				return err
+			},
+			expectCode: codes.InvalidArgument,
+		},
+		{
+			name: "price amount not a decimal",
+			call: func() error {
+				_, err := products.CreateProduct(ctx, &pb.CreateProductRequest{Name: "Teh", PriceAmount: "5.000,00", StockQty: 1, Category: "Food", Sku: "TEH001"})
+				return err
+			},
+			expectCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectCode, status.Code(tt.call()))
+		})
+	}
+}
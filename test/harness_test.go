@@ -0,0 +1,141 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/app"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	apiKey       = "RAHASIA"
+	dashboardKey = "DASHBOARD"
+)
+
+// testApp is the real application, wired by the same Wire provider sets as main, on top of a private
+// in-memory SQLite database that only lives for the duration of one test
+type testApp struct {
+	t           *testing.T
+	application *app.Application
+	server      *fiber.App
+	db          *gorm.DB
+}
+
+func setupTestApp(t *testing.T) *testApp {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", name, time.Now().UnixNano())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// The in-memory database disappears with its last connection, keep exactly one around
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	config := app.NewConfig()
+	config.MetricsAddr = ""
+	config.MetricsToken = ""
+	config.HealthTimeout = time.Second
+	// Every response of the integration suite must also honour the OpenAPI contract
+	config.OpenAPIValidation = true
+	config.OpenAPIValidateResponses = true
+	config.APIKeys = apiKey + "=admin:*;" + dashboardKey + "=dashboard:stream:products"
+	config.StreamHeartbeat = 100 * time.Millisecond
+
+	application := app.InitializeApplicationWithDB(config, db)
+	require.NoError(t, db.Use(application.Metrics.GormPlugin()))
+	require.NoError(t, application.Migrate())
+
+	return &testApp{t: t, application: application, server: application.Server, db: db}
+}
+
+// seed inserts fixtures directly through GORM
+func (a *testApp) seed(values ...interface{}) {
+	a.t.Helper()
+	for _, value := range values {
+		require.NoError(a.t, a.db.Create(value).Error)
+	}
+}
+
+// seedFixtures inserts a small catalogue shared by the resource tests
+func (a *testApp) seedFixtures() {
+	a.seed(
+		&[]domain.Category{{Id: 1, Name: "Electronics"}, {Id: 2, Name: "Food"}},
+		&[]domain.Customer{
+			{CustomerID: "C001", Name: "Budi Santoso", Email: "budi@example.com", Phone: "081234567890", Address: "Jakarta", LoyaltyPts: 120},
+			{CustomerID: "C002", Name: "Sari Dewi", Email: "sari@example.com", Phone: "081298765432", Address: "Bandung", LoyaltyPts: 0},
+		},
+		&[]domain.Employee{
+			{EmployeeID: "E001", Name: "Andi Wijaya", Role: "cashier", Email: "andi@example.com", Phone: "081211112222", DateHired: "2023-01-15"},
+		},
+		&[]domain.Product{
+			{ProductID: "P001", Name: "Laptop Gaming", Description: "Laptop dengan spesifikasi tinggi", Price: money.IDR(15000000), StockQty: 10, Category: "Electronics", SKU: "LAP123", TaxClassID: tax.ClassStandard, TaxRate: 11},
+			{ProductID: "P002", Name: "Kopi Bubuk", Description: "Kopi robusta 250g", Price: money.IDR(35000), StockQty: 200, Category: "Food", SKU: "KOP250", TaxClassID: tax.ClassStandard, TaxRate: 11},
+		},
+	)
+}
+
+// request sends a JSON request with the API key and decodes the WebResponse envelope.
+// Extra headers are given as name/value pairs, they may also override the API key.
+func (a *testApp) request(method string, url string, body interface{}, headers ...string) (int, web.WebResponse) {
+	a.t.Helper()
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		require.NoError(a.t, err)
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req := httptest.NewRequest(method, url, reqBody)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp := a.send(req)
+	defer resp.Body.Close()
+
+	var webResponse web.WebResponse
+	require.NoError(a.t, json.NewDecoder(resp.Body).Decode(&webResponse))
+	require.NotEqual(a.t, "Contract Violation", webResponse.Status, "%v", webResponse.Data)
+	return resp.StatusCode, webResponse
+}
+
+// send runs a raw request through fiber.App.Test, for the endpoints that do not answer with a WebResponse
+func (a *testApp) send(req *http.Request) *http.Response {
+	a.t.Helper()
+	resp, err := a.server.Test(req, -1)
+	require.NoError(a.t, err)
+	return resp
+}
+
+// dataAs re-decodes the data field of a response into result
+func dataAs(t *testing.T, response web.WebResponse, result interface{}) {
+	t.Helper()
+	encoded, err := json.Marshal(response.Data)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, result))
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receipt fetches the receipt of an order and returns its status, content type and body
+func (a *testApp) receipt(orderId string, query string, key string) (int, string, []byte) {
+	a.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/orders/"+orderId+"/receipt"+query, nil)
+	req.Header.Set("X-API-Key", key)
+	resp := a.send(req)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(a.t, err)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), body
+}
+
+func TestOrderFlow(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.seed(&domain.Promotion{PromotionID: "PR1", Name: "Diskon Elektronik", Type: domain.PromotionPercentage, Value: 10, Categories: []string{"Electronics"}, UsageLimit: 5, Active: true})
+
+	code, response := testApp.request(http.MethodPost, "/api/stores/", web.StoreCreateRequest{
+		StoreID:       "JKT01",
+		Name:          "Toko Maju Jaya",
+		Address:       "Jl. Thamrin 1, Jakarta",
+		Phone:         "021-555-0101",
+		ReceiptHeader: "NPWP 01.234.567.8-901.000",
+		ReceiptFooter: "Terima kasih\nBarang yang sudah dibeli tidak dapat ditukar",
+		ReceiptQR:     "https://example.com/r/{order_id}",
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	testApp.createStore("BDG01", "Bandung Dago")
+	for productId, quantity := range map[string]int{"P001": 3, "P002": 50} {
+		code, response = testApp.request(http.MethodPut, "/api/stores/JKT01/stock/"+productId, map[string]interface{}{"stock_qty": quantity})
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	}
+
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"customer_id": "C001",
+		"lines": []map[string]interface{}{
+			{"product_id": "P001", "quantity": 1},
+			{"product_id": "P002", "quantity": 2},
+		},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var order web.OrderResponse
+	dataAs(t, response, &order)
+	assert.Equal(t, "JKT01", order.StoreID)
+	require.Len(t, order.Lines, 2)
+	assert.Equal(t, money.IDR(1500000), order.Lines[0].Discount)
+	assert.Equal(t, money.IDR(15070000), order.Subtotal)
+	assert.Equal(t, money.IDR(1500000), order.Discount)
+	assert.Equal(t, money.IDR(1492700), order.Tax)
+	assert.Equal(t, money.IDR(15062700), order.Total)
+	assert.Equal(t, 1506, order.PointsEarned)
+	assert.Equal(t, 1626, order.PointsBalance)
+
+	// The sale took the stock of the store, counted the promotion and credited the customer
+	code, response = testApp.request(http.MethodGet, "/api/stores/JKT01/stock", nil)
+	require.Equal(t, http.StatusOK, code)
+	var stocks []web.StoreStockResponse
+	dataAs(t, response, &stocks)
+	require.Len(t, stocks, 2)
+	assert.Equal(t, 2, stocks[0].StockQty)
+	assert.Equal(t, 48, stocks[1].StockQty)
+	code, response = testApp.request(http.MethodGet, "/api/promotions/PR1", nil)
+	require.Equal(t, http.StatusOK, code)
+	var promotion web.PromotionResponse
+	dataAs(t, response, &promotion)
+	assert.Equal(t, 1, promotion.UsageCount)
+	code, response = testApp.request(http.MethodGet, "/api/customers/C001", nil)
+	require.Equal(t, http.StatusOK, code)
+	var customer web.CustomerResponse
+	dataAs(t, response, &customer)
+	assert.Equal(t, 1626, customer.LoyaltyPts)
+
+	code, response = testApp.request(http.MethodGet, "/api/orders/", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var orders []web.OrderResponse
+	dataAs(t, response, &orders)
+	require.Len(t, orders, 1)
+	assert.Equal(t, order.OrderID, orders[0].OrderID)
+
+	code, response = testApp.request(http.MethodGet, "/api/orders/"+order.OrderID, nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var found web.OrderResponse
+	dataAs(t, response, &found)
+	assert.Equal(t, order.Lines, found.Lines)
+	assert.Equal(t, order.Taxes, found.Taxes)
+
+	// A failed sale leaves everything as it was
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"customer_id": "C001",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 1}, {"product_id": "P001", "quantity": 5}},
+	}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code, "%v", response.Data)
+	code, response = testApp.request(http.MethodGet, "/api/customers/C001", nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &customer)
+	assert.Equal(t, 1626, customer.LoyaltyPts)
+	code, _ = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E404",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 1}},
+	}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	bandung := testApp.issueKey("kasir-bdg", auth.StorePermission("BDG01"))
+	code, _ = testApp.request(http.MethodGet, "/api/orders/"+order.OrderID, nil, "X-API-Key", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _, _ = testApp.receipt(order.OrderID, "", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestOrderReceipt(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	code, response := testApp.request(http.MethodPost, "/api/stores/", web.StoreCreateRequest{
+		StoreID:       "JKT01",
+		Name:          "Toko Maju Jaya",
+		Address:       "Jl. Thamrin 1, Jakarta",
+		ReceiptFooter: "Terima kasih",
+		ReceiptQR:     "https://example.com/r/{order_id}",
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	code, response = testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P002", map[string]interface{}{"stock_qty": 10})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"customer_id": "C001",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 3}},
+	}, "X-Store-ID", "JKT01")
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var order web.OrderResponse
+	dataAs(t, response, &order)
+	qr := "https://example.com/r/" + order.OrderID
+
+	code, contentType, body := testApp.receipt(order.OrderID, "", apiKey)
+	require.Equal(t, http.StatusOK, code, "%s", body)
+	assert.Equal(t, "text/plain; charset=utf-8", contentType)
+	text := string(body)
+	assert.Contains(t, text, "Toko Maju Jaya")
+	assert.Contains(t, text, fmt.Sprintf("Kasir%43s\n", "Andi Wijaya"))
+	assert.Contains(t, text, fmt.Sprintf("Pelanggan%39s\n", "Budi Santoso"))
+	assert.Contains(t, text, fmt.Sprintf("  3 x 35.000%36s\n", "105.000"))
+	assert.Contains(t, text, fmt.Sprintf("TOTAL%43s\n", "116.550"))
+	assert.Contains(t, text, fmt.Sprintf("Saldo poin%38s\n", "131"))
+	assert.Contains(t, text, "Terima kasih")
+
+	code, contentType, body = testApp.receipt(order.OrderID, "?format=html&paper=58", apiKey)
+	require.Equal(t, http.StatusOK, code, "%s", body)
+	assert.Equal(t, "text/html; charset=utf-8", contentType)
+	assert.Contains(t, string(body), "width: 58mm")
+	assert.Contains(t, string(body), "<svg class=\"qr\"")
+
+	code, contentType, body = testApp.receipt(order.OrderID, "?format=escpos&paper=58", apiKey)
+	require.Equal(t, http.StatusOK, code, "%s", body)
+	assert.Equal(t, "application/octet-stream", contentType)
+	assert.True(t, bytes.HasPrefix(body, []byte{0x1b, '@'}))
+	assert.True(t, bytes.HasSuffix(body, []byte{0x1d, 'V', 66, 0}))
+	assert.Contains(t, string(body), fmt.Sprintf("TOTAL%27s\n", "116.550"))
+	assert.Contains(t, string(body), "1P0"+qr)
+
+	code, _, _ = testApp.receipt(order.OrderID, "?paper=110", apiKey)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = testApp.receipt(order.OrderID, "?format=pdf", apiKey)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = testApp.receipt("O404", "", apiKey)
+	assert.Equal(t, http.StatusNotFound, code)
+}
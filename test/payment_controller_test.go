@@ -0,0 +1,234 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRISPayment(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	code, response := testApp.request(http.MethodPost, "/api/stores/", web.StoreCreateRequest{StoreID: "JKT01", Name: "Toko Maju Jaya", ReceiptQR: "{qris}"})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	testApp.createStore("BDG01", "Bandung Dago")
+	code, response = testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P002", map[string]interface{}{"stock_qty": 10})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 3}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var order web.OrderResponse
+	dataAs(t, response, &order)
+
+	code, response = testApp.request(http.MethodPost, "/api/payments/qris", map[string]interface{}{"order_id": order.OrderID, "image": "png"}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var payment web.QRISResponse
+	dataAs(t, response, &payment)
+	assert.Equal(t, order.Total, payment.Amount)
+	assert.Equal(t, strings.ToUpper(order.OrderID[:8]), payment.Reference)
+	assert.True(t, strings.HasPrefix(payment.Image, "data:image/png;base64,"))
+
+	// The receipt of the store prints the same payload and the payload reads back
+	code, _, body := testApp.receipt(order.OrderID, "?format=escpos", cashier)
+	require.Equal(t, http.StatusOK, code, "%s", body)
+	assert.Contains(t, string(body), "1P0"+payment.Payload)
+	code, response = testApp.request(http.MethodPost, "/api/payments/qris/verify", map[string]interface{}{"payload": payment.Payload})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var verified web.QRISResponse
+	dataAs(t, response, &verified)
+	assert.True(t, verified.OwnMerchant)
+	assert.Equal(t, order.Total, verified.Amount)
+
+	code, response = testApp.request(http.MethodPost, "/api/payments/qris", map[string]interface{}{"amount": "25000"})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &payment)
+	assert.Equal(t, money.IDR(25000), payment.Amount)
+
+	tampered := strings.Replace(payment.Payload, "25000", "2500", 1)
+	code, _ = testApp.request(http.MethodPost, "/api/payments/qris/verify", map[string]interface{}{"payload": tampered})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodPost, "/api/payments/qris", map[string]interface{}{"order_id": order.OrderID, "amount": "25000"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodPost, "/api/payments/qris", map[string]interface{}{"order_id": "O404"})
+	assert.Equal(t, http.StatusNotFound, code)
+	bandung := testApp.issueKey("kasir-bdg", auth.StorePermission("BDG01"))
+	code, _ = testApp.request(http.MethodPost, "/api/payments/qris", map[string]interface{}{"order_id": order.OrderID}, "X-API-Key", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestCardPayment(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	code, response := testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P002", map[string]interface{}{"stock_qty": 10})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	gateway, ok := testApp.application.PaymentProvider.(*payment.Fake)
+	require.True(t, ok)
+
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"customer_id": "C001",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 3}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var order web.OrderResponse
+	dataAs(t, response, &order)
+	require.Equal(t, money.IDR(116550), order.Total)
+	points := func() int {
+		code, response := testApp.request(http.MethodGet, "/api/customers/C001", nil)
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+		var customer web.CustomerResponse
+		dataAs(t, response, &customer)
+		return customer.LoyaltyPts
+	}
+	balance := points()
+
+	// A declined card fails the whole payment and gives the points back
+	code, response = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": order.OrderID, "tenders": []map[string]interface{}{
+		{"method": "points", "points": 50},
+		{"method": "card", "amount": "111550", "token": payment.TokenDecline},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusBadRequest, code, "%v", response.Data)
+	assert.Contains(t, response.Data, "insufficient funds")
+	assert.Equal(t, balance, points())
+
+	// Points, cash with change and a card the gateway settles later
+	code, response = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": order.OrderID, "tenders": []map[string]interface{}{
+		{"method": "points", "points": 50},
+		{"method": "cash", "amount": "11550", "tendered": "20000"},
+		{"method": "card", "amount": "100000", "token": payment.TokenDelayed},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var split web.PaymentResponse
+	dataAs(t, response, &split)
+	assert.Equal(t, domain.PaymentPending, split.Status)
+	assert.Equal(t, "C001", split.CustomerID)
+	assert.Equal(t, money.IDR(8450), split.Tenders[1].Change)
+	assert.Equal(t, payment.StatusPending, split.Tenders[2].Status)
+	assert.Equal(t, balance-50, points())
+	code, _ = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": order.OrderID, "tenders": []map[string]interface{}{
+		{"method": "cash", "amount": "116550"},
+	}}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code, "the order is being paid")
+
+	webhook, err := gateway.Settle(split.Tenders[2].ChargeID, payment.StatusCaptured)
+	require.NoError(t, err)
+	notify := func(body []byte) (int, web.WebResponse) {
+		return testApp.request(http.MethodPost, "/payments/webhooks/fake", json.RawMessage(body),
+			payment.HeaderTimestamp, webhook.Header[payment.HeaderTimestamp],
+			payment.HeaderSignature, webhook.Header[payment.HeaderSignature],
+			"X-API-Key", "")
+	}
+	code, response = notify([]byte(strings.Replace(string(webhook.Body), "captured", "declined", 1)))
+	assert.Equal(t, http.StatusUnauthorized, code, "the body is signed")
+	code, response = notify(webhook.Body)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var notification web.PaymentNotificationResponse
+	dataAs(t, response, &notification)
+	assert.Equal(t, split.PaymentID, notification.PaymentID)
+	assert.False(t, notification.Duplicate)
+	code, response = notify(webhook.Body)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &notification)
+	assert.True(t, notification.Duplicate, "a redelivery is not processed again")
+
+	code, response = testApp.request(http.MethodGet, "/api/payments/"+split.PaymentID, nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &split)
+	assert.Equal(t, domain.PaymentPaid, split.Status)
+	assert.Equal(t, payment.StatusCaptured, split.Tenders[2].Status)
+
+	// The history of the customer holds the failed and the paid payments
+	code, response = testApp.request(http.MethodGet, "/api/payments/?customer_id=C001", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var history []web.PaymentResponse
+	dataAs(t, response, &history)
+	require.Len(t, history, 2)
+	assert.ElementsMatch(t, []string{domain.PaymentPaid, domain.PaymentFailed}, []string{history[0].Status, history[1].Status})
+
+	// A charge that times out is retried with the same key
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 1}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	dataAs(t, response, &order)
+	code, response = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": order.OrderID, "tenders": []map[string]interface{}{
+		{"method": "card", "amount": order.Total, "token": payment.TokenTimeout},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var retried web.PaymentResponse
+	dataAs(t, response, &retried)
+	assert.Equal(t, domain.PaymentPaid, retried.Status)
+	code, response = testApp.request(http.MethodPost, "/api/payments/"+retried.PaymentID+"/sync", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+
+	code, _ = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": order.OrderID, "tenders": []map[string]interface{}{
+		{"method": "points", "points": 10},
+	}}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code, "an order without customer cannot be paid with points")
+	bandung := testApp.issueKey("kasir-bdg", auth.StorePermission("BDG01"))
+	code, _ = testApp.request(http.MethodGet, "/api/payments/"+retried.PaymentID, nil, "X-API-Key", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestActivePayments(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	order := func(orderId string) *domain.Order {
+		return &domain.Order{OrderID: orderId, StoreID: "JKT01", EmployeeID: "E001", Currency: "IDR", Subtotal: money.IDR(10000), Total: money.IDR(10000), CreatedAt: created}
+	}
+	legacy := func(paymentId string, orderId string, status string, minutes int) *domain.Payment {
+		return &domain.Payment{PaymentID: paymentId, OrderID: orderId, StoreID: "JKT01", Amount: money.IDR(10000), Status: status, CreatedAt: created.Add(time.Duration(minutes) * time.Minute)}
+	}
+	// Payments recorded before the active order, O1 was paid twice by concurrent requests
+	testApp.seed(order("O1"), order("O2"),
+		legacy("PAY1", "O1", domain.PaymentFailed, 1),
+		legacy("PAY2", "O1", domain.PaymentPaid, 2),
+		legacy("PAY3", "O1", domain.PaymentPending, 3),
+		legacy("PAY4", "O2", domain.PaymentFailed, 1),
+	)
+	require.NoError(t, testApp.application.Migrate())
+	require.NoError(t, testApp.application.Migrate())
+
+	var payments []domain.Payment
+	require.NoError(t, testApp.db.Order("payment_id").Find(&payments).Error)
+	active := map[string]string{}
+	for _, payment := range payments {
+		if payment.ActiveOrderID != nil {
+			active[payment.PaymentID] = *payment.ActiveOrderID
+		}
+	}
+	assert.Equal(t, map[string]string{"PAY2": "O1"}, active)
+
+	// A failed payment does not keep the order from being paid, the unique index keeps a
+	// second payment that did not fail out
+	code, response := testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": "O2", "tenders": []map[string]interface{}{
+		{"method": "cash", "amount": "10000"},
+	}})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	second := legacy("PAY5", "O2", domain.PaymentPending, 5)
+	second.ActiveOrderID = &second.OrderID
+	assert.Error(t, testApp.db.Create(second).Error)
+	code, _ = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": "O1", "tenders": []map[string]interface{}{
+		{"method": "cash", "amount": "10000"},
+	}})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
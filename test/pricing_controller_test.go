@@ -0,0 +1,135 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionCRUD(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, response := testApp.request(http.MethodPost, "/api/promotions", map[string]interface{}{
+		"name": "Diskon Elektronik", "type": "percentage", "value": 10, "categories": []string{"Electronics"},
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var promotion web.PromotionResponse
+	dataAs(t, response, &promotion)
+	assert.NotEmpty(t, promotion.PromotionID)
+	assert.True(t, promotion.Active)
+
+	code, response = testApp.request(http.MethodPut, "/api/promotions/"+promotion.PromotionID, map[string]interface{}{
+		"name": "Diskon Elektronik", "type": "percentage", "value": 15, "categories": []string{"Electronics"}, "active": false,
+	})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &promotion)
+	assert.Equal(t, 15.0, promotion.Value)
+	assert.False(t, promotion.Active)
+
+	code, response = testApp.request(http.MethodGet, "/api/promotions", nil)
+	require.Equal(t, http.StatusOK, code)
+	var promotions []web.PromotionResponse
+	dataAs(t, response, &promotions)
+	assert.Len(t, promotions, 1)
+
+	code, _ = testApp.request(http.MethodPost, "/api/promotions", map[string]interface{}{
+		"name": "Salah", "type": "percentage", "value": 150,
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodPost, "/api/promotions", map[string]interface{}{
+		"name": "Salah", "type": "buy_x_get_y",
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, response = testApp.request(http.MethodPost, "/api/promotions", map[string]interface{}{
+		"name": "Potongan 5rb", "type": "fixed_amount", "amount": "5000",
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var fixed web.PromotionResponse
+	dataAs(t, response, &fixed)
+	require.NotNil(t, fixed.Amount)
+	assert.Equal(t, money.IDR(5000), *fixed.Amount)
+	assert.Equal(t, 5000.0, fixed.Value)
+
+	code, _ = testApp.request(http.MethodDelete, "/api/promotions/"+promotion.PromotionID, nil)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = testApp.request(http.MethodGet, "/api/promotions/"+promotion.PromotionID, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestMigratePromotionAmounts(t *testing.T) {
+	testApp := setupTestApp(t)
+	// Promotions created when the amount was the value
+	testApp.seed(&[]domain.Promotion{
+		{PromotionID: "PR1", Name: "Potongan 5rb", Type: domain.PromotionFixedAmount, Value: 5000, Active: true},
+		{PromotionID: "PR2", Name: "2 Harga 60rb", Type: domain.PromotionBundlePrice, Value: 60000, BuyQty: 2, Active: true},
+		{PromotionID: "PR3", Name: "Diskon 10%", Type: domain.PromotionPercentage, Value: 10, Active: true},
+	})
+	require.NoError(t, testApp.application.Migrate())
+	require.NoError(t, testApp.application.Migrate())
+
+	var promotions []domain.Promotion
+	require.NoError(t, testApp.db.Order("promotion_id").Find(&promotions).Error)
+	require.Len(t, promotions, 3)
+	assert.Equal(t, money.IDR(5000), promotions[0].Amount)
+	assert.Equal(t, money.IDR(60000), promotions[1].Amount)
+	assert.Zero(t, promotions[1].Value)
+	assert.Equal(t, 10.0, promotions[2].Value)
+	assert.True(t, promotions[2].Amount.IsZero())
+}
+
+func TestPricingQuote(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	yesterday, tomorrow := time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour)
+	testApp.seed(&[]domain.Promotion{
+		{PromotionID: "PR1", Name: "Diskon Elektronik", Type: domain.PromotionPercentage, Value: 10, Categories: []string{"Electronics"}, StartsAt: &yesterday, EndsAt: &tomorrow, Active: true},
+		{PromotionID: "PR2", Name: "Beli 2 Gratis 1", Type: domain.PromotionBuyXGetY, BuyQty: 2, GetQty: 1, ProductIDs: []string{"P002"}, Active: true},
+		{PromotionID: "PR3", Name: "Member Sari", Type: domain.PromotionPercentage, Value: 50, CustomerIDs: []string{"C002"}, Priority: 9, Active: true},
+		{PromotionID: "PR4", Name: "Habis", Type: domain.PromotionPercentage, Value: 50, UsageLimit: 1, UsageCount: 1, Priority: 9, Active: true},
+		{PromotionID: "PR5", Name: "Kemarin", Type: domain.PromotionPercentage, Value: 50, EndsAt: &yesterday, Priority: 9, Active: true},
+	})
+
+	code, response := testApp.request(http.MethodPost, "/api/pricing/quote", map[string]interface{}{
+		"customer_id": "C001",
+		"lines": []map[string]interface{}{
+			{"product_id": "P001", "quantity": 1},
+			{"product_id": "P002", "quantity": 3},
+		},
+	})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var quote web.QuoteResponse
+	dataAs(t, response, &quote)
+	require.Len(t, quote.Lines, 2)
+	assert.Equal(t, money.IDR(1500000), quote.Lines[0].Discount)
+	assert.Equal(t, money.IDR(35000), quote.Lines[1].Discount)
+	assert.Equal(t, money.IDR(15105000), quote.Subtotal)
+	assert.Equal(t, money.IDR(1535000), quote.Discount)
+	// 11% of what is left after the discounts
+	assert.Equal(t, money.IDR(1492700), quote.Tax)
+	assert.Equal(t, money.IDR(15062700), quote.Total)
+	assert.Equal(t, "IDR", quote.Currency)
+	require.Len(t, quote.Promotions, 2)
+	assert.Equal(t, "PR1", quote.Promotions[0].PromotionID)
+	assert.Equal(t, "PR2", quote.Promotions[1].PromotionID)
+
+	code, _ = testApp.request(http.MethodPost, "/api/pricing/quote", map[string]interface{}{
+		"customer_id": "C404",
+		"lines":       []map[string]interface{}{{"product_id": "P001", "quantity": 1}},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodPost, "/api/pricing/quote", map[string]interface{}{
+		"lines": []map[string]interface{}{{"product_id": "P404", "quantity": 1}},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodPost, "/api/pricing/quote", map[string]interface{}{
+		"lines": []map[string]interface{}{},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,230 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductListAndGet(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	code, response := testApp.request(http.MethodGet, "/api/products/", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var products []web.ProductResponse
+	dataAs(t, response, &products)
+	require.Len(t, products, 2)
+
+	code, response = testApp.request(http.MethodGet, "/api/products/P002", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var product web.ProductResponse
+	dataAs(t, response, &product)
+	assert.Equal(t, web.ProductResponse{
+		ProductID: "P002", Name: "Kopi Bubuk", Description: "Kopi robusta 250g", Price: 35000, PriceAmount: money.IDR(35000), Currency: "IDR", StockQty: 200,
+		Category: "Food", SKU: "KOP250", TaxClass: "standard", TaxRate: 11,
+	}, product)
+}
+
+func TestProductCurrency(t *testing.T) {
+	testApp := setupTestApp(t)
+	override := money.New(1450, "USD")
+	testApp.seed(&domain.Product{
+		ProductID: "P-USD", Name: "Imported Tea", Price: money.New(1250, "USD"), Category: "Food", SKU: "TEA-USD", TaxClassID: "standard", TaxRate: 11,
+		Options:  []domain.ProductOption{{Name: "size"}},
+		Variants: []domain.ProductVariant{{VariantID: "V-USD", SKU: "TEA-USD-L", PriceOverride: &override, Options: []domain.ProductVariantOption{{Name: "size", Value: "L"}}}},
+	})
+
+	code, response := testApp.request(http.MethodGet, "/api/products/P-USD", nil)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	// the amounts are decimal strings in the currency of the product
+	var product struct {
+		PriceAmount string `json:"price_amount"`
+		Currency    string `json:"currency"`
+		Variants    []struct {
+			PriceAmount string `json:"price_amount"`
+		} `json:"variants"`
+	}
+	dataAs(t, response, &product)
+	assert.Equal(t, "USD", product.Currency)
+	assert.Equal(t, "12.50", product.PriceAmount)
+	require.Len(t, product.Variants, 1)
+	assert.Equal(t, "14.50", product.Variants[0].PriceAmount)
+
+	// twice, the second read comes from the cache
+	code, response = testApp.request(http.MethodGet, "/api/products/P-USD", nil)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &product)
+	assert.Equal(t, "12.50", product.PriceAmount)
+}
+
+func TestProductLifecycle(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, response := testApp.request(http.MethodPost, "/api/products/", web.ProductCreateRequest{
+		Name: "Teh Melati", Description: "Teh celup isi 25", Price: money.IDR(8500), StockQty: 50, Category: "Food", SKU: "TEH025", TaxRate: 11,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created web.ProductResponse
+	dataAs(t, response, &created)
+	require.NotEmpty(t, created.ProductID)
+
+	url := "/api/products/" + created.ProductID
+	code, response = testApp.request(http.MethodPut, url, web.ProductUpdateRequest{
+		ProductID: created.ProductID, Name: "Teh Melati", Description: "Teh celup isi 25", Price: money.IDR(9000), StockQty: 45,
+		Category: "Food", SKU: "TEH025", TaxRate: 11,
+	})
+	assert.Equal(t, http.StatusOK, code)
+
+	code, response = testApp.request(http.MethodGet, url, nil)
+	assert.Equal(t, http.StatusOK, code)
+	var updated web.ProductResponse
+	dataAs(t, response, &updated)
+	assert.Equal(t, 9000.0, updated.Price)
+	assert.Equal(t, money.IDR(9000), updated.PriceAmount)
+	assert.Equal(t, "IDR", updated.Currency)
+	assert.Equal(t, 45, updated.StockQty)
+
+	code, _ = testApp.request(http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = testApp.request(http.MethodGet, url, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestProductErrors(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         interface{}
+		expectedCode int
+	}{
+		{name: "get missing", method: http.MethodGet, url: "/api/products/P999", expectedCode: http.StatusNotFound},
+		{name: "delete missing", method: http.MethodDelete, url: "/api/products/P999", expectedCode: http.StatusNotFound},
+		{
+			name: "create with negative price", method: http.MethodPost, url: "/api/products/",
+			body:         map[string]interface{}{"name": "X", "price": -1, "stock_qty": 1, "category": "Food", "sku": "X1"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "create with a fraction of a rupiah", method: http.MethodPost, url: "/api/products/",
+			body:         map[string]interface{}{"name": "X", "price": "8500.50", "stock_qty": 1, "category": "Food", "sku": "X1"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := testApp.request(tt.method, tt.url, tt.body)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
+
+func TestProductVariants(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	override := money.IDR(99000)
+	variant := func(sku string, size string, colour string, stock int) web.ProductVariantRequest {
+		return web.ProductVariantRequest{SKU: sku, StockQty: stock, Options: map[string]string{"size": size, "colour": colour}}
+	}
+	request := web.ProductCreateRequest{
+		Name: "Kaos Polos", Price: money.IDR(89000), Category: "Apparel", SKU: "KAOS", TaxRate: 11,
+		Options: []string{"size", "colour"},
+		Variants: []web.ProductVariantRequest{
+			variant("KAOS-M-MRH", "M", "Merah", 5),
+			variant("KAOS-L-MRH", "L", "Merah", 3),
+			variant("KAOS-M-HTM", "M", "Hitam", 4),
+		},
+	}
+	request.Variants[1].PriceOverride = &override
+	code, response := testApp.request(http.MethodPost, "/api/products/", request)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var kaos web.ProductResponse
+	dataAs(t, response, &kaos)
+	assert.Equal(t, []string{"size", "colour"}, kaos.Options)
+	assert.Equal(t, 12, kaos.StockQty)
+	require.Len(t, kaos.Variants, 3)
+	assert.Equal(t, 89000.0, kaos.Variants[0].Price)
+	assert.Equal(t, 99000.0, kaos.Variants[1].Price)
+	assert.Equal(t, override, kaos.Variants[1].PriceAmount)
+	assert.Equal(t, map[string]string{"size": "L", "colour": "Merah"}, kaos.Variants[1].Options)
+
+	code, response = testApp.request(http.MethodGet, "/api/products/?options=size:M,colour:Merah", nil)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var products []web.ProductResponse
+	dataAs(t, response, &products)
+	require.Len(t, products, 1)
+	require.Len(t, products[0].Variants, 1)
+	assert.Equal(t, "KAOS-M-MRH", products[0].Variants[0].SKU)
+
+	code, response = testApp.request(http.MethodGet, "/api/products/?options=size:M", nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &products)
+	require.Len(t, products, 1)
+	assert.Len(t, products[0].Variants, 2)
+
+	code, response = testApp.request(http.MethodGet, "/api/products/?options=size:XL", nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &products)
+	assert.Empty(t, products)
+	code, _ = testApp.request(http.MethodGet, "/api/products/?options=size", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Missing option value, duplicated combination and a SKU taken by another product
+	invalid := request
+	invalid.SKU = "KAOS2"
+	invalid.Variants = []web.ProductVariantRequest{{SKU: "KAOS2-M", Options: map[string]string{"size": "M"}}}
+	code, _ = testApp.request(http.MethodPost, "/api/products/", invalid)
+	assert.Equal(t, http.StatusBadRequest, code)
+	invalid.Variants = []web.ProductVariantRequest{variant("KAOS2-M-1", "M", "Merah", 1), variant("KAOS2-M-2", "M", "Merah", 1)}
+	code, _ = testApp.request(http.MethodPost, "/api/products/", invalid)
+	assert.Equal(t, http.StatusBadRequest, code)
+	invalid.Variants = []web.ProductVariantRequest{variant("KAOS-M-MRH", "M", "Merah", 1)}
+	code, _ = testApp.request(http.MethodPost, "/api/products/", invalid)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// The variants given on update replace the others, an existing id keeps the variant
+	kept := variant("KAOS-M-MRH", "M", "Merah", 8)
+	kept.VariantID = kaos.Variants[0].VariantID
+	code, response = testApp.request(http.MethodPut, "/api/products/"+kaos.ProductID, web.ProductUpdateRequest{
+		Name: "Kaos Polos", Price: money.IDR(79000), Category: "Apparel", SKU: "KAOS", TaxRate: 11,
+		Options:  []string{"size", "colour"},
+		Variants: []web.ProductVariantRequest{kept, variant("KAOS-S-MRH", "S", "Merah", 2)},
+	})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &kaos)
+	require.Len(t, kaos.Variants, 2)
+	assert.Equal(t, kept.VariantID, kaos.Variants[0].VariantID)
+	assert.Equal(t, 79000.0, kaos.Variants[1].Price)
+	assert.Equal(t, 10, kaos.StockQty)
+
+	// Without variants the update keeps them
+	code, response = testApp.request(http.MethodPut, "/api/products/"+kaos.ProductID, web.ProductUpdateRequest{
+		Name: "Kaos Polos Katun", Price: money.IDR(79000), StockQty: 1, Category: "Apparel", SKU: "KAOS", TaxRate: 11,
+	})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	code, response = testApp.request(http.MethodGet, "/api/products/"+kaos.ProductID, nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &kaos)
+	assert.Equal(t, "Kaos Polos Katun", kaos.Name)
+	assert.Len(t, kaos.Variants, 2)
+	assert.Equal(t, 10, kaos.StockQty)
+
+	code, _ = testApp.request(http.MethodDelete, "/api/products/"+kaos.ProductID, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, response = testApp.request(http.MethodGet, "/api/products/?options=size:M", nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &products)
+	assert.Empty(t, products)
+}
@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReturn(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.seed(&domain.Employee{EmployeeID: "E002", Name: "Rina Hartono", Role: "supervisor", Email: "rina@example.com", Phone: "081233334444", DateHired: "2021-06-01"})
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	for productId, quantity := range map[string]int{"P001": 5, "P002": 10} {
+		code, response := testApp.request(http.MethodPut, "/api/stores/JKT01/stock/"+productId, map[string]interface{}{"stock_qty": quantity})
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	}
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	supervisor := testApp.issueKey("rina-jkt", auth.StorePermission("JKT01"), auth.EmployeePermission("E002"))
+	gateway, ok := testApp.application.PaymentProvider.(*payment.Fake)
+	require.True(t, ok)
+
+	stock := func(productId string) int {
+		code, response := testApp.request(http.MethodGet, "/api/stores/JKT01/stock", nil)
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+		var stocks []web.StoreStockResponse
+		dataAs(t, response, &stocks)
+		for _, stock := range stocks {
+			if stock.ProductID == productId {
+				return stock.StockQty
+			}
+		}
+		return 0
+	}
+	points := func() int {
+		code, response := testApp.request(http.MethodGet, "/api/customers/C001", nil)
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+		var customer web.CustomerResponse
+		dataAs(t, response, &customer)
+		return customer.LoyaltyPts
+	}
+	createReturn := func(body map[string]interface{}) (int, web.ReturnResponse, web.WebResponse) {
+		code, response := testApp.request(http.MethodPost, "/api/returns/", body, "X-API-Key", cashier)
+		var orderReturn web.ReturnResponse
+		if code == http.StatusCreated {
+			dataAs(t, response, &orderReturn)
+		}
+		return code, orderReturn, response
+	}
+
+	// 3 Kopi paid with points, cash and a card earn the customer 11 points
+	code, response := testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"customer_id": "C001",
+		"lines":       []map[string]interface{}{{"product_id": "P002", "quantity": 3}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var order web.OrderResponse
+	dataAs(t, response, &order)
+	require.Equal(t, money.IDR(116550), order.Total)
+	code, response = testApp.request(http.MethodPost, "/api/payments/", map[string]interface{}{"order_id": order.OrderID, "tenders": []map[string]interface{}{
+		{"method": "points", "points": 50},
+		{"method": "cash", "amount": "11550"},
+		{"method": "card", "amount": "100000", "token": payment.TokenSuccess},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var paid web.PaymentResponse
+	dataAs(t, response, &paid)
+	require.Equal(t, domain.PaymentPaid, paid.Status)
+	require.Equal(t, 120+11-50, points())
+	require.Equal(t, 7, stock("P002"))
+
+	// One damaged Kopi goes back on the shelf, its share is refunded on the card
+	code, kopi, response := createReturn(map[string]interface{}{"order_id": order.OrderID, "employee_id": "E001", "lines": []map[string]interface{}{
+		{"position": 1, "quantity": 1, "reason": "damaged", "disposition": "restock"},
+	}})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	assert.Equal(t, domain.ReturnCompleted, kopi.Status)
+	assert.Equal(t, money.IDR(38850), kopi.Refund)
+	assert.Equal(t, money.IDR(3850), kopi.Tax)
+	assert.Equal(t, []web.ReturnRefundResponse{{Method: domain.TenderCard, Amount: money.IDR(38850), ChargeID: paid.Tenders[2].ChargeID}}, kopi.Refunds)
+	assert.Equal(t, 4, kopi.PointsReversed, "77700 kept earns 7 of the 11 points")
+	assert.Equal(t, 120+7-50, points())
+	assert.Equal(t, 8, stock("P002"))
+	charge, err := gateway.Status(context.Background(), paid.Tenders[2].ChargeID)
+	require.NoError(t, err)
+	assert.Equal(t, money.IDR(38850), charge.Refunded)
+	code, response = testApp.request(http.MethodGet, "/api/payments/"+paid.PaymentID, nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &paid)
+	assert.Equal(t, money.IDR(38850), paid.Tenders[2].Refunded)
+
+	code, _, response = createReturn(map[string]interface{}{"order_id": order.OrderID, "employee_id": "E001", "lines": []map[string]interface{}{
+		{"position": 1, "quantity": 3, "reason": "changed_mind", "disposition": "restock"},
+	}})
+	assert.Equal(t, http.StatusBadRequest, code, "only 2 Kopi are left to return")
+	assert.Contains(t, response.Data, "2 of the 3 sold are left")
+
+	// Laptops refund more than the threshold and wait for a supervisor
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"lines":       []map[string]interface{}{{"product_id": "P001", "quantity": 2}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	dataAs(t, response, &order)
+	laptop := map[string]interface{}{"order_id": order.OrderID, "employee_id": "E001", "lines": []map[string]interface{}{
+		{"position": 1, "quantity": 1, "reason": "defective", "disposition": "restock"},
+	}}
+	code, approved, response := createReturn(laptop)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	assert.Equal(t, domain.ReturnPending, approved.Status)
+	assert.Empty(t, approved.Refunds)
+	assert.Equal(t, 3, stock("P001"), "nothing is restocked before the approval")
+
+	code, _ = testApp.request(http.MethodPost, "/api/returns/"+approved.ReturnID+"/approve", map[string]interface{}{"employee_id": "E002"}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusForbidden, code, "the key of a cashier does not approve for a supervisor")
+	code, response = testApp.request(http.MethodPost, "/api/returns/"+approved.ReturnID+"/approve", map[string]interface{}{}, "X-API-Key", supervisor)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &approved)
+	assert.Equal(t, domain.ReturnCompleted, approved.Status)
+	assert.Equal(t, "E002", approved.ApprovedBy)
+	assert.Equal(t, []web.ReturnRefundResponse{{Method: domain.TenderCash, Amount: money.IDR(16650000)}}, approved.Refunds, "an unpaid order is refunded in cash")
+	assert.Equal(t, 4, stock("P001"))
+
+	code, rejected, response := createReturn(laptop)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	code, response = testApp.request(http.MethodPost, "/api/returns/"+rejected.ReturnID+"/reject", map[string]interface{}{"note": "tidak ada kerusakan"}, "X-API-Key", supervisor)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &rejected)
+	assert.Equal(t, domain.ReturnRejected, rejected.Status)
+	assert.Equal(t, "tidak ada kerusakan", rejected.Note)
+	assert.Equal(t, 4, stock("P001"))
+	code, _ = testApp.request(http.MethodPost, "/api/returns/"+rejected.ReturnID+"/approve", map[string]interface{}{}, "X-API-Key", supervisor)
+	assert.Equal(t, http.StatusBadRequest, code, "a rejected return is final")
+
+	code, response = testApp.request(http.MethodGet, "/api/returns/?status=completed", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var returns []web.ReturnResponse
+	dataAs(t, response, &returns)
+	require.Len(t, returns, 2)
+	assert.ElementsMatch(t, []string{kopi.ReturnID, approved.ReturnID}, []string{returns[0].ReturnID, returns[1].ReturnID})
+	code, _ = testApp.request(http.MethodGet, "/api/returns/?status=lost", nil, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code)
+	bandung := testApp.issueKey("kasir-bdg", auth.StorePermission("BDG01"))
+	code, _ = testApp.request(http.MethodGet, "/api/returns/"+kopi.ReturnID, nil, "X-API-Key", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+}
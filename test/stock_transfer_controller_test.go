@@ -0,0 +1,230 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockTransferFlow(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	testApp.createStore("BDG01", "Bandung Dago")
+
+	code, response := testApp.request(http.MethodPost, "/api/transfers/", map[string]interface{}{
+		"destination_store_id": "JKT01",
+		"lines": []map[string]interface{}{
+			{"product_id": "P001", "quantity": 2},
+			{"product_id": "P002", "quantity": 20},
+		},
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var transfer web.StockTransferResponse
+	dataAs(t, response, &transfer)
+	assert.Equal(t, domain.TransferDraft, transfer.Status)
+
+	code, response = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/dispatch", nil)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &transfer)
+	assert.Equal(t, domain.TransferDispatched, transfer.Status)
+
+	// In transit, neither the central stock nor the store has it
+	code, response = testApp.request(http.MethodGet, "/api/products/P002", nil)
+	require.Equal(t, http.StatusOK, code)
+	var product web.ProductResponse
+	dataAs(t, response, &product)
+	assert.Equal(t, 180, product.StockQty)
+
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	code, response = testApp.request(http.MethodGet, "/api/transfers/?status=open", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var open []web.StockTransferResponse
+	dataAs(t, response, &open)
+	require.Len(t, open, 1)
+	assert.Equal(t, transfer.TransferID, open[0].TransferID)
+
+	bandung := testApp.issueKey("kasir-bdg", auth.StorePermission("BDG01"))
+	code, response = testApp.request(http.MethodGet, "/api/transfers/?status=open", nil, "X-API-Key", bandung)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &open)
+	assert.Empty(t, open)
+	code, _ = testApp.request(http.MethodGet, "/api/transfers/"+transfer.TransferID, nil, "X-API-Key", bandung)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = testApp.request(http.MethodGet, "/api/transfers/?status=lost", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, response = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/receive", map[string]interface{}{
+		"lines": []map[string]interface{}{{"product_id": "P002", "quantity": 15}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &transfer)
+	assert.Equal(t, domain.TransferPartiallyReceived, transfer.Status)
+
+	code, _ = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/receive", map[string]interface{}{
+		"lines": []map[string]interface{}{{"product_id": "P002", "quantity": 6}},
+	}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, response = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/receive", map[string]interface{}{
+		"lines": []map[string]interface{}{
+			{"product_id": "P001", "quantity": 2},
+			{"product_id": "P002", "quantity": 0, "reason": "5 pack rusak"},
+		},
+		"complete": true,
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &transfer)
+	assert.Equal(t, domain.TransferReceived, transfer.Status)
+	require.Len(t, transfer.Lines, 2)
+	assert.Equal(t, 15, transfer.Lines[1].ReceivedQty)
+	assert.Equal(t, 5, transfer.Lines[1].DiscrepancyQty)
+	assert.Equal(t, "5 pack rusak", transfer.Lines[1].DiscrepancyReason)
+
+	code, response = testApp.request(http.MethodGet, "/api/products/P002", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &product)
+	assert.Equal(t, 15, product.StockQty)
+
+	code, response = testApp.request(http.MethodGet, "/api/transfers/?status=open", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &open)
+	assert.Empty(t, open)
+}
+
+func TestStockTransferFromStore(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	testApp.createStore("BDG01", "Bandung Dago")
+	code, _ := testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P002", map[string]interface{}{"stock_qty": 10})
+	require.Equal(t, http.StatusOK, code)
+
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	code, response := testApp.request(http.MethodPost, "/api/transfers/", map[string]interface{}{
+		"source_store_id":      "JKT01",
+		"destination_store_id": "BDG01",
+		"lines":                []map[string]interface{}{{"product_id": "P002", "quantity": 20}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var transfer web.StockTransferResponse
+	dataAs(t, response, &transfer)
+
+	// Only 10 in the store
+	code, _ = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/dispatch", nil, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, response = testApp.request(http.MethodPut, "/api/transfers/"+transfer.TransferID, map[string]interface{}{
+		"lines": []map[string]interface{}{{"product_id": "P002", "quantity": 10}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	code, _ = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/dispatch", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code)
+
+	code, response = testApp.request(http.MethodGet, "/api/stores/JKT01/stock", nil)
+	require.Equal(t, http.StatusOK, code)
+	var stock []web.StoreStockResponse
+	dataAs(t, response, &stock)
+	require.Len(t, stock, 1)
+	assert.Equal(t, 0, stock[0].StockQty)
+
+	// A dispatched transfer can no longer be cancelled
+	code, _ = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/cancel", nil, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestVariantStock(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+
+	code, response := testApp.request(http.MethodPost, "/api/products/", map[string]interface{}{
+		"name": "Kaos Polos", "price": 89000, "category": "Apparel", "sku": "KAOS", "tax_rate": 11,
+		"options": []string{"size"},
+		"variants": []map[string]interface{}{
+			{"sku": "KAOS-M", "stock_qty": 5, "options": map[string]string{"size": "M"}},
+			{"sku": "KAOS-L", "stock_qty": 3, "options": map[string]string{"size": "L"}},
+		},
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var kaos web.ProductResponse
+	dataAs(t, response, &kaos)
+	medium, large := kaos.Variants[0].VariantID, kaos.Variants[1].VariantID
+	product := func(key ...string) web.ProductResponse {
+		code, response := testApp.request(http.MethodGet, "/api/products/"+kaos.ProductID, nil, key...)
+		require.Equal(t, http.StatusOK, code, "%v", response.Data)
+		var product web.ProductResponse
+		dataAs(t, response, &product)
+		return product
+	}
+
+	// A product with variants moves by variant, the central stock of the variant goes with it
+	code, _ = testApp.request(http.MethodPost, "/api/transfers/", map[string]interface{}{
+		"destination_store_id": "JKT01",
+		"lines":                []map[string]interface{}{{"product_id": kaos.ProductID, "quantity": 2}},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, response = testApp.request(http.MethodPost, "/api/transfers/", map[string]interface{}{
+		"destination_store_id": "JKT01",
+		"lines":                []map[string]interface{}{{"product_id": kaos.ProductID, "variant_id": medium, "quantity": 2}},
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var transfer web.StockTransferResponse
+	dataAs(t, response, &transfer)
+	code, response = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/dispatch", nil)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	central := product()
+	assert.Equal(t, 6, central.StockQty)
+	assert.Equal(t, 3, central.Variants[0].StockQty)
+	code, response = testApp.request(http.MethodPost, "/api/transfers/"+transfer.TransferID+"/receive", map[string]interface{}{
+		"lines": []map[string]interface{}{{"product_id": kaos.ProductID, "variant_id": medium, "quantity": 2}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+
+	stored := product("X-API-Key", cashier)
+	assert.Equal(t, 2, stored.StockQty)
+	assert.Equal(t, 2, stored.Variants[0].StockQty)
+	assert.Equal(t, 0, stored.Variants[1].StockQty)
+
+	// The sale takes the variant sold, the store has no large one
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"lines":       []map[string]interface{}{{"product_id": kaos.ProductID, "variant_id": large, "quantity": 1}},
+	}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code, "%v", response.Data)
+	code, response = testApp.request(http.MethodPost, "/api/orders/", map[string]interface{}{
+		"employee_id": "E001",
+		"lines":       []map[string]interface{}{{"product_id": kaos.ProductID, "variant_id": medium, "quantity": 2}},
+	}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var order web.OrderResponse
+	dataAs(t, response, &order)
+	stored = product("X-API-Key", cashier)
+	assert.Equal(t, 0, stored.StockQty)
+	assert.Equal(t, 0, stored.Variants[0].StockQty)
+
+	// and the return puts it back
+	code, response = testApp.request(http.MethodPost, "/api/returns/", map[string]interface{}{"order_id": order.OrderID, "employee_id": "E001", "lines": []map[string]interface{}{
+		{"position": 1, "quantity": 1, "reason": "wrong_item", "disposition": "restock"},
+	}}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	stored = product("X-API-Key", cashier)
+	assert.Equal(t, 1, stored.StockQty)
+	assert.Equal(t, 1, stored.Variants[0].StockQty)
+
+	// The stock of a store is set per variant too
+	code, _ = testApp.request(http.MethodPut, "/api/stores/JKT01/stock/"+kaos.ProductID, map[string]interface{}{"stock_qty": 4}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, response = testApp.request(http.MethodPut, "/api/stores/JKT01/stock/"+kaos.ProductID, map[string]interface{}{"variant_id": large, "stock_qty": 4}, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var stock web.StoreStockResponse
+	dataAs(t, response, &stock)
+	assert.Equal(t, 5, stock.StockQty)
+	assert.ElementsMatch(t, []web.StoreVariantStockResponse{{VariantID: medium, StockQty: 1}, {VariantID: large, StockQty: 4}}, stock.Variants)
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/auth"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// issueKey creates an API key with the given permissions and returns it
+func (a *testApp) issueKey(name string, permissions ...string) string {
+	a.t.Helper()
+	issued, err := a.application.Services.APIKey.Create(context.Background(), web.APIKeyCreateRequest{Name: name, Permissions: permissions})
+	require.NoError(a.t, err)
+	return issued.Key
+}
+
+func (a *testApp) createStore(storeId string, name string) {
+	a.t.Helper()
+	code, response := a.request(http.MethodPost, "/api/stores/", web.StoreCreateRequest{StoreID: storeId, Name: name})
+	require.Equal(a.t, http.StatusCreated, code, "%v", response.Data)
+}
+
+func TestStoreScopedProducts(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	testApp.createStore("BDG01", "Bandung Dago")
+
+	override := 30000.0
+	code, response := testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P002", map[string]interface{}{"stock_qty": 25, "price_override": override})
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	code, _ = testApp.request(http.MethodPut, "/api/stores/JKT01/stock/P404", map[string]interface{}{"stock_qty": 1})
+	assert.Equal(t, http.StatusNotFound, code)
+
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+
+	// The key is bound to one store, it is the store context without any header
+	code, response = testApp.request(http.MethodGet, "/api/products/", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var products []web.ProductResponse
+	dataAs(t, response, &products)
+	require.Len(t, products, 2)
+	for _, product := range products {
+		assert.Equal(t, "JKT01", product.StoreID)
+		switch product.ProductID {
+		case "P001":
+			assert.Equal(t, 0, product.StockQty)
+			assert.Equal(t, 15000000.0, product.Price)
+		case "P002":
+			assert.Equal(t, 25, product.StockQty)
+			assert.Equal(t, override, product.Price)
+		}
+	}
+
+	code, response = testApp.request(http.MethodGet, "/api/products/P001", nil, "X-API-Key", cashier, "X-Store-ID", "JKT01")
+	require.Equal(t, http.StatusOK, code)
+	var laptop web.ProductResponse
+	dataAs(t, response, &laptop)
+	assert.Equal(t, 0, laptop.StockQty)
+
+	code, _ = testApp.request(http.MethodGet, "/api/products/", nil, "X-API-Key", cashier, "X-Store-ID", "BDG01")
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = testApp.request(http.MethodPut, "/api/stores/BDG01/stock/P002", map[string]interface{}{"stock_qty": 5}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = testApp.request(http.MethodPost, "/api/stores/", web.StoreCreateRequest{StoreID: "SBY01", Name: "Surabaya"}, "X-API-Key", cashier)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, response = testApp.request(http.MethodGet, "/api/stores/", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code)
+	var stores []web.StoreResponse
+	dataAs(t, response, &stores)
+	require.Len(t, stores, 1)
+	assert.Equal(t, "JKT01", stores[0].StoreID)
+
+	// A key bound to several stores has to pick one
+	regional := testApp.issueKey("regional", auth.StorePermission("JKT01"), auth.StorePermission("BDG01"))
+	code, _ = testApp.request(http.MethodGet, "/api/products/", nil, "X-API-Key", regional)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodGet, "/api/products/", nil, "X-API-Key", regional, "X-Store-ID", "BDG01")
+	assert.Equal(t, http.StatusOK, code)
+
+	// The cross-store permission keeps the central view
+	code, response = testApp.request(http.MethodGet, "/api/products/P002", nil)
+	require.Equal(t, http.StatusOK, code)
+	var central web.ProductResponse
+	dataAs(t, response, &central)
+	assert.Equal(t, 200, central.StockQty)
+	assert.Empty(t, central.StoreID)
+}
+
+func TestStoreScopedEmployees(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	testApp.createStore("BDG01", "Bandung Dago")
+
+	code, response := testApp.request(http.MethodPost, "/api/employees/", web.EmployeeCreateRequest{
+		Name: "Rina", Role: "supervisor", Email: "rina@example.com", Phone: "081233334444", DateHired: "2024-03-01", StoreIDs: []string{"JKT01", "BDG01"},
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var rina web.EmployeeResponse
+	dataAs(t, response, &rina)
+	assert.ElementsMatch(t, []string{"JKT01", "BDG01"}, rina.StoreIDs)
+
+	code, _ = testApp.request(http.MethodPut, "/api/employees/E001", web.EmployeeUpdateRequest{
+		Name: "Andi Wijaya", Role: "cashier", Email: "andi@example.com", Phone: "081211112222", DateHired: "2023-01-15", StoreIDs: []string{"SBY01"},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = testApp.request(http.MethodPut, "/api/employees/E001", web.EmployeeUpdateRequest{
+		Name: "Andi Wijaya", Role: "cashier", Email: "andi@example.com", Phone: "081211112222", DateHired: "2023-01-15", StoreIDs: []string{"BDG01"},
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	cashier := testApp.issueKey("kasir-jkt", auth.StorePermission("JKT01"))
+	code, response = testApp.request(http.MethodGet, "/api/employees/", nil, "X-API-Key", cashier)
+	require.Equal(t, http.StatusOK, code)
+	var employees []web.EmployeeResponse
+	dataAs(t, response, &employees)
+	require.Len(t, employees, 1)
+	assert.Equal(t, rina.EmployeeID, employees[0].EmployeeID)
+
+	code, response = testApp.request(http.MethodGet, "/api/employees/", nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &employees)
+	assert.Len(t, employees, 2)
+
+	// Deleting a store drops its assignments
+	code, _ = testApp.request(http.MethodDelete, "/api/stores/BDG01", nil)
+	require.Equal(t, http.StatusOK, code)
+	code, response = testApp.request(http.MethodGet, "/api/employees/"+rina.EmployeeID, nil)
+	require.Equal(t, http.StatusOK, code)
+	dataAs(t, response, &rina)
+	assert.Equal(t, []string{"JKT01"}, rina.StoreIDs)
+}
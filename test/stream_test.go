@@ -0,0 +1,252 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frame is one message of a Server-Sent Events stream, comments included
+type frame struct {
+	id        string
+	eventType string
+	data      string
+	comment   string
+}
+
+type streamClient struct {
+	t      *testing.T
+	frames chan frame
+}
+
+// listen serves the application on a real socket, fiber.App.Test cannot read an endless body
+func (a *testApp) listen() string {
+	a.t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(a.t, err)
+	go a.server.Listener(listener)
+	a.t.Cleanup(func() {
+		a.application.Stream.Close()
+		listener.Close()
+	})
+	return "http://" + listener.Addr().String()
+}
+
+func openStream(t *testing.T, url string, key string, lastEventId string) *streamClient {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", key)
+	if lastEventId != "" {
+		req.Header.Set("Last-Event-ID", lastEventId)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	client := &streamClient{t: t, frames: make(chan frame, 100)}
+	go func() {
+		defer resp.Body.Close()
+		defer close(client.frames)
+		scanner := bufio.NewScanner(resp.Body)
+		var current frame
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				client.frames <- current
+				current = frame{}
+			case strings.HasPrefix(line, ":"):
+				current.comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				current.id = line[len("id: "):]
+			case strings.HasPrefix(line, "event: "):
+				current.eventType = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				current.data = line[len("data: "):]
+			}
+		}
+	}()
+	return client
+}
+
+// next returns the next event, skipping the retry announcement and heartbeats
+func (client *streamClient) next() frame {
+	client.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case f, ok := <-client.frames:
+			require.True(client.t, ok, "stream closed")
+			if f.eventType != "" {
+				return f
+			}
+		case <-timeout:
+			client.t.Fatal("no event received")
+		}
+	}
+}
+
+func (client *streamClient) waitHeartbeat() {
+	client.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case f, ok := <-client.frames:
+			require.True(client.t, ok, "stream closed")
+			if f.comment == "heartbeat" {
+				return
+			}
+		case <-timeout:
+			client.t.Fatal("no heartbeat received")
+		}
+	}
+}
+
+func eventId(t *testing.T, f frame) uint64 {
+	t.Helper()
+	id, err := strconv.ParseUint(f.id, 10, 64)
+	require.NoError(t, err)
+	return id
+}
+
+func (a *testApp) updatePrice(productId string, price int64) {
+	a.t.Helper()
+	code, response := a.request(http.MethodGet, "/api/products/"+productId, nil)
+	require.Equal(a.t, http.StatusOK, code)
+	var product web.ProductUpdateRequest
+	dataAs(a.t, response, &product)
+	product.Price = money.IDR(price)
+	code, _ = a.request(http.MethodPut, "/api/products/"+productId, product)
+	require.Equal(a.t, http.StatusOK, code)
+}
+
+func TestStreamLiveEvents(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	baseURL := testApp.listen()
+
+	client := openStream(t, baseURL+"/api/stream?topics=products", dashboardKey, "")
+	// The subscription is registered before the handler returns the headers
+	require.Equal(t, 1, testApp.application.Stream.Subscribers())
+
+	code, _ := testApp.request(http.MethodPost, "/api/categories/", web.CategoryCreateRequest{Name: "Minuman"})
+	require.Equal(t, http.StatusCreated, code)
+	testApp.updatePrice("P002", 37500)
+	_, err := testApp.application.Relay.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	updated := client.next()
+	assert.Equal(t, event.ProductUpdated, updated.eventType)
+	var payload event.Event
+	require.NoError(t, json.Unmarshal([]byte(updated.data), &payload))
+	assert.Equal(t, updated.id, strconv.FormatUint(payload.Sequence, 10))
+	assert.Contains(t, string(payload.Data), `"price":37500`)
+
+	priceChanged := client.next()
+	assert.Equal(t, event.ProductPriceChanged, priceChanged.eventType)
+	assert.Greater(t, eventId(t, priceChanged), eventId(t, updated))
+
+	// The category event is not on the products topic
+	client.waitHeartbeat()
+}
+
+func TestStreamResume(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	baseURL := testApp.listen()
+
+	testApp.updatePrice("P001", 14000000)
+	testApp.updatePrice("P002", 36000)
+	_, err := testApp.application.Relay.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	first := openStream(t, baseURL+"/api/stream?topics=products", dashboardKey, "0")
+	var ids []string
+	for i := 0; i < 4; i++ {
+		ids = append(ids, first.next().id)
+	}
+
+	// Resuming after the second event replays the last two, then continues live
+	resumed := openStream(t, baseURL+"/api/stream?topics=products", dashboardKey, ids[1])
+	assert.Equal(t, ids[2], resumed.next().id)
+	assert.Equal(t, ids[3], resumed.next().id)
+
+	testApp.updatePrice("P001", 13500000)
+	_, err = testApp.application.Relay.RunOnce(context.Background())
+	require.NoError(t, err)
+	live := resumed.next()
+	assert.Equal(t, event.ProductUpdated, live.eventType)
+	assert.Greater(t, eventId(t, live), eventId(t, frame{id: ids[3]}))
+}
+
+func TestStreamResumeAfterLateCommit(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	_, err := testApp.application.Relay.RunOnce(context.Background())
+	require.NoError(t, err)
+	baseURL := testApp.listen()
+
+	// The transaction of the first event commits after the one of the second: the second event
+	// is published first, a client receives it and reconnects before the first one is published
+	testApp.updatePrice("P001", 14000000)
+	var written []domain.OutboxEvent
+	require.NoError(t, testApp.db.Where("published_at IS NULL").Order("id").Find(&written).Error)
+	require.Len(t, written, 2)
+	outbox := repository.NewOutboxRepository(testApp.db)
+	second, ok, err := outbox.MarkPublished(context.Background(), written[1].ID, time.Now())
+	require.NoError(t, err)
+	require.True(t, ok)
+	first, ok, err := outbox.MarkPublished(context.Background(), written[0].ID, time.Now())
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Greater(t, first, second)
+
+	resumed := openStream(t, baseURL+"/api/stream?topics=products", dashboardKey, strconv.FormatUint(second, 10))
+	replayed := resumed.next()
+	assert.Equal(t, strconv.FormatUint(first, 10), replayed.id)
+	assert.Equal(t, written[0].Type, replayed.eventType)
+}
+
+func TestStreamAuthorization(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	tests := []struct {
+		name     string
+		url      string
+		key      string
+		headers  []string
+		expected int
+	}{
+		{name: "topic not granted", url: "/api/stream?topics=products,customers", key: dashboardKey, expected: http.StatusForbidden},
+		{name: "unknown topic", url: "/api/stream?topics=weather", key: apiKey, expected: http.StatusBadRequest},
+		{name: "missing topics", url: "/api/stream", key: apiKey, expected: http.StatusBadRequest},
+		{name: "invalid last event id", url: "/api/stream?topics=products", key: apiKey, headers: []string{"Last-Event-ID", "kemarin"}, expected: http.StatusBadRequest},
+		{name: "unknown key", url: "/api/stream?topics=products", key: "SALAH", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := testApp.request(http.MethodGet, tt.url, nil, append([]string{"X-API-Key", tt.key}, tt.headers...)...)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
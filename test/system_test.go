@@ -0,0 +1,146 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/health"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnauthorized(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	for _, url := range []string{"/api/categories/", "/api/customers/", "/api/employees/", "/api/products/"} {
+		t.Run(url, func(t *testing.T) {
+			code, response := testApp.request(http.MethodGet, url, nil, "X-API-Key", "SALAH")
+			assert.Equal(t, http.StatusUnauthorized, code)
+			assert.Equal(t, "UNAUTHORIZED", response.Status)
+		})
+	}
+}
+
+func TestIssuedAPIKey(t *testing.T) {
+	testApp := setupTestApp(t)
+	apiKeys := testApp.application.Services.APIKey
+
+	issued, err := apiKeys.Create(context.Background(), web.APIKeyCreateRequest{Name: "ops", Permissions: []string{"*"}})
+	require.NoError(t, err)
+
+	code, _ := testApp.request(http.MethodGet, "/api/categories/", nil, "X-API-Key", issued.Key)
+	assert.Equal(t, http.StatusOK, code)
+
+	_, err = apiKeys.Revoke(context.Background(), issued.KeyID)
+	require.NoError(t, err)
+	code, _ = testApp.request(http.MethodGet, "/api/categories/", nil, "X-API-Key", issued.Key)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, response := testApp.request(http.MethodGet, "/healthz", nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusUp, response.Status)
+
+	code, response = testApp.request(http.MethodGet, "/readyz", nil)
+	assert.Equal(t, http.StatusOK, code)
+	var report health.Report
+	dataAs(t, response, &report)
+	assert.Equal(t, health.StatusUp, report.Status)
+	assert.NotEmpty(t, report.Checks)
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	resp := testApp.send(httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var document map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&document))
+	paths, _ := document["paths"].(map[string]interface{})
+	for _, path := range []string{"/api/categories/{categoryId}", "/api/customers/{customerId}", "/api/employees/{employeeId}", "/api/products/{productId}"} {
+		assert.Contains(t, paths, path)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.request(http.MethodGet, "/api/products/", nil)
+
+	resp := testApp.send(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/api/products/",status="200"} 1`)
+	assert.Contains(t, string(body), "gorm_query_duration_seconds")
+}
+
+func TestInventoryMetrics(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	testApp.createStore("JKT01", "Jakarta Pusat")
+	testApp.createStore("BDG01", "Bandung Dago")
+	testApp.seed(&[]domain.StoreStock{
+		{StoreID: "JKT01", ProductID: "P001", StockQty: 2},
+		{StoreID: "JKT01", ProductID: "P002", StockQty: 3},
+		{StoreID: "BDG01", ProductID: "P002", StockQty: 40},
+	})
+	// The harness opens its own database, the collector NewInstrumentedDB registers is added here
+	testApp.application.Metrics.Registry.MustRegister(metrics.NewInventoryCollector(testApp.db, 10))
+
+	resp := testApp.send(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	// The central stock of P001 and P002 is 10 and 200, at or above the level of 10
+	assert.Contains(t, string(body), "products_below_reorder_level 0")
+	assert.Contains(t, string(body), `store_products_below_reorder_level{store="JKT01"} 2`)
+	assert.Contains(t, string(body), `store_products_below_reorder_level{store="BDG01"} 0`)
+}
+
+func TestProductLookupsAreCached(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+
+	for i := 0; i < 3; i++ {
+		code, _ := testApp.request(http.MethodGet, "/api/products/P001", nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	// A write behind the API's back is not seen until the entry is invalidated by an API write
+	require.NoError(t, testApp.db.Model(&domain.Product{}).Where("product_id = ?", "P001").Update("name", "Laptop Bekas").Error)
+	_, response := testApp.request(http.MethodGet, "/api/products/P001", nil)
+	var product web.ProductResponse
+	dataAs(t, response, &product)
+	assert.Equal(t, "Laptop Gaming", product.Name)
+
+	code, _ := testApp.request(http.MethodPut, "/api/products/P001", web.ProductUpdateRequest{
+		ProductID: "P001", Name: "Laptop Gaming Pro", Price: money.IDR(16000000), StockQty: 8, Category: "Electronics", SKU: "LAP123", TaxRate: 11,
+	})
+	require.Equal(t, http.StatusOK, code)
+	_, response = testApp.request(http.MethodGet, "/api/products/P001", nil)
+	dataAs(t, response, &product)
+	assert.Equal(t, "Laptop Gaming Pro", product.Name)
+
+	resp := testApp.send(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `cache_requests_total{cache="products",result="hit"} 4`)
+	assert.Contains(t, string(body), `cache_requests_total{cache="products",result="miss"} 2`)
+}
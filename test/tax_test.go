@@ -0,0 +1,115 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/tax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxClasses(t *testing.T) {
+	testApp := setupTestApp(t)
+
+	code, response := testApp.request(http.MethodGet, "/api/tax-classes", nil)
+	require.Equal(t, http.StatusOK, code)
+	var classes []web.TaxClassResponse
+	dataAs(t, response, &classes)
+	require.Len(t, classes, 3)
+	assert.Equal(t, tax.ClassLuxury, classes[1].TaxClassID)
+	assert.Equal(t, 31.0, classes[1].Percent)
+
+	code, response = testApp.request(http.MethodPost, "/api/products", map[string]interface{}{
+		"name": "Jam Tangan", "price": 5000000, "stock_qty": 3, "category": "Aksesoris", "sku": "JAM-01", "tax_class": "luxury",
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	var product web.ProductResponse
+	dataAs(t, response, &product)
+	assert.Equal(t, tax.ClassLuxury, product.TaxClass)
+	assert.Equal(t, 31.0, product.TaxRate)
+
+	// A bare rate still works when a class has it, it is no longer taken as is
+	code, response = testApp.request(http.MethodPost, "/api/products", map[string]interface{}{
+		"name": "Beras", "price": 78500, "stock_qty": 3, "category": "Sembako", "sku": "BRS-5", "tax_rate": 0,
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+	dataAs(t, response, &product)
+	assert.Equal(t, tax.ClassExempt, product.TaxClass)
+	code, _ = testApp.request(http.MethodPost, "/api/products", map[string]interface{}{
+		"name": "Beras", "price": 78500, "stock_qty": 3, "category": "Sembako", "sku": "BRS-10", "tax_rate": 1100,
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestQuoteTaxInclusiveStore(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	code, response := testApp.request(http.MethodPost, "/api/stores", map[string]interface{}{
+		"store_id": "JKT01", "name": "Jakarta Pusat", "price_mode": "inclusive", "tax_rounding": "invoice",
+	})
+	require.Equal(t, http.StatusCreated, code, "%v", response.Data)
+
+	basket := map[string]interface{}{"lines": []map[string]interface{}{{"product_id": "P002", "quantity": 3}}}
+	code, response = testApp.request(http.MethodPost, "/api/pricing/quote", basket, "X-Store-ID", "JKT01")
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	var quote web.QuoteResponse
+	dataAs(t, response, &quote)
+	assert.Equal(t, "JKT01", quote.StoreID)
+	assert.Equal(t, tax.Inclusive, quote.PriceMode)
+	assert.Equal(t, money.IDR(105000), quote.Total)
+	// 11/111 of the price, rounded to the rupiah
+	assert.Equal(t, money.IDR(10405), quote.Tax)
+	require.Len(t, quote.Taxes, 1)
+	assert.Equal(t, "PPN", quote.Taxes[0].Code)
+	assert.Equal(t, money.IDR(94595), quote.Taxes[0].Base)
+
+	code, response = testApp.request(http.MethodPost, "/api/pricing/quote", basket)
+	require.Equal(t, http.StatusOK, code, "%v", response.Data)
+	dataAs(t, response, &quote)
+	assert.Equal(t, tax.Exclusive, quote.PriceMode)
+	assert.Equal(t, money.IDR(116550), quote.Total)
+	// Cash is paid to the closest Rp 100
+	assert.Equal(t, money.IDR(116600), quote.CashTotal)
+}
+
+func TestMigrateTaxRates(t *testing.T) {
+	testApp := setupTestApp(t)
+	// Products written before tax classes only had a rate
+	testApp.seed(&[]domain.Product{
+		{ProductID: "P1", Name: "Kopi", SKU: "P1", TaxRate: 11},
+		{ProductID: "P2", Name: "Beras", SKU: "P2", TaxRate: 0},
+		{ProductID: "P3", Name: "Rokok", SKU: "P3", TaxRate: 9.9},
+		// some clients sent fractions
+		{ProductID: "P4", Name: "Teh", SKU: "P4", TaxRate: 0.11},
+		{ProductID: "P5", Name: "Gula", SKU: "P5", TaxRate: 0.099},
+		{ProductID: "P6", Name: "Cerutu", SKU: "P6", TaxRate: 12.34567890123456},
+	})
+	require.NoError(t, testApp.application.Migrate())
+	require.NoError(t, testApp.application.Migrate())
+
+	var products []domain.Product
+	require.NoError(t, testApp.db.Order("product_id").Find(&products).Error)
+	classes := map[string]string{}
+	rates := map[string]float64{}
+	for _, product := range products {
+		classes[product.ProductID] = product.TaxClassID
+		rates[product.ProductID] = product.TaxRate
+	}
+	assert.Equal(t, map[string]string{
+		"P1": tax.ClassStandard, "P2": tax.ClassExempt, "P3": "ppn-9.9",
+		"P4": tax.ClassStandard, "P5": "ppn-9.9", "P6": "ppn-12.3456789012345",
+	}, classes)
+	assert.Equal(t, 11.0, rates["P4"])
+	assert.Equal(t, 9.9, rates["P5"])
+
+	var created domain.TaxClass
+	require.NoError(t, testApp.db.First(&created, "tax_class_id = ?", "ppn-9.9").Error)
+	assert.Equal(t, 9.9, created.Percent())
+	var long domain.TaxClass
+	require.NoError(t, testApp.db.First(&long, "tax_class_id = ?", "ppn-12.3456789012345").Error)
+	assert.Len(t, long.TaxClassID, 20)
+}
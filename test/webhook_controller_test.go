@@ -0,0 +1,178 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/money"
+	"github.com/aronipurwanto/go-restful-api/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver is a subscriber endpoint answering with status
+type webhookReceiver struct {
+	*httptest.Server
+	mu         sync.Mutex
+	status     int
+	deliveries []receivedDelivery
+}
+
+func newWebhookReceiver(t *testing.T, status int) *webhookReceiver {
+	receiver := &webhookReceiver{status: status}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.deliveries = append(receiver.deliveries, receivedDelivery{header: r.Header.Clone(), body: body})
+		w.WriteHeader(receiver.status)
+		w.Write([]byte("diterima"))
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (receiver *webhookReceiver) received() []receivedDelivery {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	return append([]receivedDelivery(nil), receiver.deliveries...)
+}
+
+// relayAndSend runs one pass of the background workers
+func (a *testApp) relayAndSend() {
+	a.t.Helper()
+	ctx := context.Background()
+	_, err := a.application.Relay.RunOnce(ctx)
+	require.NoError(a.t, err)
+	_, err = a.application.WebhookSender.RunOnce(ctx)
+	require.NoError(a.t, err)
+}
+
+func (a *testApp) createWebhook(url string, eventTypes ...string) web.WebhookResponse {
+	a.t.Helper()
+	code, response := a.request(http.MethodPost, "/api/webhooks/", web.WebhookCreateRequest{URL: url, EventTypes: eventTypes})
+	require.Equal(a.t, http.StatusCreated, code, "%v", response.Data)
+	var created web.WebhookResponse
+	dataAs(a.t, response, &created)
+	return created
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	testApp := setupTestApp(t)
+	testApp.seedFixtures()
+	receiver := newWebhookReceiver(t, http.StatusOK)
+
+	created := testApp.createWebhook(receiver.URL+"/hooks/pos", event.ProductPriceChanged, event.CustomerCreated)
+	require.NotEmpty(t, created.Secret)
+	assert.True(t, created.Active)
+
+	code, _ := testApp.request(http.MethodPut, "/api/products/P002", web.ProductUpdateRequest{
+		ProductID: "P002", Name: "Kopi Bubuk", Description: "Kopi robusta 250g", Price: money.IDR(37500), StockQty: 200, Category: "Food", SKU: "KOP250", TaxRate: 11,
+	})
+	require.Equal(t, http.StatusOK, code)
+	code, _ = testApp.request(http.MethodPost, "/api/employees/", web.EmployeeCreateRequest{
+		Name: "Dewi", Role: "cashier", Email: "dewi@example.com", Phone: "0813", DateHired: "2024-03-01",
+	})
+	require.Equal(t, http.StatusCreated, code)
+
+	testApp.relayAndSend()
+
+	received := receiver.received()
+	require.Len(t, received, 1, "only product.price_changed is subscribed")
+	delivery := received[0]
+	assert.Equal(t, event.ProductPriceChanged, delivery.header.Get(webhook.HeaderEvent))
+	assert.NoError(t, webhook.Verify(created.Secret, delivery.header.Get(webhook.HeaderTimestamp), delivery.header.Get(webhook.HeaderSignature), delivery.body, 5*time.Minute, time.Now()))
+	assert.ErrorIs(t, webhook.Verify("salah", delivery.header.Get(webhook.HeaderTimestamp), delivery.header.Get(webhook.HeaderSignature), delivery.body, 5*time.Minute, time.Now()), webhook.ErrInvalidSignature)
+	assert.Contains(t, string(delivery.body), `"price":37500`)
+
+	code, response := testApp.request(http.MethodGet, "/api/webhooks/"+created.WebhookID+"/deliveries", nil)
+	require.Equal(t, http.StatusOK, code)
+	var deliveries []web.WebhookDeliveryResponse
+	dataAs(t, response, &deliveries)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "succeeded", deliveries[0].Status)
+	assert.Equal(t, http.StatusOK, deliveries[0].ResponseCode)
+	assert.Equal(t, "diterima", deliveries[0].ResponseBody)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+
+	// Nothing is delivered twice
+	testApp.relayAndSend()
+	assert.Len(t, receiver.received(), 1)
+}
+
+func TestWebhookRetryAndRedeliver(t *testing.T) {
+	testApp := setupTestApp(t)
+	receiver := newWebhookReceiver(t, http.StatusServiceUnavailable)
+	created := testApp.createWebhook(receiver.URL, event.Wildcard)
+
+	code, _ := testApp.request(http.MethodPost, "/api/categories/", web.CategoryCreateRequest{Name: "Minuman"})
+	require.Equal(t, http.StatusCreated, code)
+	testApp.relayAndSend()
+
+	_, response := testApp.request(http.MethodGet, "/api/webhooks/"+created.WebhookID+"/deliveries", nil)
+	var deliveries []web.WebhookDeliveryResponse
+	dataAs(t, response, &deliveries)
+	require.Len(t, deliveries, 1)
+	failed := deliveries[0]
+	assert.Equal(t, "pending", failed.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, failed.ResponseCode)
+	assert.True(t, failed.NextAttemptAt.After(time.Now()), "the retry is scheduled with a backoff")
+
+	// The retry is not due yet, only the manual redelivery goes out
+	receiver.mu.Lock()
+	receiver.status = http.StatusNoContent
+	receiver.mu.Unlock()
+	code, response = testApp.request(http.MethodPost, "/api/webhooks/"+created.WebhookID+"/deliveries/"+failed.DeliveryID+"/redeliver", nil)
+	require.Equal(t, http.StatusAccepted, code)
+	var redelivery web.WebhookDeliveryResponse
+	dataAs(t, response, &redelivery)
+	assert.NotEqual(t, failed.DeliveryID, redelivery.DeliveryID)
+
+	testApp.relayAndSend()
+	received := receiver.received()
+	require.Len(t, received, 2)
+	assert.Equal(t, string(received[0].body), string(received[1].body), "the same event is sent again")
+
+	code, _ = testApp.request(http.MethodPost, "/api/webhooks/"+created.WebhookID+"/deliveries/unknown/redeliver", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestWebhookManagement(t *testing.T) {
+	testApp := setupTestApp(t)
+	created := testApp.createWebhook("https://toko.example.com/hooks", event.ProductUpdated)
+
+	code, response := testApp.request(http.MethodGet, "/api/webhooks/"+created.WebhookID, nil)
+	require.Equal(t, http.StatusOK, code)
+	var found web.WebhookResponse
+	dataAs(t, response, &found)
+	assert.Empty(t, found.Secret, "the secret is never shown again")
+
+	code, response = testApp.request(http.MethodPut, "/api/webhooks/"+created.WebhookID, web.WebhookUpdateRequest{
+		URL: "https://toko.example.com/hooks/v2", EventTypes: []string{event.ProductUpdated, event.ProductDeleted}, Active: false,
+	})
+	require.Equal(t, http.StatusOK, code)
+	var updated web.WebhookResponse
+	dataAs(t, response, &updated)
+	assert.False(t, updated.Active)
+	assert.Equal(t, []string{event.ProductUpdated, event.ProductDeleted}, updated.EventTypes)
+
+	code, _ = testApp.request(http.MethodPost, "/api/webhooks/", web.WebhookCreateRequest{URL: "https://toko.example.com", EventTypes: []string{"product.exploded"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = testApp.request(http.MethodDelete, "/api/webhooks/"+created.WebhookID, nil)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = testApp.request(http.MethodGet, "/api/webhooks/"+created.WebhookID, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
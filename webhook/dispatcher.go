@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/event"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository"
+)
+
+// Dispatcher turns every relayed event into one pending delivery per subscribed webhook.
+// It runs inside the relay transaction, so an event is either fully fanned out or not at all.
+type Dispatcher struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	sender     *Sender
+}
+
+func NewDispatcher(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, sender *Sender) *Dispatcher {
+	return &Dispatcher{webhooks: webhooks, deliveries: deliveries, sender: sender}
+}
+
+// Handle implements event.Handler
+func (dispatcher *Dispatcher) Handle(ctx context.Context, e event.Event) error {
+	webhooks, err := dispatcher.webhooks.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	eventID, err := strconv.ParseUint(e.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	created := false
+	for _, webhook := range webhooks {
+		if !event.Matches(webhook.EventTypes, e.Type) {
+			continue
+		}
+		_, err := dispatcher.deliveries.Save(ctx, domain.WebhookDelivery{
+			WebhookID:     webhook.WebhookID,
+			EventID:       eventID,
+			EventType:     e.Type,
+			Payload:       string(payload),
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			return err
+		}
+		created = true
+	}
+
+	if created {
+		repository.AfterCommit(ctx, dispatcher.sender.Notify)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"gorm.io/gorm"
+)
+
+// responseBodyLimit caps the part of the subscriber response kept in the delivery log
+const responseBodyLimit = 1024
+
+// SenderConfig tunes the delivery worker
+type SenderConfig struct {
+	// MaxAttempts is the number of attempts before a delivery is marked failed
+	MaxAttempts int
+	// Backoff is the delay before the second attempt, doubled for every further one up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds one HTTP request
+	Timeout time.Duration
+	// Interval is how often due deliveries are polled when nothing wakes the sender up
+	Interval time.Duration
+	// Concurrency is the number of requests in flight
+	Concurrency int
+}
+
+// Sender posts pending deliveries to their webhook and schedules the retries
+type Sender struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	config     SenderConfig
+	client     *http.Client
+	wake       chan struct{}
+	now        func() time.Time
+}
+
+func NewSender(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, config SenderConfig) *Sender {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	return &Sender{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		config:     config,
+		client:     &http.Client{Timeout: config.Timeout},
+		wake:       make(chan struct{}, 1),
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Backoff returns the delay after the given failed attempt: base, 2*base, 4*base, ... capped at max
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// Notify wakes the sender up without waiting for the next poll
+func (sender *Sender) Notify() {
+	select {
+	case sender.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunOnce sends the deliveries that are due and returns how many were attempted
+func (sender *Sender) RunOnce(ctx context.Context) (int, error) {
+	now := sender.now()
+	due, err := sender.deliveries.FindDue(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, sender.config.Concurrency)
+	attempted := 0
+	for _, delivery := range due {
+		// The lease outlives the request, a crashed worker only delays the retry
+		claimed, err := sender.deliveries.Claim(ctx, delivery, now.Add(2*sender.config.Timeout))
+		if err != nil {
+			wg.Wait()
+			return attempted, err
+		}
+		if !claimed {
+			continue
+		}
+		delivery.Attempts++
+		attempted++
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(delivery domain.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			sender.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+	return attempted, nil
+}
+
+// Run sends deliveries until ctx is cancelled
+func (sender *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(sender.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := sender.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Webhook sender failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-sender.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (sender *Sender) deliver(ctx context.Context, delivery domain.WebhookDelivery) {
+	webhook, err := sender.webhooks.FindById(ctx, delivery.WebhookID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		sender.finish(ctx, delivery, domain.DeliveryFailed, 0, "", "webhook was deleted")
+		return
+	case err != nil:
+		sender.retry(ctx, delivery, 0, "", err.Error())
+		return
+	case !webhook.Active:
+		sender.finish(ctx, delivery, domain.DeliveryFailed, 0, "", "webhook is disabled")
+		return
+	}
+
+	code, body, err := sender.post(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		// Shutting down, the claim expires and the attempt is made again
+		return
+	}
+	switch {
+	case err != nil:
+		sender.retry(ctx, delivery, code, body, err.Error())
+	case code >= 200 && code < 300:
+		sender.finish(ctx, delivery, domain.DeliverySucceeded, code, body, "")
+	default:
+		sender.retry(ctx, delivery, code, body, http.StatusText(code))
+	}
+}
+
+func (sender *Sender) post(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) (int, string, error) {
+	payload := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := sender.now()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "go-restful-api-webhooks/1.0")
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderDelivery, delivery.DeliveryID)
+	request.Header.Set(HeaderTimestamp, formatTimestamp(timestamp))
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, payload))
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, responseBodyLimit))
+	// Drain the rest so the connection can be reused
+	_, _ = io.Copy(io.Discard, response.Body)
+	return response.StatusCode, string(body), nil
+}
+
+func (sender *Sender) retry(ctx context.Context, delivery domain.WebhookDelivery, code int, body string, message string) {
+	if delivery.Attempts >= sender.config.MaxAttempts {
+		sender.finish(ctx, delivery, domain.DeliveryFailed, code, body, message)
+		return
+	}
+	delivery.NextAttemptAt = sender.now().Add(Backoff(delivery.Attempts, sender.config.Backoff, sender.config.MaxBackoff))
+	sender.finish(ctx, delivery, domain.DeliveryPending, code, body, message)
+}
+
+func (sender *Sender) finish(ctx context.Context, delivery domain.WebhookDelivery, status string, code int, body string, message string) {
+	delivery.Status = status
+	delivery.ResponseCode = code
+	delivery.ResponseBody = body
+	delivery.Error = message
+	if _, err := sender.deliveries.Update(context.WithoutCancel(ctx), delivery); err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.DeliveryID, err)
+	}
+}
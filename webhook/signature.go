@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Sign returns the X-Webhook-Signature value: the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret. The timestamp is part of the signed content so that a captured
+// request cannot be replayed later with a fresh timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(formatTimestamp(timestamp)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a received delivery, as a subscriber would: the signature must match and the
+// timestamp must not be older than tolerance
+func Verify(secret string, timestampHeader string, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	timestamp := time.Unix(seconds, 0)
+	if tolerance > 0 && (now.Sub(timestamp) > tolerance || timestamp.Sub(now) > tolerance) {
+		return ErrInvalidSignature
+	}
+	if !strings.HasPrefix(signatureHeader, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signatureHeader)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func formatTimestamp(timestamp time.Time) string {
+	return strconv.FormatInt(timestamp.Unix(), 10)
+}
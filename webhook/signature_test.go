@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_rahasia"
+	body := []byte(`{"type":"product.updated"}`)
+	now := time.Unix(1700000000, 0)
+	signature := Sign(secret, now, body)
+	timestamp := formatTimestamp(now)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		valid     bool
+	}{
+		{name: "valid", secret: secret, timestamp: timestamp, signature: signature, body: body, now: now, valid: true},
+		{name: "within tolerance", secret: secret, timestamp: timestamp, signature: signature, body: body, now: now.Add(4 * time.Minute), valid: true},
+		{name: "replayed too late", secret: secret, timestamp: timestamp, signature: signature, body: body, now: now.Add(6 * time.Minute)},
+		{name: "wrong secret", secret: "lain", timestamp: timestamp, signature: signature, body: body, now: now},
+		{name: "tampered body", secret: secret, timestamp: timestamp, signature: signature, body: []byte(`{}`), now: now},
+		{name: "tampered timestamp", secret: secret, timestamp: formatTimestamp(now.Add(time.Second)), signature: signature, body: body, now: now},
+		{name: "malformed timestamp", secret: secret, timestamp: "kemarin", signature: signature, body: body, now: now},
+		{name: "missing prefix", secret: secret, timestamp: timestamp, signature: signature[len(signaturePrefix):], body: body, now: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, 5*time.Minute, tt.now)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := 30 * time.Second
+	expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 32 * time.Minute, time.Hour, time.Hour}
+	for i, want := range expected {
+		assert.Equal(t, want, Backoff(i+1, base, time.Hour), "attempt %d", i+1)
+	}
+}